@@ -0,0 +1,1405 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: membank/v1/memory.proto
+
+package membankv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Memory is a stored memory
+type Memory struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Empty for personal memories
+	SpaceId       string                 `protobuf:"bytes,3,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Summary       string                 `protobuf:"bytes,5,opt,name=summary,proto3" json:"summary,omitempty"`
+	Importance    int32                  `protobuf:"varint,6,opt,name=importance,proto3" json:"importance,omitempty"`
+	MemoryType    string                 `protobuf:"bytes,7,opt,name=memory_type,json=memoryType,proto3" json:"memory_type,omitempty"`
+	Tags          []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	LastAccessed  *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_accessed,json=lastAccessed,proto3" json:"last_accessed,omitempty"`
+	AccessCount   int32                  `protobuf:"varint,13,opt,name=access_count,json=accessCount,proto3" json:"access_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Memory) Reset() {
+	*x = Memory{}
+	mi := &file_membank_v1_memory_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Memory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Memory) ProtoMessage() {}
+
+func (x *Memory) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Memory.ProtoReflect.Descriptor instead.
+func (*Memory) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Memory) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Memory) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Memory) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *Memory) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Memory) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Memory) GetImportance() int32 {
+	if x != nil {
+		return x.Importance
+	}
+	return 0
+}
+
+func (x *Memory) GetMemoryType() string {
+	if x != nil {
+		return x.MemoryType
+	}
+	return ""
+}
+
+func (x *Memory) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Memory) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Memory) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Memory) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Memory) GetLastAccessed() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessed
+	}
+	return nil
+}
+
+func (x *Memory) GetAccessCount() int32 {
+	if x != nil {
+		return x.AccessCount
+	}
+	return 0
+}
+
+type CreateMemoryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Stores the memory in a shared space instead of the user's own
+	SpaceId       string           `protobuf:"bytes,2,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	Content       string           `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Summary       string           `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	Importance    int32            `protobuf:"varint,5,opt,name=importance,proto3" json:"importance,omitempty"`
+	MemoryType    string           `protobuf:"bytes,6,opt,name=memory_type,json=memoryType,proto3" json:"memory_type,omitempty"`
+	Tags          []string         `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Metadata      *structpb.Struct `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateMemoryRequest) Reset() {
+	*x = CreateMemoryRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateMemoryRequest) ProtoMessage() {}
+
+func (x *CreateMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateMemoryRequest.ProtoReflect.Descriptor instead.
+func (*CreateMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateMemoryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateMemoryRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *CreateMemoryRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreateMemoryRequest) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *CreateMemoryRequest) GetImportance() int32 {
+	if x != nil {
+		return x.Importance
+	}
+	return 0
+}
+
+func (x *CreateMemoryRequest) GetMemoryType() string {
+	if x != nil {
+		return x.MemoryType
+	}
+	return ""
+}
+
+func (x *CreateMemoryRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateMemoryRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type BatchCreateMemoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Memories      []*CreateMemoryRequest `protobuf:"bytes,1,rep,name=memories,proto3" json:"memories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateMemoriesRequest) Reset() {
+	*x = BatchCreateMemoriesRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateMemoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateMemoriesRequest) ProtoMessage() {}
+
+func (x *BatchCreateMemoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateMemoriesRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateMemoriesRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchCreateMemoriesRequest) GetMemories() []*CreateMemoryRequest {
+	if x != nil {
+		return x.Memories
+	}
+	return nil
+}
+
+type BatchCreateMemoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Memories      []*Memory              `protobuf:"bytes,1,rep,name=memories,proto3" json:"memories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateMemoriesResponse) Reset() {
+	*x = BatchCreateMemoriesResponse{}
+	mi := &file_membank_v1_memory_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateMemoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateMemoriesResponse) ProtoMessage() {}
+
+func (x *BatchCreateMemoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateMemoriesResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateMemoriesResponse) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchCreateMemoriesResponse) GetMemories() []*Memory {
+	if x != nil {
+		return x.Memories
+	}
+	return nil
+}
+
+type GetMemoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Restores tokenized personal data; only the author may do so
+	Rehydrate     bool `protobuf:"varint,2,opt,name=rehydrate,proto3" json:"rehydrate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMemoryRequest) Reset() {
+	*x = GetMemoryRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMemoryRequest) ProtoMessage() {}
+
+func (x *GetMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMemoryRequest.ProtoReflect.Descriptor instead.
+func (*GetMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetMemoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetMemoryRequest) GetRehydrate() bool {
+	if x != nil {
+		return x.Rehydrate
+	}
+	return false
+}
+
+// UpdateMemoryRequest leaves unset fields unchanged
+type UpdateMemoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Content       *string                `protobuf:"bytes,2,opt,name=content,proto3,oneof" json:"content,omitempty"`
+	Summary       *string                `protobuf:"bytes,3,opt,name=summary,proto3,oneof" json:"summary,omitempty"`
+	Importance    *int32                 `protobuf:"varint,4,opt,name=importance,proto3,oneof" json:"importance,omitempty"`
+	MemoryType    *string                `protobuf:"bytes,5,opt,name=memory_type,json=memoryType,proto3,oneof" json:"memory_type,omitempty"`
+	Tags          *Tags                  `protobuf:"bytes,6,opt,name=tags,proto3" json:"tags,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMemoryRequest) Reset() {
+	*x = UpdateMemoryRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMemoryRequest) ProtoMessage() {}
+
+func (x *UpdateMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMemoryRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateMemoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateMemoryRequest) GetContent() string {
+	if x != nil && x.Content != nil {
+		return *x.Content
+	}
+	return ""
+}
+
+func (x *UpdateMemoryRequest) GetSummary() string {
+	if x != nil && x.Summary != nil {
+		return *x.Summary
+	}
+	return ""
+}
+
+func (x *UpdateMemoryRequest) GetImportance() int32 {
+	if x != nil && x.Importance != nil {
+		return *x.Importance
+	}
+	return 0
+}
+
+func (x *UpdateMemoryRequest) GetMemoryType() string {
+	if x != nil && x.MemoryType != nil {
+		return *x.MemoryType
+	}
+	return ""
+}
+
+func (x *UpdateMemoryRequest) GetTags() *Tags {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UpdateMemoryRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Tags wraps a list of tags, so an update can clear them
+type Tags struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tags) Reset() {
+	*x = Tags{}
+	mi := &file_membank_v1_memory_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tags) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tags) ProtoMessage() {}
+
+func (x *Tags) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tags.ProtoReflect.Descriptor instead.
+func (*Tags) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Tags) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type DeleteMemoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMemoryRequest) Reset() {
+	*x = DeleteMemoryRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMemoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMemoryRequest) ProtoMessage() {}
+
+func (x *DeleteMemoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMemoryRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMemoryRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteMemoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ListMemoriesRequest selects the personal memories of user_id, or the
+// memories of space_id when set
+type ListMemoriesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpaceId string                 `protobuf:"bytes,2,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	// Defaults to 20
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMemoriesRequest) Reset() {
+	*x = ListMemoriesRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMemoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMemoriesRequest) ProtoMessage() {}
+
+func (x *ListMemoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMemoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListMemoriesRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListMemoriesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListMemoriesRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+func (x *ListMemoriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListMemoriesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListMemoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Memories      []*Memory              `protobuf:"bytes,1,rep,name=memories,proto3" json:"memories,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMemoriesResponse) Reset() {
+	*x = ListMemoriesResponse{}
+	mi := &file_membank_v1_memory_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMemoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMemoriesResponse) ProtoMessage() {}
+
+func (x *ListMemoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMemoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListMemoriesResponse) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListMemoriesResponse) GetMemories() []*Memory {
+	if x != nil {
+		return x.Memories
+	}
+	return nil
+}
+
+func (x *ListMemoriesResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListMemoriesResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// StreamMemoriesRequest selects memories as ListMemoriesRequest does
+type StreamMemoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SpaceId       string                 `protobuf:"bytes,2,opt,name=space_id,json=spaceId,proto3" json:"space_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMemoriesRequest) Reset() {
+	*x = StreamMemoriesRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMemoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMemoriesRequest) ProtoMessage() {}
+
+func (x *StreamMemoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMemoriesRequest.ProtoReflect.Descriptor instead.
+func (*StreamMemoriesRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StreamMemoriesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *StreamMemoriesRequest) GetSpaceId() string {
+	if x != nil {
+		return x.SpaceId
+	}
+	return ""
+}
+
+type SearchMemoriesRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	UserId     string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Query      string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	Tags       []string               `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	MemoryType string                 `protobuf:"bytes,4,opt,name=memory_type,json=memoryType,proto3" json:"memory_type,omitempty"`
+	// Defaults to 20
+	Limit  int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset int32 `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Also searches the user's shared spaces, or only space_ids when given
+	IncludeShared bool     `protobuf:"varint,7,opt,name=include_shared,json=includeShared,proto3" json:"include_shared,omitempty"`
+	SpaceIds      []string `protobuf:"bytes,8,rep,name=space_ids,json=spaceIds,proto3" json:"space_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMemoriesRequest) Reset() {
+	*x = SearchMemoriesRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMemoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMemoriesRequest) ProtoMessage() {}
+
+func (x *SearchMemoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMemoriesRequest.ProtoReflect.Descriptor instead.
+func (*SearchMemoriesRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchMemoriesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SearchMemoriesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchMemoriesRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SearchMemoriesRequest) GetMemoryType() string {
+	if x != nil {
+		return x.MemoryType
+	}
+	return ""
+}
+
+func (x *SearchMemoriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchMemoriesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchMemoriesRequest) GetIncludeShared() bool {
+	if x != nil {
+		return x.IncludeShared
+	}
+	return false
+}
+
+func (x *SearchMemoriesRequest) GetSpaceIds() []string {
+	if x != nil {
+		return x.SpaceIds
+	}
+	return nil
+}
+
+type SearchMemoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Memories      []*Memory              `protobuf:"bytes,1,rep,name=memories,proto3" json:"memories,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMemoriesResponse) Reset() {
+	*x = SearchMemoriesResponse{}
+	mi := &file_membank_v1_memory_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMemoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMemoriesResponse) ProtoMessage() {}
+
+func (x *SearchMemoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMemoriesResponse.ProtoReflect.Descriptor instead.
+func (*SearchMemoriesResponse) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchMemoriesResponse) GetMemories() []*Memory {
+	if x != nil {
+		return x.Memories
+	}
+	return nil
+}
+
+func (x *SearchMemoriesResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchMemoriesResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type SearchSimilarMemoriesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Content string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Defaults to 10
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Minimum similarity, defaults to 0.8
+	Threshold float64 `protobuf:"fixed64,4,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// Also searches the user's shared spaces, or only space_ids when given
+	IncludeShared bool     `protobuf:"varint,5,opt,name=include_shared,json=includeShared,proto3" json:"include_shared,omitempty"`
+	SpaceIds      []string `protobuf:"bytes,6,rep,name=space_ids,json=spaceIds,proto3" json:"space_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchSimilarMemoriesRequest) Reset() {
+	*x = SearchSimilarMemoriesRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchSimilarMemoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchSimilarMemoriesRequest) ProtoMessage() {}
+
+func (x *SearchSimilarMemoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchSimilarMemoriesRequest.ProtoReflect.Descriptor instead.
+func (*SearchSimilarMemoriesRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SearchSimilarMemoriesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SearchSimilarMemoriesRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SearchSimilarMemoriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchSimilarMemoriesRequest) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *SearchSimilarMemoriesRequest) GetIncludeShared() bool {
+	if x != nil {
+		return x.IncludeShared
+	}
+	return false
+}
+
+func (x *SearchSimilarMemoriesRequest) GetSpaceIds() []string {
+	if x != nil {
+		return x.SpaceIds
+	}
+	return nil
+}
+
+type SearchSimilarMemoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Memories      []*Memory              `protobuf:"bytes,1,rep,name=memories,proto3" json:"memories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchSimilarMemoriesResponse) Reset() {
+	*x = SearchSimilarMemoriesResponse{}
+	mi := &file_membank_v1_memory_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchSimilarMemoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchSimilarMemoriesResponse) ProtoMessage() {}
+
+func (x *SearchSimilarMemoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchSimilarMemoriesResponse.ProtoReflect.Descriptor instead.
+func (*SearchSimilarMemoriesResponse) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SearchSimilarMemoriesResponse) GetMemories() []*Memory {
+	if x != nil {
+		return x.Memories
+	}
+	return nil
+}
+
+type GetMemoryStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMemoryStatsRequest) Reset() {
+	*x = GetMemoryStatsRequest{}
+	mi := &file_membank_v1_memory_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMemoryStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMemoryStatsRequest) ProtoMessage() {}
+
+func (x *GetMemoryStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMemoryStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetMemoryStatsRequest) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetMemoryStatsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type MemoryStats struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TotalMemories     int32                  `protobuf:"varint,1,opt,name=total_memories,json=totalMemories,proto3" json:"total_memories,omitempty"`
+	MemoryTypes       map[string]int32       `protobuf:"bytes,2,rep,name=memory_types,json=memoryTypes,proto3" json:"memory_types,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	RecentMemories    int32                  `protobuf:"varint,3,opt,name=recent_memories,json=recentMemories,proto3" json:"recent_memories,omitempty"`
+	AverageImportance float64                `protobuf:"fixed64,4,opt,name=average_importance,json=averageImportance,proto3" json:"average_importance,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *MemoryStats) Reset() {
+	*x = MemoryStats{}
+	mi := &file_membank_v1_memory_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemoryStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemoryStats) ProtoMessage() {}
+
+func (x *MemoryStats) ProtoReflect() protoreflect.Message {
+	mi := &file_membank_v1_memory_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemoryStats.ProtoReflect.Descriptor instead.
+func (*MemoryStats) Descriptor() ([]byte, []int) {
+	return file_membank_v1_memory_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MemoryStats) GetTotalMemories() int32 {
+	if x != nil {
+		return x.TotalMemories
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetMemoryTypes() map[string]int32 {
+	if x != nil {
+		return x.MemoryTypes
+	}
+	return nil
+}
+
+func (x *MemoryStats) GetRecentMemories() int32 {
+	if x != nil {
+		return x.RecentMemories
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetAverageImportance() float64 {
+	if x != nil {
+		return x.AverageImportance
+	}
+	return 0
+}
+
+var File_membank_v1_memory_proto protoreflect.FileDescriptor
+
+const file_membank_v1_memory_proto_rawDesc = "" +
+	"\n" +
+	"\x17membank/v1/memory.proto\x12\n" +
+	"membank.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe4\x03\n" +
+	"\x06Memory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x19\n" +
+	"\bspace_id\x18\x03 \x01(\tR\aspaceId\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x18\n" +
+	"\asummary\x18\x05 \x01(\tR\asummary\x12\x1e\n" +
+	"\n" +
+	"importance\x18\x06 \x01(\x05R\n" +
+	"importance\x12\x1f\n" +
+	"\vmemory_type\x18\a \x01(\tR\n" +
+	"memoryType\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x123\n" +
+	"\bmetadata\x18\t \x01(\v2\x17.google.protobuf.StructR\bmetadata\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12?\n" +
+	"\rlast_accessed\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\flastAccessed\x12!\n" +
+	"\faccess_count\x18\r \x01(\x05R\vaccessCount\"\x87\x02\n" +
+	"\x13CreateMemoryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bspace_id\x18\x02 \x01(\tR\aspaceId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x18\n" +
+	"\asummary\x18\x04 \x01(\tR\asummary\x12\x1e\n" +
+	"\n" +
+	"importance\x18\x05 \x01(\x05R\n" +
+	"importance\x12\x1f\n" +
+	"\vmemory_type\x18\x06 \x01(\tR\n" +
+	"memoryType\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x123\n" +
+	"\bmetadata\x18\b \x01(\v2\x17.google.protobuf.StructR\bmetadata\"Y\n" +
+	"\x1aBatchCreateMemoriesRequest\x12;\n" +
+	"\bmemories\x18\x01 \x03(\v2\x1f.membank.v1.CreateMemoryRequestR\bmemories\"M\n" +
+	"\x1bBatchCreateMemoriesResponse\x12.\n" +
+	"\bmemories\x18\x01 \x03(\v2\x12.membank.v1.MemoryR\bmemories\"@\n" +
+	"\x10GetMemoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\trehydrate\x18\x02 \x01(\bR\trehydrate\"\xc0\x02\n" +
+	"\x13UpdateMemoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\acontent\x18\x02 \x01(\tH\x00R\acontent\x88\x01\x01\x12\x1d\n" +
+	"\asummary\x18\x03 \x01(\tH\x01R\asummary\x88\x01\x01\x12#\n" +
+	"\n" +
+	"importance\x18\x04 \x01(\x05H\x02R\n" +
+	"importance\x88\x01\x01\x12$\n" +
+	"\vmemory_type\x18\x05 \x01(\tH\x03R\n" +
+	"memoryType\x88\x01\x01\x12$\n" +
+	"\x04tags\x18\x06 \x01(\v2\x10.membank.v1.TagsR\x04tags\x123\n" +
+	"\bmetadata\x18\a \x01(\v2\x17.google.protobuf.StructR\bmetadataB\n" +
+	"\n" +
+	"\b_contentB\n" +
+	"\n" +
+	"\b_summaryB\r\n" +
+	"\v_importanceB\x0e\n" +
+	"\f_memory_type\"\x1e\n" +
+	"\x04Tags\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\"%\n" +
+	"\x13DeleteMemoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"w\n" +
+	"\x13ListMemoriesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bspace_id\x18\x02 \x01(\tR\aspaceId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"t\n" +
+	"\x14ListMemoriesResponse\x12.\n" +
+	"\bmemories\x18\x01 \x03(\v2\x12.membank.v1.MemoryR\bmemories\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"K\n" +
+	"\x15StreamMemoriesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bspace_id\x18\x02 \x01(\tR\aspaceId\"\xed\x01\n" +
+	"\x15SearchMemoriesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\x12\x1f\n" +
+	"\vmemory_type\x18\x04 \x01(\tR\n" +
+	"memoryType\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x05R\x06offset\x12%\n" +
+	"\x0einclude_shared\x18\a \x01(\bR\rincludeShared\x12\x1b\n" +
+	"\tspace_ids\x18\b \x03(\tR\bspaceIds\"v\n" +
+	"\x16SearchMemoriesResponse\x12.\n" +
+	"\bmemories\x18\x01 \x03(\v2\x12.membank.v1.MemoryR\bmemories\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"\xc9\x01\n" +
+	"\x1cSearchSimilarMemoriesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x1c\n" +
+	"\tthreshold\x18\x04 \x01(\x01R\tthreshold\x12%\n" +
+	"\x0einclude_shared\x18\x05 \x01(\bR\rincludeShared\x12\x1b\n" +
+	"\tspace_ids\x18\x06 \x03(\tR\bspaceIds\"O\n" +
+	"\x1dSearchSimilarMemoriesResponse\x12.\n" +
+	"\bmemories\x18\x01 \x03(\v2\x12.membank.v1.MemoryR\bmemories\"0\n" +
+	"\x15GetMemoryStatsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x99\x02\n" +
+	"\vMemoryStats\x12%\n" +
+	"\x0etotal_memories\x18\x01 \x01(\x05R\rtotalMemories\x12K\n" +
+	"\fmemory_types\x18\x02 \x03(\v2(.membank.v1.MemoryStats.MemoryTypesEntryR\vmemoryTypes\x12'\n" +
+	"\x0frecent_memories\x18\x03 \x01(\x05R\x0erecentMemories\x12-\n" +
+	"\x12average_importance\x18\x04 \x01(\x01R\x11averageImportance\x1a>\n" +
+	"\x10MemoryTypesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x012\xbc\x06\n" +
+	"\rMemoryService\x12C\n" +
+	"\fCreateMemory\x12\x1f.membank.v1.CreateMemoryRequest\x1a\x12.membank.v1.Memory\x12f\n" +
+	"\x13BatchCreateMemories\x12&.membank.v1.BatchCreateMemoriesRequest\x1a'.membank.v1.BatchCreateMemoriesResponse\x12=\n" +
+	"\tGetMemory\x12\x1c.membank.v1.GetMemoryRequest\x1a\x12.membank.v1.Memory\x12C\n" +
+	"\fUpdateMemory\x12\x1f.membank.v1.UpdateMemoryRequest\x1a\x12.membank.v1.Memory\x12G\n" +
+	"\fDeleteMemory\x12\x1f.membank.v1.DeleteMemoryRequest\x1a\x16.google.protobuf.Empty\x12Q\n" +
+	"\fListMemories\x12\x1f.membank.v1.ListMemoriesRequest\x1a .membank.v1.ListMemoriesResponse\x12I\n" +
+	"\x0eStreamMemories\x12!.membank.v1.StreamMemoriesRequest\x1a\x12.membank.v1.Memory0\x01\x12W\n" +
+	"\x0eSearchMemories\x12!.membank.v1.SearchMemoriesRequest\x1a\".membank.v1.SearchMemoriesResponse\x12l\n" +
+	"\x15SearchSimilarMemories\x12(.membank.v1.SearchSimilarMemoriesRequest\x1a).membank.v1.SearchSimilarMemoriesResponse\x12L\n" +
+	"\x0eGetMemoryStats\x12!.membank.v1.GetMemoryStatsRequest\x1a\x17.membank.v1.MemoryStatsB)Z'mem_bank/api/proto/membank/v1;membankv1b\x06proto3"
+
+var (
+	file_membank_v1_memory_proto_rawDescOnce sync.Once
+	file_membank_v1_memory_proto_rawDescData []byte
+)
+
+func file_membank_v1_memory_proto_rawDescGZIP() []byte {
+	file_membank_v1_memory_proto_rawDescOnce.Do(func() {
+		file_membank_v1_memory_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_membank_v1_memory_proto_rawDesc), len(file_membank_v1_memory_proto_rawDesc)))
+	})
+	return file_membank_v1_memory_proto_rawDescData
+}
+
+var file_membank_v1_memory_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_membank_v1_memory_proto_goTypes = []any{
+	(*Memory)(nil),                        // 0: membank.v1.Memory
+	(*CreateMemoryRequest)(nil),           // 1: membank.v1.CreateMemoryRequest
+	(*BatchCreateMemoriesRequest)(nil),    // 2: membank.v1.BatchCreateMemoriesRequest
+	(*BatchCreateMemoriesResponse)(nil),   // 3: membank.v1.BatchCreateMemoriesResponse
+	(*GetMemoryRequest)(nil),              // 4: membank.v1.GetMemoryRequest
+	(*UpdateMemoryRequest)(nil),           // 5: membank.v1.UpdateMemoryRequest
+	(*Tags)(nil),                          // 6: membank.v1.Tags
+	(*DeleteMemoryRequest)(nil),           // 7: membank.v1.DeleteMemoryRequest
+	(*ListMemoriesRequest)(nil),           // 8: membank.v1.ListMemoriesRequest
+	(*ListMemoriesResponse)(nil),          // 9: membank.v1.ListMemoriesResponse
+	(*StreamMemoriesRequest)(nil),         // 10: membank.v1.StreamMemoriesRequest
+	(*SearchMemoriesRequest)(nil),         // 11: membank.v1.SearchMemoriesRequest
+	(*SearchMemoriesResponse)(nil),        // 12: membank.v1.SearchMemoriesResponse
+	(*SearchSimilarMemoriesRequest)(nil),  // 13: membank.v1.SearchSimilarMemoriesRequest
+	(*SearchSimilarMemoriesResponse)(nil), // 14: membank.v1.SearchSimilarMemoriesResponse
+	(*GetMemoryStatsRequest)(nil),         // 15: membank.v1.GetMemoryStatsRequest
+	(*MemoryStats)(nil),                   // 16: membank.v1.MemoryStats
+	nil,                                   // 17: membank.v1.MemoryStats.MemoryTypesEntry
+	(*structpb.Struct)(nil),               // 18: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),         // 19: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),                 // 20: google.protobuf.Empty
+}
+var file_membank_v1_memory_proto_depIdxs = []int32{
+	18, // 0: membank.v1.Memory.metadata:type_name -> google.protobuf.Struct
+	19, // 1: membank.v1.Memory.created_at:type_name -> google.protobuf.Timestamp
+	19, // 2: membank.v1.Memory.updated_at:type_name -> google.protobuf.Timestamp
+	19, // 3: membank.v1.Memory.last_accessed:type_name -> google.protobuf.Timestamp
+	18, // 4: membank.v1.CreateMemoryRequest.metadata:type_name -> google.protobuf.Struct
+	1,  // 5: membank.v1.BatchCreateMemoriesRequest.memories:type_name -> membank.v1.CreateMemoryRequest
+	0,  // 6: membank.v1.BatchCreateMemoriesResponse.memories:type_name -> membank.v1.Memory
+	6,  // 7: membank.v1.UpdateMemoryRequest.tags:type_name -> membank.v1.Tags
+	18, // 8: membank.v1.UpdateMemoryRequest.metadata:type_name -> google.protobuf.Struct
+	0,  // 9: membank.v1.ListMemoriesResponse.memories:type_name -> membank.v1.Memory
+	0,  // 10: membank.v1.SearchMemoriesResponse.memories:type_name -> membank.v1.Memory
+	0,  // 11: membank.v1.SearchSimilarMemoriesResponse.memories:type_name -> membank.v1.Memory
+	17, // 12: membank.v1.MemoryStats.memory_types:type_name -> membank.v1.MemoryStats.MemoryTypesEntry
+	1,  // 13: membank.v1.MemoryService.CreateMemory:input_type -> membank.v1.CreateMemoryRequest
+	2,  // 14: membank.v1.MemoryService.BatchCreateMemories:input_type -> membank.v1.BatchCreateMemoriesRequest
+	4,  // 15: membank.v1.MemoryService.GetMemory:input_type -> membank.v1.GetMemoryRequest
+	5,  // 16: membank.v1.MemoryService.UpdateMemory:input_type -> membank.v1.UpdateMemoryRequest
+	7,  // 17: membank.v1.MemoryService.DeleteMemory:input_type -> membank.v1.DeleteMemoryRequest
+	8,  // 18: membank.v1.MemoryService.ListMemories:input_type -> membank.v1.ListMemoriesRequest
+	10, // 19: membank.v1.MemoryService.StreamMemories:input_type -> membank.v1.StreamMemoriesRequest
+	11, // 20: membank.v1.MemoryService.SearchMemories:input_type -> membank.v1.SearchMemoriesRequest
+	13, // 21: membank.v1.MemoryService.SearchSimilarMemories:input_type -> membank.v1.SearchSimilarMemoriesRequest
+	15, // 22: membank.v1.MemoryService.GetMemoryStats:input_type -> membank.v1.GetMemoryStatsRequest
+	0,  // 23: membank.v1.MemoryService.CreateMemory:output_type -> membank.v1.Memory
+	3,  // 24: membank.v1.MemoryService.BatchCreateMemories:output_type -> membank.v1.BatchCreateMemoriesResponse
+	0,  // 25: membank.v1.MemoryService.GetMemory:output_type -> membank.v1.Memory
+	0,  // 26: membank.v1.MemoryService.UpdateMemory:output_type -> membank.v1.Memory
+	20, // 27: membank.v1.MemoryService.DeleteMemory:output_type -> google.protobuf.Empty
+	9,  // 28: membank.v1.MemoryService.ListMemories:output_type -> membank.v1.ListMemoriesResponse
+	0,  // 29: membank.v1.MemoryService.StreamMemories:output_type -> membank.v1.Memory
+	12, // 30: membank.v1.MemoryService.SearchMemories:output_type -> membank.v1.SearchMemoriesResponse
+	14, // 31: membank.v1.MemoryService.SearchSimilarMemories:output_type -> membank.v1.SearchSimilarMemoriesResponse
+	16, // 32: membank.v1.MemoryService.GetMemoryStats:output_type -> membank.v1.MemoryStats
+	23, // [23:33] is the sub-list for method output_type
+	13, // [13:23] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_membank_v1_memory_proto_init() }
+func file_membank_v1_memory_proto_init() {
+	if File_membank_v1_memory_proto != nil {
+		return
+	}
+	file_membank_v1_memory_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_membank_v1_memory_proto_rawDesc), len(file_membank_v1_memory_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_membank_v1_memory_proto_goTypes,
+		DependencyIndexes: file_membank_v1_memory_proto_depIdxs,
+		MessageInfos:      file_membank_v1_memory_proto_msgTypes,
+	}.Build()
+	File_membank_v1_memory_proto = out.File
+	file_membank_v1_memory_proto_goTypes = nil
+	file_membank_v1_memory_proto_depIdxs = nil
+}
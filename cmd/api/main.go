@@ -45,9 +45,15 @@ func main() {
 	}
 	redisClient, err := database.NewRedisClient(redisConfig, appLogger)
 	if err != nil {
-		appLogger.WithError(err).Fatal("Failed to connect to Redis")
+		// The in-memory queue does not need Redis; caches, revocations and
+		// rate limits are then kept in process and event streams are off
+		if config.Queue.Backend != "memory" {
+			appLogger.WithError(err).Fatal("Failed to connect to Redis")
+		}
+		appLogger.WithError(err).Warn("Redis unavailable, running single-node without shared state")
+	} else {
+		defer redisClient.Close()
 	}
-	defer redisClient.Close()
 
 	// Create application config
 	appConfig := app.Config{
@@ -2,6 +2,7 @@ package configs
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -9,16 +10,26 @@ import (
 )
 
 type Config struct {
-	Server    ServerConfig    `mapstructure:"server"`
-	Database  DatabaseConfig  `mapstructure:"database"`
-	Redis     RedisConfig     `mapstructure:"redis"`
-	Logging   LoggingConfig   `mapstructure:"logging"`
-	AI        AIConfig        `mapstructure:"ai"`
-	Security  SecurityConfig  `mapstructure:"security"`
-	LLM       LLMConfig       `mapstructure:"llm"`
-	Queue     QueueConfig     `mapstructure:"queue"`
-	Embedding EmbeddingConfig `mapstructure:"embedding"`
-	Qdrant    QdrantConfig    `mapstructure:"qdrant"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Redis      RedisConfig      `mapstructure:"redis"`
+	Logging    LoggingConfig    `mapstructure:"logging"`
+	AI         AIConfig         `mapstructure:"ai"`
+	Security   SecurityConfig   `mapstructure:"security"`
+	LLM        LLMConfig        `mapstructure:"llm"`
+	Queue      QueueConfig      `mapstructure:"queue"`
+	Embedding  EmbeddingConfig  `mapstructure:"embedding"`
+	Qdrant     QdrantConfig     `mapstructure:"qdrant"`
+	Quota      QuotaConfig      `mapstructure:"quota"`
+	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	OIDC       OIDCConfig       `mapstructure:"oidc"`
+	Encryption EncryptionConfig `mapstructure:"encryption"`
+	PII        PIIConfig        `mapstructure:"pii"`
+	Audit      AuditConfig      `mapstructure:"audit"`
+	Events     EventsConfig     `mapstructure:"events"`
+	Webhooks   WebhooksConfig   `mapstructure:"webhooks"`
+	Stream     StreamConfig     `mapstructure:"stream"`
+	GRPC       GRPCConfig       `mapstructure:"grpc"`
 }
 
 type ServerConfig struct {
@@ -76,6 +87,30 @@ type SecurityConfig struct {
 	BCryptCost     int           `mapstructure:"bcrypt_cost"`
 	RateLimit      int           `mapstructure:"rate_limit"`
 	AllowedOrigins []string      `mapstructure:"allowed_origins"`
+
+	// Signing algorithm of access tokens: HS256 with jwt_secret, or RS256 or
+	// EdDSA with keys rotated every jwt_key_rotation and published at
+	// /.well-known/jwks.json. HS256 tokens stay valid for one jwt_expiry
+	// after switching.
+	JWTAlgorithm      string        `mapstructure:"jwt_algorithm"`
+	JWTKeyRotation    time.Duration `mapstructure:"jwt_key_rotation"`
+	JWTKeyPropagation time.Duration `mapstructure:"jwt_key_propagation"`
+
+	// Lifetime of refresh tokens; each refresh rotates the token
+	RefreshTokenExpiry time.Duration `mapstructure:"refresh_token_expiry"`
+
+	// Failed logins in a row before an account is locked; the lock lasts
+	// lockout_duration and doubles per further failure up to max_lockout_duration
+	MaxLoginAttempts   int           `mapstructure:"max_login_attempts"`
+	LockoutDuration    time.Duration `mapstructure:"lockout_duration"`
+	MaxLockoutDuration time.Duration `mapstructure:"max_lockout_duration"`
+
+	// Unrevoked API keys a user may hold at once
+	MaxAPIKeysPerUser int `mapstructure:"max_api_keys_per_user"`
+
+	// Usernames or emails of users promoted to admin at startup; this is
+	// how the first admin is created
+	Admins []string `mapstructure:"admins"`
 }
 
 type LLMConfig struct {
@@ -87,9 +122,22 @@ type LLMConfig struct {
 	TimeoutSeconds  int    `mapstructure:"timeout_seconds"`
 	MaxRetries      int    `mapstructure:"max_retries"`
 	RateLimit       int    `mapstructure:"rate_limit"`
+
+	// Directory holding tiktoken vocabularies (cl100k_base.tiktoken,
+	// o200k_base.tiktoken); token counts are approximated without them
+	TokenizerDir string `mapstructure:"tokenizer_dir"`
+
+	// Per-model prices in US dollars per 1K tokens, overriding the built-in list prices
+	Pricing map[string]LLMPricingConfig `mapstructure:"pricing"`
+}
+
+type LLMPricingConfig struct {
+	PromptPer1K     float64 `mapstructure:"prompt_per_1k"`
+	CompletionPer1K float64 `mapstructure:"completion_per_1k"`
 }
 
 type QueueConfig struct {
+	Backend             string        `mapstructure:"backend"`
 	QueueName           string        `mapstructure:"queue_name"`
 	MaxRetries          int           `mapstructure:"max_retries"`
 	RetryDelay          time.Duration `mapstructure:"retry_delay"`
@@ -100,18 +148,37 @@ type QueueConfig struct {
 	CleanupInterval     time.Duration `mapstructure:"cleanup_interval"`
 	StatsEnabled        bool          `mapstructure:"stats_enabled"`
 	StatsUpdateInterval time.Duration `mapstructure:"stats_update_interval"`
+	ConsumerGroup       string        `mapstructure:"consumer_group"`
+	ConsumerName        string        `mapstructure:"consumer_name"`
+	ClaimMinIdle        time.Duration `mapstructure:"claim_min_idle"`
+	StreamMaxLen        int64         `mapstructure:"stream_max_len"`
 }
 
 type EmbeddingConfig struct {
 	MaxTextLength          int  `mapstructure:"max_text_length"`
 	CacheEnabled           bool `mapstructure:"cache_enabled"`
 	CacheTTLMinutes        int  `mapstructure:"cache_ttl_minutes"`
+	LocalCacheSize         int  `mapstructure:"local_cache_size"`
 	BatchSize              int  `mapstructure:"batch_size"`
 	NormalizeWhitespace    bool `mapstructure:"normalize_whitespace"`
 	ToLowercase            bool `mapstructure:"to_lowercase"`
 	RemoveExtraPunctuation bool `mapstructure:"remove_extra_punctuation"`
 	ChunkSize              int  `mapstructure:"chunk_size"`
 	ChunkOverlap           int  `mapstructure:"chunk_overlap"`
+
+	DistributedLock  bool          `mapstructure:"distributed_lock"`
+	LockTTL          time.Duration `mapstructure:"lock_ttl"`
+	LockWaitTimeout  time.Duration `mapstructure:"lock_wait_timeout"`
+	LockPollInterval time.Duration `mapstructure:"lock_poll_interval"`
+
+	// Preprocessing pipelines keyed by embedding model name, or "default"
+	Pipelines map[string]EmbeddingPipelineConfig `mapstructure:"pipelines"`
+}
+
+type EmbeddingPipelineConfig struct {
+	Steps     []string `mapstructure:"steps"`
+	MaxRunes  int      `mapstructure:"max_runes"`
+	MaxTokens int      `mapstructure:"max_tokens"`
 }
 
 type QdrantConfig struct {
@@ -124,6 +191,130 @@ type QdrantConfig struct {
 	Enabled        bool   `mapstructure:"enabled"`
 }
 
+type QuotaConfig struct {
+	Enabled     bool                       `mapstructure:"enabled"`
+	DefaultPlan string                     `mapstructure:"default_plan"`
+	Plans       map[string]QuotaPlanConfig `mapstructure:"plans"`
+}
+
+// QuotaPlanConfig holds the limits of one plan; zero means unlimited
+type QuotaPlanConfig struct {
+	MaxMemories             int   `mapstructure:"max_memories"`
+	MaxStorageBytes         int64 `mapstructure:"max_storage_bytes"`
+	MonthlyEmbeddingTokens  int64 `mapstructure:"monthly_embedding_tokens"`
+	MonthlyCompletionTokens int64 `mapstructure:"monthly_completion_tokens"`
+}
+
+// OIDCConfig configures login through an external OpenID provider. Users
+// are provisioned on first login and linked by issuer and subject.
+type OIDCConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Issuer       string        `mapstructure:"issuer"`
+	ClientID     string        `mapstructure:"client_id"`
+	ClientSecret string        `mapstructure:"client_secret"`
+	RedirectURL  string        `mapstructure:"redirect_url"` // .../api/v1/auth/oidc/callback
+	Scopes       []string      `mapstructure:"scopes"`
+	StateTTL     time.Duration `mapstructure:"state_ttl"` // how long a login may take
+}
+
+// EncryptionConfig configures encryption of memory content at rest. Each
+// user's data key is wrapped by a master key held in the KMS.
+type EncryptionConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	KMS          string `mapstructure:"kms"`            // local
+	LocalKeyFile string `mapstructure:"local_key_file"` // master keys of the local KMS, created if missing
+}
+
+// PIIConfig configures screening of memories for personal data before
+// they are stored or sent for embedding. Users may choose their own policy
+// in their settings.
+type PIIConfig struct {
+	Enabled       bool            `mapstructure:"enabled"`
+	DefaultPolicy string          `mapstructure:"default_policy"` // allow, redact, tokenize or reject
+	DisabledRules []string        `mapstructure:"disabled_rules"` // built-in kinds to skip, e.g. phone
+	Rules         []PIIRuleConfig `mapstructure:"rules"`
+}
+
+// PIIRuleConfig adds a kind of personal data found by a regular expression
+type PIIRuleConfig struct {
+	Kind    string `mapstructure:"kind"`
+	Pattern string `mapstructure:"pattern"`
+}
+
+// AuditConfig configures the audit log of memory and user operations.
+// Entries are written by a queue job.
+type AuditConfig struct {
+	Enabled   bool `mapstructure:"enabled"`
+	HashChain bool `mapstructure:"hash_chain"` // link entries by hash so changes can be detected
+}
+
+// EventsConfig configures the feed of memory events, which is also the
+// outbox webhooks are delivered from
+type EventsConfig struct {
+	Retention     time.Duration `mapstructure:"retention"`      // how far back the feed can be read
+	PurgeInterval time.Duration `mapstructure:"purge_interval"` // how often expired events are deleted
+}
+
+// WebhooksConfig configures the delivery of memory events to endpoints
+// registered by users. Deliveries are queue jobs.
+type WebhooksConfig struct {
+	Enabled                 bool          `mapstructure:"enabled"`
+	MaxSubscriptionsPerUser int           `mapstructure:"max_subscriptions_per_user"`
+	Timeout                 time.Duration `mapstructure:"timeout"`                // per delivery attempt
+	MaxAttempts             int           `mapstructure:"max_attempts"`           // before a delivery is given up
+	AllowPrivateNetworks    bool          `mapstructure:"allow_private_networks"` // permit endpoints on internal addresses
+	DispatchInterval        time.Duration `mapstructure:"dispatch_interval"`
+	DispatchBatchSize       int           `mapstructure:"dispatch_batch_size"`
+}
+
+// StreamConfig configures the server-sent event streams of memory events.
+// Events are published from the outbox to Redis, which fans them out to
+// the streams open on every replica.
+type StreamConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	PublishInterval  time.Duration `mapstructure:"publish_interval"` // how often new events are picked up from the outbox
+	PublishBatchSize int           `mapstructure:"publish_batch_size"`
+	BufferSize       int           `mapstructure:"buffer_size"` // events kept per user for Last-Event-ID resumption
+	BufferTTL        time.Duration `mapstructure:"buffer_ttl"`  // how long a quiet user's buffer is kept
+	Heartbeat        time.Duration `mapstructure:"heartbeat"`   // comment sent to idle streams to keep proxies from closing them
+}
+
+// GRPCConfig configures the gRPC API, served next to the HTTP API on its
+// own port with the same services, authentication and rate limits. It is
+// off by default; the port speaks plaintext, so put it behind TLS.
+type GRPCConfig struct {
+	Enabled        bool `mapstructure:"enabled"`
+	Port           int  `mapstructure:"port"`             // listens on server.host
+	MaxMessageSize int  `mapstructure:"max_message_size"` // largest request accepted, in bytes
+}
+
+// RateLimitConfig configures request rate limiting. The default limit is
+// security.rate_limit requests per minute.
+type RateLimitConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Backend string `mapstructure:"backend"` // redis (shared by replicas) or memory
+	Burst   int    `mapstructure:"burst"`   // 0 allows security.rate_limit back to back
+
+	Roles  map[string]RateLimitRuleConfig `mapstructure:"roles"`
+	Routes []RouteRateLimitConfig         `mapstructure:"routes"`
+}
+
+// RateLimitRuleConfig allows Requests per Period with up to Burst at once
+type RateLimitRuleConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Period   time.Duration `mapstructure:"period"` // defaults to one minute
+	Burst    int           `mapstructure:"burst"`  // defaults to requests
+}
+
+// RouteRateLimitConfig overrides the limit for a route, optionally for one role
+type RouteRateLimitConfig struct {
+	Method string `mapstructure:"method"`
+	Path   string `mapstructure:"path"`
+	Role   string `mapstructure:"role"`
+
+	RateLimitRuleConfig `mapstructure:",squash"`
+}
+
 // LoadConfig loads configuration with proper priority: env vars > config file > defaults
 func LoadConfig(configPath string) (*Config, error) {
 	config := &Config{}
@@ -207,9 +398,17 @@ func setViperDefaults() {
 
 	// Security defaults
 	viper.SetDefault("security.jwt_expiry", "24h")
+	viper.SetDefault("security.refresh_token_expiry", "720h")
+	viper.SetDefault("security.jwt_algorithm", "HS256")
+	viper.SetDefault("security.jwt_key_rotation", "720h")
+	viper.SetDefault("security.jwt_key_propagation", "5m")
 	viper.SetDefault("security.bcrypt_cost", 12)
 	viper.SetDefault("security.rate_limit", 100)
 	viper.SetDefault("security.allowed_origins", []string{"*"})
+	viper.SetDefault("security.max_login_attempts", 5)
+	viper.SetDefault("security.lockout_duration", "1m")
+	viper.SetDefault("security.max_lockout_duration", "1h")
+	viper.SetDefault("security.max_api_keys_per_user", 10)
 
 	// LLM defaults
 	viper.SetDefault("llm.provider", "openai")
@@ -221,6 +420,7 @@ func setViperDefaults() {
 	viper.SetDefault("llm.rate_limit", 60)
 
 	// Queue defaults
+	viper.SetDefault("queue.backend", "redis")
 	viper.SetDefault("queue.queue_name", "mem_bank_queue")
 	viper.SetDefault("queue.max_retries", 3)
 	viper.SetDefault("queue.retry_delay", "5s")
@@ -231,11 +431,19 @@ func setViperDefaults() {
 	viper.SetDefault("queue.cleanup_interval", "3600s")
 	viper.SetDefault("queue.stats_enabled", true)
 	viper.SetDefault("queue.stats_update_interval", "10s")
+	viper.SetDefault("queue.consumer_group", "mem_bank_workers")
+	viper.SetDefault("queue.claim_min_idle", "360s")
+	viper.SetDefault("queue.stream_max_len", 100000)
 
 	// Embedding defaults
 	viper.SetDefault("embedding.max_text_length", 8192)
 	viper.SetDefault("embedding.cache_enabled", true)
 	viper.SetDefault("embedding.cache_ttl_minutes", 60)
+	viper.SetDefault("embedding.local_cache_size", 10000)
+	viper.SetDefault("embedding.distributed_lock", false)
+	viper.SetDefault("embedding.lock_ttl", "30s")
+	viper.SetDefault("embedding.lock_wait_timeout", "10s")
+	viper.SetDefault("embedding.lock_poll_interval", "50ms")
 	viper.SetDefault("embedding.batch_size", 100)
 	viper.SetDefault("embedding.normalize_whitespace", true)
 	viper.SetDefault("embedding.to_lowercase", true)
@@ -250,6 +458,59 @@ func setViperDefaults() {
 	viper.SetDefault("qdrant.vector_size", 1536)
 	viper.SetDefault("qdrant.use_https", false)
 	viper.SetDefault("qdrant.enabled", true)
+
+	// Quota defaults
+	viper.SetDefault("quota.enabled", true)
+	viper.SetDefault("quota.default_plan", "free")
+
+	// Rate limit defaults
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.backend", "redis")
+	viper.SetDefault("rate_limit.burst", 0)
+
+	// OIDC defaults
+	viper.SetDefault("oidc.enabled", false)
+	viper.SetDefault("oidc.scopes", []string{"openid", "email", "profile"})
+	viper.SetDefault("oidc.state_ttl", "10m")
+
+	// Encryption defaults
+	viper.SetDefault("encryption.enabled", false)
+	viper.SetDefault("encryption.kms", "local")
+	viper.SetDefault("encryption.local_key_file", "./data/master_keys.json")
+
+	// PII defaults
+	viper.SetDefault("pii.enabled", true)
+	viper.SetDefault("pii.default_policy", "allow")
+
+	// Audit defaults
+	viper.SetDefault("audit.enabled", true)
+	viper.SetDefault("audit.hash_chain", false)
+
+	// Events defaults
+	viper.SetDefault("events.retention", "168h")
+	viper.SetDefault("events.purge_interval", "1h")
+
+	// Webhooks defaults
+	viper.SetDefault("webhooks.enabled", true)
+	viper.SetDefault("webhooks.max_subscriptions_per_user", 10)
+	viper.SetDefault("webhooks.timeout", "10s")
+	viper.SetDefault("webhooks.max_attempts", 5)
+	viper.SetDefault("webhooks.allow_private_networks", false)
+	viper.SetDefault("webhooks.dispatch_interval", "2s")
+	viper.SetDefault("webhooks.dispatch_batch_size", 100)
+
+	// Stream defaults
+	viper.SetDefault("stream.enabled", true)
+	viper.SetDefault("stream.publish_interval", "500ms")
+	viper.SetDefault("stream.publish_batch_size", 100)
+	viper.SetDefault("stream.buffer_size", 500)
+	viper.SetDefault("stream.buffer_ttl", "1h")
+	viper.SetDefault("stream.heartbeat", "15s")
+
+	// gRPC defaults
+	viper.SetDefault("grpc.enabled", false)
+	viper.SetDefault("grpc.port", 9090)
+	viper.SetDefault("grpc.max_message_size", 4<<20)
 }
 
 // setupViper configures viper for reading configuration
@@ -319,9 +580,18 @@ func bindEnvironmentVariables() {
 	// Security config
 	viper.BindEnv("security.jwt_secret", "MEM_BANK_SECURITY_JWT_SECRET", "JWT_SECRET")
 	viper.BindEnv("security.jwt_expiry", "MEM_BANK_SECURITY_JWT_EXPIRY", "JWT_EXPIRY")
+	viper.BindEnv("security.refresh_token_expiry", "MEM_BANK_SECURITY_REFRESH_TOKEN_EXPIRY")
+	viper.BindEnv("security.jwt_algorithm", "MEM_BANK_SECURITY_JWT_ALGORITHM")
+	viper.BindEnv("security.jwt_key_rotation", "MEM_BANK_SECURITY_JWT_KEY_ROTATION")
+	viper.BindEnv("security.jwt_key_propagation", "MEM_BANK_SECURITY_JWT_KEY_PROPAGATION")
 	viper.BindEnv("security.bcrypt_cost", "MEM_BANK_SECURITY_BCRYPT_COST")
 	viper.BindEnv("security.rate_limit", "MEM_BANK_SECURITY_RATE_LIMIT", "RATE_LIMIT")
 	viper.BindEnv("security.allowed_origins", "MEM_BANK_SECURITY_ALLOWED_ORIGINS", "ALLOWED_ORIGINS")
+	viper.BindEnv("security.max_login_attempts", "MEM_BANK_SECURITY_MAX_LOGIN_ATTEMPTS")
+	viper.BindEnv("security.lockout_duration", "MEM_BANK_SECURITY_LOCKOUT_DURATION")
+	viper.BindEnv("security.max_lockout_duration", "MEM_BANK_SECURITY_MAX_LOCKOUT_DURATION")
+	viper.BindEnv("security.max_api_keys_per_user", "MEM_BANK_SECURITY_MAX_API_KEYS_PER_USER")
+	viper.BindEnv("security.admins", "MEM_BANK_SECURITY_ADMINS")
 
 	// LLM config - support OpenAI-compatible env vars
 	viper.BindEnv("llm.provider", "MEM_BANK_LLM_PROVIDER", "LLM_PROVIDER")
@@ -332,8 +602,11 @@ func bindEnvironmentVariables() {
 	viper.BindEnv("llm.timeout_seconds", "MEM_BANK_LLM_TIMEOUT_SECONDS", "LLM_TIMEOUT_SECONDS")
 	viper.BindEnv("llm.max_retries", "MEM_BANK_LLM_MAX_RETRIES", "LLM_MAX_RETRIES")
 	viper.BindEnv("llm.rate_limit", "MEM_BANK_LLM_RATE_LIMIT", "LLM_RATE_LIMIT")
+	viper.BindEnv("llm.tokenizer_dir", "MEM_BANK_LLM_TOKENIZER_DIR")
 
 	// Queue config
+	viper.BindEnv("queue.backend", "MEM_BANK_QUEUE_BACKEND", "QUEUE_BACKEND")
+	viper.BindEnv("queue.consumer_name", "MEM_BANK_QUEUE_CONSUMER_NAME", "QUEUE_CONSUMER_NAME")
 	viper.BindEnv("queue.queue_name", "MEM_BANK_QUEUE_QUEUE_NAME", "QUEUE_NAME")
 	viper.BindEnv("queue.max_retries", "MEM_BANK_QUEUE_MAX_RETRIES")
 	viper.BindEnv("queue.default_concurrency", "MEM_BANK_QUEUE_DEFAULT_CONCURRENCY")
@@ -341,6 +614,8 @@ func bindEnvironmentVariables() {
 	// Embedding config
 	viper.BindEnv("embedding.max_text_length", "MEM_BANK_EMBEDDING_MAX_TEXT_LENGTH")
 	viper.BindEnv("embedding.cache_enabled", "MEM_BANK_EMBEDDING_CACHE_ENABLED")
+	viper.BindEnv("embedding.local_cache_size", "MEM_BANK_EMBEDDING_LOCAL_CACHE_SIZE")
+	viper.BindEnv("embedding.distributed_lock", "MEM_BANK_EMBEDDING_DISTRIBUTED_LOCK")
 	viper.BindEnv("embedding.batch_size", "MEM_BANK_EMBEDDING_BATCH_SIZE")
 
 	// Qdrant config
@@ -351,6 +626,62 @@ func bindEnvironmentVariables() {
 	viper.BindEnv("qdrant.collection_name", "MEM_BANK_QDRANT_COLLECTION_NAME", "QDRANT_COLLECTION_NAME")
 	viper.BindEnv("qdrant.vector_size", "MEM_BANK_QDRANT_VECTOR_SIZE", "QDRANT_VECTOR_SIZE")
 	viper.BindEnv("qdrant.use_https", "MEM_BANK_QDRANT_USE_HTTPS", "QDRANT_USE_HTTPS")
+
+	// Quota configuration
+	viper.BindEnv("quota.enabled", "MEM_BANK_QUOTA_ENABLED")
+	viper.BindEnv("quota.default_plan", "MEM_BANK_QUOTA_DEFAULT_PLAN")
+
+	// Rate limit configuration
+	viper.BindEnv("rate_limit.enabled", "MEM_BANK_RATE_LIMIT_ENABLED")
+	viper.BindEnv("rate_limit.backend", "MEM_BANK_RATE_LIMIT_BACKEND")
+	viper.BindEnv("rate_limit.burst", "MEM_BANK_RATE_LIMIT_BURST")
+
+	// OIDC configuration
+	viper.BindEnv("oidc.enabled", "MEM_BANK_OIDC_ENABLED")
+	viper.BindEnv("oidc.issuer", "MEM_BANK_OIDC_ISSUER")
+	viper.BindEnv("oidc.client_id", "MEM_BANK_OIDC_CLIENT_ID")
+	viper.BindEnv("oidc.client_secret", "MEM_BANK_OIDC_CLIENT_SECRET")
+	viper.BindEnv("oidc.redirect_url", "MEM_BANK_OIDC_REDIRECT_URL")
+	viper.BindEnv("oidc.state_ttl", "MEM_BANK_OIDC_STATE_TTL")
+
+	// Encryption configuration
+	viper.BindEnv("encryption.enabled", "MEM_BANK_ENCRYPTION_ENABLED")
+	viper.BindEnv("encryption.kms", "MEM_BANK_ENCRYPTION_KMS")
+	viper.BindEnv("encryption.local_key_file", "MEM_BANK_ENCRYPTION_LOCAL_KEY_FILE")
+
+	// PII configuration
+	viper.BindEnv("pii.enabled", "MEM_BANK_PII_ENABLED")
+	viper.BindEnv("pii.default_policy", "MEM_BANK_PII_DEFAULT_POLICY")
+
+	// Audit configuration
+	viper.BindEnv("audit.enabled", "MEM_BANK_AUDIT_ENABLED")
+	viper.BindEnv("audit.hash_chain", "MEM_BANK_AUDIT_HASH_CHAIN")
+
+	// Events configuration
+	viper.BindEnv("events.retention", "MEM_BANK_EVENTS_RETENTION")
+	viper.BindEnv("events.purge_interval", "MEM_BANK_EVENTS_PURGE_INTERVAL")
+
+	// Webhooks configuration
+	viper.BindEnv("webhooks.enabled", "MEM_BANK_WEBHOOKS_ENABLED")
+	viper.BindEnv("webhooks.max_subscriptions_per_user", "MEM_BANK_WEBHOOKS_MAX_SUBSCRIPTIONS_PER_USER")
+	viper.BindEnv("webhooks.timeout", "MEM_BANK_WEBHOOKS_TIMEOUT")
+	viper.BindEnv("webhooks.max_attempts", "MEM_BANK_WEBHOOKS_MAX_ATTEMPTS")
+	viper.BindEnv("webhooks.allow_private_networks", "MEM_BANK_WEBHOOKS_ALLOW_PRIVATE_NETWORKS")
+	viper.BindEnv("webhooks.dispatch_interval", "MEM_BANK_WEBHOOKS_DISPATCH_INTERVAL")
+	viper.BindEnv("webhooks.dispatch_batch_size", "MEM_BANK_WEBHOOKS_DISPATCH_BATCH_SIZE")
+
+	// Stream configuration
+	viper.BindEnv("stream.enabled", "MEM_BANK_STREAM_ENABLED")
+	viper.BindEnv("stream.publish_interval", "MEM_BANK_STREAM_PUBLISH_INTERVAL")
+	viper.BindEnv("stream.publish_batch_size", "MEM_BANK_STREAM_PUBLISH_BATCH_SIZE")
+	viper.BindEnv("stream.buffer_size", "MEM_BANK_STREAM_BUFFER_SIZE")
+	viper.BindEnv("stream.buffer_ttl", "MEM_BANK_STREAM_BUFFER_TTL")
+	viper.BindEnv("stream.heartbeat", "MEM_BANK_STREAM_HEARTBEAT")
+
+	// gRPC configuration
+	viper.BindEnv("grpc.enabled", "MEM_BANK_GRPC_ENABLED")
+	viper.BindEnv("grpc.port", "MEM_BANK_GRPC_PORT")
+	viper.BindEnv("grpc.max_message_size", "MEM_BANK_GRPC_MAX_MESSAGE_SIZE")
 }
 
 // postProcessConfig handles special configuration processing that requires custom logic
@@ -366,6 +697,15 @@ func postProcessConfig(config *Config) {
 			config.Security.AllowedOrigins = origins
 		}
 	}
+
+	// Admins may likewise be a comma-separated list
+	if adminsStr := viper.GetString("security.admins"); strings.Contains(adminsStr, ",") {
+		admins := strings.Split(adminsStr, ",")
+		for i, admin := range admins {
+			admins[i] = strings.TrimSpace(admin)
+		}
+		config.Security.Admins = admins
+	}
 }
 
 // validateConfig validates the final configuration
@@ -388,6 +728,16 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("JWT secret must be at least 32 characters long for security")
 	}
 
+	switch config.Security.JWTAlgorithm {
+	case "HS256":
+	case "RS256", "EdDSA":
+		if config.Security.JWTKeyPropagation >= config.Security.JWTKeyRotation {
+			return fmt.Errorf("jwt_key_propagation must be shorter than jwt_key_rotation")
+		}
+	default:
+		return fmt.Errorf("invalid JWT algorithm: %s (must be HS256, RS256 or EdDSA)", config.Security.JWTAlgorithm)
+	}
+
 	// LLM validation (if provider is set)
 	if config.LLM.Provider != "" {
 		if config.LLM.APIKey == "" {
@@ -400,6 +750,109 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("invalid rate limit: %d (must be positive)", config.Security.RateLimit)
 	}
 
+	if config.RateLimit.Burst < 0 {
+		return fmt.Errorf("invalid rate limit burst: %d (must be non-negative)", config.RateLimit.Burst)
+	}
+	switch config.RateLimit.Backend {
+	case "redis", "memory":
+	default:
+		return fmt.Errorf("invalid rate limit backend: %s (must be redis or memory)", config.RateLimit.Backend)
+	}
+	for role, rule := range config.RateLimit.Roles {
+		if rule.Requests <= 0 {
+			return fmt.Errorf("invalid rate limit for role %s: %d requests (must be positive)", role, rule.Requests)
+		}
+	}
+	for _, route := range config.RateLimit.Routes {
+		if route.Path == "" {
+			return fmt.Errorf("rate limit route requires a path")
+		}
+		if route.Requests <= 0 {
+			return fmt.Errorf("invalid rate limit for route %s: %d requests (must be positive)", route.Path, route.Requests)
+		}
+	}
+
+	// OIDC validation
+	if config.OIDC.Enabled {
+		if config.OIDC.Issuer == "" || config.OIDC.ClientID == "" || config.OIDC.RedirectURL == "" {
+			return fmt.Errorf("OIDC issuer, client_id and redirect_url are required when OIDC is enabled")
+		}
+		if !strings.HasPrefix(config.OIDC.Issuer, "https://") && !strings.HasPrefix(config.OIDC.Issuer, "http://") {
+			return fmt.Errorf("invalid OIDC issuer: %s (must be an http:// or https:// URL)", config.OIDC.Issuer)
+		}
+	}
+
+	// Encryption validation
+	if config.Encryption.Enabled {
+		switch config.Encryption.KMS {
+		case "local":
+			if config.Encryption.LocalKeyFile == "" {
+				return fmt.Errorf("encryption local_key_file is required with the local KMS")
+			}
+		default:
+			return fmt.Errorf("invalid encryption KMS: %s (must be local)", config.Encryption.KMS)
+		}
+	}
+
+	// PII validation
+	if config.PII.Enabled {
+		switch config.PII.DefaultPolicy {
+		case "allow", "redact", "tokenize", "reject":
+		default:
+			return fmt.Errorf("invalid PII default policy: %s (must be allow, redact, tokenize or reject)", config.PII.DefaultPolicy)
+		}
+		for _, rule := range config.PII.Rules {
+			if strings.TrimSpace(rule.Kind) == "" {
+				return fmt.Errorf("PII rule requires a kind")
+			}
+			if _, err := regexp.Compile(rule.Pattern); err != nil || rule.Pattern == "" {
+				return fmt.Errorf("invalid pattern for PII rule %s", rule.Kind)
+			}
+		}
+	}
+
+	// Events validation
+	if config.Events.Retention <= 0 || config.Events.PurgeInterval <= 0 {
+		return fmt.Errorf("events retention and purge_interval must be positive")
+	}
+
+	// Webhooks validation
+	if config.Webhooks.Enabled {
+		if config.Webhooks.Timeout <= 0 || config.Webhooks.DispatchInterval <= 0 {
+			return fmt.Errorf("webhooks timeout and dispatch_interval must be positive")
+		}
+		if config.Webhooks.MaxAttempts <= 0 || config.Webhooks.DispatchBatchSize <= 0 || config.Webhooks.MaxSubscriptionsPerUser <= 0 {
+			return fmt.Errorf("webhooks max_attempts, dispatch_batch_size and max_subscriptions_per_user must be positive")
+		}
+	}
+
+	// Stream validation
+	if config.Stream.Enabled {
+		if config.Stream.PublishInterval <= 0 || config.Stream.BufferTTL <= 0 || config.Stream.Heartbeat <= 0 {
+			return fmt.Errorf("stream publish_interval, buffer_ttl and heartbeat must be positive")
+		}
+		if config.Stream.PublishBatchSize <= 0 || config.Stream.BufferSize <= 0 {
+			return fmt.Errorf("stream publish_batch_size and buffer_size must be positive")
+		}
+	}
+
+	// gRPC validation
+	if config.GRPC.Enabled {
+		if config.GRPC.Port <= 0 || config.GRPC.Port > 65535 {
+			return fmt.Errorf("invalid grpc port: %d", config.GRPC.Port)
+		}
+		if config.GRPC.MaxMessageSize <= 0 {
+			return fmt.Errorf("grpc max_message_size must be positive")
+		}
+	}
+
+	// Queue backend validation
+	switch config.Queue.Backend {
+	case "redis", "redis_streams", "memory":
+	default:
+		return fmt.Errorf("invalid queue backend: %s (must be redis, redis_streams or memory)", config.Queue.Backend)
+	}
+
 	// Validate allowed origins format
 	for _, origin := range config.Security.AllowedOrigins {
 		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
@@ -132,6 +132,7 @@ func TestAllowedOriginsPostProcessing(t *testing.T) {
 
 	// Test comma-separated origins
 	os.Setenv("MEM_BANK_SECURITY_ALLOWED_ORIGINS", "http://localhost:3000, https://app.example.com, https://admin.example.com")
+	os.Setenv("MEM_BANK_SECURITY_ADMINS", "root, ops@example.com")
 
 	config, err := LoadConfig("non_existent_config.yaml")
 	if err != nil {
@@ -148,6 +149,10 @@ func TestAllowedOriginsPostProcessing(t *testing.T) {
 			t.Errorf("Expected origin[%d] '%s', got '%s'", i, expected, config.Security.AllowedOrigins[i])
 		}
 	}
+
+	if len(config.Security.Admins) != 2 || config.Security.Admins[0] != "root" || config.Security.Admins[1] != "ops@example.com" {
+		t.Errorf("Expected admins [root ops@example.com], got %v", config.Security.Admins)
+	}
 }
 
 func TestConfigValidation(t *testing.T) {
@@ -242,7 +247,7 @@ func clearTestEnv() {
 		"MEM_BANK_AI_EMBEDDING_DIM",
 		"MEM_BANK_REDIS_HOST", "REDIS_HOST",
 		"MEM_BANK_SERVER_MODE", "SERVER_MODE", "GIN_MODE",
-		"MEM_BANK_SECURITY_ALLOWED_ORIGINS", "ALLOWED_ORIGINS",
+		"MEM_BANK_SECURITY_ALLOWED_ORIGINS", "ALLOWED_ORIGINS", "MEM_BANK_SECURITY_ADMINS",
 		"MEM_BANK_SERVER_READ_TIMEOUT",
 		"MEM_BANK_DATABASE_MAX_LIFETIME",
 	}
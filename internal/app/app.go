@@ -2,43 +2,95 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"gorm.io/gorm"
 
+	membankv1 "mem_bank/api/proto/membank/v1"
 	"mem_bank/configs"
+	apikeyDao "mem_bank/internal/dao/apikey"
+	auditDao "mem_bank/internal/dao/audit"
+	authDao "mem_bank/internal/dao/auth"
+	encryptionDao "mem_bank/internal/dao/encryption"
+	erasureDao "mem_bank/internal/dao/erasure"
 	memoryDao "mem_bank/internal/dao/memory"
+	piiDao "mem_bank/internal/dao/pii"
+	quotaDao "mem_bank/internal/dao/quota"
+	spaceDao "mem_bank/internal/dao/space"
+	usageDao "mem_bank/internal/dao/usage"
 	userDao "mem_bank/internal/dao/user"
+	webhookDao "mem_bank/internal/dao/webhook"
+	"mem_bank/internal/domain/apikey"
+	"mem_bank/internal/domain/audit"
+	"mem_bank/internal/domain/encryption"
+	"mem_bank/internal/domain/erasure"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/pii"
+	"mem_bank/internal/domain/quota"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/domain/webhook"
+	memoryGRPC "mem_bank/internal/handler/grpc/memory"
+	userGRPC "mem_bank/internal/handler/grpc/user"
+	apikeyHandler "mem_bank/internal/handler/http/apikey"
+	auditHandler "mem_bank/internal/handler/http/audit"
+	authHandler "mem_bank/internal/handler/http/auth"
+	encryptionHandler "mem_bank/internal/handler/http/encryption"
+	erasureHandler "mem_bank/internal/handler/http/erasure"
+	eventHandler "mem_bank/internal/handler/http/event"
 	memoryHandler "mem_bank/internal/handler/http/memory"
+	quotaHandler "mem_bank/internal/handler/http/quota"
+	spaceHandler "mem_bank/internal/handler/http/space"
+	usageHandler "mem_bank/internal/handler/http/usage"
 	userHandler "mem_bank/internal/handler/http/user"
+	webhookHandler "mem_bank/internal/handler/http/webhook"
 	"mem_bank/internal/middleware"
 	"mem_bank/internal/queue"
+	apikeyService "mem_bank/internal/service/apikey"
+	auditService "mem_bank/internal/service/audit"
+	authService "mem_bank/internal/service/auth"
 	embeddingService "mem_bank/internal/service/embedding"
+	encryptionService "mem_bank/internal/service/encryption"
+	erasureService "mem_bank/internal/service/erasure"
+	eventService "mem_bank/internal/service/event"
 	memoryService "mem_bank/internal/service/memory"
+	piiService "mem_bank/internal/service/pii"
+	quotaService "mem_bank/internal/service/quota"
+	spaceService "mem_bank/internal/service/space"
+	usageService "mem_bank/internal/service/usage"
 	userService "mem_bank/internal/service/user"
+	webhookService "mem_bank/internal/service/webhook"
 	"mem_bank/pkg/auth"
+	pkgencryption "mem_bank/pkg/encryption"
 	"mem_bank/pkg/llm"
 	"mem_bank/pkg/logger"
+	"mem_bank/pkg/metrics"
+	"mem_bank/pkg/oidc"
+	pkgpii "mem_bank/pkg/pii"
+	"mem_bank/pkg/ratelimit"
 	"mem_bank/pkg/response"
 )
 
 // App represents the application
 type App struct {
 	server     *http.Server
+	grpcServer *grpc.Server
 	db         *gorm.DB
 	redis      *redis.Client
 	logger     logger.Logger
 	config     *configs.Config
 	jobQueue   queue.Queue
 	jwtService *auth.JWTService
+	apiKeys    apikey.Service
 }
 
 // Config holds application configuration
@@ -65,12 +117,36 @@ func NewApp(db *gorm.DB, redisClient *redis.Client, appLogger logger.Logger, app
 func (a *App) Start(ctx context.Context, config Config) error {
 	// Wire up dependencies using constructor-based dependency injection
 
-	// Initialize JWT Service
+	// Initialize JWT Service. Without Redis, which the in-memory queue does
+	// not need, revocations are only seen by this replica.
+	var revocations auth.RevocationList = auth.NewMemoryRevocationList()
+	if a.redis != nil {
+		revocations = auth.NewRedisRevocationList(a.redis, "mem_bank:revoked:")
+	} else {
+		a.logger.Warn("Redis unavailable, keeping token revocations in memory")
+	}
 	a.jwtService = auth.NewJWTService(
 		a.config.Security.JWTSecret,
 		"mem_bank",
 		a.config.Security.JWTExpiry,
-	)
+	).WithRevocationList(revocations)
+
+	if algorithm := a.config.Security.JWTAlgorithm; algorithm != auth.AlgorithmHS256 {
+		keys, err := auth.NewKeyRing(authDao.NewSigningKeyStore(a.db), a.logger, auth.KeyRingConfig{
+			Algorithm:         algorithm,
+			RotationInterval:  a.config.Security.JWTKeyRotation,
+			PropagationDelay:  a.config.Security.JWTKeyPropagation,
+			VerificationGrace: a.config.Security.JWTExpiry,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create JWT key ring: %w", err)
+		}
+		if err := keys.Maintain(ctx); err != nil {
+			return fmt.Errorf("failed to load JWT signing keys: %w", err)
+		}
+		go keys.Run(ctx)
+		a.jwtService.WithKeyRing(keys)
+	}
 
 	// Initialize LLM Provider
 	llmConfig := &llm.Config{
@@ -84,19 +160,66 @@ func (a *App) Start(ctx context.Context, config Config) error {
 		RateLimit:       a.config.LLM.RateLimit,
 	}
 
-	llmProvider := llm.NewOpenAIProvider(llmConfig)
+	tokenizers, err := llm.NewTokenizers(a.config.LLM.TokenizerDir)
+	if err != nil {
+		return fmt.Errorf("failed to load tokenizers: %w", err)
+	}
+
+	userRepository := userDao.NewPostgresRepository(a.db)
+	usageRepository := usageDao.NewPostgresRepository(a.db)
+
+	// Every provider call is priced and recorded in the usage ledger
+	usageSvc := usageService.NewService(
+		usageRepository,
+		a.logger,
+		usageService.Config{Pricing: llmPricing(a.config.LLM.Pricing)},
+	)
+
+	// Quotas cap memories, storage and monthly token spend per user
+	var quotas quota.Service
+	var usageLimiter llm.UsageLimiter
+	if a.config.Quota.Enabled {
+		quotaSvc := quotaService.NewService(
+			quotaDao.NewPostgresRepository(a.db),
+			userRepository,
+			usageRepository,
+			a.logger,
+			quotaService.Config{
+				DefaultPlan: a.config.Quota.DefaultPlan,
+				Plans:       quotaPlans(a.config.Quota.Plans),
+			},
+		)
+		quotas = quotaSvc
+		usageLimiter = quotaSvc
+	}
+
+	llmProvider := llm.NewMeteredProvider(
+		llm.NewInstrumentedProvider(llm.NewOpenAIProvider(llmConfig)),
+		tokenizers,
+		usageSvc,
+		usageLimiter,
+	)
 	embeddingProvider := llmProvider // OpenAIProvider implements both interfaces
 
 	// Initialize Embedding Service
+	pipelines, err := embeddingPipelines(a.config.Embedding.Pipelines)
+	if err != nil {
+		return err
+	}
 	embeddingSvc := embeddingService.NewService(
 		embeddingProvider,
 		a.redis,
 		a.logger,
 		embeddingService.Config{
-			MaxTextLength:   a.config.Embedding.MaxTextLength,
-			CacheEnabled:    a.config.Embedding.CacheEnabled,
-			CacheTTLMinutes: a.config.Embedding.CacheTTLMinutes,
-			BatchSize:       a.config.Embedding.BatchSize,
+			MaxTextLength:    a.config.Embedding.MaxTextLength,
+			CacheEnabled:     a.config.Embedding.CacheEnabled,
+			CacheTTLMinutes:  a.config.Embedding.CacheTTLMinutes,
+			LocalCacheSize:   a.config.Embedding.LocalCacheSize,
+			DistributedLock:  a.config.Embedding.DistributedLock,
+			LockTTL:          a.config.Embedding.LockTTL,
+			LockWaitTimeout:  a.config.Embedding.LockWaitTimeout,
+			LockPollInterval: a.config.Embedding.LockPollInterval,
+			BatchSize:        a.config.Embedding.BatchSize,
 			PreprocessingConfig: embeddingService.PreprocessingConfig{
 				NormalizeWhitespace:    a.config.Embedding.NormalizeWhitespace,
 				ToLowercase:            a.config.Embedding.ToLowercase,
@@ -104,32 +227,33 @@ func (a *App) Start(ctx context.Context, config Config) error {
 				ChunkSize:              a.config.Embedding.ChunkSize,
 				ChunkOverlap:           a.config.Embedding.ChunkOverlap,
 			},
+			Pipelines:  pipelines,
+			Tokenizers: tokenizers,
 		},
 	)
 
 	// Initialize Job Queue
-	a.jobQueue = queue.NewRedisQueue(
-		a.redis,
-		a.logger,
-		queue.Config{
-			QueueName:           a.config.Queue.QueueName,
-			MaxRetries:          a.config.Queue.MaxRetries,
-			RetryDelay:          a.config.Queue.RetryDelay,
-			JobTimeout:          a.config.Queue.JobTimeout,
-			ResultTTL:           a.config.Queue.ResultTTL,
-			DefaultConcurrency:  a.config.Queue.DefaultConcurrency,
-			PollInterval:        a.config.Queue.PollInterval,
-			CleanupInterval:     a.config.Queue.CleanupInterval,
-			StatsEnabled:        a.config.Queue.StatsEnabled,
-			StatsUpdateInterval: a.config.Queue.StatsUpdateInterval,
-		},
-	)
+	a.jobQueue = a.newJobQueue()
 
 	// DAOs (Data Access Objects)
-	userRepository := userDao.NewPostgresRepository(a.db)
 	memoryRepository := memoryDao.NewPostgresRepository(a.db)
 
+	// Optionally encrypt memory content at rest
+	var encryptionSvc encryption.Service
+	var keyRing *pkgencryption.KeyRing
+	if a.config.Encryption.Enabled {
+		kms, err := pkgencryption.NewLocalKMS(a.config.Encryption.LocalKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to open local KMS: %w", err)
+		}
+		keyRing = pkgencryption.NewKeyRing(kms, encryptionDao.NewKeyStore(a.db), a.logger)
+		memoryRepository = memoryDao.NewEncryptedPostgresRepository(a.db, keyRing)
+		encryptionSvc = encryptionService.NewService(keyRing, memoryDao.NewReencrypter(a.db, keyRing), userRepository, a.logger)
+		a.logger.Info("Memory encryption at rest enabled")
+	}
+
 	// Optionally use Qdrant if enabled
+	var vectors *memoryDao.QdrantRepository
 	if a.config.Qdrant.Enabled {
 		qdrantRepo, err := memoryDao.NewQdrantRepository(
 			memoryDao.QdrantConfig{
@@ -146,6 +270,7 @@ func (a *App) Start(ctx context.Context, config Config) error {
 			a.logger.WithError(err).Warn("Failed to initialize Qdrant repository, using PostgreSQL only")
 		} else {
 			memoryRepository = qdrantRepo
+			vectors = qdrantRepo
 			a.logger.Info("Qdrant vector database initialized successfully")
 		}
 	}
@@ -157,6 +282,81 @@ func (a *App) Start(ctx context.Context, config Config) error {
 	a.jobQueue.RegisterHandler("generate_embedding", generateEmbeddingHandler)
 	a.jobQueue.RegisterHandler("batch_embedding", batchEmbeddingHandler)
 
+	// User erasure wipes every store holding user data, rows last
+	var erasureStores []erasure.Store
+	if purger, ok := a.jobQueue.(queue.Purger); ok {
+		erasureStores = append(erasureStores, erasureService.NewJobStore(purger))
+	}
+	if vectors != nil {
+		erasureStores = append(erasureStores, erasureService.NewVectorStore(vectors))
+	}
+	erasureStores = append(erasureStores, erasureService.NewEmbeddingCacheStore(embeddingSvc))
+	if keyRing != nil {
+		erasureStores = append(erasureStores, erasureService.NewDataKeyStore(keyRing))
+	}
+	erasureStores = append(erasureStores, erasureService.NewPostgresStore(userRepository))
+	erasureSvc := erasureService.NewService(
+		erasureDao.NewPostgresRepository(a.db),
+		memoryDao.NewErasureLister(a.db, keyRing),
+		erasureStores,
+		userRepository,
+		a.jobQueue,
+		a.jwtService,
+		a.logger,
+	)
+	a.jobQueue.RegisterHandler(queue.JobTypeEraseUser, queue.NewEraseUserHandler(erasureSvc, a.logger))
+
+	// Optionally record memory and user operations in the audit log
+	var auditSvc audit.Service
+	if a.config.Audit.Enabled {
+		auditSvc = auditService.NewService(auditDao.NewPostgresRepository(a.db, a.config.Audit.HashChain), a.jobQueue, a.config.Audit.HashChain, a.logger)
+		a.jobQueue.RegisterHandler(queue.JobTypeAuditEntry, queue.NewAuditEntryHandler(auditSvc, a.logger))
+		a.logger.WithField("hash_chain", a.config.Audit.HashChain).Info("Audit log enabled")
+	}
+
+	// Feed of memory events, read from the outbox the memory repository writes
+	eventRepository := memoryDao.NewEventRepository(a.db)
+	eventSvc := eventService.NewService(eventRepository, a.logger, eventService.Config{
+		Retention:     a.config.Events.Retention,
+		PurgeInterval: a.config.Events.PurgeInterval,
+	})
+	go eventSvc.Run(ctx)
+
+	// Optionally stream memory events to clients through Redis, so a stream
+	// receives the events published by any replica
+	if a.config.Stream.Enabled && a.redis == nil {
+		a.logger.Warn("Redis unavailable, memory event streams disabled")
+	} else if a.config.Stream.Enabled {
+		streamConfig := eventService.StreamConfig{
+			PublishInterval:  a.config.Stream.PublishInterval,
+			PublishBatchSize: a.config.Stream.PublishBatchSize,
+			BufferSize:       a.config.Stream.BufferSize,
+			BufferTTL:        a.config.Stream.BufferTTL,
+		}
+		broker := eventService.NewRedisBroker(a.redis, "mem_bank:events:", a.logger, streamConfig)
+		eventSvc.WithBroker(broker)
+		go eventService.NewPublisher(eventRepository, broker, a.logger, streamConfig).Run(ctx)
+		a.logger.Info("Memory event streams enabled")
+	}
+
+	// Optionally deliver memory events to endpoints registered by users
+	var webhookSvc webhook.Service
+	if a.config.Webhooks.Enabled {
+		webhookConfig := webhookService.Config{
+			MaxSubscriptionsPerUser: a.config.Webhooks.MaxSubscriptionsPerUser,
+			Timeout:                 a.config.Webhooks.Timeout,
+			AllowPrivateNetworks:    a.config.Webhooks.AllowPrivateNetworks,
+			MaxAttempts:             a.config.Webhooks.MaxAttempts,
+			DispatchInterval:        a.config.Webhooks.DispatchInterval,
+			DispatchBatchSize:       a.config.Webhooks.DispatchBatchSize,
+		}
+		webhookRepository := webhookDao.NewPostgresRepository(a.db)
+		webhookSvc = webhookService.NewService(webhookRepository, eventRepository, a.logger, webhookConfig)
+		a.jobQueue.RegisterHandler(queue.JobTypeDeliverWebhook, queue.NewDeliverWebhookHandler(webhookSvc, a.logger))
+		go webhookService.NewDispatcher(eventRepository, webhookRepository, a.jobQueue, a.logger, webhookConfig).Run(ctx)
+		a.logger.Info("Webhook delivery enabled")
+	}
+
 	// Start job queue with concurrency
 	if err := a.jobQueue.StartConsuming(ctx, a.config.Queue.DefaultConcurrency); err != nil {
 		return fmt.Errorf("failed to start job queue consumer: %w", err)
@@ -164,17 +364,121 @@ func (a *App) Start(ctx context.Context, config Config) error {
 
 	// Services
 	userSvc := userService.NewService(userRepository)
+	if auditSvc != nil {
+		userSvc = auditService.NewUserService(userSvc, userRepository, auditSvc)
+	}
+	a.promoteAdmins(ctx, userSvc)
+	authSvc := authService.NewService(
+		authDao.NewPostgresRepository(a.db),
+		userSvc,
+		userRepository,
+		auth.NewPasswordHasher(a.config.Security.BCryptCost),
+		a.logger,
+		authService.Config{
+			MaxFailedAttempts:  a.config.Security.MaxLoginAttempts,
+			LockoutDuration:    a.config.Security.LockoutDuration,
+			MaxLockoutDuration: a.config.Security.MaxLockoutDuration,
+		},
+	)
+
+	// Shared spaces
+	spaceRepository := spaceDao.NewPostgresRepository(a.db)
+	spaceSvc := spaceService.NewService(spaceRepository, userRepository, a.logger)
+
+	// Optionally screen memories for personal data before storage and embedding
+	var piiSvc pii.Service
+	if a.config.PII.Enabled {
+		detector, err := newPIIDetector(a.config.PII)
+		if err != nil {
+			return err
+		}
+		piiSvc = piiService.NewService(detector, piiDao.NewVault(a.db, keyRing), userRepository, a.config.PII.DefaultPolicy, a.logger)
+		a.logger.WithField("default_policy", a.config.PII.DefaultPolicy).Info("Personal data screening enabled")
+	}
 
 	// Create regular memory service
-	regularMemorySvc := memoryService.NewService(memoryRepository, userRepository, embeddingSvc, a.logger)
+	regularMemorySvc := memoryService.NewService(memoryRepository, userRepository, spaceRepository, embeddingSvc, quotas, piiSvc, a.logger)
 
 	// Initialize AI Memory Service if needed
 	// For now, we'll use the regular service
 	enhancedMemorySvc := regularMemorySvc
+	if auditSvc != nil {
+		enhancedMemorySvc = auditService.NewMemoryService(enhancedMemorySvc, memoryRepository, auditSvc)
+	}
 
 	// Handlers
 	userHandler := userHandler.NewHandler(userSvc)
+	tokenSvc := authService.NewTokenService(
+		authDao.NewRefreshTokenRepository(a.db),
+		userRepository,
+		a.jwtService,
+		a.logger,
+		authService.TokenConfig{RefreshTokenExpiry: a.config.Security.RefreshTokenExpiry},
+	)
+	authHandler := authHandler.NewHandler(authSvc, tokenSvc, a.jwtService, a.logger)
+	if a.config.OIDC.Enabled {
+		provider, err := oidc.Discover(ctx, nil, oidc.Config{
+			Issuer:       a.config.OIDC.Issuer,
+			ClientID:     a.config.OIDC.ClientID,
+			ClientSecret: a.config.OIDC.ClientSecret,
+			RedirectURL:  a.config.OIDC.RedirectURL,
+			Scopes:       a.config.OIDC.Scopes,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set up OpenID Connect login: %w", err)
+		}
+		var states oidc.StateStore = oidc.NewMemoryStateStore()
+		if a.redis != nil {
+			states = oidc.NewRedisStateStore(a.redis, "mem_bank:oidc_state:")
+		}
+		authHandler.WithOIDC(authService.NewOIDCService(
+			provider,
+			states,
+			authDao.NewIdentityRepository(a.db),
+			userSvc,
+			userRepository,
+			a.logger,
+			authService.OIDCConfig{StateTTL: a.config.OIDC.StateTTL},
+		))
+	}
+	a.apiKeys = apikeyService.NewService(
+		apikeyDao.NewPostgresRepository(a.db),
+		userRepository,
+		a.logger,
+		apikeyService.Config{MaxKeysPerUser: a.config.Security.MaxAPIKeysPerUser},
+	)
+	apikeyHandler := apikeyHandler.NewHandler(a.apiKeys, a.logger)
 	memoryHandler := memoryHandler.NewHandler(enhancedMemorySvc, a.logger)
+	spaceHandler := spaceHandler.NewHandler(spaceSvc, a.logger)
+	usageHandler := usageHandler.NewHandler(usageSvc, a.logger)
+	var quotasHandler *quotaHandler.Handler
+	if quotas != nil {
+		quotasHandler = quotaHandler.NewHandler(quotas, a.logger)
+	}
+	var encryptionKeysHandler *encryptionHandler.Handler
+	if encryptionSvc != nil {
+		encryptionKeysHandler = encryptionHandler.NewHandler(encryptionSvc, a.logger)
+	}
+	erasureHandler := erasureHandler.NewHandler(erasureSvc, a.logger)
+	var auditLogHandler *auditHandler.Handler
+	if auditSvc != nil {
+		auditLogHandler = auditHandler.NewHandler(auditSvc, a.logger)
+	}
+	eventsHandler := eventHandler.NewHandler(eventSvc, a.logger).WithHeartbeat(a.config.Stream.Heartbeat)
+	var webhooksHandler *webhookHandler.Handler
+	if webhookSvc != nil {
+		webhooksHandler = webhookHandler.NewHandler(webhookSvc, a.logger)
+	}
+
+	// HTTP and gRPC calls draw on the same rate limits
+	limiter := a.newRateLimiter()
+
+	// Serve the memory and user services over gRPC as well
+	if a.config.GRPC.Enabled {
+		if err := a.startGRPC(config.Host, enhancedMemorySvc, userSvc, limiter); err != nil {
+			return err
+		}
+	}
 
 	// Setup router
 	gin.SetMode(config.Mode)
@@ -182,6 +486,7 @@ func (a *App) Start(ctx context.Context, config Config) error {
 
 	// Add essential middleware
 	router.Use(middleware.RequestID())
+	router.Use(middleware.Metrics())
 	router.Use(middleware.ResponseTime())
 	router.Use(middleware.RequestLogger(a.logger))
 	router.Use(middleware.Recovery(a.logger))
@@ -194,7 +499,9 @@ func (a *App) Start(ctx context.Context, config Config) error {
 	router.Use(middleware.SecurityHeaders())
 	router.Use(middleware.XSSProtection())
 	router.Use(middleware.SQLInjectionProtection())
-	router.Use(middleware.RateLimit(a.config.Security.RateLimit, 60)) // Rate limit per minute
+	if limiter != nil {
+		router.Use(middleware.RateLimit(limiter, rateLimitPolicy(a.config), a.jwtService, a.apiKeys, a.logger))
+	}
 
 	// CORS middleware with proper configuration
 	allowedOrigins := a.config.Security.AllowedOrigins
@@ -211,7 +518,7 @@ func (a *App) Start(ctx context.Context, config Config) error {
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Content-Type", "Authorization", "X-API-Key", "X-Request-ID"},
-		ExposeHeaders:    []string{"X-Request-ID"},
+		ExposeHeaders:    []string{"X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -220,7 +527,7 @@ func (a *App) Start(ctx context.Context, config Config) error {
 	router.Use(middleware.RequestSizeLimit(10 << 20)) // 10MB limit
 
 	// Setup routes
-	a.setupRoutes(router, userHandler, memoryHandler)
+	a.setupRoutes(router, authHandler, apikeyHandler, userHandler, memoryHandler, spaceHandler, usageHandler, quotasHandler, encryptionKeysHandler, erasureHandler, auditLogHandler, eventsHandler, webhooksHandler)
 
 	// Setup server
 	a.server = &http.Server{
@@ -237,7 +544,16 @@ func (a *App) Start(ctx context.Context, config Config) error {
 }
 
 // setupRoutes configures all application routes
-func (a *App) setupRoutes(router *gin.Engine, userHandler *userHandler.Handler, memoryHandler *memoryHandler.Handler) {
+func (a *App) setupRoutes(router *gin.Engine, authHandler *authHandler.Handler, apikeyHandler *apikeyHandler.Handler, userHandler *userHandler.Handler, memoryHandler *memoryHandler.Handler, spaceHandler *spaceHandler.Handler, usageHandler *usageHandler.Handler, quotasHandler *quotaHandler.Handler, encryptionKeysHandler *encryptionHandler.Handler, erasureHandler *erasureHandler.Handler, auditLogHandler *auditHandler.Handler, eventsHandler *eventHandler.Handler, webhooksHandler *webhookHandler.Handler) {
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Public keys for verifying access tokens in other services
+	router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=60")
+		c.JSON(http.StatusOK, a.jwtService.JWKS())
+	})
+
 	// Public routes
 	api := router.Group("/api/v1")
 
@@ -251,52 +567,126 @@ func (a *App) setupRoutes(router *gin.Engine, userHandler *userHandler.Handler,
 		})
 	})
 
-	// Public user creation endpoint
-	api.POST("/users", userHandler.CreateUser)
-
 	// Authentication endpoints
 	auth := api.Group("/auth")
 	{
-		auth.POST("/login", a.handleLogin)
-		auth.POST("/refresh", a.handleRefreshToken)
-		auth.POST("/logout", middleware.OptionalJWTAuth(a.jwtService), a.handleLogout)
+		auth.POST("/register", authHandler.Register)
+		auth.POST("/login", authHandler.Login)
+		auth.POST("/refresh", authHandler.RefreshToken)
+		auth.POST("/logout", middleware.OptionalJWTAuth(a.jwtService), authHandler.Logout)
+		auth.PUT("/password", middleware.JWTAuth(a.jwtService), authHandler.ChangePassword)
+		auth.GET("/oidc/login", authHandler.OIDCLogin)
+		auth.GET("/oidc/callback", authHandler.OIDCCallback)
 	}
 
 	// Protected routes requiring authentication
 	protected := api.Group("")
-	protected.Use(middleware.OptionalJWTAuth(a.jwtService)) // Allow optional JWT auth
+	protected.Use(middleware.JWTOrAPIKeyAuth(a.jwtService, a.apiKeys))
 
 	// User routes
 	users := protected.Group("/users")
+	users.Use(middleware.RequireScope(apikey.ScopeAdmin))
 	users.Use(middleware.ValidateJSON())
 	{
+		// Users sign themselves up through /auth/register, which proves they
+		// hold a password; creating accounts for others is for admins
+		users.POST("", middleware.RequireRole("admin", "system"), userHandler.CreateUser)
 		users.GET("/:id", middleware.ValidateUUID("id"), userHandler.GetUser)
 		users.GET("/username/:username", userHandler.GetUserByUsername)
 		users.GET("/search", userHandler.GetUserByEmail) // ?email=...
 		users.PUT("/:id", middleware.ValidateUUID("id"), userHandler.UpdateUser)
 		users.DELETE("/:id", middleware.ValidateUUID("id"), userHandler.DeleteUser)
-		users.GET("", userHandler.ListUsers)
-		users.GET("/stats", userHandler.GetUserStats)
+		users.GET("", middleware.RequireRole("admin", "system"), userHandler.ListUsers)
+		users.GET("/stats", middleware.RequireRole("admin", "system"), userHandler.GetUserStats)
 		users.POST("/:id/login", middleware.ValidateUUID("id"), userHandler.UpdateLastLogin)
+		users.POST("/:id/forget", middleware.ValidateUUID("id"), erasureHandler.RequestErasure)
 	}
 
-	// Memory routes - these should be more strictly protected in production
+	// Erasure receipts - readable by the requester as well as admins
+	protected.GET("/erasures/:id", middleware.RequireScope(apikey.ScopeAdmin), middleware.ValidateUUID("id"), erasureHandler.GetErasure)
+
+	// Memory routes - the memory service checks the caller owns the memories
+	// or is a member of their space
 	memories := protected.Group("/memories")
 	memories.Use(middleware.ValidateJSON())
 	{
-		memories.POST("", memoryHandler.CreateMemory)
-		memories.GET("/:id", middleware.ValidateUUID("id"), memoryHandler.GetMemory)
-		memories.PUT("/:id", middleware.ValidateUUID("id"), memoryHandler.UpdateMemory)
-		memories.DELETE("/:id", middleware.ValidateUUID("id"), memoryHandler.DeleteMemory)
-		memories.GET("/users/:user_id", middleware.ValidateUUID("user_id"), memoryHandler.ListUserMemories)
-		memories.POST("/users/:user_id/search", middleware.ValidateUUID("user_id"), memoryHandler.SearchMemories)
-		memories.GET("/users/:user_id/similar", middleware.ValidateUUID("user_id"), memoryHandler.SearchSimilarMemories)
-		memories.GET("/users/:user_id/stats", middleware.ValidateUUID("user_id"), memoryHandler.GetMemoryStats)
+		read := middleware.RequireScope(apikey.ScopeMemoriesRead)
+		write := middleware.RequireScope(apikey.ScopeMemoriesWrite)
+
+		memories.POST("", write, memoryHandler.CreateMemory)
+		memories.POST("/batch", write, memoryHandler.BatchCreateMemories)
+		memories.GET("/:id", read, middleware.ValidateUUID("id"), memoryHandler.GetMemory)
+		memories.PUT("/:id", write, middleware.ValidateUUID("id"), memoryHandler.UpdateMemory)
+		memories.DELETE("/:id", write, middleware.ValidateUUID("id"), memoryHandler.DeleteMemory)
+		memories.GET("/users/:user_id", read, middleware.ValidateUUID("user_id"), memoryHandler.ListUserMemories)
+		memories.POST("/users/:user_id/search", read, middleware.ValidateUUID("user_id"), memoryHandler.SearchMemories)
+		memories.GET("/users/:user_id/similar", read, middleware.ValidateUUID("user_id"), memoryHandler.SearchSimilarMemories)
+		memories.GET("/users/:user_id/stats", read, middleware.ValidateUUID("user_id"), memoryHandler.GetMemoryStats)
+		memories.GET("/users/:user_id/stream", read, middleware.ValidateUUID("user_id"), eventsHandler.StreamUserMemories)
+		memories.GET("/spaces/:space_id", read, middleware.ValidateUUID("space_id"), memoryHandler.ListSpaceMemories)
 	}
 
-	// Admin routes - require JWT authentication and admin role
+	// Shared spaces of the authenticated user and their members
+	spaces := protected.Group("/spaces")
+	spaces.Use(middleware.ValidateJSON())
+	{
+		read := middleware.RequireScope(apikey.ScopeMemoriesRead)
+		write := middleware.RequireScope(apikey.ScopeMemoriesWrite)
+
+		spaces.POST("", write, spaceHandler.CreateSpace)
+		spaces.GET("", read, spaceHandler.ListSpaces)
+		spaces.GET("/:id", read, middleware.ValidateUUID("id"), spaceHandler.GetSpace)
+		spaces.PUT("/:id", write, middleware.ValidateUUID("id"), spaceHandler.UpdateSpace)
+		spaces.DELETE("/:id", write, middleware.ValidateUUID("id"), spaceHandler.DeleteSpace)
+		spaces.GET("/:id/members", read, middleware.ValidateUUID("id"), spaceHandler.ListMembers)
+		spaces.POST("/:id/members", write, middleware.ValidateUUID("id"), spaceHandler.AddMember)
+		spaces.PUT("/:id/members/:user_id", write, middleware.ValidateUUID("id"), middleware.ValidateUUID("user_id"), spaceHandler.UpdateMember)
+		spaces.DELETE("/:id/members/:user_id", write, middleware.ValidateUUID("id"), middleware.ValidateUUID("user_id"), spaceHandler.RemoveMember)
+	}
+
+	// API keys of the authenticated user; keys cannot manage keys unless
+	// they carry the admin scope
+	apiKeys := protected.Group("/api-keys")
+	apiKeys.Use(middleware.RequireScope(apikey.ScopeAdmin))
+	{
+		apiKeys.POST("", apikeyHandler.CreateAPIKey)
+		apiKeys.GET("", apikeyHandler.ListAPIKeys)
+		apiKeys.GET("/:id", middleware.ValidateUUID("id"), apikeyHandler.GetAPIKey)
+		apiKeys.DELETE("/:id", middleware.ValidateUUID("id"), apikeyHandler.RevokeAPIKey)
+	}
+
+	// Memory events of the authenticated user, polled with ?since=<sequence>
+	protected.GET("/events", middleware.RequireScope(apikey.ScopeMemoriesRead), eventsHandler.ListEvents)
+
+	// Webhook subscriptions of the authenticated user and their delivery log.
+	// Subscriptions keep receiving events after the key that made them is
+	// revoked, so managing them needs the write scope.
+	if webhooksHandler != nil {
+		webhooks := protected.Group("/webhooks")
+		webhooks.Use(middleware.ValidateJSON())
+		read := middleware.RequireScope(apikey.ScopeMemoriesRead)
+		write := middleware.RequireScope(apikey.ScopeMemoriesWrite)
+		{
+			webhooks.POST("", write, webhooksHandler.CreateSubscription)
+			webhooks.GET("", read, webhooksHandler.ListSubscriptions)
+			webhooks.GET("/:id", read, middleware.ValidateUUID("id"), webhooksHandler.GetSubscription)
+			webhooks.PUT("/:id", write, middleware.ValidateUUID("id"), webhooksHandler.UpdateSubscription)
+			webhooks.DELETE("/:id", write, middleware.ValidateUUID("id"), webhooksHandler.DeleteSubscription)
+			webhooks.GET("/:id/deliveries", read, middleware.ValidateUUID("id"), webhooksHandler.ListDeliveries)
+		}
+	}
+
+	// LLM usage of the authenticated user - ?from=...&to=... (RFC 3339)
+	protected.GET("/usage", usageHandler.GetUsage)
+
+	// Quota of the authenticated user
+	if quotasHandler != nil {
+		protected.GET("/quota", quotasHandler.GetMyQuota)
+	}
+
+	// Admin routes - require a token or API key with the admin role
 	admin := api.Group("/admin")
-	admin.Use(middleware.JWTAuth(a.jwtService))
+	admin.Use(middleware.JWTOrAPIKeyAuth(a.jwtService, a.apiKeys))
 	admin.Use(middleware.RequireRole("admin", "system"))
 	{
 		// Example admin endpoints
@@ -310,153 +700,177 @@ func (a *App) setupRoutes(router *gin.Engine, userHandler *userHandler.Handler,
 				},
 			})
 		})
-	}
-}
 
-// LoginRequest represents a login request
-type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
-}
+		// Quota administration
+		if quotasHandler != nil {
+			admin.GET("/quotas/:user_id", middleware.ValidateUUID("user_id"), quotasHandler.GetQuota)
+			admin.PUT("/quotas/:user_id", middleware.ValidateUUID("user_id"), quotasHandler.UpdateQuota)
+		}
 
-// LoginResponse represents a login response
-type LoginResponse struct {
-	Success      bool   `json:"success"`
-	AccessToken  string `json:"access_token"`
-	TokenType    string `json:"token_type"`
-	ExpiresIn    int64  `json:"expires_in"`
-	RefreshToken string `json:"refresh_token,omitempty"`
-	User         struct {
-		ID       string `json:"id"`
-		Username string `json:"username"`
-		Email    string `json:"email"`
-		Role     string `json:"role"`
-	} `json:"user"`
-}
+		// Encryption key administration
+		if encryptionKeysHandler != nil {
+			admin.POST("/encryption/users/:user_id/rotate", middleware.ValidateUUID("user_id"), encryptionKeysHandler.RotateUserKey)
+			admin.POST("/encryption/rewrap", encryptionKeysHandler.RewrapKeys)
+		}
 
-// RefreshTokenRequest represents a refresh token request
-type RefreshTokenRequest struct {
-	RefreshToken string `json:"refresh_token" binding:"required"`
+		// User erasure receipts
+		admin.GET("/erasures/:id", middleware.ValidateUUID("id"), erasureHandler.GetErasure)
+
+		// Audit log
+		if auditLogHandler != nil {
+			admin.GET("/audit", auditLogHandler.ListEntries)
+			admin.GET("/audit/verify", auditLogHandler.VerifyChain)
+		}
+	}
 }
 
-// handleLogin handles user authentication and JWT token generation
-func (a *App) handleLogin(c *gin.Context) {
-	var req LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "invalid_request", "Invalid request format")
-		return
+// startGRPC serves the gRPC API in the background. Calls are authenticated
+// and scoped like the matching HTTP routes.
+func (a *App) startGRPC(host string, memories memory.Service, users user.Service, limiter ratelimit.Limiter) error {
+	authn := middleware.NewGRPCAuth(a.jwtService, a.apiKeys).
+		Public(
+			healthpb.Health_Check_FullMethodName,
+			healthpb.Health_Watch_FullMethodName,
+		).
+		RequireScope(apikey.ScopeMemoriesRead,
+			membankv1.MemoryService_GetMemory_FullMethodName,
+			membankv1.MemoryService_ListMemories_FullMethodName,
+			membankv1.MemoryService_StreamMemories_FullMethodName,
+			membankv1.MemoryService_SearchMemories_FullMethodName,
+			membankv1.MemoryService_SearchSimilarMemories_FullMethodName,
+			membankv1.MemoryService_GetMemoryStats_FullMethodName,
+		).
+		RequireScope(apikey.ScopeMemoriesWrite,
+			membankv1.MemoryService_CreateMemory_FullMethodName,
+			membankv1.MemoryService_BatchCreateMemories_FullMethodName,
+			membankv1.MemoryService_UpdateMemory_FullMethodName,
+			membankv1.MemoryService_DeleteMemory_FullMethodName,
+		).
+		RequireScope(apikey.ScopeAdmin,
+			membankv1.UserService_CreateUser_FullMethodName,
+			membankv1.UserService_GetUser_FullMethodName,
+			membankv1.UserService_GetUserByUsername_FullMethodName,
+			membankv1.UserService_GetUserByEmail_FullMethodName,
+			membankv1.UserService_UpdateUser_FullMethodName,
+			membankv1.UserService_DeleteUser_FullMethodName,
+			membankv1.UserService_ListUsers_FullMethodName,
+			membankv1.UserService_GetUserStats_FullMethodName,
+		)
+	logUnary, logStream := middleware.GRPCLogger(a.logger)
+	recoverUnary, recoverStream := middleware.GRPCRecovery(a.logger)
+	unary := []grpc.UnaryServerInterceptor{logUnary, recoverUnary}
+	stream := []grpc.StreamServerInterceptor{logStream, recoverStream}
+	if limiter != nil {
+		limitUnary, limitStream := middleware.GRPCRateLimit(limiter, rateLimitPolicy(a.config), a.jwtService, a.apiKeys, a.logger)
+		unary = append(unary, limitUnary)
+		stream = append(stream, limitStream)
 	}
 
-	// TODO: Implement actual user authentication
-	// For now, use a simple demonstration authentication
-	if req.Username == "demo" && req.Password == "demo123" {
-		userID := uuid.New()
+	a.grpcServer = grpc.NewServer(
+		grpc.MaxRecvMsgSize(a.config.GRPC.MaxMessageSize),
+		grpc.ChainUnaryInterceptor(append(unary, authn.Unary())...),
+		grpc.ChainStreamInterceptor(append(stream, authn.Stream())...),
+	)
+	membankv1.RegisterMemoryServiceServer(a.grpcServer, memoryGRPC.NewServer(memories, a.logger))
+	membankv1.RegisterUserServiceServer(a.grpcServer, userGRPC.NewServer(users, a.logger))
+	healthpb.RegisterHealthServer(a.grpcServer, health.NewServer())
 
-		// Generate JWT token
-		token, err := a.jwtService.GenerateToken(userID, req.Username, "demo@example.com", "user")
-		if err != nil {
-			a.logger.WithError(err).Error("Failed to generate JWT token")
-			response.InternalError(c, "Failed to generate authentication token")
-			return
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, a.config.GRPC.Port))
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC: %w", err)
+	}
+
+	a.logger.WithField("address", listener.Addr().String()).Info("Starting gRPC server")
+	go func() {
+		if err := a.grpcServer.Serve(listener); err != nil {
+			a.logger.WithError(err).Error("gRPC server stopped")
 		}
+	}()
+	return nil
+}
 
-		loginResp := LoginResponse{
-			Success:     true,
-			AccessToken: token,
-			TokenType:   "Bearer",
-			ExpiresIn:   int64(a.config.Security.JWTExpiry.Seconds()),
+// promoteAdmins gives the admin role to the users named in security.admins.
+// Users missing at startup are promoted on the next start after they sign up.
+func (a *App) promoteAdmins(ctx context.Context, users user.Service) {
+	role := user.RoleAdmin
+	for _, name := range a.config.Security.Admins {
+		u, err := users.GetUserByUsername(ctx, name)
+		if errors.Is(err, user.ErrNotFound) {
+			u, err = users.GetUserByEmail(ctx, name)
+		}
+		if err != nil {
+			a.logger.WithError(err).WithField("admin", name).Warn("Configured admin not found")
+			continue
+		}
+		if u.Role == user.RoleAdmin {
+			continue
 		}
-		loginResp.User.ID = userID.String()
-		loginResp.User.Username = req.Username
-		loginResp.User.Email = "demo@example.com"
-		loginResp.User.Role = "user"
 
-		response.Success(c, http.StatusOK, loginResp)
-		return
+		if _, err := users.UpdateUser(ctx, u.ID, user.UpdateRequest{Role: &role}); err != nil {
+			a.logger.WithError(err).WithField("user_id", u.ID.String()).Error("Failed to promote configured admin")
+			continue
+		}
+		a.logger.WithField("user_id", u.ID.String()).Info("Promoted configured admin")
 	}
-
-	// Authentication failed
-	response.Unauthorized(c, "Invalid credentials")
 }
 
-// handleRefreshToken handles JWT token refresh
-func (a *App) handleRefreshToken(c *gin.Context) {
-	var req RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "invalid_request", "Invalid request format")
-		return
+// newJobQueue creates the job queue for the configured backend
+func (a *App) newJobQueue() queue.Queue {
+	config := queue.Config{
+		Backend:             a.config.Queue.Backend,
+		QueueName:           a.config.Queue.QueueName,
+		MaxRetries:          a.config.Queue.MaxRetries,
+		RetryDelay:          a.config.Queue.RetryDelay,
+		JobTimeout:          a.config.Queue.JobTimeout,
+		ResultTTL:           a.config.Queue.ResultTTL,
+		DefaultConcurrency:  a.config.Queue.DefaultConcurrency,
+		PollInterval:        a.config.Queue.PollInterval,
+		CleanupInterval:     a.config.Queue.CleanupInterval,
+		ConsumerGroup:       a.config.Queue.ConsumerGroup,
+		ConsumerName:        a.config.Queue.ConsumerName,
+		ClaimMinIdle:        a.config.Queue.ClaimMinIdle,
+		StreamMaxLen:        a.config.Queue.StreamMaxLen,
+		StatsEnabled:        a.config.Queue.StatsEnabled,
+		StatsUpdateInterval: a.config.Queue.StatsUpdateInterval,
 	}
 
-	// Refresh the token
-	newToken, err := a.jwtService.RefreshToken(req.RefreshToken)
-	if err != nil {
-		response.Unauthorized(c, err.Error())
-		return
+	var backend queue.Queue
+	switch config.Backend {
+	case queue.BackendMemory:
+		a.logger.Info("Using in-memory job queue")
+		backend = queue.NewMemoryQueue(a.logger, config)
+	case queue.BackendRedisStreams:
+		a.logger.Info("Using Redis Streams job queue")
+		backend = queue.NewRedisStreamsQueue(a.redis, a.logger, config)
+	default:
+		backend = queue.NewRedisQueue(a.redis, a.logger, config)
 	}
 
-	// Get claims from new token to return user info
-	claims, err := a.jwtService.ValidateToken(newToken)
-	if err != nil {
-		a.logger.WithError(err).Error("Failed to validate refreshed token")
-		response.InternalError(c, "Token validation failed")
-		return
-	}
-
-	refreshResp := LoginResponse{
-		Success:     true,
-		AccessToken: newToken,
-		TokenType:   "Bearer",
-		ExpiresIn:   int64(a.config.Security.JWTExpiry.Seconds()),
-	}
-	refreshResp.User.ID = claims.UserID.String()
-	refreshResp.User.Username = claims.Username
-	refreshResp.User.Email = claims.Email
-	refreshResp.User.Role = claims.Role
-
-	response.Success(c, http.StatusOK, refreshResp)
+	return queue.NewInstrumentedQueue(backend, a.logger, config)
 }
 
-// handleLogout handles user logout (token invalidation)
-func (a *App) handleLogout(c *gin.Context) {
-	// For stateless JWT, logout is handled client-side by removing the token
-	// In a production system, you might want to implement a token blacklist
-	response.Success(c, http.StatusOK, gin.H{
-		"message": "Successfully logged out",
-	})
-}
+// newPIIDetector builds the personal data detector from the built-in rules
+// that are not disabled and the rules in config
+func newPIIDetector(config configs.PIIConfig) (*pkgpii.Detector, error) {
+	disabled := make(map[string]bool, len(config.DisabledRules))
+	for _, kind := range config.DisabledRules {
+		disabled[kind] = true
+	}
 
-// loadAPIKeysFromConfig loads API keys from configuration or environment variables
-func (a *App) loadAPIKeysFromConfig() map[string]string {
-	keys := make(map[string]string)
-
-	// Try to load from environment variable first
-	if envKeys := os.Getenv("API_KEYS"); envKeys != "" {
-		// Expected format: "key1:user1,key2:user2"
-		pairs := strings.Split(envKeys, ",")
-		for _, pair := range pairs {
-			kv := strings.SplitN(pair, ":", 2)
-			if len(kv) == 2 {
-				keys[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
-			}
+	var rules []pkgpii.Rule
+	for _, rule := range pkgpii.BuiltinRules() {
+		if !disabled[rule.Kind] {
+			rules = append(rules, rule)
 		}
 	}
-
-	// Fallback to config file or default development key
-	if len(keys) == 0 {
-		a.logger.Warn("No API keys found in environment variables, using development fallback")
-		if gin.IsDebugging() {
-			// Only use development key in debug mode
-			if devKey := os.Getenv("DEV_API_KEY"); devKey != "" {
-				keys[devKey] = "system"
-			} else {
-				a.logger.Error("No API keys configured and DEV_API_KEY not set")
-			}
-		} else {
-			a.logger.Error("No API keys configured for production mode")
+	for _, rc := range config.Rules {
+		rule, err := pkgpii.NewRule(rc.Kind, rc.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PII rule: %w", err)
 		}
+		rules = append(rules, rule)
 	}
-
-	return keys
+	return pkgpii.NewDetector(rules...), nil
 }
 
 // Shutdown gracefully shuts down the application
@@ -473,6 +887,20 @@ func (a *App) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// Let gRPC calls finish, cutting those still running when ctx ends
+	if a.grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			a.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			a.grpcServer.Stop()
+		}
+	}
+
 	// Shutdown HTTP server
 	if a.server != nil {
 		return a.server.Shutdown(ctx)
@@ -480,3 +908,98 @@ func (a *App) Shutdown(ctx context.Context) error {
 
 	return nil
 }
+
+// embeddingPipelines converts configured preprocessing pipelines for the
+// embedding service. Invalid pipelines fail startup rather than fall back, as
+// a misspelt step could silently send unmasked text to the provider.
+func embeddingPipelines(pipelines map[string]configs.EmbeddingPipelineConfig) (map[string]embeddingService.PipelineConfig, error) {
+	result := make(map[string]embeddingService.PipelineConfig, len(pipelines))
+	for model, pipeline := range pipelines {
+		config := embeddingService.PipelineConfig{
+			Steps:     pipeline.Steps,
+			MaxRunes:  pipeline.MaxRunes,
+			MaxTokens: pipeline.MaxTokens,
+		}
+		if _, err := embeddingService.NewPipeline(config); err != nil {
+			return nil, fmt.Errorf("invalid embedding pipeline for %s: %w", model, err)
+		}
+		result[model] = config
+	}
+	return result, nil
+}
+
+// llmPricing converts configured model prices into usage service pricing
+func llmPricing(pricing map[string]configs.LLMPricingConfig) map[string]usageService.Pricing {
+	result := make(map[string]usageService.Pricing, len(pricing))
+	for model, price := range pricing {
+		result[model] = usageService.Pricing{
+			PromptPer1K:     price.PromptPer1K,
+			CompletionPer1K: price.CompletionPer1K,
+		}
+	}
+	return result
+}
+
+// quotaPlans converts configured quota plans into quota service limits
+func quotaPlans(plans map[string]configs.QuotaPlanConfig) map[string]quota.Limits {
+	result := make(map[string]quota.Limits, len(plans))
+	for name, plan := range plans {
+		result[name] = quota.Limits{
+			MaxMemories:             plan.MaxMemories,
+			MaxStorageBytes:         plan.MaxStorageBytes,
+			MonthlyEmbeddingTokens:  plan.MonthlyEmbeddingTokens,
+			MonthlyCompletionTokens: plan.MonthlyCompletionTokens,
+		}
+	}
+	return result
+}
+
+// newRateLimiter returns the limiter for API calls, or nil when rate
+// limiting is disabled
+func (a *App) newRateLimiter() ratelimit.Limiter {
+	switch {
+	case !a.config.RateLimit.Enabled:
+		return nil
+	case a.config.RateLimit.Backend == "memory":
+		return ratelimit.NewMemoryLimiter()
+	case a.redis == nil:
+		// Redis is optional with the in-memory queue; limits then only
+		// hold per replica
+		a.logger.Warn("Redis unavailable, rate limiting in memory")
+		return ratelimit.NewMemoryLimiter()
+	default:
+		return ratelimit.NewRedisLimiter(a.redis, "mem_bank:ratelimit:")
+	}
+}
+
+// rateLimitPolicy builds the request rate limits from configuration
+func rateLimitPolicy(config *configs.Config) middleware.RateLimitPolicy {
+	rule := func(r configs.RateLimitRuleConfig) ratelimit.Limit {
+		limit := ratelimit.Limit{Requests: r.Requests, Period: r.Period, Burst: r.Burst}
+		if limit.Period <= 0 {
+			limit.Period = time.Minute
+		}
+		return limit
+	}
+
+	policy := middleware.RateLimitPolicy{
+		Default: ratelimit.Limit{
+			Requests: config.Security.RateLimit,
+			Period:   time.Minute,
+			Burst:    config.RateLimit.Burst,
+		},
+		Roles: make(map[string]ratelimit.Limit, len(config.RateLimit.Roles)),
+	}
+	for role, r := range config.RateLimit.Roles {
+		policy.Roles[role] = rule(r)
+	}
+	for _, route := range config.RateLimit.Routes {
+		policy.Routes = append(policy.Routes, middleware.RouteRateLimit{
+			Method: route.Method,
+			Path:   route.Path,
+			Role:   route.Role,
+			Limit:  rule(route.RateLimitRuleConfig),
+		})
+	}
+	return policy
+}
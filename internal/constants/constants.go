@@ -162,8 +162,11 @@ const (
 	HeaderRequestID       = "X-Request-ID"
 	HeaderResponseTime    = "X-Response-Time"
 	HeaderResponseTimeMs  = "X-Response-Time-Ms"
-	HeaderRateLimitLimit  = "X-RateLimit-Limit"
-	HeaderRateLimitRemain = "X-RateLimit-Remaining"
+	HeaderRateLimitLimit  = "RateLimit-Limit"
+	HeaderRateLimitRemain = "RateLimit-Remaining"
+	HeaderRateLimitReset  = "RateLimit-Reset"
+	HeaderRateLimitPolicy = "RateLimit-Policy"
+	HeaderRetryAfter      = "Retry-After"
 )
 
 // Security headers
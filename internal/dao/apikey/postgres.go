@@ -0,0 +1,171 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"mem_bank/internal/domain/apikey"
+	"mem_bank/internal/domain/user"
+)
+
+// apiKeyRow mirrors a row of the api_keys table
+type apiKeyRow struct {
+	ID         string         `gorm:"column:id;primaryKey"`
+	UserID     string         `gorm:"column:user_id"`
+	Name       string         `gorm:"column:name"`
+	Prefix     string         `gorm:"column:prefix"`
+	KeyHash    string         `gorm:"column:key_hash"`
+	Scopes     pq.StringArray `gorm:"column:scopes;type:text[]"`
+	ExpiresAt  *time.Time     `gorm:"column:expires_at"`
+	LastUsedAt *time.Time     `gorm:"column:last_used_at"`
+	CreatedAt  time.Time      `gorm:"column:created_at"`
+	RevokedAt  *time.Time     `gorm:"column:revoked_at"`
+}
+
+func (apiKeyRow) TableName() string { return "api_keys" }
+
+// postgresRepository implements apikey.Repository using PostgreSQL
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based API key repository
+func NewPostgresRepository(db *gorm.DB) apikey.Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) Store(ctx context.Context, key *apikey.APIKey) error {
+	row := &apiKeyRow{
+		ID:        key.ID.String(),
+		UserID:    key.UserID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.KeyHash,
+		Scopes:    pq.StringArray(key.Scopes),
+		ExpiresAt: timePtr(key.ExpiresAt),
+		CreatedAt: key.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("storing API key: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) FindByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error) {
+	return r.find(ctx, "id = ?", id.String())
+}
+
+func (r *postgresRepository) FindByHash(ctx context.Context, keyHash string) (*apikey.APIKey, error) {
+	return r.find(ctx, "key_hash = ?", keyHash)
+}
+
+func (r *postgresRepository) find(ctx context.Context, query string, args ...interface{}) (*apikey.APIKey, error) {
+	var row apiKeyRow
+	err := r.db.WithContext(ctx).Where(query, args...).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apikey.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding API key: %w", err)
+	}
+	return toAPIKey(&row)
+}
+
+func (r *postgresRepository) ListByUserID(ctx context.Context, userID user.ID) ([]*apikey.APIKey, error) {
+	var rows []apiKeyRow
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID.String()).
+		Order("created_at DESC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("listing API keys: %w", err)
+	}
+
+	keys := make([]*apikey.APIKey, 0, len(rows))
+	for i := range rows {
+		key, err := toAPIKey(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (r *postgresRepository) CountActiveByUserID(ctx context.Context, userID user.ID) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&apiKeyRow{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID.String()).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("counting API keys: %w", err)
+	}
+	return int(count), nil
+}
+
+func (r *postgresRepository) Revoke(ctx context.Context, userID user.ID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&apiKeyRow{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id.String(), userID.String()).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("revoking API key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apikey.ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, t time.Time) error {
+	err := r.db.WithContext(ctx).Model(&apiKeyRow{}).
+		Where("id = ?", id.String()).
+		Update("last_used_at", t).Error
+	if err != nil {
+		return fmt.Errorf("updating API key last use: %w", err)
+	}
+	return nil
+}
+
+func toAPIKey(row *apiKeyRow) (*apikey.APIKey, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing API key ID: %w", err)
+	}
+	userID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+
+	key := &apikey.APIKey{
+		ID:        id,
+		UserID:    user.ID(userID),
+		Name:      row.Name,
+		Prefix:    row.Prefix,
+		KeyHash:   row.KeyHash,
+		Scopes:    []string(row.Scopes),
+		CreatedAt: row.CreatedAt,
+	}
+	if row.ExpiresAt != nil {
+		key.ExpiresAt = *row.ExpiresAt
+	}
+	if row.LastUsedAt != nil {
+		key.LastUsedAt = *row.LastUsedAt
+	}
+	if row.RevokedAt != nil {
+		key.RevokedAt = *row.RevokedAt
+	}
+	return key, nil
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,217 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mem_bank/internal/domain/audit"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+)
+
+// chainLockKey serializes appends while the hash chain is enabled, so each
+// entry links to the one appended just before it
+const chainLockKey = 0x61756469 // "audi"
+
+// entryRow mirrors a row of the audit_log table
+type entryRow struct {
+	Sequence      int64     `gorm:"column:sequence;primaryKey;autoIncrement"`
+	ID            string    `gorm:"column:id"`
+	ActorType     string    `gorm:"column:actor_type"`
+	ActorUserID   *string   `gorm:"column:actor_user_id"`
+	ActorRole     *string   `gorm:"column:actor_role"`
+	ActorAPIKeyID *string   `gorm:"column:actor_api_key_id"`
+	Action        string    `gorm:"column:action"`
+	ResourceType  string    `gorm:"column:resource_type"`
+	ResourceID    string    `gorm:"column:resource_id"`
+	RequestID     *string   `gorm:"column:request_id"`
+	BeforeHash    *string   `gorm:"column:before_hash"`
+	AfterHash     *string   `gorm:"column:after_hash"`
+	PrevHash      *string   `gorm:"column:prev_hash"`
+	Hash          *string   `gorm:"column:hash"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+func (entryRow) TableName() string { return "audit_log" }
+
+// postgresRepository implements audit.Repository using PostgreSQL. The log
+// is only written by the audit job and read by admins, so the repository
+// bypasses row-level security.
+type postgresRepository struct {
+	db      *gorm.DB
+	chained bool
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based audit repository.
+// When chained is true each entry carries a hash linking it to the entry
+// before it, so changes to the log can be detected.
+func NewPostgresRepository(db *gorm.DB, chained bool) audit.Repository {
+	return &postgresRepository{db: db, chained: chained}
+}
+
+func (r *postgresRepository) transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+	return database.Transaction(ctx, r.db, fn)
+}
+
+func (r *postgresRepository) Append(ctx context.Context, e *audit.Entry) error {
+	row := toRow(e)
+	err := r.transaction(ctx, func(tx *gorm.DB) error {
+		if r.chained {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", chainLockKey).Error; err != nil {
+				return fmt.Errorf("locking audit log: %w", err)
+			}
+			var last entryRow
+			result := tx.Where("hash IS NOT NULL").Order("sequence DESC").Limit(1).Find(&last)
+			if result.Error != nil {
+				return fmt.Errorf("finding last audit entry: %w", result.Error)
+			}
+			prevHash := ""
+			if result.RowsAffected > 0 {
+				prevHash = *last.Hash
+			}
+			hash := e.ChainHash(prevHash)
+			row.PrevHash, row.Hash = &prevHash, &hash
+		}
+		// A retried audit job appends the same entry again
+		return tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, DoNothing: true}).Create(row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("appending audit entry: %w", err)
+	}
+
+	e.Sequence = row.Sequence
+	e.PrevHash = stringValue(row.PrevHash)
+	e.Hash = stringValue(row.Hash)
+	return nil
+}
+
+func (r *postgresRepository) Find(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	var rows []entryRow
+	err := r.transaction(ctx, func(tx *gorm.DB) error {
+		query := tx.Model(&entryRow{})
+		if !filter.ActorID.IsZero() {
+			query = query.Where("actor_user_id = ?", filter.ActorID.String())
+		}
+		if filter.Action != "" {
+			query = query.Where("action = ?", filter.Action)
+		}
+		if filter.ResourceType != "" {
+			query = query.Where("resource_type = ?", filter.ResourceType)
+		}
+		if filter.ResourceID != "" {
+			query = query.Where("resource_id = ?", filter.ResourceID)
+		}
+		if filter.RequestID != "" {
+			query = query.Where("request_id = ?", filter.RequestID)
+		}
+		if !filter.Since.IsZero() {
+			query = query.Where("created_at >= ?", filter.Since)
+		}
+		if !filter.Until.IsZero() {
+			query = query.Where("created_at < ?", filter.Until)
+		}
+		return query.Order("sequence DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding audit entries: %w", err)
+	}
+	return toEntries(rows)
+}
+
+func (r *postgresRepository) ListAfter(ctx context.Context, sequence int64, limit int) ([]*audit.Entry, error) {
+	var rows []entryRow
+	err := r.transaction(ctx, func(tx *gorm.DB) error {
+		return tx.Where("sequence > ?", sequence).Order("sequence").Limit(limit).Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing audit entries: %w", err)
+	}
+	return toEntries(rows)
+}
+
+func toRow(e *audit.Entry) *entryRow {
+	row := &entryRow{
+		ID:            e.ID.String(),
+		ActorType:     e.Actor.Type,
+		ActorRole:     stringPtr(e.Actor.Role),
+		ActorAPIKeyID: stringPtr(e.Actor.APIKeyID),
+		Action:        e.Action,
+		ResourceType:  e.ResourceType,
+		ResourceID:    e.ResourceID,
+		RequestID:     stringPtr(e.RequestID),
+		BeforeHash:    stringPtr(e.BeforeHash),
+		AfterHash:     stringPtr(e.AfterHash),
+		CreatedAt:     e.CreatedAt,
+	}
+	if !e.Actor.UserID.IsZero() {
+		row.ActorUserID = stringPtr(e.Actor.UserID.String())
+	}
+	return row
+}
+
+func toEntries(rows []entryRow) ([]*audit.Entry, error) {
+	entries := make([]*audit.Entry, 0, len(rows))
+	for i := range rows {
+		e, err := toEntry(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func toEntry(row *entryRow) (*audit.Entry, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing audit entry ID: %w", err)
+	}
+
+	e := &audit.Entry{
+		ID:       audit.ID(id),
+		Sequence: row.Sequence,
+		Actor: audit.Actor{
+			Type:     row.ActorType,
+			Role:     stringValue(row.ActorRole),
+			APIKeyID: stringValue(row.ActorAPIKeyID),
+		},
+		Action:       row.Action,
+		ResourceType: row.ResourceType,
+		ResourceID:   row.ResourceID,
+		RequestID:    stringValue(row.RequestID),
+		BeforeHash:   stringValue(row.BeforeHash),
+		AfterHash:    stringValue(row.AfterHash),
+		PrevHash:     stringValue(row.PrevHash),
+		Hash:         stringValue(row.Hash),
+		CreatedAt:    row.CreatedAt,
+	}
+	if row.ActorUserID != nil {
+		actorID, err := uuid.Parse(*row.ActorUserID)
+		if err != nil {
+			return nil, fmt.Errorf("parsing actor user ID: %w", err)
+		}
+		e.Actor.UserID = user.ID(actorID)
+	}
+	return e, nil
+}
+
+// stringPtr maps empty strings to NULL
+func stringPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+)
+
+// identityRow mirrors a row of the user_identities table
+type identityRow struct {
+	ID        string     `gorm:"column:id;primaryKey"`
+	UserID    string     `gorm:"column:user_id"`
+	Issuer    string     `gorm:"column:issuer"`
+	Subject   string     `gorm:"column:subject"`
+	Email     string     `gorm:"column:email"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	LastLogin *time.Time `gorm:"column:last_login"`
+}
+
+func (identityRow) TableName() string { return "user_identities" }
+
+// identityRepository implements auth.IdentityRepository using PostgreSQL
+type identityRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityRepository creates a new PostgreSQL-based identity repository
+func NewIdentityRepository(db *gorm.DB) auth.IdentityRepository {
+	return &identityRepository{db: db}
+}
+
+func (r *identityRepository) FindByIssuerSubject(ctx context.Context, issuer, subject string) (*auth.Identity, error) {
+	var row identityRow
+	err := r.db.WithContext(ctx).Where("issuer = ? AND subject = ?", issuer, subject).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, auth.ErrIdentityNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding identity: %w", err)
+	}
+
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing identity ID: %w", err)
+	}
+	userID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+
+	identity := &auth.Identity{
+		ID:        id,
+		UserID:    user.ID(userID),
+		Issuer:    row.Issuer,
+		Subject:   row.Subject,
+		Email:     row.Email,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.LastLogin != nil {
+		identity.LastLogin = *row.LastLogin
+	}
+	return identity, nil
+}
+
+func (r *identityRepository) Store(ctx context.Context, identity *auth.Identity) error {
+	row := &identityRow{
+		ID:        identity.ID.String(),
+		UserID:    identity.UserID.String(),
+		Issuer:    identity.Issuer,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("storing identity: %w", err)
+	}
+	return nil
+}
+
+func (r *identityRepository) TouchLastLogin(ctx context.Context, id uuid.UUID, t time.Time) error {
+	err := r.db.WithContext(ctx).Model(&identityRow{}).
+		Where("id = ?", id.String()).
+		Update("last_login", t).Error
+	if err != nil {
+		return fmt.Errorf("updating identity last login: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+)
+
+// credentialRow mirrors a row of the user_credentials table
+type credentialRow struct {
+	UserID            string     `gorm:"column:user_id;primaryKey"`
+	PasswordHash      string     `gorm:"column:password_hash"`
+	FailedAttempts    int        `gorm:"column:failed_attempts"`
+	LockedUntil       *time.Time `gorm:"column:locked_until"`
+	PasswordChangedAt time.Time  `gorm:"column:password_changed_at"`
+	UpdatedAt         time.Time
+}
+
+func (credentialRow) TableName() string { return "user_credentials" }
+
+// postgresRepository implements auth.CredentialRepository using PostgreSQL
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based credential repository
+func NewPostgresRepository(db *gorm.DB) auth.CredentialRepository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) FindByUserID(ctx context.Context, userID user.ID) (*auth.Credential, error) {
+	var row credentialRow
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID.String()).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, auth.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding credential: %w", err)
+	}
+
+	credential := &auth.Credential{
+		UserID:            userID,
+		PasswordHash:      row.PasswordHash,
+		FailedAttempts:    row.FailedAttempts,
+		PasswordChangedAt: row.PasswordChangedAt,
+		UpdatedAt:         row.UpdatedAt,
+	}
+	if row.LockedUntil != nil {
+		credential.LockedUntil = *row.LockedUntil
+	}
+	return credential, nil
+}
+
+func (r *postgresRepository) Save(ctx context.Context, credential *auth.Credential) error {
+	credential.UpdatedAt = time.Now()
+	row := &credentialRow{
+		UserID:            credential.UserID.String(),
+		PasswordHash:      credential.PasswordHash,
+		FailedAttempts:    credential.FailedAttempts,
+		PasswordChangedAt: credential.PasswordChangedAt,
+		UpdatedAt:         credential.UpdatedAt,
+	}
+	if !credential.LockedUntil.IsZero() {
+		row.LockedUntil = &credential.LockedUntil
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		UpdateAll: true,
+	}).Create(row).Error
+	if err != nil {
+		return fmt.Errorf("saving credential: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) RecordFailure(ctx context.Context, userID user.ID) (int, error) {
+	var attempts int
+	err := r.db.WithContext(ctx).Raw(
+		`UPDATE user_credentials
+		SET failed_attempts = failed_attempts + 1, updated_at = NOW()
+		WHERE user_id = ?
+		RETURNING failed_attempts`,
+		userID.String(),
+	).Scan(&attempts).Error
+	if err != nil {
+		return 0, fmt.Errorf("recording failed login: %w", err)
+	}
+	return attempts, nil
+}
+
+func (r *postgresRepository) Lock(ctx context.Context, userID user.ID, until time.Time) error {
+	err := r.db.WithContext(ctx).Model(&credentialRow{}).
+		Where("user_id = ?", userID.String()).
+		Updates(map[string]interface{}{
+			"locked_until": until,
+			"updated_at":   time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("locking credential: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) ResetFailures(ctx context.Context, userID user.ID) error {
+	err := r.db.WithContext(ctx).Model(&credentialRow{}).
+		Where("user_id = ?", userID.String()).
+		Updates(map[string]interface{}{
+			"failed_attempts": 0,
+			"locked_until":    nil,
+			"updated_at":      time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("resetting failed logins: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+)
+
+// refreshTokenRow mirrors a row of the refresh_tokens table
+type refreshTokenRow struct {
+	ID         string     `gorm:"column:id;primaryKey"`
+	UserID     string     `gorm:"column:user_id"`
+	FamilyID   string     `gorm:"column:family_id"`
+	TokenHash  string     `gorm:"column:token_hash"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UsedAt     *time.Time `gorm:"column:used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	ReplacedBy *string    `gorm:"column:replaced_by"`
+}
+
+func (refreshTokenRow) TableName() string { return "refresh_tokens" }
+
+// refreshTokenRepository implements auth.RefreshTokenRepository using PostgreSQL
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository creates a new PostgreSQL-based refresh token repository
+func NewRefreshTokenRepository(db *gorm.DB) auth.RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Store(ctx context.Context, token *auth.RefreshToken) error {
+	row := &refreshTokenRow{
+		ID:        token.ID.String(),
+		UserID:    token.UserID.String(),
+		FamilyID:  token.FamilyID.String(),
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("storing refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*auth.RefreshToken, error) {
+	var row refreshTokenRow
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding refresh token: %w", err)
+	}
+
+	return toRefreshToken(&row)
+}
+
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID, replacedBy uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&refreshTokenRow{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id.String()).
+		Updates(map[string]interface{}{
+			"used_at":     time.Now(),
+			"replaced_by": replacedBy.String(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("marking refresh token used: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(&refreshTokenRow{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID.String()).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("revoking refresh token family: %w", err)
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) RevokeUser(ctx context.Context, userID user.ID) ([]uuid.UUID, error) {
+	var families []string
+	err := r.db.WithContext(ctx).Raw(
+		`UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE user_id = ? AND revoked_at IS NULL
+		RETURNING family_id`,
+		userID.String(),
+	).Scan(&families).Error
+	if err != nil {
+		return nil, fmt.Errorf("revoking refresh tokens: %w", err)
+	}
+
+	seen := make(map[string]bool, len(families))
+	ids := make([]uuid.UUID, 0, len(families))
+	for _, family := range families {
+		if seen[family] {
+			continue
+		}
+		seen[family] = true
+
+		id, err := uuid.Parse(family)
+		if err != nil {
+			return nil, fmt.Errorf("parsing family ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func toRefreshToken(row *refreshTokenRow) (*auth.RefreshToken, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing refresh token ID: %w", err)
+	}
+	userID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+	familyID, err := uuid.Parse(row.FamilyID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing family ID: %w", err)
+	}
+
+	token := &auth.RefreshToken{
+		ID:        id,
+		UserID:    user.ID(userID),
+		FamilyID:  familyID,
+		TokenHash: row.TokenHash,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.UsedAt != nil {
+		token.UsedAt = *row.UsedAt
+	}
+	if row.RevokedAt != nil {
+		token.RevokedAt = *row.RevokedAt
+	}
+	if row.ReplacedBy != nil {
+		if replacedBy, err := uuid.Parse(*row.ReplacedBy); err == nil {
+			token.ReplacedBy = replacedBy
+		}
+	}
+	return token, nil
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	pkgauth "mem_bank/pkg/auth"
+)
+
+// signingKeyRow mirrors a row of the jwt_signing_keys table
+type signingKeyRow struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	Algorithm  string    `gorm:"column:algorithm"`
+	PrivateKey []byte    `gorm:"column:private_key"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+	ActiveAt   time.Time `gorm:"column:active_at"`
+	RetireAt   time.Time `gorm:"column:retire_at"`
+}
+
+func (signingKeyRow) TableName() string { return "jwt_signing_keys" }
+
+// signingKeyStore implements pkgauth.KeyStore using PostgreSQL
+type signingKeyStore struct {
+	db *gorm.DB
+}
+
+// NewSigningKeyStore creates a new PostgreSQL-based JWT signing key store
+func NewSigningKeyStore(db *gorm.DB) pkgauth.KeyStore {
+	return &signingKeyStore{db: db}
+}
+
+func (s *signingKeyStore) List(ctx context.Context) ([]*pkgauth.SigningKey, error) {
+	var rows []signingKeyRow
+	if err := s.db.WithContext(ctx).Order("active_at DESC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("listing signing keys: %w", err)
+	}
+
+	keys := make([]*pkgauth.SigningKey, 0, len(rows))
+	for _, row := range rows {
+		signer, err := pkgauth.ParsePrivateKey(row.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", row.ID, err)
+		}
+		keys = append(keys, &pkgauth.SigningKey{
+			ID:         row.ID,
+			Algorithm:  row.Algorithm,
+			PrivateKey: signer,
+			CreatedAt:  row.CreatedAt,
+			ActiveAt:   row.ActiveAt,
+			RetireAt:   row.RetireAt,
+		})
+	}
+	return keys, nil
+}
+
+func (s *signingKeyStore) Add(ctx context.Context, key *pkgauth.SigningKey) error {
+	encoded, err := key.MarshalPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	row := &signingKeyRow{
+		ID:         key.ID,
+		Algorithm:  key.Algorithm,
+		PrivateKey: encoded,
+		CreatedAt:  key.CreatedAt,
+		ActiveAt:   key.ActiveAt,
+		RetireAt:   key.RetireAt,
+	}
+	if err := s.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("storing signing key: %w", err)
+	}
+	return nil
+}
+
+func (s *signingKeyStore) DeleteRetired(ctx context.Context, t time.Time) error {
+	if err := s.db.WithContext(ctx).Where("retire_at <= ?", t).Delete(&signingKeyRow{}).Error; err != nil {
+		return fmt.Errorf("deleting retired signing keys: %w", err)
+	}
+	return nil
+}
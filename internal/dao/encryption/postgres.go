@@ -0,0 +1,128 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mem_bank/pkg/database"
+	pkgencryption "mem_bank/pkg/encryption"
+)
+
+// dataKeyRow mirrors a row of the user_data_keys table
+type dataKeyRow struct {
+	UserID      string    `gorm:"column:user_id;primaryKey"`
+	Version     int       `gorm:"column:version;primaryKey"`
+	MasterKeyID string    `gorm:"column:master_key_id"`
+	WrappedKey  []byte    `gorm:"column:wrapped_key"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+}
+
+func (dataKeyRow) TableName() string { return "user_data_keys" }
+
+// keyStore implements pkgencryption.KeyStore using PostgreSQL. Keys are
+// needed whoever the caller is, for example to read a shared memory written
+// by another member, so the store bypasses row-level security.
+type keyStore struct {
+	db *gorm.DB
+}
+
+// NewKeyStore creates a new PostgreSQL-based data key store
+func NewKeyStore(db *gorm.DB) pkgencryption.KeyStore {
+	return &keyStore{db: db}
+}
+
+func (s *keyStore) transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+	return database.Transaction(ctx, s.db, fn)
+}
+
+func (s *keyStore) List(ctx context.Context, userID string) ([]*pkgencryption.StoredKey, error) {
+	var rows []dataKeyRow
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userID).Order("version DESC").Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing data keys: %w", err)
+	}
+	return toStoredKeys(rows), nil
+}
+
+func (s *keyStore) Add(ctx context.Context, key *pkgencryption.StoredKey) error {
+	row := &dataKeyRow{
+		UserID:      key.UserID,
+		Version:     key.Version,
+		MasterKeyID: key.MasterKeyID,
+		WrappedKey:  key.WrappedKey,
+		CreatedAt:   key.CreatedAt,
+	}
+	var created int64
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(row)
+		created = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("storing data key: %w", err)
+	}
+	if created == 0 {
+		return pkgencryption.ErrKeyExists
+	}
+	return nil
+}
+
+func (s *keyStore) ListNotWrappedBy(ctx context.Context, masterKeyID string, limit int) ([]*pkgencryption.StoredKey, error) {
+	var rows []dataKeyRow
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		return tx.Where("master_key_id <> ?", masterKeyID).
+			Order("user_id, version").
+			Limit(limit).
+			Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing data keys to rewrap: %w", err)
+	}
+	return toStoredKeys(rows), nil
+}
+
+func (s *keyStore) Rewrap(ctx context.Context, key *pkgencryption.StoredKey) error {
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		return tx.Model(&dataKeyRow{}).
+			Where("user_id = ? AND version = ?", key.UserID, key.Version).
+			Updates(map[string]interface{}{
+				"master_key_id": key.MasterKeyID,
+				"wrapped_key":   key.WrappedKey,
+			}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("rewrapping data key: %w", err)
+	}
+	return nil
+}
+
+func (s *keyStore) Delete(ctx context.Context, userID string) error {
+	err := s.transaction(ctx, func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userID).Delete(&dataKeyRow{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("deleting data keys: %w", err)
+	}
+	return nil
+}
+
+func toStoredKeys(rows []dataKeyRow) []*pkgencryption.StoredKey {
+	keys := make([]*pkgencryption.StoredKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, &pkgencryption.StoredKey{
+			UserID:      row.UserID,
+			Version:     row.Version,
+			MasterKeyID: row.MasterKeyID,
+			WrappedKey:  row.WrappedKey,
+			CreatedAt:   row.CreatedAt,
+		})
+	}
+	return keys
+}
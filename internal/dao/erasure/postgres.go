@@ -0,0 +1,165 @@
+package erasure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mem_bank/internal/domain/erasure"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+)
+
+// erasureRow mirrors a row of the user_erasures table
+type erasureRow struct {
+	ID          string     `gorm:"column:id;primaryKey"`
+	UserID      string     `gorm:"column:user_id"`
+	RequestedBy string     `gorm:"column:requested_by"`
+	Status      string     `gorm:"column:status"`
+	Stores      string     `gorm:"column:stores"`
+	LastError   *string    `gorm:"column:last_error"`
+	Receipt     *string    `gorm:"column:receipt"`
+	RequestedAt time.Time  `gorm:"column:requested_at"`
+	CompletedAt *time.Time `gorm:"column:completed_at"`
+}
+
+func (erasureRow) TableName() string { return "user_erasures" }
+
+// postgresRepository implements erasure.Repository using PostgreSQL.
+// Erasures are only handled by the erasure service, its job and admins, so
+// the repository bypasses row-level security.
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based erasure repository
+func NewPostgresRepository(db *gorm.DB) erasure.Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+	return database.Transaction(ctx, r.db, fn)
+}
+
+func (r *postgresRepository) Create(ctx context.Context, e *erasure.Erasure) error {
+	row, err := toRow(e)
+	if err != nil {
+		return err
+	}
+	err = r.transaction(ctx, func(tx *gorm.DB) error {
+		return tx.Create(row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("storing erasure: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) FindByID(ctx context.Context, id erasure.ID) (*erasure.Erasure, error) {
+	var row erasureRow
+	err := r.transaction(ctx, func(tx *gorm.DB) error {
+		return tx.Where("id = ?", id.String()).First(&row).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, erasure.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding erasure: %w", err)
+	}
+	return toErasure(&row)
+}
+
+func (r *postgresRepository) Update(ctx context.Context, e *erasure.Erasure) error {
+	row, err := toRow(e)
+	if err != nil {
+		return err
+	}
+	var result *gorm.DB
+	err = r.transaction(ctx, func(tx *gorm.DB) error {
+		result = tx.Model(&erasureRow{}).
+			Where("id = ?", row.ID).
+			Updates(map[string]interface{}{
+				"status":       row.Status,
+				"stores":       row.Stores,
+				"last_error":   row.LastError,
+				"receipt":      row.Receipt,
+				"completed_at": row.CompletedAt,
+			})
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("updating erasure: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return erasure.ErrNotFound
+	}
+	return nil
+}
+
+func toRow(e *erasure.Erasure) (*erasureRow, error) {
+	stores := e.Stores
+	if stores == nil {
+		stores = []erasure.StoreResult{}
+	}
+	data, err := json.Marshal(stores)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling store results: %w", err)
+	}
+
+	row := &erasureRow{
+		ID:          e.ID.String(),
+		UserID:      e.UserID.String(),
+		RequestedBy: e.RequestedBy.String(),
+		Status:      e.Status,
+		Stores:      string(data),
+		RequestedAt: e.RequestedAt,
+		CompletedAt: e.CompletedAt,
+	}
+	if e.LastError != "" {
+		row.LastError = &e.LastError
+	}
+	if e.Receipt != "" {
+		row.Receipt = &e.Receipt
+	}
+	return row, nil
+}
+
+func toErasure(row *erasureRow) (*erasure.Erasure, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing erasure ID: %w", err)
+	}
+	userID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+	requestedBy, err := uuid.Parse(row.RequestedBy)
+	if err != nil {
+		return nil, fmt.Errorf("parsing requester ID: %w", err)
+	}
+
+	e := &erasure.Erasure{
+		ID:          erasure.ID(id),
+		UserID:      user.ID(userID),
+		RequestedBy: user.ID(requestedBy),
+		Status:      row.Status,
+		RequestedAt: row.RequestedAt,
+		CompletedAt: row.CompletedAt,
+	}
+	if err := json.Unmarshal([]byte(row.Stores), &e.Stores); err != nil {
+		return nil, fmt.Errorf("unmarshaling store results: %w", err)
+	}
+	if row.LastError != nil {
+		e.LastError = *row.LastError
+	}
+	if row.Receipt != nil {
+		e.Receipt = *row.Receipt
+	}
+	return e, nil
+}
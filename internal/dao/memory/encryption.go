@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mem_bank/internal/domain/encryption"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/model"
+	"mem_bank/pkg/database"
+	pkgencryption "mem_bank/pkg/encryption"
+)
+
+// Encrypted fields of a memory; with the memory ID they form the associated
+// data of each ciphertext, so it cannot be moved to another field or row
+const (
+	fieldContent  = "content"
+	fieldSummary  = "summary"
+	fieldMetadata = "metadata"
+)
+
+// searchTokenRow mirrors a row of the memory_search_tokens table
+type searchTokenRow struct {
+	MemoryID string `gorm:"column:memory_id;primaryKey"`
+	Token    string `gorm:"column:token;primaryKey"`
+}
+
+func (searchTokenRow) TableName() string { return "memory_search_tokens" }
+
+// NewEncryptedPostgresRepository creates a PostgreSQL-based memory
+// repository that encrypts content, summary and metadata with the owner's
+// data key from keys. Embeddings stay in the clear for similarity search;
+// content search matches whole words through a blind index.
+func NewEncryptedPostgresRepository(db *gorm.DB, keys *pkgencryption.KeyRing) memory.Repository {
+	return &postgresRepository{db: db, keys: keys}
+}
+
+// NewReencrypter creates an encryption.MemoryReencrypter over the memories
+// in db, for use after a user's data key was rotated
+func NewReencrypter(db *gorm.DB, keys *pkgencryption.KeyRing) encryption.MemoryReencrypter {
+	return &postgresRepository{db: db, keys: keys}
+}
+
+func fieldAAD(memoryID, field string) []byte {
+	return []byte(memoryID + "/" + field)
+}
+
+// encrypt replaces the content, summary and metadata of gormMemory with
+// ciphertext under the owner's current data key
+func (r *postgresRepository) encrypt(ctx context.Context, gormMemory *model.Memory) error {
+	key, err := r.keys.Current(ctx, gormMemory.UserID)
+	if err != nil {
+		return fmt.Errorf("getting data key: %w", err)
+	}
+
+	seal := func(field, plaintext string) (string, error) {
+		ciphertext, err := key.Seal([]byte(plaintext), fieldAAD(gormMemory.ID, field))
+		if err != nil {
+			return "", fmt.Errorf("encrypting %s: %w", field, err)
+		}
+		return base64.StdEncoding.EncodeToString(ciphertext), nil
+	}
+
+	if gormMemory.Content, err = seal(fieldContent, gormMemory.Content); err != nil {
+		return err
+	}
+	if gormMemory.Summary != nil && *gormMemory.Summary != "" {
+		summary, err := seal(fieldSummary, *gormMemory.Summary)
+		if err != nil {
+			return err
+		}
+		gormMemory.Summary = &summary
+	}
+	if gormMemory.Metadata != nil {
+		metadata, err := seal(fieldMetadata, *gormMemory.Metadata)
+		if err != nil {
+			return err
+		}
+		// The column is JSONB, so the ciphertext goes in as a JSON string
+		quoted, _ := json.Marshal(metadata)
+		gormMemory.Metadata = stringPtr(string(quoted))
+	}
+	gormMemory.KeyVersion = intPtr(int32(key.Version))
+	return nil
+}
+
+// decrypt restores the plaintext of an encrypted gormMemory in place
+func (r *postgresRepository) decrypt(ctx context.Context, gormMemory *model.Memory) error {
+	if gormMemory.KeyVersion == nil {
+		return nil
+	}
+	if r.keys == nil {
+		return errors.New("memory is encrypted but encryption is not configured")
+	}
+
+	key, err := r.keys.Key(ctx, gormMemory.UserID, int(*gormMemory.KeyVersion))
+	if err != nil {
+		return fmt.Errorf("getting data key version %d: %w", *gormMemory.KeyVersion, err)
+	}
+
+	open := func(field, encoded string) (string, error) {
+		ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", fmt.Errorf("decoding %s: %w", field, err)
+		}
+		plaintext, err := key.Open(ciphertext, fieldAAD(gormMemory.ID, field))
+		if err != nil {
+			return "", fmt.Errorf("decrypting %s: %w", field, err)
+		}
+		return string(plaintext), nil
+	}
+
+	if gormMemory.Content, err = open(fieldContent, gormMemory.Content); err != nil {
+		return err
+	}
+	if gormMemory.Summary != nil && *gormMemory.Summary != "" {
+		summary, err := open(fieldSummary, *gormMemory.Summary)
+		if err != nil {
+			return err
+		}
+		gormMemory.Summary = &summary
+	}
+	if gormMemory.Metadata != nil {
+		var encoded string
+		if err := json.Unmarshal([]byte(*gormMemory.Metadata), &encoded); err != nil {
+			return fmt.Errorf("decoding metadata: %w", err)
+		}
+		metadata, err := open(fieldMetadata, encoded)
+		if err != nil {
+			return err
+		}
+		gormMemory.Metadata = &metadata
+	}
+	gormMemory.KeyVersion = nil
+	return nil
+}
+
+// searchTokens returns the blind index rows of a memory, or none when
+// memories are not encrypted
+func (r *postgresRepository) searchTokens(ctx context.Context, m *memory.Memory) ([]searchTokenRow, error) {
+	if r.keys == nil {
+		return nil, nil
+	}
+	key, err := r.keys.Current(ctx, m.UserID.String())
+	if err != nil {
+		return nil, fmt.Errorf("getting data key: %w", err)
+	}
+
+	tokens := key.BlindIndex(m.Content + " " + m.Summary)
+	rows := make([]searchTokenRow, len(tokens))
+	for i, token := range tokens {
+		rows[i] = searchTokenRow{MemoryID: m.ID.String(), Token: token}
+	}
+	return rows, nil
+}
+
+// writeSearchTokens replaces the blind index rows of a memory
+func (r *postgresRepository) writeSearchTokens(tx *gorm.DB, memoryID string, rows []searchTokenRow) error {
+	if r.keys == nil {
+		return nil
+	}
+	if err := tx.Where("memory_id = ?", memoryID).Delete(&searchTokenRow{}).Error; err != nil {
+		return fmt.Errorf("deleting search tokens: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		return fmt.Errorf("storing search tokens: %w", err)
+	}
+	return nil
+}
+
+// contentCondition builds the WHERE condition of a content search over the
+// memories of owners. Plaintext memories match text as a substring;
+// encrypted ones match when they contain all of its words.
+func (r *postgresRepository) contentCondition(ctx context.Context, text string, owners []string) (string, []interface{}, error) {
+	like := fmt.Sprintf("%%%s%%", text)
+	if r.keys == nil {
+		return "content LIKE ?", []interface{}{like}, nil
+	}
+
+	words := len(pkgencryption.Words(text))
+	var tokens []string
+	for _, owner := range owners {
+		key, err := r.keys.Latest(ctx, owner)
+		if errors.Is(err, pkgencryption.ErrUnknownKey) {
+			continue // nothing of theirs is encrypted
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("getting data key: %w", err)
+		}
+		tokens = append(tokens, key.BlindIndex(text)...)
+	}
+
+	plaintext := "(key_version IS NULL AND content LIKE ?)"
+	if words == 0 || len(tokens) == 0 {
+		return plaintext, []interface{}{like}, nil
+	}
+	return "(" + plaintext + ` OR id IN (
+		SELECT memory_id FROM memory_search_tokens WHERE token IN ?
+		GROUP BY memory_id HAVING COUNT(*) = ?))`, []interface{}{like, tokens, words}, nil
+}
+
+// ReencryptMemories re-encrypts up to limit memories of a user that are in
+// plaintext or under an older key version, returning how many it did
+func (r *postgresRepository) ReencryptMemories(ctx context.Context, userID user.ID, limit int) (int, error) {
+	key, err := r.keys.Current(ctx, userID.String())
+	if err != nil {
+		return 0, fmt.Errorf("getting data key: %w", err)
+	}
+
+	var gormMemories []*model.Memory
+	err = database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where("user_id = ? AND (key_version IS NULL OR key_version <> ?)", userID.String(), key.Version).
+			Order("id").
+			Limit(limit).
+			Find(&gormMemories).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("finding memories to re-encrypt: %w", err)
+	}
+
+	for i, gormMemory := range gormMemories {
+		m, err := r.toDomain(ctx, gormMemory)
+		if err != nil {
+			return i, fmt.Errorf("decrypting memory %s: %w", gormMemory.ID, err)
+		}
+		updated, err := r.toModel(ctx, m)
+		if err != nil {
+			return i, fmt.Errorf("encrypting memory %s: %w", gormMemory.ID, err)
+		}
+		tokens, err := r.searchTokens(ctx, m)
+		if err != nil {
+			return i, err
+		}
+
+		err = database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+			err := tx.Model(&model.Memory{}).
+				Where("id = ?", updated.ID).
+				Updates(map[string]interface{}{
+					"content":     updated.Content,
+					"summary":     updated.Summary,
+					"metadata":    updated.Metadata,
+					"key_version": updated.KeyVersion,
+				}).Error
+			if err != nil {
+				return fmt.Errorf("storing re-encrypted memory %s: %w", updated.ID, err)
+			}
+			return r.writeSearchTokens(tx, updated.ID, tokens)
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(gormMemories), nil
+}
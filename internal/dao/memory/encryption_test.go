@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/configs"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+	pkgencryption "mem_bank/pkg/encryption"
+	"mem_bank/pkg/logger"
+)
+
+// In-memory data key store; one version per user is enough here
+type memoryKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]*pkgencryption.StoredKey
+}
+
+func (s *memoryKeyStore) List(ctx context.Context, userID string) ([]*pkgencryption.StoredKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[userID]; ok {
+		return []*pkgencryption.StoredKey{key}, nil
+	}
+	return nil, nil
+}
+
+func (s *memoryKeyStore) Add(ctx context.Context, key *pkgencryption.StoredKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.UserID] = key
+	return nil
+}
+
+func (s *memoryKeyStore) ListNotWrappedBy(ctx context.Context, masterKeyID string, limit int) ([]*pkgencryption.StoredKey, error) {
+	return nil, nil
+}
+
+func (s *memoryKeyStore) Rewrap(ctx context.Context, key *pkgencryption.StoredKey) error {
+	return nil
+}
+
+func (s *memoryKeyStore) Delete(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, userID)
+	return nil
+}
+
+func newEncryptedTestRepository(t *testing.T) *postgresRepository {
+	t.Helper()
+	log, err := logger.NewLogger(&configs.LoggingConfig{Level: "info", Output: "stdout"})
+	require.NoError(t, err)
+	kms, err := pkgencryption.NewLocalKMS(filepath.Join(t.TempDir(), "master.json"))
+	require.NoError(t, err)
+
+	store := &memoryKeyStore{keys: make(map[string]*pkgencryption.StoredKey)}
+	return &postgresRepository{keys: pkgencryption.NewKeyRing(kms, store, log)}
+}
+
+func TestEncryptedConversion(t *testing.T) {
+	repo := newEncryptedTestRepository(t)
+	ctx := context.Background()
+
+	testMemory := &memory.Memory{
+		ID:         memory.ID(uuid.New()),
+		UserID:     user.ID(uuid.New()),
+		Content:    "Allergic to penicillin",
+		Summary:    "Allergy",
+		Embedding:  []float32{0.1, 0.2},
+		Importance: 9,
+		MemoryType: "medical",
+		Metadata:   map[string]interface{}{"source": "doctor"},
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	gormMemory, err := repo.toModel(ctx, testMemory)
+	require.NoError(t, err)
+	require.NotNil(t, gormMemory.KeyVersion)
+	assert.Equal(t, int32(1), *gormMemory.KeyVersion)
+	assert.NotContains(t, gormMemory.Content, "penicillin")
+	assert.NotContains(t, *gormMemory.Summary, "Allergy")
+	assert.NotContains(t, *gormMemory.Metadata, "doctor")
+	assert.Len(t, gormMemory.Embedding.Slice(), 2, "embeddings stay in the clear")
+
+	domainMemory, err := repo.toDomain(ctx, gormMemory)
+	require.NoError(t, err)
+	assert.Equal(t, testMemory.Content, domainMemory.Content)
+	assert.Equal(t, testMemory.Summary, domainMemory.Summary)
+	assert.Equal(t, "doctor", domainMemory.Metadata["source"])
+
+	t.Run("ciphertext is bound to its memory", func(t *testing.T) {
+		moved, err := repo.toModel(ctx, testMemory)
+		require.NoError(t, err)
+		moved.ID = uuid.New().String()
+		_, err = repo.toDomain(ctx, moved)
+		assert.ErrorIs(t, err, pkgencryption.ErrDecrypt)
+	})
+
+	t.Run("plaintext rows still read", func(t *testing.T) {
+		plain, err := (&postgresRepository{}).toModel(ctx, testMemory)
+		require.NoError(t, err)
+		domainMemory, err := repo.toDomain(ctx, plain)
+		require.NoError(t, err)
+		assert.Equal(t, testMemory.Content, domainMemory.Content)
+	})
+
+	t.Run("encrypted rows need keys", func(t *testing.T) {
+		encrypted, err := repo.toModel(ctx, testMemory)
+		require.NoError(t, err)
+		_, err = (&postgresRepository{}).toDomain(ctx, encrypted)
+		assert.Error(t, err)
+	})
+}
+
+func TestContentCondition(t *testing.T) {
+	repo := newEncryptedTestRepository(t)
+	ctx := context.Background()
+	owner := uuid.New().String()
+
+	condition, args, err := (&postgresRepository{}).contentCondition(ctx, "blood", []string{owner})
+	require.NoError(t, err)
+	assert.Equal(t, "content LIKE ?", condition)
+	assert.Equal(t, []interface{}{"%blood%"}, args)
+
+	// No key yet: nothing of the owner's can be encrypted
+	condition, _, err = repo.contentCondition(ctx, "blood test", []string{owner})
+	require.NoError(t, err)
+	assert.NotContains(t, condition, "memory_search_tokens")
+
+	key, err := repo.keys.Current(ctx, owner)
+	require.NoError(t, err)
+	condition, args, err = repo.contentCondition(ctx, "Blood test", []string{owner})
+	require.NoError(t, err)
+	assert.Contains(t, condition, "memory_search_tokens")
+	require.Len(t, args, 3)
+	assert.Equal(t, key.BlindIndex("blood TEST"), args[1])
+	assert.Equal(t, 2, args[2], "every word must match")
+}
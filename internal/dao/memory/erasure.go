@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mem_bank/internal/domain/erasure"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/model"
+	"mem_bank/pkg/database"
+	pkgencryption "mem_bank/pkg/encryption"
+)
+
+// NewErasureLister creates an erasure.MemoryLister over the memories in db;
+// keys is nil when memories are not encrypted
+func NewErasureLister(db *gorm.DB, keys *pkgencryption.KeyRing) erasure.MemoryLister {
+	return &postgresRepository{db: db, keys: keys}
+}
+
+// ListMemoryContents returns memories a user wrote, in personal and shared
+// spaces alike. A memory whose key was already destroyed, by an earlier
+// attempt at the same erasure, is returned without content.
+func (r *postgresRepository) ListMemoryContents(ctx context.Context, userID user.ID, limit int, cursor memory.ID) ([]erasure.MemoryContent, error) {
+	var gormMemories []*model.Memory
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		query := tx.Select("id", "user_id", "content", "key_version").Where("user_id = ?", userID.String())
+		if !cursor.IsZero() {
+			query = query.Where("id > ?", cursor.String())
+		}
+		return query.Order("id").Limit(limit).Find(&gormMemories).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing memories: %w", err)
+	}
+
+	contents := make([]erasure.MemoryContent, 0, len(gormMemories))
+	for _, gormMemory := range gormMemories {
+		id, err := uuid.Parse(gormMemory.ID)
+		if err != nil {
+			return nil, fmt.Errorf("parsing memory ID: %w", err)
+		}
+		if err := r.decrypt(ctx, gormMemory); err != nil {
+			if !errors.Is(err, pkgencryption.ErrUnknownKey) {
+				return nil, fmt.Errorf("decrypting memory %s: %w", gormMemory.ID, err)
+			}
+			gormMemory.Content = ""
+		}
+		contents = append(contents, erasure.MemoryContent{ID: memory.ID(id), Content: gormMemory.Content})
+	}
+	return contents, nil
+}
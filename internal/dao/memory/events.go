@@ -0,0 +1,273 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/model"
+	"mem_bank/pkg/database"
+)
+
+// eventRow mirrors a row of the memory_events outbox
+type eventRow struct {
+	Sequence     int64      `gorm:"column:sequence;primaryKey;autoIncrement"`
+	ID           string     `gorm:"column:id"`
+	Type         string     `gorm:"column:type"`
+	UserID       string     `gorm:"column:user_id"`
+	SpaceID      *string    `gorm:"column:space_id"`
+	MemoryID     string     `gorm:"column:memory_id"`
+	Data         string     `gorm:"column:data;type:jsonb"`
+	CreatedAt    time.Time  `gorm:"column:created_at"`
+	DispatchedAt *time.Time `gorm:"column:dispatched_at"`
+	PublishedAt  *time.Time `gorm:"column:published_at"`
+}
+
+func (eventRow) TableName() string { return "memory_events" }
+
+// writeEvents adds events to the outbox in tx, so they are committed
+// together with the change they describe
+func writeEvents(tx *gorm.DB, events ...*event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]*eventRow, 0, len(events))
+	for _, e := range events {
+		data, err := json.Marshal(e.Data)
+		if err != nil {
+			return fmt.Errorf("marshaling event data: %w", err)
+		}
+		row := &eventRow{
+			ID:        e.ID.String(),
+			Type:      e.Type,
+			UserID:    e.UserID.String(),
+			MemoryID:  e.MemoryID.String(),
+			Data:      string(data),
+			CreatedAt: e.CreatedAt,
+		}
+		if !e.SpaceID.IsZero() {
+			row.SpaceID = stringPtr(e.SpaceID.String())
+		}
+		rows = append(rows, row)
+	}
+
+	if err := tx.CreateInBatches(rows, 500).Error; err != nil {
+		return fmt.Errorf("writing memory events: %w", err)
+	}
+	for i, row := range rows {
+		events[i].Sequence = row.Sequence
+	}
+	return nil
+}
+
+// updateEvents returns the events of storing m over its current row in tx.
+// It must run before the update, to compare the stored embedding.
+func updateEvents(tx *gorm.DB, m *memory.Memory) ([]*event.Event, error) {
+	events := []*event.Event{event.NewMemoryEvent(event.TypeMemoryUpdated, m)}
+	if len(m.Embedding) == 0 {
+		return events, nil
+	}
+
+	var embedded bool
+	err := tx.Raw("SELECT embedding IS DISTINCT FROM ? FROM memories WHERE id = ?",
+		pgvector.NewVector(m.Embedding), m.ID.String()).Scan(&embedded).Error
+	if err != nil {
+		return nil, fmt.Errorf("comparing embeddings: %w", err)
+	}
+	if embedded {
+		events = append(events, event.NewMemoryEvent(event.TypeMemoryEmbedded, m))
+	}
+	return events, nil
+}
+
+// createEvents returns the events of creating m
+func createEvents(m *memory.Memory) []*event.Event {
+	events := []*event.Event{event.NewMemoryEvent(event.TypeMemoryCreated, m)}
+	if len(m.Embedding) > 0 {
+		events = append(events, event.NewMemoryEvent(event.TypeMemoryEmbedded, m))
+	}
+	return events
+}
+
+// eventMemory returns the fields of a stored row that events describe;
+// the possibly encrypted content, summary and metadata are left out
+func (r *postgresRepository) eventMemory(ctx context.Context, gormMemory *model.Memory) (*memory.Memory, error) {
+	row := *gormMemory
+	row.Content, row.Summary, row.Metadata, row.KeyVersion = "", nil, nil, nil
+	return r.toDomain(ctx, &row)
+}
+
+// eventRepository implements event.Repository over the outbox. The feed is
+// read in the caller's tenant; dispatch, publishing and retention bypass it.
+type eventRepository struct {
+	db *gorm.DB
+}
+
+// NewEventRepository creates an event.Repository over the memory_events
+// outbox written by the memory repositories in db
+func NewEventRepository(db *gorm.DB) event.Repository {
+	return &eventRepository{db: db}
+}
+
+func (r *eventRepository) List(ctx context.Context, filter event.Filter) ([]*event.Event, error) {
+	var rows []eventRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		q := tx.Where("sequence > ?", filter.Since).
+			Where("(space_id IS NULL AND user_id = ?) OR space_id IN (SELECT space_id FROM space_members WHERE user_id = ?)",
+				filter.UserID.String(), filter.UserID.String())
+		if len(filter.Types) > 0 {
+			q = q.Where("type IN ?", filter.Types)
+		}
+		return q.Order("sequence").Limit(filter.Limit).Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing memory events: %w", err)
+	}
+	return toEvents(rows)
+}
+
+func (r *eventRepository) FindByID(ctx context.Context, id event.ID) (*event.Event, error) {
+	var row eventRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Where("id = ?", id.String()).First(&row).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, event.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding memory event: %w", err)
+	}
+	return toEvent(&row)
+}
+
+func (r *eventRepository) DispatchPending(ctx context.Context, limit int, fn func(e *event.Event) error) (int, error) {
+	return r.processPending(ctx, "dispatched_at", limit, fn)
+}
+
+func (r *eventRepository) PublishPending(ctx context.Context, limit int, fn func(e *event.Event) error) (int, error) {
+	return r.processPending(ctx, "published_at", limit, fn)
+}
+
+// processPending passes the events whose marker column is unset to fn and
+// sets it on those fn accepted
+func (r *eventRepository) processPending(ctx context.Context, marker string, limit int, fn func(e *event.Event) error) (int, error) {
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+
+	processed := 0
+	var fnErr error
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		var rows []eventRow
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where(marker + " IS NULL").
+			Order("sequence").
+			Limit(limit).
+			Find(&rows).Error
+		if err != nil {
+			return fmt.Errorf("finding pending memory events: %w", err)
+		}
+
+		var sequences []int64
+		for i := range rows {
+			e, err := toEvent(&rows[i])
+			if err != nil {
+				return err
+			}
+			if fnErr = fn(e); fnErr != nil {
+				break
+			}
+			sequences = append(sequences, rows[i].Sequence)
+		}
+
+		if len(sequences) > 0 {
+			err := tx.Model(&eventRow{}).
+				Where("sequence IN ?", sequences).
+				Update(marker, time.Now()).Error
+			if err != nil {
+				return fmt.Errorf("marking memory events processed: %w", err)
+			}
+		}
+		processed = len(sequences)
+		// Commit the events fn accepted before reporting its error
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return processed, fnErr
+}
+
+func (r *eventRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+
+	var deleted int64
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		result := tx.Where("created_at < ?", t).Delete(&eventRow{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("deleting memory events: %w", err)
+	}
+	return deleted, nil
+}
+
+func toEvents(rows []eventRow) ([]*event.Event, error) {
+	events := make([]*event.Event, 0, len(rows))
+	for i := range rows {
+		e, err := toEvent(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func toEvent(row *eventRow) (*event.Event, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing event ID: %w", err)
+	}
+	userID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+	memoryID, err := uuid.Parse(row.MemoryID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing memory ID: %w", err)
+	}
+
+	e := &event.Event{
+		ID:        event.ID(id),
+		Sequence:  row.Sequence,
+		Type:      row.Type,
+		UserID:    user.ID(userID),
+		MemoryID:  memory.ID(memoryID),
+		CreatedAt: row.CreatedAt,
+	}
+	if row.SpaceID != nil {
+		spaceID, err := uuid.Parse(*row.SpaceID)
+		if err != nil {
+			return nil, fmt.Errorf("parsing space ID: %w", err)
+		}
+		e.SpaceID = space.ID(spaceID)
+	}
+	if row.Data != "" {
+		if err := json.Unmarshal([]byte(row.Data), &e.Data); err != nil {
+			return nil, fmt.Errorf("unmarshaling event data: %w", err)
+		}
+	}
+	return e, nil
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/memory"
+)
+
+func eventTypes(events []*event.Event, id memory.ID) []string {
+	var types []string
+	for _, e := range events {
+		if e.MemoryID == id {
+			types = append(types, e.Type)
+		}
+	}
+	return types
+}
+
+func TestEventRepository_Integration(t *testing.T) {
+	db := rlsTestDB(t)
+	repo := NewPostgresRepository(db)
+	events := NewEventRepository(db)
+
+	alice, bob := createTestUser(t, db), createTestUser(t, db)
+	aliceCtx, bobCtx := tenantContext(alice), tenantContext(bob)
+
+	m := newTestMemory(alice, "Likes tea")
+	require.NoError(t, repo.Store(aliceCtx, m))
+	m.Content = "Likes green tea"
+	require.NoError(t, repo.Update(aliceCtx, m))
+	m.UpdateEmbedding(make([]float32, 1536), "text-embedding-ada-002")
+	require.NoError(t, repo.Update(aliceCtx, m))
+	require.NoError(t, repo.Delete(aliceCtx, m.ID))
+
+	feed, err := events.List(aliceCtx, event.Filter{UserID: alice, Limit: 100})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		event.TypeMemoryCreated,
+		event.TypeMemoryUpdated,
+		event.TypeMemoryUpdated,
+		event.TypeMemoryEmbedded,
+		event.TypeMemoryDeleted,
+	}, eventTypes(feed, m.ID))
+	for i := 1; i < len(feed); i++ {
+		assert.Greater(t, feed[i].Sequence, feed[i-1].Sequence)
+	}
+	assert.NotContains(t, feed[0].Data, "content", "events leave the content out")
+
+	since := feed[len(feed)-2].Sequence
+	tail, err := events.List(aliceCtx, event.Filter{UserID: alice, Since: since, Limit: 100})
+	require.NoError(t, err)
+	assert.Equal(t, []string{event.TypeMemoryDeleted}, eventTypes(tail, m.ID))
+
+	// Neither the filter nor the policies let another user read the feed
+	feed, err = events.List(bobCtx, event.Filter{UserID: alice, Limit: 100})
+	require.NoError(t, err)
+	assert.Empty(t, eventTypes(feed, m.ID))
+
+	t.Run("dispatch marks accepted events", func(t *testing.T) {
+		var seen []string
+		errStop := errors.New("stop")
+		_, err := events.DispatchPending(context.Background(), 1000, func(e *event.Event) error {
+			if e.MemoryID == m.ID && e.Type == event.TypeMemoryDeleted {
+				return errStop
+			}
+			if e.MemoryID == m.ID {
+				seen = append(seen, e.Type)
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Len(t, seen, 4)
+
+		seen = nil
+		_, err = events.DispatchPending(context.Background(), 1000, func(e *event.Event) error {
+			if e.MemoryID == m.ID {
+				seen = append(seen, e.Type)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{event.TypeMemoryDeleted}, seen)
+	})
+
+	t.Run("publishing is tracked apart from dispatch", func(t *testing.T) {
+		var seen []string
+		_, err := events.PublishPending(context.Background(), 1000, func(e *event.Event) error {
+			if e.MemoryID == m.ID {
+				seen = append(seen, e.Type)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Len(t, seen, 5)
+	})
+}
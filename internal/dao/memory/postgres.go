@@ -3,41 +3,69 @@ package memory
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/pgvector/pgvector-go"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"mem_bank/internal/domain/event"
 	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/space"
 	"mem_bank/internal/domain/user"
 	"mem_bank/internal/model"
 	"mem_bank/internal/query"
+	"mem_bank/pkg/database"
+	pkgencryption "mem_bank/pkg/encryption"
 )
 
-// postgresRepository implements memory.Repository using PostgreSQL
+// postgresRepository implements memory.Repository using PostgreSQL. Every
+// statement runs in a transaction scoped to the tenant in the context, so
+// the row-level security policies on memories apply even to a query that
+// forgets its user_id filter.
 type postgresRepository struct {
-	db *gorm.DB
-	q  *query.Query
+	db   *gorm.DB
+	keys *pkgencryption.KeyRing // nil leaves memories in plaintext
 }
 
 // NewPostgresRepository creates a new PostgreSQL-based memory repository
 func NewPostgresRepository(db *gorm.DB) memory.Repository {
-	return &postgresRepository{
-		db: db,
-		q:  query.Use(db),
-	}
+	return &postgresRepository{db: db}
+}
+
+// scoped runs fn in a transaction scoped to the tenant in ctx
+func (r *postgresRepository) scoped(ctx context.Context, fn func(tx *gorm.DB, q *query.Query) error) error {
+	return database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return fn(tx, query.Use(tx))
+	})
 }
 
 func (r *postgresRepository) Store(ctx context.Context, m *memory.Memory) error {
-	gormMemory, err := r.toModel(m)
+	gormMemory, err := r.toModel(ctx, m)
 	if err != nil {
 		return fmt.Errorf("converting to model: %w", err)
 	}
 
-	if err := r.q.Memory.WithContext(ctx).Create(gormMemory); err != nil {
+	tokens, err := r.searchTokens(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	err = r.scoped(ctx, func(tx *gorm.DB, q *query.Query) error {
+		if err := q.Memory.WithContext(ctx).Create(gormMemory); err != nil {
+			return err
+		}
+		if err := r.writeSearchTokens(tx, gormMemory.ID, tokens); err != nil {
+			return err
+		}
+		return writeEvents(tx, createEvents(m)...)
+	})
+	if err != nil {
 		return fmt.Errorf("creating memory: %w", err)
 	}
 
@@ -45,31 +73,41 @@ func (r *postgresRepository) Store(ctx context.Context, m *memory.Memory) error
 }
 
 func (r *postgresRepository) FindByID(ctx context.Context, id memory.ID) (*memory.Memory, error) {
-	gormMemory, err := r.q.Memory.WithContext(ctx).Where(r.q.Memory.ID.Eq(id.String())).First()
+	var gormMemory *model.Memory
+	err := r.scoped(ctx, func(tx *gorm.DB, q *query.Query) error {
+		var err error
+		gormMemory, err = q.Memory.WithContext(ctx).Where(q.Memory.ID.Eq(id.String())).First()
+		return err
+	})
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, memory.ErrNotFound
 		}
 		return nil, fmt.Errorf("finding memory: %w", err)
 	}
 
-	return r.toDomain(gormMemory)
+	return r.toDomain(ctx, gormMemory)
 }
 
 func (r *postgresRepository) FindByUserID(ctx context.Context, userID user.ID, limit, offset int) ([]*memory.Memory, error) {
-	gormMemories, err := r.q.Memory.WithContext(ctx).
-		Where(r.q.Memory.UserID.Eq(userID.String())).
-		Order(r.q.Memory.CreatedAt.Desc()).
-		Limit(limit).
-		Offset(offset).
-		Find()
+	var gormMemories []*model.Memory
+	err := r.scoped(ctx, func(tx *gorm.DB, q *query.Query) error {
+		var err error
+		gormMemories, err = q.Memory.WithContext(ctx).
+			Where(q.Memory.UserID.Eq(userID.String()), q.Memory.SpaceID.IsNull()).
+			Order(q.Memory.CreatedAt.Desc()).
+			Limit(limit).
+			Offset(offset).
+			Find()
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("finding memories by user ID: %w", err)
 	}
 
 	memories := make([]*memory.Memory, 0, len(gormMemories))
 	for _, gormMemory := range gormMemories {
-		m, err := r.toDomain(gormMemory)
+		m, err := r.toDomain(ctx, gormMemory)
 		if err != nil {
 			return nil, fmt.Errorf("converting memory: %w", err)
 		}
@@ -80,17 +118,37 @@ func (r *postgresRepository) FindByUserID(ctx context.Context, userID user.ID, l
 }
 
 func (r *postgresRepository) Update(ctx context.Context, m *memory.Memory) error {
-	gormMemory, err := r.toModel(m)
+	gormMemory, err := r.toModel(ctx, m)
 	if err != nil {
 		return fmt.Errorf("converting to model: %w", err)
 	}
 
-	result, err := r.q.Memory.WithContext(ctx).Where(r.q.Memory.ID.Eq(m.ID.String())).Updates(gormMemory)
+	tokens, err := r.searchTokens(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	var rowsAffected int64
+	err = r.scoped(ctx, func(tx *gorm.DB, q *query.Query) error {
+		events, err := updateEvents(tx, m)
+		if err != nil {
+			return err
+		}
+		result, err := q.Memory.WithContext(ctx).Where(q.Memory.ID.Eq(m.ID.String())).Updates(gormMemory)
+		rowsAffected = result.RowsAffected
+		if err != nil || rowsAffected == 0 {
+			return err
+		}
+		if err := r.writeSearchTokens(tx, gormMemory.ID, tokens); err != nil {
+			return err
+		}
+		return writeEvents(tx, events...)
+	})
 	if err != nil {
 		return fmt.Errorf("updating memory: %w", err)
 	}
 
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		return memory.ErrNotFound
 	}
 
@@ -98,18 +156,41 @@ func (r *postgresRepository) Update(ctx context.Context, m *memory.Memory) error
 }
 
 func (r *postgresRepository) Delete(ctx context.Context, id memory.ID) error {
-	result, err := r.q.Memory.WithContext(ctx).Where(r.q.Memory.ID.Eq(id.String())).Delete()
+	var rowsAffected int64
+	err := r.scoped(ctx, func(tx *gorm.DB, q *query.Query) error {
+		var deleted []*model.Memory
+		result := tx.Clauses(clause.Returning{}).Where("id = ?", id.String()).Delete(&deleted)
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		return r.writeDeleteEvents(ctx, tx, deleted)
+	})
 	if err != nil {
 		return fmt.Errorf("deleting memory: %w", err)
 	}
 
-	if result.RowsAffected == 0 {
+	if rowsAffected == 0 {
 		return memory.ErrNotFound
 	}
 
 	return nil
 }
 
+// writeDeleteEvents adds the events of deleting the rows returned by a
+// delete to the outbox in tx
+func (r *postgresRepository) writeDeleteEvents(ctx context.Context, tx *gorm.DB, deleted []*model.Memory) error {
+	events := make([]*event.Event, 0, len(deleted))
+	for _, gormMemory := range deleted {
+		m, err := r.eventMemory(ctx, gormMemory)
+		if err != nil {
+			return err
+		}
+		events = append(events, event.NewMemoryEvent(event.TypeMemoryDeleted, m))
+	}
+	return writeEvents(tx, events...)
+}
+
 func (r *postgresRepository) SearchSimilar(ctx context.Context, embedding []float32, userID user.ID, limit int, threshold float64) ([]*memory.Memory, error) {
 	if len(embedding) == 0 {
 		return []*memory.Memory{}, nil
@@ -123,20 +204,21 @@ func (r *postgresRepository) SearchSimilar(ctx context.Context, embedding []floa
 	// and filter by threshold (similarity >= threshold means 1 - cosine_distance >= threshold)
 	var gormMemories []*model.Memory
 
-	query := r.db.WithContext(ctx).
-		Where("user_id = ? AND embedding IS NOT NULL", userID.String()).
-		Where("1 - (embedding <=> ?) >= ?", vec, threshold).
-		Order(gorm.Expr("embedding <=> ?", vec)). // Order by cosine distance (ascending = most similar first)
-		Limit(limit)
-
-	err := query.Find(&gormMemories).Error
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where("user_id = ? AND space_id IS NULL AND embedding IS NOT NULL", userID.String()).
+			Where("1 - (embedding <=> ?) >= ?", vec, threshold).
+			Order(gorm.Expr("embedding <=> ?", vec)). // Order by cosine distance (ascending = most similar first)
+			Limit(limit).
+			Find(&gormMemories).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("searching similar memories: %w", err)
 	}
 
 	memories := make([]*memory.Memory, 0, len(gormMemories))
 	for _, gormMemory := range gormMemories {
-		m, err := r.toDomain(gormMemory)
+		m, err := r.toDomain(ctx, gormMemory)
 		if err != nil {
 			return nil, fmt.Errorf("converting memory: %w", err)
 		}
@@ -160,21 +242,22 @@ func (r *postgresRepository) SearchSimilarWithScores(ctx context.Context, embedd
 		Score  float64
 	}
 
-	query := r.db.WithContext(ctx).
-		Select("*, 1 - (embedding <=> ?) AS score", vec).
-		Where("user_id = ? AND embedding IS NOT NULL", userID.String()).
-		Where("1 - (embedding <=> ?) >= ?", vec, threshold).
-		Order("score DESC"). // Order by similarity score descending (most similar first)
-		Limit(limit)
-
-	err := query.Find(&results).Error
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Select("*, 1 - (embedding <=> ?) AS score", vec).
+			Where("user_id = ? AND space_id IS NULL AND embedding IS NOT NULL", userID.String()).
+			Where("1 - (embedding <=> ?) >= ?", vec, threshold).
+			Order("score DESC"). // Order by similarity score descending (most similar first)
+			Limit(limit).
+			Find(&results).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("searching similar memories with scores: %w", err)
 	}
 
 	memoriesWithScores := make([]*memory.MemoryWithScore, 0, len(results))
 	for _, result := range results {
-		m, err := r.toDomain(&result.Memory)
+		m, err := r.toDomain(ctx, &result.Memory)
 		if err != nil {
 			return nil, fmt.Errorf("converting memory: %w", err)
 		}
@@ -202,20 +285,21 @@ func (r *postgresRepository) SearchSimilarByMemory(ctx context.Context, memoryID
 	vec := pgvector.NewVector(sourceMemory.Embedding)
 	var gormMemories []*model.Memory
 
-	query := r.db.WithContext(ctx).
-		Where("user_id = ? AND embedding IS NOT NULL AND id != ?", userID.String(), memoryID.String()).
-		Where("1 - (embedding <=> ?) >= ?", vec, threshold).
-		Order(gorm.Expr("embedding <=> ?", vec)). // Order by cosine distance (ascending = most similar first)
-		Limit(limit)
-
-	err = query.Find(&gormMemories).Error
+	err = database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where("user_id = ? AND space_id IS NULL AND embedding IS NOT NULL AND id != ?", userID.String(), memoryID.String()).
+			Where("1 - (embedding <=> ?) >= ?", vec, threshold).
+			Order(gorm.Expr("embedding <=> ?", vec)). // Order by cosine distance (ascending = most similar first)
+			Limit(limit).
+			Find(&gormMemories).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("searching similar memories by memory: %w", err)
 	}
 
 	memories := make([]*memory.Memory, 0, len(gormMemories))
 	for _, gormMemory := range gormMemories {
-		m, err := r.toDomain(gormMemory)
+		m, err := r.toDomain(ctx, gormMemory)
 		if err != nil {
 			return nil, fmt.Errorf("converting memory: %w", err)
 		}
@@ -225,22 +309,29 @@ func (r *postgresRepository) SearchSimilarByMemory(ctx context.Context, memoryID
 	return memories, nil
 }
 
-func (r *postgresRepository) SearchByContent(ctx context.Context, query string, userID user.ID, limit, offset int) ([]*memory.Memory, error) {
-	searchTerm := fmt.Sprintf("%%%s%%", query)
-	gormMemories, err := r.q.Memory.WithContext(ctx).
-		Where(r.q.Memory.UserID.Eq(userID.String())).
-		Where(r.q.Memory.Content.Like(searchTerm)).
-		Order(r.q.Memory.CreatedAt.Desc()).
-		Limit(limit).
-		Offset(offset).
-		Find()
+func (r *postgresRepository) SearchByContent(ctx context.Context, text string, userID user.ID, limit, offset int) ([]*memory.Memory, error) {
+	condition, args, err := r.contentCondition(ctx, text, []string{userID.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	var gormMemories []*model.Memory
+	err = database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where("user_id = ? AND space_id IS NULL", userID.String()).
+			Where(condition, args...).
+			Order("created_at DESC").
+			Limit(limit).
+			Offset(offset).
+			Find(&gormMemories).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("searching memories by content: %w", err)
 	}
 
 	memories := make([]*memory.Memory, 0, len(gormMemories))
 	for _, gormMemory := range gormMemories {
-		m, err := r.toDomain(gormMemory)
+		m, err := r.toDomain(ctx, gormMemory)
 		if err != nil {
 			return nil, fmt.Errorf("converting memory: %w", err)
 		}
@@ -258,19 +349,21 @@ func (r *postgresRepository) FindByTags(ctx context.Context, tags []string, user
 	// Use PostgreSQL array overlap operator (&&) to find memories with any of the specified tags
 	// This is much more efficient than LIKE queries and works properly with PostgreSQL arrays
 	var gormMemories []*model.Memory
-	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND tags && ?", userID.String(), pq.Array(tags)).
-		Order("created_at DESC").
-		Limit(limit).
-		Offset(offset).
-		Find(&gormMemories).Error
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where("user_id = ? AND space_id IS NULL AND tags && ?", userID.String(), pq.Array(tags)).
+			Order("created_at DESC").
+			Limit(limit).
+			Offset(offset).
+			Find(&gormMemories).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("finding memories by tags: %w", err)
 	}
 
 	memories := make([]*memory.Memory, 0, len(gormMemories))
 	for _, gormMemory := range gormMemories {
-		m, err := r.toDomain(gormMemory)
+		m, err := r.toDomain(ctx, gormMemory)
 		if err != nil {
 			return nil, fmt.Errorf("converting memory: %w", err)
 		}
@@ -280,51 +373,215 @@ func (r *postgresRepository) FindByTags(ctx context.Context, tags []string, user
 	return memories, nil
 }
 
-func (r *postgresRepository) UpdateAccessInfo(ctx context.Context, id memory.ID) error {
-	now := time.Now()
-	result, err := r.q.Memory.WithContext(ctx).
-		Where(r.q.Memory.ID.Eq(id.String())).
-		Updates(map[string]interface{}{
-			"last_accessed": &now,
-			"access_count":  gorm.Expr("access_count + 1"),
+func (r *postgresRepository) FindInScope(ctx context.Context, scope memory.Scope, limit, offset int) ([]*memory.Memory, error) {
+	condition, args := scopeCondition(scope)
+
+	var gormMemories []*model.Memory
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where(condition, args...).
+			Order("created_at DESC").
+			Limit(limit).
+			Offset(offset).
+			Find(&gormMemories).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding memories in scope: %w", err)
+	}
+
+	return r.toDomainList(ctx, gormMemories)
+}
+
+func (r *postgresRepository) SearchByContentInScope(ctx context.Context, text string, scope memory.Scope, limit, offset int) ([]*memory.Memory, error) {
+	condition, args := scopeCondition(scope)
+
+	// Members of a shared space encrypt with their own keys, so the blind
+	// index needs the tokens of everyone who wrote in scope
+	var owners []string
+	if r.keys != nil {
+		err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+			return tx.Model(&model.Memory{}).
+				Where(condition, args...).
+				Where("key_version IS NOT NULL").
+				Distinct().
+				Pluck("user_id", &owners).Error
 		})
+		if err != nil {
+			return nil, fmt.Errorf("finding memory owners in scope: %w", err)
+		}
+	}
+
+	contentCond, contentArgs, err := r.contentCondition(ctx, text, owners)
 	if err != nil {
-		return fmt.Errorf("updating access info: %w", err)
+		return nil, err
 	}
 
-	if result.RowsAffected == 0 {
-		return memory.ErrNotFound
+	var gormMemories []*model.Memory
+	err = database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where(condition, args...).
+			Where(contentCond, contentArgs...).
+			Order("created_at DESC").
+			Limit(limit).
+			Offset(offset).
+			Find(&gormMemories).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching memories in scope by content: %w", err)
 	}
 
-	return nil
+	return r.toDomainList(ctx, gormMemories)
 }
 
-func (r *postgresRepository) GetStatsByUserID(ctx context.Context, userID user.ID) (*memory.Stats, error) {
-	// Get total count
-	totalCount, err := r.q.Memory.WithContext(ctx).Where(r.q.Memory.UserID.Eq(userID.String())).Count()
+func (r *postgresRepository) FindByTagsInScope(ctx context.Context, tags []string, scope memory.Scope, limit, offset int) ([]*memory.Memory, error) {
+	if len(tags) == 0 {
+		return []*memory.Memory{}, nil
+	}
+
+	condition, args := scopeCondition(scope)
+
+	var gormMemories []*model.Memory
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where(condition, args...).
+			Where("tags && ?", pq.Array(tags)).
+			Order("created_at DESC").
+			Limit(limit).
+			Offset(offset).
+			Find(&gormMemories).Error
+	})
 	if err != nil {
-		return nil, fmt.Errorf("counting memories: %w", err)
+		return nil, fmt.Errorf("finding memories in scope by tags: %w", err)
+	}
+
+	return r.toDomainList(ctx, gormMemories)
+}
+
+func (r *postgresRepository) SearchSimilarInScope(ctx context.Context, embedding []float32, scope memory.Scope, limit int, threshold float64) ([]*memory.Memory, error) {
+	if len(embedding) == 0 {
+		return []*memory.Memory{}, nil
 	}
 
-	// Get recent count (last 7 days)
-	weekAgo := time.Now().AddDate(0, 0, -7)
-	recentCount, err := r.q.Memory.WithContext(ctx).
-		Where(r.q.Memory.UserID.Eq(userID.String())).
-		Where(r.q.Memory.CreatedAt.Gte(weekAgo)).
-		Count()
+	condition, args := scopeCondition(scope)
+	vec := pgvector.NewVector(embedding)
+
+	var gormMemories []*model.Memory
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where(condition, args...).
+			Where("embedding IS NOT NULL").
+			Where("1 - (embedding <=> ?) >= ?", vec, threshold).
+			Order(gorm.Expr("embedding <=> ?", vec)).
+			Limit(limit).
+			Find(&gormMemories).Error
+	})
 	if err != nil {
-		return nil, fmt.Errorf("counting recent memories: %w", err)
+		return nil, fmt.Errorf("searching similar memories in scope: %w", err)
+	}
+
+	return r.toDomainList(ctx, gormMemories)
+}
+
+// scopeCondition builds the WHERE condition selecting the memories of a
+// scope. Shared spaces are matched through space_members, so a user only
+// ever sees spaces they belong to, whatever SpaceIDs asks for.
+func scopeCondition(scope memory.Scope) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if scope.Personal {
+		clauses = append(clauses, "(space_id IS NULL AND user_id = ?)")
+		args = append(args, scope.UserID.String())
+	}
+
+	if scope.Shared {
+		shared := "space_id IS NOT NULL"
+		if !scope.Unrestricted {
+			shared = "space_id IN (SELECT space_id FROM space_members WHERE user_id = ?)"
+			args = append(args, scope.UserID.String())
+		}
+		if len(scope.SpaceIDs) > 0 {
+			shared = "(" + shared + " AND space_id IN ?)"
+			args = append(args, spaceIDStrings(scope.SpaceIDs))
+		}
+		clauses = append(clauses, shared)
 	}
 
-	// Get average importance
+	if len(clauses) == 0 {
+		return "FALSE", nil
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+func spaceIDStrings(ids []space.ID) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, id.String())
+	}
+	return out
+}
+
+func (r *postgresRepository) UpdateAccessInfo(ctx context.Context, id memory.ID) error {
+	now := time.Now()
+	var rowsAffected int64
+	err := r.scoped(ctx, func(tx *gorm.DB, q *query.Query) error {
+		result, err := q.Memory.WithContext(ctx).
+			Where(q.Memory.ID.Eq(id.String())).
+			Updates(map[string]interface{}{
+				"last_accessed": &now,
+				"access_count":  gorm.Expr("access_count + 1"),
+			})
+		rowsAffected = result.RowsAffected
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("updating access info: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return memory.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) GetStatsByUserID(ctx context.Context, userID user.ID) (*memory.Stats, error) {
+	var totalCount, recentCount int64
 	var avgImportance float64
-	err = r.db.WithContext(ctx).
-		Model(&model.Memory{}).
-		Where("user_id = ?", userID.String()).
-		Select("AVG(COALESCE(importance, 5))").
-		Scan(&avgImportance).Error
+
+	err := r.scoped(ctx, func(tx *gorm.DB, q *query.Query) error {
+		// Get total count
+		var err error
+		totalCount, err = q.Memory.WithContext(ctx).
+			Where(q.Memory.UserID.Eq(userID.String()), q.Memory.SpaceID.IsNull()).
+			Count()
+		if err != nil {
+			return fmt.Errorf("counting memories: %w", err)
+		}
+
+		// Get recent count (last 7 days)
+		weekAgo := time.Now().AddDate(0, 0, -7)
+		recentCount, err = q.Memory.WithContext(ctx).
+			Where(q.Memory.UserID.Eq(userID.String()), q.Memory.SpaceID.IsNull()).
+			Where(q.Memory.CreatedAt.Gte(weekAgo)).
+			Count()
+		if err != nil {
+			return fmt.Errorf("counting recent memories: %w", err)
+		}
+
+		// Get average importance
+		err = tx.
+			Model(&model.Memory{}).
+			Where("user_id = ? AND space_id IS NULL", userID.String()).
+			Select("AVG(COALESCE(importance, 5))").
+			Scan(&avgImportance).Error
+		if err != nil {
+			return fmt.Errorf("calculating average importance: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("calculating average importance: %w", err)
+		return nil, err
 	}
 
 	// For now, return basic stats without memory type breakdown
@@ -338,7 +595,12 @@ func (r *postgresRepository) GetStatsByUserID(ctx context.Context, userID user.I
 }
 
 func (r *postgresRepository) CountByUserID(ctx context.Context, userID user.ID) (int, error) {
-	count, err := r.q.Memory.WithContext(ctx).Where(r.q.Memory.UserID.Eq(userID.String())).Count()
+	var count int64
+	err := r.scoped(ctx, func(tx *gorm.DB, q *query.Query) error {
+		var err error
+		count, err = q.Memory.WithContext(ctx).Where(q.Memory.UserID.Eq(userID.String())).Count()
+		return err
+	})
 	if err != nil {
 		return 0, fmt.Errorf("counting user memories: %w", err)
 	}
@@ -346,6 +608,66 @@ func (r *postgresRepository) CountByUserID(ctx context.Context, userID user.ID)
 	return int(count), nil
 }
 
+func (r *postgresRepository) FindWithoutEmbedding(ctx context.Context, userID user.ID, embeddingModel string, limit int, cursor memory.ID) ([]*memory.Memory, error) {
+	var gormMemories []*model.Memory
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		// Both branches are served by partial indexes on (user_id, id), see migration 002:
+		// idx_memories_missing_embedding covers the NULL side and idx_memories_embedded
+		// the outdated-model side, which Postgres combines with a BitmapOr
+		query := tx.Where("user_id = ?", userID.String())
+		if embeddingModel == "" {
+			query = query.Where("embedding IS NULL")
+		} else {
+			query = query.Where("(embedding IS NULL OR embedding_model IS DISTINCT FROM ?)", embeddingModel)
+		}
+		if !cursor.IsZero() {
+			query = query.Where("id > ?", cursor.String())
+		}
+		return query.Order("id").Limit(limit).Find(&gormMemories).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding memories without embedding: %w", err)
+	}
+
+	memories := make([]*memory.Memory, 0, len(gormMemories))
+	for _, gormMemory := range gormMemories {
+		m, err := r.toDomain(ctx, gormMemory)
+		if err != nil {
+			return nil, fmt.Errorf("converting memory: %w", err)
+		}
+		memories = append(memories, m)
+	}
+
+	return memories, nil
+}
+
+func (r *postgresRepository) GetEmbeddingCoverage(ctx context.Context, userID user.ID, embeddingModel string) (*memory.EmbeddingCoverage, error) {
+	var row struct {
+		Total         int64
+		WithEmbedding int64
+		WithModel     int64
+	}
+
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Model(&model.Memory{}).
+			Select(`COUNT(*) AS total,
+				COUNT(embedding) AS with_embedding,
+				COUNT(*) FILTER (WHERE embedding IS NOT NULL AND embedding_model = ?) AS with_model`, embeddingModel).
+			Where("user_id = ?", userID.String()).
+			Scan(&row).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calculating embedding coverage: %w", err)
+	}
+
+	return &memory.EmbeddingCoverage{
+		TotalMemories: int(row.Total),
+		WithEmbedding: int(row.WithEmbedding),
+		WithModel:     int(row.WithModel),
+	}, nil
+}
+
 // Helper functions
 func intPtr(i int32) *int32 {
 	return &i
@@ -355,7 +677,20 @@ func stringPtr(s string) *string {
 	return &s
 }
 
-func (r *postgresRepository) toModel(m *memory.Memory) (*model.Memory, error) {
+func (r *postgresRepository) toDomainList(ctx context.Context, gormMemories []*model.Memory) ([]*memory.Memory, error) {
+	memories := make([]*memory.Memory, 0, len(gormMemories))
+	for _, gormMemory := range gormMemories {
+		m, err := r.toDomain(ctx, gormMemory)
+		if err != nil {
+			return nil, fmt.Errorf("converting memory: %w", err)
+		}
+		memories = append(memories, m)
+	}
+
+	return memories, nil
+}
+
+func (r *postgresRepository) toModel(ctx context.Context, m *memory.Memory) (*model.Memory, error) {
 	metadata, err := json.Marshal(m.Metadata)
 	if err != nil {
 		return nil, fmt.Errorf("marshaling metadata: %w", err)
@@ -379,15 +714,33 @@ func (r *postgresRepository) toModel(m *memory.Memory) (*model.Memory, error) {
 		AccessCount:  intPtr(int32(m.AccessCount)),
 	}
 
+	if m.IsShared() {
+		gormMemory.SpaceID = stringPtr(m.SpaceID.String())
+	}
+
 	// Convert embedding to pgvector format if present
 	if len(m.Embedding) > 0 {
 		gormMemory.Embedding = pgvector.NewVector(m.Embedding)
 	}
 
+	if m.EmbeddingModel != "" {
+		gormMemory.EmbeddingModel = stringPtr(m.EmbeddingModel)
+	}
+
+	if r.keys != nil {
+		if err := r.encrypt(ctx, gormMemory); err != nil {
+			return nil, err
+		}
+	}
+
 	return gormMemory, nil
 }
 
-func (r *postgresRepository) toDomain(gormMemory *model.Memory) (*memory.Memory, error) {
+func (r *postgresRepository) toDomain(ctx context.Context, gormMemory *model.Memory) (*memory.Memory, error) {
+	if err := r.decrypt(ctx, gormMemory); err != nil {
+		return nil, fmt.Errorf("decrypting memory %s: %w", gormMemory.ID, err)
+	}
+
 	id, err := uuid.Parse(gormMemory.ID)
 	if err != nil {
 		return nil, fmt.Errorf("parsing memory ID: %w", err)
@@ -407,6 +760,14 @@ func (r *postgresRepository) toDomain(gormMemory *model.Memory) (*memory.Memory,
 		AccessCount: 0,
 	}
 
+	if gormMemory.SpaceID != nil {
+		spaceID, err := uuid.Parse(*gormMemory.SpaceID)
+		if err != nil {
+			return nil, fmt.Errorf("parsing space ID: %w", err)
+		}
+		m.SpaceID = space.ID(spaceID)
+	}
+
 	if gormMemory.Summary != nil {
 		m.Summary = *gormMemory.Summary
 	}
@@ -448,6 +809,10 @@ func (r *postgresRepository) toDomain(gormMemory *model.Memory) (*memory.Memory,
 		m.AccessCount = int(*gormMemory.AccessCount)
 	}
 
+	if gormMemory.EmbeddingModel != nil {
+		m.EmbeddingModel = *gormMemory.EmbeddingModel
+	}
+
 	// Convert pgvector.Vector to []float32 if present
 	vectorSlice := gormMemory.Embedding.Slice()
 	if len(vectorSlice) > 0 {
@@ -467,15 +832,22 @@ func (r *postgresRepository) BatchStore(ctx context.Context, memories []*memory.
 	}
 
 	models := make([]*model.Memory, 0, len(memories))
+	var tokens []searchTokenRow
 	for _, m := range memories {
-		model, err := r.toModel(m)
+		model, err := r.toModel(ctx, m)
 		if err != nil {
 			return fmt.Errorf("converting memory to model: %w", err)
 		}
 		models = append(models, model)
+
+		memoryTokens, err := r.searchTokens(ctx, m)
+		if err != nil {
+			return err
+		}
+		tokens = append(tokens, memoryTokens...)
 	}
 
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
 		batchSize := 100 // Process in batches to avoid memory issues
 		for i := 0; i < len(models); i += batchSize {
 			end := i + batchSize
@@ -487,7 +859,17 @@ func (r *postgresRepository) BatchStore(ctx context.Context, memories []*memory.
 				return fmt.Errorf("batch creating memories (batch %d): %w", i/batchSize+1, err)
 			}
 		}
-		return nil
+		if len(tokens) > 0 {
+			if err := tx.CreateInBatches(tokens, 1000).Error; err != nil {
+				return fmt.Errorf("storing search tokens: %w", err)
+			}
+		}
+
+		var events []*event.Event
+		for _, m := range memories {
+			events = append(events, createEvents(m)...)
+		}
+		return writeEvents(tx, events...)
 	})
 }
 
@@ -497,13 +879,18 @@ func (r *postgresRepository) BatchUpdate(ctx context.Context, memories []*memory
 		return nil
 	}
 
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
 		for _, m := range memories {
-			model, err := r.toModel(m)
+			model, err := r.toModel(ctx, m)
 			if err != nil {
 				return fmt.Errorf("converting memory to model: %w", err)
 			}
 
+			events, err := updateEvents(tx, m)
+			if err != nil {
+				return err
+			}
+
 			result := tx.Where("id = ?", m.ID.String()).Updates(model)
 			if result.Error != nil {
 				return fmt.Errorf("updating memory %s: %w", m.ID.String(), result.Error)
@@ -511,6 +898,17 @@ func (r *postgresRepository) BatchUpdate(ctx context.Context, memories []*memory
 			if result.RowsAffected == 0 {
 				return fmt.Errorf("memory %s not found for update", m.ID.String())
 			}
+
+			tokens, err := r.searchTokens(ctx, m)
+			if err != nil {
+				return err
+			}
+			if err := r.writeSearchTokens(tx, m.ID.String(), tokens); err != nil {
+				return err
+			}
+			if err := writeEvents(tx, events...); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -527,16 +925,19 @@ func (r *postgresRepository) BatchDelete(ctx context.Context, ids []memory.ID) e
 		stringIDs = append(stringIDs, id.String())
 	}
 
-	result := r.db.WithContext(ctx).Where("id IN ?", stringIDs).Delete(&model.Memory{})
-	if result.Error != nil {
-		return fmt.Errorf("batch deleting memories: %w", result.Error)
-	}
+	return database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		var deleted []*model.Memory
+		result := tx.Clauses(clause.Returning{}).Where("id IN ?", stringIDs).Delete(&deleted)
+		if result.Error != nil {
+			return fmt.Errorf("batch deleting memories: %w", result.Error)
+		}
 
-	if int(result.RowsAffected) != len(ids) {
-		return fmt.Errorf("expected to delete %d memories, but deleted %d", len(ids), result.RowsAffected)
-	}
+		if int(result.RowsAffected) != len(ids) {
+			return fmt.Errorf("expected to delete %d memories, but deleted %d", len(ids), result.RowsAffected)
+		}
 
-	return nil
+		return r.writeDeleteEvents(ctx, tx, deleted)
+	})
 }
 
 // BatchUpdateEmbeddings updates embeddings for multiple memories efficiently
@@ -545,7 +946,7 @@ func (r *postgresRepository) BatchUpdateEmbeddings(ctx context.Context, updates
 		return nil
 	}
 
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
 		for _, update := range updates {
 			// Convert embedding to pgvector format
 			var embedding pgvector.Vector
@@ -553,11 +954,14 @@ func (r *postgresRepository) BatchUpdateEmbeddings(ctx context.Context, updates
 				embedding = pgvector.NewVector(update.Embedding)
 			}
 
-			result := tx.Model(&model.Memory{}).
+			var updated model.Memory
+			result := tx.Model(&updated).
+				Clauses(clause.Returning{}).
 				Where("id = ?", update.ID.String()).
 				Updates(map[string]interface{}{
-					"embedding":  embedding,
-					"updated_at": time.Now(),
+					"embedding":       embedding,
+					"embedding_model": update.Model,
+					"updated_at":      time.Now(),
 				})
 
 			if result.Error != nil {
@@ -566,6 +970,14 @@ func (r *postgresRepository) BatchUpdateEmbeddings(ctx context.Context, updates
 			if result.RowsAffected == 0 {
 				return fmt.Errorf("memory %s not found for embedding update", update.ID.String())
 			}
+
+			m, err := r.eventMemory(ctx, &updated)
+			if err != nil {
+				return err
+			}
+			if err := writeEvents(tx, event.NewMemoryEvent(event.TypeMemoryEmbedded, m)); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -90,7 +90,7 @@ func TestMemoryConversion(t *testing.T) {
 		repo := &postgresRepository{}
 
 		// Convert to model
-		gormMemory, err := repo.toModel(testMemory)
+		gormMemory, err := repo.toModel(context.Background(), testMemory)
 		assert.NoError(t, err, "toModel should not return error")
 		assert.NotNil(t, gormMemory, "gormMemory should not be nil")
 		assert.Equal(t, testMemory.ID.String(), gormMemory.ID)
@@ -98,7 +98,7 @@ func TestMemoryConversion(t *testing.T) {
 		assert.Equal(t, testMemory.Content, gormMemory.Content)
 
 		// Convert back to domain
-		domainMemory, err := repo.toDomain(gormMemory)
+		domainMemory, err := repo.toDomain(context.Background(), gormMemory)
 		assert.NoError(t, err, "toDomain should not return error")
 		assert.NotNil(t, domainMemory, "domainMemory should not be nil")
 		assert.Equal(t, testMemory.ID, domainMemory.ID)
@@ -131,7 +131,7 @@ func TestMemoryConversion(t *testing.T) {
 
 		repo := &postgresRepository{}
 
-		gormMemory, err := repo.toModel(testMemory)
+		gormMemory, err := repo.toModel(context.Background(), testMemory)
 		assert.NoError(t, err)
 		assert.NotNil(t, gormMemory)
 		// Embedding should be nil when empty
@@ -103,7 +103,7 @@ func TestPostgresRepository_SearchSimilar_Integration(t *testing.T) {
 	}
 
 	// Store test memories
-	ctx := context.Background()
+	ctx := database.WithTenant(context.Background(), database.Tenant{Bypass: true})
 	for _, mem := range memories {
 		err := repo.Store(ctx, mem)
 		require.NoError(t, err)
@@ -186,7 +186,7 @@ func TestPostgresRepository_VectorOperations_Integration(t *testing.T) {
 	repo := NewPostgresRepository(db)
 
 	testUserID := user.ID(uuid.New())
-	ctx := context.Background()
+	ctx := database.WithTenant(context.Background(), database.Tenant{Bypass: true})
 
 	t.Run("store_and_retrieve_with_embedding", func(t *testing.T) {
 		embedding := []float32{0.1, 0.2, 0.3, 0.4, 0.5}
@@ -247,7 +247,7 @@ func TestPostgresRepository_VectorOperations_Integration(t *testing.T) {
 		defer repo.Delete(ctx, mem.ID)
 
 		// Update embedding
-		mem.UpdateEmbedding(updatedEmbedding)
+		mem.UpdateEmbedding(updatedEmbedding, "test-model")
 		err = repo.Update(ctx, mem)
 		require.NoError(t, err)
 
@@ -326,7 +326,7 @@ func BenchmarkPostgresRepository_SearchSimilar(b *testing.B) {
 
 	repo := NewPostgresRepository(db.DB)
 	testUserID := user.ID(uuid.New())
-	ctx := context.Background()
+	ctx := database.WithTenant(context.Background(), database.Tenant{Bypass: true})
 
 	// Create test data
 	numMemories := 100
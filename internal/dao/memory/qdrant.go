@@ -187,11 +187,13 @@ func (r *QdrantRepository) SearchSimilar(ctx context.Context, embedding []float3
 		}
 	}
 
-	// Retrieve full memory objects from PostgreSQL
+	// Retrieve full memory objects from PostgreSQL. Points are filtered by
+	// author only, so memories the user wrote into shared spaces are dropped
+	// here to keep this a personal search.
 	memories := make([]*memory.Memory, 0, len(memoryIDs))
 	for _, id := range memoryIDs {
 		mem, err := r.postgresRepo.FindByID(ctx, id)
-		if err == nil { // Skip errors for individual memories
+		if err == nil && !mem.IsShared() { // Skip errors for individual memories
 			memories = append(memories, mem)
 		}
 	}
@@ -209,6 +211,27 @@ func (r *QdrantRepository) FindByTags(ctx context.Context, tags []string, userID
 	return r.postgresRepo.FindByTags(ctx, tags, userID, limit, offset)
 }
 
+// FindInScope retrieves memories within a scope using PostgreSQL
+func (r *QdrantRepository) FindInScope(ctx context.Context, scope memory.Scope, limit, offset int) ([]*memory.Memory, error) {
+	return r.postgresRepo.FindInScope(ctx, scope, limit, offset)
+}
+
+// SearchByContentInScope searches memories within a scope using PostgreSQL
+func (r *QdrantRepository) SearchByContentInScope(ctx context.Context, query string, scope memory.Scope, limit, offset int) ([]*memory.Memory, error) {
+	return r.postgresRepo.SearchByContentInScope(ctx, query, scope, limit, offset)
+}
+
+// FindByTagsInScope retrieves memories within a scope by tags using PostgreSQL
+func (r *QdrantRepository) FindByTagsInScope(ctx context.Context, tags []string, scope memory.Scope, limit, offset int) ([]*memory.Memory, error) {
+	return r.postgresRepo.FindByTagsInScope(ctx, tags, scope, limit, offset)
+}
+
+// SearchSimilarInScope finds similar memories within a scope using pgvector,
+// where space membership is checked in the same query as the search
+func (r *QdrantRepository) SearchSimilarInScope(ctx context.Context, embedding []float32, scope memory.Scope, limit int, threshold float64) ([]*memory.Memory, error) {
+	return r.postgresRepo.SearchSimilarInScope(ctx, embedding, scope, limit, threshold)
+}
+
 // UpdateAccessInfo updates the access information using PostgreSQL
 func (r *QdrantRepository) UpdateAccessInfo(ctx context.Context, id memory.ID) error {
 	return r.postgresRepo.UpdateAccessInfo(ctx, id)
@@ -224,6 +247,16 @@ func (r *QdrantRepository) CountByUserID(ctx context.Context, userID user.ID) (i
 	return r.postgresRepo.CountByUserID(ctx, userID)
 }
 
+// FindWithoutEmbedding retrieves memories missing an embedding from PostgreSQL
+func (r *QdrantRepository) FindWithoutEmbedding(ctx context.Context, userID user.ID, model string, limit int, cursor memory.ID) ([]*memory.Memory, error) {
+	return r.postgresRepo.FindWithoutEmbedding(ctx, userID, model, limit, cursor)
+}
+
+// GetEmbeddingCoverage returns embedding coverage counts from PostgreSQL
+func (r *QdrantRepository) GetEmbeddingCoverage(ctx context.Context, userID user.ID, model string) (*memory.EmbeddingCoverage, error) {
+	return r.postgresRepo.GetEmbeddingCoverage(ctx, userID, model)
+}
+
 // Close closes the Qdrant connection
 func (r *QdrantRepository) Close() error {
 	if r.client != nil {
@@ -285,11 +318,11 @@ func (r *QdrantRepository) upsertVector(ctx context.Context, mem *memory.Memory)
 		return fmt.Errorf("memory has no embedding")
 	}
 
-	// Create payload
+	// Create payload. Content stays in PostgreSQL, where it may be
+	// encrypted; search results are loaded from there anyway.
 	payload := qdrant.NewValueMap(map[string]any{
 		"memory_id":   mem.ID.String(),
 		"user_id":     mem.UserID.String(),
-		"content":     mem.Content,
 		"importance":  int64(mem.Importance),
 		"memory_type": mem.MemoryType,
 		"created_at":  mem.CreatedAt.Format(time.RFC3339),
@@ -331,6 +364,36 @@ func (r *QdrantRepository) deleteVector(ctx context.Context, id memory.ID) error
 	return nil
 }
 
+// DeleteUserVectors removes every vector of memories a user wrote,
+// returning how many there were
+func (r *QdrantRepository) DeleteUserVectors(ctx context.Context, userID user.ID) (int64, error) {
+	filter := &qdrant.Filter{
+		Must: []*qdrant.Condition{
+			qdrant.NewMatch("user_id", userID.String()),
+		},
+	}
+
+	count, err := r.client.Count(ctx, &qdrant.CountPoints{
+		CollectionName: r.collectionName,
+		Filter:         filter,
+		Exact:          qdrant.PtrOf(true),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("counting user vectors: %w", err)
+	}
+
+	_, err = r.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: r.collectionName,
+		Wait:           qdrant.PtrOf(true),
+		Points:         qdrant.NewPointsSelectorFilter(filter),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("deleting user vectors: %w", err)
+	}
+
+	return int64(count), nil
+}
+
 // SearchSimilarWithScores finds similar memories with similarity scores
 func (r *QdrantRepository) SearchSimilarWithScores(ctx context.Context, embedding []float32, userID user.ID, limit int, threshold float64) ([]*memory.MemoryWithScore, error) {
 	// First delegate to PostgreSQL implementation for now
@@ -361,7 +424,6 @@ func (r *QdrantRepository) BatchStore(ctx context.Context, memories []*memory.Me
 			payload := qdrant.NewValueMap(map[string]any{
 				"memory_id":   mem.ID.String(),
 				"user_id":     mem.UserID.String(),
-				"content":     mem.Content,
 				"importance":  int64(mem.Importance),
 				"memory_type": mem.MemoryType,
 				"created_at":  mem.CreatedAt.Format(time.RFC3339),
@@ -409,7 +471,6 @@ func (r *QdrantRepository) BatchUpdate(ctx context.Context, memories []*memory.M
 			payload := qdrant.NewValueMap(map[string]any{
 				"memory_id":   mem.ID.String(),
 				"user_id":     mem.UserID.String(),
-				"content":     mem.Content,
 				"importance":  int64(mem.Importance),
 				"memory_type": mem.MemoryType,
 				"created_at":  mem.CreatedAt.Format(time.RFC3339),
@@ -493,7 +554,6 @@ func (r *QdrantRepository) BatchUpdateEmbeddings(ctx context.Context, updates []
 			payload := qdrant.NewValueMap(map[string]any{
 				"memory_id":   mem.ID.String(),
 				"user_id":     mem.UserID.String(),
-				"content":     mem.Content,
 				"importance":  int64(mem.Importance),
 				"memory_type": mem.MemoryType,
 				"created_at":  mem.CreatedAt.Format(time.RFC3339),
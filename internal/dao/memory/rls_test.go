@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+)
+
+// rlsTestDB returns a connection the row-level security policies apply to.
+// Superusers and BYPASSRLS roles ignore them, so for those the connection
+// pool is cut to one connection that switches to a plain role.
+func rlsTestDB(t *testing.T) *gorm.DB {
+	db := setupTestDB(t)
+
+	var exempt bool
+	err := db.Raw("SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&exempt).Error
+	require.NoError(t, err)
+	if !exempt {
+		return db
+	}
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, stmt := range []string{
+		`DO $$ BEGIN
+			IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'mem_bank_rls_test') THEN
+				CREATE ROLE mem_bank_rls_test NOLOGIN;
+			END IF;
+		END $$`,
+		"GRANT SELECT, INSERT, UPDATE, DELETE ON users, memories, spaces, space_members, memory_events TO mem_bank_rls_test",
+		"GRANT USAGE ON SEQUENCE memory_events_sequence_seq TO mem_bank_rls_test",
+		"SET ROLE mem_bank_rls_test",
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	t.Cleanup(func() { db.Exec("RESET ROLE") })
+	return db
+}
+
+func tenantContext(id user.ID) context.Context {
+	return database.WithTenant(context.Background(), database.Tenant{UserID: id.String()})
+}
+
+func createTestUser(t *testing.T, db *gorm.DB) user.ID {
+	t.Helper()
+	id := user.ID(uuid.New())
+	name := fmt.Sprintf("rls_%s", id.String()[:8])
+	err := db.Exec("INSERT INTO users (id, username, email) VALUES (?, ?, ?)",
+		id.String(), name, name+"@example.com").Error
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		// Memories and memberships go with the user through ON DELETE CASCADE
+		db.Exec("DELETE FROM users WHERE id = ?", id.String())
+	})
+	return id
+}
+
+func newTestMemory(userID user.ID, content string) *memory.Memory {
+	now := time.Now()
+	return &memory.Memory{
+		ID:         memory.NewID(),
+		UserID:     userID,
+		Content:    content,
+		Importance: 5,
+		MemoryType: "general",
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// visibleIDs runs an unfiltered query, as a buggy caller might, and returns
+// the IDs of the given memories it can see
+func visibleIDs(t *testing.T, ctx context.Context, db *gorm.DB, ids ...memory.ID) []string {
+	t.Helper()
+	wanted := make([]string, 0, len(ids))
+	for _, id := range ids {
+		wanted = append(wanted, id.String())
+	}
+
+	var seen []string
+	err := database.Transaction(ctx, db, func(tx *gorm.DB) error {
+		return tx.Raw("SELECT id FROM memories WHERE id IN ?", wanted).Scan(&seen).Error
+	})
+	require.NoError(t, err)
+	return seen
+}
+
+func TestPostgresRepository_RowLevelSecurity_Integration(t *testing.T) {
+	db := rlsTestDB(t)
+	repo := NewPostgresRepository(db)
+
+	alice, bob := createTestUser(t, db), createTestUser(t, db)
+	aliceCtx, bobCtx := tenantContext(alice), tenantContext(bob)
+
+	aliceMemory := newTestMemory(alice, "Alice's secret")
+	require.NoError(t, repo.Store(aliceCtx, aliceMemory))
+	bobMemory := newTestMemory(bob, "Bob's note")
+	require.NoError(t, repo.Store(bobCtx, bobMemory))
+
+	t.Run("repository hides other users' memories", func(t *testing.T) {
+		_, err := repo.FindByID(bobCtx, aliceMemory.ID)
+		assert.ErrorIs(t, err, memory.ErrNotFound)
+
+		memories, err := repo.FindByUserID(bobCtx, alice, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, memories)
+
+		changed := *aliceMemory
+		changed.Content = "overwritten"
+		assert.ErrorIs(t, repo.Update(bobCtx, &changed), memory.ErrNotFound)
+		assert.ErrorIs(t, repo.Delete(bobCtx, aliceMemory.ID), memory.ErrNotFound)
+
+		found, err := repo.FindByID(aliceCtx, aliceMemory.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Alice's secret", found.Content)
+	})
+
+	t.Run("unfiltered query only sees the tenant's rows", func(t *testing.T) {
+		assert.Equal(t, []string{bobMemory.ID.String()}, visibleIDs(t, bobCtx, db, aliceMemory.ID, bobMemory.ID))
+		assert.Equal(t, []string{aliceMemory.ID.String()}, visibleIDs(t, aliceCtx, db, aliceMemory.ID, bobMemory.ID))
+	})
+
+	t.Run("no tenant sees nothing", func(t *testing.T) {
+		assert.Empty(t, visibleIDs(t, context.Background(), db, aliceMemory.ID, bobMemory.ID))
+
+		// Nor does a query outside a tenant transaction
+		var count int64
+		require.NoError(t, db.Table("memories").Where("user_id IN ?", []string{alice.String(), bob.String()}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("bypass sees every row", func(t *testing.T) {
+		ctx := database.WithTenant(context.Background(), database.Tenant{Bypass: true})
+		assert.ElementsMatch(t,
+			[]string{aliceMemory.ID.String(), bobMemory.ID.String()},
+			visibleIDs(t, ctx, db, aliceMemory.ID, bobMemory.ID))
+	})
+
+	t.Run("memories cannot be written for another user", func(t *testing.T) {
+		assert.Error(t, repo.Store(bobCtx, newTestMemory(alice, "planted")))
+	})
+}
+
+func TestPostgresRepository_RowLevelSecurity_Spaces_Integration(t *testing.T) {
+	db := rlsTestDB(t)
+	repo := NewPostgresRepository(db)
+
+	alice, bob := createTestUser(t, db), createTestUser(t, db)
+	aliceCtx, bobCtx := tenantContext(alice), tenantContext(bob)
+
+	spaceID := space.NewID()
+	err := database.Transaction(aliceCtx, db, func(tx *gorm.DB) error {
+		if err := tx.Exec("INSERT INTO spaces (id, name, created_by) VALUES (?, ?, ?)",
+			spaceID.String(), "Team", alice.String()).Error; err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO space_members (space_id, user_id, role) VALUES (?, ?, ?)",
+			spaceID.String(), alice.String(), space.RoleOwner).Error
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ctx := database.WithTenant(context.Background(), database.Tenant{Bypass: true})
+		database.Transaction(ctx, db, func(tx *gorm.DB) error {
+			return tx.Exec("DELETE FROM spaces WHERE id = ?", spaceID.String()).Error
+		})
+	})
+
+	shared := newTestMemory(alice, "Team plan")
+	shared.SpaceID = spaceID
+	require.NoError(t, repo.Store(aliceCtx, shared))
+
+	_, err = repo.FindByID(bobCtx, shared.ID)
+	assert.ErrorIs(t, err, memory.ErrNotFound, "non-members must not see the space's memories")
+
+	err = database.Transaction(aliceCtx, db, func(tx *gorm.DB) error {
+		return tx.Exec("INSERT INTO space_members (space_id, user_id, role) VALUES (?, ?, ?)",
+			spaceID.String(), bob.String(), space.RoleViewer).Error
+	})
+	require.NoError(t, err)
+
+	found, err := repo.FindByID(bobCtx, shared.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Team plan", found.Content)
+
+	t.Run("only owners change memberships", func(t *testing.T) {
+		carol := createTestUser(t, db)
+
+		err := database.Transaction(tenantContext(carol), db, func(tx *gorm.DB) error {
+			return tx.Exec("INSERT INTO space_members (space_id, user_id, role) VALUES (?, ?, ?)",
+				spaceID.String(), carol.String(), space.RoleOwner).Error
+		})
+		assert.Error(t, err, "non-members must not join on their own")
+
+		err = database.Transaction(bobCtx, db, func(tx *gorm.DB) error {
+			return tx.Exec("INSERT INTO space_members (space_id, user_id, role) VALUES (?, ?, ?)",
+				spaceID.String(), carol.String(), space.RoleEditor).Error
+		})
+		assert.Error(t, err, "viewers must not add members")
+
+		var promoted int64
+		err = database.Transaction(bobCtx, db, func(tx *gorm.DB) error {
+			result := tx.Exec("UPDATE space_members SET role = ? WHERE space_id = ? AND user_id = ?",
+				space.RoleOwner, spaceID.String(), bob.String())
+			promoted = result.RowsAffected
+			return result.Error
+		})
+		require.NoError(t, err)
+		assert.Zero(t, promoted, "viewers must not promote themselves")
+	})
+
+	t.Run("members may leave", func(t *testing.T) {
+		var left int64
+		err := database.Transaction(bobCtx, db, func(tx *gorm.DB) error {
+			result := tx.Exec("DELETE FROM space_members WHERE space_id = ? AND user_id = ?",
+				spaceID.String(), bob.String())
+			left = result.RowsAffected
+			return result.Error
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), left)
+	})
+}
@@ -0,0 +1,161 @@
+package pii
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/pii"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+	pkgencryption "mem_bank/pkg/encryption"
+)
+
+// tokenRow mirrors a row of the memory_pii_tokens table
+type tokenRow struct {
+	MemoryID   string    `gorm:"column:memory_id;primaryKey"`
+	Token      string    `gorm:"column:token;primaryKey"`
+	UserID     string    `gorm:"column:user_id"`
+	Kind       string    `gorm:"column:kind"`
+	Value      string    `gorm:"column:value"`
+	KeyVersion *int      `gorm:"column:key_version"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (tokenRow) TableName() string { return "memory_pii_tokens" }
+
+// postgresVault implements pii.Vault using PostgreSQL. Queries run in
+// transactions scoped to the tenant in the context, so row-level security
+// limits each user to the tokens of their own memories.
+type postgresVault struct {
+	db   *gorm.DB
+	keys *pkgencryption.KeyRing
+}
+
+// NewVault creates a PostgreSQL-based vault. When keys is not nil, values
+// are encrypted with the author's data key, as memory content is.
+func NewVault(db *gorm.DB, keys *pkgencryption.KeyRing) pii.Vault {
+	return &postgresVault{db: db, keys: keys}
+}
+
+func valueAAD(memoryID, token string) []byte {
+	return []byte(memoryID + "/pii/" + token)
+}
+
+func (v *postgresVault) Replace(ctx context.Context, memoryID memory.ID, tokens []pii.Token) error {
+	rows := make([]tokenRow, 0, len(tokens))
+	for _, t := range tokens {
+		row, err := v.toRow(ctx, memoryID, &t)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, *row)
+	}
+
+	err := database.Transaction(ctx, v.db, func(tx *gorm.DB) error {
+		if err := tx.Where("memory_id = ?", memoryID.String()).Delete(&tokenRow{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return fmt.Errorf("storing personal data tokens: %w", err)
+	}
+	return nil
+}
+
+func (v *postgresVault) FindByMemoryID(ctx context.Context, memoryID memory.ID) ([]pii.Token, error) {
+	var rows []tokenRow
+	err := database.Transaction(ctx, v.db, func(tx *gorm.DB) error {
+		return tx.Where("memory_id = ?", memoryID.String()).Order("created_at, token").Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding personal data tokens: %w", err)
+	}
+
+	tokens := make([]pii.Token, 0, len(rows))
+	for i := range rows {
+		t, err := v.toToken(ctx, &rows[i])
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *t)
+	}
+	return tokens, nil
+}
+
+func (v *postgresVault) toRow(ctx context.Context, memoryID memory.ID, t *pii.Token) (*tokenRow, error) {
+	row := &tokenRow{
+		MemoryID:  memoryID.String(),
+		Token:     t.Token,
+		UserID:    t.UserID.String(),
+		Kind:      t.Kind,
+		Value:     t.Value,
+		CreatedAt: t.CreatedAt,
+	}
+	if v.keys == nil {
+		return row, nil
+	}
+
+	key, err := v.keys.Current(ctx, row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("getting data key: %w", err)
+	}
+	ciphertext, err := key.Seal([]byte(t.Value), valueAAD(row.MemoryID, row.Token))
+	if err != nil {
+		return nil, fmt.Errorf("encrypting personal data token: %w", err)
+	}
+	row.Value = base64.StdEncoding.EncodeToString(ciphertext)
+	version := key.Version
+	row.KeyVersion = &version
+	return row, nil
+}
+
+func (v *postgresVault) toToken(ctx context.Context, row *tokenRow) (*pii.Token, error) {
+	memoryID, err := uuid.Parse(row.MemoryID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing memory ID: %w", err)
+	}
+	userID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+
+	value := row.Value
+	if row.KeyVersion != nil {
+		if v.keys == nil {
+			return nil, errors.New("personal data token is encrypted but encryption is not configured")
+		}
+		key, err := v.keys.Key(ctx, row.UserID, *row.KeyVersion)
+		if err != nil {
+			return nil, fmt.Errorf("getting data key version %d: %w", *row.KeyVersion, err)
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(row.Value)
+		if err != nil {
+			return nil, fmt.Errorf("decoding personal data token: %w", err)
+		}
+		plaintext, err := key.Open(ciphertext, valueAAD(row.MemoryID, row.Token))
+		if err != nil {
+			return nil, fmt.Errorf("decrypting personal data token: %w", err)
+		}
+		value = string(plaintext)
+	}
+
+	return &pii.Token{
+		MemoryID:  memory.ID(memoryID),
+		UserID:    user.ID(userID),
+		Token:     row.Token,
+		Kind:      row.Kind,
+		Value:     value,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mem_bank/internal/domain/quota"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+)
+
+// quotaRow mirrors a row of the user_quotas table
+type quotaRow struct {
+	UserID                  string `gorm:"column:user_id;primaryKey"`
+	Plan                    string `gorm:"column:plan"`
+	MaxStorageBytes         *int64 `gorm:"column:max_storage_bytes"`
+	MonthlyEmbeddingTokens  *int64 `gorm:"column:monthly_embedding_tokens"`
+	MonthlyCompletionTokens *int64 `gorm:"column:monthly_completion_tokens"`
+	UpdatedAt               time.Time
+}
+
+func (quotaRow) TableName() string { return "user_quotas" }
+
+// postgresRepository implements quota.Repository using PostgreSQL. Queries
+// run in transactions scoped to the tenant in the context, see
+// database.Transaction.
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based quota repository
+func NewPostgresRepository(db *gorm.DB) quota.Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) FindByUserID(ctx context.Context, userID user.ID) (*quota.Assignment, error) {
+	var row quotaRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userID.String()).First(&row).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, quota.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding quota assignment: %w", err)
+	}
+
+	return &quota.Assignment{
+		UserID: userID,
+		Plan:   row.Plan,
+		Overrides: quota.Overrides{
+			MaxStorageBytes:         row.MaxStorageBytes,
+			MonthlyEmbeddingTokens:  row.MonthlyEmbeddingTokens,
+			MonthlyCompletionTokens: row.MonthlyCompletionTokens,
+		},
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
+
+func (r *postgresRepository) Save(ctx context.Context, assignment *quota.Assignment) error {
+	assignment.UpdatedAt = time.Now()
+	row := &quotaRow{
+		UserID:                  assignment.UserID.String(),
+		Plan:                    assignment.Plan,
+		MaxStorageBytes:         assignment.Overrides.MaxStorageBytes,
+		MonthlyEmbeddingTokens:  assignment.Overrides.MonthlyEmbeddingTokens,
+		MonthlyCompletionTokens: assignment.Overrides.MonthlyCompletionTokens,
+		UpdatedAt:               assignment.UpdatedAt,
+	}
+
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			UpdateAll: true,
+		}).Create(row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("saving quota assignment: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) GetStorage(ctx context.Context, userID user.ID) (int, int64, error) {
+	var row struct {
+		Memories int
+		Bytes    int64
+	}
+
+	// Usage counts every memory the user wrote, including those in spaces
+	// they have since left, which row-level security would hide from them
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Table("memories").
+			Select("COUNT(*) AS memories, COALESCE(SUM(octet_length(content)), 0) AS bytes").
+			Where("user_id = ?", userID.String()).
+			Scan(&row).Error
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("calculating storage usage: %w", err)
+	}
+	return row.Memories, row.Bytes, nil
+}
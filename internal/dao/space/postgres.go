@@ -0,0 +1,302 @@
+package space
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+)
+
+// spaceRow mirrors a row of the spaces table
+type spaceRow struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	Name        string    `gorm:"column:name"`
+	Description string    `gorm:"column:description"`
+	CreatedBy   *string   `gorm:"column:created_by"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+func (spaceRow) TableName() string { return "spaces" }
+
+// memberRow mirrors a row of the space_members table
+type memberRow struct {
+	SpaceID   string    `gorm:"column:space_id;primaryKey"`
+	UserID    string    `gorm:"column:user_id;primaryKey"`
+	Role      string    `gorm:"column:role"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (memberRow) TableName() string { return "space_members" }
+
+// postgresRepository implements space.Repository using PostgreSQL. Queries
+// run in transactions scoped to the tenant in the context, see
+// database.Transaction.
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based space repository
+func NewPostgresRepository(db *gorm.DB) space.Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) Create(ctx context.Context, s *space.Space, owner *space.Member) error {
+	createdBy := s.CreatedBy.String()
+	row := &spaceRow{
+		ID:          s.ID.String(),
+		Name:        s.Name,
+		Description: s.Description,
+		CreatedBy:   &createdBy,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+
+	return database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		if err := tx.Create(row).Error; err != nil {
+			return fmt.Errorf("storing space: %w", err)
+		}
+		if err := tx.Create(toMemberRow(owner)).Error; err != nil {
+			return fmt.Errorf("storing space owner: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *postgresRepository) FindByID(ctx context.Context, id space.ID) (*space.Space, error) {
+	var row spaceRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Where("id = ?", id.String()).First(&row).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, space.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding space: %w", err)
+	}
+	return toSpace(&row)
+}
+
+func (r *postgresRepository) ListByUserID(ctx context.Context, userID user.ID) ([]*space.Membership, error) {
+	var rows []struct {
+		spaceRow
+		Role string `gorm:"column:role"`
+	}
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Table("spaces").
+			Select("spaces.*, space_members.role").
+			Joins("JOIN space_members ON space_members.space_id = spaces.id").
+			Where("space_members.user_id = ?", userID.String()).
+			Order("spaces.name").
+			Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing spaces: %w", err)
+	}
+
+	memberships := make([]*space.Membership, 0, len(rows))
+	for i := range rows {
+		s, err := toSpace(&rows[i].spaceRow)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, &space.Membership{Space: s, Role: rows[i].Role})
+	}
+	return memberships, nil
+}
+
+func (r *postgresRepository) Update(ctx context.Context, s *space.Space) error {
+	var result *gorm.DB
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		result = tx.Model(&spaceRow{}).
+			Where("id = ?", s.ID.String()).
+			Updates(map[string]interface{}{
+				"name":        s.Name,
+				"description": s.Description,
+			})
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("updating space: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return space.ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, id space.ID) error {
+	// Memberships and memories go with the space through ON DELETE CASCADE
+	var result *gorm.DB
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		result = tx.Where("id = ?", id.String()).Delete(&spaceRow{})
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("deleting space: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return space.ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresRepository) FindMember(ctx context.Context, spaceID space.ID, userID user.ID) (*space.Member, error) {
+	var row memberRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where("space_id = ? AND user_id = ?", spaceID.String(), userID.String()).
+			First(&row).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, space.ErrNotMember
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding space member: %w", err)
+	}
+	return toMember(&row)
+}
+
+func (r *postgresRepository) ListMembers(ctx context.Context, spaceID space.ID) ([]*space.Member, error) {
+	var rows []memberRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Where("space_id = ?", spaceID.String()).
+			Order("created_at").
+			Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing space members: %w", err)
+	}
+
+	members := make([]*space.Member, 0, len(rows))
+	for i := range rows {
+		member, err := toMember(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (r *postgresRepository) AddMember(ctx context.Context, member *space.Member) error {
+	var result *gorm.DB
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		result = tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(toMemberRow(member))
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("adding space member: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return space.ErrAlreadyMember
+	}
+	return nil
+}
+
+func (r *postgresRepository) UpdateMemberRole(ctx context.Context, spaceID space.ID, userID user.ID, role string) error {
+	var result *gorm.DB
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		result = tx.Model(&memberRow{}).
+			Where("space_id = ? AND user_id = ?", spaceID.String(), userID.String()).
+			Update("role", role)
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("updating space member: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return space.ErrNotMember
+	}
+	return nil
+}
+
+func (r *postgresRepository) RemoveMember(ctx context.Context, spaceID space.ID, userID user.ID) error {
+	var result *gorm.DB
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		result = tx.
+			Where("space_id = ? AND user_id = ?", spaceID.String(), userID.String()).
+			Delete(&memberRow{})
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("removing space member: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return space.ErrNotMember
+	}
+	return nil
+}
+
+func (r *postgresRepository) CountOwners(ctx context.Context, spaceID space.ID) (int, error) {
+	var count int64
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Model(&memberRow{}).
+			Where("space_id = ? AND role = ?", spaceID.String(), space.RoleOwner).
+			Count(&count).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("counting space owners: %w", err)
+	}
+	return int(count), nil
+}
+
+func toMemberRow(member *space.Member) *memberRow {
+	return &memberRow{
+		SpaceID:   member.SpaceID.String(),
+		UserID:    member.UserID.String(),
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	}
+}
+
+func toSpace(row *spaceRow) (*space.Space, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing space ID: %w", err)
+	}
+
+	s := &space.Space{
+		ID:          space.ID(id),
+		Name:        row.Name,
+		Description: row.Description,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+	if row.CreatedBy != nil {
+		if createdBy, err := uuid.Parse(*row.CreatedBy); err == nil {
+			s.CreatedBy = user.ID(createdBy)
+		}
+	}
+	return s, nil
+}
+
+func toMember(row *memberRow) (*space.Member, error) {
+	spaceID, err := uuid.Parse(row.SpaceID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing space ID: %w", err)
+	}
+	userID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+
+	return &space.Member{
+		SpaceID:   space.ID(spaceID),
+		UserID:    user.ID(userID),
+		Role:      row.Role,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"mem_bank/internal/domain/usage"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+)
+
+// usageRow mirrors a row of the llm_usage table
+type usageRow struct {
+	ID               string  `gorm:"column:id;primaryKey"`
+	UserID           *string `gorm:"column:user_id"`
+	Provider         string  `gorm:"column:provider"`
+	Model            string  `gorm:"column:model"`
+	Operation        string  `gorm:"column:operation"`
+	PromptTokens     int     `gorm:"column:prompt_tokens"`
+	CompletionTokens int     `gorm:"column:completion_tokens"`
+	TotalTokens      int     `gorm:"column:total_tokens"`
+	Estimated        bool    `gorm:"column:estimated"`
+	CostUSD          float64 `gorm:"column:cost_usd"`
+	CreatedAt        time.Time
+}
+
+func (usageRow) TableName() string { return "llm_usage" }
+
+// postgresRepository implements usage.Repository using PostgreSQL. Queries
+// run in transactions scoped to the tenant in the context, see
+// database.Transaction.
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based usage repository
+func NewPostgresRepository(db *gorm.DB) usage.Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) Store(ctx context.Context, record *usage.Record) error {
+	if record.ID == uuid.Nil {
+		record.ID = uuid.New()
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	row := &usageRow{
+		ID:               record.ID.String(),
+		Provider:         record.Provider,
+		Model:            record.Model,
+		Operation:        record.Operation,
+		PromptTokens:     record.PromptTokens,
+		CompletionTokens: record.CompletionTokens,
+		TotalTokens:      record.TotalTokens,
+		Estimated:        record.Estimated,
+		CostUSD:          record.CostUSD,
+		CreatedAt:        record.CreatedAt,
+	}
+	if record.UserID != nil {
+		userID := record.UserID.String()
+		row.UserID = &userID
+	}
+
+	// The ledger is written for whoever a call is attributed to, who need
+	// not be the tenant of the request that made it
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Create(row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("storing usage record: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) Summarize(ctx context.Context, userID user.ID, from, to time.Time) ([]usage.Summary, error) {
+	var rows []struct {
+		Model            string
+		Operation        string
+		Requests         int
+		PromptTokens     int
+		CompletionTokens int
+		TotalTokens      int
+		EstimatedTokens  int
+		CostUSD          float64
+	}
+
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.
+			Model(&usageRow{}).
+			Select(`model, operation,
+				COUNT(*) AS requests,
+				COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens,
+				COALESCE(SUM(completion_tokens), 0) AS completion_tokens,
+				COALESCE(SUM(total_tokens), 0) AS total_tokens,
+				COALESCE(SUM(total_tokens) FILTER (WHERE estimated), 0) AS estimated_tokens,
+				COALESCE(SUM(cost_usd), 0) AS cost_usd`).
+			Where("user_id = ? AND created_at >= ? AND created_at < ?", userID.String(), from, to).
+			Group("model, operation").
+			Order("model, operation").
+			Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("summarizing usage: %w", err)
+	}
+
+	summaries := make([]usage.Summary, len(rows))
+	for i, row := range rows {
+		summaries[i] = usage.Summary{
+			Model:            row.Model,
+			Operation:        row.Operation,
+			Requests:         row.Requests,
+			PromptTokens:     row.PromptTokens,
+			CompletionTokens: row.CompletionTokens,
+			TotalTokens:      row.TotalTokens,
+			EstimatedTokens:  row.EstimatedTokens,
+			CostUSD:          row.CostUSD,
+		}
+	}
+	return summaries, nil
+}
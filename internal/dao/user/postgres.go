@@ -237,6 +237,10 @@ func (r *postgresRepository) toModel(u *user.User) (*model.User, error) {
 		IsActive:  &u.IsActive,
 	}
 
+	if u.Role != "" {
+		gormUser.Role = &u.Role
+	}
+
 	if !u.LastLogin.IsZero() {
 		gormUser.LastLogin = &u.LastLogin
 	}
@@ -255,6 +259,7 @@ func (r *postgresRepository) toDomain(gormUser *model.User) (*user.User, error)
 		Username: gormUser.Username,
 		Email:    gormUser.Email,
 		IsActive: true,
+		Role:     user.RoleUser,
 	}
 
 	if gormUser.Profile != nil && *gormUser.Profile != "" && *gormUser.Profile != "{}" {
@@ -285,5 +290,9 @@ func (r *postgresRepository) toDomain(gormUser *model.User) (*user.User, error)
 		u.IsActive = *gormUser.IsActive
 	}
 
+	if gormUser.Role != nil {
+		u.Role = *gormUser.Role
+	}
+
 	return u, nil
 }
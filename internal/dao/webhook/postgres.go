@@ -0,0 +1,303 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/domain/webhook"
+	"mem_bank/pkg/database"
+)
+
+// subscriptionRow mirrors a row of the webhook_subscriptions table
+type subscriptionRow struct {
+	ID          string         `gorm:"column:id;primaryKey"`
+	UserID      string         `gorm:"column:user_id"`
+	URL         string         `gorm:"column:url"`
+	Secret      string         `gorm:"column:secret"`
+	Description string         `gorm:"column:description"`
+	EventTypes  pq.StringArray `gorm:"column:event_types;type:text[]"`
+	Active      bool           `gorm:"column:active"`
+	CreatedAt   time.Time      `gorm:"column:created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at"`
+}
+
+func (subscriptionRow) TableName() string { return "webhook_subscriptions" }
+
+// deliveryRow mirrors a row of the webhook_deliveries table
+type deliveryRow struct {
+	ID             string    `gorm:"column:id;primaryKey"`
+	SubscriptionID string    `gorm:"column:subscription_id"`
+	UserID         string    `gorm:"column:user_id"`
+	EventID        string    `gorm:"column:event_id"`
+	EventType      string    `gorm:"column:event_type"`
+	Attempt        int       `gorm:"column:attempt"`
+	StatusCode     *int      `gorm:"column:status_code"`
+	Succeeded      bool      `gorm:"column:succeeded"`
+	Error          *string   `gorm:"column:error"`
+	DurationMS     int64     `gorm:"column:duration_ms"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
+
+func (deliveryRow) TableName() string { return "webhook_deliveries" }
+
+// postgresRepository implements webhook.Repository using PostgreSQL.
+// Queries run in transactions scoped to the tenant in the context, see
+// database.Transaction.
+type postgresRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based webhook repository
+func NewPostgresRepository(db *gorm.DB) webhook.Repository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) Store(ctx context.Context, s *webhook.Subscription) error {
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Create(toSubscriptionRow(s)).Error
+	})
+	if err != nil {
+		return fmt.Errorf("storing webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) FindByID(ctx context.Context, id webhook.ID) (*webhook.Subscription, error) {
+	var row subscriptionRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Where("id = ?", id.String()).First(&row).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, webhook.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding webhook subscription: %w", err)
+	}
+	return toSubscription(&row)
+}
+
+func (r *postgresRepository) ListByUserID(ctx context.Context, userID user.ID) ([]*webhook.Subscription, error) {
+	var rows []subscriptionRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Where("user_id = ?", userID.String()).Order("created_at DESC").Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing webhook subscriptions: %w", err)
+	}
+	return toSubscriptions(rows)
+}
+
+func (r *postgresRepository) CountByUserID(ctx context.Context, userID user.ID) (int, error) {
+	var count int64
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Model(&subscriptionRow{}).Where("user_id = ?", userID.String()).Count(&count).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("counting webhook subscriptions: %w", err)
+	}
+	return int(count), nil
+}
+
+func (r *postgresRepository) Update(ctx context.Context, s *webhook.Subscription) error {
+	var result *gorm.DB
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		result = tx.Model(&subscriptionRow{}).
+			Where("id = ?", s.ID.String()).
+			Updates(map[string]interface{}{
+				"url":         s.URL,
+				"description": s.Description,
+				"event_types": pq.StringArray(s.EventTypes),
+				"active":      s.Active,
+				"updated_at":  s.UpdatedAt,
+			})
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("updating webhook subscription: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, id webhook.ID) error {
+	// Deliveries go with the subscription through ON DELETE CASCADE
+	var result *gorm.DB
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		result = tx.Where("id = ?", id.String()).Delete(&subscriptionRow{})
+		return result.Error
+	})
+	if err != nil {
+		return fmt.Errorf("deleting webhook subscription: %w", err)
+	}
+	if result.RowsAffected == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+func (r *postgresRepository) FindSubscribers(ctx context.Context, e *event.Event) ([]*webhook.Subscription, error) {
+	var rows []subscriptionRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		q := tx.Where("active AND created_at <= ?", e.CreatedAt).
+			Where("(cardinality(event_types) = 0 OR ? = ANY(event_types))", e.Type)
+		if e.SpaceID.IsZero() {
+			q = q.Where("user_id = ?", e.UserID.String())
+		} else {
+			q = q.Where("user_id IN (SELECT user_id FROM space_members WHERE space_id = ?)", e.SpaceID.String())
+		}
+		return q.Order("created_at").Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding webhook subscribers: %w", err)
+	}
+	return toSubscriptions(rows)
+}
+
+func (r *postgresRepository) StoreDelivery(ctx context.Context, d *webhook.Delivery) error {
+	row := &deliveryRow{
+		ID:             d.ID.String(),
+		SubscriptionID: d.SubscriptionID.String(),
+		UserID:         d.UserID.String(),
+		EventID:        d.EventID.String(),
+		EventType:      d.EventType,
+		Attempt:        d.Attempt,
+		Succeeded:      d.Succeeded,
+		DurationMS:     d.Duration.Milliseconds(),
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.StatusCode != 0 {
+		row.StatusCode = &d.StatusCode
+	}
+	if d.Error != "" {
+		row.Error = &d.Error
+	}
+
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Create(row).Error
+	})
+	if err != nil {
+		return fmt.Errorf("storing webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *postgresRepository) ListDeliveries(ctx context.Context, id webhook.ID, limit, offset int) ([]*webhook.Delivery, error) {
+	var rows []deliveryRow
+	err := database.Transaction(ctx, r.db, func(tx *gorm.DB) error {
+		return tx.Where("subscription_id = ?", id.String()).
+			Order("created_at DESC").
+			Limit(limit).
+			Offset(offset).
+			Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*webhook.Delivery, 0, len(rows))
+	for i := range rows {
+		d, err := toDelivery(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+func toSubscriptionRow(s *webhook.Subscription) *subscriptionRow {
+	return &subscriptionRow{
+		ID:          s.ID.String(),
+		UserID:      s.UserID.String(),
+		URL:         s.URL,
+		Secret:      s.Secret,
+		Description: s.Description,
+		EventTypes:  pq.StringArray(s.EventTypes),
+		Active:      s.Active,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+func toSubscriptions(rows []subscriptionRow) ([]*webhook.Subscription, error) {
+	subscriptions := make([]*webhook.Subscription, 0, len(rows))
+	for i := range rows {
+		s, err := toSubscription(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, nil
+}
+
+func toSubscription(row *subscriptionRow) (*webhook.Subscription, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing webhook subscription ID: %w", err)
+	}
+	userID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+
+	return &webhook.Subscription{
+		ID:          webhook.ID(id),
+		UserID:      user.ID(userID),
+		URL:         row.URL,
+		Secret:      row.Secret,
+		Description: row.Description,
+		EventTypes:  []string(row.EventTypes),
+		Active:      row.Active,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}, nil
+}
+
+func toDelivery(row *deliveryRow) (*webhook.Delivery, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing webhook delivery ID: %w", err)
+	}
+	subscriptionID, err := uuid.Parse(row.SubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing webhook subscription ID: %w", err)
+	}
+	userID, err := uuid.Parse(row.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+	eventID, err := uuid.Parse(row.EventID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing event ID: %w", err)
+	}
+
+	d := &webhook.Delivery{
+		ID:             id,
+		SubscriptionID: webhook.ID(subscriptionID),
+		UserID:         user.ID(userID),
+		EventID:        event.ID(eventID),
+		EventType:      row.EventType,
+		Attempt:        row.Attempt,
+		Succeeded:      row.Succeeded,
+		Duration:       time.Duration(row.DurationMS) * time.Millisecond,
+		CreatedAt:      row.CreatedAt,
+	}
+	if row.StatusCode != nil {
+		d.StatusCode = *row.StatusCode
+	}
+	if row.Error != nil {
+		d.Error = *row.Error
+	}
+	return d, nil
+}
@@ -0,0 +1,66 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Scopes an API key may be granted
+const (
+	ScopeMemoriesRead  = "memories:read"
+	ScopeMemoriesWrite = "memories:write"
+	// ScopeAdmin grants every scope and the admin role
+	ScopeAdmin = "admin"
+)
+
+// KeyPrefix starts every API key so leaked keys are easy to recognise
+const KeyPrefix = "mbk_"
+
+// ValidScope reports whether scope is known
+func ValidScope(scope string) bool {
+	switch scope {
+	case ScopeMemoriesRead, ScopeMemoriesWrite, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// APIKey is a long-lived credential owned by a user. The secret is only
+// shown once at creation; afterwards a key is identified by its prefix and
+// stored as a SHA-256 hash.
+type APIKey struct {
+	ID         uuid.UUID
+	UserID     user.ID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  time.Time // zero for keys that never expire
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+	RevokedAt  time.Time
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired reports whether the key has expired at t
+func (k *APIKey) IsExpired(t time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !t.Before(k.ExpiresAt)
+}
+
+// IsRevoked reports whether the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return !k.RevokedAt.IsZero()
+}
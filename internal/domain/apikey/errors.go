@@ -0,0 +1,13 @@
+package apikey
+
+import "errors"
+
+// Domain-specific errors for API keys
+var (
+	ErrNotFound      = errors.New("API key not found")
+	ErrInvalidKey    = errors.New("invalid API key")
+	ErrInvalidName   = errors.New("invalid API key name")
+	ErrInvalidScope  = errors.New("invalid API key scope")
+	ErrInvalidExpiry = errors.New("API key expiry must be in the future")
+	ErrTooManyKeys   = errors.New("too many API keys")
+)
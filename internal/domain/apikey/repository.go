@@ -0,0 +1,35 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Repository defines the interface for API key data access operations
+type Repository interface {
+	// Store saves a new API key
+	Store(ctx context.Context, key *APIKey) error
+
+	// FindByID retrieves an API key by ID
+	FindByID(ctx context.Context, id uuid.UUID) (*APIKey, error)
+
+	// FindByHash retrieves an API key by the hash of its value
+	FindByHash(ctx context.Context, keyHash string) (*APIKey, error)
+
+	// ListByUserID returns the keys of a user, newest first, including
+	// revoked and expired ones
+	ListByUserID(ctx context.Context, userID user.ID) ([]*APIKey, error)
+
+	// CountActiveByUserID counts the unrevoked keys of a user
+	CountActiveByUserID(ctx context.Context, userID user.ID) (int, error)
+
+	// Revoke marks a key of a user as revoked
+	Revoke(ctx context.Context, userID user.ID, id uuid.UUID) error
+
+	// TouchLastUsed records that a key was used at t
+	TouchLastUsed(ctx context.Context, id uuid.UUID, t time.Time) error
+}
@@ -0,0 +1,11 @@
+package apikey
+
+import "time"
+
+// CreateRequest represents a request to create an API key
+type CreateRequest struct {
+	Name   string
+	Scopes []string
+	// ExpiresAt is optional; the zero value creates a key that never expires
+	ExpiresAt time.Time
+}
@@ -0,0 +1,28 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Service defines the business operations for API keys
+type Service interface {
+	// Create issues a key for a user and returns it with its secret value,
+	// which is not stored and cannot be retrieved again
+	Create(ctx context.Context, userID user.ID, req CreateRequest) (*APIKey, string, error)
+
+	// List returns the keys of a user
+	List(ctx context.Context, userID user.ID) ([]*APIKey, error)
+
+	// Get returns a key of a user
+	Get(ctx context.Context, userID user.ID, id uuid.UUID) (*APIKey, error)
+
+	// Revoke revokes a key of a user
+	Revoke(ctx context.Context, userID user.ID, id uuid.UUID) error
+
+	// Authenticate resolves a presented key to the key and its active owner
+	Authenticate(ctx context.Context, key string) (*APIKey, *user.User, error)
+}
@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/user"
+)
+
+// ID represents an audit entry identifier
+type ID uuid.UUID
+
+// NewID creates a new audit entry ID
+func NewID() ID {
+	return ID(uuid.New())
+}
+
+// String returns the string representation of the audit entry ID
+func (id ID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// IsZero checks if the ID is zero
+func (id ID) IsZero() bool {
+	return uuid.UUID(id) == uuid.Nil
+}
+
+// Kinds of actor
+const (
+	ActorUser   = "user"
+	ActorAPIKey = "api_key"
+	ActorSystem = "system"
+)
+
+// Resource types
+const (
+	ResourceMemory = "memory"
+	ResourceUser   = "user"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionMemoryCreate    = "memory.create"
+	ActionMemoryRead      = "memory.read"
+	ActionMemoryRehydrate = "memory.rehydrate"
+	ActionMemoryUpdate    = "memory.update"
+	ActionMemoryDelete    = "memory.delete"
+	ActionUserCreate      = "user.create"
+	ActionUserUpdate      = "user.update"
+	ActionUserDelete      = "user.delete"
+	ActionUserLogin       = "user.login"
+)
+
+// Actor is who performed an action. System actors are background jobs and
+// other internal callers without a user.
+type Actor struct {
+	Type     string
+	UserID   user.ID
+	Role     string
+	APIKeyID string
+}
+
+// Entry records one action on a resource. The log holds hashes of the
+// resource before and after the action, never its content.
+type Entry struct {
+	ID           ID
+	Sequence     int64 // position in the log, assigned when appended
+	Actor        Actor
+	Action       string
+	ResourceType string
+	ResourceID   string
+	RequestID    string
+	BeforeHash   string // empty when the resource did not exist before
+	AfterHash    string // empty when the resource no longer exists
+	CreatedAt    time.Time
+
+	// PrevHash and Hash link the entry to the one before it when the hash
+	// chain is enabled; both are empty otherwise
+	PrevHash string
+	Hash     string
+}
+
+// ChainHash returns the hash of the entry linked to the hash of the entry
+// before it. Any change to a recorded field changes the hash.
+func (e *Entry) ChainHash(prevHash string) string {
+	fields := []string{
+		prevHash,
+		e.ID.String(),
+		e.Actor.Type,
+		e.Actor.UserID.String(),
+		e.Actor.Role,
+		e.Actor.APIKeyID,
+		e.Action,
+		e.ResourceType,
+		e.ResourceID,
+		e.RequestID,
+		e.BeforeHash,
+		e.AfterHash,
+		strconv.FormatInt(e.CreatedAt.UnixMicro(), 10),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Filter selects audit entries; zero fields match everything
+type Filter struct {
+	ActorID      user.ID
+	Action       string
+	ResourceType string
+	ResourceID   string
+	RequestID    string
+	Since        time.Time
+	Until        time.Time
+	Limit        int
+	Offset       int
+}
+
+// ChainVerification is the outcome of checking the hash chain
+type ChainVerification struct {
+	Checked int64 // entries checked
+	Valid   bool
+	// BrokenAt is the sequence of the first entry whose hash does not
+	// match, when the chain is not valid
+	BrokenAt int64
+}
@@ -0,0 +1,9 @@
+package audit
+
+import "errors"
+
+// Domain-specific errors for the audit log
+var (
+	ErrInvalidFilter   = errors.New("invalid audit filter")
+	ErrChainNotEnabled = errors.New("audit hash chain is not enabled")
+)
@@ -0,0 +1,16 @@
+package audit
+
+import "context"
+
+// Repository stores the audit log. Entries are only ever appended.
+type Repository interface {
+	// Append adds an entry at the end of the log and sets its sequence, and
+	// its hashes when the chain is enabled
+	Append(ctx context.Context, e *Entry) error
+
+	// Find returns the entries matching filter, newest first
+	Find(ctx context.Context, filter Filter) ([]*Entry, error)
+
+	// ListAfter returns up to limit entries following sequence, oldest first
+	ListAfter(ctx context.Context, sequence int64, limit int) ([]*Entry, error)
+}
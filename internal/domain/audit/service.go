@@ -0,0 +1,26 @@
+package audit
+
+import "context"
+
+// Recorder records actions on resources. Recording never fails the action:
+// entries are written asynchronously and errors are logged.
+type Recorder interface {
+	// Record notes that the caller in ctx performed action on a resource;
+	// before and after are the resource's states around the action and are
+	// only hashed. Either may be nil.
+	Record(ctx context.Context, action, resourceType, resourceID string, before, after interface{})
+}
+
+// Service defines the operations of the audit log
+type Service interface {
+	Recorder
+
+	// Append writes an entry recorded earlier; it runs in the audit job
+	Append(ctx context.Context, e *Entry) error
+
+	// Query returns the entries matching filter, newest first; admins only
+	Query(ctx context.Context, filter Filter) ([]*Entry, error)
+
+	// VerifyChain checks the hash chain from the start of the log; admins only
+	VerifyChain(ctx context.Context) (*ChainVerification, error)
+}
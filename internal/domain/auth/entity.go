@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Credential holds a user's password hash and failed login state
+type Credential struct {
+	UserID            user.ID
+	PasswordHash      string
+	FailedAttempts    int
+	LockedUntil       time.Time
+	PasswordChangedAt time.Time
+	UpdatedAt         time.Time
+}
+
+// IsLocked reports whether logins are refused at t
+func (c *Credential) IsLocked(t time.Time) bool {
+	return c.LockedUntil.After(t)
+}
+
+// Identity links a user to an account at an external OpenID provider
+type Identity struct {
+	ID        uuid.UUID
+	UserID    user.ID
+	Issuer    string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+	LastLogin time.Time
+}
+
+// Roles carried in issued tokens
+const (
+	// RoleUser is the role of regular users
+	RoleUser = user.RoleUser
+	// RoleAdmin may manage every user and their resources
+	RoleAdmin = user.RoleAdmin
+	// RoleSystem is used by internal callers acting on behalf of any user
+	RoleSystem = "system"
+)
+
+// RefreshToken is a long-lived credential exchanged for access tokens. Only
+// a hash of the token is stored. Every refresh replaces the token with a new
+// one of the same family; presenting a replaced token again revokes the family.
+type RefreshToken struct {
+	ID         uuid.UUID
+	UserID     user.ID
+	FamilyID   uuid.UUID
+	TokenHash  string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	UsedAt     time.Time
+	RevokedAt  time.Time
+	ReplacedBy uuid.UUID
+}
+
+// IsUsed reports whether the token was already exchanged
+func (t *RefreshToken) IsUsed() bool {
+	return !t.UsedAt.IsZero()
+}
+
+// IsRevoked reports whether the token was revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return !t.RevokedAt.IsZero()
+}
+
+// IsExpired reports whether the token has expired at now
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// TokenPair holds the tokens issued at login or refresh
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	ExpiresIn        time.Duration
+	RefreshExpiresIn time.Duration
+	SessionID        string
+}
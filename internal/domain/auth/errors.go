@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Domain-specific errors for authentication
+var (
+	ErrNotFound            = errors.New("credential not found")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrAccountLocked       = errors.New("account temporarily locked")
+	ErrInactive            = errors.New("account is inactive")
+	ErrWeakPassword        = errors.New("password does not meet requirements")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+	ErrPermissionDenied    = errors.New("permission denied")
+	ErrIdentityNotFound    = errors.New("identity not found")
+	ErrIdentityConflict    = errors.New("email belongs to an existing account")
+	ErrOIDCLoginFailed     = errors.New("OpenID Connect login failed")
+)
+
+// LockedError reports until when an account refuses logins
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("account locked until %s", e.Until.UTC().Format(time.RFC3339))
+}
+
+// Is makes errors.Is(err, ErrAccountLocked) match
+func (e *LockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
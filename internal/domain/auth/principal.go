@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	UserID user.ID
+	Role   string
+	// APIKeyID is set when the caller signed in with an API key
+	APIKeyID string
+}
+
+// IsPrivileged reports whether the principal may act on any user's resources
+func (p Principal) IsPrivileged() bool {
+	return p.Role == RoleAdmin || p.Role == RoleSystem
+}
+
+// CanAccess reports whether the principal may act on resources owned by owner
+func (p Principal) CanAccess(owner user.ID) bool {
+	return p.IsPrivileged() || (!p.UserID.IsZero() && p.UserID == owner)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the caller of a request
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the caller attached to ctx, if any
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Authorize checks that the caller in ctx owns resources of owner or holds
+// an admin or system role. Contexts without a caller are denied.
+func Authorize(ctx context.Context, owner user.ID) error {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok || !p.CanAccess(owner) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/user"
+)
+
+// CredentialRepository defines the interface for credential data access operations
+type CredentialRepository interface {
+	// FindByUserID retrieves the credential of a user
+	FindByUserID(ctx context.Context, userID user.ID) (*Credential, error)
+
+	// Save creates or replaces the credential of a user
+	Save(ctx context.Context, credential *Credential) error
+
+	// RecordFailure atomically increments the failed attempts of a user and
+	// returns the new count
+	RecordFailure(ctx context.Context, userID user.ID) (int, error)
+
+	// Lock refuses logins for a user until the given time
+	Lock(ctx context.Context, userID user.ID, until time.Time) error
+
+	// ResetFailures clears failed attempts and any lock
+	ResetFailures(ctx context.Context, userID user.ID) error
+}
+
+// RefreshTokenRepository defines the interface for refresh token data access operations
+type RefreshTokenRepository interface {
+	// Store saves a new refresh token
+	Store(ctx context.Context, token *RefreshToken) error
+
+	// FindByHash retrieves a refresh token by the hash of its value
+	FindByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+
+	// MarkUsed records that a token was exchanged for replacedBy. It reports
+	// false when the token was already used or revoked, so two concurrent
+	// refreshes with one token cannot both succeed.
+	MarkUsed(ctx context.Context, id uuid.UUID, replacedBy uuid.UUID) (bool, error)
+
+	// RevokeFamily revokes every token of a family
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+
+	// RevokeUser revokes every active token of a user and returns the
+	// families they belonged to
+	RevokeUser(ctx context.Context, userID user.ID) ([]uuid.UUID, error)
+}
+
+// IdentityRepository defines the interface for external identity data access operations
+type IdentityRepository interface {
+	// FindByIssuerSubject retrieves the identity a provider knows as subject
+	FindByIssuerSubject(ctx context.Context, issuer, subject string) (*Identity, error)
+
+	// Store saves a new identity
+	Store(ctx context.Context, identity *Identity) error
+
+	// TouchLastLogin records a login through an identity
+	TouchLastLogin(ctx context.Context, id uuid.UUID, t time.Time) error
+}
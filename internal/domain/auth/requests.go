@@ -0,0 +1,24 @@
+package auth
+
+import "mem_bank/internal/domain/user"
+
+// RegisterRequest represents a request to create a user with a password
+type RegisterRequest struct {
+	Username string
+	Email    string
+	Password string
+	Profile  user.Profile
+}
+
+// ChangePasswordRequest represents a request to replace a user's password
+type ChangePasswordRequest struct {
+	CurrentPassword string
+	NewPassword     string
+}
+
+// LogoutRequest identifies what a logout revokes: the presented access
+// token and its session, and the family of a presented refresh token
+type LogoutRequest struct {
+	RefreshToken string
+	SessionID    string
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Service defines the business operations for password authentication
+type Service interface {
+	// Register creates a user with a password
+	Register(ctx context.Context, req RegisterRequest) (*user.User, error)
+
+	// Login verifies a username or email and password and returns the user
+	Login(ctx context.Context, req user.LoginRequest) (*user.User, error)
+
+	// SetPassword sets a user's password without checking the current one
+	SetPassword(ctx context.Context, userID user.ID, password string) error
+
+	// ChangePassword replaces a user's password after verifying the current one
+	ChangePassword(ctx context.Context, userID user.ID, req ChangePasswordRequest) error
+}
+
+// TokenService defines the operations on refresh token sessions
+type TokenService interface {
+	// IssueTokens starts a new session for a user
+	IssueTokens(ctx context.Context, u *user.User) (*TokenPair, error)
+
+	// RefreshTokens exchanges a refresh token for a new pair, rotating the
+	// refresh token. Reusing a rotated token revokes its whole family.
+	RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, *user.User, error)
+
+	// Logout revokes a session
+	Logout(ctx context.Context, req LogoutRequest) error
+
+	// RevokeAll revokes every session of a user
+	RevokeAll(ctx context.Context, userID user.ID) error
+}
+
+// OIDCService defines the operations for signing in through an external
+// OpenID provider
+type OIDCService interface {
+	// LoginURL starts a login and returns the provider URL to redirect to
+	LoginURL(ctx context.Context) (string, error)
+
+	// Callback completes a login with the state and code the provider
+	// redirected back with and returns the signed in user, provisioning one
+	// on first login
+	Callback(ctx context.Context, state, code string) (*user.User, error)
+}
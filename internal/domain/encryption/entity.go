@@ -0,0 +1,10 @@
+package encryption
+
+import "mem_bank/internal/domain/user"
+
+// KeyRotation reports the outcome of rotating a user's data key
+type KeyRotation struct {
+	UserID      user.ID
+	Version     int
+	Reencrypted int
+}
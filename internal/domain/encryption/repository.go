@@ -0,0 +1,14 @@
+package encryption
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// MemoryReencrypter brings stored memories onto their owner's current data key
+type MemoryReencrypter interface {
+	// ReencryptMemories re-encrypts up to limit memories of a user that are
+	// in plaintext or under an older key version, returning how many it did
+	ReencryptMemories(ctx context.Context, userID user.ID, limit int) (int, error)
+}
@@ -0,0 +1,18 @@
+package encryption
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Service defines the administration of memory encryption keys
+type Service interface {
+	// RotateUserKey gives a user a new data key version and re-encrypts
+	// their memories with it
+	RotateUserKey(ctx context.Context, userID user.ID) (*KeyRotation, error)
+
+	// RewrapKeys wraps every data key with the current master key of the
+	// KMS, after the master key was rotated, returning how many changed
+	RewrapKeys(ctx context.Context) (int, error)
+}
@@ -0,0 +1,93 @@
+package erasure
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+)
+
+// ID represents an erasure identifier
+type ID uuid.UUID
+
+// NewID creates a new erasure ID
+func NewID() ID {
+	return ID(uuid.New())
+}
+
+// String returns the string representation of the erasure ID
+func (id ID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// IsZero checks if the ID is zero
+func (id ID) IsZero() bool {
+	return uuid.UUID(id) == uuid.Nil
+}
+
+// Erasure statuses
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+)
+
+// Erasure is a request to forget a user: every store holding their data
+// is wiped, and a signed receipt records what was removed. The erasure
+// outlives the user it names.
+type Erasure struct {
+	ID          ID
+	UserID      user.ID
+	RequestedBy user.ID
+	Status      string
+	Stores      []StoreResult // filled in as each store is wiped
+	LastError   string
+	RequestedAt time.Time
+	CompletedAt *time.Time
+
+	// Receipt is a token signed with the access token keys whose claims
+	// restate the erasure; it is set once the erasure completes
+	Receipt string
+}
+
+// StoreResult records what was removed from one store. What an item is
+// depends on the store: a memory, a vector, a cache entry, a job or a key.
+type StoreResult struct {
+	Store   string `json:"store"`
+	Removed int64  `json:"removed"`
+}
+
+// Subject is what an erasure needs to know about the user before their
+// data is gone: the memories they wrote and the text that was embedded
+type Subject struct {
+	UserID   user.ID
+	Memories []MemoryContent
+}
+
+// MemoryIDs returns the IDs of the subject's memories
+func (s *Subject) MemoryIDs() []memory.ID {
+	ids := make([]memory.ID, len(s.Memories))
+	for i, m := range s.Memories {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+// MemoryContent is the ID and plaintext content of a memory. Content is
+// empty when it can no longer be decrypted.
+type MemoryContent struct {
+	ID      memory.ID
+	Content string
+}
+
+// AddResult records the result of a store, adding to those of earlier attempts
+func (e *Erasure) AddResult(store string, removed int64) {
+	for i := range e.Stores {
+		if e.Stores[i].Store == store {
+			e.Stores[i].Removed += removed
+			return
+		}
+	}
+	e.Stores = append(e.Stores, StoreResult{Store: store, Removed: removed})
+}
@@ -0,0 +1,9 @@
+package erasure
+
+import "errors"
+
+// Domain-specific errors for erasures
+var (
+	ErrNotFound  = errors.New("erasure not found")
+	ErrInvalidID = errors.New("invalid erasure ID")
+)
@@ -0,0 +1,39 @@
+package erasure
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+)
+
+// Repository persists erasures
+type Repository interface {
+	// Create stores a new erasure
+	Create(ctx context.Context, e *Erasure) error
+
+	// FindByID retrieves an erasure by its ID
+	FindByID(ctx context.Context, id ID) (*Erasure, error)
+
+	// Update stores the progress of an erasure
+	Update(ctx context.Context, e *Erasure) error
+}
+
+// MemoryLister lists the memories a user wrote, including those in shared
+// spaces, whoever the caller is
+type MemoryLister interface {
+	// ListMemoryContents returns up to limit memories of a user with IDs
+	// after cursor, in ID order
+	ListMemoryContents(ctx context.Context, userID user.ID, limit int, cursor memory.ID) ([]MemoryContent, error)
+}
+
+// Store is a place that holds user data
+type Store interface {
+	// Name identifies the store in receipts
+	Name() string
+
+	// EraseUser removes the data of subject, returning how many items it
+	// removed. It is called again when an erasure is retried, so it must
+	// tolerate data that is already gone.
+	EraseUser(ctx context.Context, subject *Subject) (int64, error)
+}
@@ -0,0 +1,22 @@
+package erasure
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Service defines the "forget me" operation
+type Service interface {
+	// RequestErasure deactivates a user and queues the erasure of their
+	// data; the user themselves or an admin may ask
+	RequestErasure(ctx context.Context, userID user.ID) (*Erasure, error)
+
+	// GetErasure retrieves an erasure and its receipt; the erased user, whoever
+	// requested it or an admin may read it
+	GetErasure(ctx context.Context, id ID) (*Erasure, error)
+
+	// Erase wipes the user's data from every store and signs the receipt.
+	// It runs in the erasure job and may be retried.
+	Erase(ctx context.Context, id ID) (*Erasure, error)
+}
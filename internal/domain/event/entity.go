@@ -0,0 +1,101 @@
+package event
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+)
+
+// ID represents an event identifier
+type ID uuid.UUID
+
+// NewID creates a new event ID
+func NewID() ID {
+	return ID(uuid.New())
+}
+
+// String returns the string representation of the event ID
+func (id ID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// IsZero checks if the ID is zero
+func (id ID) IsZero() bool {
+	return uuid.UUID(id) == uuid.Nil
+}
+
+// Types of event. Every change to a stored memory is a memory.updated
+// event; one that also sets a new embedding is a memory.embedded event too.
+const (
+	TypeMemoryCreated  = "memory.created"
+	TypeMemoryUpdated  = "memory.updated"
+	TypeMemoryDeleted  = "memory.deleted"
+	TypeMemoryEmbedded = "memory.embedded"
+)
+
+// ValidType reports whether t is a known event type
+func ValidType(t string) bool {
+	switch t {
+	case TypeMemoryCreated, TypeMemoryUpdated, TypeMemoryDeleted, TypeMemoryEmbedded:
+		return true
+	default:
+		return false
+	}
+}
+
+// Event is a change to a memory. Events are written to an outbox in the
+// transaction that makes the change, so they exist exactly when the change
+// was committed, and are numbered in commit order by Sequence.
+//
+// Data describes the memory without its content or summary, which may be
+// encrypted or hold personal data; consumers read the memory for those.
+type Event struct {
+	ID       ID
+	Sequence int64
+	Type     string
+	// UserID is the author of the memory
+	UserID    user.ID
+	SpaceID   space.ID // zero for personal memories
+	MemoryID  memory.ID
+	Data      map[string]interface{}
+	CreatedAt time.Time
+}
+
+// NewMemoryEvent creates an event of type eventType about m
+func NewMemoryEvent(eventType string, m *memory.Memory) *Event {
+	data := map[string]interface{}{
+		"memory_type": m.MemoryType,
+		"importance":  m.Importance,
+		"tags":        m.Tags,
+	}
+	if m.EmbeddingModel != "" {
+		data["embedding_model"] = m.EmbeddingModel
+		data["embedding_dimensions"] = len(m.Embedding)
+	}
+
+	return &Event{
+		ID:        NewID(),
+		Type:      eventType,
+		UserID:    m.UserID,
+		SpaceID:   m.SpaceID,
+		MemoryID:  m.ID,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Filter selects the events of a feed
+type Filter struct {
+	// UserID selects the events of the user's personal memories and of the
+	// memories of the spaces they are a member of
+	UserID user.ID
+	// Since selects the events after this sequence number
+	Since int64
+	// Types limits the events to these types when set
+	Types []string
+	Limit int
+}
@@ -0,0 +1,10 @@
+package event
+
+import "errors"
+
+// Domain-specific errors for memory events
+var (
+	ErrNotFound       = errors.New("event not found")
+	ErrInvalidFilter  = errors.New("invalid event filter")
+	ErrStreamDisabled = errors.New("event stream is disabled")
+)
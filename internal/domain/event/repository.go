@@ -0,0 +1,29 @@
+package event
+
+import (
+	"context"
+	"time"
+)
+
+// Repository reads the event outbox. Events are written by the memory
+// repository as part of each change.
+type Repository interface {
+	// List returns the events matching filter, oldest first
+	List(ctx context.Context, filter Filter) ([]*Event, error)
+
+	// FindByID retrieves an event by its ID
+	FindByID(ctx context.Context, id ID) (*Event, error)
+
+	// DispatchPending passes up to limit events that were not dispatched yet
+	// to fn, oldest first, and marks those fn accepted as dispatched. It
+	// stops at the first error. The events stay locked while fn runs, so
+	// concurrent dispatchers skip them.
+	DispatchPending(ctx context.Context, limit int, fn func(e *Event) error) (int, error)
+
+	// PublishPending is DispatchPending for publishing to the streams, which
+	// keeps its own record of the events it has processed
+	PublishPending(ctx context.Context, limit int, fn func(e *Event) error) (int, error)
+
+	// DeleteBefore removes the events created before t
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
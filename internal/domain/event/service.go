@@ -0,0 +1,31 @@
+package event
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Service defines the operations of the event feed
+type Service interface {
+	// List returns the events of filter.UserID after filter.Since, oldest
+	// first. Callers may only read their own feed unless they are admins.
+	List(ctx context.Context, filter Filter) ([]*Event, error)
+
+	// Stream returns the events of the memories userID authored as they
+	// happen, after the retained events with a sequence above after. The
+	// channel is closed when ctx is done. The same access rules as List apply.
+	Stream(ctx context.Context, userID user.ID, after int64) (<-chan *Event, error)
+}
+
+// Broker fans events out to the streams open on every replica and retains
+// the recent events of each user so streams can resume
+type Broker interface {
+	// Publish sends e to the streams of its author
+	Publish(ctx context.Context, e *Event) error
+
+	// Subscribe returns the retained events of userID with a sequence above
+	// after, then those published until ctx is done. An after of zero
+	// skips the retained events.
+	Subscribe(ctx context.Context, userID user.ID, after int64) (<-chan *Event, error)
+}
@@ -5,27 +5,32 @@ import (
 
 	"github.com/google/uuid"
 
+	"mem_bank/internal/domain/space"
 	"mem_bank/internal/domain/user"
 )
 
 // ID represents a memory identifier
 type ID uuid.UUID
 
-// Memory represents a core business entity for memories
+// Memory represents a core business entity for memories. UserID is the
+// author; a memory with a SpaceID belongs to that shared space, one without
+// is in its author's personal space.
 type Memory struct {
-	ID           ID
-	UserID       user.ID
-	Content      string
-	Summary      string
-	Embedding    []float32
-	Importance   int
-	MemoryType   string
-	Tags         []string
-	Metadata     map[string]interface{}
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	LastAccessed time.Time
-	AccessCount  int
+	ID             ID
+	UserID         user.ID
+	SpaceID        space.ID
+	Content        string
+	Summary        string
+	Embedding      []float32
+	EmbeddingModel string
+	Importance     int
+	MemoryType     string
+	Tags           []string
+	Metadata       map[string]interface{}
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	LastAccessed   time.Time
+	AccessCount    int
 }
 
 // NewID creates a new memory ID
@@ -61,6 +66,11 @@ func NewMemory(userID user.ID, content, summary string, importance int, memoryTy
 	}
 }
 
+// IsShared reports whether the memory belongs to a shared space
+func (m *Memory) IsShared() bool {
+	return !m.SpaceID.IsZero()
+}
+
 // Access records an access to this memory
 func (m *Memory) Access() {
 	m.LastAccessed = time.Now()
@@ -78,8 +88,9 @@ func (m *Memory) Update(content, summary string, importance int, tags []string,
 }
 
 // UpdateEmbedding updates the embedding vector
-func (m *Memory) UpdateEmbedding(embedding []float32) {
+func (m *Memory) UpdateEmbedding(embedding []float32, model string) {
 	m.Embedding = embedding
+	m.EmbeddingModel = model
 	m.UpdatedAt = time.Now()
 }
 
@@ -119,6 +130,7 @@ func (m *Memory) HasTag(tag string) bool {
 type EmbeddingUpdate struct {
 	ID        ID        `json:"id"`
 	Embedding []float32 `json:"embedding"`
+	Model     string    `json:"model"`
 }
 
 // MemoryWithScore represents a memory with its similarity score
@@ -68,4 +68,6 @@ const (
 	ErrCodePermissionDenied = "PERMISSION_DENIED"
 	ErrCodeInternalError    = "INTERNAL_ERROR"
 	ErrCodeExternalService  = "EXTERNAL_SERVICE_ERROR"
+	ErrCodeQuotaExceeded    = "QUOTA_EXCEEDED"
+	ErrCodePIIDetected      = "PII_DETECTED"
 )
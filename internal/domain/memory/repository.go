@@ -3,9 +3,27 @@ package memory
 import (
 	"context"
 
+	"mem_bank/internal/domain/space"
 	"mem_bank/internal/domain/user"
 )
 
+// Scope selects the memories a query may return on behalf of a user: their
+// personal memories and those of the shared spaces they are a member of.
+// Repositories check membership within the query itself, so a scope never
+// reaches a space the user does not belong to.
+type Scope struct {
+	UserID user.ID
+	// Personal includes the user's personal memories
+	Personal bool
+	// Shared includes the memories of the user's spaces, limited to
+	// SpaceIDs when set
+	Shared   bool
+	SpaceIDs []space.ID
+	// Unrestricted skips the membership check; only for admin and system
+	// callers
+	Unrestricted bool
+}
+
 // Repository defines the interface for memory data access operations
 type Repository interface {
 	// Store creates a new memory in the repository
@@ -14,7 +32,7 @@ type Repository interface {
 	// FindByID retrieves a memory by its ID
 	FindByID(ctx context.Context, id ID) (*Memory, error)
 
-	// FindByUserID retrieves memories for a specific user with pagination
+	// FindByUserID retrieves a user's personal memories with pagination
 	FindByUserID(ctx context.Context, userID user.ID, limit, offset int) ([]*Memory, error)
 
 	// Update updates an existing memory
@@ -38,6 +56,18 @@ type Repository interface {
 	// FindByTags retrieves memories by tags for a specific user
 	FindByTags(ctx context.Context, tags []string, userID user.ID, limit, offset int) ([]*Memory, error)
 
+	// FindInScope retrieves the memories within a scope with pagination
+	FindInScope(ctx context.Context, scope Scope, limit, offset int) ([]*Memory, error)
+
+	// SearchByContentInScope searches the memories within a scope by content text
+	SearchByContentInScope(ctx context.Context, query string, scope Scope, limit, offset int) ([]*Memory, error)
+
+	// FindByTagsInScope retrieves the memories within a scope by tags
+	FindByTagsInScope(ctx context.Context, tags []string, scope Scope, limit, offset int) ([]*Memory, error)
+
+	// SearchSimilarInScope finds similar memories within a scope
+	SearchSimilarInScope(ctx context.Context, embedding []float32, scope Scope, limit int, threshold float64) ([]*Memory, error)
+
 	// UpdateAccessInfo updates the access information (last accessed time and count)
 	UpdateAccessInfo(ctx context.Context, id ID) error
 
@@ -47,6 +77,14 @@ type Repository interface {
 	// CountByUserID returns the total number of memories for a user
 	CountByUserID(ctx context.Context, userID user.ID) (int, error)
 
+	// FindWithoutEmbedding retrieves memories that have no embedding from the given model,
+	// ordered by ID and starting after cursor. Pass the zero ID to start from the beginning
+	// and an empty model to only match memories without any embedding.
+	FindWithoutEmbedding(ctx context.Context, userID user.ID, model string, limit int, cursor ID) ([]*Memory, error)
+
+	// GetEmbeddingCoverage returns embedding coverage counts for a user
+	GetEmbeddingCoverage(ctx context.Context, userID user.ID, model string) (*EmbeddingCoverage, error)
+
 	// Batch operations for better performance
 	BatchStore(ctx context.Context, memories []*Memory) error
 	BatchUpdate(ctx context.Context, memories []*Memory) error
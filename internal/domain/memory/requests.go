@@ -1,10 +1,15 @@
 package memory
 
-import "mem_bank/internal/domain/user"
+import (
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+)
 
 // CreateRequest represents a request to create a new memory
 type CreateRequest struct {
-	UserID     user.ID
+	UserID user.ID
+	// SpaceID stores the memory in a shared space; zero means personal
+	SpaceID    space.ID
 	Content    string
 	Summary    string
 	Importance int
@@ -32,6 +37,10 @@ type SearchRequest struct {
 	Limit      int
 	Offset     int
 	Threshold  float64
+	// IncludeShared extends the search to the shared spaces the user is a
+	// member of, or to SpaceIDs when set
+	IncludeShared bool
+	SpaceIDs      []space.ID
 }
 
 // Stats represents memory statistics for a user
@@ -41,3 +50,11 @@ type Stats struct {
 	RecentMemories    int
 	AverageImportance float64
 }
+
+// EmbeddingCoverage represents how many of a user's memories have embeddings
+type EmbeddingCoverage struct {
+	TotalMemories int
+	WithEmbedding int
+	// WithModel counts memories embedded with the requested model
+	WithModel int
+}
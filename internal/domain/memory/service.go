@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 
+	"mem_bank/internal/domain/space"
 	"mem_bank/internal/domain/user"
 )
 
@@ -11,24 +12,38 @@ type Service interface {
 	// CreateMemory creates a new memory with validation and embedding generation
 	CreateMemory(ctx context.Context, req CreateRequest) (*Memory, error)
 
+	// BatchCreateMemories creates several memories at once; either all are stored or none
+	BatchCreateMemories(ctx context.Context, reqs []CreateRequest) ([]*Memory, error)
+
 	// GetMemory retrieves a memory by ID and updates access info
 	GetMemory(ctx context.Context, id ID) (*Memory, error)
 
+	// RehydrateMemory retrieves a memory with its tokenized personal data
+	// restored; only the author may do so
+	RehydrateMemory(ctx context.Context, id ID) (*Memory, error)
+
 	// UpdateMemory updates an existing memory
 	UpdateMemory(ctx context.Context, id ID, req UpdateRequest) (*Memory, error)
 
 	// DeleteMemory deletes a memory by ID
 	DeleteMemory(ctx context.Context, id ID) error
 
-	// ListUserMemories returns a list of memories for a user with pagination
+	// ListUserMemories returns a list of a user's personal memories with pagination
 	ListUserMemories(ctx context.Context, userID user.ID, limit, offset int) ([]*Memory, error)
 
+	// ListSpaceMemories returns a list of the memories of a shared space with pagination
+	ListSpaceMemories(ctx context.Context, spaceID space.ID, limit, offset int) ([]*Memory, error)
+
 	// SearchMemories searches memories based on various criteria
 	SearchMemories(ctx context.Context, req SearchRequest) ([]*Memory, error)
 
 	// SearchSimilarMemories finds similar memories using embedding vectors
 	SearchSimilarMemories(ctx context.Context, content string, userID user.ID, limit int, threshold float64) ([]*Memory, error)
 
+	// SearchSimilarAcrossSpaces finds similar memories in the user's personal
+	// space and their shared spaces, limited to spaceIDs when set
+	SearchSimilarAcrossSpaces(ctx context.Context, content string, userID user.ID, spaceIDs []space.ID, limit int, threshold float64) ([]*Memory, error)
+
 	// GetMemoryStats returns memory statistics for a user
 	GetMemoryStats(ctx context.Context, userID user.ID) (*Stats, error)
 }
@@ -0,0 +1,65 @@
+package pii
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+)
+
+// Policies decide what happens to personal data found in a memory
+const (
+	// PolicyAllow stores the memory as written
+	PolicyAllow = "allow"
+	// PolicyRedact replaces personal data with its kind, for good
+	PolicyRedact = "redact"
+	// PolicyTokenize replaces personal data with tokens and keeps the
+	// values in a vault, from which the author can restore them
+	PolicyTokenize = "tokenize"
+	// PolicyReject refuses memories containing personal data
+	PolicyReject = "reject"
+)
+
+// ValidPolicy reports whether policy names a known policy
+func ValidPolicy(policy string) bool {
+	switch policy {
+	case PolicyAllow, PolicyRedact, PolicyTokenize, PolicyReject:
+		return true
+	default:
+		return false
+	}
+}
+
+// Token is a value tokenized out of a memory and kept in the vault
+type Token struct {
+	MemoryID  memory.ID
+	UserID    user.ID
+	Token     string
+	Kind      string
+	Value     string
+	CreatedAt time.Time
+}
+
+// Placeholder is the text that stands in for the value in the memory
+func (t *Token) Placeholder() string {
+	return Placeholder(t.Kind, t.Token)
+}
+
+// Placeholder formats the text that stands in for a tokenized value
+func Placeholder(kind, token string) string {
+	return fmt.Sprintf("[PII:%s:%s]", strings.ToUpper(kind), token)
+}
+
+// RedactedPlaceholder is the text that stands in for a redacted value
+func RedactedPlaceholder(kind string) string {
+	return fmt.Sprintf("[REDACTED:%s]", strings.ToUpper(kind))
+}
+
+// Screening is the outcome of applying a policy to the text of a memory
+type Screening struct {
+	Policy string
+	Kinds  []string // kinds of personal data found, in order of appearance
+	Tokens []Token  // values to keep in the vault under PolicyTokenize
+}
@@ -0,0 +1,26 @@
+package pii
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Domain-specific errors for personal data screening
+var (
+	ErrDetected = errors.New("personal data detected")
+)
+
+// DetectedError reports the kinds of personal data that made a memory be
+// rejected; it matches ErrDetected
+type DetectedError struct {
+	Kinds []string
+}
+
+func (e *DetectedError) Error() string {
+	return fmt.Sprintf("personal data detected: %s", strings.Join(e.Kinds, ", "))
+}
+
+func (e *DetectedError) Is(target error) bool {
+	return target == ErrDetected
+}
@@ -0,0 +1,17 @@
+package pii
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/memory"
+)
+
+// Vault keeps the values tokenized out of memories. Only the author of a
+// memory (or a caller bypassing row-level security) can read its tokens.
+type Vault interface {
+	// Replace stores tokens as the complete set of tokens of a memory
+	Replace(ctx context.Context, memoryID memory.ID, tokens []Token) error
+
+	// FindByMemoryID returns the tokens of a memory
+	FindByMemoryID(ctx context.Context, memoryID memory.ID) ([]Token, error)
+}
@@ -0,0 +1,24 @@
+package pii
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+)
+
+// Service screens memories for personal data before they are stored or
+// embedded, following the author's policy
+type Service interface {
+	// Screen applies the policy of userID to texts in place. Under
+	// PolicyReject it returns a DetectedError instead of changing them.
+	Screen(ctx context.Context, userID user.ID, texts ...*string) (*Screening, error)
+
+	// SaveTokens stores the vault tokens of a stored memory: those of
+	// screening plus any earlier ones m still refers to
+	SaveTokens(ctx context.Context, m *memory.Memory, screening *Screening) error
+
+	// Rehydrate puts the tokenized values of m back in place; only its
+	// author and admins may do so
+	Rehydrate(ctx context.Context, m *memory.Memory) error
+}
@@ -0,0 +1,63 @@
+package quota
+
+import (
+	"time"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Resources a quota limits
+const (
+	ResourceMemories         = "memories"
+	ResourceStorageBytes     = "storage_bytes"
+	ResourceEmbeddingTokens  = "embedding_tokens"
+	ResourceCompletionTokens = "completion_tokens"
+)
+
+// Limits holds the maximum a user may consume of each resource. Zero means
+// unlimited; token budgets reset at the start of every calendar month (UTC).
+type Limits struct {
+	MaxMemories             int   `json:"max_memories"`
+	MaxStorageBytes         int64 `json:"max_storage_bytes"`
+	MonthlyEmbeddingTokens  int64 `json:"monthly_embedding_tokens"`
+	MonthlyCompletionTokens int64 `json:"monthly_completion_tokens"`
+}
+
+// Overrides replaces individual plan limits for one user; nil fields inherit
+// the plan's value
+type Overrides struct {
+	MaxStorageBytes         *int64
+	MonthlyEmbeddingTokens  *int64
+	MonthlyCompletionTokens *int64
+}
+
+// Assignment is the plan and overrides stored for a user
+type Assignment struct {
+	UserID    user.ID
+	Plan      string
+	Overrides Overrides
+	UpdatedAt time.Time
+}
+
+// Usage is what a user currently consumes against their limits
+type Usage struct {
+	Memories         int       `json:"memories"`
+	StorageBytes     int64     `json:"storage_bytes"`
+	EmbeddingTokens  int64     `json:"embedding_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	PeriodStart      time.Time `json:"period_start"`
+}
+
+// Status is a user's effective limits together with their usage
+type Status struct {
+	UserID user.ID
+	Plan   string
+	Limits Limits
+	Usage  Usage
+}
+
+// PeriodStart returns the start of the monthly budget period containing t
+func PeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,32 @@
+package quota
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Domain-specific errors for quota operations
+var (
+	ErrNotFound      = errors.New("quota assignment not found")
+	ErrInvalidUserID = errors.New("invalid user ID")
+	ErrUnknownPlan   = errors.New("unknown quota plan")
+	ErrInvalidLimit  = errors.New("quota limits must not be negative")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// ExceededError reports which limit a request would break
+type ExceededError struct {
+	Resource  string `json:"resource"`
+	Limit     int64  `json:"limit"`
+	Used      int64  `json:"used"`
+	Requested int64  `json:"requested"`
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s quota exceeded: %d used of %d, %d requested", e.Resource, e.Used, e.Limit, e.Requested)
+}
+
+// Is makes errors.Is(err, ErrQuotaExceeded) match every ExceededError
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
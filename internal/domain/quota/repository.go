@@ -0,0 +1,19 @@
+package quota
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Repository defines the interface for quota data access operations
+type Repository interface {
+	// FindByUserID retrieves the plan assignment of a user, or ErrNotFound
+	FindByUserID(ctx context.Context, userID user.ID) (*Assignment, error)
+
+	// Save creates or replaces the plan assignment of a user
+	Save(ctx context.Context, assignment *Assignment) error
+
+	// GetStorage returns the number of memories a user holds and their total content size in bytes
+	GetStorage(ctx context.Context, userID user.ID) (memories int, bytes int64, err error)
+}
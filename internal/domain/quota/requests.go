@@ -0,0 +1,15 @@
+package quota
+
+// UpdateRequest adjusts a user's quota. MaxMemories is written to the user's
+// settings; the other limits override the plan for this user. Nil fields are
+// left unchanged.
+type UpdateRequest struct {
+	Plan                    *string
+	MaxMemories             *int
+	MaxStorageBytes         *int64
+	MonthlyEmbeddingTokens  *int64
+	MonthlyCompletionTokens *int64
+
+	// ResetOverrides drops all per-user overrides before applying this request
+	ResetOverrides bool
+}
@@ -0,0 +1,26 @@
+package quota
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Service defines the business operations for quotas
+type Service interface {
+	// GetStatus returns a user's effective limits and current usage
+	GetStatus(ctx context.Context, userID user.ID) (*Status, error)
+
+	// UpdateQuota changes a user's plan or limits
+	UpdateQuota(ctx context.Context, userID user.ID, req UpdateRequest) (*Status, error)
+
+	// CheckMemories returns an ExceededError when storing count more memories
+	// of bytes total content would break the user's memory or storage limit.
+	// It only reads current usage, so the limits are best-effort: concurrent
+	// creates that each pass the check can together exceed them.
+	CheckMemories(ctx context.Context, userID user.ID, count int, bytes int64) error
+
+	// CheckTokens returns an ExceededError when tokens more tokens for
+	// operation ("embedding" or "completion") would break the monthly budget
+	CheckTokens(ctx context.Context, userID user.ID, operation string, tokens int) error
+}
@@ -0,0 +1,94 @@
+package space
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/user"
+)
+
+// ID represents a space identifier
+type ID uuid.UUID
+
+// NewID creates a new space ID
+func NewID() ID {
+	return ID(uuid.New())
+}
+
+// String returns the string representation of the space ID
+func (id ID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// IsZero checks if the ID is zero
+func (id ID) IsZero() bool {
+	return uuid.UUID(id) == uuid.Nil
+}
+
+// Space is a shared workspace whose memories every member can read, for
+// example a team or an organisation. Memories without a space belong to
+// the personal space of their user.
+type Space struct {
+	ID          ID
+	Name        string
+	Description string
+	CreatedBy   user.ID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewSpace creates a new space
+func NewSpace(name, description string, createdBy user.ID) *Space {
+	now := time.Now()
+	return &Space{
+		ID:          NewID(),
+		Name:        name,
+		Description: description,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Member roles, from most to least privileged
+const (
+	// RoleOwner manages the space and its members
+	RoleOwner = "owner"
+	// RoleEditor creates, changes and deletes memories of the space
+	RoleEditor = "editor"
+	// RoleViewer reads and searches memories of the space
+	RoleViewer = "viewer"
+)
+
+// roleRank orders roles by privilege
+var roleRank = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// ValidRole reports whether role is a known member role
+func ValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// Member is a user's membership in a space
+type Member struct {
+	SpaceID   ID
+	UserID    user.ID
+	Role      string
+	CreatedAt time.Time
+}
+
+// Has reports whether the member's role grants at least role
+func (m *Member) Has(role string) bool {
+	return roleRank[m.Role] >= roleRank[role]
+}
+
+// Membership is a space together with the role of a member in it
+type Membership struct {
+	Space *Space
+	Role  string
+}
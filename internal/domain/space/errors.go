@@ -0,0 +1,14 @@
+package space
+
+import "errors"
+
+// Domain-specific errors for spaces
+var (
+	ErrNotFound      = errors.New("space not found")
+	ErrInvalidID     = errors.New("invalid space ID")
+	ErrInvalidName   = errors.New("invalid space name")
+	ErrInvalidRole   = errors.New("invalid member role")
+	ErrNotMember     = errors.New("not a member of the space")
+	ErrAlreadyMember = errors.New("user is already a member of the space")
+	ErrLastOwner     = errors.New("a space must keep at least one owner")
+)
@@ -0,0 +1,43 @@
+package space
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Repository defines the interface for space data access operations
+type Repository interface {
+	// Create stores a new space together with its first owner
+	Create(ctx context.Context, space *Space, owner *Member) error
+
+	// FindByID retrieves a space by its ID
+	FindByID(ctx context.Context, id ID) (*Space, error)
+
+	// ListByUserID retrieves the spaces a user is a member of
+	ListByUserID(ctx context.Context, userID user.ID) ([]*Membership, error)
+
+	// Update updates the name and description of a space
+	Update(ctx context.Context, space *Space) error
+
+	// Delete removes a space with its memberships and memories
+	Delete(ctx context.Context, id ID) error
+
+	// FindMember retrieves the membership of a user, or ErrNotMember
+	FindMember(ctx context.Context, spaceID ID, userID user.ID) (*Member, error)
+
+	// ListMembers retrieves every member of a space
+	ListMembers(ctx context.Context, spaceID ID) ([]*Member, error)
+
+	// AddMember adds a user to a space, or returns ErrAlreadyMember
+	AddMember(ctx context.Context, member *Member) error
+
+	// UpdateMemberRole changes the role of a member
+	UpdateMemberRole(ctx context.Context, spaceID ID, userID user.ID, role string) error
+
+	// RemoveMember removes a user from a space
+	RemoveMember(ctx context.Context, spaceID ID, userID user.ID) error
+
+	// CountOwners returns the number of owners of a space
+	CountOwners(ctx context.Context, spaceID ID) (int, error)
+}
@@ -0,0 +1,21 @@
+package space
+
+import "mem_bank/internal/domain/user"
+
+// CreateRequest represents a request to create a space
+type CreateRequest struct {
+	Name        string
+	Description string
+}
+
+// UpdateRequest represents a request to update a space
+type UpdateRequest struct {
+	Name        *string
+	Description *string
+}
+
+// AddMemberRequest represents a request to add a user to a space
+type AddMemberRequest struct {
+	UserID user.ID
+	Role   string
+}
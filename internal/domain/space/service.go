@@ -0,0 +1,38 @@
+package space
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Service defines the business operations for shared spaces. The caller is
+// taken from the principal in the context.
+type Service interface {
+	// CreateSpace creates a space owned by the caller
+	CreateSpace(ctx context.Context, req CreateRequest) (*Space, error)
+
+	// GetSpace retrieves a space the caller is a member of
+	GetSpace(ctx context.Context, id ID) (*Space, error)
+
+	// ListSpaces returns the spaces the caller is a member of
+	ListSpaces(ctx context.Context) ([]*Membership, error)
+
+	// UpdateSpace renames or describes a space; owners only
+	UpdateSpace(ctx context.Context, id ID, req UpdateRequest) (*Space, error)
+
+	// DeleteSpace deletes a space and its memories; owners only
+	DeleteSpace(ctx context.Context, id ID) error
+
+	// ListMembers returns the members of a space the caller is a member of
+	ListMembers(ctx context.Context, id ID) ([]*Member, error)
+
+	// AddMember adds a user to a space; owners only
+	AddMember(ctx context.Context, id ID, req AddMemberRequest) (*Member, error)
+
+	// UpdateMember changes the role of a member; owners only
+	UpdateMember(ctx context.Context, id ID, userID user.ID, role string) (*Member, error)
+
+	// RemoveMember removes a member; owners may remove anyone, members themselves
+	RemoveMember(ctx context.Context, id ID, userID user.ID) error
+}
@@ -0,0 +1,53 @@
+package usage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Operation names the kind of provider call a record was made for
+const (
+	OperationEmbedding  = "embedding"
+	OperationCompletion = "completion"
+)
+
+// Record is one LLM provider call in the usage ledger
+type Record struct {
+	ID               uuid.UUID
+	UserID           *user.ID // nil for work not attributed to a user
+	Provider         string
+	Model            string
+	Operation        string
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	Estimated        bool // token counts were estimated locally, not reported by the provider
+	CostUSD          float64
+	CreatedAt        time.Time
+}
+
+// Summary aggregates the records for one model and operation
+type Summary struct {
+	Model            string  `json:"model"`
+	Operation        string  `json:"operation"`
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	EstimatedTokens  int     `json:"estimated_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+// Report is a user's usage over a time range
+type Report struct {
+	UserID      user.ID
+	From        time.Time
+	To          time.Time
+	Items       []Summary
+	Requests    int
+	TotalTokens int
+	CostUSD     float64
+}
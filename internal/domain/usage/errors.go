@@ -0,0 +1,9 @@
+package usage
+
+import "errors"
+
+// Domain-specific errors for usage operations
+var (
+	ErrInvalidUserID = errors.New("invalid user ID")
+	ErrInvalidRange  = errors.New("invalid time range")
+)
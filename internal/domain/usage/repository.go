@@ -0,0 +1,17 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Repository defines the interface for usage ledger access
+type Repository interface {
+	// Store appends a record to the ledger
+	Store(ctx context.Context, record *Record) error
+
+	// Summarize aggregates a user's records in [from, to) by model and operation
+	Summarize(ctx context.Context, userID user.ID, from, to time.Time) ([]Summary, error)
+}
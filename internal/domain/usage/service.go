@@ -0,0 +1,14 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"mem_bank/internal/domain/user"
+)
+
+// Service defines the business operations for LLM usage
+type Service interface {
+	// GetUsage reports a user's token usage and cost in [from, to)
+	GetUsage(ctx context.Context, userID user.ID, from, to time.Time) (*Report, error)
+}
@@ -20,6 +20,21 @@ type User struct {
 	UpdatedAt time.Time
 	LastLogin time.Time
 	IsActive  bool
+	// Role is the role carried in the user's access tokens
+	Role string
+}
+
+// Roles a user may hold
+const (
+	// RoleUser is the role of regular users
+	RoleUser = "user"
+	// RoleAdmin may manage every user and their resources
+	RoleAdmin = "admin"
+)
+
+// ValidRole reports whether role may be stored on a user
+func ValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin
 }
 
 // Profile represents user profile information
@@ -39,8 +54,13 @@ type Settings struct {
 	PrivacyLevel         string
 	NotificationSettings map[string]bool
 	EmbeddingModel       string
-	MaxMemories          int
-	AutoSummary          bool
+	// MaxMemories overrides the memory limit of the user's quota plan. Only
+	// admins may change it.
+	MaxMemories int
+	AutoSummary bool
+	// PIIPolicy decides what happens to personal data in new memories, see
+	// the pii package; empty means the server default
+	PIIPolicy string
 }
 
 // NewID creates a new user ID
@@ -69,6 +89,7 @@ func NewUser(username, email string, profile Profile, settings Settings) *User {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		IsActive:  true,
+		Role:      RoleUser,
 	}
 }
 
@@ -96,6 +117,12 @@ func (u *User) Deactivate() {
 	u.UpdatedAt = time.Now()
 }
 
+// SetRole changes the user's role
+func (u *User) SetRole(role string) {
+	u.Role = role
+	u.UpdatedAt = time.Now()
+}
+
 // Activate activates the user
 func (u *User) Activate() {
 	u.IsActive = true
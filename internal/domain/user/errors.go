@@ -12,4 +12,6 @@ var (
 	ErrEmailTaken      = errors.New("email already taken")
 	ErrUsernameTaken   = errors.New("username already taken")
 	ErrInactive        = errors.New("user is inactive")
+	ErrInvalidSettings = errors.New("invalid settings")
+	ErrInvalidRole     = errors.New("invalid role")
 )
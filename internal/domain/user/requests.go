@@ -15,6 +15,7 @@ type UpdateRequest struct {
 	Profile  *Profile
 	Settings *Settings
 	IsActive *bool
+	Role     *string
 }
 
 // LoginRequest represents a user login request
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/user"
+)
+
+// ID represents a webhook subscription identifier
+type ID uuid.UUID
+
+// NewID creates a new subscription ID
+func NewID() ID {
+	return ID(uuid.New())
+}
+
+// String returns the string representation of the subscription ID
+func (id ID) String() string {
+	return uuid.UUID(id).String()
+}
+
+// IsZero checks if the ID is zero
+func (id ID) IsZero() bool {
+	return uuid.UUID(id) == uuid.Nil
+}
+
+// Subscription is an endpoint a user registered to receive the events of
+// their memories, and of the spaces they are a member of, as signed HTTP
+// POST requests. The secret signs each request; it is only shown when the
+// subscription is created.
+type Subscription struct {
+	ID          ID
+	UserID      user.ID
+	URL         string
+	Secret      string
+	Description string
+	// EventTypes limits the events delivered; empty means every type
+	EventTypes []string
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Wants reports whether the subscription receives events of eventType
+func (s *Subscription) Wants(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one attempt to deliver an event to a subscription
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID ID
+	UserID         user.ID
+	EventID        event.ID
+	EventType      string
+	Attempt        int
+	// StatusCode is zero when no response was received
+	StatusCode int
+	Succeeded  bool
+	Error      string
+	Duration   time.Duration
+	CreatedAt  time.Time
+}
@@ -0,0 +1,12 @@
+package webhook
+
+import "errors"
+
+// Domain-specific errors for webhooks
+var (
+	ErrNotFound             = errors.New("webhook subscription not found")
+	ErrInvalidURL           = errors.New("invalid webhook URL")
+	ErrInvalidEventType     = errors.New("invalid webhook event type")
+	ErrInvalidDescription   = errors.New("invalid webhook description")
+	ErrTooManySubscriptions = errors.New("too many webhook subscriptions")
+)
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/user"
+)
+
+// Repository defines the interface for webhook data access operations
+type Repository interface {
+	// Store saves a new subscription
+	Store(ctx context.Context, s *Subscription) error
+
+	// FindByID retrieves a subscription by ID
+	FindByID(ctx context.Context, id ID) (*Subscription, error)
+
+	// ListByUserID returns the subscriptions of a user, newest first
+	ListByUserID(ctx context.Context, userID user.ID) ([]*Subscription, error)
+
+	// CountByUserID counts the subscriptions of a user
+	CountByUserID(ctx context.Context, userID user.ID) (int, error)
+
+	// Update saves changes to a subscription
+	Update(ctx context.Context, s *Subscription) error
+
+	// Delete removes a subscription and its delivery log
+	Delete(ctx context.Context, id ID) error
+
+	// FindSubscribers returns the active subscriptions that receive e: those
+	// of the author of a personal memory, or of the members of the space of
+	// a shared one, which want its type and existed when it happened
+	FindSubscribers(ctx context.Context, e *event.Event) ([]*Subscription, error)
+
+	// StoreDelivery records a delivery attempt
+	StoreDelivery(ctx context.Context, d *Delivery) error
+
+	// ListDeliveries returns the delivery attempts of a subscription,
+	// newest first
+	ListDeliveries(ctx context.Context, id ID, limit, offset int) ([]*Delivery, error)
+}
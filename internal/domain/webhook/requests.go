@@ -0,0 +1,18 @@
+package webhook
+
+// CreateRequest represents a request to subscribe to events
+type CreateRequest struct {
+	URL         string
+	Description string
+	// EventTypes is optional; empty subscribes to every type
+	EventTypes []string
+}
+
+// UpdateRequest represents a request to change a subscription; nil fields
+// are left unchanged
+type UpdateRequest struct {
+	URL         *string
+	Description *string
+	EventTypes  *[]string
+	Active      *bool
+}
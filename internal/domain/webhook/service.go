@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/user"
+)
+
+// Service defines the business operations for webhooks
+type Service interface {
+	// Create subscribes a user to events; the returned subscription carries
+	// its signing secret, which is not returned again
+	Create(ctx context.Context, userID user.ID, req CreateRequest) (*Subscription, error)
+
+	// List returns the subscriptions of a user
+	List(ctx context.Context, userID user.ID) ([]*Subscription, error)
+
+	// Get returns a subscription of a user
+	Get(ctx context.Context, userID user.ID, id ID) (*Subscription, error)
+
+	// Update changes a subscription of a user
+	Update(ctx context.Context, userID user.ID, id ID, req UpdateRequest) (*Subscription, error)
+
+	// Delete removes a subscription of a user
+	Delete(ctx context.Context, userID user.ID, id ID) error
+
+	// ListDeliveries returns the delivery log of a subscription of a user
+	ListDeliveries(ctx context.Context, userID user.ID, id ID, limit, offset int) ([]*Delivery, error)
+
+	// Deliver sends an event to a subscription and records the attempt; it
+	// runs in the webhook delivery job, which retries when it fails
+	Deliver(ctx context.Context, id ID, eventID event.ID, attempt int) error
+}
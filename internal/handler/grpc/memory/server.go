@@ -0,0 +1,456 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	membankv1 "mem_bank/api/proto/membank/v1"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// Defaults matching the HTTP API
+const (
+	defaultListLimit    = 20
+	defaultSimilarLimit = 10
+	defaultThreshold    = 0.8
+	// streamPageSize is how many memories StreamMemories reads at a time
+	streamPageSize = 100
+)
+
+// Server implements the gRPC MemoryService over memory.Service
+type Server struct {
+	membankv1.UnimplementedMemoryServiceServer
+	service memory.Service
+	logger  logger.Logger
+}
+
+// NewServer creates a new memory gRPC server
+func NewServer(service memory.Service, logger logger.Logger) *Server {
+	return &Server{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (s *Server) CreateMemory(ctx context.Context, req *membankv1.CreateMemoryRequest) (*membankv1.Memory, error) {
+	createReq, err := toCreateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.service.CreateMemory(ctx, createReq)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return s.toProto(m)
+}
+
+func (s *Server) BatchCreateMemories(ctx context.Context, req *membankv1.BatchCreateMemoriesRequest) (*membankv1.BatchCreateMemoriesResponse, error) {
+	createReqs := make([]memory.CreateRequest, len(req.GetMemories()))
+	for i, item := range req.GetMemories() {
+		createReq, err := toCreateRequest(item)
+		if err != nil {
+			return nil, err
+		}
+		createReqs[i] = createReq
+	}
+
+	memories, err := s.service.BatchCreateMemories(ctx, createReqs)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	resp := &membankv1.BatchCreateMemoriesResponse{}
+	if resp.Memories, err = s.toProtos(memories); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) GetMemory(ctx context.Context, req *membankv1.GetMemoryRequest) (*membankv1.Memory, error) {
+	id, err := parseID(req.GetId(), "memory ID")
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokenized personal data is restored only when asked for
+	get := s.service.GetMemory
+	if req.GetRehydrate() {
+		get = s.service.RehydrateMemory
+	}
+
+	m, err := get(ctx, memory.ID(id))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return s.toProto(m)
+}
+
+func (s *Server) UpdateMemory(ctx context.Context, req *membankv1.UpdateMemoryRequest) (*membankv1.Memory, error) {
+	id, err := parseID(req.GetId(), "memory ID")
+	if err != nil {
+		return nil, err
+	}
+
+	updateReq := memory.UpdateRequest{
+		Content:    req.Content,
+		Summary:    req.Summary,
+		MemoryType: req.MemoryType,
+	}
+	if req.Importance != nil {
+		importance := int(req.GetImportance())
+		updateReq.Importance = &importance
+	}
+	if req.Tags != nil {
+		updateReq.Tags = append([]string{}, req.GetTags().GetValues()...)
+	}
+	if req.Metadata != nil {
+		updateReq.Metadata = req.GetMetadata().AsMap()
+	}
+
+	m, err := s.service.UpdateMemory(ctx, memory.ID(id), updateReq)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return s.toProto(m)
+}
+
+func (s *Server) DeleteMemory(ctx context.Context, req *membankv1.DeleteMemoryRequest) (*emptypb.Empty, error) {
+	id, err := parseID(req.GetId(), "memory ID")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteMemory(ctx, memory.ID(id)); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListMemories(ctx context.Context, req *membankv1.ListMemoriesRequest) (*membankv1.ListMemoriesResponse, error) {
+	list, err := s.lister(req.GetUserId(), req.GetSpaceId())
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := int(req.GetLimit()), int(req.GetOffset())
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset = max(offset, 0)
+
+	memories, err := list(ctx, limit, offset)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	resp := &membankv1.ListMemoriesResponse{Limit: int32(limit), Offset: int32(offset)}
+	if resp.Memories, err = s.toProtos(memories); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// StreamMemories pages through the memories and sends them one at a time,
+// so clients need not page themselves
+func (s *Server) StreamMemories(req *membankv1.StreamMemoriesRequest, stream membankv1.MemoryService_StreamMemoriesServer) error {
+	list, err := s.lister(req.GetUserId(), req.GetSpaceId())
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	for offset := 0; ; offset += streamPageSize {
+		memories, err := list(ctx, streamPageSize, offset)
+		if err != nil {
+			return s.toStatus(err)
+		}
+		for _, m := range memories {
+			msg, err := s.toProto(m)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+		if len(memories) < streamPageSize {
+			return nil
+		}
+	}
+}
+
+func (s *Server) SearchMemories(ctx context.Context, req *membankv1.SearchMemoriesRequest) (*membankv1.SearchMemoriesResponse, error) {
+	userID, err := parseID(req.GetUserId(), "user ID")
+	if err != nil {
+		return nil, err
+	}
+	spaceIDs, err := parseSpaceIDs(req.GetSpaceIds())
+	if err != nil {
+		return nil, err
+	}
+
+	searchReq := memory.SearchRequest{
+		UserID:        user.ID(userID),
+		Query:         req.GetQuery(),
+		Tags:          req.GetTags(),
+		MemoryType:    req.GetMemoryType(),
+		Limit:         int(req.GetLimit()),
+		Offset:        max(int(req.GetOffset()), 0),
+		IncludeShared: req.GetIncludeShared(),
+		SpaceIDs:      spaceIDs,
+	}
+	if searchReq.Limit <= 0 {
+		searchReq.Limit = defaultListLimit
+	}
+
+	memories, err := s.service.SearchMemories(ctx, searchReq)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	resp := &membankv1.SearchMemoriesResponse{Limit: int32(searchReq.Limit), Offset: int32(searchReq.Offset)}
+	if resp.Memories, err = s.toProtos(memories); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) SearchSimilarMemories(ctx context.Context, req *membankv1.SearchSimilarMemoriesRequest) (*membankv1.SearchSimilarMemoriesResponse, error) {
+	userID, err := parseID(req.GetUserId(), "user ID")
+	if err != nil {
+		return nil, err
+	}
+	if req.GetContent() == "" {
+		return nil, status.Error(codes.InvalidArgument, "content is required")
+	}
+	spaceIDs, err := parseSpaceIDs(req.GetSpaceIds())
+	if err != nil {
+		return nil, err
+	}
+
+	limit, threshold := int(req.GetLimit()), req.GetThreshold()
+	if limit <= 0 {
+		limit = defaultSimilarLimit
+	}
+	if threshold <= 0 {
+		threshold = defaultThreshold
+	}
+
+	var memories []*memory.Memory
+	if req.GetIncludeShared() || len(spaceIDs) > 0 {
+		memories, err = s.service.SearchSimilarAcrossSpaces(ctx, req.GetContent(), user.ID(userID), spaceIDs, limit, threshold)
+	} else {
+		memories, err = s.service.SearchSimilarMemories(ctx, req.GetContent(), user.ID(userID), limit, threshold)
+	}
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	resp := &membankv1.SearchSimilarMemoriesResponse{}
+	if resp.Memories, err = s.toProtos(memories); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) GetMemoryStats(ctx context.Context, req *membankv1.GetMemoryStatsRequest) (*membankv1.MemoryStats, error) {
+	userID, err := parseID(req.GetUserId(), "user ID")
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.service.GetMemoryStats(ctx, user.ID(userID))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	resp := &membankv1.MemoryStats{
+		TotalMemories:     int32(stats.TotalMemories),
+		MemoryTypes:       make(map[string]int32, len(stats.MemoryTypes)),
+		RecentMemories:    int32(stats.RecentMemories),
+		AverageImportance: stats.AverageImportance,
+	}
+	for memoryType, count := range stats.MemoryTypes {
+		resp.MemoryTypes[memoryType] = int32(count)
+	}
+	return resp, nil
+}
+
+// lister returns a page reader of the memories of spaceID when set, or
+// of the personal memories of userID
+func (s *Server) lister(userID, spaceID string) (func(ctx context.Context, limit, offset int) ([]*memory.Memory, error), error) {
+	if spaceID != "" {
+		id, err := parseID(spaceID, "space ID")
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, limit, offset int) ([]*memory.Memory, error) {
+			return s.service.ListSpaceMemories(ctx, space.ID(id), limit, offset)
+		}, nil
+	}
+
+	id, err := parseID(userID, "user ID")
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, limit, offset int) ([]*memory.Memory, error) {
+		return s.service.ListUserMemories(ctx, user.ID(id), limit, offset)
+	}, nil
+}
+
+// toStatus maps service errors to gRPC status errors as the HTTP handler
+// maps them to status codes
+func (s *Server) toStatus(err error) error {
+	var serviceErr *memory.ServiceError
+	if errors.As(err, &serviceErr) {
+		return status.Error(codeFromErrorCode(serviceErr.Code), serviceErr.Message)
+	}
+
+	var validationErr *memory.ValidationError
+	if errors.As(err, &validationErr) {
+		return status.Errorf(codes.InvalidArgument, "%s: %s", validationErr.Field, validationErr.Message)
+	}
+
+	switch {
+	case errors.Is(err, memory.ErrNotFound):
+		return status.Error(codes.NotFound, "memory not found")
+	case errors.Is(err, memory.ErrInvalidID), errors.Is(err, memory.ErrInvalidUserID),
+		errors.Is(err, memory.ErrInvalidContent), errors.Is(err, memory.ErrInvalidImportance),
+		errors.Is(err, memory.ErrInvalidMemoryType):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	case errors.Is(err, memory.ErrEmbeddingFailed):
+		return status.Error(codes.Internal, "failed to process memory content")
+	default:
+		s.logger.WithError(err).Error("Unhandled service error")
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+func codeFromErrorCode(code string) codes.Code {
+	switch code {
+	case memory.ErrCodeNotFound:
+		return codes.NotFound
+	case memory.ErrCodeInvalidInput:
+		return codes.InvalidArgument
+	case memory.ErrCodePermissionDenied:
+		return codes.PermissionDenied
+	case memory.ErrCodeExternalService:
+		return codes.Unavailable
+	case memory.ErrCodeQuotaExceeded:
+		return codes.ResourceExhausted
+	case memory.ErrCodePIIDetected:
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+func (s *Server) toProtos(memories []*memory.Memory) ([]*membankv1.Memory, error) {
+	msgs := make([]*membankv1.Memory, len(memories))
+	for i, m := range memories {
+		msg, err := s.toProto(m)
+		if err != nil {
+			return nil, err
+		}
+		msgs[i] = msg
+	}
+	return msgs, nil
+}
+
+func (s *Server) toProto(m *memory.Memory) (*membankv1.Memory, error) {
+	msg := &membankv1.Memory{
+		Id:           m.ID.String(),
+		UserId:       m.UserID.String(),
+		Content:      m.Content,
+		Summary:      m.Summary,
+		Importance:   int32(m.Importance),
+		MemoryType:   m.MemoryType,
+		Tags:         m.Tags,
+		CreatedAt:    timestamp(m.CreatedAt),
+		UpdatedAt:    timestamp(m.UpdatedAt),
+		LastAccessed: timestamp(m.LastAccessed),
+		AccessCount:  int32(m.AccessCount),
+	}
+	if m.IsShared() {
+		msg.SpaceId = m.SpaceID.String()
+	}
+	if m.Metadata != nil {
+		metadata, err := structpb.NewStruct(m.Metadata)
+		if err != nil {
+			s.logger.WithError(err).WithField("memory_id", m.ID.String()).Error("Failed to encode memory metadata")
+			return nil, status.Error(codes.Internal, "failed to encode memory metadata")
+		}
+		msg.Metadata = metadata
+	}
+	return msg, nil
+}
+
+func toCreateRequest(req *membankv1.CreateMemoryRequest) (memory.CreateRequest, error) {
+	userID, err := parseID(req.GetUserId(), "user ID")
+	if err != nil {
+		return memory.CreateRequest{}, err
+	}
+
+	createReq := memory.CreateRequest{
+		UserID:     user.ID(userID),
+		Content:    req.GetContent(),
+		Summary:    req.GetSummary(),
+		Importance: int(req.GetImportance()),
+		MemoryType: req.GetMemoryType(),
+		Tags:       req.GetTags(),
+	}
+	if req.Metadata != nil {
+		createReq.Metadata = req.GetMetadata().AsMap()
+	}
+	if req.GetSpaceId() != "" {
+		spaceID, err := parseID(req.GetSpaceId(), "space ID")
+		if err != nil {
+			return memory.CreateRequest{}, err
+		}
+		createReq.SpaceID = space.ID(spaceID)
+	}
+	return createReq, nil
+}
+
+func parseID(value, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s", name)
+	}
+	return id, nil
+}
+
+func parseSpaceIDs(values []string) ([]space.ID, error) {
+	ids := make([]space.ID, 0, len(values))
+	for _, value := range values {
+		id, err := parseID(value, "space ID")
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, space.ID(id))
+	}
+	return ids, nil
+}
+
+// timestamp converts t, leaving zero times unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
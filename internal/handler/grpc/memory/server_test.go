@@ -0,0 +1,247 @@
+package memory
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	membankv1 "mem_bank/api/proto/membank/v1"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// Mock memory service
+type mockMemoryService struct {
+	mock.Mock
+}
+
+func (m *mockMemoryService) memory(args mock.Arguments) (*memory.Memory, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*memory.Memory), args.Error(1)
+}
+
+func (m *mockMemoryService) memories(args mock.Arguments) ([]*memory.Memory, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*memory.Memory), args.Error(1)
+}
+
+func (m *mockMemoryService) CreateMemory(ctx context.Context, req memory.CreateRequest) (*memory.Memory, error) {
+	return m.memory(m.Called(ctx, req))
+}
+
+func (m *mockMemoryService) BatchCreateMemories(ctx context.Context, reqs []memory.CreateRequest) ([]*memory.Memory, error) {
+	return m.memories(m.Called(ctx, reqs))
+}
+
+func (m *mockMemoryService) GetMemory(ctx context.Context, id memory.ID) (*memory.Memory, error) {
+	return m.memory(m.Called(ctx, id))
+}
+
+func (m *mockMemoryService) RehydrateMemory(ctx context.Context, id memory.ID) (*memory.Memory, error) {
+	return m.memory(m.Called(ctx, id))
+}
+
+func (m *mockMemoryService) UpdateMemory(ctx context.Context, id memory.ID, req memory.UpdateRequest) (*memory.Memory, error) {
+	return m.memory(m.Called(ctx, id, req))
+}
+
+func (m *mockMemoryService) DeleteMemory(ctx context.Context, id memory.ID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockMemoryService) ListUserMemories(ctx context.Context, userID user.ID, limit, offset int) ([]*memory.Memory, error) {
+	return m.memories(m.Called(ctx, userID, limit, offset))
+}
+
+func (m *mockMemoryService) ListSpaceMemories(ctx context.Context, spaceID space.ID, limit, offset int) ([]*memory.Memory, error) {
+	return m.memories(m.Called(ctx, spaceID, limit, offset))
+}
+
+func (m *mockMemoryService) SearchMemories(ctx context.Context, req memory.SearchRequest) ([]*memory.Memory, error) {
+	return m.memories(m.Called(ctx, req))
+}
+
+func (m *mockMemoryService) SearchSimilarMemories(ctx context.Context, content string, userID user.ID, limit int, threshold float64) ([]*memory.Memory, error) {
+	return m.memories(m.Called(ctx, content, userID, limit, threshold))
+}
+
+func (m *mockMemoryService) SearchSimilarAcrossSpaces(ctx context.Context, content string, userID user.ID, spaceIDs []space.ID, limit int, threshold float64) ([]*memory.Memory, error) {
+	return m.memories(m.Called(ctx, content, userID, spaceIDs, limit, threshold))
+}
+
+func (m *mockMemoryService) GetMemoryStats(ctx context.Context, userID user.ID) (*memory.Stats, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*memory.Stats), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+// newClient serves the memory server over an in-memory connection, with
+// every call made as caller
+func newClient(t *testing.T, service memory.Service, log logger.Logger, caller auth.Principal) membankv1.MemoryServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(auth.WithPrincipal(ctx, caller), req)
+	}))
+	membankv1.RegisterMemoryServiceServer(server, NewServer(service, log))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return membankv1.NewMemoryServiceClient(conn)
+}
+
+func TestServer_GetMemory(t *testing.T) {
+	owner := user.ID(uuid.New())
+	other := user.ID(uuid.New())
+	m := memory.NewMemory(owner, "likes tea", "", 5, "preference")
+
+	tests := []struct {
+		name       string
+		caller     user.ID
+		id         string
+		setupMocks func(*mockMemoryService, *mockLogger)
+		wantCode   codes.Code
+	}{
+		{
+			name:   "owner reads the memory",
+			caller: owner,
+			id:     m.ID.String(),
+			setupMocks: func(s *mockMemoryService, l *mockLogger) {
+				s.On("GetMemory", mock.Anything, m.ID).Return(m, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:   "other user is denied",
+			caller: other,
+			id:     m.ID.String(),
+			setupMocks: func(s *mockMemoryService, l *mockLogger) {
+				s.On("GetMemory", mock.Anything, m.ID).Return(nil, auth.ErrPermissionDenied)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "missing memory",
+			caller: owner,
+			id:     m.ID.String(),
+			setupMocks: func(s *mockMemoryService, l *mockLogger) {
+				s.On("GetMemory", mock.Anything, m.ID).Return(nil, memory.ErrNotFound)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:       "malformed ID",
+			caller:     owner,
+			id:         "not-a-uuid",
+			setupMocks: func(s *mockMemoryService, l *mockLogger) {},
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:   "unexpected error is logged and hidden",
+			caller: owner,
+			id:     m.ID.String(),
+			setupMocks: func(s *mockMemoryService, l *mockLogger) {
+				s.On("GetMemory", mock.Anything, m.ID).Return(nil, assert.AnError)
+				l.On("Error", mock.Anything).Once()
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockMemoryService{}
+			log := &mockLogger{}
+			tt.setupMocks(service, log)
+
+			client := newClient(t, service, log, auth.Principal{UserID: tt.caller, Role: auth.RoleUser})
+			resp, err := client.GetMemory(context.Background(), &membankv1.GetMemoryRequest{Id: tt.id})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, m.ID.String(), resp.GetId())
+				assert.Equal(t, owner.String(), resp.GetUserId())
+			}
+
+			service.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_StatusMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{"validation error", memory.NewValidationError("content", "is required"), codes.InvalidArgument},
+		{"invalid importance", memory.ErrInvalidImportance, codes.InvalidArgument},
+		{"quota exceeded", memory.NewServiceError(memory.ErrCodeQuotaExceeded, "quota exceeded", nil), codes.ResourceExhausted},
+		{"personal data rejected", memory.NewServiceError(memory.ErrCodePIIDetected, "personal data", nil), codes.FailedPrecondition},
+		{"embedding unavailable", memory.NewServiceError(memory.ErrCodeExternalService, "embedding down", nil), codes.Unavailable},
+		{"permission denied", auth.ErrPermissionDenied, codes.PermissionDenied},
+	}
+
+	userID := user.ID(uuid.New())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockMemoryService{}
+			service.On("CreateMemory", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			client := newClient(t, service, &mockLogger{}, auth.Principal{UserID: userID, Role: auth.RoleUser})
+			_, err := client.CreateMemory(context.Background(), &membankv1.CreateMemoryRequest{
+				UserId:  userID.String(),
+				Content: "likes tea",
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			service.AssertExpectations(t)
+		})
+	}
+}
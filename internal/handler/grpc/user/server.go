@@ -0,0 +1,312 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	membankv1 "mem_bank/api/proto/membank/v1"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// defaultListLimit matches the HTTP API
+const defaultListLimit = 20
+
+// Server implements the gRPC UserService over user.Service. Like the HTTP
+// handler, it checks the caller may reach the user before acting.
+type Server struct {
+	membankv1.UnimplementedUserServiceServer
+	service user.Service
+	logger  logger.Logger
+}
+
+// NewServer creates a new user gRPC server
+func NewServer(service user.Service, logger logger.Logger) *Server {
+	return &Server{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateUser creates an account for someone else and is limited to admins;
+// users sign themselves up over HTTP at /auth/register
+func (s *Server) CreateUser(ctx context.Context, req *membankv1.CreateUserRequest) (*membankv1.User, error) {
+	if !isPrivileged(ctx) {
+		return nil, s.toStatus(auth.ErrPermissionDenied)
+	}
+	if req.GetUsername() == "" || req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "username and email are required")
+	}
+
+	u, err := s.service.CreateUser(ctx, user.CreateRequest{
+		Username: req.GetUsername(),
+		Email:    req.GetEmail(),
+		Profile:  toProfile(req.GetProfile()),
+		Settings: toSettings(req.GetSettings()),
+	})
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return s.toProto(u)
+}
+
+func (s *Server) GetUser(ctx context.Context, req *membankv1.GetUserRequest) (*membankv1.User, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.Authorize(ctx, user.ID(id)); err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	u, err := s.service.GetUser(ctx, user.ID(id))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return s.toProto(u)
+}
+
+func (s *Server) GetUserByUsername(ctx context.Context, req *membankv1.GetUserByUsernameRequest) (*membankv1.User, error) {
+	if req.GetUsername() == "" {
+		return nil, status.Error(codes.InvalidArgument, "username is required")
+	}
+
+	u, err := s.service.GetUserByUsername(ctx, req.GetUsername())
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	// Other users are reported as missing, so lookups do not reveal who
+	// is registered
+	if err := auth.Authorize(ctx, u.ID); err != nil {
+		return nil, s.toStatus(user.ErrNotFound)
+	}
+	return s.toProto(u)
+}
+
+func (s *Server) GetUserByEmail(ctx context.Context, req *membankv1.GetUserByEmailRequest) (*membankv1.User, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	u, err := s.service.GetUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	// Other users are reported as missing, so lookups do not reveal who
+	// is registered
+	if err := auth.Authorize(ctx, u.ID); err != nil {
+		return nil, s.toStatus(user.ErrNotFound)
+	}
+	return s.toProto(u)
+}
+
+func (s *Server) UpdateUser(ctx context.Context, req *membankv1.UpdateUserRequest) (*membankv1.User, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.Authorize(ctx, user.ID(id)); err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	// Only admins may activate or deactivate accounts
+	if req.IsActive != nil && !isPrivileged(ctx) {
+		return nil, s.toStatus(auth.ErrPermissionDenied)
+	}
+
+	updateReq := user.UpdateRequest{
+		Username: req.Username,
+		Email:    req.Email,
+		IsActive: req.IsActive,
+	}
+	if req.Profile != nil {
+		profile := toProfile(req.GetProfile())
+		updateReq.Profile = &profile
+	}
+	if req.Settings != nil {
+		settings := toSettings(req.GetSettings())
+		updateReq.Settings = &settings
+	}
+
+	u, err := s.service.UpdateUser(ctx, user.ID(id), updateReq)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return s.toProto(u)
+}
+
+func (s *Server) DeleteUser(ctx context.Context, req *membankv1.DeleteUserRequest) (*emptypb.Empty, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.Authorize(ctx, user.ID(id)); err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	if err := s.service.DeleteUser(ctx, user.ID(id)); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListUsers(ctx context.Context, req *membankv1.ListUsersRequest) (*membankv1.ListUsersResponse, error) {
+	if !isPrivileged(ctx) {
+		return nil, s.toStatus(auth.ErrPermissionDenied)
+	}
+
+	limit, offset := int(req.GetLimit()), max(int(req.GetOffset()), 0)
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	users, err := s.service.ListUsers(ctx, limit, offset)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	resp := &membankv1.ListUsersResponse{
+		Users:  make([]*membankv1.User, len(users)),
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	}
+	for i, u := range users {
+		if resp.Users[i], err = s.toProto(u); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (s *Server) GetUserStats(ctx context.Context, req *membankv1.GetUserStatsRequest) (*membankv1.UserStats, error) {
+	if !isPrivileged(ctx) {
+		return nil, s.toStatus(auth.ErrPermissionDenied)
+	}
+
+	stats, err := s.service.GetUserStats(ctx)
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	return &membankv1.UserStats{
+		TotalUsers:    int32(stats.TotalUsers),
+		ActiveUsers:   int32(stats.ActiveUsers),
+		NewUsers:      int32(stats.NewUsers),
+		TotalMemories: int32(stats.TotalMemories),
+	}, nil
+}
+
+// toStatus maps service errors to gRPC status errors as the HTTP handler
+// maps them to status codes
+func (s *Server) toStatus(err error) error {
+	switch {
+	case errors.Is(err, user.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, user.ErrAlreadyExists), errors.Is(err, user.ErrEmailTaken), errors.Is(err, user.ErrUsernameTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, user.ErrInvalidEmail), errors.Is(err, user.ErrInvalidUsername),
+		errors.Is(err, user.ErrInvalidID), errors.Is(err, user.ErrInvalidSettings),
+		errors.Is(err, user.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, user.ErrInactive), errors.Is(err, auth.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		s.logger.WithError(err).Error("Unhandled user service error")
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+func (s *Server) toProto(u *user.User) (*membankv1.User, error) {
+	msg := &membankv1.User{
+		Id:       u.ID.String(),
+		Username: u.Username,
+		Email:    u.Email,
+		Profile: &membankv1.Profile{
+			FirstName: u.Profile.FirstName,
+			LastName:  u.Profile.LastName,
+			Avatar:    u.Profile.Avatar,
+			Bio:       u.Profile.Bio,
+		},
+		Settings: &membankv1.Settings{
+			Language:             u.Settings.Language,
+			Timezone:             u.Settings.Timezone,
+			MemoryRetention:      int32(u.Settings.MemoryRetention),
+			PrivacyLevel:         u.Settings.PrivacyLevel,
+			NotificationSettings: u.Settings.NotificationSettings,
+			EmbeddingModel:       u.Settings.EmbeddingModel,
+			MaxMemories:          int32(u.Settings.MaxMemories),
+			AutoSummary:          u.Settings.AutoSummary,
+			PiiPolicy:            u.Settings.PIIPolicy,
+		},
+		CreatedAt: timestamp(u.CreatedAt),
+		UpdatedAt: timestamp(u.UpdatedAt),
+		LastLogin: timestamp(u.LastLogin),
+		IsActive:  u.IsActive,
+	}
+	if u.Profile.Preferences != nil {
+		preferences, err := structpb.NewStruct(u.Profile.Preferences)
+		if err != nil {
+			s.logger.WithError(err).WithField("user_id", u.ID.String()).Error("Failed to encode user preferences")
+			return nil, status.Error(codes.Internal, "failed to encode user preferences")
+		}
+		msg.Profile.Preferences = preferences
+	}
+	return msg, nil
+}
+
+func toProfile(msg *membankv1.Profile) user.Profile {
+	profile := user.Profile{
+		FirstName: msg.GetFirstName(),
+		LastName:  msg.GetLastName(),
+		Avatar:    msg.GetAvatar(),
+		Bio:       msg.GetBio(),
+	}
+	if msg.GetPreferences() != nil {
+		profile.Preferences = msg.GetPreferences().AsMap()
+	}
+	return profile
+}
+
+func toSettings(msg *membankv1.Settings) user.Settings {
+	return user.Settings{
+		Language:             msg.GetLanguage(),
+		Timezone:             msg.GetTimezone(),
+		MemoryRetention:      int(msg.GetMemoryRetention()),
+		PrivacyLevel:         msg.GetPrivacyLevel(),
+		NotificationSettings: msg.GetNotificationSettings(),
+		EmbeddingModel:       msg.GetEmbeddingModel(),
+		MaxMemories:          int(msg.GetMaxMemories()),
+		AutoSummary:          msg.GetAutoSummary(),
+		PIIPolicy:            msg.GetPiiPolicy(),
+	}
+}
+
+func isPrivileged(ctx context.Context) bool {
+	p, _ := auth.PrincipalFromContext(ctx)
+	return p.IsPrivileged()
+}
+
+func parseID(value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+	return id, nil
+}
+
+// timestamp converts t, leaving zero times unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
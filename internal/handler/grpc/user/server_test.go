@@ -0,0 +1,277 @@
+package user
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	membankv1 "mem_bank/api/proto/membank/v1"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// Mock user service
+type mockUserService struct {
+	mock.Mock
+}
+
+func (m *mockUserService) user(args mock.Arguments) (*user.User, error) {
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserService) CreateUser(ctx context.Context, req user.CreateRequest) (*user.User, error) {
+	return m.user(m.Called(ctx, req))
+}
+
+func (m *mockUserService) GetUser(ctx context.Context, id user.ID) (*user.User, error) {
+	return m.user(m.Called(ctx, id))
+}
+
+func (m *mockUserService) GetUserByUsername(ctx context.Context, username string) (*user.User, error) {
+	return m.user(m.Called(ctx, username))
+}
+
+func (m *mockUserService) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	return m.user(m.Called(ctx, email))
+}
+
+func (m *mockUserService) UpdateUser(ctx context.Context, id user.ID, req user.UpdateRequest) (*user.User, error) {
+	return m.user(m.Called(ctx, id, req))
+}
+
+func (m *mockUserService) DeleteUser(ctx context.Context, id user.ID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockUserService) ListUsers(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+func (m *mockUserService) UpdateLastLogin(ctx context.Context, id user.ID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockUserService) GetUserStats(ctx context.Context) (*user.Stats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.Stats), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+// newClient serves the user server over an in-memory connection, with
+// every call made as caller
+func newClient(t *testing.T, service user.Service, caller auth.Principal) membankv1.UserServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(auth.WithPrincipal(ctx, caller), req)
+	}))
+	membankv1.RegisterUserServiceServer(server, NewServer(service, &mockLogger{}))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return membankv1.NewUserServiceClient(conn)
+}
+
+func TestServer_Ownership(t *testing.T) {
+	owner := user.NewUser("alice", "alice@example.com", user.Profile{}, user.Settings{})
+	other := auth.Principal{UserID: user.ID(uuid.New()), Role: auth.RoleUser}
+	self := auth.Principal{UserID: owner.ID, Role: auth.RoleUser}
+	admin := auth.Principal{UserID: user.ID(uuid.New()), Role: auth.RoleAdmin}
+
+	tests := []struct {
+		name       string
+		caller     auth.Principal
+		call       func(membankv1.UserServiceClient) error
+		setupMocks func(*mockUserService)
+		wantCode   codes.Code
+	}{
+		{
+			name:   "owner gets themselves",
+			caller: self,
+			call: func(c membankv1.UserServiceClient) error {
+				_, err := c.GetUser(context.Background(), &membankv1.GetUserRequest{Id: owner.ID.String()})
+				return err
+			},
+			setupMocks: func(s *mockUserService) {
+				s.On("GetUser", mock.Anything, owner.ID).Return(owner, nil)
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:   "other user cannot get",
+			caller: other,
+			call: func(c membankv1.UserServiceClient) error {
+				_, err := c.GetUser(context.Background(), &membankv1.GetUserRequest{Id: owner.ID.String()})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "other user cannot get by username",
+			caller: other,
+			call: func(c membankv1.UserServiceClient) error {
+				_, err := c.GetUserByUsername(context.Background(), &membankv1.GetUserByUsernameRequest{Username: "alice"})
+				return err
+			},
+			setupMocks: func(s *mockUserService) {
+				s.On("GetUserByUsername", mock.Anything, "alice").Return(owner, nil)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:   "other user cannot get by email",
+			caller: other,
+			call: func(c membankv1.UserServiceClient) error {
+				_, err := c.GetUserByEmail(context.Background(), &membankv1.GetUserByEmailRequest{Email: "alice@example.com"})
+				return err
+			},
+			setupMocks: func(s *mockUserService) {
+				s.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(owner, nil)
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:   "owner cannot deactivate themselves",
+			caller: self,
+			call: func(c membankv1.UserServiceClient) error {
+				_, err := c.UpdateUser(context.Background(), &membankv1.UpdateUserRequest{Id: owner.ID.String(), IsActive: proto.Bool(false)})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "other user cannot delete",
+			caller: other,
+			call: func(c membankv1.UserServiceClient) error {
+				_, err := c.DeleteUser(context.Background(), &membankv1.DeleteUserRequest{Id: owner.ID.String()})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "regular user cannot create accounts",
+			caller: other,
+			call: func(c membankv1.UserServiceClient) error {
+				_, err := c.CreateUser(context.Background(), &membankv1.CreateUserRequest{Username: "alice2", Email: "alice@example.org"})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "regular user cannot list users",
+			caller: other,
+			call: func(c membankv1.UserServiceClient) error {
+				_, err := c.ListUsers(context.Background(), &membankv1.ListUsersRequest{})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "admin deletes",
+			caller: admin,
+			call: func(c membankv1.UserServiceClient) error {
+				_, err := c.DeleteUser(context.Background(), &membankv1.DeleteUserRequest{Id: owner.ID.String()})
+				return err
+			},
+			setupMocks: func(s *mockUserService) {
+				s.On("DeleteUser", mock.Anything, owner.ID).Return(nil)
+			},
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockUserService{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(service)
+			}
+
+			err := tt.call(newClient(t, service, tt.caller))
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_StatusMapping(t *testing.T) {
+	admin := auth.Principal{UserID: user.ID(uuid.New()), Role: auth.RoleAdmin}
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{"not found", user.ErrNotFound, codes.NotFound},
+		{"email taken", user.ErrEmailTaken, codes.AlreadyExists},
+		{"invalid email", user.ErrInvalidEmail, codes.InvalidArgument},
+		{"invalid role", user.ErrInvalidRole, codes.InvalidArgument},
+		{"inactive", user.ErrInactive, codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockUserService{}
+			service.On("CreateUser", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			_, err := newClient(t, service, admin).CreateUser(context.Background(), &membankv1.CreateUserRequest{
+				Username: "alice",
+				Email:    "alice@example.com",
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			service.AssertExpectations(t)
+		})
+	}
+
+	_, err := newClient(t, &mockUserService{}, admin).GetUser(context.Background(), &membankv1.GetUserRequest{Id: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,198 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/apikey"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/middleware"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// Handler handles HTTP requests for the authenticated user's API keys
+type Handler struct {
+	service apikey.Service
+	logger  logger.Logger
+}
+
+// NewHandler creates a new API key HTTP handler
+func NewHandler(service apikey.Service, logger logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateAPIKeyRequest represents the JSON request for creating an API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyResponse describes an API key without its secret
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKeyResponse includes the secret, which is only returned once
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// CreateAPIKey issues a new key for the authenticated user
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	createReq := apikey.CreateRequest{
+		Name:   req.Name,
+		Scopes: req.Scopes,
+	}
+	if req.ExpiresAt != nil {
+		createReq.ExpiresAt = *req.ExpiresAt
+	}
+
+	key, secret, err := h.service.Create(c.Request.Context(), user.ID(userID), createReq)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, CreatedAPIKeyResponse{
+		APIKeyResponse: toResponse(key),
+		Key:            secret,
+	})
+}
+
+// ListAPIKeys returns the authenticated user's keys
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	keys, err := h.service.List(c.Request.Context(), user.ID(userID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = toResponse(key)
+	}
+	response.Success(c, http.StatusOK, responses)
+}
+
+// GetAPIKey returns one of the authenticated user's keys
+func (h *Handler) GetAPIKey(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_id", "Invalid API key ID")
+		return
+	}
+
+	key, err := h.service.Get(c.Request.Context(), user.ID(userID), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, toResponse(key))
+}
+
+// RevokeAPIKey revokes one of the authenticated user's keys
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_id", "Invalid API key ID")
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), user.ID(userID), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"message": "API key revoked",
+	})
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, apikey.ErrNotFound):
+		response.NotFound(c, "API key")
+	case errors.Is(err, apikey.ErrInvalidName):
+		response.BadRequest(c, "invalid_name", "Name must be between 1 and 100 characters")
+	case errors.Is(err, apikey.ErrInvalidScope):
+		response.BadRequest(c, "invalid_scope", err.Error())
+	case errors.Is(err, apikey.ErrInvalidExpiry):
+		response.BadRequest(c, "invalid_expiry", err.Error())
+	case errors.Is(err, apikey.ErrTooManyKeys):
+		response.Error(c, http.StatusConflict, "too_many_keys", "API key limit reached, revoke an unused key first")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		response.Forbidden(c, "Only admins may create keys with the admin scope")
+	default:
+		h.logger.WithError(err).Error("Failed to handle API key request")
+		response.InternalError(c, "Failed to handle API key request")
+	}
+}
+
+func toResponse(key *apikey.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  optionalTime(key.ExpiresAt),
+		LastUsedAt: optionalTime(key.LastUsedAt),
+		CreatedAt:  key.CreatedAt,
+		RevokedAt:  optionalTime(key.RevokedAt),
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
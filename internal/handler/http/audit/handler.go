@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/audit"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// Handler handles HTTP requests for the audit log
+type Handler struct {
+	service audit.Service
+	logger  logger.Logger
+}
+
+// EntryResponse is an audit entry as returned by the API
+type EntryResponse struct {
+	ID           string    `json:"id"`
+	Sequence     int64     `json:"sequence"`
+	ActorType    string    `json:"actor_type"`
+	ActorUserID  string    `json:"actor_user_id,omitempty"`
+	ActorRole    string    `json:"actor_role,omitempty"`
+	APIKeyID     string    `json:"api_key_id,omitempty"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	RequestID    string    `json:"request_id,omitempty"`
+	BeforeHash   string    `json:"before_hash,omitempty"`
+	AfterHash    string    `json:"after_hash,omitempty"`
+	PrevHash     string    `json:"prev_hash,omitempty"`
+	Hash         string    `json:"hash,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ChainVerificationResponse is the outcome of checking the hash chain
+type ChainVerificationResponse struct {
+	Checked  int64 `json:"checked"`
+	Valid    bool  `json:"valid"`
+	BrokenAt int64 `json:"broken_at,omitempty"`
+}
+
+// NewHandler creates a new audit HTTP handler
+func NewHandler(service audit.Service, logger logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListEntries returns audit entries, newest first (admin). Entries are
+// filtered by the actor_id, action, resource_type, resource_id and
+// request_id query parameters and the RFC 3339 "since" and "until".
+func (h *Handler) ListEntries(c *gin.Context) {
+	filter := audit.Filter{
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		RequestID:    c.Query("request_id"),
+	}
+
+	if value := c.Query("actor_id"); value != "" {
+		actorID, err := uuid.Parse(value)
+		if err != nil {
+			response.BadRequest(c, "invalid_actor_id", "Invalid actor ID")
+			return
+		}
+		filter.ActorID = user.ID(actorID)
+	}
+	var err error
+	if value := c.Query("since"); value != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, value); err != nil {
+			response.BadRequest(c, "invalid_since", "since must be an RFC 3339 timestamp")
+			return
+		}
+	}
+	if value := c.Query("until"); value != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, value); err != nil {
+			response.BadRequest(c, "invalid_until", "until must be an RFC 3339 timestamp")
+			return
+		}
+	}
+	if filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "50")); err != nil {
+		response.BadRequest(c, "invalid_limit", "Invalid limit")
+		return
+	}
+	if filter.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil {
+		response.BadRequest(c, "invalid_offset", "Invalid offset")
+		return
+	}
+
+	entries, err := h.service.Query(c.Request.Context(), filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]EntryResponse, len(entries))
+	for i, e := range entries {
+		responses[i] = toResponse(e)
+	}
+	response.Success(c, http.StatusOK, responses)
+}
+
+// VerifyChain checks the hash chain of the audit log (admin)
+func (h *Handler) VerifyChain(c *gin.Context) {
+	result, err := h.service.VerifyChain(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, ChainVerificationResponse{
+		Checked:  result.Checked,
+		Valid:    result.Valid,
+		BrokenAt: result.BrokenAt,
+	})
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, audit.ErrInvalidFilter):
+		response.BadRequest(c, "invalid_filter", err.Error())
+	case errors.Is(err, audit.ErrChainNotEnabled):
+		response.BadRequest(c, "chain_not_enabled", "The audit hash chain is not enabled")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		response.Forbidden(c, "Permission denied")
+	default:
+		h.logger.WithError(err).Error("Failed to read audit log")
+		response.InternalError(c, "Failed to read audit log")
+	}
+}
+
+func toResponse(e *audit.Entry) EntryResponse {
+	r := EntryResponse{
+		ID:           e.ID.String(),
+		Sequence:     e.Sequence,
+		ActorType:    e.Actor.Type,
+		ActorRole:    e.Actor.Role,
+		APIKeyID:     e.Actor.APIKeyID,
+		Action:       e.Action,
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID,
+		RequestID:    e.RequestID,
+		BeforeHash:   e.BeforeHash,
+		AfterHash:    e.AfterHash,
+		PrevHash:     e.PrevHash,
+		Hash:         e.Hash,
+		CreatedAt:    e.CreatedAt,
+	}
+	if !e.Actor.UserID.IsZero() {
+		r.ActorUserID = e.Actor.UserID.String()
+	}
+	return r
+}
@@ -0,0 +1,321 @@
+package auth
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/middleware"
+	pkgauth "mem_bank/pkg/auth"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// Handler handles HTTP requests for registration, login and sessions
+type Handler struct {
+	service    auth.Service
+	tokens     auth.TokenService
+	jwtService *pkgauth.JWTService
+	oidc       auth.OIDCService
+	logger     logger.Logger
+}
+
+// NewHandler creates a new authentication HTTP handler
+func NewHandler(service auth.Service, tokens auth.TokenService, jwtService *pkgauth.JWTService, logger logger.Logger) *Handler {
+	return &Handler{
+		service:    service,
+		tokens:     tokens,
+		jwtService: jwtService,
+		logger:     logger,
+	}
+}
+
+// WithOIDC enables login through an external OpenID provider
+func (h *Handler) WithOIDC(service auth.OIDCService) *Handler {
+	h.oidc = service
+	return h
+}
+
+// RegisterRequest represents the JSON request for creating an account
+type RegisterRequest struct {
+	Username string       `json:"username" binding:"required"`
+	Email    string       `json:"email" binding:"required,email"`
+	Password string       `json:"password" binding:"required"`
+	Profile  user.Profile `json:"profile"`
+}
+
+// LoginRequest represents a login request; username may also be an email
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginResponse represents a login response
+type LoginResponse struct {
+	Success          bool   `json:"success"`
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in,omitempty"`
+	User             struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+		Role     string `json:"role"`
+	} `json:"user"`
+}
+
+// RefreshTokenRequest represents a refresh token request
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest represents a logout request; the refresh token is optional
+// when the request carries an access token
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ChangePasswordRequest represents the JSON request for changing a password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// Register creates a user with a password and logs them in
+func (h *Handler) Register(c *gin.Context) {
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	u, err := h.service.Register(c.Request.Context(), auth.RegisterRequest{
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+		Profile:  req.Profile,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusCreated, u)
+}
+
+// Login verifies a password and issues an access token for the user
+func (h *Handler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", "Invalid request format")
+		return
+	}
+
+	u, err := h.service.Login(c.Request.Context(), user.LoginRequest{
+		Username: req.Username,
+		Password: req.Password,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, u)
+}
+
+// OIDCLogin redirects to the OpenID provider to sign in
+func (h *Handler) OIDCLogin(c *gin.Context) {
+	if h.oidc == nil {
+		response.NotFound(c, "OpenID Connect login")
+		return
+	}
+
+	url, err := h.oidc.LoginURL(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, url)
+}
+
+// OIDCCallback completes a login at the OpenID provider and issues tokens
+// for the signed in user, creating the user on first login
+func (h *Handler) OIDCCallback(c *gin.Context) {
+	if h.oidc == nil {
+		response.NotFound(c, "OpenID Connect login")
+		return
+	}
+
+	if providerErr := c.Query("error"); providerErr != "" {
+		response.Unauthorized(c, "Login at the identity provider failed: "+providerErr)
+		return
+	}
+
+	u, err := h.oidc.Callback(c.Request.Context(), c.Query("state"), c.Query("code"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	h.respondWithTokens(c, http.StatusOK, u)
+}
+
+// RefreshToken exchanges a refresh token for a new token pair. The
+// presented refresh token is used up; presenting it again ends the session.
+func (h *Handler) RefreshToken(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", "Invalid request format")
+		return
+	}
+
+	pair, u, err := h.tokens.RefreshTokens(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, h.loginResponse(pair, u))
+}
+
+// Logout revokes the presented access token and its session, and the
+// session of a refresh token sent in the body
+func (h *Handler) Logout(c *gin.Context) {
+	var req LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "invalid_request", "Invalid request format")
+			return
+		}
+	}
+
+	logout := auth.LogoutRequest{RefreshToken: req.RefreshToken}
+	if claims, err := middleware.GetClaims(c); err == nil {
+		if err := h.jwtService.RevokeToken(c.Request.Context(), claims); err != nil {
+			h.handleError(c, err)
+			return
+		}
+		logout.SessionID = claims.SessionID
+	}
+
+	if err := h.tokens.Logout(c.Request.Context(), logout); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"message": "Successfully logged out",
+	})
+}
+
+// ChangePassword replaces the authenticated user's password
+func (h *Handler) ChangePassword(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	err = h.service.ChangePassword(c.Request.Context(), user.ID(userID), auth.ChangePasswordRequest{
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// A changed password ends every session, including this one
+	if err := h.tokens.RevokeAll(c.Request.Context(), user.ID(userID)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"message": "Password changed, please log in again",
+	})
+}
+
+func (h *Handler) respondWithTokens(c *gin.Context, status int, u *user.User) {
+	pair, err := h.tokens.IssueTokens(c.Request.Context(), u)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to issue tokens")
+		response.InternalError(c, "Failed to generate authentication token")
+		return
+	}
+
+	response.Success(c, status, h.loginResponse(pair, u))
+}
+
+func (h *Handler) loginResponse(pair *auth.TokenPair, u *user.User) LoginResponse {
+	resp := LoginResponse{
+		Success:          true,
+		AccessToken:      pair.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(pair.ExpiresIn.Seconds()),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresIn: int64(pair.RefreshExpiresIn.Seconds()),
+	}
+	resp.User.ID = u.ID.String()
+	resp.User.Username = u.Username
+	resp.User.Email = u.Email
+	resp.User.Role = u.Role
+	return resp
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	var locked *auth.LockedError
+	switch {
+	case errors.As(err, &locked):
+		retryAfter := int(math.Ceil(time.Until(locked.Until).Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		response.Error(c, http.StatusTooManyRequests, "account_locked", "Too many failed login attempts, try again later")
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		response.Unauthorized(c, "Invalid credentials")
+	case errors.Is(err, auth.ErrRefreshTokenReused):
+		response.Error(c, http.StatusUnauthorized, "token_revoked", "Refresh token already used; the session has been revoked")
+	case errors.Is(err, auth.ErrInvalidRefreshToken):
+		response.Error(c, http.StatusUnauthorized, "invalid_token", "Invalid or expired refresh token")
+	case errors.Is(err, auth.ErrOIDCLoginFailed):
+		response.Unauthorized(c, "Login at the identity provider failed")
+	case errors.Is(err, auth.ErrIdentityConflict):
+		response.Error(c, http.StatusConflict, "conflict", "An account with this email already exists; sign in with its password")
+	case errors.Is(err, auth.ErrInactive):
+		response.Forbidden(c, "Account is inactive")
+	case errors.Is(err, auth.ErrWeakPassword):
+		response.BadRequest(c, "weak_password", err.Error())
+	case errors.Is(err, user.ErrUsernameTaken):
+		response.Error(c, http.StatusConflict, "conflict", "Username already taken")
+	case errors.Is(err, user.ErrEmailTaken):
+		response.Error(c, http.StatusConflict, "conflict", "Email already taken")
+	case errors.Is(err, user.ErrInvalidUsername):
+		response.BadRequest(c, "invalid_username", "Invalid username")
+	case errors.Is(err, user.ErrInvalidEmail):
+		response.BadRequest(c, "invalid_email", "Invalid email format")
+	case errors.Is(err, user.ErrNotFound):
+		response.NotFound(c, "User")
+	default:
+		h.logger.WithError(err).Error("Failed to handle authentication request")
+		response.InternalError(c, "Failed to handle authentication request")
+	}
+}
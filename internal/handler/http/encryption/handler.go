@@ -0,0 +1,82 @@
+package encryption
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/encryption"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// Handler handles HTTP requests for encryption key administration
+type Handler struct {
+	service encryption.Service
+	logger  logger.Logger
+}
+
+// KeyRotationResponse reports the outcome of rotating a user's data key
+type KeyRotationResponse struct {
+	UserID      string `json:"user_id"`
+	Version     int    `json:"version"`
+	Reencrypted int    `json:"reencrypted"`
+}
+
+// NewHandler creates a new encryption HTTP handler
+func NewHandler(service encryption.Service, logger logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RotateUserKey gives a user a new data key and re-encrypts their memories (admin)
+func (h *Handler) RotateUserKey(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+		return
+	}
+
+	rotation, err := h.service.RotateUserKey(c.Request.Context(), user.ID(userID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, KeyRotationResponse{
+		UserID:      rotation.UserID.String(),
+		Version:     rotation.Version,
+		Reencrypted: rotation.Reencrypted,
+	})
+}
+
+// RewrapKeys wraps every data key with the current master key (admin)
+func (h *Handler) RewrapKeys(c *gin.Context) {
+	count, err := h.service.RewrapKeys(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"rewrapped": count})
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, user.ErrNotFound):
+		response.NotFound(c, "User")
+	case errors.Is(err, user.ErrInvalidID):
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		response.Forbidden(c, "Permission denied")
+	default:
+		h.logger.WithError(err).Error("Failed to handle encryption request")
+		response.InternalError(c, "Failed to handle encryption request")
+	}
+}
@@ -0,0 +1,113 @@
+package erasure
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/erasure"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// Handler handles HTTP requests for user erasure
+type Handler struct {
+	service erasure.Service
+	logger  logger.Logger
+}
+
+// ErasureResponse describes an erasure and, once complete, its signed receipt
+type ErasureResponse struct {
+	ID          string                `json:"id"`
+	UserID      string                `json:"user_id"`
+	RequestedBy string                `json:"requested_by"`
+	Status      string                `json:"status"`
+	Stores      []erasure.StoreResult `json:"stores"`
+	LastError   string                `json:"last_error,omitempty"`
+	RequestedAt time.Time             `json:"requested_at"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty"`
+	Receipt     string                `json:"receipt,omitempty"`
+}
+
+// NewHandler creates a new erasure HTTP handler
+func NewHandler(service erasure.Service, logger logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RequestErasure deactivates a user and queues the erasure of all their data
+func (h *Handler) RequestErasure(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+		return
+	}
+
+	e, err := h.service.RequestErasure(c.Request.Context(), user.ID(userID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, toResponse(e))
+}
+
+// GetErasure retrieves the progress and receipt of an erasure
+func (h *Handler) GetErasure(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_erasure_id", "Invalid erasure ID")
+		return
+	}
+
+	e, err := h.service.GetErasure(c.Request.Context(), erasure.ID(id))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, toResponse(e))
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, user.ErrNotFound):
+		response.NotFound(c, "User")
+	case errors.Is(err, user.ErrInvalidID):
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+	case errors.Is(err, erasure.ErrNotFound):
+		response.NotFound(c, "Erasure")
+	case errors.Is(err, erasure.ErrInvalidID):
+		response.BadRequest(c, "invalid_erasure_id", "Invalid erasure ID")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		response.Forbidden(c, "Permission denied")
+	default:
+		h.logger.WithError(err).Error("Failed to handle erasure request")
+		response.InternalError(c, "Failed to handle erasure request")
+	}
+}
+
+func toResponse(e *erasure.Erasure) ErasureResponse {
+	stores := e.Stores
+	if stores == nil {
+		stores = []erasure.StoreResult{}
+	}
+	return ErasureResponse{
+		ID:          e.ID.String(),
+		UserID:      e.UserID.String(),
+		RequestedBy: e.RequestedBy.String(),
+		Status:      e.Status,
+		Stores:      stores,
+		LastError:   e.LastError,
+		RequestedAt: e.RequestedAt,
+		CompletedAt: e.CompletedAt,
+		Receipt:     e.Receipt,
+	}
+}
@@ -0,0 +1,219 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/middleware"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// defaultHeartbeat is how often idle streams get a comment by default
+const defaultHeartbeat = 15 * time.Second
+
+// Handler handles HTTP requests for the memory event feed
+type Handler struct {
+	service   event.Service
+	logger    logger.Logger
+	heartbeat time.Duration
+}
+
+// NewHandler creates a new event HTTP handler
+func NewHandler(service event.Service, logger logger.Logger) *Handler {
+	return &Handler{
+		service:   service,
+		logger:    logger,
+		heartbeat: defaultHeartbeat,
+	}
+}
+
+// WithHeartbeat sets how often idle streams get a comment, which keeps
+// proxies from closing them
+func (h *Handler) WithHeartbeat(heartbeat time.Duration) *Handler {
+	if heartbeat > 0 {
+		h.heartbeat = heartbeat
+	}
+	return h
+}
+
+// EventResponse is a memory event as returned by the API; webhooks deliver
+// the same document
+type EventResponse struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	Sequence  int64                  `json:"sequence"`
+	MemoryID  string                 `json:"memory_id"`
+	UserID    string                 `json:"user_id"`
+	SpaceID   string                 `json:"space_id,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// FeedResponse is a page of the feed. NextSince is the since to pass for
+// the following page; it equals the request's since when nothing is new.
+type FeedResponse struct {
+	Events    []EventResponse `json:"events"`
+	NextSince int64           `json:"next_since"`
+}
+
+// ListEvents returns the authenticated user's memory events after the
+// "since" sequence number, oldest first. Clients poll with the returned
+// next_since. Events are limited to the comma-separated "types" when set;
+// admins may read another user's feed with "user_id".
+func (h *Handler) ListEvents(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	filter := event.Filter{UserID: user.ID(userID)}
+	if value := c.Query("user_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+			return
+		}
+		filter.UserID = user.ID(id)
+	}
+	if filter.Since, err = strconv.ParseInt(c.DefaultQuery("since", "0"), 10, 64); err != nil {
+		response.BadRequest(c, "invalid_since", "since must be an event sequence number")
+		return
+	}
+	if filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "100")); err != nil {
+		response.BadRequest(c, "invalid_limit", "Invalid limit")
+		return
+	}
+	if value := c.Query("types"); value != "" {
+		filter.Types = strings.Split(value, ",")
+	}
+
+	events, err := h.service.List(c.Request.Context(), filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	feed := FeedResponse{
+		Events:    make([]EventResponse, len(events)),
+		NextSince: filter.Since,
+	}
+	for i, e := range events {
+		feed.Events[i] = toResponse(e)
+		feed.NextSince = e.Sequence
+	}
+	response.Success(c, http.StatusOK, feed)
+}
+
+// StreamUserMemories streams the changes to the memories of the user in
+// the path as server-sent events, for as long as the client stays
+// connected. Each event is named after its type and carries the same
+// document as the feed, with the sequence number as its ID. A client that
+// reconnects with Last-Event-ID, or "last_event_id" where it cannot set
+// headers, first receives the recent events it missed.
+func (h *Handler) StreamUserMemories(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var after int64
+	if lastEventID != "" {
+		if after, err = strconv.ParseInt(lastEventID, 10, 64); err != nil {
+			response.BadRequest(c, "invalid_last_event_id", "Last-Event-ID must be an event sequence number")
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	events, err := h.service.Stream(ctx, user.ID(userID), after)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Streams outlive the server's write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.WithError(err).Warn("Failed to lift the write deadline of an event stream")
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(toResponse(e))
+			if err != nil {
+				h.logger.WithError(err).Error("Failed to encode stream event")
+				continue
+			}
+			if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", e.Sequence, e.Type, data); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, event.ErrStreamDisabled):
+		response.Error(c, http.StatusServiceUnavailable, "stream_disabled", "Event streams are disabled")
+	case errors.Is(err, event.ErrInvalidFilter):
+		response.BadRequest(c, "invalid_filter", err.Error())
+	case errors.Is(err, auth.ErrPermissionDenied):
+		response.Forbidden(c, "Permission denied")
+	default:
+		h.logger.WithError(err).Error("Failed to read memory events")
+		response.InternalError(c, "Failed to read memory events")
+	}
+}
+
+func toResponse(e *event.Event) EventResponse {
+	r := EventResponse{
+		ID:        e.ID.String(),
+		Type:      e.Type,
+		Sequence:  e.Sequence,
+		MemoryID:  e.MemoryID.String(),
+		UserID:    e.UserID.String(),
+		Data:      e.Data,
+		CreatedAt: e.CreatedAt,
+	}
+	if !e.SpaceID.IsZero() {
+		r.SpaceID = e.SpaceID.String()
+	}
+	return r
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/space"
 	"mem_bank/internal/domain/user"
 	"mem_bank/pkg/logger"
 )
@@ -29,7 +30,9 @@ func NewHandler(service memory.Service, logger logger.Logger) *Handler {
 
 // CreateMemoryRequest represents the JSON request for creating a memory
 type CreateMemoryRequest struct {
-	UserID     string                 `json:"user_id" binding:"required"`
+	UserID string `json:"user_id" binding:"required"`
+	// SpaceID stores the memory in a shared space instead of the user's own
+	SpaceID    string                 `json:"space_id"`
 	Content    string                 `json:"content" binding:"required"`
 	Summary    string                 `json:"summary"`
 	Importance int                    `json:"importance" binding:"min=1,max=10"`
@@ -55,6 +58,10 @@ type SearchMemoryRequest struct {
 	MemoryType string   `json:"memory_type"`
 	Limit      int      `json:"limit"`
 	Offset     int      `json:"offset"`
+	// IncludeShared also searches the user's shared spaces, or only
+	// SpaceIDs when given
+	IncludeShared bool     `json:"include_shared"`
+	SpaceIDs      []string `json:"space_ids"`
 }
 
 // BatchCreateRequest represents batch memory creation request
@@ -104,9 +111,16 @@ func (h *Handler) CreateMemory(c *gin.Context) {
 		return
 	}
 
+	spaceID, err := parseSpaceID(req.SpaceID)
+	if err != nil {
+		h.sendErrorResponse(c, http.StatusBadRequest, "INVALID_SPACE_ID", "Invalid space ID format", err.Error())
+		return
+	}
+
 	// Convert to domain request
 	createReq := memory.CreateRequest{
 		UserID:     user.ID(userID),
+		SpaceID:    spaceID,
 		Content:    req.Content,
 		Summary:    req.Summary,
 		Importance: req.Importance,
@@ -124,6 +138,53 @@ func (h *Handler) CreateMemory(c *gin.Context) {
 	h.sendSuccessResponse(c, http.StatusCreated, h.toResponse(m))
 }
 
+func (h *Handler) BatchCreateMemories(c *gin.Context) {
+	var req BatchCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendErrorResponse(c, http.StatusBadRequest, "VALIDATION_ERROR", "Invalid request data", err.Error())
+		return
+	}
+
+	createReqs := make([]memory.CreateRequest, len(req.Memories))
+	for i, item := range req.Memories {
+		userID, err := uuid.Parse(item.UserID)
+		if err != nil {
+			h.sendErrorResponse(c, http.StatusBadRequest, "INVALID_USER_ID", "Invalid user ID format", err.Error())
+			return
+		}
+
+		spaceID, err := parseSpaceID(item.SpaceID)
+		if err != nil {
+			h.sendErrorResponse(c, http.StatusBadRequest, "INVALID_SPACE_ID", "Invalid space ID format", err.Error())
+			return
+		}
+
+		createReqs[i] = memory.CreateRequest{
+			UserID:     user.ID(userID),
+			SpaceID:    spaceID,
+			Content:    item.Content,
+			Summary:    item.Summary,
+			Importance: item.Importance,
+			MemoryType: item.MemoryType,
+			Tags:       item.Tags,
+			Metadata:   item.Metadata,
+		}
+	}
+
+	memories, err := h.service.BatchCreateMemories(c.Request.Context(), createReqs)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	responses := make([]interface{}, len(memories))
+	for i, m := range memories {
+		responses[i] = h.toResponse(m)
+	}
+
+	h.sendSuccessResponse(c, http.StatusCreated, responses)
+}
+
 func (h *Handler) GetMemory(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -132,7 +193,13 @@ func (h *Handler) GetMemory(c *gin.Context) {
 		return
 	}
 
-	m, err := h.service.GetMemory(c.Request.Context(), memory.ID(id))
+	// Tokenized personal data is restored only when asked for
+	get := h.service.GetMemory
+	if c.Query("rehydrate") == "true" {
+		get = h.service.RehydrateMemory
+	}
+
+	m, err := get(c.Request.Context(), memory.ID(id))
 	if err != nil {
 		h.handleServiceError(c, err)
 		return
@@ -229,6 +296,41 @@ func (h *Handler) ListUserMemories(c *gin.Context) {
 	})
 }
 
+// ListSpaceMemories lists the memories of a shared space the caller belongs to
+func (h *Handler) ListSpaceMemories(c *gin.Context) {
+	spaceID, err := uuid.Parse(c.Param("space_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid space ID"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	memories, err := h.service.ListSpaceMemories(c.Request.Context(), space.ID(spaceID), limit, offset)
+	if err != nil {
+		h.handleServiceError(c, err)
+		return
+	}
+
+	response := make([]interface{}, len(memories))
+	for i, m := range memories {
+		response[i] = h.toResponse(m)
+	}
+
+	h.sendPaginatedResponse(c, response, &PageMeta{
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
 func (h *Handler) SearchMemories(c *gin.Context) {
 	userIDStr := c.Param("user_id")
 	userID, err := uuid.Parse(userIDStr)
@@ -250,14 +352,22 @@ func (h *Handler) SearchMemories(c *gin.Context) {
 		req.Offset = 0
 	}
 
+	spaceIDs, err := parseSpaceIDs(req.SpaceIDs)
+	if err != nil {
+		h.sendErrorResponse(c, http.StatusBadRequest, "INVALID_SPACE_ID", "Invalid space ID format", err.Error())
+		return
+	}
+
 	// Convert to domain request
 	searchReq := memory.SearchRequest{
-		UserID:     user.ID(userID),
-		Query:      req.Query,
-		Tags:       req.Tags,
-		MemoryType: req.MemoryType,
-		Limit:      req.Limit,
-		Offset:     req.Offset,
+		UserID:        user.ID(userID),
+		Query:         req.Query,
+		Tags:          req.Tags,
+		MemoryType:    req.MemoryType,
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+		IncludeShared: req.IncludeShared,
+		SpaceIDs:      spaceIDs,
 	}
 
 	memories, err := h.service.SearchMemories(c.Request.Context(), searchReq)
@@ -304,7 +414,20 @@ func (h *Handler) SearchSimilarMemories(c *gin.Context) {
 		threshold = 0.8
 	}
 
-	memories, err := h.service.SearchSimilarMemories(c.Request.Context(), content, user.ID(userID), limit, threshold)
+	// include_shared=true, or one or more space_id parameters, extend the
+	// search to shared spaces
+	spaceIDs, err := parseSpaceIDs(c.QueryArray("space_id"))
+	if err != nil {
+		h.sendErrorResponse(c, http.StatusBadRequest, "INVALID_SPACE_ID", "Invalid space ID format", err.Error())
+		return
+	}
+
+	var memories []*memory.Memory
+	if c.Query("include_shared") == "true" || len(spaceIDs) > 0 {
+		memories, err = h.service.SearchSimilarAcrossSpaces(c.Request.Context(), content, user.ID(userID), spaceIDs, limit, threshold)
+	} else {
+		memories, err = h.service.SearchSimilarMemories(c.Request.Context(), content, user.ID(userID), limit, threshold)
+	}
 	if err != nil {
 		h.handleServiceError(c, err)
 		return
@@ -426,15 +549,25 @@ func (h *Handler) getStatusFromErrorCode(code string) int {
 		return http.StatusForbidden
 	case memory.ErrCodeExternalService:
 		return http.StatusBadGateway
+	case memory.ErrCodeQuotaExceeded:
+		return http.StatusTooManyRequests
+	case memory.ErrCodePIIDetected:
+		return http.StatusUnprocessableEntity
 	default:
 		return http.StatusInternalServerError
 	}
 }
 
 func (h *Handler) toResponse(m *memory.Memory) interface{} {
+	var spaceID interface{}
+	if m.IsShared() {
+		spaceID = m.SpaceID.String()
+	}
+
 	return map[string]interface{}{
 		"id":            m.ID,
 		"user_id":       m.UserID,
+		"space_id":      spaceID,
 		"content":       m.Content,
 		"summary":       m.Summary,
 		"importance":    m.Importance,
@@ -447,3 +580,27 @@ func (h *Handler) toResponse(m *memory.Memory) interface{} {
 		"access_count":  m.AccessCount,
 	}
 }
+
+// parseSpaceID parses an optional space ID; empty means the personal space
+func parseSpaceID(s string) (space.ID, error) {
+	if s == "" {
+		return space.ID{}, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return space.ID{}, err
+	}
+	return space.ID(id), nil
+}
+
+func parseSpaceIDs(values []string) ([]space.ID, error) {
+	ids := make([]space.ID, 0, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, space.ID(id))
+	}
+	return ids, nil
+}
@@ -0,0 +1,127 @@
+package quota
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/quota"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/middleware"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// Handler handles HTTP requests for quota operations
+type Handler struct {
+	service quota.Service
+	logger  logger.Logger
+}
+
+// NewHandler creates a new quota HTTP handler
+func NewHandler(service quota.Service, logger logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// UpdateQuotaRequest represents the JSON request for adjusting a user's quota.
+// Limits of zero mean unlimited; omitted fields are left unchanged.
+type UpdateQuotaRequest struct {
+	Plan                    *string `json:"plan,omitempty"`
+	MaxMemories             *int    `json:"max_memories,omitempty"`
+	MaxStorageBytes         *int64  `json:"max_storage_bytes,omitempty"`
+	MonthlyEmbeddingTokens  *int64  `json:"monthly_embedding_tokens,omitempty"`
+	MonthlyCompletionTokens *int64  `json:"monthly_completion_tokens,omitempty"`
+	ResetOverrides          bool    `json:"reset_overrides,omitempty"`
+}
+
+// GetMyQuota returns the authenticated user's limits and usage
+func (h *Handler) GetMyQuota(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	h.respondWithStatus(c, user.ID(userID))
+}
+
+// GetQuota returns any user's limits and usage (admin)
+func (h *Handler) GetQuota(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+		return
+	}
+
+	h.respondWithStatus(c, user.ID(userID))
+}
+
+// UpdateQuota changes a user's plan or limits (admin)
+func (h *Handler) UpdateQuota(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+		return
+	}
+
+	var req UpdateQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	status, err := h.service.UpdateQuota(c.Request.Context(), user.ID(userID), quota.UpdateRequest{
+		Plan:                    req.Plan,
+		MaxMemories:             req.MaxMemories,
+		MaxStorageBytes:         req.MaxStorageBytes,
+		MonthlyEmbeddingTokens:  req.MonthlyEmbeddingTokens,
+		MonthlyCompletionTokens: req.MonthlyCompletionTokens,
+		ResetOverrides:          req.ResetOverrides,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, h.toResponse(status))
+}
+
+func (h *Handler) respondWithStatus(c *gin.Context, userID user.ID) {
+	status, err := h.service.GetStatus(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, h.toResponse(status))
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, user.ErrNotFound):
+		response.NotFound(c, "User")
+	case errors.Is(err, quota.ErrInvalidUserID):
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+	case errors.Is(err, quota.ErrUnknownPlan):
+		response.BadRequest(c, "unknown_plan", "Unknown quota plan")
+	case errors.Is(err, quota.ErrInvalidLimit):
+		response.BadRequest(c, "invalid_limit", "Quota limits must not be negative")
+	default:
+		h.logger.WithError(err).Error("Failed to handle quota request")
+		response.InternalError(c, "Failed to handle quota request")
+	}
+}
+
+func (h *Handler) toResponse(status *quota.Status) interface{} {
+	return map[string]interface{}{
+		"user_id": status.UserID.String(),
+		"plan":    status.Plan,
+		"limits":  status.Limits,
+		"usage":   status.Usage,
+	}
+}
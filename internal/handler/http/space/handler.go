@@ -0,0 +1,332 @@
+package space
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// Handler handles HTTP requests for shared spaces and their members
+type Handler struct {
+	service space.Service
+	logger  logger.Logger
+}
+
+// NewHandler creates a new space HTTP handler
+func NewHandler(service space.Service, logger logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateSpaceRequest represents the JSON request for creating a space
+type CreateSpaceRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// UpdateSpaceRequest represents the JSON request for updating a space
+type UpdateSpaceRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// AddMemberRequest represents the JSON request for adding a member
+type AddMemberRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required"`
+}
+
+// UpdateMemberRequest represents the JSON request for changing a member's role
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// SpaceResponse describes a space, with the caller's role when listed
+type SpaceResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	CreatedBy   *string    `json:"created_by,omitempty"`
+	Role        string     `json:"role,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// MemberResponse describes a member of a space
+type MemberResponse struct {
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateSpace creates a space owned by the authenticated user
+func (h *Handler) CreateSpace(c *gin.Context) {
+	var req CreateSpaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	sp, err := h.service.CreateSpace(c.Request.Context(), space.CreateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	resp := toResponse(sp)
+	resp.Role = space.RoleOwner
+	response.Success(c, http.StatusCreated, resp)
+}
+
+// ListSpaces returns the spaces the authenticated user is a member of
+func (h *Handler) ListSpaces(c *gin.Context) {
+	memberships, err := h.service.ListSpaces(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]SpaceResponse, len(memberships))
+	for i, membership := range memberships {
+		responses[i] = toResponse(membership.Space)
+		responses[i].Role = membership.Role
+	}
+	response.Success(c, http.StatusOK, responses)
+}
+
+// GetSpace returns a space the authenticated user is a member of
+func (h *Handler) GetSpace(c *gin.Context) {
+	id, ok := h.spaceID(c)
+	if !ok {
+		return
+	}
+
+	sp, err := h.service.GetSpace(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, toResponse(sp))
+}
+
+// UpdateSpace renames or describes a space
+func (h *Handler) UpdateSpace(c *gin.Context) {
+	id, ok := h.spaceID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSpaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	sp, err := h.service.UpdateSpace(c.Request.Context(), id, space.UpdateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, toResponse(sp))
+}
+
+// DeleteSpace deletes a space together with its memories
+func (h *Handler) DeleteSpace(c *gin.Context) {
+	id, ok := h.spaceID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSpace(c.Request.Context(), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"message": "Space deleted",
+	})
+}
+
+// ListMembers returns the members of a space
+func (h *Handler) ListMembers(c *gin.Context) {
+	id, ok := h.spaceID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]MemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = toMemberResponse(member)
+	}
+	response.Success(c, http.StatusOK, responses)
+}
+
+// AddMember adds a user to a space with a role
+func (h *Handler) AddMember(c *gin.Context) {
+	id, ok := h.spaceID(c)
+	if !ok {
+		return
+	}
+
+	var req AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+		return
+	}
+
+	member, err := h.service.AddMember(c.Request.Context(), id, space.AddMemberRequest{
+		UserID: user.ID(userID),
+		Role:   req.Role,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, toMemberResponse(member))
+}
+
+// UpdateMember changes the role of a member
+func (h *Handler) UpdateMember(c *gin.Context) {
+	id, ok := h.spaceID(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+		return
+	}
+
+	var req UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	member, err := h.service.UpdateMember(c.Request.Context(), id, user.ID(userID), req.Role)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, toMemberResponse(member))
+}
+
+// RemoveMember removes a member from a space; members may remove themselves
+func (h *Handler) RemoveMember(c *gin.Context) {
+	id, ok := h.spaceID(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+		return
+	}
+
+	if err := h.service.RemoveMember(c.Request.Context(), id, user.ID(userID)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"message": "Member removed",
+	})
+}
+
+func (h *Handler) spaceID(c *gin.Context) (space.ID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_id", "Invalid space ID")
+		return space.ID{}, false
+	}
+	return space.ID(id), true
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, space.ErrNotFound):
+		response.NotFound(c, "Space")
+	case errors.Is(err, space.ErrNotMember):
+		response.NotFound(c, "Member")
+	case errors.Is(err, user.ErrNotFound):
+		response.NotFound(c, "User")
+	case errors.Is(err, space.ErrInvalidID):
+		response.BadRequest(c, "invalid_id", "Invalid space ID")
+	case errors.Is(err, user.ErrInvalidID):
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+	case errors.Is(err, space.ErrInvalidName):
+		response.BadRequest(c, "invalid_name", "Name must be between 1 and 100 characters")
+	case errors.Is(err, space.ErrInvalidRole):
+		response.BadRequest(c, "invalid_role", "Role must be owner, editor or viewer")
+	case errors.Is(err, space.ErrAlreadyMember):
+		response.Error(c, http.StatusConflict, "conflict", "User is already a member of the space")
+	case errors.Is(err, space.ErrLastOwner):
+		response.Error(c, http.StatusConflict, "last_owner", "A space must keep at least one owner")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		response.Forbidden(c, "Your role in this space does not allow this")
+	default:
+		h.logger.WithError(err).Error("Failed to handle space request")
+		response.InternalError(c, "Failed to handle space request")
+	}
+}
+
+func toResponse(sp *space.Space) SpaceResponse {
+	resp := SpaceResponse{
+		ID:          sp.ID.String(),
+		Name:        sp.Name,
+		Description: sp.Description,
+		CreatedAt:   sp.CreatedAt,
+	}
+	if !sp.CreatedBy.IsZero() {
+		createdBy := sp.CreatedBy.String()
+		resp.CreatedBy = &createdBy
+	}
+	if !sp.UpdatedAt.IsZero() {
+		updatedAt := sp.UpdatedAt
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
+
+func toMemberResponse(member *space.Member) MemberResponse {
+	return MemberResponse{
+		UserID:    member.UserID.String(),
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	}
+}
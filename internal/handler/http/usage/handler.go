@@ -0,0 +1,89 @@
+package usage
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mem_bank/internal/domain/usage"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/middleware"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// Handler handles HTTP requests for LLM usage reports
+type Handler struct {
+	service usage.Service
+	logger  logger.Logger
+}
+
+// NewHandler creates a new usage HTTP handler
+func NewHandler(service usage.Service, logger logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetUsage reports the authenticated user's token usage and cost. The range
+// is given by the RFC 3339 "from" and "to" query parameters and defaults to
+// the current calendar month.
+func (h *Handler) GetUsage(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			response.BadRequest(c, "invalid_from", "from must be an RFC 3339 timestamp")
+			return
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			response.BadRequest(c, "invalid_to", "to must be an RFC 3339 timestamp")
+			return
+		}
+	}
+
+	report, err := h.service.GetUsage(c.Request.Context(), user.ID(userID), from, to)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, h.toResponse(report))
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usage.ErrInvalidRange):
+		response.BadRequest(c, "invalid_range", "from must be before to and the range at most one year")
+	case errors.Is(err, usage.ErrInvalidUserID):
+		response.BadRequest(c, "invalid_user_id", "Invalid user ID")
+	default:
+		h.logger.WithError(err).Error("Failed to get usage report")
+		response.InternalError(c, "Failed to get usage report")
+	}
+}
+
+func (h *Handler) toResponse(report *usage.Report) interface{} {
+	return map[string]interface{}{
+		"user_id":      report.UserID.String(),
+		"from":         report.From,
+		"to":           report.To,
+		"items":        report.Items,
+		"requests":     report.Requests,
+		"total_tokens": report.TotalTokens,
+		"cost_usd":     report.CostUSD,
+	}
+}
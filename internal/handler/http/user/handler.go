@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"mem_bank/internal/domain/auth"
 	"mem_bank/internal/domain/user"
 )
 
@@ -37,6 +38,7 @@ type UpdateUserRequest struct {
 	Profile  *user.Profile  `json:"profile,omitempty"`
 	Settings *user.Settings `json:"settings,omitempty"`
 	IsActive *bool          `json:"is_active,omitempty"`
+	Role     *string        `json:"role,omitempty"`
 }
 
 func (h *Handler) CreateUser(c *gin.Context) {
@@ -70,6 +72,10 @@ func (h *Handler) GetUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
+	if err := auth.Authorize(c.Request.Context(), user.ID(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
 
 	u, err := h.service.GetUser(c.Request.Context(), user.ID(id))
 	if err != nil {
@@ -92,6 +98,12 @@ func (h *Handler) GetUserByUsername(c *gin.Context) {
 		h.handleError(c, err)
 		return
 	}
+	// Other users are reported as missing, so lookups do not reveal who
+	// is registered
+	if err := auth.Authorize(c.Request.Context(), u.ID); err != nil {
+		h.handleError(c, user.ErrNotFound)
+		return
+	}
 
 	c.JSON(http.StatusOK, h.toResponse(u))
 }
@@ -108,6 +120,12 @@ func (h *Handler) GetUserByEmail(c *gin.Context) {
 		h.handleError(c, err)
 		return
 	}
+	// Other users are reported as missing, so lookups do not reveal who
+	// is registered
+	if err := auth.Authorize(c.Request.Context(), u.ID); err != nil {
+		h.handleError(c, user.ErrNotFound)
+		return
+	}
 
 	c.JSON(http.StatusOK, h.toResponse(u))
 }
@@ -119,6 +137,10 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
+	if err := auth.Authorize(c.Request.Context(), user.ID(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
 
 	var req UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -126,6 +148,14 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	// Only admins may activate or deactivate accounts and change roles
+	if req.IsActive != nil || req.Role != nil {
+		if p, _ := auth.PrincipalFromContext(c.Request.Context()); !p.IsPrivileged() {
+			h.handleError(c, auth.ErrPermissionDenied)
+			return
+		}
+	}
+
 	// Convert to domain request
 	updateReq := user.UpdateRequest{
 		Username: req.Username,
@@ -133,6 +163,7 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		Profile:  req.Profile,
 		Settings: req.Settings,
 		IsActive: req.IsActive,
+		Role:     req.Role,
 	}
 
 	u, err := h.service.UpdateUser(c.Request.Context(), user.ID(id), updateReq)
@@ -151,6 +182,10 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
+	if err := auth.Authorize(c.Request.Context(), user.ID(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
 
 	err = h.service.DeleteUser(c.Request.Context(), user.ID(id))
 	if err != nil {
@@ -210,6 +245,10 @@ func (h *Handler) UpdateLastLogin(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
+	if err := auth.Authorize(c.Request.Context(), user.ID(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
 
 	err = h.service.UpdateLastLogin(c.Request.Context(), user.ID(id))
 	if err != nil {
@@ -226,9 +265,9 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 	case user.ErrAlreadyExists, user.ErrEmailTaken, user.ErrUsernameTaken:
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-	case user.ErrInvalidEmail, user.ErrInvalidUsername, user.ErrInvalidID:
+	case user.ErrInvalidEmail, user.ErrInvalidUsername, user.ErrInvalidID, user.ErrInvalidSettings, user.ErrInvalidRole:
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-	case user.ErrInactive:
+	case user.ErrInactive, auth.ErrPermissionDenied:
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
@@ -246,5 +285,6 @@ func (h *Handler) toResponse(u *user.User) interface{} {
 		"updated_at": u.UpdatedAt,
 		"last_login": u.LastLogin,
 		"is_active":  u.IsActive,
+		"role":       u.Role,
 	}
 }
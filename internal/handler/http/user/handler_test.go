@@ -0,0 +1,313 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/middleware"
+	"mem_bank/pkg/auth"
+)
+
+// Mock user service
+type mockUserService struct {
+	mock.Mock
+}
+
+func (m *mockUserService) CreateUser(ctx context.Context, req user.CreateRequest) (*user.User, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserService) GetUser(ctx context.Context, id user.ID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserService) GetUserByUsername(ctx context.Context, username string) (*user.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserService) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserService) UpdateUser(ctx context.Context, id user.ID, req user.UpdateRequest) (*user.User, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserService) DeleteUser(ctx context.Context, id user.ID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockUserService) ListUsers(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+func (m *mockUserService) UpdateLastLogin(ctx context.Context, id user.ID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockUserService) GetUserStats(ctx context.Context) (*user.Stats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.Stats), args.Error(1)
+}
+
+var testJWT = auth.NewJWTService("0123456789abcdef0123456789abcdef", "test", time.Hour)
+
+// setupRouter mounts the handler as the app does
+func setupRouter(service user.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewHandler(service)
+
+	router := gin.New()
+	users := router.Group("/users", middleware.JWTAuth(testJWT))
+	users.POST("", middleware.RequireRole("admin", "system"), handler.CreateUser)
+	users.GET("/:id", handler.GetUser)
+	users.GET("/username/:username", handler.GetUserByUsername)
+	users.GET("/search", handler.GetUserByEmail)
+	users.PUT("/:id", handler.UpdateUser)
+	users.DELETE("/:id", handler.DeleteUser)
+	users.GET("", middleware.RequireRole("admin", "system"), handler.ListUsers)
+	users.GET("/stats", middleware.RequireRole("admin", "system"), handler.GetUserStats)
+	users.POST("/:id/login", handler.UpdateLastLogin)
+	return router
+}
+
+func tokenFor(t *testing.T, u *user.User) string {
+	t.Helper()
+	token, err := testJWT.GenerateToken(uuid.UUID(u.ID), u.Username, u.Email, u.Role)
+	require.NoError(t, err)
+	return token
+}
+
+func serve(router *gin.Engine, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_ListUsers(t *testing.T) {
+	admin := user.NewUser("root", "root@example.com", user.Profile{}, user.Settings{})
+	admin.Role = user.RoleAdmin
+	regular := user.NewUser("alice", "alice@example.com", user.Profile{}, user.Settings{})
+
+	tests := []struct {
+		name       string
+		caller     *user.User
+		setupMocks func(*mockUserService)
+		wantStatus int
+	}{
+		{
+			name:   "admin lists users",
+			caller: admin,
+			setupMocks: func(s *mockUserService) {
+				s.On("ListUsers", mock.Anything, 20, 0).Return([]*user.User{admin, regular}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "regular user is forbidden",
+			caller:     regular,
+			setupMocks: func(s *mockUserService) {},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockUserService{}
+			tt.setupMocks(service)
+
+			w := serve(setupRouter(service), http.MethodGet, "/users", tokenFor(t, tt.caller), "")
+			assert.Equal(t, tt.wantStatus, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Ownership(t *testing.T) {
+	owner := user.NewUser("alice", "alice@example.com", user.Profile{}, user.Settings{})
+	other := user.NewUser("mallory", "mallory@example.com", user.Profile{}, user.Settings{})
+	admin := user.NewUser("root", "root@example.com", user.Profile{}, user.Settings{})
+	admin.Role = user.RoleAdmin
+	active, role := false, user.RoleAdmin
+
+	tests := []struct {
+		name       string
+		caller     *user.User
+		method     string
+		path       string
+		body       string
+		setupMocks func(*mockUserService)
+		wantStatus int
+	}{
+		{
+			name:       "regular user cannot create accounts",
+			caller:     other,
+			method:     http.MethodPost,
+			path:       "/users",
+			body:       `{"username":"alice2","email":"alice@example.org"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "owner gets themselves",
+			caller: owner,
+			method: http.MethodGet,
+			path:   "/users/" + owner.ID.String(),
+			setupMocks: func(s *mockUserService) {
+				s.On("GetUser", mock.Anything, owner.ID).Return(owner, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "other user cannot get",
+			caller:     other,
+			method:     http.MethodGet,
+			path:       "/users/" + owner.ID.String(),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "other user cannot get by username",
+			caller: other,
+			method: http.MethodGet,
+			path:   "/users/username/alice",
+			setupMocks: func(s *mockUserService) {
+				s.On("GetUserByUsername", mock.Anything, "alice").Return(owner, nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "unknown username",
+			caller: other,
+			method: http.MethodGet,
+			path:   "/users/username/nobody",
+			setupMocks: func(s *mockUserService) {
+				s.On("GetUserByUsername", mock.Anything, "nobody").Return(nil, user.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "other user cannot get by email",
+			caller: other,
+			method: http.MethodGet,
+			path:   "/users/search?email=alice@example.com",
+			setupMocks: func(s *mockUserService) {
+				s.On("GetUserByEmail", mock.Anything, "alice@example.com").Return(owner, nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "other user cannot update",
+			caller:     other,
+			method:     http.MethodPut,
+			path:       "/users/" + owner.ID.String(),
+			body:       `{"username":"taken"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "owner cannot deactivate themselves",
+			caller:     owner,
+			method:     http.MethodPut,
+			path:       "/users/" + owner.ID.String(),
+			body:       `{"is_active":false}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "owner cannot promote themselves",
+			caller:     owner,
+			method:     http.MethodPut,
+			path:       "/users/" + owner.ID.String(),
+			body:       `{"role":"admin"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "admin deactivates and promotes",
+			caller: admin,
+			method: http.MethodPut,
+			path:   "/users/" + owner.ID.String(),
+			body:   `{"is_active":false,"role":"admin"}`,
+			setupMocks: func(s *mockUserService) {
+				s.On("UpdateUser", mock.Anything, owner.ID, user.UpdateRequest{IsActive: &active, Role: &role}).Return(owner, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "other user cannot delete",
+			caller:     other,
+			method:     http.MethodDelete,
+			path:       "/users/" + owner.ID.String(),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "admin deletes",
+			caller: admin,
+			method: http.MethodDelete,
+			path:   "/users/" + owner.ID.String(),
+			setupMocks: func(s *mockUserService) {
+				s.On("DeleteUser", mock.Anything, owner.ID).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "other user cannot record a login",
+			caller:     other,
+			method:     http.MethodPost,
+			path:       "/users/" + owner.ID.String() + "/login",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockUserService{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(service)
+			}
+
+			w := serve(setupRouter(service), tt.method, tt.path, tokenFor(t, tt.caller), tt.body)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/domain/webhook"
+	"mem_bank/internal/middleware"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/response"
+)
+
+// Handler handles HTTP requests for the authenticated user's webhook
+// subscriptions
+type Handler struct {
+	service webhook.Service
+	logger  logger.Logger
+}
+
+// NewHandler creates a new webhook HTTP handler
+func NewHandler(service webhook.Service, logger logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateSubscriptionRequest represents the JSON request for subscribing to
+// memory events
+type CreateSubscriptionRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Description string   `json:"description,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+}
+
+// UpdateSubscriptionRequest represents the JSON request for changing a
+// subscription; omitted fields are left unchanged
+type UpdateSubscriptionRequest struct {
+	URL         *string   `json:"url,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	EventTypes  *[]string `json:"event_types,omitempty"`
+	Active      *bool     `json:"active,omitempty"`
+}
+
+// SubscriptionResponse describes a subscription without its secret
+type SubscriptionResponse struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	EventTypes  []string  `json:"event_types"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreatedSubscriptionResponse includes the signing secret, which is only
+// returned once
+type CreatedSubscriptionResponse struct {
+	SubscriptionResponse
+	Secret string `json:"secret"`
+}
+
+// DeliveryResponse describes one delivery attempt
+type DeliveryResponse struct {
+	ID         string    `json:"id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Succeeded  bool      `json:"succeeded"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateSubscription subscribes the authenticated user to memory events
+func (h *Handler) CreateSubscription(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	var req CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	sub, err := h.service.Create(c.Request.Context(), user.ID(userID), webhook.CreateRequest{
+		URL:         req.URL,
+		Description: req.Description,
+		EventTypes:  req.EventTypes,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, CreatedSubscriptionResponse{
+		SubscriptionResponse: toResponse(sub),
+		Secret:               sub.Secret,
+	})
+}
+
+// ListSubscriptions returns the authenticated user's subscriptions
+func (h *Handler) ListSubscriptions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	subs, err := h.service.List(c.Request.Context(), user.ID(userID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]SubscriptionResponse, len(subs))
+	for i, sub := range subs {
+		responses[i] = toResponse(sub)
+	}
+	response.Success(c, http.StatusOK, responses)
+}
+
+// GetSubscription returns one of the authenticated user's subscriptions
+func (h *Handler) GetSubscription(c *gin.Context) {
+	userID, id, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.service.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, toResponse(sub))
+}
+
+// UpdateSubscription changes one of the authenticated user's subscriptions
+func (h *Handler) UpdateSubscription(c *gin.Context) {
+	userID, id, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid_request", err.Error())
+		return
+	}
+
+	sub, err := h.service.Update(c.Request.Context(), userID, id, webhook.UpdateRequest{
+		URL:         req.URL,
+		Description: req.Description,
+		EventTypes:  req.EventTypes,
+		Active:      req.Active,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, toResponse(sub))
+}
+
+// DeleteSubscription removes one of the authenticated user's subscriptions
+func (h *Handler) DeleteSubscription(c *gin.Context) {
+	userID, id, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), userID, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"message": "Webhook subscription deleted",
+	})
+}
+
+// ListDeliveries returns the delivery log of one of the authenticated
+// user's subscriptions, newest first
+func (h *Handler) ListDeliveries(c *gin.Context) {
+	userID, id, ok := h.params(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		response.BadRequest(c, "invalid_limit", "Invalid limit")
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		response.BadRequest(c, "invalid_offset", "Invalid offset")
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Request.Context(), userID, id, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	responses := make([]DeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		responses[i] = DeliveryResponse{
+			ID:         d.ID.String(),
+			EventID:    d.EventID.String(),
+			EventType:  d.EventType,
+			Attempt:    d.Attempt,
+			StatusCode: d.StatusCode,
+			Succeeded:  d.Succeeded,
+			Error:      d.Error,
+			DurationMS: d.Duration.Milliseconds(),
+			CreatedAt:  d.CreatedAt,
+		}
+	}
+	response.Success(c, http.StatusOK, responses)
+}
+
+// params returns the authenticated user and the subscription ID of the path
+func (h *Handler) params(c *gin.Context) (user.ID, webhook.ID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "Authentication required")
+		return user.ID{}, webhook.ID{}, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "invalid_id", "Invalid webhook subscription ID")
+		return user.ID{}, webhook.ID{}, false
+	}
+	return user.ID(userID), webhook.ID(id), true
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrNotFound):
+		response.NotFound(c, "Webhook subscription")
+	case errors.Is(err, webhook.ErrInvalidURL):
+		response.BadRequest(c, "invalid_url", err.Error())
+	case errors.Is(err, webhook.ErrInvalidEventType):
+		response.BadRequest(c, "invalid_event_type", err.Error())
+	case errors.Is(err, webhook.ErrInvalidDescription):
+		response.BadRequest(c, "invalid_description", "Description must be at most 255 characters")
+	case errors.Is(err, webhook.ErrTooManySubscriptions):
+		response.Error(c, http.StatusConflict, "too_many_subscriptions", "Webhook subscription limit reached, delete an unused subscription first")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		response.Forbidden(c, "Permission denied")
+	default:
+		h.logger.WithError(err).Error("Failed to handle webhook request")
+		response.InternalError(c, "Failed to handle webhook request")
+	}
+}
+
+func toResponse(sub *webhook.Subscription) SubscriptionResponse {
+	eventTypes := sub.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return SubscriptionResponse{
+		ID:          sub.ID.String(),
+		URL:         sub.URL,
+		Description: sub.Description,
+		EventTypes:  eventTypes,
+		Active:      sub.Active,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+}
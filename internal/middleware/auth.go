@@ -1,124 +1,243 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 
+	"mem_bank/internal/domain/apikey"
+	authDomain "mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
 	"mem_bank/pkg/auth"
+	"mem_bank/pkg/database"
 )
 
-// ApiKeyAuth provides API key authentication middleware
-func ApiKeyAuth(validApiKeys map[string]string) gin.HandlerFunc {
+// APIKeyAuth provides API key authentication middleware
+func APIKeyAuth(keys apikey.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			apiKey = c.Query("api_key")
-		}
-
 		if apiKey == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "API key required",
-				"code":  "missing_api_key",
+				"success": false,
+				"error":   "API key required",
+				"code":    "missing_api_key",
 			})
 			c.Abort()
 			return
 		}
 
-		// Check if API key is valid
-		userID, exists := validApiKeys[apiKey]
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid API key",
-				"code":  "invalid_api_key",
-			})
-			c.Abort()
+		if !authenticateAPIKey(c, keys, apiKey) {
 			return
 		}
-
-		// Set user ID in context
-		c.Set("user_id", userID)
 		c.Next()
 	}
 }
 
-// OptionalAuth allows both authenticated and unauthenticated access
-func OptionalAuth(validApiKeys map[string]string) gin.HandlerFunc {
+// JWTAuth provides JWT authentication middleware
+func JWTAuth(jwtService *auth.JWTService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			apiKey = c.Query("api_key")
+		if !authenticateJWT(c, jwtService) {
+			return
 		}
+		c.Next()
+	}
+}
 
-		if apiKey != "" {
-			userID, exists := validApiKeys[apiKey]
-			if exists {
-				c.Set("user_id", userID)
+// JWTOrAPIKeyAuth accepts a bearer token or an X-API-Key header and sets
+// the same claims for both, so handlers need not know how a caller signed in
+func JWTOrAPIKeyAuth(jwtService *auth.JWTService, keys apikey.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+				if !authenticateAPIKey(c, keys, apiKey) {
+					return
+				}
+				c.Next()
+				return
 			}
 		}
 
+		if !authenticateJWT(c, jwtService) {
+			return
+		}
 		c.Next()
 	}
 }
 
-// JWTAuth provides JWT authentication middleware
-func JWTAuth(jwtService *auth.JWTService) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Authorization header required",
-				"code":    "missing_auth_header",
-			})
-			c.Abort()
-			return
+// authenticateJWT validates the bearer token of a request and stores its
+// claims, or aborts with 401
+func authenticateJWT(c *gin.Context, jwtService *auth.JWTService) bool {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Authorization header required",
+			"code":    "missing_auth_header",
+		})
+		c.Abort()
+		return false
+	}
+
+	// Extract token from header
+	token := auth.ExtractTokenFromHeader(authHeader)
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Invalid authorization header format",
+			"code":    "invalid_auth_format",
+		})
+		c.Abort()
+		return false
+	}
+
+	// Validate JWT token and check it has not been revoked
+	claims, err := authenticateToken(c.Request.Context(), jwtService, token)
+	if err != nil {
+		var code string
+		switch {
+		case errors.Is(err, auth.ErrExpiredToken):
+			code = "token_expired"
+		case errors.Is(err, auth.ErrInvalidToken):
+			code = "invalid_token"
+		case errors.Is(err, auth.ErrRevokedToken):
+			code = "token_revoked"
+		default:
+			code = "auth_error"
 		}
 
-		// Extract token from header
-		token := auth.ExtractTokenFromHeader(authHeader)
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Invalid authorization header format",
-				"code":    "invalid_auth_format",
-			})
-			c.Abort()
-			return
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"code":    code,
+		})
+		c.Abort()
+		return false
+	}
+
+	// Set user info in context
+	setClaims(c, claims, "")
+	return true
+}
+
+// authenticateAPIKey resolves an API key to claims equivalent to those of a
+// token issued to its owner, restricted to the key's scopes
+func authenticateAPIKey(c *gin.Context, keys apikey.Service, apiKey string) bool {
+	key, u, err := authenticateKey(c.Request.Context(), keys, apiKey)
+	if err != nil {
+		status, code, message := http.StatusUnauthorized, "invalid_api_key", "Invalid API key"
+		switch {
+		case errors.Is(err, user.ErrInactive):
+			status, code, message = http.StatusForbidden, "account_inactive", "Account is inactive"
+		case !errors.Is(err, apikey.ErrInvalidKey):
+			status, code, message = http.StatusInternalServerError, "auth_error", "Failed to verify API key"
 		}
 
-		// Validate JWT token
-		claims, err := jwtService.ValidateToken(token)
-		if err != nil {
-			var code string
-			switch {
-			case errors.Is(err, auth.ErrExpiredToken):
-				code = "token_expired"
-			case errors.Is(err, auth.ErrInvalidToken):
-				code = "invalid_token"
-			default:
-				code = "auth_error"
-			}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   message,
+			"code":    code,
+		})
+		c.Abort()
+		return false
+	}
 
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   err.Error(),
-				"code":    code,
-			})
-			c.Abort()
-			return
+	setClaims(c, apiKeyClaims(key, u), key.ID.String())
+	c.Set("api_key_id", key.ID.String())
+	return true
+}
+
+// apiKeyClaims returns the claims of a caller signed in with key
+func apiKeyClaims(key *apikey.APIKey, u *user.User) *auth.Claims {
+	// Keys keep the admin scope after their owner is demoted, so the role
+	// is only granted while the owner is still an admin
+	role := authDomain.RoleUser
+	if key.HasScope(apikey.ScopeAdmin) && u.Role == user.RoleAdmin {
+		role = authDomain.RoleAdmin
+	}
+
+	claims := &auth.Claims{
+		UserID:   uuid.UUID(u.ID),
+		Username: u.Username,
+		Email:    u.Email,
+		Role:     role,
+		Scopes:   key.Scopes,
+	}
+	claims.Subject = u.ID.String()
+	if !key.ExpiresAt.IsZero() {
+		claims.ExpiresAt = jwt.NewNumericDate(key.ExpiresAt)
+	}
+	return claims
+}
+
+// credential is the outcome of checking the bearer token or, without one,
+// the API key of a request. Rate limiting checks credentials before the
+// route's authentication runs and keeps the outcome in the request context,
+// so authentication reuses it rather than validating the token or looking
+// up the key a second time.
+type credential struct {
+	token  string
+	claims *auth.Claims
+
+	apiKey string
+	key    *apikey.APIKey
+	owner  *user.User
+
+	err error
+}
+
+type credentialKey struct{}
+
+// checkCredential checks the bearer token in authorization or, without
+// one, apiKey. It returns nil when there is nothing it can check.
+func checkCredential(ctx context.Context, jwtService *auth.JWTService, keys apikey.Service, authorization, apiKey string) *credential {
+	if token := auth.ExtractTokenFromHeader(authorization); token != "" {
+		if jwtService == nil {
+			return nil
 		}
+		claims, err := jwtService.Authenticate(ctx, token)
+		return &credential{token: token, claims: claims, err: err}
+	}
 
-		// Set user info in context
-		c.Set("user_id", claims.UserID.String())
-		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
-		c.Set("claims", claims)
-		c.Next()
+	if apiKey != "" && keys != nil {
+		key, owner, err := keys.Authenticate(ctx, apiKey)
+		return &credential{apiKey: apiKey, key: key, owner: owner, err: err}
+	}
+	return nil
+}
+
+// callerClaims returns the claims of a valid credential
+func (c *credential) callerClaims() *auth.Claims {
+	if c.key != nil {
+		return apiKeyClaims(c.key, c.owner)
+	}
+	return c.claims
+}
+
+// withCredential keeps cred in ctx for authentication to reuse
+func withCredential(ctx context.Context, cred *credential) context.Context {
+	return context.WithValue(ctx, credentialKey{}, cred)
+}
+
+// authenticateToken validates a bearer token and checks it has not been
+// revoked, reusing the check rate limiting made for the request if any
+func authenticateToken(ctx context.Context, jwtService *auth.JWTService, token string) (*auth.Claims, error) {
+	if cred, ok := ctx.Value(credentialKey{}).(*credential); ok && cred.token != "" && cred.token == token {
+		return cred.claims, cred.err
 	}
+	return jwtService.Authenticate(ctx, token)
+}
+
+// authenticateKey resolves an API key and its owner, reusing the lookup
+// rate limiting made for the request if any
+func authenticateKey(ctx context.Context, keys apikey.Service, apiKey string) (*apikey.APIKey, *user.User, error) {
+	if cred, ok := ctx.Value(credentialKey{}).(*credential); ok && cred.apiKey != "" && cred.apiKey == apiKey {
+		return cred.key, cred.owner, cred.err
+	}
+	return keys.Authenticate(ctx, apiKey)
 }
 
 // OptionalJWTAuth provides optional JWT authentication
@@ -139,14 +258,10 @@ func OptionalJWTAuth(jwtService *auth.JWTService) gin.HandlerFunc {
 		}
 
 		// Try to validate token
-		claims, err := jwtService.ValidateToken(token)
+		claims, err := authenticateToken(c.Request.Context(), jwtService, token)
 		if err == nil {
 			// Valid token, set user info
-			c.Set("user_id", claims.UserID.String())
-			c.Set("username", claims.Username)
-			c.Set("email", claims.Email)
-			c.Set("role", claims.Role)
-			c.Set("claims", claims)
+			setClaims(c, claims, "")
 			c.Set("authenticated", true)
 		} else {
 			// Invalid or expired token, continue as unauthenticated user
@@ -157,6 +272,35 @@ func OptionalJWTAuth(jwtService *auth.JWTService) gin.HandlerFunc {
 	}
 }
 
+// setClaims stores the caller's claims in the gin context and attaches the
+// caller to the request context for services that authorize by ownership.
+// apiKeyID is set when the caller signed in with an API key.
+func setClaims(c *gin.Context, claims *auth.Claims, apiKeyID string) {
+	c.Set("user_id", claims.UserID.String())
+	c.Set("username", claims.Username)
+	c.Set("email", claims.Email)
+	c.Set("role", claims.Role)
+	c.Set("claims", claims)
+	c.Request = c.Request.WithContext(withCaller(c.Request.Context(), claims, apiKeyID))
+}
+
+// withCaller attaches the caller of claims to ctx for services that
+// authorize by ownership
+func withCaller(ctx context.Context, claims *auth.Claims, apiKeyID string) context.Context {
+	principal := authDomain.Principal{
+		UserID:   user.ID(claims.UserID),
+		Role:     claims.Role,
+		APIKeyID: apiKeyID,
+	}
+	ctx = authDomain.WithPrincipal(ctx, principal)
+	// Scope the request's database transactions to the caller so row-level
+	// security backs up the service's own checks
+	return database.WithTenant(ctx, database.Tenant{
+		UserID: claims.UserID.String(),
+		Bypass: principal.IsPrivileged(),
+	})
+}
+
 // RequireRole requires specific role for access
 func RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -188,6 +332,49 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 	}
 }
 
+// RequireScope requires API key callers to hold a scope. Callers with a
+// session token are not restricted by scopes.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := GetClaims(c)
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Authentication required",
+				"code":    "auth_required",
+			})
+			c.Abort()
+			return
+		}
+
+		if hasScope(claims, scope) {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "API key lacks the " + scope + " scope",
+			"code":    "insufficient_scope",
+		})
+		c.Abort()
+	}
+}
+
+// hasScope reports whether claims allow scope; only API keys are limited
+// by scopes
+func hasScope(claims *auth.Claims, scope string) bool {
+	if len(claims.Scopes) == 0 {
+		return true
+	}
+	for _, granted := range claims.Scopes {
+		if granted == scope || granted == apikey.ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
 // GetUserID extracts user ID from context
 func GetUserID(c *gin.Context) (uuid.UUID, error) {
 	userIDStr, exists := c.Get("user_id")
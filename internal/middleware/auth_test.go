@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/apikey"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/auth"
+)
+
+func TestJWTAuth_RejectsRevokedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("0123456789abcdef0123456789abcdef", "test", time.Hour).
+		WithRevocationList(auth.NewMemoryRevocationList())
+
+	router := gin.New()
+	router.GET("/me", JWTAuth(jwtService), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	get := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	token, err := jwtService.GenerateSessionToken(uuid.New(), "alice", "alice@example.com", "user", "session-1")
+	require.NoError(t, err)
+	other, err := jwtService.GenerateSessionToken(uuid.New(), "bob", "bob@example.com", "user", "session-2")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, get(token))
+
+	claims, err := jwtService.ValidateToken(token)
+	require.NoError(t, err)
+	require.NoError(t, jwtService.RevokeToken(context.Background(), claims))
+	assert.Equal(t, http.StatusUnauthorized, get(token))
+	assert.Equal(t, http.StatusOK, get(other))
+
+	require.NoError(t, jwtService.RevokeSession(context.Background(), "session-2"))
+	assert.Equal(t, http.StatusUnauthorized, get(other))
+}
+
+// staticKeys authenticates a fixed set of API keys
+type staticKeys struct {
+	apikey.Service
+	keys  map[string]*apikey.APIKey
+	owner *user.User
+}
+
+func (s staticKeys) Authenticate(ctx context.Context, raw string) (*apikey.APIKey, *user.User, error) {
+	if key, ok := s.keys[raw]; ok {
+		return key, s.owner, nil
+	}
+	return nil, nil, apikey.ErrInvalidKey
+}
+
+func TestJWTOrAPIKeyAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := auth.NewJWTService("0123456789abcdef0123456789abcdef", "test", time.Hour)
+	owner := &user.User{ID: user.ID(uuid.New()), Username: "alice", Email: "alice@example.com", IsActive: true, Role: user.RoleAdmin}
+	keys := staticKeys{
+		owner: owner,
+		keys: map[string]*apikey.APIKey{
+			"mbk_read":  {ID: uuid.New(), UserID: owner.ID, Scopes: []string{apikey.ScopeMemoriesRead}},
+			"mbk_admin": {ID: uuid.New(), UserID: owner.ID, Scopes: []string{apikey.ScopeAdmin}},
+		},
+	}
+
+	var seen *auth.Claims
+	router := gin.New()
+	router.Use(JWTOrAPIKeyAuth(jwtService, keys))
+	handler := func(c *gin.Context) {
+		seen, _ = GetClaims(c)
+		c.Status(http.StatusOK)
+	}
+	router.GET("/memories", RequireScope(apikey.ScopeMemoriesRead), handler)
+	router.POST("/memories", RequireScope(apikey.ScopeMemoriesWrite), handler)
+
+	do := func(method string, header, value string) int {
+		req := httptest.NewRequest(method, "/memories", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "", ""))
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "X-API-Key", "mbk_unknown"))
+
+	// API keys produce the same claims as tokens, limited to their scopes
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "X-API-Key", "mbk_read"))
+	require.NotNil(t, seen)
+	assert.Equal(t, uuid.UUID(owner.ID), seen.UserID)
+	assert.Equal(t, "alice", seen.Username)
+	assert.Equal(t, "user", seen.Role)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "X-API-Key", "mbk_read"))
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "X-API-Key", "mbk_admin"))
+	assert.Equal(t, "admin", seen.Role)
+
+	// Admin keys of demoted owners no longer carry the admin role
+	owner.Role = user.RoleUser
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "X-API-Key", "mbk_admin"))
+	assert.Equal(t, "user", seen.Role)
+
+	// Session tokens are not restricted by scopes
+	token, err := jwtService.GenerateToken(uuid.UUID(owner.ID), "alice", "alice@example.com", "user")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "Authorization", "Bearer "+token))
+	assert.Empty(t, seen.Scopes)
+}
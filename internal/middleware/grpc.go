@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"mem_bank/internal/constants"
+	"mem_bank/internal/domain/apikey"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/auth"
+	"mem_bank/pkg/logger"
+)
+
+// GRPCAuth authenticates gRPC calls as JWTOrAPIKeyAuth does HTTP requests,
+// with a bearer token in the "authorization" metadata or an API key in
+// "x-api-key", and attaches the caller to the call's context. API keys
+// must hold the scope required for the method.
+type GRPCAuth struct {
+	jwtService *auth.JWTService
+	keys       apikey.Service
+	public     map[string]bool
+	scopes     map[string]string
+}
+
+// NewGRPCAuth creates gRPC authentication requiring a signed-in caller for
+// every method
+func NewGRPCAuth(jwtService *auth.JWTService, keys apikey.Service) *GRPCAuth {
+	return &GRPCAuth{
+		jwtService: jwtService,
+		keys:       keys,
+		public:     make(map[string]bool),
+		scopes:     make(map[string]string),
+	}
+}
+
+// Public lets the methods, given by full name, be called without signing in
+func (a *GRPCAuth) Public(methods ...string) *GRPCAuth {
+	for _, method := range methods {
+		a.public[method] = true
+	}
+	return a
+}
+
+// RequireScope requires API key callers of the methods to hold scope
+func (a *GRPCAuth) RequireScope(scope string, methods ...string) *GRPCAuth {
+	for _, method := range methods {
+		a.scopes[method] = scope
+	}
+	return a
+}
+
+// Unary returns the interceptor for unary calls
+func (a *GRPCAuth) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns the interceptor for streaming calls
+func (a *GRPCAuth) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (a *GRPCAuth) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if a.public[method] {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var claims *auth.Claims
+	var apiKeyID string
+	switch {
+	case firstValue(md, "authorization") != "":
+		token := auth.ExtractTokenFromHeader(firstValue(md, "authorization"))
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization metadata format")
+		}
+		var err error
+		if claims, err = authenticateToken(ctx, a.jwtService, token); err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+	case firstValue(md, "x-api-key") != "":
+		key, u, err := authenticateKey(ctx, a.keys, firstValue(md, "x-api-key"))
+		switch {
+		case errors.Is(err, user.ErrInactive):
+			return nil, status.Error(codes.PermissionDenied, "account is inactive")
+		case errors.Is(err, apikey.ErrInvalidKey):
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		case err != nil:
+			return nil, status.Error(codes.Internal, "failed to verify API key")
+		}
+		claims, apiKeyID = apiKeyClaims(key, u), key.ID.String()
+	default:
+		return nil, status.Error(codes.Unauthenticated, "authorization required")
+	}
+
+	if scope, ok := a.scopes[method]; ok && !hasScope(claims, scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "API key lacks the %s scope", scope)
+	}
+	return withCaller(ctx, claims, apiKeyID), nil
+}
+
+// GRPCLogger logs each gRPC call once it completes and gives it a request
+// ID, taken from the "x-request-id" metadata when set
+func GRPCLogger(appLogger logger.Logger) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	logCall := func(ctx context.Context, method string, start time.Time, err error) {
+		fields := map[string]interface{}{
+			"method":     method,
+			"code":       status.Code(err).String(),
+			"latency":    time.Since(start).String(),
+			"request_id": ctx.Value(constants.ContextKeyRequestID),
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			fields["client_ip"] = p.Addr.String()
+		}
+
+		switch status.Code(err) {
+		case codes.OK:
+			appLogger.WithFields(fields).Info("gRPC call completed")
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			appLogger.WithFields(fields).WithError(err).Error("gRPC call completed with server error")
+		default:
+			appLogger.WithFields(fields).WithError(err).Warn("gRPC call completed with client error")
+		}
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = withRequestID(ctx)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withRequestID(ss.Context())
+		start := time.Now()
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, info.FullMethod, start, err)
+		return err
+	}
+	return unary, stream
+}
+
+// GRPCRecovery turns panics in gRPC handlers into Internal errors
+func GRPCRecovery(appLogger logger.Logger) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	recovered := func(method string, r interface{}) error {
+		appLogger.WithFields(map[string]interface{}{
+			"method": method,
+			"panic":  r,
+		}).Error("gRPC call panic recovered")
+		return status.Error(codes.Internal, "an unexpected error occurred during call processing")
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+	return unary, stream
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := firstValue(md, "x-request-id")
+	if requestID == "" {
+		requestID = generateRequestID()
+	}
+	return context.WithValue(ctx, constants.ContextKeyRequestID, requestID)
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// contextStream is a server stream with a replaced context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
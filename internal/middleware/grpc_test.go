@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"mem_bank/internal/domain/apikey"
+	authDomain "mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/auth"
+)
+
+func TestGRPCAuth(t *testing.T) {
+	jwtService := auth.NewJWTService("0123456789abcdef0123456789abcdef", "test", time.Hour)
+	owner := &user.User{ID: user.ID(uuid.New()), Username: "alice", Email: "alice@example.com", IsActive: true}
+	keys := staticKeys{
+		owner: owner,
+		keys: map[string]*apikey.APIKey{
+			"mbk_read": {ID: uuid.New(), UserID: owner.ID, Scopes: []string{apikey.ScopeMemoriesRead}},
+		},
+	}
+
+	interceptor := NewGRPCAuth(jwtService, keys).
+		Public("/test.Service/Register").
+		RequireScope(apikey.ScopeMemoriesRead, "/test.Service/Get").
+		RequireScope(apikey.ScopeMemoriesWrite, "/test.Service/Create").
+		Unary()
+
+	var seen authDomain.Principal
+	call := func(method string, md metadata.MD) codes.Code {
+		seen = authDomain.Principal{}
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			seen, _ = authDomain.PrincipalFromContext(ctx)
+			return nil, nil
+		})
+		return status.Code(err)
+	}
+
+	assert.Equal(t, codes.OK, call("/test.Service/Register", nil))
+	assert.Equal(t, codes.Unauthenticated, call("/test.Service/Get", nil))
+	assert.Equal(t, codes.Unauthenticated, call("/test.Service/Get", metadata.Pairs("x-api-key", "mbk_unknown")))
+	assert.Equal(t, codes.Unauthenticated, call("/test.Service/Get", metadata.Pairs("authorization", "Token abc")))
+
+	// API keys are limited to their scopes
+	assert.Equal(t, codes.OK, call("/test.Service/Get", metadata.Pairs("x-api-key", "mbk_read")))
+	assert.Equal(t, owner.ID, seen.UserID)
+	assert.Equal(t, codes.PermissionDenied, call("/test.Service/Create", metadata.Pairs("x-api-key", "mbk_read")))
+
+	// Session tokens are not restricted by scopes
+	token, err := jwtService.GenerateToken(uuid.UUID(owner.ID), "alice", "alice@example.com", "user")
+	require.NoError(t, err)
+	assert.Equal(t, codes.OK, call("/test.Service/Create", metadata.Pairs("authorization", "Bearer "+token)))
+	assert.Equal(t, owner.ID, seen.UserID)
+}
@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"context"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"mem_bank/internal/constants"
 	"mem_bank/pkg/logger"
 )
 
@@ -51,6 +54,9 @@ func RequestID() gin.HandlerFunc {
 
 		c.Set("request_id", requestID)
 		c.Header("X-Request-ID", requestID)
+		// Services read the request ID from the request context, e.g. for auditing
+		ctx := context.WithValue(c.Request.Context(), constants.ContextKeyRequestID, requestID)
+		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
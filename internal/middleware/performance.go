@@ -55,7 +55,8 @@ func SlowQueryDetector(threshold time.Duration, logger logger.Logger) gin.Handle
 
 		duration := time.Since(start)
 
-		if duration > threshold {
+		// Event streams stay open by design
+		if duration > threshold && c.Writer.Header().Get("Content-Type") != "text/event-stream" {
 			logger.WithFields(map[string]interface{}{
 				"method":     c.Request.Method,
 				"path":       c.Request.URL.Path,
@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"mem_bank/internal/domain/apikey"
+	"mem_bank/pkg/auth"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/metrics"
+	"mem_bank/pkg/ratelimit"
+	"mem_bank/pkg/response"
+)
+
+// RouteRateLimit overrides the limit for one route, optionally for one role.
+// Requests to the route are counted in their own bucket.
+type RouteRateLimit struct {
+	Method string // empty matches every method
+	Path   string // route pattern as registered, e.g. /api/v1/auth/login
+	Role   string // empty matches every caller
+	Limit  ratelimit.Limit
+}
+
+// RateLimitPolicy selects the limit for a request. The most specific match
+// wins: route and role, then route, then role, then Default.
+type RateLimitPolicy struct {
+	Default ratelimit.Limit
+	Roles   map[string]ratelimit.Limit
+	Routes  []RouteRateLimit
+}
+
+// resolve returns the limit for a request and the bucket it is counted in
+func (p RateLimitPolicy) resolve(method, route, role string) (ratelimit.Limit, string) {
+	var routeMatch *RouteRateLimit
+	for i := range p.Routes {
+		rule := &p.Routes[i]
+		if rule.Path != route || (rule.Method != "" && !strings.EqualFold(rule.Method, method)) {
+			continue
+		}
+		if rule.Role != "" && rule.Role == role {
+			return rule.Limit, method + " " + route
+		}
+		if rule.Role == "" && routeMatch == nil {
+			routeMatch = rule
+		}
+	}
+	if routeMatch != nil {
+		return routeMatch.Limit, method + " " + route
+	}
+
+	if limit, ok := p.Roles[role]; ok && role != "" {
+		return limit, ""
+	}
+	return p.Default, ""
+}
+
+// RateLimit limits requests per caller. Callers are identified by user ID
+// when authenticated and by client IP otherwise. jwtService and keys may be
+// nil; when set, valid bearer tokens and API keys identify callers even
+// though the middleware runs before the route's authentication, which then
+// reuses the check. Invalid credentials are counted against the client IP,
+// so sending a new made-up key with every request does not escape the limit.
+//
+// Limiter errors let the request through so an unavailable Redis does not
+// take the API down with it.
+func RateLimit(limiter ratelimit.Limiter, policy RateLimitPolicy, jwtService *auth.JWTService, keys apikey.Service, appLogger logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, role := rateLimitIdentity(c, jwtService, keys)
+		route := c.FullPath()
+
+		limit, bucket := policy.resolve(c.Request.Method, route, role)
+		if bucket != "" {
+			key += "|" + bucket
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), key, limit)
+		if err != nil {
+			appLogger.WithError(err).WithField("route", route).Warn("Rate limiter unavailable, allowing request")
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		c.Header("RateLimit-Policy", limit.String())
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			metrics.HTTPRateLimited.WithLabelValues(c.Request.Method, route).Inc()
+			response.TooManyRequests(c, "Rate limit exceeded")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GRPCRateLimit limits gRPC calls as RateLimit does HTTP requests. Given the
+// same limiter, a caller's gRPC and HTTP calls share one budget. Route rules
+// match the full method name, e.g. /membank.v1.MemoryService/CreateMemory.
+func GRPCRateLimit(limiter ratelimit.Limiter, policy RateLimitPolicy, jwtService *auth.JWTService, keys apikey.Service, appLogger logger.Logger) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	allow := func(ctx context.Context, method string) (context.Context, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		cred := checkCredential(ctx, jwtService, keys, firstValue(md, "authorization"), firstValue(md, "x-api-key"))
+		if cred != nil {
+			ctx = withCredential(ctx, cred)
+		}
+		key, role, ok := credentialIdentity(cred)
+		if !ok {
+			key = "ip:" + peerIP(ctx)
+		}
+
+		limit, bucket := policy.resolve("GRPC", method, role)
+		if bucket != "" {
+			key += "|" + bucket
+		}
+
+		result, err := limiter.Allow(ctx, key, limit)
+		if err != nil {
+			appLogger.WithError(err).WithField("method", method).Warn("Rate limiter unavailable, allowing call")
+			return ctx, nil
+		}
+		if !result.Allowed {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(ceilSeconds(result.RetryAfter))))
+			metrics.GRPCRateLimited.WithLabelValues(method).Inc()
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return ctx, nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := allow(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := allow(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+	return unary, stream
+}
+
+// rateLimitIdentity returns the bucket key and role of the caller. The
+// credential it checks is kept in the request context for authentication.
+func rateLimitIdentity(c *gin.Context, jwtService *auth.JWTService, keys apikey.Service) (string, string) {
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID, c.GetString("role")
+	}
+
+	cred := checkCredential(c.Request.Context(), jwtService, keys, c.GetHeader("Authorization"), c.GetHeader("X-API-Key"))
+	if cred != nil {
+		c.Request = c.Request.WithContext(withCredential(c.Request.Context(), cred))
+	}
+	if key, role, ok := credentialIdentity(cred); ok {
+		return key, role
+	}
+
+	return "ip:" + c.ClientIP(), ""
+}
+
+// credentialIdentity returns the bucket key and role of a caller presenting
+// a valid bearer token or API key
+func credentialIdentity(cred *credential) (string, string, bool) {
+	if cred == nil || cred.err != nil {
+		return "", "", false
+	}
+	claims := cred.callerClaims()
+	return "user:" + claims.UserID.String(), claims.Role, true
+}
+
+// peerIP returns the IP address of the gRPC client
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// ceilSeconds rounds d up to whole seconds for header values
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
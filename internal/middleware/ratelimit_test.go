@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"mem_bank/configs"
+	"mem_bank/internal/domain/apikey"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/auth"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/ratelimit"
+)
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, ratelimit.Limit) (*ratelimit.Result, error) {
+	return nil, errors.New("redis unavailable")
+}
+
+func newRateLimitRouter(t *testing.T, limiter ratelimit.Limiter, policy RateLimitPolicy, jwtService *auth.JWTService, keys apikey.Service) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	appLogger, err := logger.NewLogger(&configs.LoggingConfig{Level: "info", Output: "stdout"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(RateLimit(limiter, policy, jwtService, keys, appLogger))
+	router.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func serve(router *gin.Engine, method, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_HeadersAndRejection(t *testing.T) {
+	policy := RateLimitPolicy{Default: ratelimit.Limit{Requests: 2, Period: time.Minute}}
+	router := newRateLimitRouter(t, ratelimit.NewMemoryLimiter(), policy, nil, nil)
+
+	w := serve(router, http.MethodGet, "/items", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60;burst=2", w.Header().Get("RateLimit-Policy"))
+
+	serve(router, http.MethodGet, "/items", nil)
+	w = serve(router, http.MethodGet, "/items", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+}
+
+func TestRateLimit_KeysByUserAndAPIKey(t *testing.T) {
+	jwtService := auth.NewJWTService("0123456789abcdef0123456789abcdef", "test", time.Hour)
+	token, err := jwtService.GenerateToken(uuid.New(), "alice", "alice@example.com", "user")
+	require.NoError(t, err)
+
+	owner := &user.User{ID: user.ID(uuid.New()), Username: "bob", Email: "bob@example.com", IsActive: true}
+	keys := staticKeys{owner: owner, keys: map[string]*apikey.APIKey{
+		"secret": {ID: uuid.New(), UserID: owner.ID, Scopes: []string{apikey.ScopeMemoriesRead}},
+	}}
+
+	policy := RateLimitPolicy{Default: ratelimit.Limit{Requests: 1, Period: time.Minute}}
+	router := newRateLimitRouter(t, ratelimit.NewMemoryLimiter(), policy, jwtService, keys)
+
+	// Same IP, different callers: each has its own bucket
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/items", nil).Code)
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/items", http.Header{"Authorization": {"Bearer " + token}}).Code)
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/items", http.Header{"X-Api-Key": {"secret"}}).Code)
+
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, http.MethodGet, "/items", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, http.MethodGet, "/items", http.Header{"Authorization": {"Bearer " + token}}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, http.MethodGet, "/items", http.Header{"X-Api-Key": {"secret"}}).Code)
+}
+
+// countingKeys counts the API key lookups made through it
+type countingKeys struct {
+	staticKeys
+	lookups int
+}
+
+func (k *countingKeys) Authenticate(ctx context.Context, raw string) (*apikey.APIKey, *user.User, error) {
+	k.lookups++
+	return k.staticKeys.Authenticate(ctx, raw)
+}
+
+// countingRevocations counts the revocation checks made through it
+type countingRevocations struct {
+	auth.RevocationList
+	checks int
+}
+
+func (r *countingRevocations) IsRevoked(ctx context.Context, keys ...string) (bool, error) {
+	r.checks++
+	return r.RevocationList.IsRevoked(ctx, keys...)
+}
+
+func TestRateLimit_AuthenticationReusesCredential(t *testing.T) {
+	revocations := &countingRevocations{RevocationList: auth.NewMemoryRevocationList()}
+	jwtService := auth.NewJWTService("0123456789abcdef0123456789abcdef", "test", time.Hour).
+		WithRevocationList(revocations)
+	token, err := jwtService.GenerateToken(uuid.New(), "alice", "alice@example.com", "user")
+	require.NoError(t, err)
+
+	owner := &user.User{ID: user.ID(uuid.New()), Username: "bob", Email: "bob@example.com", IsActive: true}
+	keys := &countingKeys{staticKeys: staticKeys{owner: owner, keys: map[string]*apikey.APIKey{
+		"secret": {ID: uuid.New(), UserID: owner.ID, Scopes: []string{apikey.ScopeMemoriesRead}},
+	}}}
+
+	appLogger, err := logger.NewLogger(&configs.LoggingConfig{Level: "info", Output: "stdout"})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimit(ratelimit.NewMemoryLimiter(), RateLimitPolicy{Default: ratelimit.PerMinute(10)}, jwtService, keys, appLogger))
+	router.GET("/items", JWTOrAPIKeyAuth(jwtService, keys), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/items", http.Header{"X-Api-Key": {"secret"}}).Code)
+	assert.Equal(t, 1, keys.lookups)
+	assert.Equal(t, http.StatusUnauthorized, serve(router, http.MethodGet, "/items", http.Header{"X-Api-Key": {"mbk_unknown"}}).Code)
+	assert.Equal(t, 2, keys.lookups)
+
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/items", http.Header{"Authorization": {"Bearer " + token}}).Code)
+	assert.Equal(t, 1, revocations.checks)
+}
+
+func TestRateLimit_InvalidAPIKeysCountAgainstIP(t *testing.T) {
+	policy := RateLimitPolicy{Default: ratelimit.Limit{Requests: 2, Period: time.Minute}}
+	router := newRateLimitRouter(t, ratelimit.NewMemoryLimiter(), policy, nil, staticKeys{})
+
+	// A new made-up key per request still shares the client IP's bucket
+	for i := 0; i < 2; i++ {
+		w := serve(router, http.MethodPost, "/login", http.Header{"X-Api-Key": {"mbk_" + uuid.NewString()}})
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	w := serve(router, http.MethodPost, "/login", http.Header{"X-Api-Key": {"mbk_" + uuid.NewString()}})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, http.MethodGet, "/items", nil).Code)
+}
+
+func TestRateLimit_RouteAndRoleLimits(t *testing.T) {
+	jwtService := auth.NewJWTService("0123456789abcdef0123456789abcdef", "test", time.Hour)
+	adminToken, err := jwtService.GenerateToken(uuid.New(), "root", "root@example.com", "admin")
+	require.NoError(t, err)
+
+	policy := RateLimitPolicy{
+		Default: ratelimit.Limit{Requests: 5, Period: time.Minute},
+		Roles:   map[string]ratelimit.Limit{"admin": {Requests: 50, Period: time.Minute}},
+		Routes: []RouteRateLimit{
+			{Method: http.MethodPost, Path: "/login", Limit: ratelimit.Limit{Requests: 1, Period: time.Minute}},
+		},
+	}
+	router := newRateLimitRouter(t, ratelimit.NewMemoryLimiter(), policy, jwtService, nil)
+
+	w := serve(router, http.MethodPost, "/login", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, http.MethodPost, "/login", nil).Code)
+
+	// The route bucket is separate from the default one
+	w = serve(router, http.MethodGet, "/items", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "5", w.Header().Get("RateLimit-Limit"))
+
+	w = serve(router, http.MethodGet, "/items", http.Header{"Authorization": {"Bearer " + adminToken}})
+	assert.Equal(t, "50", w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimit_FailsOpen(t *testing.T) {
+	policy := RateLimitPolicy{Default: ratelimit.PerMinute(1)}
+	router := newRateLimitRouter(t, failingLimiter{}, policy, nil, nil)
+
+	w := serve(router, http.MethodGet, "/items", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestGRPCRateLimit_SharesHTTPBudget(t *testing.T) {
+	jwtService := auth.NewJWTService("0123456789abcdef0123456789abcdef", "test", time.Hour)
+	userID := uuid.New()
+	token, err := jwtService.GenerateToken(userID, "alice", "alice@example.com", "user")
+	require.NoError(t, err)
+
+	appLogger, err := logger.NewLogger(&configs.LoggingConfig{Level: "info", Output: "stdout"})
+	require.NoError(t, err)
+
+	limiter := ratelimit.NewMemoryLimiter()
+	policy := RateLimitPolicy{Default: ratelimit.Limit{Requests: 2, Period: time.Minute}}
+	router := newRateLimitRouter(t, limiter, policy, jwtService, staticKeys{})
+	unary, _ := GRPCRateLimit(limiter, policy, jwtService, staticKeys{}, appLogger)
+
+	call := func(md metadata.MD) codes.Code {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4321}})
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Get"}, func(context.Context, interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return status.Code(err)
+	}
+
+	// One HTTP request and one call use up the user's budget
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/items", http.Header{"Authorization": {"Bearer " + token}}).Code)
+	assert.Equal(t, codes.OK, call(metadata.Pairs("authorization", "Bearer "+token)))
+	assert.Equal(t, codes.ResourceExhausted, call(metadata.Pairs("authorization", "Bearer "+token)))
+
+	// Made-up keys are counted against the peer IP, shared with HTTP as well
+	assert.Equal(t, codes.OK, call(metadata.Pairs("x-api-key", "mbk_"+uuid.NewString())))
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/items", nil).Code)
+	assert.Equal(t, codes.ResourceExhausted, call(metadata.Pairs("x-api-key", "mbk_"+uuid.NewString())))
+}
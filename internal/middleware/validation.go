@@ -4,7 +4,6 @@ import (
 	"html"
 	"net/http"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -147,47 +146,6 @@ func containsSQLInjection(input string, patterns []*regexp.Regexp) bool {
 	return false
 }
 
-// RateLimit middleware provides basic rate limiting
-func RateLimit(maxRequests int, windowSeconds int) gin.HandlerFunc {
-	// Simple in-memory rate limiting (for production, use Redis)
-	requests := make(map[string][]time.Time)
-
-	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
-		now := time.Now()
-		window := time.Duration(windowSeconds) * time.Second
-
-		// Clean old requests outside the window
-		if clientRequests, exists := requests[clientIP]; exists {
-			var validRequests []time.Time
-			for _, reqTime := range clientRequests {
-				if now.Sub(reqTime) < window {
-					validRequests = append(validRequests, reqTime)
-				}
-			}
-			requests[clientIP] = validRequests
-		}
-
-		// Check if rate limit exceeded
-		if len(requests[clientIP]) >= maxRequests {
-			c.Header("X-RateLimit-Limit", strconv.Itoa(maxRequests))
-			c.Header("X-RateLimit-Remaining", "0")
-			response.TooManyRequests(c, "Rate limit exceeded")
-			c.Abort()
-			return
-		}
-
-		// Add current request
-		requests[clientIP] = append(requests[clientIP], now)
-
-		// Add rate limit headers
-		c.Header("X-RateLimit-Limit", strconv.Itoa(maxRequests))
-		c.Header("X-RateLimit-Remaining", strconv.Itoa(maxRequests-len(requests[clientIP])))
-
-		c.Next()
-	}
-}
-
 // SecurityHeaders middleware adds security headers to all responses
 func SecurityHeaders() gin.HandlerFunc {
 	return func(c *gin.Context) {
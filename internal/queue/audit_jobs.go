@@ -0,0 +1,127 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/audit"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// JobTypeAuditEntry is the job type that appends an entry to the audit log
+const JobTypeAuditEntry = "audit_entry"
+
+// AuditEntryHandler handles audit entry jobs
+type AuditEntryHandler struct {
+	auditService audit.Service
+	logger       logger.Logger
+}
+
+// NewAuditEntryHandler creates a new audit entry handler
+func NewAuditEntryHandler(auditService audit.Service, logger logger.Logger) *AuditEntryHandler {
+	return &AuditEntryHandler{
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// Handle processes an audit entry job
+func (h *AuditEntryHandler) Handle(ctx context.Context, job *Job) (*JobResult, error) {
+	e, err := auditEntryFromPayload(job.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.auditService.Append(ctx, e); err != nil {
+		return nil, fmt.Errorf("appending audit entry: %w", err)
+	}
+
+	return &JobResult{
+		Result: map[string]interface{}{
+			"entry_id": e.ID.String(),
+			"sequence": e.Sequence,
+		},
+	}, nil
+}
+
+// Name returns the handler name
+func (h *AuditEntryHandler) Name() string {
+	return "AuditEntryHandler"
+}
+
+// JobType returns the job type this handler processes
+func (h *AuditEntryHandler) JobType() string {
+	return JobTypeAuditEntry
+}
+
+// CreateAuditEntryJob creates a job that appends e to the audit log. It
+// runs ahead of ordinary work so the log stays close to real time.
+func (f *JobFactory) CreateAuditEntryJob(e *audit.Entry) *Job {
+	payload := map[string]interface{}{
+		"entry_id":         e.ID.String(),
+		"actor_type":       e.Actor.Type,
+		"actor_role":       e.Actor.Role,
+		"actor_api_key_id": e.Actor.APIKeyID,
+		"action":           e.Action,
+		"resource_type":    e.ResourceType,
+		"resource_id":      e.ResourceID,
+		"request_id":       e.RequestID,
+		"before_hash":      e.BeforeHash,
+		"after_hash":       e.AfterHash,
+		"created_at":       e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if !e.Actor.UserID.IsZero() {
+		payload["actor_user_id"] = e.Actor.UserID.String()
+	}
+
+	return &Job{
+		Type:      JobTypeAuditEntry,
+		Priority:  7,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}
+}
+
+func auditEntryFromPayload(payload map[string]interface{}) (*audit.Entry, error) {
+	str := func(key string) string {
+		s, _ := payload[key].(string)
+		return s
+	}
+
+	id, err := uuid.Parse(str("entry_id"))
+	if err != nil {
+		return nil, fmt.Errorf("missing or invalid entry_id in job payload: %w", err)
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, str("created_at"))
+	if err != nil {
+		return nil, fmt.Errorf("missing or invalid created_at in job payload: %w", err)
+	}
+
+	e := &audit.Entry{
+		ID: audit.ID(id),
+		Actor: audit.Actor{
+			Type:     str("actor_type"),
+			Role:     str("actor_role"),
+			APIKeyID: str("actor_api_key_id"),
+		},
+		Action:       str("action"),
+		ResourceType: str("resource_type"),
+		ResourceID:   str("resource_id"),
+		RequestID:    str("request_id"),
+		BeforeHash:   str("before_hash"),
+		AfterHash:    str("after_hash"),
+		CreatedAt:    createdAt,
+	}
+	if actorID := str("actor_user_id"); actorID != "" {
+		parsed, err := uuid.Parse(actorID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid actor_user_id in job payload: %w", err)
+		}
+		e.Actor.UserID = user.ID(parsed)
+	}
+	return e, nil
+}
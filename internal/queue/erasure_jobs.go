@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/erasure"
+	"mem_bank/pkg/logger"
+)
+
+// JobTypeEraseUser is the job type that wipes a user's data
+const JobTypeEraseUser = "erase_user"
+
+// EraseUserHandler handles user erasure jobs
+type EraseUserHandler struct {
+	erasureService erasure.Service
+	logger         logger.Logger
+}
+
+// NewEraseUserHandler creates a new user erasure handler
+func NewEraseUserHandler(erasureService erasure.Service, logger logger.Logger) *EraseUserHandler {
+	return &EraseUserHandler{
+		erasureService: erasureService,
+		logger:         logger,
+	}
+}
+
+// Handle processes a user erasure job. Only the erasure ID is reported back,
+// since the job result outlives the data it would otherwise describe.
+func (h *EraseUserHandler) Handle(ctx context.Context, job *Job) (*JobResult, error) {
+	erasureIDStr, ok := job.Payload["erasure_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid erasure_id in job payload")
+	}
+
+	id, err := uuid.Parse(erasureIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid erasure ID: %w", err)
+	}
+
+	e, err := h.erasureService.Erase(ctx, erasure.ID(id))
+	if err != nil {
+		return nil, fmt.Errorf("erasing user: %w", err)
+	}
+
+	return &JobResult{
+		Result: map[string]interface{}{
+			"erasure_id": e.ID.String(),
+		},
+	}, nil
+}
+
+// Name returns the handler name
+func (h *EraseUserHandler) Name() string {
+	return "EraseUserHandler"
+}
+
+// JobType returns the job type this handler processes
+func (h *EraseUserHandler) JobType() string {
+	return JobTypeEraseUser
+}
+
+// CreateEraseUserJob creates a job that wipes the data of an erasure's user.
+// It runs ahead of ordinary work so no new jobs are spent on a departing user.
+func (f *JobFactory) CreateEraseUserJob(e *erasure.Erasure) *Job {
+	return &Job{
+		Type:     JobTypeEraseUser,
+		Priority: 9,
+		Payload: map[string]interface{}{
+			"erasure_id": e.ID.String(),
+			"user_id":    e.UserID.String(),
+		},
+		CreatedAt: time.Now(),
+	}
+}
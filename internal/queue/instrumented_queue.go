@@ -0,0 +1,205 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/metrics"
+)
+
+// statsProvider is implemented by backends that can report queue statistics
+type statsProvider interface {
+	GetStats(ctx context.Context) (*Stats, error)
+}
+
+// InstrumentedQueue wraps a Queue and records Prometheus metrics for every
+// job that passes through it. When Config.StatsEnabled is set it also
+// publishes depth and oldest-job age per priority band every
+// Config.StatsUpdateInterval.
+type InstrumentedQueue struct {
+	Queue
+	logger   logger.Logger
+	config   Config
+	stopChan chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewInstrumentedQueue wraps queue with metrics collection
+func NewInstrumentedQueue(queue Queue, logger logger.Logger, config Config) *InstrumentedQueue {
+	if config.StatsUpdateInterval == 0 {
+		config.StatsUpdateInterval = 10 * time.Second
+	}
+
+	return &InstrumentedQueue{
+		Queue:    queue,
+		logger:   logger,
+		config:   config,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Enqueue adds a job to the queue and counts it
+func (q *InstrumentedQueue) Enqueue(ctx context.Context, job *Job) error {
+	if err := q.Queue.Enqueue(ctx, job); err != nil {
+		return err
+	}
+	metrics.QueueJobsEnqueued.WithLabelValues(job.Type).Inc()
+	return nil
+}
+
+// EnqueueBatch adds multiple jobs to the queue and counts them
+func (q *InstrumentedQueue) EnqueueBatch(ctx context.Context, jobs []*Job) error {
+	if err := q.Queue.EnqueueBatch(ctx, jobs); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		metrics.QueueJobsEnqueued.WithLabelValues(job.Type).Inc()
+	}
+	return nil
+}
+
+// RegisterHandler registers handler wrapped with metrics collection
+func (q *InstrumentedQueue) RegisterHandler(jobType string, handler JobHandler) {
+	q.Queue.RegisterHandler(jobType, &instrumentedHandler{JobHandler: handler})
+}
+
+// StartConsuming starts the underlying consumer and the stats reporter
+func (q *InstrumentedQueue) StartConsuming(ctx context.Context, concurrency int) error {
+	if err := q.Queue.StartConsuming(ctx, concurrency); err != nil {
+		return err
+	}
+
+	if _, ok := q.Queue.(statsProvider); ok && q.config.StatsEnabled {
+		q.wg.Add(1)
+		go q.reportStats(ctx)
+	}
+
+	return nil
+}
+
+// StopConsuming stops the stats reporter and the underlying consumer
+func (q *InstrumentedQueue) StopConsuming() error {
+	q.stopOnce.Do(func() { close(q.stopChan) })
+	q.wg.Wait()
+	return q.Queue.StopConsuming()
+}
+
+// Close stops the stats reporter and closes the underlying queue
+func (q *InstrumentedQueue) Close() error {
+	q.stopOnce.Do(func() { close(q.stopChan) })
+	q.wg.Wait()
+	return q.Queue.Close()
+}
+
+// GetStats returns statistics from the underlying queue
+func (q *InstrumentedQueue) GetStats(ctx context.Context) (*Stats, error) {
+	provider, ok := q.Queue.(statsProvider)
+	if !ok {
+		return nil, fmt.Errorf("queue backend does not report statistics")
+	}
+	return provider.GetStats(ctx)
+}
+
+// GetFailedJobs returns failed jobs from the underlying queue
+func (q *InstrumentedQueue) GetFailedJobs(ctx context.Context, limit, offset int) ([]*Job, error) {
+	monitor, ok := q.Queue.(Monitor)
+	if !ok {
+		return nil, fmt.Errorf("queue backend does not support monitoring")
+	}
+	return monitor.GetFailedJobs(ctx, limit, offset)
+}
+
+// RetryFailedJob retries a failed job on the underlying queue
+func (q *InstrumentedQueue) RetryFailedJob(ctx context.Context, jobID string) error {
+	monitor, ok := q.Queue.(Monitor)
+	if !ok {
+		return fmt.Errorf("queue backend does not support monitoring")
+	}
+	return monitor.RetryFailedJob(ctx, jobID)
+}
+
+// PurgeCompletedJobs purges completed jobs on the underlying queue
+func (q *InstrumentedQueue) PurgeCompletedJobs(ctx context.Context, olderThan time.Duration) (int64, error) {
+	monitor, ok := q.Queue.(Monitor)
+	if !ok {
+		return 0, fmt.Errorf("queue backend does not support monitoring")
+	}
+	return monitor.PurgeCompletedJobs(ctx, olderThan)
+}
+
+// PurgeJobs removes matching jobs from the underlying queue
+func (q *InstrumentedQueue) PurgeJobs(ctx context.Context, match func(*Job) bool) (int64, error) {
+	purger, ok := q.Queue.(Purger)
+	if !ok {
+		return 0, fmt.Errorf("queue backend does not support purging jobs")
+	}
+	return purger.PurgeJobs(ctx, match)
+}
+
+// reportStats periodically publishes queue depth and oldest-job age
+func (q *InstrumentedQueue) reportStats(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.config.StatsUpdateInterval)
+	defer ticker.Stop()
+
+	q.publishStats(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopChan:
+			return
+		case <-ticker.C:
+			q.publishStats(ctx)
+		}
+	}
+}
+
+// publishStats copies the current per-band statistics into the gauges
+func (q *InstrumentedQueue) publishStats(ctx context.Context) {
+	stats, err := q.GetStats(ctx)
+	if err != nil {
+		q.logger.WithError(err).Warn("Failed to collect queue stats")
+		return
+	}
+
+	for _, band := range PriorityBands {
+		bandStats := stats.Bands[band]
+		metrics.QueueDepth.WithLabelValues(band).Set(float64(bandStats.Depth))
+		metrics.QueueOldestJobAge.WithLabelValues(band).Set(bandStats.OldestJobAge.Seconds())
+	}
+}
+
+// instrumentedHandler records start, outcome and latency of a JobHandler
+type instrumentedHandler struct {
+	JobHandler
+}
+
+// Handle runs the wrapped handler and records its outcome. A failure counts as
+// retried while the job still has retries left, mirroring the backends.
+func (h *instrumentedHandler) Handle(ctx context.Context, job *Job) (*JobResult, error) {
+	metrics.QueueJobsStarted.WithLabelValues(job.Type).Inc()
+	start := time.Now()
+
+	result, err := h.JobHandler.Handle(ctx, job)
+
+	status := metrics.StatusSuccess
+	switch {
+	case err == nil:
+		metrics.QueueJobsSucceeded.WithLabelValues(job.Type).Inc()
+	case job.Retries+1 < job.MaxRetries:
+		status = metrics.StatusError
+		metrics.QueueJobsRetried.WithLabelValues(job.Type).Inc()
+	default:
+		status = metrics.StatusError
+		metrics.QueueJobsFailed.WithLabelValues(job.Type).Inc()
+	}
+	metrics.QueueHandlerDuration.WithLabelValues(job.Type, status).Observe(time.Since(start).Seconds())
+
+	return result, err
+}
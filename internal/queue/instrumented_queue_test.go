@@ -0,0 +1,70 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/pkg/metrics"
+)
+
+func TestInstrumentedQueue_RecordsJobOutcomes(t *testing.T) {
+	ctx := context.Background()
+	log := newTestLogger(t)
+
+	config := Config{
+		MaxRetries:          2,
+		RetryDelay:          10 * time.Millisecond,
+		PollInterval:        10 * time.Millisecond,
+		StatsEnabled:        true,
+		StatsUpdateInterval: 10 * time.Millisecond,
+	}
+	q := NewInstrumentedQueue(NewMemoryQueue(log, config), log, config)
+	defer q.Close()
+
+	const jobType = "instrumented_test"
+	// Fails twice: the first failure is retried, the second exhausts the budget
+	failing := newRecordingHandler(jobType, 2)
+	q.RegisterHandler(jobType, failing)
+
+	require.NoError(t, q.Enqueue(ctx, &Job{ID: "fails", Type: jobType}))
+	require.NoError(t, q.StartConsuming(ctx, 1))
+	waitForJobs(t, failing, 2)
+
+	require.NoError(t, q.RetryFailedJob(ctx, "fails"))
+	waitForJobs(t, failing, 1)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.QueueJobsEnqueued.WithLabelValues(jobType)))
+	assert.Equal(t, float64(3), testutil.ToFloat64(metrics.QueueJobsStarted.WithLabelValues(jobType)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.QueueJobsRetried.WithLabelValues(jobType)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.QueueJobsFailed.WithLabelValues(jobType)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.QueueJobsSucceeded.WithLabelValues(jobType)))
+
+	stats, err := q.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Len(t, stats.Bands, len(PriorityBands))
+}
+
+func TestMemoryQueue_BandStats(t *testing.T) {
+	ctx := context.Background()
+	q := newTestMemoryQueue(t)
+
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, q.EnqueueBatch(ctx, []*Job{
+		{Type: "test", Priority: 9, CreatedAt: old},
+		{Type: "test", Priority: 8},
+		{Type: "test", Priority: 1},
+	}))
+
+	stats, err := q.GetStats(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), stats.Bands[PriorityBandHigh].Depth)
+	assert.GreaterOrEqual(t, stats.Bands[PriorityBandHigh].OldestJobAge, time.Minute)
+	assert.Equal(t, int64(0), stats.Bands[PriorityBandNormal].Depth)
+	assert.Equal(t, int64(1), stats.Bands[PriorityBandLow].Depth)
+}
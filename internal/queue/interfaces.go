@@ -98,6 +98,15 @@ type Stats struct {
 	CompletedJobs  int64 `json:"completed_jobs"`
 	FailedJobs     int64 `json:"failed_jobs"`
 	TotalJobs      int64 `json:"total_jobs"`
+
+	// Bands breaks waiting jobs down by priority band, when the backend supports it
+	Bands map[string]BandStats `json:"bands,omitempty"`
+}
+
+// BandStats describes the jobs waiting in one priority band
+type BandStats struct {
+	Depth        int64         `json:"depth"`
+	OldestJobAge time.Duration `json:"oldest_job_age"`
 }
 
 // Monitor defines the interface for queue monitoring
@@ -115,8 +124,49 @@ type Monitor interface {
 	PurgeCompletedJobs(ctx context.Context, olderThan time.Duration) (int64, error)
 }
 
+// Purger defines the interface for removing jobs by content, e.g. every job
+// that refers to a user whose data is being erased
+type Purger interface {
+	// PurgeJobs removes waiting, retrying and failed jobs for which match
+	// returns true, together with the stored details and results of any job
+	// that matches. Jobs already being processed run to completion.
+	PurgeJobs(ctx context.Context, match func(*Job) bool) (int64, error)
+}
+
+// Queue backends selectable through Config.Backend
+const (
+	BackendRedis        = "redis"
+	BackendRedisStreams = "redis_streams"
+	BackendMemory       = "memory"
+)
+
+// Priority bands used by backends that cannot order jobs by raw priority
+const (
+	PriorityBandHigh   = "high"
+	PriorityBandNormal = "normal"
+	PriorityBandLow    = "low"
+)
+
+// PriorityBands lists the priority bands from highest to lowest
+var PriorityBands = []string{PriorityBandHigh, PriorityBandNormal, PriorityBandLow}
+
+// PriorityBand maps a job priority to its priority band
+func PriorityBand(priority int) string {
+	switch {
+	case priority >= 7:
+		return PriorityBandHigh
+	case priority >= 4:
+		return PriorityBandNormal
+	default:
+		return PriorityBandLow
+	}
+}
+
 // Config holds queue configuration
 type Config struct {
+	// Backend selects the queue implementation (redis, redis_streams, memory)
+	Backend string `mapstructure:"backend"`
+
 	// Redis connection settings
 	RedisAddr     string `mapstructure:"redis_addr"`
 	RedisPassword string `mapstructure:"redis_password"`
@@ -134,6 +184,12 @@ type Config struct {
 	DefaultConcurrency int           `mapstructure:"default_concurrency"`
 	PollInterval       time.Duration `mapstructure:"poll_interval"`
 
+	// Redis Streams settings
+	ConsumerGroup string        `mapstructure:"consumer_group"`
+	ConsumerName  string        `mapstructure:"consumer_name"`
+	ClaimMinIdle  time.Duration `mapstructure:"claim_min_idle"`
+	StreamMaxLen  int64         `mapstructure:"stream_max_len"`
+
 	// Monitoring settings
 	StatsEnabled        bool          `mapstructure:"stats_enabled"`
 	StatsUpdateInterval time.Duration `mapstructure:"stats_update_interval"`
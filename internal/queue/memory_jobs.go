@@ -10,6 +10,8 @@ import (
 	"mem_bank/internal/domain/memory"
 	"mem_bank/internal/domain/user"
 	"mem_bank/internal/service/embedding"
+	"mem_bank/pkg/database"
+	"mem_bank/pkg/llm"
 	"mem_bank/pkg/logger"
 )
 
@@ -49,6 +51,9 @@ func (h *GenerateEmbeddingHandler) Handle(ctx context.Context, job *Job) (*JobRe
 		return nil, fmt.Errorf("invalid memory ID: %w", err)
 	}
 
+	// The job only names a memory, so it runs outside any one user's rows
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+
 	// Retrieve memory from repository
 	mem, err := h.memoryRepo.FindByID(ctx, memoryID)
 	if err != nil {
@@ -56,13 +61,13 @@ func (h *GenerateEmbeddingHandler) Handle(ctx context.Context, job *Job) (*JobRe
 	}
 
 	// Generate embedding for memory content
-	embeddingResult, err := h.embeddingService.GenerateEmbedding(ctx, mem.Content)
+	embeddingResult, err := h.embeddingService.GenerateEmbedding(llm.WithUserID(ctx, mem.UserID.String()), mem.Content)
 	if err != nil {
 		return nil, fmt.Errorf("generating embedding: %w", err)
 	}
 
 	// Update memory with embedding
-	mem.UpdateEmbedding(embeddingResult.Embedding)
+	mem.UpdateEmbedding(embeddingResult.Embedding, embeddingResult.Model)
 
 	// Save updated memory
 	if err := h.memoryRepo.Update(ctx, mem); err != nil {
@@ -113,9 +118,11 @@ func NewBatchEmbeddingHandler(embeddingService *embedding.Service, memoryRepo me
 	}
 }
 
-// Handle processes a batch embedding generation job
+// Handle processes a batch embedding generation job. It pages through every
+// memory of the user that lacks an embedding from the current model, so one
+// job covers the whole backlog; "limit" sets the page size.
 func (h *BatchEmbeddingHandler) Handle(ctx context.Context, job *Job) (*JobResult, error) {
-	// Extract user ID and limit from payload
+	// Extract user ID and page size from payload
 	userIDStr, ok := job.Payload["user_id"].(string)
 	if !ok {
 		return nil, fmt.Errorf("missing or invalid user_id in job payload")
@@ -127,17 +134,79 @@ func (h *BatchEmbeddingHandler) Handle(ctx context.Context, job *Job) (*JobResul
 	}
 
 	limit := 100 // Default batch size
-	if limitFloat, ok := job.Payload["limit"].(float64); ok {
-		limit = int(limitFloat)
+	switch v := job.Payload["limit"].(type) {
+	case float64:
+		limit = int(v)
+	case int:
+		limit = v
+	}
+	if limit <= 0 {
+		limit = 100
 	}
 
-	// Find memories without embeddings
-	memories, err := h.findMemoriesWithoutEmbeddings(ctx, userID, limit)
-	if err != nil {
-		return nil, fmt.Errorf("finding memories without embeddings: %w", err)
+	model := h.embeddingService.Model()
+	ctx = llm.WithUserID(ctx, userID.String())
+	ctx = database.WithTenant(ctx, database.Tenant{UserID: userID.String()})
+
+	var (
+		cursor        memory.ID
+		totalMemories int
+		updatedCount  int
+		pages         int
+		totalUsage    llm.Usage
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			// Already processed pages are persisted; a retry resumes with the remainder
+			return nil, fmt.Errorf("batch embedding interrupted after %d memories: %w", updatedCount, err)
+		}
+
+		memories, err := h.memoryRepo.FindWithoutEmbedding(ctx, userID, model, limit, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("finding memories without embeddings: %w", err)
+		}
+		if len(memories) == 0 {
+			break
+		}
+
+		pages++
+		totalMemories += len(memories)
+		cursor = memories[len(memories)-1].ID
+
+		// Extract content for batch embedding generation
+		texts := make([]string, len(memories))
+		for i, mem := range memories {
+			texts[i] = mem.Content
+		}
+
+		// Generate embeddings in batch
+		batchResult, err := h.embeddingService.GenerateEmbeddings(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("generating batch embeddings: %w", err)
+		}
+		totalUsage.PromptTokens += batchResult.Usage.PromptTokens
+		totalUsage.CompletionTokens += batchResult.Usage.CompletionTokens
+		totalUsage.TotalTokens += batchResult.Usage.TotalTokens
+
+		// Update memories with embeddings
+		for i, mem := range memories {
+			if i < len(batchResult.Results) {
+				mem.UpdateEmbedding(batchResult.Results[i].Embedding, batchResult.Results[i].Model)
+				if err := h.memoryRepo.Update(ctx, mem); err != nil {
+					h.logger.WithError(err).WithField("memory_id", mem.ID.String()).Error("Failed to update memory with embedding")
+					continue
+				}
+				updatedCount++
+			}
+		}
+
+		if len(memories) < limit {
+			break
+		}
 	}
 
-	if len(memories) == 0 {
+	if totalMemories == 0 {
 		return &JobResult{
 			Result: map[string]interface{}{
 				"processed_count": 0,
@@ -146,43 +215,20 @@ func (h *BatchEmbeddingHandler) Handle(ctx context.Context, job *Job) (*JobResul
 		}, nil
 	}
 
-	// Extract content for batch embedding generation
-	texts := make([]string, len(memories))
-	for i, mem := range memories {
-		texts[i] = mem.Content
-	}
-
-	// Generate embeddings in batch
-	batchResult, err := h.embeddingService.GenerateEmbeddings(ctx, texts)
-	if err != nil {
-		return nil, fmt.Errorf("generating batch embeddings: %w", err)
-	}
-
-	// Update memories with embeddings
-	updatedCount := 0
-	for i, mem := range memories {
-		if i < len(batchResult.Results) {
-			mem.UpdateEmbedding(batchResult.Results[i].Embedding)
-			if err := h.memoryRepo.Update(ctx, mem); err != nil {
-				h.logger.WithError(err).WithField("memory_id", mem.ID.String()).Error("Failed to update memory with embedding")
-				continue
-			}
-			updatedCount++
-		}
-	}
-
 	h.logger.WithFields(map[string]interface{}{
 		"user_id":         userID.String(),
 		"processed_count": updatedCount,
-		"total_usage":     batchResult.Usage,
+		"pages":           pages,
+		"total_usage":     totalUsage,
 	}).Info("Batch embeddings generated and updated")
 
 	return &JobResult{
 		Result: map[string]interface{}{
 			"user_id":         userID.String(),
 			"processed_count": updatedCount,
-			"total_memories":  len(memories),
-			"token_usage":     batchResult.Usage,
+			"total_memories":  totalMemories,
+			"pages":           pages,
+			"token_usage":     totalUsage,
 		},
 	}, nil
 }
@@ -197,29 +243,6 @@ func (h *BatchEmbeddingHandler) JobType() string {
 	return JobTypeBatchEmbedding
 }
 
-// findMemoriesWithoutEmbeddings finds memories that don't have embeddings yet
-func (h *BatchEmbeddingHandler) findMemoriesWithoutEmbeddings(ctx context.Context, userID user.ID, limit int) ([]*memory.Memory, error) {
-	// This is a simplified implementation. In practice, you might want to add
-	// a specific method to the repository to efficiently find memories without embeddings
-	memories, err := h.memoryRepo.FindByUserID(ctx, userID, limit*2, 0) // Get more than we need
-	if err != nil {
-		return nil, err
-	}
-
-	// Filter out memories that already have embeddings
-	result := make([]*memory.Memory, 0, limit)
-	for _, mem := range memories {
-		if len(mem.Embedding) == 0 {
-			result = append(result, mem)
-			if len(result) >= limit {
-				break
-			}
-		}
-	}
-
-	return result, nil
-}
-
 // Helper functions
 func parseMemoryID(idStr string) (memory.ID, error) {
 	id, err := uuid.Parse(idStr)
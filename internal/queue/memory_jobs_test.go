@@ -0,0 +1,261 @@
+package queue
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/service/embedding"
+	"mem_bank/pkg/llm"
+)
+
+// fakeEmbeddingProvider returns a deterministic embedding per input and
+// records the users calls are attributed to
+type fakeEmbeddingProvider struct {
+	mu    sync.Mutex
+	calls int
+	users []string
+}
+
+func (p *fakeEmbeddingProvider) GenerateEmbeddings(ctx context.Context, req *llm.EmbeddingRequest) (*llm.EmbeddingResponse, error) {
+	p.mu.Lock()
+	p.calls++
+	p.users = append(p.users, llm.UserIDFromContext(ctx))
+	p.mu.Unlock()
+
+	embeddings := make([][]float32, len(req.Input))
+	for i, text := range req.Input {
+		embeddings[i] = []float32{float32(len(text)), 1, 0}
+	}
+	return &llm.EmbeddingResponse{
+		Embeddings: embeddings,
+		Model:      p.GetDefaultModel(),
+		Usage:      llm.Usage{PromptTokens: len(req.Input), TotalTokens: len(req.Input)},
+	}, nil
+}
+
+func (p *fakeEmbeddingProvider) GetEmbeddingDimension(model string) int { return 3 }
+func (p *fakeEmbeddingProvider) GetDefaultModel() string                { return "fake-embedding" }
+
+// fakeMemoryRepository keeps memories in a map; methods the handlers do not use
+// fall through to the embedded nil interface and panic if called
+type fakeMemoryRepository struct {
+	memory.Repository
+
+	mu       sync.Mutex
+	memories map[memory.ID]*memory.Memory
+	order    []memory.ID
+}
+
+func newFakeMemoryRepository(memories ...*memory.Memory) *fakeMemoryRepository {
+	r := &fakeMemoryRepository{memories: make(map[memory.ID]*memory.Memory)}
+	for _, m := range memories {
+		r.memories[m.ID] = m
+		r.order = append(r.order, m.ID)
+	}
+	return r
+}
+
+func (r *fakeMemoryRepository) FindByID(ctx context.Context, id memory.ID) (*memory.Memory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.memories[id]
+	if !ok {
+		return nil, memory.ErrNotFound
+	}
+	c := *m
+	return &c, nil
+}
+
+func (r *fakeMemoryRepository) FindByUserID(ctx context.Context, userID user.ID, limit, offset int) ([]*memory.Memory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*memory.Memory
+	for _, id := range r.order {
+		m := r.memories[id]
+		if m.UserID != userID {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		c := *m
+		result = append(result, &c)
+		if len(result) >= limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeMemoryRepository) FindWithoutEmbedding(ctx context.Context, userID user.ID, model string, limit int, cursor memory.ID) ([]*memory.Memory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*memory.Memory
+	for _, m := range r.memories {
+		if m.UserID != userID || !cursorBefore(cursor, m.ID) {
+			continue
+		}
+		if len(m.Embedding) > 0 && (model == "" || m.EmbeddingModel == model) {
+			continue
+		}
+		c := *m
+		result = append(result, &c)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].ID.String() < result[j].ID.String() })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func cursorBefore(cursor, id memory.ID) bool {
+	return cursor.IsZero() || cursor.String() < id.String()
+}
+
+func (r *fakeMemoryRepository) Update(ctx context.Context, m *memory.Memory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.memories[m.ID]; !ok {
+		return memory.ErrNotFound
+	}
+	c := *m
+	r.memories[m.ID] = &c
+	return nil
+}
+
+func (r *fakeMemoryRepository) get(id memory.ID) *memory.Memory {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.memories[id]
+}
+
+func newTestMemory(userID user.ID, content string) *memory.Memory {
+	return &memory.Memory{
+		ID:        memory.ID(uuid.New()),
+		UserID:    userID,
+		Content:   content,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestGenerateEmbeddingHandler_ThroughMemoryQueue(t *testing.T) {
+	ctx := context.Background()
+	log := newTestLogger(t)
+
+	userID := user.ID(uuid.New())
+	mem := newTestMemory(userID, "remember the milk")
+	repo := newFakeMemoryRepository(mem)
+	embeddingSvc := embedding.NewService(&fakeEmbeddingProvider{}, nil, log, embedding.Config{})
+
+	q := newTestMemoryQueue(t)
+	q.RegisterHandler(JobTypeGenerateEmbedding, NewGenerateEmbeddingHandler(embeddingSvc, repo, log))
+	require.NoError(t, q.StartConsuming(ctx, 1))
+
+	job := NewJobFactory().CreateGenerateEmbeddingJob(mem.ID, 5)
+	require.NoError(t, q.Enqueue(ctx, job))
+
+	require.Eventually(t, func() bool {
+		result, err := q.GetJobResult(ctx, job.ID)
+		return err == nil && result.Status == JobStatusCompleted
+	}, 2*time.Second, 5*time.Millisecond)
+
+	updated := repo.get(mem.ID)
+	require.NotNil(t, updated)
+	assert.Len(t, updated.Embedding, 3)
+}
+
+func TestGenerateEmbeddingHandler_InvalidPayload(t *testing.T) {
+	log := newTestLogger(t)
+	embeddingSvc := embedding.NewService(&fakeEmbeddingProvider{}, nil, log, embedding.Config{})
+	handler := NewGenerateEmbeddingHandler(embeddingSvc, newFakeMemoryRepository(), log)
+
+	_, err := handler.Handle(context.Background(), &Job{Payload: map[string]interface{}{}})
+	assert.Error(t, err)
+
+	_, err = handler.Handle(context.Background(), &Job{Payload: map[string]interface{}{"memory_id": "not-a-uuid"}})
+	assert.Error(t, err)
+
+	_, err = handler.Handle(context.Background(), &Job{Payload: map[string]interface{}{"memory_id": uuid.New().String()}})
+	assert.Error(t, err)
+}
+
+func TestBatchEmbeddingHandler_SkipsEmbeddedMemories(t *testing.T) {
+	ctx := context.Background()
+	log := newTestLogger(t)
+
+	userID := user.ID(uuid.New())
+	embedded := newTestMemory(userID, "already embedded")
+	embedded.UpdateEmbedding([]float32{1, 2, 3}, "fake-embedding")
+	outdated := newTestMemory(userID, "embedded by an older model")
+	outdated.UpdateEmbedding([]float32{9, 9}, "old-model")
+	pending1 := newTestMemory(userID, "first")
+	pending2 := newTestMemory(userID, "second")
+	other := newTestMemory(user.ID(uuid.New()), "someone else")
+
+	repo := newFakeMemoryRepository(embedded, outdated, pending1, pending2, other)
+	provider := &fakeEmbeddingProvider{}
+	embeddingSvc := embedding.NewService(provider, nil, log, embedding.Config{})
+	handler := NewBatchEmbeddingHandler(embeddingSvc, repo, log)
+
+	job := NewJobFactory().CreateBatchEmbeddingJob(userID, 10, 3)
+	result, err := handler.Handle(ctx, job)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Result["processed_count"])
+	assert.Len(t, repo.get(outdated.ID).Embedding, 3)
+	assert.Equal(t, "fake-embedding", repo.get(outdated.ID).EmbeddingModel)
+	assert.Len(t, repo.get(pending1.ID).Embedding, 3)
+	assert.Len(t, repo.get(pending2.ID).Embedding, 3)
+	assert.Empty(t, repo.get(other.ID).Embedding)
+	assert.Equal(t, []float32{1, 2, 3}, repo.get(embedded.ID).Embedding)
+
+	// Provider calls count against the budget of the job's user
+	require.NotEmpty(t, provider.users)
+	for _, attributed := range provider.users {
+		assert.Equal(t, userID.String(), attributed)
+	}
+}
+
+func TestBatchEmbeddingHandler_PagesThroughBacklog(t *testing.T) {
+	ctx := context.Background()
+	log := newTestLogger(t)
+
+	userID := user.ID(uuid.New())
+	memories := make([]*memory.Memory, 25)
+	for i := range memories {
+		memories[i] = newTestMemory(userID, "backlog memory")
+	}
+
+	repo := newFakeMemoryRepository(memories...)
+	embeddingSvc := embedding.NewService(&fakeEmbeddingProvider{}, nil, log, embedding.Config{})
+	handler := NewBatchEmbeddingHandler(embeddingSvc, repo, log)
+
+	result, err := handler.Handle(ctx, NewJobFactory().CreateBatchEmbeddingJob(userID, 10, 3))
+	require.NoError(t, err)
+
+	assert.Equal(t, 25, result.Result["processed_count"])
+	assert.Equal(t, 3, result.Result["pages"])
+	for _, m := range memories {
+		assert.NotEmpty(t, repo.get(m.ID).Embedding)
+	}
+
+	remaining, err := repo.FindWithoutEmbedding(ctx, userID, embeddingSvc.Model(), 100, memory.ID{})
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
@@ -0,0 +1,656 @@
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/pkg/logger"
+)
+
+// MemoryQueue implements the Queue and Monitor interfaces in process.
+//
+// It keeps the semantics of RedisQueue (highest priority first, delayed retries
+// up to MaxRetries, results kept for ResultTTL) without any external
+// dependency, which makes it suitable for local development, embedded use and
+// tests. Jobs are lost when the process exits.
+type MemoryQueue struct {
+	logger   logger.Logger
+	config   Config
+	handlers map[string]JobHandler
+
+	mu         sync.Mutex
+	pending    jobHeap
+	sequence   uint64
+	jobs       map[string]*memoryEntry
+	results    map[string]*memoryEntry
+	failed     map[string]time.Time
+	delayed    int64
+	processing int64
+	completed  int64
+
+	handlersMu sync.RWMutex
+	wake       chan struct{}
+	stopChan   chan struct{}
+	stopOnce   sync.Once
+	wg         sync.WaitGroup
+}
+
+// memoryEntry holds a stored job or result together with its expiry
+type memoryEntry struct {
+	job       *Job
+	result    *JobResult
+	expiresAt time.Time
+}
+
+// NewMemoryQueue creates a new in-process queue
+func NewMemoryQueue(logger logger.Logger, config Config) *MemoryQueue {
+	// Set defaults
+	if config.QueueName == "" {
+		config.QueueName = "mem_bank_jobs"
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = 30 * time.Second
+	}
+	if config.JobTimeout == 0 {
+		config.JobTimeout = 5 * time.Minute
+	}
+	if config.ResultTTL == 0 {
+		config.ResultTTL = 24 * time.Hour
+	}
+	if config.DefaultConcurrency == 0 {
+		config.DefaultConcurrency = 5
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 1 * time.Second
+	}
+	if config.CleanupInterval == 0 {
+		config.CleanupInterval = 1 * time.Hour
+	}
+
+	return &MemoryQueue{
+		logger:   logger,
+		config:   config,
+		handlers: make(map[string]JobHandler),
+		jobs:     make(map[string]*memoryEntry),
+		results:  make(map[string]*memoryEntry),
+		failed:   make(map[string]time.Time),
+		wake:     make(chan struct{}, 1),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Enqueue adds a job to the queue
+func (q *MemoryQueue) Enqueue(ctx context.Context, job *Job) error {
+	q.prepareJob(job)
+
+	q.mu.Lock()
+	q.push(job)
+	q.mu.Unlock()
+
+	q.notify()
+
+	q.logger.WithFields(map[string]interface{}{
+		"job_id":   job.ID,
+		"job_type": job.Type,
+		"priority": job.Priority,
+	}).Info("Job enqueued")
+
+	return nil
+}
+
+// EnqueueBatch adds multiple jobs to the queue
+func (q *MemoryQueue) EnqueueBatch(ctx context.Context, jobs []*Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	for _, job := range jobs {
+		q.prepareJob(job)
+		q.push(job)
+	}
+	q.mu.Unlock()
+
+	q.notify()
+
+	q.logger.WithField("count", len(jobs)).Info("Jobs batch enqueued")
+	return nil
+}
+
+// GetJob retrieves a job by ID
+func (q *MemoryQueue) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entry, ok := q.jobs[jobID]
+	if !ok || entry.expired(time.Now()) {
+		return nil, fmt.Errorf("job not found: %s", jobID)
+	}
+
+	return copyJob(entry.job), nil
+}
+
+// GetJobResult retrieves the result of a processed job
+func (q *MemoryQueue) GetJobResult(ctx context.Context, jobID string) (*JobResult, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entry, ok := q.results[jobID]
+	if !ok || entry.expired(time.Now()) {
+		return nil, fmt.Errorf("job result not found: %s", jobID)
+	}
+
+	result := *entry.result
+	return &result, nil
+}
+
+// StartConsuming starts consuming jobs from the queue
+func (q *MemoryQueue) StartConsuming(ctx context.Context, concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = q.config.DefaultConcurrency
+	}
+
+	q.logger.WithField("concurrency", concurrency).Info("Starting in-memory job consumer")
+
+	for i := 0; i < concurrency; i++ {
+		q.wg.Add(1)
+		go q.worker(ctx, i)
+	}
+
+	q.wg.Add(1)
+	go q.cleanup(ctx)
+
+	return nil
+}
+
+// RegisterHandler registers a job handler for a specific job type
+func (q *MemoryQueue) RegisterHandler(jobType string, handler JobHandler) {
+	q.handlersMu.Lock()
+	defer q.handlersMu.Unlock()
+
+	q.handlers[jobType] = handler
+	q.logger.WithFields(map[string]interface{}{
+		"job_type": jobType,
+		"handler":  handler.Name(),
+	}).Info("Job handler registered")
+}
+
+// StopConsuming stops consuming jobs. It is safe to call more than once.
+func (q *MemoryQueue) StopConsuming() error {
+	q.stopOnce.Do(func() {
+		q.logger.Info("Stopping in-memory job consumer")
+		close(q.stopChan)
+	})
+	q.wg.Wait()
+	return nil
+}
+
+// Close closes the queue
+func (q *MemoryQueue) Close() error {
+	return q.StopConsuming()
+}
+
+// GetStats returns queue statistics
+func (q *MemoryQueue) GetStats(ctx context.Context) (*Stats, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := &Stats{
+		PendingJobs:    int64(q.pending.Len()) + q.delayed,
+		ProcessingJobs: q.processing,
+		CompletedJobs:  q.completed,
+		FailedJobs:     int64(len(q.failed)),
+	}
+	stats.TotalJobs = stats.PendingJobs + stats.ProcessingJobs + stats.CompletedJobs + stats.FailedJobs
+
+	now := time.Now()
+	stats.Bands = make(map[string]BandStats, len(PriorityBands))
+	for _, band := range PriorityBands {
+		stats.Bands[band] = BandStats{}
+	}
+	for _, queued := range q.pending {
+		band := PriorityBand(queued.job.Priority)
+		bandStats := stats.Bands[band]
+		bandStats.Depth++
+		if age := now.Sub(queued.job.CreatedAt); age > bandStats.OldestJobAge {
+			bandStats.OldestJobAge = age
+		}
+		stats.Bands[band] = bandStats
+	}
+
+	return stats, nil
+}
+
+// GetFailedJobs returns permanently failed jobs, most recent first
+func (q *MemoryQueue) GetFailedJobs(ctx context.Context, limit, offset int) ([]*Job, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ids := make([]string, 0, len(q.failed))
+	for id := range q.failed {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return q.failed[ids[i]].After(q.failed[ids[j]])
+	})
+
+	if offset >= len(ids) {
+		return []*Job{}, nil
+	}
+	end := offset + limit
+	if end > len(ids) {
+		end = len(ids)
+	}
+
+	jobs := make([]*Job, 0, end-offset)
+	for _, id := range ids[offset:end] {
+		if entry, ok := q.jobs[id]; ok {
+			jobs = append(jobs, copyJob(entry.job))
+		}
+	}
+
+	return jobs, nil
+}
+
+// RetryFailedJob re-enqueues a failed job with a fresh retry budget
+func (q *MemoryQueue) RetryFailedJob(ctx context.Context, jobID string) error {
+	q.mu.Lock()
+	if _, ok := q.failed[jobID]; !ok {
+		q.mu.Unlock()
+		return fmt.Errorf("job is not in failed state: %s", jobID)
+	}
+
+	entry, ok := q.jobs[jobID]
+	if !ok {
+		q.mu.Unlock()
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+
+	delete(q.failed, jobID)
+	delete(q.results, jobID)
+
+	job := copyJob(entry.job)
+	job.Retries = 0
+	job.Error = ""
+	job.FailedAt = nil
+	q.push(job)
+	q.mu.Unlock()
+
+	q.notify()
+	return nil
+}
+
+// PurgeCompletedJobs removes completed job results older than the specified duration
+func (q *MemoryQueue) PurgeCompletedJobs(ctx context.Context, olderThan time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-olderThan)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var purged int64
+	for id, entry := range q.results {
+		if entry.result.Status != JobStatusCompleted || entry.result.CreatedAt.After(cutoff) {
+			continue
+		}
+		delete(q.results, id)
+		delete(q.jobs, id)
+		purged++
+	}
+
+	return purged, nil
+}
+
+// PurgeJobs removes matching jobs from the queue along with their details and results
+func (q *MemoryQueue) PurgeJobs(ctx context.Context, match func(*Job) bool) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	purged := make(map[string]struct{})
+
+	kept := q.pending[:0]
+	for _, queued := range q.pending {
+		if match(copyJob(queued.job)) {
+			purged[queued.job.ID] = struct{}{}
+			continue
+		}
+		kept = append(kept, queued)
+	}
+	for i := len(kept); i < len(q.pending); i++ {
+		q.pending[i] = nil
+	}
+	q.pending = kept
+	heap.Init(&q.pending)
+
+	// Retrying and failed jobs only live in q.jobs; a retry whose entry is
+	// gone is dropped when its timer fires
+	for id, entry := range q.jobs {
+		if match(copyJob(entry.job)) {
+			purged[id] = struct{}{}
+		}
+	}
+	for id := range purged {
+		delete(q.jobs, id)
+		delete(q.results, id)
+		delete(q.failed, id)
+	}
+
+	return int64(len(purged)), nil
+}
+
+// worker processes jobs until the queue is stopped
+func (q *MemoryQueue) worker(ctx context.Context, workerID int) {
+	defer q.wg.Done()
+
+	logger := q.logger.WithField("worker_id", workerID)
+	logger.Info("Worker started")
+
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Worker stopped due to context cancellation")
+			return
+		case <-q.stopChan:
+			logger.Info("Worker stopped")
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+
+		// Drain the queue before waiting again
+		for q.processNextJob(ctx, logger) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.stopChan:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// processNextJob processes the next available job and reports whether one was found
+func (q *MemoryQueue) processNextJob(ctx context.Context, workerLogger logger.Logger) bool {
+	q.mu.Lock()
+	if q.pending.Len() == 0 {
+		q.mu.Unlock()
+		return false
+	}
+	job := heap.Pop(&q.pending).(*queuedJob).job
+	q.processing++
+	q.mu.Unlock()
+
+	// Another worker may be able to pick up the next job right away
+	q.notify()
+
+	defer func() {
+		q.mu.Lock()
+		q.processing--
+		q.mu.Unlock()
+	}()
+
+	jobLogger := workerLogger.WithFields(map[string]interface{}{
+		"job_id":   job.ID,
+		"job_type": job.Type,
+		"retries":  job.Retries,
+	})
+
+	jobLogger.Info("Processing job")
+	start := time.Now()
+
+	q.handlersMu.RLock()
+	handler, exists := q.handlers[job.Type]
+	q.handlersMu.RUnlock()
+
+	if !exists {
+		q.handleJobFailure(ctx, job, fmt.Errorf("no handler for job type: %s", job.Type), jobLogger)
+		return true
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, q.config.JobTimeout)
+	defer cancel()
+
+	result, err := handler.Handle(jobCtx, job)
+	duration := time.Since(start)
+
+	if err != nil {
+		jobLogger.WithError(err).WithField("duration", duration).Error("Job processing failed")
+		q.handleJobFailure(ctx, job, err, jobLogger)
+		return true
+	}
+
+	if result == nil {
+		result = &JobResult{}
+	}
+	result.JobID = job.ID
+	result.Status = JobStatusCompleted
+	result.Duration = duration
+	result.CreatedAt = time.Now()
+
+	q.mu.Lock()
+	q.completed++
+	q.storeJobResult(result)
+	q.mu.Unlock()
+
+	jobLogger.WithField("duration", duration).Info("Job completed successfully")
+	return true
+}
+
+// handleJobFailure handles job processing failures with retry logic
+func (q *MemoryQueue) handleJobFailure(ctx context.Context, job *Job, jobErr error, jobLogger logger.Logger) {
+	job.Retries++
+	job.Error = jobErr.Error()
+
+	if job.Retries < job.MaxRetries {
+		delay := time.Duration(job.Retries) * q.config.RetryDelay
+
+		jobLogger.WithFields(map[string]interface{}{
+			"retry_in": delay,
+			"retries":  job.Retries,
+		}).Warn("Job failed, will retry")
+
+		q.mu.Lock()
+		q.delayed++
+		q.storeJob(job)
+		q.mu.Unlock()
+
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+				q.mu.Lock()
+				q.delayed--
+				if _, ok := q.jobs[job.ID]; !ok {
+					q.mu.Unlock()
+					jobLogger.Info("Job purged, skipping job retry")
+					return
+				}
+				q.push(job)
+				q.mu.Unlock()
+				q.notify()
+			case <-ctx.Done():
+				jobLogger.Info("Context cancelled, skipping job retry")
+			case <-q.stopChan:
+				jobLogger.Info("Queue stopped, skipping job retry")
+			}
+		}()
+		return
+	}
+
+	// Maximum retries exceeded
+	now := time.Now()
+	job.FailedAt = &now
+
+	q.mu.Lock()
+	q.failed[job.ID] = now
+	q.storeJob(job)
+	q.storeJobResult(&JobResult{
+		JobID:     job.ID,
+		Status:    JobStatusFailed,
+		Error:     jobErr.Error(),
+		CreatedAt: now,
+	})
+	q.mu.Unlock()
+
+	jobLogger.Error("Job failed permanently after max retries")
+}
+
+// cleanup periodically drops expired jobs and results
+func (q *MemoryQueue) cleanup(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopChan:
+			return
+		case <-ticker.C:
+			q.performCleanup()
+		}
+	}
+}
+
+// performCleanup removes expired entries
+func (q *MemoryQueue) performCleanup() {
+	now := time.Now()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cleaned := 0
+	for id, entry := range q.results {
+		if entry.expired(now) {
+			delete(q.results, id)
+			cleaned++
+		}
+	}
+	for id, entry := range q.jobs {
+		if entry.expired(now) {
+			delete(q.jobs, id)
+			delete(q.failed, id)
+		}
+	}
+
+	if cleaned > 0 {
+		q.logger.WithField("count", cleaned).Info("Cleaned up job results")
+	}
+}
+
+// push adds a job to the pending heap; the caller must hold q.mu
+func (q *MemoryQueue) push(job *Job) {
+	q.storeJob(job)
+	q.sequence++
+	heap.Push(&q.pending, &queuedJob{job: copyJob(job), sequence: q.sequence})
+}
+
+// storeJob records job details for GetJob; the caller must hold q.mu
+func (q *MemoryQueue) storeJob(job *Job) {
+	q.jobs[job.ID] = &memoryEntry{
+		job:       copyJob(job),
+		expiresAt: time.Now().Add(q.config.ResultTTL),
+	}
+}
+
+// storeJobResult records a job result; the caller must hold q.mu
+func (q *MemoryQueue) storeJobResult(result *JobResult) {
+	q.results[result.JobID] = &memoryEntry{
+		result:    result,
+		expiresAt: time.Now().Add(q.config.ResultTTL),
+	}
+}
+
+// notify wakes up one idle worker without blocking
+func (q *MemoryQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// prepareJob fills in job defaults before it is enqueued
+func (q *MemoryQueue) prepareJob(job *Job) {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now()
+	}
+	if job.MaxRetries == 0 {
+		job.MaxRetries = q.config.MaxRetries
+	}
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return now.After(e.expiresAt)
+}
+
+// copyJob returns a copy of job so callers cannot mutate queued state
+func copyJob(job *Job) *Job {
+	c := *job
+	if job.Payload != nil {
+		c.Payload = make(map[string]interface{}, len(job.Payload))
+		for k, v := range job.Payload {
+			c.Payload[k] = v
+		}
+	}
+	return &c
+}
+
+// queuedJob is a pending job with its insertion order
+type queuedJob struct {
+	job      *Job
+	sequence uint64
+}
+
+// jobHeap orders jobs by priority (highest first), then by insertion order
+type jobHeap []*queuedJob
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].job.Priority != h[j].job.Priority {
+		return h[i].job.Priority > h[j].job.Priority
+	}
+	return h[i].sequence < h[j].sequence
+}
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x interface{}) { *h = append(*h, x.(*queuedJob)) }
+
+func (h *jobHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
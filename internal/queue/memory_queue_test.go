@@ -0,0 +1,280 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/configs"
+	"mem_bank/pkg/logger"
+)
+
+// recordingHandler records the jobs it sees and fails the first failures calls
+type recordingHandler struct {
+	mu       sync.Mutex
+	jobType  string
+	failures int
+	calls    int
+	seen     []string
+	done     chan string
+}
+
+func newRecordingHandler(jobType string, failures int) *recordingHandler {
+	return &recordingHandler{
+		jobType:  jobType,
+		failures: failures,
+		done:     make(chan string, 100),
+	}
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, job *Job) (*JobResult, error) {
+	h.mu.Lock()
+	h.calls++
+	call := h.calls
+	h.seen = append(h.seen, job.ID)
+	h.mu.Unlock()
+
+	defer func() { h.done <- job.ID }()
+
+	if call <= h.failures {
+		return nil, errors.New("transient failure")
+	}
+	return &JobResult{Result: map[string]interface{}{"call": call}}, nil
+}
+
+func (h *recordingHandler) Name() string    { return "recordingHandler" }
+func (h *recordingHandler) JobType() string { return h.jobType }
+
+func newTestLogger(t *testing.T) logger.Logger {
+	t.Helper()
+	log, err := logger.NewLogger(&configs.LoggingConfig{Level: "error", Format: "text", Output: "stderr"})
+	require.NoError(t, err)
+	return log
+}
+
+func newTestMemoryQueue(t *testing.T) *MemoryQueue {
+	t.Helper()
+	q := NewMemoryQueue(newTestLogger(t), Config{
+		MaxRetries:   3,
+		RetryDelay:   10 * time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+		ResultTTL:    time.Minute,
+	})
+	t.Cleanup(func() { _ = q.Close() })
+	return q
+}
+
+func waitForJobs(t *testing.T, h *recordingHandler, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-h.done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for job %d of %d", i+1, n)
+		}
+	}
+}
+
+func TestMemoryQueue_PriorityOrder(t *testing.T) {
+	q := newTestMemoryQueue(t)
+	ctx := context.Background()
+
+	handler := newRecordingHandler("test", 0)
+	q.RegisterHandler("test", handler)
+
+	// Enqueue before consuming so ordering is decided by the heap alone
+	jobs := []*Job{
+		{ID: "low", Type: "test", Priority: 1},
+		{ID: "high", Type: "test", Priority: 9},
+		{ID: "normal-1", Type: "test", Priority: 5},
+		{ID: "normal-2", Type: "test", Priority: 5},
+	}
+	require.NoError(t, q.EnqueueBatch(ctx, jobs))
+	require.NoError(t, q.StartConsuming(ctx, 1))
+
+	waitForJobs(t, handler, len(jobs))
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	assert.Equal(t, []string{"high", "normal-1", "normal-2", "low"}, handler.seen)
+}
+
+func TestMemoryQueue_RetryThenSucceed(t *testing.T) {
+	q := newTestMemoryQueue(t)
+	ctx := context.Background()
+
+	handler := newRecordingHandler("test", 2)
+	q.RegisterHandler("test", handler)
+	require.NoError(t, q.StartConsuming(ctx, 2))
+
+	job := &Job{Type: "test"}
+	require.NoError(t, q.Enqueue(ctx, job))
+	require.NotEmpty(t, job.ID)
+
+	waitForJobs(t, handler, 3)
+
+	require.Eventually(t, func() bool {
+		_, err := q.GetJobResult(ctx, job.ID)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	result, err := q.GetJobResult(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusCompleted, result.Status)
+	assert.Equal(t, 3, result.Result["call"])
+
+	stats, err := q.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.CompletedJobs)
+	assert.Equal(t, int64(0), stats.FailedJobs)
+}
+
+func TestMemoryQueue_FailedJobsAndRetry(t *testing.T) {
+	q := newTestMemoryQueue(t)
+	ctx := context.Background()
+
+	handler := newRecordingHandler("test", 3)
+	q.RegisterHandler("test", handler)
+	require.NoError(t, q.StartConsuming(ctx, 1))
+
+	job := &Job{ID: "job-1", Type: "test"}
+	require.NoError(t, q.Enqueue(ctx, job))
+
+	waitForJobs(t, handler, 3)
+
+	require.Eventually(t, func() bool {
+		failed, _ := q.GetFailedJobs(ctx, 10, 0)
+		return len(failed) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	failed, err := q.GetFailedJobs(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", failed[0].ID)
+	assert.Equal(t, 3, failed[0].Retries)
+	assert.NotNil(t, failed[0].FailedAt)
+
+	result, err := q.GetJobResult(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusFailed, result.Status)
+
+	// A manual retry gets a fresh budget and the handler now succeeds
+	require.NoError(t, q.RetryFailedJob(ctx, "job-1"))
+	waitForJobs(t, handler, 1)
+
+	require.Eventually(t, func() bool {
+		result, err := q.GetJobResult(ctx, "job-1")
+		return err == nil && result.Status == JobStatusCompleted
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Error(t, q.RetryFailedJob(ctx, "job-1"))
+}
+
+func TestMemoryQueue_MissingHandler(t *testing.T) {
+	q := NewMemoryQueue(newTestLogger(t), Config{
+		MaxRetries:   1,
+		PollInterval: 10 * time.Millisecond,
+	})
+	defer q.Close()
+	ctx := context.Background()
+
+	require.NoError(t, q.StartConsuming(ctx, 1))
+	require.NoError(t, q.Enqueue(ctx, &Job{ID: "orphan", Type: "unknown"}))
+
+	require.Eventually(t, func() bool {
+		result, err := q.GetJobResult(ctx, "orphan")
+		return err == nil && result.Status == JobStatusFailed
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestMemoryQueue_ResultTTLAndPurge(t *testing.T) {
+	q := NewMemoryQueue(newTestLogger(t), Config{
+		PollInterval: 10 * time.Millisecond,
+		ResultTTL:    50 * time.Millisecond,
+	})
+	defer q.Close()
+	ctx := context.Background()
+
+	handler := newRecordingHandler("test", 0)
+	q.RegisterHandler("test", handler)
+	require.NoError(t, q.StartConsuming(ctx, 1))
+
+	require.NoError(t, q.Enqueue(ctx, &Job{ID: "a", Type: "test"}))
+	waitForJobs(t, handler, 1)
+
+	require.Eventually(t, func() bool {
+		_, err := q.GetJobResult(ctx, "a")
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	purged, err := q.PurgeCompletedJobs(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+
+	// Results disappear once ResultTTL has passed
+	require.Eventually(t, func() bool {
+		_, err := q.GetJobResult(ctx, "a")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	purged, err = q.PurgeCompletedJobs(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func TestMemoryQueue_PurgeJobs(t *testing.T) {
+	q := newTestMemoryQueue(t)
+	ctx := context.Background()
+
+	forUser := func(userID string) func(*Job) bool {
+		return func(job *Job) bool { return job.Payload["user_id"] == userID }
+	}
+
+	require.NoError(t, q.EnqueueBatch(ctx, []*Job{
+		{ID: "a1", Type: "test", Payload: map[string]interface{}{"user_id": "alice"}},
+		{ID: "b1", Type: "test", Payload: map[string]interface{}{"user_id": "bob"}},
+		{ID: "a2", Type: "test", Priority: 9, Payload: map[string]interface{}{"user_id": "alice"}},
+	}))
+
+	purged, err := q.PurgeJobs(ctx, forUser("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	_, err = q.GetJob(ctx, "a1")
+	assert.Error(t, err)
+
+	handler := newRecordingHandler("test", 1)
+	q.RegisterHandler("test", handler)
+	require.NoError(t, q.StartConsuming(ctx, 1))
+	waitForJobs(t, handler, 1)
+	require.Eventually(t, func() bool {
+		job, err := q.GetJob(ctx, "b1")
+		return err == nil && job.Retries == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// b1 failed once and is waiting to be retried; purging drops the retry
+	purged, err = q.PurgeJobs(ctx, forUser("bob"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	time.Sleep(50 * time.Millisecond)
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	assert.Equal(t, []string{"b1"}, handler.seen)
+
+	stats, err := q.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stats.PendingJobs)
+}
+
+func TestMemoryQueue_StopIsIdempotent(t *testing.T) {
+	q := NewMemoryQueue(newTestLogger(t), Config{})
+	require.NoError(t, q.StartConsuming(context.Background(), 1))
+
+	assert.NoError(t, q.StopConsuming())
+	assert.NoError(t, q.Close())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
@@ -344,6 +345,11 @@ func (q *RedisQueue) handleJobFailure(ctx context.Context, job *Job, jobErr erro
 
 			select {
 			case <-timer.C:
+				// Jobs purged while waiting are not retried
+				if exists, err := q.client.Exists(ctx, q.getJobKey(job.ID)).Result(); err == nil && exists == 0 {
+					jobLogger.Info("Job purged, skipping job retry")
+					return
+				}
 				if err := q.Enqueue(ctx, job); err != nil {
 					jobLogger.WithError(err).Error("Failed to re-enqueue job for retry")
 				}
@@ -433,6 +439,106 @@ func (q *RedisQueue) performCleanup(ctx context.Context) {
 	}
 }
 
+// GetStats returns the number of waiting jobs, broken down by priority band.
+// Completed and failed jobs are not tracked by this backend.
+func (q *RedisQueue) GetStats(ctx context.Context) (*Stats, error) {
+	queueKey := q.getQueueKey()
+
+	// Scores are priority*1e9 + unix time. Every queued job was created within a
+	// few years of now, so priority p occupies the score range centred on
+	// p*1e9 + now, and the lowest score within a range is its oldest job.
+	type priorityRange struct {
+		band     string
+		min, max string
+	}
+	now := time.Now()
+	boundary := func(p int) string {
+		return strconv.FormatInt(int64(p)*1e9+now.Unix()-5e8, 10)
+	}
+	ranges := make([]priorityRange, 0, 11)
+	for p := 0; p <= 10; p++ {
+		r := priorityRange{
+			band: PriorityBand(p),
+			min:  boundary(p),
+			max:  "(" + boundary(p+1),
+		}
+		if p == 0 {
+			r.min = "-inf"
+		}
+		if p == 10 {
+			r.max = "+inf"
+		}
+		ranges = append(ranges, r)
+	}
+
+	pipe := q.client.Pipeline()
+	counts := make([]*redis.IntCmd, len(ranges))
+	oldest := make([]*redis.ZSliceCmd, len(ranges))
+	for i, r := range ranges {
+		counts[i] = pipe.ZCount(ctx, queueKey, r.min, r.max)
+		oldest[i] = pipe.ZRangeByScoreWithScores(ctx, queueKey, &redis.ZRangeBy{Min: r.min, Max: r.max, Count: 1})
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("reading queue stats: %w", err)
+	}
+
+	stats := &Stats{Bands: make(map[string]BandStats, len(PriorityBands))}
+	for _, band := range PriorityBands {
+		stats.Bands[band] = BandStats{}
+	}
+
+	for i, r := range ranges {
+		count := counts[i].Val()
+		if count == 0 {
+			continue
+		}
+
+		bandStats := stats.Bands[r.band]
+		bandStats.Depth += count
+		stats.PendingJobs += count
+
+		if members := oldest[i].Val(); len(members) > 0 {
+			var job Job
+			if member, ok := members[0].Member.(string); ok && json.Unmarshal([]byte(member), &job) == nil {
+				if age := now.Sub(job.CreatedAt); age > bandStats.OldestJobAge {
+					bandStats.OldestJobAge = age
+				}
+			}
+		}
+		stats.Bands[r.band] = bandStats
+	}
+
+	stats.TotalJobs = stats.PendingJobs
+	return stats, nil
+}
+
+// PurgeJobs removes matching jobs from the queue along with their details and results.
+// A job that is waiting for a retry is dropped when its retry is due.
+func (q *RedisQueue) PurgeJobs(ctx context.Context, match func(*Job) bool) (int64, error) {
+	purged := make(map[string]struct{})
+
+	members, err := matchingMembers(ctx, q.client, q.getQueueKey(), match)
+	if err != nil {
+		return 0, err
+	}
+	for id, member := range members {
+		if err := q.client.ZRem(ctx, q.getQueueKey(), member).Err(); err != nil {
+			return 0, fmt.Errorf("removing queued job %s: %w", id, err)
+		}
+		purged[id] = struct{}{}
+	}
+
+	stored, err := purgeStoredJobs(ctx, q.client, q.config.QueueName, match)
+	if err != nil {
+		return 0, err
+	}
+	for id := range stored {
+		purged[id] = struct{}{}
+	}
+
+	return int64(len(purged)), nil
+}
+
 // Redis key helpers
 func (q *RedisQueue) getQueueKey() string {
 	return fmt.Sprintf("%s:queue", q.config.QueueName)
@@ -445,3 +551,61 @@ func (q *RedisQueue) getJobKey(jobID string) string {
 func (q *RedisQueue) getResultKey(jobID string) string {
 	return fmt.Sprintf("%s:result:%s", q.config.QueueName, jobID)
 }
+
+// matchingMembers returns the sorted set members holding a matching job, keyed by job ID
+func matchingMembers(ctx context.Context, client *redis.Client, key string, match func(*Job) bool) (map[string]string, error) {
+	members := make(map[string]string)
+
+	iter := client.ZScan(ctx, key, 0, "", 500).Iterator()
+	for i := 0; iter.Next(ctx); i++ {
+		// ZSCAN returns members and scores interleaved
+		if i%2 == 1 {
+			continue
+		}
+		member := iter.Val()
+
+		var job Job
+		if err := json.Unmarshal([]byte(member), &job); err != nil {
+			continue
+		}
+		if match(&job) {
+			members[job.ID] = member
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", key, err)
+	}
+
+	return members, nil
+}
+
+// purgeStoredJobs deletes the details and results of matching jobs stored under
+// queueName and returns their IDs
+func purgeStoredJobs(ctx context.Context, client *redis.Client, queueName string, match func(*Job) bool) (map[string]struct{}, error) {
+	purged := make(map[string]struct{})
+
+	iter := client.Scan(ctx, 0, fmt.Sprintf("%s:job:*", queueName), 500).Iterator()
+	for iter.Next(ctx) {
+		data, err := client.Get(ctx, iter.Val()).Bytes()
+		if err != nil {
+			// Expired since the scan saw it
+			continue
+		}
+
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil || !match(&job) {
+			continue
+		}
+
+		resultKey := fmt.Sprintf("%s:result:%s", queueName, job.ID)
+		if err := client.Del(ctx, iter.Val(), resultKey).Err(); err != nil {
+			return nil, fmt.Errorf("deleting job %s: %w", job.ID, err)
+		}
+		purged[job.ID] = struct{}{}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("scanning stored jobs: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,1064 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"mem_bank/pkg/logger"
+)
+
+// RedisStreamsQueue implements the Queue and Monitor interfaces using Redis Streams.
+//
+// Jobs are appended to one stream per priority band and consumed through a
+// consumer group, so a job stays in the group's pending entries list until a
+// worker acknowledges it. Messages left pending by a crashed worker are taken
+// over with XAUTOCLAIM once they have been idle for Config.ClaimMinIdle.
+type RedisStreamsQueue struct {
+	client   *redis.Client
+	logger   logger.Logger
+	config   Config
+	handlers map[string]JobHandler
+	mu       sync.RWMutex
+	claimed  chan redis.XMessage
+	stopChan chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewRedisStreamsQueue creates a new Redis Streams based queue
+func NewRedisStreamsQueue(client *redis.Client, logger logger.Logger, config Config) *RedisStreamsQueue {
+	// Set defaults
+	if config.QueueName == "" {
+		config.QueueName = "mem_bank_jobs"
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = 30 * time.Second
+	}
+	if config.JobTimeout == 0 {
+		config.JobTimeout = 5 * time.Minute
+	}
+	if config.ResultTTL == 0 {
+		config.ResultTTL = 24 * time.Hour
+	}
+	if config.DefaultConcurrency == 0 {
+		config.DefaultConcurrency = 5
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 1 * time.Second
+	}
+	if config.CleanupInterval == 0 {
+		config.CleanupInterval = 1 * time.Hour
+	}
+	if config.ConsumerGroup == "" {
+		config.ConsumerGroup = "mem_bank_workers"
+	}
+	if config.ConsumerName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "worker"
+		}
+		config.ConsumerName = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	if config.ClaimMinIdle == 0 {
+		// Must comfortably exceed JobTimeout, otherwise jobs that are still
+		// running get claimed by another consumer
+		config.ClaimMinIdle = config.JobTimeout + time.Minute
+	}
+	if config.StreamMaxLen == 0 {
+		config.StreamMaxLen = 100000
+	}
+
+	return &RedisStreamsQueue{
+		client:   client,
+		logger:   logger,
+		config:   config,
+		handlers: make(map[string]JobHandler),
+		claimed:  make(chan redis.XMessage),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Enqueue adds a job to the stream of its priority band
+func (q *RedisStreamsQueue) Enqueue(ctx context.Context, job *Job) error {
+	q.prepareJob(job)
+
+	jobData, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("marshaling job: %w", err)
+	}
+
+	pipe := q.client.TxPipeline()
+	pipe.Set(ctx, q.getJobKey(job.ID), jobData, q.config.ResultTTL)
+	pipe.XAdd(ctx, q.xAddArgs(job, jobData))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("enqueuing job: %w", err)
+	}
+
+	q.logger.WithFields(map[string]interface{}{
+		"job_id":   job.ID,
+		"job_type": job.Type,
+		"priority": job.Priority,
+	}).Info("Job enqueued")
+
+	return nil
+}
+
+// EnqueueBatch adds multiple jobs to the queue
+func (q *RedisStreamsQueue) EnqueueBatch(ctx context.Context, jobs []*Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	pipe := q.client.Pipeline()
+	for _, job := range jobs {
+		q.prepareJob(job)
+
+		jobData, err := json.Marshal(job)
+		if err != nil {
+			return fmt.Errorf("marshaling job %s: %w", job.ID, err)
+		}
+
+		pipe.Set(ctx, q.getJobKey(job.ID), jobData, q.config.ResultTTL)
+		pipe.XAdd(ctx, q.xAddArgs(job, jobData))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("executing batch enqueue: %w", err)
+	}
+
+	q.logger.WithField("count", len(jobs)).Info("Jobs batch enqueued")
+	return nil
+}
+
+// GetJob retrieves a job by ID
+func (q *RedisStreamsQueue) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	jobData, err := q.client.Get(ctx, q.getJobKey(jobID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, fmt.Errorf("job not found: %s", jobID)
+		}
+		return nil, fmt.Errorf("retrieving job: %w", err)
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(jobData), &job); err != nil {
+		return nil, fmt.Errorf("unmarshaling job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetJobResult retrieves the result of a processed job
+func (q *RedisStreamsQueue) GetJobResult(ctx context.Context, jobID string) (*JobResult, error) {
+	resultData, err := q.client.Get(ctx, q.getResultKey(jobID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, fmt.Errorf("job result not found: %s", jobID)
+		}
+		return nil, fmt.Errorf("retrieving job result: %w", err)
+	}
+
+	var result JobResult
+	if err := json.Unmarshal([]byte(resultData), &result); err != nil {
+		return nil, fmt.Errorf("unmarshaling job result: %w", err)
+	}
+
+	return &result, nil
+}
+
+// StartConsuming creates the consumer group and starts consuming jobs
+func (q *RedisStreamsQueue) StartConsuming(ctx context.Context, concurrency int) error {
+	if concurrency <= 0 {
+		concurrency = q.config.DefaultConcurrency
+	}
+
+	if err := q.ensureGroups(ctx); err != nil {
+		return err
+	}
+
+	q.logger.WithFields(map[string]interface{}{
+		"concurrency": concurrency,
+		"group":       q.config.ConsumerGroup,
+		"consumer":    q.config.ConsumerName,
+	}).Info("Starting stream consumer")
+
+	for i := 0; i < concurrency; i++ {
+		q.wg.Add(1)
+		go q.worker(ctx, i)
+	}
+
+	q.wg.Add(3)
+	go q.scheduleRetries(ctx)
+	go q.reclaim(ctx)
+	go q.cleanup(ctx)
+
+	return nil
+}
+
+// RegisterHandler registers a job handler for a specific job type
+func (q *RedisStreamsQueue) RegisterHandler(jobType string, handler JobHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handlers[jobType] = handler
+	q.logger.WithFields(map[string]interface{}{
+		"job_type": jobType,
+		"handler":  handler.Name(),
+	}).Info("Job handler registered")
+}
+
+// StopConsuming stops consuming jobs. It is safe to call more than once.
+func (q *RedisStreamsQueue) StopConsuming() error {
+	q.stopOnce.Do(func() {
+		q.logger.Info("Stopping stream consumer")
+		close(q.stopChan)
+	})
+	q.wg.Wait()
+	return nil
+}
+
+// Close closes the queue
+func (q *RedisStreamsQueue) Close() error {
+	return q.StopConsuming()
+}
+
+// GetStats returns queue statistics aggregated over all priority bands
+func (q *RedisStreamsQueue) GetStats(ctx context.Context) (*Stats, error) {
+	stats := &Stats{Bands: make(map[string]BandStats, len(PriorityBands))}
+
+	for _, band := range PriorityBands {
+		stats.Bands[band] = BandStats{}
+
+		stream := q.getStreamKey(band)
+		groups, err := q.client.XInfoGroups(ctx, stream).Result()
+		if err != nil {
+			if isMissingStreamErr(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading consumer groups for %s: %w", stream, err)
+		}
+
+		for _, group := range groups {
+			if group.Name != q.config.ConsumerGroup {
+				continue
+			}
+			stats.ProcessingJobs += group.Pending
+			if group.Lag <= 0 {
+				continue
+			}
+			stats.PendingJobs += group.Lag
+
+			bandStats := BandStats{Depth: group.Lag}
+			// The oldest waiting job is the first entry after the last delivered one
+			next, err := q.client.XRangeN(ctx, stream, nextStreamID(group.LastDeliveredID), "+", 1).Result()
+			if err != nil {
+				return nil, fmt.Errorf("reading oldest entry of %s: %w", stream, err)
+			}
+			if len(next) > 0 {
+				ms, _ := parseStreamID(next[0].ID)
+				bandStats.OldestJobAge = time.Since(time.UnixMilli(int64(ms)))
+			}
+			stats.Bands[band] = bandStats
+		}
+	}
+
+	delayed, err := q.client.ZCard(ctx, q.getDelayedKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("counting delayed jobs: %w", err)
+	}
+	stats.PendingJobs += delayed
+
+	stats.FailedJobs, err = q.client.ZCard(ctx, q.getFailedKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("counting failed jobs: %w", err)
+	}
+
+	stats.CompletedJobs, err = q.client.Get(ctx, q.getCompletedKey()).Int64()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("reading completed counter: %w", err)
+	}
+
+	stats.TotalJobs = stats.PendingJobs + stats.ProcessingJobs + stats.CompletedJobs + stats.FailedJobs
+	return stats, nil
+}
+
+// GetFailedJobs returns dead-lettered jobs, most recent first
+func (q *RedisStreamsQueue) GetFailedJobs(ctx context.Context, limit, offset int) ([]*Job, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ids, err := q.client.ZRevRange(ctx, q.getFailedKey(), int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("listing failed jobs: %w", err)
+	}
+	if len(ids) == 0 {
+		return []*Job{}, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = q.getJobKey(id)
+	}
+
+	values, err := q.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("loading failed jobs: %w", err)
+	}
+
+	jobs := make([]*Job, 0, len(values))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// Job details expired before the failure record
+			continue
+		}
+
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			q.logger.WithError(err).WithField("job_id", ids[i]).Warn("Failed to unmarshal failed job")
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, nil
+}
+
+// RetryFailedJob moves a dead-lettered job back onto its stream with a fresh retry budget
+func (q *RedisStreamsQueue) RetryFailedJob(ctx context.Context, jobID string) error {
+	job, err := q.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+
+	removed, err := q.client.ZRem(ctx, q.getFailedKey(), jobID).Result()
+	if err != nil {
+		return fmt.Errorf("removing failed job: %w", err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("job is not in failed state: %s", jobID)
+	}
+
+	job.Retries = 0
+	job.Error = ""
+	job.FailedAt = nil
+
+	if err := q.client.Del(ctx, q.getResultKey(jobID)).Err(); err != nil {
+		q.logger.WithError(err).WithField("job_id", jobID).Warn("Failed to delete previous job result")
+	}
+
+	return q.Enqueue(ctx, job)
+}
+
+// PurgeCompletedJobs trims acknowledged stream entries older than the specified duration.
+// Entries that are still pending or not yet delivered are never trimmed.
+func (q *RedisStreamsQueue) PurgeCompletedJobs(ctx context.Context, olderThan time.Duration) (int64, error) {
+	cutoff := fmt.Sprintf("%d-0", time.Now().Add(-olderThan).UnixMilli())
+
+	var purged int64
+	for _, band := range PriorityBands {
+		stream := q.getStreamKey(band)
+
+		minID, err := q.trimBoundary(ctx, stream, cutoff)
+		if err != nil {
+			if isMissingStreamErr(err) {
+				continue
+			}
+			return purged, err
+		}
+		if minID == "" {
+			continue
+		}
+
+		n, err := q.client.XTrimMinID(ctx, stream, minID).Result()
+		if err != nil {
+			return purged, fmt.Errorf("trimming stream %s: %w", stream, err)
+		}
+		purged += n
+	}
+
+	if purged > 0 {
+		q.logger.WithField("count", purged).Info("Purged completed stream entries")
+	}
+
+	return purged, nil
+}
+
+// PurgeJobs removes matching jobs from the band streams, the delayed retries and
+// the dead letters, along with their details and results
+func (q *RedisStreamsQueue) PurgeJobs(ctx context.Context, match func(*Job) bool) (int64, error) {
+	purged := make(map[string]struct{})
+
+	for _, band := range PriorityBands {
+		ids, err := q.purgeStream(ctx, q.getStreamKey(band), match)
+		if err != nil {
+			return 0, err
+		}
+		for _, id := range ids {
+			purged[id] = struct{}{}
+		}
+	}
+
+	members, err := matchingMembers(ctx, q.client, q.getDelayedKey(), match)
+	if err != nil {
+		return 0, err
+	}
+	for id, member := range members {
+		if err := q.client.ZRem(ctx, q.getDelayedKey(), member).Err(); err != nil {
+			return 0, fmt.Errorf("removing delayed job %s: %w", id, err)
+		}
+		purged[id] = struct{}{}
+	}
+
+	stored, err := purgeStoredJobs(ctx, q.client, q.config.QueueName, match)
+	if err != nil {
+		return 0, err
+	}
+	for id := range stored {
+		purged[id] = struct{}{}
+	}
+
+	if len(purged) > 0 {
+		failed := make([]interface{}, 0, len(purged))
+		for id := range purged {
+			failed = append(failed, id)
+		}
+		if err := q.client.ZRem(ctx, q.getFailedKey(), failed...).Err(); err != nil {
+			return 0, fmt.Errorf("removing failed jobs: %w", err)
+		}
+	}
+
+	return int64(len(purged)), nil
+}
+
+// purgeStream acknowledges and deletes the entries of stream holding a matching job
+func (q *RedisStreamsQueue) purgeStream(ctx context.Context, stream string, match func(*Job) bool) ([]string, error) {
+	var purged []string
+
+	start := "-"
+	for {
+		messages, err := q.client.XRangeN(ctx, stream, start, "+", 500).Result()
+		if err != nil {
+			if isMissingStreamErr(err) {
+				return purged, nil
+			}
+			return nil, fmt.Errorf("reading stream %s: %w", stream, err)
+		}
+
+		for _, msg := range messages {
+			data, _ := msg.Values["data"].(string)
+
+			var job Job
+			if err := json.Unmarshal([]byte(data), &job); err != nil || !match(&job) {
+				continue
+			}
+
+			pipe := q.client.TxPipeline()
+			pipe.XAck(ctx, stream, q.config.ConsumerGroup, msg.ID)
+			pipe.XDel(ctx, stream, msg.ID)
+			if _, err := pipe.Exec(ctx); err != nil {
+				return nil, fmt.Errorf("deleting stream entry %s: %w", msg.ID, err)
+			}
+			purged = append(purged, job.ID)
+		}
+
+		if len(messages) < 500 {
+			return purged, nil
+		}
+		start = nextStreamID(messages[len(messages)-1].ID)
+	}
+}
+
+// trimBoundary returns the lowest stream ID that must be kept, or "" when nothing can be trimmed
+func (q *RedisStreamsQueue) trimBoundary(ctx context.Context, stream, cutoff string) (string, error) {
+	groups, err := q.client.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return "", err
+	}
+
+	var lastDelivered string
+	for _, group := range groups {
+		if group.Name == q.config.ConsumerGroup {
+			lastDelivered = group.LastDeliveredID
+		}
+	}
+	if lastDelivered == "" || lastDelivered == "0-0" {
+		return "", nil
+	}
+
+	boundary := cutoff
+	if next := nextStreamID(lastDelivered); compareStreamIDs(next, boundary) < 0 {
+		boundary = next
+	}
+
+	pending, err := q.client.XPending(ctx, stream, q.config.ConsumerGroup).Result()
+	if err != nil {
+		return "", fmt.Errorf("reading pending entries for %s: %w", stream, err)
+	}
+	if pending.Count > 0 && compareStreamIDs(pending.Lower, boundary) < 0 {
+		boundary = pending.Lower
+	}
+
+	return boundary, nil
+}
+
+// ensureGroups creates the consumer group on every band stream
+func (q *RedisStreamsQueue) ensureGroups(ctx context.Context) error {
+	for _, band := range PriorityBands {
+		stream := q.getStreamKey(band)
+		// Start from the beginning so jobs enqueued before the group existed are delivered
+		err := q.client.XGroupCreateMkStream(ctx, stream, q.config.ConsumerGroup, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return fmt.Errorf("creating consumer group for %s: %w", stream, err)
+		}
+	}
+	return nil
+}
+
+// worker reads and processes messages until the queue is stopped
+func (q *RedisStreamsQueue) worker(ctx context.Context, workerID int) {
+	defer q.wg.Done()
+
+	logger := q.logger.WithField("worker_id", workerID)
+	logger.Info("Worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Worker stopped due to context cancellation")
+			return
+		case <-q.stopChan:
+			logger.Info("Worker stopped")
+			return
+		case msg := <-q.claimed:
+			q.processMessage(ctx, msg, logger)
+			continue
+		default:
+		}
+
+		messages, err := q.readNext(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.WithError(err).Error("Failed to read from streams")
+				q.sleep(ctx, q.config.PollInterval)
+			}
+			continue
+		}
+
+		for _, msg := range messages {
+			q.processMessage(ctx, msg, logger)
+		}
+	}
+}
+
+// readNext returns the next messages for this consumer, favouring higher priority bands.
+// Each band is polled without blocking first; if all are empty the worker blocks on
+// every band for up to PollInterval.
+func (q *RedisStreamsQueue) readNext(ctx context.Context) ([]redis.XMessage, error) {
+	for _, band := range PriorityBands {
+		streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    q.config.ConsumerGroup,
+			Consumer: q.config.ConsumerName,
+			Streams:  []string{q.getStreamKey(band), ">"},
+			Count:    1,
+			Block:    -1,
+		}).Result()
+		if err != nil {
+			if err == redis.Nil {
+				continue
+			}
+			return nil, err
+		}
+		if messages := flattenStreams(streams); len(messages) > 0 {
+			return messages, nil
+		}
+	}
+
+	keys := make([]string, 0, len(PriorityBands)*2)
+	for _, band := range PriorityBands {
+		keys = append(keys, q.getStreamKey(band))
+	}
+	for range PriorityBands {
+		keys = append(keys, ">")
+	}
+
+	streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.config.ConsumerGroup,
+		Consumer: q.config.ConsumerName,
+		Streams:  keys,
+		Count:    1,
+		Block:    q.config.PollInterval,
+	}).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return flattenStreams(streams), nil
+}
+
+// processMessage runs the handler for a stream message and acknowledges it
+func (q *RedisStreamsQueue) processMessage(ctx context.Context, msg redis.XMessage, workerLogger logger.Logger) {
+	stream, _ := msg.Values["stream"].(string)
+	data, _ := msg.Values["data"].(string)
+
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		workerLogger.WithError(err).WithField("message_id", msg.ID).Error("Failed to unmarshal job, discarding message")
+		q.ack(ctx, stream, msg.ID, workerLogger)
+		return
+	}
+
+	jobLogger := workerLogger.WithFields(map[string]interface{}{
+		"job_id":     job.ID,
+		"job_type":   job.Type,
+		"retries":    job.Retries,
+		"message_id": msg.ID,
+	})
+
+	jobLogger.Info("Processing job")
+	start := time.Now()
+
+	q.mu.RLock()
+	handler, exists := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	if !exists {
+		q.handleJobFailure(ctx, stream, msg.ID, &job, fmt.Errorf("no handler for job type: %s", job.Type), jobLogger)
+		return
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, q.config.JobTimeout)
+	defer cancel()
+
+	result, err := handler.Handle(jobCtx, &job)
+	duration := time.Since(start)
+
+	if err != nil {
+		jobLogger.WithError(err).WithField("duration", duration).Error("Job processing failed")
+		q.handleJobFailure(ctx, stream, msg.ID, &job, err, jobLogger)
+		return
+	}
+
+	if result == nil {
+		result = &JobResult{}
+	}
+	result.JobID = job.ID
+	result.Status = JobStatusCompleted
+	result.Duration = duration
+	result.CreatedAt = time.Now()
+
+	q.storeJobResult(ctx, result, jobLogger)
+	if err := q.client.Incr(ctx, q.getCompletedKey()).Err(); err != nil {
+		jobLogger.WithError(err).Warn("Failed to update completed counter")
+	}
+	q.ack(ctx, stream, msg.ID, jobLogger)
+	jobLogger.WithField("duration", duration).Info("Job completed successfully")
+}
+
+// handleJobFailure schedules a retry or dead-letters the job, then acknowledges the message.
+// The retry is persisted before the ack so a crash in between re-delivers rather than loses the job.
+func (q *RedisStreamsQueue) handleJobFailure(ctx context.Context, stream, messageID string, job *Job, jobErr error, jobLogger logger.Logger) {
+	job.Retries++
+	job.Error = jobErr.Error()
+
+	if job.Retries < job.MaxRetries {
+		delay := time.Duration(job.Retries) * q.config.RetryDelay
+
+		jobData, err := json.Marshal(job)
+		if err != nil {
+			jobLogger.WithError(err).Error("Failed to marshal job for retry")
+			return
+		}
+
+		runAt := float64(time.Now().Add(delay).UnixMilli())
+		if err := q.client.ZAdd(ctx, q.getDelayedKey(), redis.Z{Score: runAt, Member: jobData}).Err(); err != nil {
+			// Leave the message pending so it is reclaimed later
+			jobLogger.WithError(err).Error("Failed to schedule job retry")
+			return
+		}
+
+		jobLogger.WithFields(map[string]interface{}{
+			"retry_in": delay,
+			"retries":  job.Retries,
+		}).Warn("Job failed, will retry")
+	} else {
+		q.deadLetter(ctx, job, jobErr, jobLogger)
+	}
+
+	q.ack(ctx, stream, messageID, jobLogger)
+}
+
+// deadLetter records a job as permanently failed
+func (q *RedisStreamsQueue) deadLetter(ctx context.Context, job *Job, jobErr error, jobLogger logger.Logger) {
+	now := time.Now()
+	job.FailedAt = &now
+	job.Error = jobErr.Error()
+
+	jobData, err := json.Marshal(job)
+	if err != nil {
+		jobLogger.WithError(err).Error("Failed to marshal failed job")
+		return
+	}
+
+	pipe := q.client.TxPipeline()
+	pipe.Set(ctx, q.getJobKey(job.ID), jobData, q.config.ResultTTL)
+	pipe.ZAdd(ctx, q.getFailedKey(), redis.Z{Score: float64(now.Unix()), Member: job.ID})
+	if _, err := pipe.Exec(ctx); err != nil {
+		jobLogger.WithError(err).Error("Failed to record failed job")
+	}
+
+	q.storeJobResult(ctx, &JobResult{
+		JobID:     job.ID,
+		Status:    JobStatusFailed,
+		Error:     jobErr.Error(),
+		CreatedAt: now,
+	}, jobLogger)
+	jobLogger.Error("Job failed permanently after max retries")
+}
+
+// scheduleRetries moves due retries from the delayed set back onto their streams
+func (q *RedisStreamsQueue) scheduleRetries(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopChan:
+			return
+		case <-ticker.C:
+			q.promoteDueRetries(ctx)
+		}
+	}
+}
+
+// promoteDueRetries re-enqueues delayed jobs whose retry time has passed
+func (q *RedisStreamsQueue) promoteDueRetries(ctx context.Context) {
+	due, err := q.client.ZRangeByScore(ctx, q.getDelayedKey(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: 100,
+	}).Result()
+	if err != nil {
+		q.logger.WithError(err).Error("Failed to read delayed jobs")
+		return
+	}
+
+	for _, jobData := range due {
+		// Only the consumer that removes the entry re-enqueues it
+		removed, err := q.client.ZRem(ctx, q.getDelayedKey(), jobData).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
+
+		var job Job
+		if err := json.Unmarshal([]byte(jobData), &job); err != nil {
+			q.logger.WithError(err).Error("Failed to unmarshal delayed job")
+			continue
+		}
+
+		if err := q.Enqueue(ctx, &job); err != nil {
+			q.logger.WithError(err).WithField("job_id", job.ID).Error("Failed to re-enqueue job for retry")
+		}
+	}
+}
+
+// reclaim periodically takes over messages left pending by dead consumers
+func (q *RedisStreamsQueue) reclaim(ctx context.Context) {
+	defer q.wg.Done()
+
+	interval := q.config.ClaimMinIdle / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopChan:
+			return
+		case <-ticker.C:
+			for _, band := range PriorityBands {
+				q.claimStale(ctx, q.getStreamKey(band))
+			}
+		}
+	}
+}
+
+// claimStale claims idle messages on a stream and hands them to the workers
+func (q *RedisStreamsQueue) claimStale(ctx context.Context, stream string) {
+	start := "0-0"
+	for {
+		messages, next, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    q.config.ConsumerGroup,
+			Consumer: q.config.ConsumerName,
+			MinIdle:  q.config.ClaimMinIdle,
+			Start:    start,
+			Count:    10,
+		}).Result()
+		if err != nil {
+			if !isMissingStreamErr(err) {
+				q.logger.WithError(err).WithField("stream", stream).Error("Failed to claim stale messages")
+			}
+			return
+		}
+
+		for _, msg := range messages {
+			msg.Values["stream"] = stream
+			if !q.withinDeliveryLimit(ctx, stream, msg) {
+				continue
+			}
+
+			q.logger.WithFields(map[string]interface{}{
+				"stream":     stream,
+				"message_id": msg.ID,
+			}).Warn("Reclaimed stale message")
+
+			select {
+			case q.claimed <- msg:
+			case <-ctx.Done():
+				return
+			case <-q.stopChan:
+				return
+			}
+		}
+
+		if next == "" || next == "0-0" {
+			return
+		}
+		start = next
+	}
+}
+
+// withinDeliveryLimit dead-letters messages that have been delivered too many times,
+// which usually means the job crashes its worker
+func (q *RedisStreamsQueue) withinDeliveryLimit(ctx context.Context, stream string, msg redis.XMessage) bool {
+	data, _ := msg.Values["data"].(string)
+
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		q.logger.WithError(err).WithField("message_id", msg.ID).Error("Failed to unmarshal claimed job, discarding message")
+		q.ack(ctx, stream, msg.ID, q.logger)
+		return false
+	}
+
+	pending, err := q.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  q.config.ConsumerGroup,
+		Start:  msg.ID,
+		End:    msg.ID,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return true
+	}
+
+	maxDeliveries := int64(job.MaxRetries)
+	if maxDeliveries <= 0 {
+		maxDeliveries = int64(q.config.MaxRetries)
+	}
+	if pending[0].RetryCount <= maxDeliveries {
+		return true
+	}
+
+	jobLogger := q.logger.WithFields(map[string]interface{}{
+		"job_id":     job.ID,
+		"job_type":   job.Type,
+		"message_id": msg.ID,
+		"deliveries": pending[0].RetryCount,
+	})
+	q.deadLetter(ctx, &job, fmt.Errorf("exceeded %d deliveries without acknowledgement", maxDeliveries), jobLogger)
+	q.ack(ctx, stream, msg.ID, jobLogger)
+	return false
+}
+
+// cleanup periodically purges acknowledged entries older than the result TTL
+func (q *RedisStreamsQueue) cleanup(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopChan:
+			return
+		case <-ticker.C:
+			if _, err := q.PurgeCompletedJobs(ctx, q.config.ResultTTL); err != nil {
+				q.logger.WithError(err).Error("Failed to purge completed stream entries")
+			}
+		}
+	}
+}
+
+// storeJobResult stores the job result in Redis
+func (q *RedisStreamsQueue) storeJobResult(ctx context.Context, result *JobResult, jobLogger logger.Logger) {
+	resultData, err := json.Marshal(result)
+	if err != nil {
+		jobLogger.WithError(err).Error("Failed to marshal job result")
+		return
+	}
+
+	if err := q.client.Set(ctx, q.getResultKey(result.JobID), resultData, q.config.ResultTTL).Err(); err != nil {
+		jobLogger.WithError(err).Error("Failed to store job result")
+	}
+}
+
+// ack acknowledges a message in the consumer group
+func (q *RedisStreamsQueue) ack(ctx context.Context, stream, messageID string, jobLogger logger.Logger) {
+	if stream == "" {
+		return
+	}
+	if err := q.client.XAck(ctx, stream, q.config.ConsumerGroup, messageID).Err(); err != nil {
+		jobLogger.WithError(err).Error("Failed to acknowledge message")
+	}
+}
+
+// sleep waits for the given duration unless the queue is stopped first
+func (q *RedisStreamsQueue) sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	case <-q.stopChan:
+	}
+}
+
+// prepareJob fills in job defaults before it is enqueued
+func (q *RedisStreamsQueue) prepareJob(job *Job) {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now()
+	}
+	if job.MaxRetries == 0 {
+		job.MaxRetries = q.config.MaxRetries
+	}
+}
+
+// xAddArgs builds the XADD arguments for a job
+func (q *RedisStreamsQueue) xAddArgs(job *Job, jobData []byte) *redis.XAddArgs {
+	return &redis.XAddArgs{
+		Stream: q.getStreamKey(PriorityBand(job.Priority)),
+		MaxLen: q.config.StreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"job_id": job.ID,
+			"data":   string(jobData),
+		},
+	}
+}
+
+// flattenStreams collects the messages of an XREADGROUP reply, tagging each with its stream
+func flattenStreams(streams []redis.XStream) []redis.XMessage {
+	var messages []redis.XMessage
+	for _, s := range streams {
+		for _, msg := range s.Messages {
+			if msg.Values == nil {
+				msg.Values = make(map[string]interface{})
+			}
+			msg.Values["stream"] = s.Stream
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// isMissingStreamErr reports whether err means the stream or group does not exist yet
+func isMissingStreamErr(err error) bool {
+	if errors.Is(err, redis.Nil) {
+		return true
+	}
+	msg := err.Error()
+	return strings.HasPrefix(msg, "NOGROUP") || strings.Contains(msg, "no such key")
+}
+
+// compareStreamIDs compares two stream IDs of the form <ms>-<seq>
+func compareStreamIDs(a, b string) int {
+	aMs, aSeq := parseStreamID(a)
+	bMs, bSeq := parseStreamID(b)
+
+	switch {
+	case aMs < bMs:
+		return -1
+	case aMs > bMs:
+		return 1
+	case aSeq < bSeq:
+		return -1
+	case aSeq > bSeq:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// nextStreamID returns the smallest stream ID greater than id
+func nextStreamID(id string) string {
+	ms, seq := parseStreamID(id)
+	return fmt.Sprintf("%d-%d", ms, seq+1)
+}
+
+func parseStreamID(id string) (uint64, uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ := strconv.ParseUint(msPart, 10, 64)
+	seq, _ := strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
+
+// Redis key helpers
+func (q *RedisStreamsQueue) getStreamKey(band string) string {
+	return fmt.Sprintf("%s:stream:%s", q.config.QueueName, band)
+}
+
+func (q *RedisStreamsQueue) getJobKey(jobID string) string {
+	return fmt.Sprintf("%s:job:%s", q.config.QueueName, jobID)
+}
+
+func (q *RedisStreamsQueue) getResultKey(jobID string) string {
+	return fmt.Sprintf("%s:result:%s", q.config.QueueName, jobID)
+}
+
+func (q *RedisStreamsQueue) getDelayedKey() string {
+	return fmt.Sprintf("%s:delayed", q.config.QueueName)
+}
+
+func (q *RedisStreamsQueue) getFailedKey() string {
+	return fmt.Sprintf("%s:failed", q.config.QueueName)
+}
+
+func (q *RedisStreamsQueue) getCompletedKey() string {
+	return fmt.Sprintf("%s:stats:completed", q.config.QueueName)
+}
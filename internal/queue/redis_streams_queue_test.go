@@ -0,0 +1,189 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/pkg/database"
+)
+
+// newTestStreamsQueue returns a streams queue with its own key prefix on a
+// local Redis, skipping the test when Redis is not available
+func newTestStreamsQueue(t *testing.T, config Config) (*RedisStreamsQueue, *redis.Client) {
+	t.Helper()
+
+	client, err := database.NewRedisClientWithOptions(&redis.Options{
+		Addr: "localhost:6379",
+		DB:   1, // Use different DB for testing
+	}, time.Second)
+	if err != nil {
+		t.Skip("Redis not available, skipping streams queue tests:", err)
+	}
+
+	config.QueueName = "test_streams_" + uuid.NewString()
+	config.ConsumerName = "consumer-1"
+	if config.PollInterval == 0 {
+		config.PollInterval = 50 * time.Millisecond
+	}
+	q := NewRedisStreamsQueue(client, newTestLogger(t), config)
+
+	t.Cleanup(func() {
+		_ = q.Close()
+		ctx := context.Background()
+		keys, _ := client.Keys(ctx, config.QueueName+":*").Result()
+		if len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+		client.Close()
+	})
+	return q, client
+}
+
+// pendingCount returns how many messages of the job's band await acknowledgement
+func pendingCount(t *testing.T, q *RedisStreamsQueue, client *redis.Client, job *Job) int64 {
+	t.Helper()
+	pending, err := client.XPending(context.Background(), q.getStreamKey(PriorityBand(job.Priority)), q.config.ConsumerGroup).Result()
+	require.NoError(t, err)
+	return pending.Count
+}
+
+func TestRedisStreamsQueue_AcksCompletedJobs(t *testing.T) {
+	q, client := newTestStreamsQueue(t, Config{})
+	ctx := context.Background()
+
+	handler := newRecordingHandler("test", 0)
+	q.RegisterHandler("test", handler)
+	require.NoError(t, q.StartConsuming(ctx, 1))
+
+	job := &Job{Type: "test", Priority: 5}
+	require.NoError(t, q.Enqueue(ctx, job))
+	waitForJobs(t, handler, 1)
+
+	require.Eventually(t, func() bool {
+		return pendingCount(t, q, client, job) == 0
+	}, 2*time.Second, 10*time.Millisecond, "the message is acknowledged")
+
+	result, err := q.GetJobResult(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusCompleted, result.Status)
+}
+
+func TestRedisStreamsQueue_ReclaimsIdleMessages(t *testing.T) {
+	q, client := newTestStreamsQueue(t, Config{ClaimMinIdle: 200 * time.Millisecond})
+	ctx := context.Background()
+
+	job := &Job{Type: "test", Priority: 5}
+	require.NoError(t, q.Enqueue(ctx, job))
+
+	// Another consumer reads the job and dies without acknowledging it
+	require.NoError(t, q.ensureGroups(ctx))
+	streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.config.ConsumerGroup,
+		Consumer: "crashed",
+		Streams:  []string{q.getStreamKey(PriorityBand(job.Priority)), ">"},
+		Count:    1,
+	}).Result()
+	require.NoError(t, err)
+	require.Len(t, flattenStreams(streams), 1)
+
+	handler := newRecordingHandler("test", 0)
+	q.RegisterHandler("test", handler)
+	require.NoError(t, q.StartConsuming(ctx, 1))
+
+	// The reclaimer runs every second at the earliest
+	select {
+	case id := <-handler.done:
+		assert.Equal(t, job.ID, id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the stale message to be reclaimed")
+	}
+
+	require.Eventually(t, func() bool {
+		return pendingCount(t, q, client, job) == 0
+	}, 2*time.Second, 10*time.Millisecond, "the reclaimed message is acknowledged")
+}
+
+func TestRedisStreamsQueue_DeadLettersAfterMaxRetries(t *testing.T) {
+	q, client := newTestStreamsQueue(t, Config{MaxRetries: 2, RetryDelay: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	handler := newRecordingHandler("test", 100)
+	q.RegisterHandler("test", handler)
+	require.NoError(t, q.StartConsuming(ctx, 1))
+
+	job := &Job{Type: "test", Priority: 5}
+	require.NoError(t, q.Enqueue(ctx, job))
+	waitForJobs(t, handler, 2)
+
+	require.Eventually(t, func() bool {
+		result, err := q.GetJobResult(ctx, job.ID)
+		return err == nil && result.Status == JobStatusFailed
+	}, 2*time.Second, 10*time.Millisecond)
+
+	failed, err := q.GetFailedJobs(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, job.ID, failed[0].ID)
+	assert.Equal(t, 2, failed[0].Retries)
+	assert.Equal(t, int64(0), pendingCount(t, q, client, job))
+
+	// No third attempt is made
+	select {
+	case <-handler.done:
+		t.Fatal("the job ran again after reaching its retry limit")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestRedisStreamsQueue_DeadLettersRedeliveredMessages(t *testing.T) {
+	q, client := newTestStreamsQueue(t, Config{MaxRetries: 1})
+	ctx := context.Background()
+
+	job := &Job{Type: "test", Priority: 5}
+	require.NoError(t, q.Enqueue(ctx, job))
+	require.NoError(t, q.ensureGroups(ctx))
+	stream := q.getStreamKey(PriorityBand(job.Priority))
+
+	// Delivered once, then claimed again after its consumer died: one
+	// delivery more than the job allows
+	_, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.config.ConsumerGroup,
+		Consumer: "crashed",
+		Streams:  []string{stream, ">"},
+		Count:    1,
+	}).Result()
+	require.NoError(t, err)
+
+	pending, err := client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  q.config.ConsumerGroup,
+		Start:  "-",
+		End:    "+",
+		Count:  1,
+	}).Result()
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	messages, err := client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    q.config.ConsumerGroup,
+		Consumer: q.config.ConsumerName,
+		Messages: []string{pending[0].ID},
+	}).Result()
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	assert.False(t, q.withinDeliveryLimit(ctx, stream, messages[0]))
+	assert.Equal(t, int64(0), pendingCount(t, q, client, job))
+
+	failed, err := q.GetFailedJobs(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, job.ID, failed[0].ID)
+}
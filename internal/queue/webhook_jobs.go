@@ -0,0 +1,93 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/webhook"
+	"mem_bank/pkg/logger"
+)
+
+// JobTypeDeliverWebhook is the job type that delivers an event to a
+// webhook subscription
+const JobTypeDeliverWebhook = "deliver_webhook"
+
+// DeliverWebhookHandler handles webhook delivery jobs
+type DeliverWebhookHandler struct {
+	webhookService webhook.Service
+	logger         logger.Logger
+}
+
+// NewDeliverWebhookHandler creates a new webhook delivery handler
+func NewDeliverWebhookHandler(webhookService webhook.Service, logger logger.Logger) *DeliverWebhookHandler {
+	return &DeliverWebhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// Handle processes a webhook delivery job. A failed delivery fails the job,
+// so the queue retries it with backoff until the job's retries run out.
+func (h *DeliverWebhookHandler) Handle(ctx context.Context, job *Job) (*JobResult, error) {
+	subscriptionIDStr, ok := job.Payload["subscription_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid subscription_id in job payload")
+	}
+	eventIDStr, ok := job.Payload["event_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or invalid event_id in job payload")
+	}
+
+	subscriptionID, err := uuid.Parse(subscriptionIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription ID: %w", err)
+	}
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event ID: %w", err)
+	}
+
+	attempt := job.Retries + 1
+	if err := h.webhookService.Deliver(ctx, webhook.ID(subscriptionID), event.ID(eventID), attempt); err != nil {
+		return nil, fmt.Errorf("delivering webhook: %w", err)
+	}
+
+	return &JobResult{
+		Result: map[string]interface{}{
+			"subscription_id": subscriptionIDStr,
+			"event_id":        eventIDStr,
+			"attempt":         attempt,
+		},
+	}, nil
+}
+
+// Name returns the handler name
+func (h *DeliverWebhookHandler) Name() string {
+	return "DeliverWebhookHandler"
+}
+
+// JobType returns the job type this handler processes
+func (h *DeliverWebhookHandler) JobType() string {
+	return JobTypeDeliverWebhook
+}
+
+// CreateDeliverWebhookJob creates a job that delivers e to a subscription,
+// attempted up to maxAttempts times. The payload names the subscriber so
+// the job is purged with the rest of their data on erasure.
+func (f *JobFactory) CreateDeliverWebhookJob(sub *webhook.Subscription, e *event.Event, maxAttempts int) *Job {
+	return &Job{
+		Type:       JobTypeDeliverWebhook,
+		Priority:   5,
+		MaxRetries: maxAttempts,
+		Payload: map[string]interface{}{
+			"subscription_id": sub.ID.String(),
+			"event_id":        e.ID.String(),
+			"user_id":         sub.UserID.String(),
+		},
+		CreatedAt: time.Now(),
+	}
+}
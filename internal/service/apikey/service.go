@@ -0,0 +1,232 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/apikey"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+const (
+	// prefixBytes and secretBytes size the public and secret parts of a key
+	prefixBytes = 4
+	secretBytes = 32
+
+	// maxNameLength bounds key names
+	maxNameLength = 100
+
+	// lastUsedInterval limits how often use of a key is written back
+	lastUsedInterval = time.Minute
+)
+
+// Config holds API key configuration
+type Config struct {
+	// Maximum number of unrevoked keys per user
+	MaxKeysPerUser int `mapstructure:"max_keys_per_user"`
+}
+
+// DefaultConfig returns the default API key configuration
+func DefaultConfig() Config {
+	return Config{MaxKeysPerUser: 10}
+}
+
+// Service implements apikey.Service. Keys look like mbk_<prefix>_<secret>;
+// the prefix identifies a key and only a hash of the whole key is stored.
+type Service struct {
+	repo     apikey.Repository
+	userRepo user.Repository
+	logger   logger.Logger
+	config   Config
+	now      func() time.Time
+}
+
+// NewService creates a new API key service
+func NewService(repo apikey.Repository, userRepo user.Repository, logger logger.Logger, config Config) *Service {
+	if config.MaxKeysPerUser <= 0 {
+		config.MaxKeysPerUser = DefaultConfig().MaxKeysPerUser
+	}
+
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+		logger:   logger,
+		config:   config,
+		now:      time.Now,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, userID user.ID, req apikey.CreateRequest) (*apikey.APIKey, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, "", apikey.ErrInvalidName
+	}
+
+	scopes, err := s.validateScopes(ctx, req.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.now()
+	if !req.ExpiresAt.IsZero() && !req.ExpiresAt.After(now) {
+		return nil, "", apikey.ErrInvalidExpiry
+	}
+
+	count, err := s.repo.CountActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= s.config.MaxKeysPerUser {
+		return nil, "", apikey.ErrTooManyKeys
+	}
+
+	prefix, secret, err := newKey()
+	if err != nil {
+		return nil, "", err
+	}
+	raw := prefix + "_" + secret
+
+	key := &apikey.APIKey{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashKey(raw),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: now,
+	}
+	if err := s.repo.Store(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"user_id": userID.String(),
+		"key_id":  key.ID.String(),
+		"prefix":  prefix,
+	}).Info("API key created")
+
+	return key, raw, nil
+}
+
+func (s *Service) List(ctx context.Context, userID user.ID) ([]*apikey.APIKey, error) {
+	return s.repo.ListByUserID(ctx, userID)
+}
+
+func (s *Service) Get(ctx context.Context, userID user.ID, id uuid.UUID) (*apikey.APIKey, error) {
+	key, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Other users' keys are reported as missing
+	if key.UserID != userID {
+		return nil, apikey.ErrNotFound
+	}
+	return key, nil
+}
+
+func (s *Service) Revoke(ctx context.Context, userID user.ID, id uuid.UUID) error {
+	if err := s.repo.Revoke(ctx, userID, id); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"user_id": userID.String(),
+		"key_id":  id.String(),
+	}).Info("API key revoked")
+	return nil
+}
+
+func (s *Service) Authenticate(ctx context.Context, raw string) (*apikey.APIKey, *user.User, error) {
+	if !strings.HasPrefix(raw, apikey.KeyPrefix) {
+		return nil, nil, apikey.ErrInvalidKey
+	}
+
+	key, err := s.repo.FindByHash(ctx, hashKey(raw))
+	if errors.Is(err, apikey.ErrNotFound) {
+		return nil, nil, apikey.ErrInvalidKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := s.now()
+	if key.IsRevoked() || key.IsExpired(now) {
+		return nil, nil, apikey.ErrInvalidKey
+	}
+
+	u, err := s.userRepo.FindByID(ctx, key.UserID)
+	if errors.Is(err, user.ErrNotFound) {
+		return nil, nil, apikey.ErrInvalidKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !u.IsActive {
+		return nil, nil, user.ErrInactive
+	}
+
+	if now.Sub(key.LastUsedAt) >= lastUsedInterval {
+		if err := s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			// Tracking is best effort and must not fail the request
+			s.logger.WithError(err).WithField("key_id", key.ID.String()).Warn("Failed to record API key use")
+		} else {
+			key.LastUsedAt = now
+		}
+	}
+
+	return key, u, nil
+}
+
+// validateScopes checks and deduplicates requested scopes. Only admins may
+// hand out the admin scope.
+func (s *Service) validateScopes(ctx context.Context, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return nil, apikey.ErrInvalidScope
+	}
+
+	seen := make(map[string]bool, len(requested))
+	scopes := make([]string, 0, len(requested))
+	for _, scope := range requested {
+		if !apikey.ValidScope(scope) {
+			return nil, fmt.Errorf("%w: %q", apikey.ErrInvalidScope, scope)
+		}
+		if scope == apikey.ScopeAdmin {
+			if p, _ := auth.PrincipalFromContext(ctx); !p.IsPrivileged() {
+				return nil, auth.ErrPermissionDenied
+			}
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// newKey returns the public prefix and the secret of a new key
+func newKey() (string, string, error) {
+	buf := make([]byte, prefixBytes+secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("generating API key: %w", err)
+	}
+	prefix := apikey.KeyPrefix + hex.EncodeToString(buf[:prefixBytes])
+	return prefix, base64.RawURLEncoding.EncodeToString(buf[prefixBytes:]), nil
+}
+
+// hashKey returns the stored form of an API key
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,475 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/apikey"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// Mock API key repository
+type mockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *mockAPIKeyRepository) Store(ctx context.Context, key *apikey.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *mockAPIKeyRepository) FindByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*apikey.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*apikey.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*apikey.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyRepository) ListByUserID(ctx context.Context, userID user.ID) ([]*apikey.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*apikey.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyRepository) CountActiveByUserID(ctx context.Context, userID user.ID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockAPIKeyRepository) Revoke(ctx context.Context, userID user.ID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func (m *mockAPIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, t time.Time) error {
+	args := m.Called(ctx, id, t)
+	return args.Error(0)
+}
+
+// Mock user repository
+type mockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id user.ID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+var testNow = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestService(repo *mockAPIKeyRepository, userRepo *mockUserRepository, log *mockLogger) *Service {
+	svc := NewService(repo, userRepo, log, Config{MaxKeysPerUser: 2})
+	svc.now = func() time.Time { return testNow }
+	return svc
+}
+
+func TestService_Create(t *testing.T) {
+	userID := user.ID(uuid.New())
+	dbErr := errors.New("database error")
+	userCtx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: auth.RoleUser})
+	adminCtx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: auth.RoleAdmin})
+
+	testCases := []struct {
+		name       string
+		ctx        context.Context
+		req        apikey.CreateRequest
+		setupMocks func(*mockAPIKeyRepository, *mockLogger)
+		wantScopes []string
+		wantErr    error
+	}{
+		{
+			name: "successful creation with duplicate scopes",
+			ctx:  userCtx,
+			req:  apikey.CreateRequest{Name: " ci ", Scopes: []string{apikey.ScopeMemoriesRead, apikey.ScopeMemoriesRead}},
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {
+				r.On("CountActiveByUserID", mock.Anything, userID).Return(1, nil)
+				r.On("Store", mock.Anything, mock.AnythingOfType("*apikey.APIKey")).Return(nil)
+				l.On("Info", "API key created").Once()
+			},
+			wantScopes: []string{apikey.ScopeMemoriesRead},
+		},
+		{
+			name: "admin may create admin key",
+			ctx:  adminCtx,
+			req:  apikey.CreateRequest{Name: "root", Scopes: []string{apikey.ScopeAdmin}},
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {
+				r.On("CountActiveByUserID", mock.Anything, userID).Return(0, nil)
+				r.On("Store", mock.Anything, mock.AnythingOfType("*apikey.APIKey")).Return(nil)
+				l.On("Info", "API key created").Once()
+			},
+			wantScopes: []string{apikey.ScopeAdmin},
+		},
+		{
+			name:       "blank name",
+			ctx:        userCtx,
+			req:        apikey.CreateRequest{Name: " ", Scopes: []string{apikey.ScopeMemoriesRead}},
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {},
+			wantErr:    apikey.ErrInvalidName,
+		},
+		{
+			name:       "no scopes",
+			ctx:        userCtx,
+			req:        apikey.CreateRequest{Name: "none"},
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {},
+			wantErr:    apikey.ErrInvalidScope,
+		},
+		{
+			name:       "unknown scope",
+			ctx:        userCtx,
+			req:        apikey.CreateRequest{Name: "bad", Scopes: []string{"memories:delete"}},
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {},
+			wantErr:    apikey.ErrInvalidScope,
+		},
+		{
+			name:       "user may not create admin key",
+			ctx:        userCtx,
+			req:        apikey.CreateRequest{Name: "root", Scopes: []string{apikey.ScopeAdmin}},
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {},
+			wantErr:    auth.ErrPermissionDenied,
+		},
+		{
+			name:       "expiry in the past",
+			ctx:        userCtx,
+			req:        apikey.CreateRequest{Name: "past", Scopes: []string{apikey.ScopeMemoriesRead}, ExpiresAt: testNow.Add(-time.Minute)},
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {},
+			wantErr:    apikey.ErrInvalidExpiry,
+		},
+		{
+			name: "too many keys",
+			ctx:  userCtx,
+			req:  apikey.CreateRequest{Name: "third", Scopes: []string{apikey.ScopeMemoriesRead}},
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {
+				r.On("CountActiveByUserID", mock.Anything, userID).Return(2, nil)
+			},
+			wantErr: apikey.ErrTooManyKeys,
+		},
+		{
+			name: "store error",
+			ctx:  userCtx,
+			req:  apikey.CreateRequest{Name: "ci", Scopes: []string{apikey.ScopeMemoriesRead}},
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {
+				r.On("CountActiveByUserID", mock.Anything, userID).Return(0, nil)
+				r.On("Store", mock.Anything, mock.AnythingOfType("*apikey.APIKey")).Return(dbErr)
+			},
+			wantErr: dbErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockAPIKeyRepository{}
+			log := &mockLogger{}
+			tc.setupMocks(repo, log)
+
+			key, secret, err := newTestService(repo, &mockUserRepository{}, log).Create(tc.ctx, userID, tc.req)
+
+			repo.AssertExpectations(t)
+			log.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, key)
+				assert.Empty(t, secret)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, userID, key.UserID)
+			assert.Equal(t, strings.TrimSpace(tc.req.Name), key.Name)
+			assert.Equal(t, tc.wantScopes, key.Scopes)
+			assert.True(t, strings.HasPrefix(key.Prefix, apikey.KeyPrefix))
+			assert.True(t, strings.HasPrefix(secret, key.Prefix+"_"))
+
+			// Only the hash is stored
+			stored := repo.Calls[1].Arguments.Get(1).(*apikey.APIKey)
+			assert.NotContains(t, stored.KeyHash, secret)
+			assert.Equal(t, hashKey(secret), stored.KeyHash)
+		})
+	}
+}
+
+func TestService_Authenticate(t *testing.T) {
+	prefix, secret, err := newKey()
+	require.NoError(t, err)
+	raw := prefix + "_" + secret
+
+	u := &user.User{ID: user.ID(uuid.New()), Username: "alice", IsActive: true}
+	dbErr := errors.New("database error")
+	newAPIKey := func() *apikey.APIKey {
+		return &apikey.APIKey{
+			ID:      uuid.New(),
+			UserID:  u.ID,
+			Prefix:  prefix,
+			KeyHash: hashKey(raw),
+			Scopes:  []string{apikey.ScopeMemoriesRead},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		raw          string
+		setupMocks   func(*mockAPIKeyRepository, *mockUserRepository, *mockLogger, *apikey.APIKey)
+		wantLastUsed time.Time
+		wantErr      error
+	}{
+		{
+			name: "successful authentication",
+			raw:  raw,
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {
+				r.On("FindByHash", mock.Anything, hashKey(raw)).Return(key, nil)
+				ur.On("FindByID", mock.Anything, u.ID).Return(u, nil)
+				r.On("TouchLastUsed", mock.Anything, key.ID, testNow).Return(nil)
+			},
+			wantLastUsed: testNow,
+		},
+		{
+			name: "recent use is not written back",
+			raw:  raw,
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {
+				key.LastUsedAt = testNow.Add(-time.Second)
+				r.On("FindByHash", mock.Anything, hashKey(raw)).Return(key, nil)
+				ur.On("FindByID", mock.Anything, u.ID).Return(u, nil)
+			},
+			wantLastUsed: testNow.Add(-time.Second),
+		},
+		{
+			name: "failing use tracking does not fail authentication",
+			raw:  raw,
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {
+				r.On("FindByHash", mock.Anything, hashKey(raw)).Return(key, nil)
+				ur.On("FindByID", mock.Anything, u.ID).Return(u, nil)
+				r.On("TouchLastUsed", mock.Anything, key.ID, testNow).Return(dbErr)
+				l.On("Warn", "Failed to record API key use").Once()
+			},
+		},
+		{
+			name:       "missing key prefix",
+			raw:        "not-a-key",
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {},
+			wantErr:    apikey.ErrInvalidKey,
+		},
+		{
+			name: "unknown key",
+			raw:  raw + "x",
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {
+				r.On("FindByHash", mock.Anything, hashKey(raw+"x")).Return(nil, apikey.ErrNotFound)
+			},
+			wantErr: apikey.ErrInvalidKey,
+		},
+		{
+			name: "repository error",
+			raw:  raw,
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {
+				r.On("FindByHash", mock.Anything, hashKey(raw)).Return(nil, dbErr)
+			},
+			wantErr: dbErr,
+		},
+		{
+			name: "revoked key",
+			raw:  raw,
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {
+				key.RevokedAt = testNow.Add(-time.Hour)
+				r.On("FindByHash", mock.Anything, hashKey(raw)).Return(key, nil)
+			},
+			wantErr: apikey.ErrInvalidKey,
+		},
+		{
+			name: "expired key",
+			raw:  raw,
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {
+				key.ExpiresAt = testNow.Add(-time.Hour)
+				r.On("FindByHash", mock.Anything, hashKey(raw)).Return(key, nil)
+			},
+			wantErr: apikey.ErrInvalidKey,
+		},
+		{
+			name: "deleted owner",
+			raw:  raw,
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {
+				r.On("FindByHash", mock.Anything, hashKey(raw)).Return(key, nil)
+				ur.On("FindByID", mock.Anything, u.ID).Return(nil, user.ErrNotFound)
+			},
+			wantErr: apikey.ErrInvalidKey,
+		},
+		{
+			name: "inactive owner",
+			raw:  raw,
+			setupMocks: func(r *mockAPIKeyRepository, ur *mockUserRepository, l *mockLogger, key *apikey.APIKey) {
+				r.On("FindByHash", mock.Anything, hashKey(raw)).Return(key, nil)
+				ur.On("FindByID", mock.Anything, u.ID).Return(&user.User{ID: u.ID}, nil)
+			},
+			wantErr: user.ErrInactive,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockAPIKeyRepository{}
+			userRepo := &mockUserRepository{}
+			log := &mockLogger{}
+			key := newAPIKey()
+			tc.setupMocks(repo, userRepo, log, key)
+
+			found, owner, err := newTestService(repo, userRepo, log).Authenticate(context.Background(), tc.raw)
+
+			repo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			log.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, found)
+				assert.Nil(t, owner)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, found.ID)
+			assert.Equal(t, u.ID, owner.ID)
+			assert.True(t, found.HasScope(apikey.ScopeMemoriesRead))
+			assert.False(t, found.HasScope(apikey.ScopeMemoriesWrite))
+			assert.Equal(t, tc.wantLastUsed, found.LastUsedAt)
+		})
+	}
+}
+
+func TestService_Get(t *testing.T) {
+	userID := user.ID(uuid.New())
+	key := &apikey.APIKey{ID: uuid.New(), UserID: userID, Name: "ci"}
+
+	testCases := []struct {
+		name       string
+		userID     user.ID
+		setupMocks func(*mockAPIKeyRepository)
+		wantErr    error
+	}{
+		{
+			name:   "own key",
+			userID: userID,
+			setupMocks: func(r *mockAPIKeyRepository) {
+				r.On("FindByID", mock.Anything, key.ID).Return(key, nil)
+			},
+		},
+		{
+			name:   "other user's key",
+			userID: user.ID(uuid.New()),
+			setupMocks: func(r *mockAPIKeyRepository) {
+				r.On("FindByID", mock.Anything, key.ID).Return(key, nil)
+			},
+			wantErr: apikey.ErrNotFound,
+		},
+		{
+			name:   "missing key",
+			userID: userID,
+			setupMocks: func(r *mockAPIKeyRepository) {
+				r.On("FindByID", mock.Anything, key.ID).Return(nil, apikey.ErrNotFound)
+			},
+			wantErr: apikey.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockAPIKeyRepository{}
+			tc.setupMocks(repo)
+
+			found, err := newTestService(repo, &mockUserRepository{}, &mockLogger{}).Get(context.Background(), tc.userID, key.ID)
+
+			repo.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, found)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ci", found.Name)
+		})
+	}
+}
+
+func TestService_Revoke(t *testing.T) {
+	userID := user.ID(uuid.New())
+	keyID := uuid.New()
+
+	testCases := []struct {
+		name       string
+		setupMocks func(*mockAPIKeyRepository, *mockLogger)
+		wantErr    error
+	}{
+		{
+			name: "successful revocation",
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {
+				r.On("Revoke", mock.Anything, userID, keyID).Return(nil)
+				l.On("Info", "API key revoked").Once()
+			},
+		},
+		{
+			name: "missing or foreign key",
+			setupMocks: func(r *mockAPIKeyRepository, l *mockLogger) {
+				r.On("Revoke", mock.Anything, userID, keyID).Return(apikey.ErrNotFound)
+			},
+			wantErr: apikey.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockAPIKeyRepository{}
+			log := &mockLogger{}
+			tc.setupMocks(repo, log)
+
+			err := newTestService(repo, &mockUserRepository{}, log).Revoke(context.Background(), userID, keyID)
+
+			repo.AssertExpectations(t)
+			log.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
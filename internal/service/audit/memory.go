@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/audit"
+	"mem_bank/internal/domain/memory"
+)
+
+// memoryService records the reads and changes of single memories made
+// through a memory.Service. Listing and search pass through unrecorded.
+type memoryService struct {
+	memory.Service
+	repo     memory.Repository
+	recorder audit.Recorder
+}
+
+// NewMemoryService wraps memories so that successful operations on single
+// memories are recorded; repo is used to read a memory's state before it
+// changes
+func NewMemoryService(memories memory.Service, repo memory.Repository, recorder audit.Recorder) memory.Service {
+	return &memoryService{Service: memories, repo: repo, recorder: recorder}
+}
+
+func (s *memoryService) CreateMemory(ctx context.Context, req memory.CreateRequest) (*memory.Memory, error) {
+	m, err := s.Service.CreateMemory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.recorder.Record(ctx, audit.ActionMemoryCreate, audit.ResourceMemory, m.ID.String(), nil, m)
+	return m, nil
+}
+
+func (s *memoryService) BatchCreateMemories(ctx context.Context, reqs []memory.CreateRequest) ([]*memory.Memory, error) {
+	memories, err := s.Service.BatchCreateMemories(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range memories {
+		s.recorder.Record(ctx, audit.ActionMemoryCreate, audit.ResourceMemory, m.ID.String(), nil, m)
+	}
+	return memories, nil
+}
+
+func (s *memoryService) GetMemory(ctx context.Context, id memory.ID) (*memory.Memory, error) {
+	m, err := s.Service.GetMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.recorder.Record(ctx, audit.ActionMemoryRead, audit.ResourceMemory, id.String(), nil, nil)
+	return m, nil
+}
+
+func (s *memoryService) RehydrateMemory(ctx context.Context, id memory.ID) (*memory.Memory, error) {
+	m, err := s.Service.RehydrateMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.recorder.Record(ctx, audit.ActionMemoryRehydrate, audit.ResourceMemory, id.String(), nil, nil)
+	return m, nil
+}
+
+func (s *memoryService) UpdateMemory(ctx context.Context, id memory.ID, req memory.UpdateRequest) (*memory.Memory, error) {
+	before := s.find(ctx, id)
+	m, err := s.Service.UpdateMemory(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	s.recorder.Record(ctx, audit.ActionMemoryUpdate, audit.ResourceMemory, id.String(), before, m)
+	return m, nil
+}
+
+func (s *memoryService) DeleteMemory(ctx context.Context, id memory.ID) error {
+	before := s.find(ctx, id)
+	if err := s.Service.DeleteMemory(ctx, id); err != nil {
+		return err
+	}
+	s.recorder.Record(ctx, audit.ActionMemoryDelete, audit.ResourceMemory, id.String(), before, nil)
+	return nil
+}
+
+// find returns the memory's current state, or nil if it cannot be read;
+// the wrapped service reports why
+func (s *memoryService) find(ctx context.Context, id memory.ID) interface{} {
+	m, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,175 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mem_bank/internal/constants"
+	"mem_bank/internal/domain/audit"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/queue"
+	"mem_bank/pkg/logger"
+)
+
+// Limits on the entries returned by Query
+const (
+	defaultQueryLimit = 50
+	maxQueryLimit     = 500
+)
+
+// verifyBatchSize is how many entries are read per page when verifying the chain
+const verifyBatchSize = 500
+
+// Service implements audit.Service
+type Service struct {
+	repo       audit.Repository
+	jobQueue   queue.Producer
+	jobFactory *queue.JobFactory
+	chained    bool
+	logger     logger.Logger
+	now        func() time.Time
+}
+
+// NewService creates a new audit service. Entries are recorded through
+// jobQueue and appended by the audit job; chained tells whether repo keeps
+// the hash chain.
+func NewService(repo audit.Repository, jobQueue queue.Producer, chained bool, logger logger.Logger) *Service {
+	return &Service{
+		repo:       repo,
+		jobQueue:   jobQueue,
+		jobFactory: queue.NewJobFactory(),
+		chained:    chained,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+func (s *Service) Record(ctx context.Context, action, resourceType, resourceID string, before, after interface{}) {
+	e := &audit.Entry{
+		ID:           audit.NewID(),
+		Actor:        actorFromContext(ctx),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		RequestID:    requestIDFromContext(ctx),
+		BeforeHash:   hashState(before),
+		AfterHash:    hashState(after),
+		// The log keeps microseconds; the chain hash must survive the trip
+		CreatedAt: s.now().UTC().Truncate(time.Microsecond),
+	}
+
+	// The entry is queued even if the request is cancelled once answered
+	job := s.jobFactory.CreateAuditEntryJob(e)
+	if err := s.jobQueue.Enqueue(context.WithoutCancel(ctx), job); err != nil {
+		s.logger.WithError(err).WithFields(map[string]interface{}{
+			"action":      action,
+			"resource_id": resourceID,
+		}).Error("Failed to queue audit entry")
+	}
+}
+
+func (s *Service) Append(ctx context.Context, e *audit.Entry) error {
+	return s.repo.Append(ctx, e)
+}
+
+func (s *Service) Query(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, fmt.Errorf("%w: limit and offset must not be negative", audit.ErrInvalidFilter)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return nil, fmt.Errorf("%w: since must be before until", audit.ErrInvalidFilter)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultQueryLimit
+	}
+	filter.Limit = min(filter.Limit, maxQueryLimit)
+
+	return s.repo.Find(ctx, filter)
+}
+
+func (s *Service) VerifyChain(ctx context.Context) (*audit.ChainVerification, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if !s.chained {
+		return nil, audit.ErrChainNotEnabled
+	}
+
+	result := &audit.ChainVerification{Valid: true}
+	var sequence int64
+	prevHash := ""
+	for {
+		entries, err := s.repo.ListAfter(ctx, sequence, verifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			sequence = e.Sequence
+			// Entries appended before the chain was enabled are not linked
+			if e.Hash == "" {
+				continue
+			}
+			result.Checked++
+			if e.PrevHash != prevHash || e.Hash != e.ChainHash(prevHash) {
+				result.Valid = false
+				result.BrokenAt = e.Sequence
+				return result, nil
+			}
+			prevHash = e.Hash
+		}
+		if len(entries) < verifyBatchSize {
+			return result, nil
+		}
+	}
+}
+
+func authorizeAdmin(ctx context.Context) error {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok || !p.IsPrivileged() {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
+// actorFromContext describes the caller in ctx; callers without a principal
+// are internal, such as background jobs
+func actorFromContext(ctx context.Context) audit.Actor {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return audit.Actor{Type: audit.ActorSystem}
+	}
+
+	actor := audit.Actor{Type: audit.ActorUser, UserID: p.UserID, Role: p.Role, APIKeyID: p.APIKeyID}
+	switch {
+	case p.Role == auth.RoleSystem:
+		actor.Type = audit.ActorSystem
+	case p.APIKeyID != "":
+		actor.Type = audit.ActorAPIKey
+	}
+	return actor
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(constants.ContextKeyRequestID).(string)
+	return requestID
+}
+
+// hashState returns the SHA-256 of the JSON form of state, or "" for nil
+func hashState(state interface{}) string {
+	if state == nil {
+		return ""
+	}
+	data, err := json.Marshal(state)
+	if err != nil || string(data) == "null" {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
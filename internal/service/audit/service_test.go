@@ -0,0 +1,436 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/constants"
+	"mem_bank/internal/domain/audit"
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/queue"
+	"mem_bank/pkg/logger"
+)
+
+// Mock audit repository
+type mockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *mockAuditRepository) Append(ctx context.Context, e *audit.Entry) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *mockAuditRepository) Find(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*audit.Entry), args.Error(1)
+}
+
+func (m *mockAuditRepository) ListAfter(ctx context.Context, sequence int64, limit int) ([]*audit.Entry, error) {
+	args := m.Called(ctx, sequence, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*audit.Entry), args.Error(1)
+}
+
+// Mock job producer; only the methods under test are mocked
+type mockProducer struct {
+	queue.Producer
+	mock.Mock
+}
+
+func (m *mockProducer) Enqueue(ctx context.Context, job *queue.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+// Mock recorder
+type mockRecorder struct {
+	mock.Mock
+}
+
+func (m *mockRecorder) Record(ctx context.Context, action, resourceType, resourceID string, before, after interface{}) {
+	m.Called(ctx, action, resourceType, resourceID, before, after)
+}
+
+// Mock memory service; only the methods under test are mocked
+type mockMemoryService struct {
+	memory.Service
+	mock.Mock
+}
+
+func (m *mockMemoryService) UpdateMemory(ctx context.Context, id memory.ID, req memory.UpdateRequest) (*memory.Memory, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*memory.Memory), args.Error(1)
+}
+
+func (m *mockMemoryService) DeleteMemory(ctx context.Context, id memory.ID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Mock memory repository; only the methods under test are mocked
+type mockMemoryRepository struct {
+	memory.Repository
+	mock.Mock
+}
+
+func (m *mockMemoryRepository) FindByID(ctx context.Context, id memory.ID) (*memory.Memory, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*memory.Memory), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: user.ID(uuid.New()), Role: auth.RoleAdmin})
+}
+
+func userContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: user.ID(uuid.New()), Role: auth.RoleUser})
+}
+
+// chain links entries the way the PostgreSQL repository does
+func chain(n int) []*audit.Entry {
+	entries := make([]*audit.Entry, n)
+	prevHash := ""
+	for i := range entries {
+		e := &audit.Entry{
+			ID:           audit.NewID(),
+			Sequence:     int64(i + 1),
+			Actor:        audit.Actor{Type: audit.ActorSystem},
+			Action:       audit.ActionMemoryRead,
+			ResourceType: audit.ResourceMemory,
+			ResourceID:   uuid.NewString(),
+			CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+			PrevHash:     prevHash,
+		}
+		e.Hash = e.ChainHash(prevHash)
+		prevHash = e.Hash
+		entries[i] = e
+	}
+	return entries
+}
+
+func TestService_Record(t *testing.T) {
+	actorID := user.ID(uuid.New())
+	keyID := uuid.NewString()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		before    interface{}
+		after     interface{}
+		wantActor audit.Actor
+		wantReqID string
+		wantHash  bool
+	}{
+		{
+			name: "API key caller",
+			ctx: context.WithValue(
+				auth.WithPrincipal(context.Background(), auth.Principal{UserID: actorID, Role: auth.RoleUser, APIKeyID: keyID}),
+				constants.ContextKeyRequestID, "req-1"),
+			before:    map[string]string{"content": "a"},
+			after:     map[string]string{"content": "b"},
+			wantActor: audit.Actor{Type: audit.ActorAPIKey, UserID: actorID, Role: auth.RoleUser, APIKeyID: keyID},
+			wantReqID: "req-1",
+			wantHash:  true,
+		},
+		{
+			name:      "signed-in user",
+			ctx:       auth.WithPrincipal(context.Background(), auth.Principal{UserID: actorID, Role: auth.RoleUser}),
+			wantActor: audit.Actor{Type: audit.ActorUser, UserID: actorID, Role: auth.RoleUser},
+		},
+		{
+			name:      "background job",
+			ctx:       context.Background(),
+			wantActor: audit.Actor{Type: audit.ActorSystem},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockAuditRepository{}
+			producer := &mockProducer{}
+			log := &mockLogger{}
+			service := NewService(repo, producer, true, log)
+
+			// The queued job appends the entry
+			var job *queue.Job
+			producer.On("Enqueue", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				job = args.Get(1).(*queue.Job)
+			}).Return(nil)
+			var appended *audit.Entry
+			repo.On("Append", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				appended = args.Get(1).(*audit.Entry)
+			}).Return(nil)
+
+			service.Record(tt.ctx, audit.ActionMemoryUpdate, audit.ResourceMemory, "m1", tt.before, tt.after)
+			require.NotNil(t, job)
+			assert.Equal(t, queue.JobTypeAuditEntry, job.Type)
+			_, err := queue.NewAuditEntryHandler(service, log).Handle(context.Background(), job)
+			require.NoError(t, err)
+
+			require.NotNil(t, appended)
+			assert.Equal(t, tt.wantActor, appended.Actor)
+			assert.Equal(t, tt.wantReqID, appended.RequestID)
+			assert.Equal(t, "m1", appended.ResourceID)
+			if tt.wantHash {
+				assert.Len(t, appended.BeforeHash, 64)
+				assert.NotEqual(t, appended.BeforeHash, appended.AfterHash)
+			} else {
+				assert.Empty(t, appended.BeforeHash)
+				assert.Empty(t, appended.AfterHash)
+			}
+
+			producer.AssertExpectations(t)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Record_QueueFailure(t *testing.T) {
+	producer := &mockProducer{}
+	log := &mockLogger{}
+	service := NewService(&mockAuditRepository{}, producer, true, log)
+
+	producer.On("Enqueue", mock.Anything, mock.Anything).Return(errors.New("queue unavailable"))
+	log.On("Error", "Failed to queue audit entry").Once()
+
+	service.Record(context.Background(), audit.ActionUserLogin, audit.ResourceUser, "u1", nil, nil)
+
+	producer.AssertExpectations(t)
+	log.AssertExpectations(t)
+}
+
+func TestService_Query(t *testing.T) {
+	entries := chain(2)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		filter      audit.Filter
+		setupMocks  func(*mockAuditRepository)
+		wantEntries []*audit.Entry
+		wantErr     error
+	}{
+		{
+			name: "admin gets entries with the default limit",
+			ctx:  adminContext(),
+			filter: audit.Filter{
+				Action: audit.ActionMemoryCreate,
+			},
+			setupMocks: func(r *mockAuditRepository) {
+				r.On("Find", mock.Anything, audit.Filter{Action: audit.ActionMemoryCreate, Limit: defaultQueryLimit}).Return(entries, nil)
+			},
+			wantEntries: entries,
+		},
+		{
+			name:   "limit is capped",
+			ctx:    adminContext(),
+			filter: audit.Filter{Limit: 10000},
+			setupMocks: func(r *mockAuditRepository) {
+				r.On("Find", mock.Anything, audit.Filter{Limit: maxQueryLimit}).Return([]*audit.Entry{}, nil)
+			},
+			wantEntries: []*audit.Entry{},
+		},
+		{
+			name:    "regular user is denied",
+			ctx:     userContext(),
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name:    "negative limit",
+			ctx:     adminContext(),
+			filter:  audit.Filter{Limit: -1},
+			wantErr: audit.ErrInvalidFilter,
+		},
+		{
+			name:    "empty time range",
+			ctx:     adminContext(),
+			filter:  audit.Filter{Since: time.Now(), Until: time.Now().Add(-time.Hour)},
+			wantErr: audit.ErrInvalidFilter,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockAuditRepository{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo)
+			}
+			service := NewService(repo, &mockProducer{}, true, &mockLogger{})
+
+			found, err := service.Query(tt.ctx, tt.filter)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantEntries, found)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_VerifyChain(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		chained    bool
+		entries    func() []*audit.Entry
+		wantResult *audit.ChainVerification
+		wantErr    error
+	}{
+		{
+			name:       "intact chain",
+			ctx:        adminContext(),
+			chained:    true,
+			entries:    func() []*audit.Entry { return chain(3) },
+			wantResult: &audit.ChainVerification{Checked: 3, Valid: true},
+		},
+		{
+			name:    "rewritten entry breaks the chain",
+			ctx:     adminContext(),
+			chained: true,
+			entries: func() []*audit.Entry {
+				entries := chain(3)
+				entries[1].ResourceID = "rewritten"
+				return entries
+			},
+			wantResult: &audit.ChainVerification{Checked: 2, BrokenAt: 2},
+		},
+		{
+			name:    "entries from before the chain are skipped",
+			ctx:     adminContext(),
+			chained: true,
+			entries: func() []*audit.Entry {
+				return append([]*audit.Entry{{ID: audit.NewID(), Sequence: 0}}, chain(2)...)
+			},
+			wantResult: &audit.ChainVerification{Checked: 2, Valid: true},
+		},
+		{
+			name:    "chain disabled",
+			ctx:     adminContext(),
+			wantErr: audit.ErrChainNotEnabled,
+		},
+		{
+			name:    "regular user is denied",
+			ctx:     userContext(),
+			chained: true,
+			wantErr: auth.ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockAuditRepository{}
+			if tt.entries != nil {
+				repo.On("ListAfter", mock.Anything, int64(0), verifyBatchSize).Return(tt.entries(), nil)
+			}
+			service := NewService(repo, &mockProducer{}, tt.chained, &mockLogger{})
+
+			result, err := service.VerifyChain(tt.ctx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantResult, result)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoryService(t *testing.T) {
+	m := memory.NewMemory(user.ID(uuid.New()), "before", "", 5, "fact")
+	content := "after"
+	updated := *m
+	updated.Content = content
+
+	tests := []struct {
+		name       string
+		call       func(memory.Service) error
+		setupMocks func(*mockMemoryService, *mockMemoryRepository, *mockRecorder)
+		wantErr    error
+	}{
+		{
+			name: "update is recorded with both states",
+			call: func(s memory.Service) error {
+				_, err := s.UpdateMemory(context.Background(), m.ID, memory.UpdateRequest{Content: &content})
+				return err
+			},
+			setupMocks: func(s *mockMemoryService, r *mockMemoryRepository, rec *mockRecorder) {
+				r.On("FindByID", mock.Anything, m.ID).Return(m, nil)
+				s.On("UpdateMemory", mock.Anything, m.ID, memory.UpdateRequest{Content: &content}).Return(&updated, nil)
+				rec.On("Record", mock.Anything, audit.ActionMemoryUpdate, audit.ResourceMemory, m.ID.String(), m, &updated).Once()
+			},
+		},
+		{
+			name: "failed delete is not recorded",
+			call: func(s memory.Service) error {
+				return s.DeleteMemory(context.Background(), m.ID)
+			},
+			setupMocks: func(s *mockMemoryService, r *mockMemoryRepository, rec *mockRecorder) {
+				r.On("FindByID", mock.Anything, m.ID).Return(nil, memory.ErrNotFound)
+				s.On("DeleteMemory", mock.Anything, m.ID).Return(memory.ErrNotFound)
+			},
+			wantErr: memory.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memories := &mockMemoryService{}
+			repo := &mockMemoryRepository{}
+			recorder := &mockRecorder{}
+			tt.setupMocks(memories, repo, recorder)
+
+			err := tt.call(NewMemoryService(memories, repo, recorder))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			memories.AssertExpectations(t)
+			repo.AssertExpectations(t)
+			recorder.AssertExpectations(t)
+		})
+	}
+}
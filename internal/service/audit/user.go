@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"context"
+
+	"mem_bank/internal/domain/audit"
+	"mem_bank/internal/domain/user"
+)
+
+// userService records the changes to users made through a user.Service
+type userService struct {
+	user.Service
+	repo     user.Repository
+	recorder audit.Recorder
+}
+
+// NewUserService wraps users so that successful changes to users are
+// recorded; repo is used to read a user's state before it changes
+func NewUserService(users user.Service, repo user.Repository, recorder audit.Recorder) user.Service {
+	return &userService{Service: users, repo: repo, recorder: recorder}
+}
+
+func (s *userService) CreateUser(ctx context.Context, req user.CreateRequest) (*user.User, error) {
+	u, err := s.Service.CreateUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.recorder.Record(ctx, audit.ActionUserCreate, audit.ResourceUser, u.ID.String(), nil, u)
+	return u, nil
+}
+
+func (s *userService) UpdateUser(ctx context.Context, id user.ID, req user.UpdateRequest) (*user.User, error) {
+	before := s.find(ctx, id)
+	u, err := s.Service.UpdateUser(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	s.recorder.Record(ctx, audit.ActionUserUpdate, audit.ResourceUser, id.String(), before, u)
+	return u, nil
+}
+
+func (s *userService) DeleteUser(ctx context.Context, id user.ID) error {
+	before := s.find(ctx, id)
+	if err := s.Service.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	s.recorder.Record(ctx, audit.ActionUserDelete, audit.ResourceUser, id.String(), before, nil)
+	return nil
+}
+
+func (s *userService) UpdateLastLogin(ctx context.Context, id user.ID) error {
+	if err := s.Service.UpdateLastLogin(ctx, id); err != nil {
+		return err
+	}
+	s.recorder.Record(ctx, audit.ActionUserLogin, audit.ResourceUser, id.String(), nil, nil)
+	return nil
+}
+
+// find returns the user's current state, or nil if it cannot be read; the
+// wrapped service reports why
+func (s *userService) find(ctx context.Context, id user.ID) interface{} {
+	u, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return u
+}
@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/oidc"
+)
+
+// Username length limits enforced by the user service
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 50
+)
+
+// usernameAttempts bounds how many suffixed usernames are tried when the
+// one derived from the identity is taken
+const usernameAttempts = 5
+
+// OIDCConfig holds OpenID Connect login configuration
+type OIDCConfig struct {
+	// How long a started login may take before its callback is refused
+	StateTTL time.Duration `mapstructure:"state_ttl"`
+}
+
+// OIDCService implements auth.OIDCService. Users signing in for the first
+// time are provisioned from their ID token and linked by issuer and subject;
+// accounts are never linked by email, since the provider may not own it.
+type OIDCService struct {
+	client     *oidc.Client
+	states     oidc.StateStore
+	identities auth.IdentityRepository
+	users      user.Service
+	userRepo   user.Repository
+	logger     logger.Logger
+	config     OIDCConfig
+	now        func() time.Time
+}
+
+// NewOIDCService creates a new OpenID Connect login service
+func NewOIDCService(client *oidc.Client, states oidc.StateStore, identities auth.IdentityRepository, users user.Service, userRepo user.Repository, logger logger.Logger, config OIDCConfig) *OIDCService {
+	if config.StateTTL <= 0 {
+		config.StateTTL = 10 * time.Minute
+	}
+
+	return &OIDCService{
+		client:     client,
+		states:     states,
+		identities: identities,
+		users:      users,
+		userRepo:   userRepo,
+		logger:     logger,
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+func (s *OIDCService) LoginURL(ctx context.Context) (string, error) {
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.states.Save(ctx, state, oidc.LoginState{
+		Verifier:  verifier,
+		Nonce:     nonce,
+		CreatedAt: s.now(),
+	}, s.config.StateTTL); err != nil {
+		return "", err
+	}
+
+	return s.client.AuthCodeURL(state, nonce, verifier), nil
+}
+
+func (s *OIDCService) Callback(ctx context.Context, state, code string) (*user.User, error) {
+	if state == "" || code == "" {
+		return nil, auth.ErrOIDCLoginFailed
+	}
+
+	login, err := s.states.Take(ctx, state)
+	if errors.Is(err, oidc.ErrUnknownState) {
+		return nil, fmt.Errorf("%w: %v", auth.ErrOIDCLoginFailed, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	idToken, err := s.client.Exchange(ctx, code, login.Verifier, login.Nonce)
+	if err != nil {
+		s.logger.WithError(err).Warn("OpenID Connect code exchange failed")
+		return nil, fmt.Errorf("%w: %v", auth.ErrOIDCLoginFailed, err)
+	}
+
+	identity, err := s.identities.FindByIssuerSubject(ctx, idToken.Issuer, idToken.Subject)
+	if errors.Is(err, auth.ErrIdentityNotFound) {
+		return s.provision(ctx, idToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.FindByID(ctx, identity.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsActive {
+		return nil, auth.ErrInactive
+	}
+
+	if err := s.identities.TouchLastLogin(ctx, identity.ID, s.now()); err != nil {
+		s.logger.WithError(err).WithField("user_id", u.ID.String()).Warn("Failed to update identity last login")
+	}
+	if err := s.userRepo.UpdateLastLogin(ctx, u.ID); err != nil {
+		s.logger.WithError(err).WithField("user_id", u.ID.String()).Warn("Failed to update last login")
+	}
+	return u, nil
+}
+
+// provision creates a user for an identity signing in for the first time
+func (s *OIDCService) provision(ctx context.Context, idToken *oidc.IDToken) (*user.User, error) {
+	if idToken.Email == "" {
+		return nil, fmt.Errorf("%w: provider did not return an email address", auth.ErrOIDCLoginFailed)
+	}
+
+	// An existing account with the same email is not taken over: the
+	// provider asserting an address does not prove the account owner signed in
+	if _, err := s.userRepo.FindByEmail(ctx, idToken.Email); err == nil {
+		return nil, auth.ErrIdentityConflict
+	} else if !errors.Is(err, user.ErrNotFound) {
+		return nil, err
+	}
+
+	u, err := s.createUser(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.identities.Store(ctx, &auth.Identity{
+		ID:        uuid.New(),
+		UserID:    u.ID,
+		Issuer:    idToken.Issuer,
+		Subject:   idToken.Subject,
+		Email:     idToken.Email,
+		CreatedAt: s.now(),
+		LastLogin: s.now(),
+	}); err != nil {
+		// An unlinked user could never sign in; undo the creation
+		if delErr := s.userRepo.Delete(context.WithoutCancel(ctx), u.ID); delErr != nil {
+			s.logger.WithError(delErr).WithField("user_id", u.ID.String()).Error("Failed to remove user after identity error")
+		}
+		return nil, err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"user_id": u.ID.String(),
+		"issuer":  idToken.Issuer,
+	}).Info("Provisioned user from OpenID Connect identity")
+	return u, nil
+}
+
+// createUser creates the user for an identity, suffixing the derived
+// username while it is taken
+func (s *OIDCService) createUser(ctx context.Context, idToken *oidc.IDToken) (*user.User, error) {
+	base := deriveUsername(idToken)
+	req := user.CreateRequest{
+		Username: base,
+		Email:    idToken.Email,
+		Profile: user.Profile{
+			FirstName: idToken.GivenName,
+			LastName:  idToken.FamilyName,
+			Avatar:    idToken.Picture,
+		},
+	}
+
+	for attempt := 0; ; attempt++ {
+		u, err := s.users.CreateUser(ctx, req)
+		switch {
+		case err == nil:
+			return u, nil
+		case errors.Is(err, user.ErrEmailTaken):
+			return nil, auth.ErrIdentityConflict
+		case !errors.Is(err, user.ErrUsernameTaken) || attempt+1 >= usernameAttempts:
+			return nil, err
+		}
+
+		suffix, err := randomSuffix()
+		if err != nil {
+			return nil, err
+		}
+		req.Username = truncate(base, maxUsernameLength-len(suffix)-1) + "_" + suffix
+	}
+}
+
+// deriveUsername picks a username from the preferred username or the local
+// part of the email, keeping letters, digits, dots, dashes and underscores
+func deriveUsername(idToken *oidc.IDToken) string {
+	candidate := idToken.PreferredUsername
+	if at := strings.IndexByte(candidate, '@'); at >= 0 {
+		candidate = candidate[:at]
+	}
+	if candidate == "" {
+		candidate, _, _ = strings.Cut(idToken.Email, "@")
+	}
+
+	username := strings.Map(func(r rune) rune {
+		switch {
+		case r > unicode.MaxASCII:
+			return -1
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '.', r == '-', r == '_':
+			return unicode.ToLower(r)
+		default:
+			return -1
+		}
+	}, candidate)
+
+	for len(username) < minUsernameLength {
+		username += "_"
+	}
+	return truncate(username, maxUsernameLength)
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+func randomSuffix() (string, error) {
+	buf := make([]byte, 3)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating username suffix: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,316 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/oidc"
+	"mem_bank/pkg/oidc/oidctest"
+)
+
+// Mock login state store
+type mockStateStore struct {
+	mock.Mock
+}
+
+func (m *mockStateStore) Save(ctx context.Context, key string, state oidc.LoginState, ttl time.Duration) error {
+	args := m.Called(ctx, key, state, ttl)
+	return args.Error(0)
+}
+
+func (m *mockStateStore) Take(ctx context.Context, key string) (*oidc.LoginState, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*oidc.LoginState), args.Error(1)
+}
+
+// Mock identity repository
+type mockIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *mockIdentityRepository) FindByIssuerSubject(ctx context.Context, issuer, subject string) (*auth.Identity, error) {
+	args := m.Called(ctx, issuer, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth.Identity), args.Error(1)
+}
+
+func (m *mockIdentityRepository) Store(ctx context.Context, identity *auth.Identity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *mockIdentityRepository) TouchLastLogin(ctx context.Context, id uuid.UUID, t time.Time) error {
+	args := m.Called(ctx, id, t)
+	return args.Error(0)
+}
+
+// newTestProvider starts an identity provider that signs in as identity
+func newTestProvider(t *testing.T, identity oidctest.Identity) (*oidctest.Server, *oidc.Client) {
+	t.Helper()
+	provider := oidctest.NewServer("mem-bank", "secret")
+	t.Cleanup(provider.Close)
+	provider.SetIdentity(identity)
+
+	client, err := oidc.Discover(context.Background(), nil, oidc.Config{
+		Issuer:       provider.Issuer(),
+		ClientID:     "mem-bank",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/callback",
+	})
+	require.NoError(t, err)
+	return provider, client
+}
+
+// login starts a login and runs its browser side, returning the callback
+// parameters and the login state the service stored
+func login(t *testing.T, service *OIDCService, provider *oidctest.Server, states *mockStateStore) (state, code string, stored oidc.LoginState) {
+	t.Helper()
+	states.On("Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(2).(oidc.LoginState)
+	}).Return(nil).Once()
+
+	authURL, err := service.LoginURL(context.Background())
+	require.NoError(t, err)
+	code, state, err = provider.Login(authURL)
+	require.NoError(t, err)
+	return state, code, stored
+}
+
+func TestOIDCService_LoginURL(t *testing.T) {
+	provider, client := newTestProvider(t, oidctest.Identity{Subject: "user-123"})
+	states := &mockStateStore{}
+	var key string
+	var stored oidc.LoginState
+	states.On("Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		key = args.String(1)
+		stored = args.Get(2).(oidc.LoginState)
+	}).Return(nil)
+	service := NewOIDCService(client, states, &mockIdentityRepository{}, &mockUserService{}, &mockUserRepository{}, &mockLogger{}, OIDCConfig{})
+
+	authURL, err := service.LoginURL(context.Background())
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.True(t, strings.HasPrefix(authURL, provider.Issuer()+"/authorize?"))
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, key, query.Get("state"), "login state should be stored under the state parameter")
+	assert.Equal(t, oidc.Challenge(stored.Verifier), query.Get("code_challenge"))
+	assert.Equal(t, stored.Nonce, query.Get("nonce"))
+	states.AssertExpectations(t)
+}
+
+func TestOIDCService_Callback(t *testing.T) {
+	identity := oidctest.Identity{
+		Subject:           "user-123",
+		Email:             "alice@example.com",
+		EmailVerified:     true,
+		PreferredUsername: "Alice",
+	}
+	alice := &user.User{ID: user.ID(uuid.New()), Username: "alice", Email: identity.Email, IsActive: true}
+	linked := &auth.Identity{ID: uuid.New(), UserID: alice.ID, Subject: identity.Subject}
+
+	tests := []struct {
+		name     string
+		identity oidctest.Identity
+		// modifyState tampers with the stored login state before it is taken
+		modifyState func(*oidc.LoginState)
+		setupMocks  func(*mockIdentityRepository, *mockUserService, *mockUserRepository, *mockLogger, string)
+		wantErr     error
+	}{
+		{
+			name:     "first login provisions a user",
+			identity: identity,
+			setupMocks: func(i *mockIdentityRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger, issuer string) {
+				i.On("FindByIssuerSubject", mock.Anything, issuer, identity.Subject).Return(nil, auth.ErrIdentityNotFound)
+				ur.On("FindByEmail", mock.Anything, identity.Email).Return(nil, user.ErrNotFound)
+				us.On("CreateUser", mock.Anything, mock.MatchedBy(func(req user.CreateRequest) bool {
+					return req.Username == "alice" && req.Email == identity.Email
+				})).Return(alice, nil).Once()
+				i.On("Store", mock.Anything, mock.MatchedBy(func(stored *auth.Identity) bool {
+					return stored.UserID == alice.ID && stored.Issuer == issuer && stored.Subject == identity.Subject
+				})).Return(nil)
+				l.On("Info", "Provisioned user from OpenID Connect identity").Once()
+			},
+		},
+		{
+			name:     "later logins reuse the linked user",
+			identity: identity,
+			setupMocks: func(i *mockIdentityRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger, issuer string) {
+				i.On("FindByIssuerSubject", mock.Anything, issuer, identity.Subject).Return(linked, nil)
+				ur.On("FindByID", mock.Anything, alice.ID).Return(alice, nil)
+				i.On("TouchLastLogin", mock.Anything, linked.ID, mock.Anything).Return(nil)
+				ur.On("UpdateLastLogin", mock.Anything, alice.ID).Return(nil)
+			},
+		},
+		{
+			name:     "taken username gets a suffix",
+			identity: identity,
+			setupMocks: func(i *mockIdentityRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger, issuer string) {
+				i.On("FindByIssuerSubject", mock.Anything, issuer, identity.Subject).Return(nil, auth.ErrIdentityNotFound)
+				ur.On("FindByEmail", mock.Anything, identity.Email).Return(nil, user.ErrNotFound)
+				us.On("CreateUser", mock.Anything, mock.MatchedBy(func(req user.CreateRequest) bool {
+					return req.Username == "alice"
+				})).Return(nil, user.ErrUsernameTaken).Once()
+				us.On("CreateUser", mock.Anything, mock.MatchedBy(func(req user.CreateRequest) bool {
+					return strings.HasPrefix(req.Username, "alice_") && len(req.Username) == len("alice_")+6
+				})).Return(alice, nil).Once()
+				i.On("Store", mock.Anything, mock.Anything).Return(nil)
+				l.On("Info", "Provisioned user from OpenID Connect identity").Once()
+			},
+		},
+		{
+			name:     "existing account with the same email is not linked",
+			identity: identity,
+			setupMocks: func(i *mockIdentityRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger, issuer string) {
+				i.On("FindByIssuerSubject", mock.Anything, issuer, identity.Subject).Return(nil, auth.ErrIdentityNotFound)
+				ur.On("FindByEmail", mock.Anything, identity.Email).Return(alice, nil)
+			},
+			wantErr: auth.ErrIdentityConflict,
+		},
+		{
+			name:     "inactive user is refused",
+			identity: identity,
+			setupMocks: func(i *mockIdentityRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger, issuer string) {
+				i.On("FindByIssuerSubject", mock.Anything, issuer, identity.Subject).Return(linked, nil)
+				ur.On("FindByID", mock.Anything, alice.ID).Return(&user.User{ID: alice.ID, IsActive: false}, nil)
+			},
+			wantErr: auth.ErrInactive,
+		},
+		{
+			name:        "wrong PKCE verifier is rejected by the provider",
+			identity:    identity,
+			modifyState: func(s *oidc.LoginState) { s.Verifier = "attacker-verifier" },
+			setupMocks: func(i *mockIdentityRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger, issuer string) {
+				l.On("Warn", "OpenID Connect code exchange failed").Once()
+			},
+			wantErr: auth.ErrOIDCLoginFailed,
+		},
+		{
+			name:        "ID token with another nonce is rejected",
+			identity:    identity,
+			modifyState: func(s *oidc.LoginState) { s.Nonce = "another-nonce" },
+			setupMocks: func(i *mockIdentityRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger, issuer string) {
+				l.On("Warn", "OpenID Connect code exchange failed").Once()
+			},
+			wantErr: auth.ErrOIDCLoginFailed,
+		},
+		{
+			name:     "identity without email is refused",
+			identity: oidctest.Identity{Subject: "no-email"},
+			setupMocks: func(i *mockIdentityRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger, issuer string) {
+				i.On("FindByIssuerSubject", mock.Anything, issuer, "no-email").Return(nil, auth.ErrIdentityNotFound)
+			},
+			wantErr: auth.ErrOIDCLoginFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, client := newTestProvider(t, tt.identity)
+			states := &mockStateStore{}
+			identities := &mockIdentityRepository{}
+			users := &mockUserService{}
+			userRepo := &mockUserRepository{}
+			log := &mockLogger{}
+			tt.setupMocks(identities, users, userRepo, log, provider.Issuer())
+			service := NewOIDCService(client, states, identities, users, userRepo, log, OIDCConfig{})
+
+			state, code, stored := login(t, service, provider, states)
+			if tt.modifyState != nil {
+				tt.modifyState(&stored)
+			}
+			states.On("Take", mock.Anything, state).Return(&stored, nil).Once()
+
+			u, err := service.Callback(context.Background(), state, code)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, alice.ID, u.ID)
+			}
+
+			states.AssertExpectations(t)
+			identities.AssertExpectations(t)
+			users.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOIDCService_CallbackReplay(t *testing.T) {
+	provider, client := newTestProvider(t, oidctest.Identity{Subject: "user-123", Email: "alice@example.com"})
+	alice := &user.User{ID: user.ID(uuid.New()), IsActive: true}
+	linked := &auth.Identity{ID: uuid.New(), UserID: alice.ID}
+
+	states := &mockStateStore{}
+	identities := &mockIdentityRepository{}
+	identities.On("FindByIssuerSubject", mock.Anything, provider.Issuer(), "user-123").Return(linked, nil).Once()
+	identities.On("TouchLastLogin", mock.Anything, linked.ID, mock.Anything).Return(nil).Once()
+	userRepo := &mockUserRepository{}
+	userRepo.On("FindByID", mock.Anything, alice.ID).Return(alice, nil).Once()
+	userRepo.On("UpdateLastLogin", mock.Anything, alice.ID).Return(nil).Once()
+	log := &mockLogger{}
+	log.On("Warn", "OpenID Connect code exchange failed").Once()
+	service := NewOIDCService(client, states, identities, &mockUserService{}, userRepo, log, OIDCConfig{})
+
+	state, code, stored := login(t, service, provider, states)
+	states.On("Take", mock.Anything, state).Return(&stored, nil).Once()
+	states.On("Take", mock.Anything, state).Return(nil, oidc.ErrUnknownState).Once()
+	states.On("Take", mock.Anything, "replayed").Return(&stored, nil).Once()
+
+	_, err := service.Callback(context.Background(), state, code)
+	require.NoError(t, err)
+
+	// The state is single use
+	_, err = service.Callback(context.Background(), state, code)
+	assert.ErrorIs(t, err, auth.ErrOIDCLoginFailed)
+
+	// Even with a valid state the provider refuses a used code
+	_, err = service.Callback(context.Background(), "replayed", code)
+	assert.ErrorIs(t, err, auth.ErrOIDCLoginFailed)
+
+	states.AssertExpectations(t)
+	identities.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+	log.AssertExpectations(t)
+}
+
+func TestDeriveUsername(t *testing.T) {
+	tests := []struct {
+		name  string
+		token oidc.IDToken
+		want  string
+	}{
+		{"preferred username", oidc.IDToken{PreferredUsername: "Bob.Smith", Email: "x@example.com"}, "bob.smith"},
+		{"preferred username is an email", oidc.IDToken{PreferredUsername: "bob@corp.example"}, "bob"},
+		{"email local part", oidc.IDToken{Email: "carol+tag@example.com"}, "caroltag"},
+		{"too short is padded", oidc.IDToken{Email: "d@example.com"}, "d__"},
+		{"non-ASCII dropped", oidc.IDToken{PreferredUsername: "émile"}, "mile"},
+		{"too long is truncated", oidc.IDToken{PreferredUsername: strings.Repeat("a", 60)}, strings.Repeat("a", 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, deriveUsername(&tt.token))
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	pkgauth "mem_bank/pkg/auth"
+	"mem_bank/pkg/logger"
+)
+
+// Config holds authentication service configuration
+type Config struct {
+	// Failed logins in a row before the account is locked
+	MaxFailedAttempts int `mapstructure:"max_failed_attempts"`
+
+	// Lock duration after MaxFailedAttempts; it doubles with every further
+	// failure up to MaxLockoutDuration
+	LockoutDuration    time.Duration `mapstructure:"lockout_duration"`
+	MaxLockoutDuration time.Duration `mapstructure:"max_lockout_duration"`
+}
+
+// DefaultConfig returns the default lockout policy
+func DefaultConfig() Config {
+	return Config{
+		MaxFailedAttempts:  5,
+		LockoutDuration:    time.Minute,
+		MaxLockoutDuration: time.Hour,
+	}
+}
+
+// Service implements auth.Service with bcrypt password credentials stored
+// next to the user. Repeated failures lock the account with exponential
+// backoff; a successful login clears the failure count.
+type Service struct {
+	credentials auth.CredentialRepository
+	users       user.Service
+	userRepo    user.Repository
+	hasher      *pkgauth.PasswordHasher
+	logger      logger.Logger
+	config      Config
+	now         func() time.Time
+
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+// NewService creates a new authentication service
+func NewService(credentials auth.CredentialRepository, users user.Service, userRepo user.Repository, hasher *pkgauth.PasswordHasher, logger logger.Logger, config Config) *Service {
+	defaults := DefaultConfig()
+	if config.MaxFailedAttempts <= 0 {
+		config.MaxFailedAttempts = defaults.MaxFailedAttempts
+	}
+	if config.LockoutDuration <= 0 {
+		config.LockoutDuration = defaults.LockoutDuration
+	}
+	if config.MaxLockoutDuration < config.LockoutDuration {
+		config.MaxLockoutDuration = config.LockoutDuration
+	}
+
+	return &Service{
+		credentials: credentials,
+		users:       users,
+		userRepo:    userRepo,
+		hasher:      hasher,
+		logger:      logger,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+func (s *Service) Register(ctx context.Context, req auth.RegisterRequest) (*user.User, error) {
+	if err := s.hasher.Validate(req.Password); err != nil {
+		return nil, fmt.Errorf("%w: %v", auth.ErrWeakPassword, err)
+	}
+	hash, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.users.CreateUser(ctx, user.CreateRequest{
+		Username: req.Username,
+		Email:    req.Email,
+		Profile:  req.Profile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if err := s.credentials.Save(ctx, &auth.Credential{
+		UserID:            u.ID,
+		PasswordHash:      hash,
+		PasswordChangedAt: now,
+	}); err != nil {
+		// Without a credential the user could never log in; undo the creation
+		if delErr := s.userRepo.Delete(context.WithoutCancel(ctx), u.ID); delErr != nil {
+			s.logger.WithError(delErr).WithField("user_id", u.ID.String()).Error("Failed to remove user after credential error")
+		}
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (s *Service) Login(ctx context.Context, req user.LoginRequest) (*user.User, error) {
+	identifier := strings.TrimSpace(req.Username)
+	if identifier == "" || req.Password == "" {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	u, err := s.findUser(ctx, identifier)
+	if errors.Is(err, user.ErrNotFound) {
+		s.burnVerification(req.Password)
+		return nil, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.credentials.FindByUserID(ctx, u.ID)
+	if errors.Is(err, auth.ErrNotFound) {
+		// Users created without a password cannot log in with one
+		s.burnVerification(req.Password)
+		return nil, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if credential.IsLocked(s.now()) {
+		return nil, &auth.LockedError{Until: credential.LockedUntil}
+	}
+
+	if err := s.hasher.Verify(credential.PasswordHash, req.Password); err != nil {
+		if !errors.Is(err, pkgauth.ErrPasswordMismatch) {
+			return nil, err
+		}
+		return nil, s.recordFailure(ctx, u.ID)
+	}
+
+	if !u.IsActive {
+		return nil, auth.ErrInactive
+	}
+
+	if credential.FailedAttempts > 0 || !credential.LockedUntil.IsZero() {
+		if err := s.credentials.ResetFailures(ctx, u.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.hasher.NeedsRehash(credential.PasswordHash) {
+		s.rehash(ctx, credential, req.Password)
+	}
+
+	if err := s.userRepo.UpdateLastLogin(ctx, u.ID); err != nil {
+		s.logger.WithError(err).WithField("user_id", u.ID.String()).Warn("Failed to update last login")
+	}
+
+	return u, nil
+}
+
+func (s *Service) SetPassword(ctx context.Context, userID user.ID, password string) error {
+	if userID.IsZero() {
+		return user.ErrInvalidID
+	}
+	if err := s.hasher.Validate(password); err != nil {
+		return fmt.Errorf("%w: %v", auth.ErrWeakPassword, err)
+	}
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return err
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return s.credentials.Save(ctx, &auth.Credential{
+		UserID:            userID,
+		PasswordHash:      hash,
+		PasswordChangedAt: s.now(),
+	})
+}
+
+func (s *Service) ChangePassword(ctx context.Context, userID user.ID, req auth.ChangePasswordRequest) error {
+	if userID.IsZero() {
+		return user.ErrInvalidID
+	}
+
+	credential, err := s.credentials.FindByUserID(ctx, userID)
+	if errors.Is(err, auth.ErrNotFound) {
+		return auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return err
+	}
+	if credential.IsLocked(s.now()) {
+		return &auth.LockedError{Until: credential.LockedUntil}
+	}
+
+	if err := s.hasher.Verify(credential.PasswordHash, req.CurrentPassword); err != nil {
+		if !errors.Is(err, pkgauth.ErrPasswordMismatch) {
+			return err
+		}
+		return s.recordFailure(ctx, userID)
+	}
+
+	return s.SetPassword(ctx, userID, req.NewPassword)
+}
+
+// findUser looks a user up by email when identifier contains "@", by username otherwise
+func (s *Service) findUser(ctx context.Context, identifier string) (*user.User, error) {
+	if strings.Contains(identifier, "@") {
+		return s.userRepo.FindByEmail(ctx, identifier)
+	}
+	return s.userRepo.FindByUsername(ctx, identifier)
+}
+
+// recordFailure counts a wrong password and locks the account once the
+// limit is reached. It returns the error to report to the caller.
+func (s *Service) recordFailure(ctx context.Context, userID user.ID) error {
+	attempts, err := s.credentials.RecordFailure(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if attempts < s.config.MaxFailedAttempts {
+		return auth.ErrInvalidCredentials
+	}
+
+	until := s.now().Add(s.lockoutDuration(attempts))
+	if err := s.credentials.Lock(ctx, userID, until); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"user_id":         userID.String(),
+		"failed_attempts": attempts,
+		"locked_until":    until,
+	}).Warn("Account locked after repeated failed logins")
+
+	return &auth.LockedError{Until: until}
+}
+
+// lockoutDuration doubles the base lockout for every failure past the limit
+func (s *Service) lockoutDuration(attempts int) time.Duration {
+	duration := s.config.LockoutDuration
+	for i := s.config.MaxFailedAttempts; i < attempts; i++ {
+		duration *= 2
+		if duration >= s.config.MaxLockoutDuration {
+			return s.config.MaxLockoutDuration
+		}
+	}
+	return duration
+}
+
+// rehash upgrades a hash made with an outdated cost; failures only cost a
+// slower login next time
+func (s *Service) rehash(ctx context.Context, credential *auth.Credential, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err == nil {
+		credential.PasswordHash = hash
+		credential.FailedAttempts = 0
+		credential.LockedUntil = time.Time{}
+		err = s.credentials.Save(ctx, credential)
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("user_id", credential.UserID.String()).Warn("Failed to rehash password")
+	}
+}
+
+// burnVerification spends the time of a real password check so response
+// times do not reveal whether an account exists
+func (s *Service) burnVerification(password string) {
+	s.dummyOnce.Do(func() {
+		s.dummyHash, _ = s.hasher.Hash("not-a-real-password")
+	})
+	_ = s.hasher.Verify(s.dummyHash, password)
+}
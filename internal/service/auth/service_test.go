@@ -0,0 +1,483 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	pkgauth "mem_bank/pkg/auth"
+	"mem_bank/pkg/logger"
+)
+
+// Mock credential repository
+type mockCredentialRepository struct {
+	mock.Mock
+}
+
+func (m *mockCredentialRepository) FindByUserID(ctx context.Context, userID user.ID) (*auth.Credential, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth.Credential), args.Error(1)
+}
+
+func (m *mockCredentialRepository) Save(ctx context.Context, credential *auth.Credential) error {
+	args := m.Called(ctx, credential)
+	return args.Error(0)
+}
+
+func (m *mockCredentialRepository) RecordFailure(ctx context.Context, userID user.ID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockCredentialRepository) Lock(ctx context.Context, userID user.ID, until time.Time) error {
+	args := m.Called(ctx, userID, until)
+	return args.Error(0)
+}
+
+func (m *mockCredentialRepository) ResetFailures(ctx context.Context, userID user.ID) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// Mock user service; only CreateUser is expected
+type mockUserService struct {
+	mock.Mock
+	user.Service
+}
+
+func (m *mockUserService) CreateUser(ctx context.Context, req user.CreateRequest) (*user.User, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+// Mock user repository; only the methods the auth services use are expected
+type mockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id user.ID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserRepository) FindByUsername(ctx context.Context, username string) (*user.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserRepository) UpdateLastLogin(ctx context.Context, id user.ID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) Delete(ctx context.Context, id user.ID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+// testClock is the time the authentication service sees in tests
+var testClock = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestService(credentials *mockCredentialRepository, users *mockUserService, userRepo *mockUserRepository, log *mockLogger) *Service {
+	service := NewService(credentials, users, userRepo, pkgauth.NewPasswordHasher(bcrypt.MinCost), log, Config{
+		MaxFailedAttempts:  3,
+		LockoutDuration:    time.Minute,
+		MaxLockoutDuration: 3 * time.Minute,
+	})
+	service.now = func() time.Time { return testClock }
+	return service
+}
+
+// hashPassword hashes a password as a credential stores it
+func hashPassword(t *testing.T, password string, cost int) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	require.NoError(t, err)
+	return string(hash)
+}
+
+// hashOf matches a credential whose hash is of password, made with cost
+func hashOf(password string, cost int) interface{} {
+	return mock.MatchedBy(func(c *auth.Credential) bool {
+		hashCost, err := bcrypt.Cost([]byte(c.PasswordHash))
+		return err == nil && hashCost == cost &&
+			bcrypt.CompareHashAndPassword([]byte(c.PasswordHash), []byte(password)) == nil
+	})
+}
+
+func TestService_Register(t *testing.T) {
+	alice := &user.User{ID: user.ID(uuid.New()), Username: "alice", Email: "alice@example.com", IsActive: true}
+	dbErr := errors.New("db down")
+
+	tests := []struct {
+		name       string
+		password   string
+		setupMocks func(*mockCredentialRepository, *mockUserService, *mockUserRepository, *mockLogger)
+		wantErr    error
+	}{
+		{
+			name:     "stores a hash of the password",
+			password: "correct horse",
+			setupMocks: func(c *mockCredentialRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger) {
+				us.On("CreateUser", mock.Anything, user.CreateRequest{Username: "alice", Email: "alice@example.com"}).Return(alice, nil)
+				c.On("Save", mock.Anything, hashOf("correct horse", bcrypt.MinCost)).Return(nil)
+			},
+		},
+		{
+			name:     "weak password is rejected before creating the user",
+			password: "short",
+			wantErr:  auth.ErrWeakPassword,
+		},
+		{
+			name:     "taken username",
+			password: "correct horse",
+			setupMocks: func(c *mockCredentialRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger) {
+				us.On("CreateUser", mock.Anything, mock.Anything).Return(nil, user.ErrUsernameTaken)
+			},
+			wantErr: user.ErrUsernameTaken,
+		},
+		{
+			name:     "user is removed when the credential cannot be stored",
+			password: "correct horse",
+			setupMocks: func(c *mockCredentialRepository, us *mockUserService, ur *mockUserRepository, l *mockLogger) {
+				us.On("CreateUser", mock.Anything, mock.Anything).Return(alice, nil)
+				c.On("Save", mock.Anything, mock.Anything).Return(dbErr)
+				ur.On("Delete", mock.Anything, alice.ID).Return(nil)
+			},
+			wantErr: dbErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credentials := &mockCredentialRepository{}
+			users := &mockUserService{}
+			userRepo := &mockUserRepository{}
+			log := &mockLogger{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(credentials, users, userRepo, log)
+			}
+			service := newTestService(credentials, users, userRepo, log)
+
+			u, err := service.Register(context.Background(), auth.RegisterRequest{
+				Username: "alice",
+				Email:    "alice@example.com",
+				Password: tt.password,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, u)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, alice.ID, u.ID)
+			}
+
+			credentials.AssertExpectations(t)
+			users.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Login(t *testing.T) {
+	alice := &user.User{ID: user.ID(uuid.New()), Username: "alice", Email: "alice@example.com", IsActive: true}
+	inactive := &user.User{ID: alice.ID, Username: "alice", IsActive: false}
+	hash := hashPassword(t, "correct horse", bcrypt.MinCost)
+	right := user.LoginRequest{Username: "alice", Password: "correct horse"}
+	wrong := user.LoginRequest{Username: "alice", Password: "wrong horse"}
+
+	credential := func(failedAttempts int, lockedUntil time.Time) *auth.Credential {
+		return &auth.Credential{UserID: alice.ID, PasswordHash: hash, FailedAttempts: failedAttempts, LockedUntil: lockedUntil}
+	}
+	findAlice := func(c *mockCredentialRepository, u *mockUserRepository, stored *auth.Credential) {
+		u.On("FindByUsername", mock.Anything, "alice").Return(alice, nil)
+		c.On("FindByUserID", mock.Anything, alice.ID).Return(stored, nil)
+	}
+
+	tests := []struct {
+		name       string
+		req        user.LoginRequest
+		setupMocks func(*mockCredentialRepository, *mockUserRepository, *mockLogger)
+		wantErr    error
+		wantLocked time.Time
+	}{
+		{
+			name: "by username",
+			req:  right,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				findAlice(c, u, credential(0, time.Time{}))
+				u.On("UpdateLastLogin", mock.Anything, alice.ID).Return(nil)
+			},
+		},
+		{
+			name: "by email",
+			req:  user.LoginRequest{Username: "alice@example.com", Password: "correct horse"},
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				u.On("FindByEmail", mock.Anything, "alice@example.com").Return(alice, nil)
+				c.On("FindByUserID", mock.Anything, alice.ID).Return(credential(0, time.Time{}), nil)
+				u.On("UpdateLastLogin", mock.Anything, alice.ID).Return(nil)
+			},
+		},
+		{
+			name: "wrong password",
+			req:  wrong,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				findAlice(c, u, credential(0, time.Time{}))
+				c.On("RecordFailure", mock.Anything, alice.ID).Return(1, nil)
+			},
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "unknown user looks like a wrong password",
+			req:  user.LoginRequest{Username: "mallory", Password: "whatever1"},
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				u.On("FindByUsername", mock.Anything, "mallory").Return(nil, user.ErrNotFound)
+			},
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "user without a password",
+			req:  right,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				u.On("FindByUsername", mock.Anything, "alice").Return(alice, nil)
+				c.On("FindByUserID", mock.Anything, alice.ID).Return(nil, auth.ErrNotFound)
+			},
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name: "inactive user",
+			req:  right,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				u.On("FindByUsername", mock.Anything, "alice").Return(inactive, nil)
+				c.On("FindByUserID", mock.Anything, alice.ID).Return(credential(0, time.Time{}), nil)
+			},
+			wantErr: auth.ErrInactive,
+		},
+		{
+			name: "locked account refuses the right password",
+			req:  right,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				findAlice(c, u, credential(3, testClock.Add(time.Minute)))
+			},
+			wantErr: auth.ErrAccountLocked,
+		},
+		{
+			name: "reaching the limit locks for the base duration",
+			req:  wrong,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				findAlice(c, u, credential(2, time.Time{}))
+				c.On("RecordFailure", mock.Anything, alice.ID).Return(3, nil)
+				c.On("Lock", mock.Anything, alice.ID, testClock.Add(time.Minute)).Return(nil)
+				l.On("Warn", "Account locked after repeated failed logins").Once()
+			},
+			wantLocked: testClock.Add(time.Minute),
+		},
+		{
+			name: "further failures double the lock",
+			req:  wrong,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				findAlice(c, u, credential(3, testClock.Add(-time.Second)))
+				c.On("RecordFailure", mock.Anything, alice.ID).Return(4, nil)
+				c.On("Lock", mock.Anything, alice.ID, testClock.Add(2*time.Minute)).Return(nil)
+				l.On("Warn", "Account locked after repeated failed logins").Once()
+			},
+			wantLocked: testClock.Add(2 * time.Minute),
+		},
+		{
+			name: "lock backoff is capped",
+			req:  wrong,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				findAlice(c, u, credential(5, testClock.Add(-time.Second)))
+				c.On("RecordFailure", mock.Anything, alice.ID).Return(6, nil)
+				c.On("Lock", mock.Anything, alice.ID, testClock.Add(3*time.Minute)).Return(nil)
+				l.On("Warn", "Account locked after repeated failed logins").Once()
+			},
+			wantLocked: testClock.Add(3 * time.Minute),
+		},
+		{
+			name: "successful login clears failures",
+			req:  right,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				findAlice(c, u, credential(3, testClock.Add(-time.Second)))
+				c.On("ResetFailures", mock.Anything, alice.ID).Return(nil)
+				u.On("UpdateLastLogin", mock.Anything, alice.ID).Return(nil)
+			},
+		},
+		{
+			name: "passwords hashed with another cost are rehashed",
+			req:  right,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				stored := credential(0, time.Time{})
+				stored.PasswordHash = hashPassword(t, "correct horse", bcrypt.MinCost+1)
+				findAlice(c, u, stored)
+				c.On("Save", mock.Anything, hashOf("correct horse", bcrypt.MinCost)).Return(nil)
+				u.On("UpdateLastLogin", mock.Anything, alice.ID).Return(nil)
+			},
+		},
+		{
+			name: "last login failure does not fail the login",
+			req:  right,
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository, l *mockLogger) {
+				findAlice(c, u, credential(0, time.Time{}))
+				u.On("UpdateLastLogin", mock.Anything, alice.ID).Return(errors.New("db down"))
+				l.On("Warn", "Failed to update last login").Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credentials := &mockCredentialRepository{}
+			userRepo := &mockUserRepository{}
+			log := &mockLogger{}
+			tt.setupMocks(credentials, userRepo, log)
+			service := newTestService(credentials, &mockUserService{}, userRepo, log)
+
+			u, err := service.Login(context.Background(), tt.req)
+			switch {
+			case !tt.wantLocked.IsZero():
+				var locked *auth.LockedError
+				require.ErrorAs(t, err, &locked)
+				assert.Equal(t, tt.wantLocked, locked.Until)
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, alice.ID, u.ID)
+			}
+
+			credentials.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	alice := &user.User{ID: user.ID(uuid.New()), Username: "alice", IsActive: true}
+	stored := &auth.Credential{UserID: alice.ID, PasswordHash: hashPassword(t, "correct horse", bcrypt.MinCost)}
+
+	tests := []struct {
+		name       string
+		userID     user.ID
+		req        auth.ChangePasswordRequest
+		setupMocks func(*mockCredentialRepository, *mockUserRepository)
+		wantErr    error
+	}{
+		{
+			name:   "successful change",
+			userID: alice.ID,
+			req:    auth.ChangePasswordRequest{CurrentPassword: "correct horse", NewPassword: "battery staple"},
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository) {
+				c.On("FindByUserID", mock.Anything, alice.ID).Return(stored, nil)
+				u.On("FindByID", mock.Anything, alice.ID).Return(alice, nil)
+				c.On("Save", mock.Anything, hashOf("battery staple", bcrypt.MinCost)).Return(nil)
+			},
+		},
+		{
+			name:   "wrong current password counts as a failure",
+			userID: alice.ID,
+			req:    auth.ChangePasswordRequest{CurrentPassword: "wrong horse", NewPassword: "battery staple"},
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository) {
+				c.On("FindByUserID", mock.Anything, alice.ID).Return(stored, nil)
+				c.On("RecordFailure", mock.Anything, alice.ID).Return(1, nil)
+			},
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:   "weak new password",
+			userID: alice.ID,
+			req:    auth.ChangePasswordRequest{CurrentPassword: "correct horse", NewPassword: "short"},
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository) {
+				c.On("FindByUserID", mock.Anything, alice.ID).Return(stored, nil)
+			},
+			wantErr: auth.ErrWeakPassword,
+		},
+		{
+			name:   "user without a password",
+			userID: alice.ID,
+			req:    auth.ChangePasswordRequest{CurrentPassword: "correct horse", NewPassword: "battery staple"},
+			setupMocks: func(c *mockCredentialRepository, u *mockUserRepository) {
+				c.On("FindByUserID", mock.Anything, alice.ID).Return(nil, auth.ErrNotFound)
+			},
+			wantErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:    "zero user ID",
+			userID:  user.ID{},
+			req:     auth.ChangePasswordRequest{CurrentPassword: "correct horse", NewPassword: "battery staple"},
+			wantErr: user.ErrInvalidID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credentials := &mockCredentialRepository{}
+			userRepo := &mockUserRepository{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(credentials, userRepo)
+			}
+			service := newTestService(credentials, &mockUserService{}, userRepo, &mockLogger{})
+
+			err := service.ChangePassword(context.Background(), tt.userID, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			credentials.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
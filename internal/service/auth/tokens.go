@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	pkgauth "mem_bank/pkg/auth"
+	"mem_bank/pkg/logger"
+)
+
+// refreshTokenBytes is the entropy of a refresh token
+const refreshTokenBytes = 32
+
+// TokenConfig holds refresh token configuration
+type TokenConfig struct {
+	// Lifetime of refresh tokens; every rotation starts a new lifetime
+	RefreshTokenExpiry time.Duration `mapstructure:"refresh_token_expiry"`
+}
+
+// TokenService issues access tokens with rotating refresh tokens. A login
+// starts a token family whose ID becomes the session ID of its access
+// tokens; reusing a rotated refresh token revokes the family and, through
+// the JWT revocation list, its access tokens.
+type TokenService struct {
+	repo       auth.RefreshTokenRepository
+	userRepo   user.Repository
+	jwtService *pkgauth.JWTService
+	logger     logger.Logger
+	config     TokenConfig
+	now        func() time.Time
+}
+
+// NewTokenService creates a new token service
+func NewTokenService(repo auth.RefreshTokenRepository, userRepo user.Repository, jwtService *pkgauth.JWTService, logger logger.Logger, config TokenConfig) *TokenService {
+	if config.RefreshTokenExpiry <= 0 {
+		config.RefreshTokenExpiry = 30 * 24 * time.Hour
+	}
+
+	return &TokenService{
+		repo:       repo,
+		userRepo:   userRepo,
+		jwtService: jwtService,
+		logger:     logger,
+		config:     config,
+		now:        time.Now,
+	}
+}
+
+func (s *TokenService) IssueTokens(ctx context.Context, u *user.User) (*auth.TokenPair, error) {
+	return s.issue(ctx, u, uuid.New(), uuid.New())
+}
+
+func (s *TokenService) RefreshTokens(ctx context.Context, refreshToken string) (*auth.TokenPair, *user.User, error) {
+	if refreshToken == "" {
+		return nil, nil, auth.ErrInvalidRefreshToken
+	}
+
+	token, err := s.repo.FindByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, nil, err
+	}
+	if token.IsRevoked() || token.IsExpired(s.now()) {
+		return nil, nil, auth.ErrInvalidRefreshToken
+	}
+	if token.IsUsed() {
+		return nil, nil, s.revokeReusedFamily(ctx, token)
+	}
+
+	nextID := uuid.New()
+	swapped, err := s.repo.MarkUsed(ctx, token.ID, nextID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !swapped {
+		// Another request exchanged the token first
+		return nil, nil, s.revokeReusedFamily(ctx, token)
+	}
+
+	u, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !u.IsActive {
+		s.revokeFamily(ctx, token.FamilyID)
+		return nil, nil, auth.ErrInactive
+	}
+
+	pair, err := s.issue(ctx, u, token.FamilyID, nextID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, u, nil
+}
+
+func (s *TokenService) Logout(ctx context.Context, req auth.LogoutRequest) error {
+	if req.RefreshToken != "" {
+		token, err := s.repo.FindByHash(ctx, hashToken(req.RefreshToken))
+		switch {
+		case errors.Is(err, auth.ErrInvalidRefreshToken):
+			// Unknown tokens have nothing left to revoke
+		case err != nil:
+			return err
+		default:
+			if err := s.repo.RevokeFamily(ctx, token.FamilyID); err != nil {
+				return err
+			}
+			if err := s.jwtService.RevokeSession(ctx, token.FamilyID.String()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if familyID, err := uuid.Parse(req.SessionID); err == nil {
+		if err := s.repo.RevokeFamily(ctx, familyID); err != nil {
+			return err
+		}
+		return s.jwtService.RevokeSession(ctx, req.SessionID)
+	}
+	return nil
+}
+
+func (s *TokenService) RevokeAll(ctx context.Context, userID user.ID) error {
+	families, err := s.repo.RevokeUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, familyID := range families {
+		if err := s.jwtService.RevokeSession(ctx, familyID.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issue creates a refresh token with the given ID in a family and an access
+// token for the family's session
+func (s *TokenService) issue(ctx context.Context, u *user.User, familyID, tokenID uuid.UUID) (*auth.TokenPair, error) {
+	raw, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if err := s.repo.Store(ctx, &auth.RefreshToken{
+		ID:        tokenID,
+		UserID:    u.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: now.Add(s.config.RefreshTokenExpiry),
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.jwtService.GenerateSessionToken(uuid.UUID(u.ID), u.Username, u.Email, u.Role, familyID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     raw,
+		ExpiresIn:        s.jwtService.Expiry(),
+		RefreshExpiresIn: s.config.RefreshTokenExpiry,
+		SessionID:        familyID.String(),
+	}, nil
+}
+
+// revokeReusedFamily handles a refresh token presented after rotation: the
+// token has leaked to someone, so the whole session is ended
+func (s *TokenService) revokeReusedFamily(ctx context.Context, token *auth.RefreshToken) error {
+	s.logger.WithFields(map[string]interface{}{
+		"user_id":   token.UserID.String(),
+		"family_id": token.FamilyID.String(),
+	}).Warn("Refresh token reused, revoking its session")
+
+	s.revokeFamily(ctx, token.FamilyID)
+	return auth.ErrRefreshTokenReused
+}
+
+// revokeFamily revokes a family and its access tokens, logging failures;
+// callers are already returning an error of their own
+func (s *TokenService) revokeFamily(ctx context.Context, familyID uuid.UUID) {
+	ctx = context.WithoutCancel(ctx)
+	if err := s.repo.RevokeFamily(ctx, familyID); err != nil {
+		s.logger.WithError(err).WithField("family_id", familyID.String()).Error("Failed to revoke refresh token family")
+	}
+	if err := s.jwtService.RevokeSession(ctx, familyID.String()); err != nil {
+		s.logger.WithError(err).WithField("family_id", familyID.String()).Error("Failed to revoke session")
+	}
+}
+
+// newRefreshToken returns a random URL-safe token
+func newRefreshToken() (string, error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken returns the stored form of a refresh token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,358 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	pkgauth "mem_bank/pkg/auth"
+)
+
+// Mock refresh token repository
+type mockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *mockRefreshTokenRepository) Store(ctx context.Context, token *auth.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *mockRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*auth.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth.RefreshToken), args.Error(1)
+}
+
+func (m *mockRefreshTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID, replacedBy uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id, replacedBy)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *mockRefreshTokenRepository) RevokeUser(ctx context.Context, userID user.ID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func newTestJWTService() *pkgauth.JWTService {
+	return pkgauth.NewJWTService("0123456789abcdef0123456789abcdef", "test", 15*time.Minute).
+		WithRevocationList(pkgauth.NewMemoryRevocationList())
+}
+
+func newTestTokenService(repo *mockRefreshTokenRepository, userRepo *mockUserRepository, jwtService *pkgauth.JWTService, log *mockLogger) *TokenService {
+	service := NewTokenService(repo, userRepo, jwtService, log, TokenConfig{RefreshTokenExpiry: time.Hour})
+	service.now = func() time.Time { return testClock }
+	return service
+}
+
+// sessionToken returns an access token of the session of a token family
+func sessionToken(t *testing.T, jwtService *pkgauth.JWTService, u *user.User, familyID uuid.UUID) string {
+	t.Helper()
+	token, err := jwtService.GenerateSessionToken(uuid.UUID(u.ID), u.Username, u.Email, u.Role, familyID.String())
+	require.NoError(t, err)
+	return token
+}
+
+func TestTokenService_IssueTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		wantRole string
+	}{
+		{"user", user.RoleUser, auth.RoleUser},
+		{"admin", user.RoleAdmin, auth.RoleAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &user.User{ID: user.ID(uuid.New()), Username: "alice", Email: "alice@example.com", IsActive: true, Role: tt.role}
+			var stored *auth.RefreshToken
+			repo := &mockRefreshTokenRepository{}
+			repo.On("Store", mock.Anything, mock.AnythingOfType("*auth.RefreshToken")).Run(func(args mock.Arguments) {
+				stored = args.Get(1).(*auth.RefreshToken)
+			}).Return(nil)
+			jwtService := newTestJWTService()
+			service := newTestTokenService(repo, &mockUserRepository{}, jwtService, &mockLogger{})
+
+			pair, err := service.IssueTokens(context.Background(), u)
+			require.NoError(t, err)
+			assert.NotEmpty(t, pair.RefreshToken)
+			assert.Equal(t, 15*time.Minute, pair.ExpiresIn)
+			assert.Equal(t, time.Hour, pair.RefreshExpiresIn)
+
+			claims, err := jwtService.Authenticate(context.Background(), pair.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, uuid.UUID(u.ID), claims.UserID)
+			assert.Equal(t, pair.SessionID, claims.SessionID)
+			assert.NotEmpty(t, claims.ID)
+			assert.Equal(t, tt.wantRole, claims.Role)
+
+			// Only the hash is stored
+			require.NotNil(t, stored)
+			assert.Equal(t, u.ID, stored.UserID)
+			assert.Equal(t, pair.SessionID, stored.FamilyID.String())
+			assert.Equal(t, hashToken(pair.RefreshToken), stored.TokenHash)
+			assert.NotEqual(t, pair.RefreshToken, stored.TokenHash)
+			assert.Equal(t, testClock.Add(time.Hour), stored.ExpiresAt)
+		})
+	}
+}
+
+func TestTokenService_RefreshTokens(t *testing.T) {
+	alice := &user.User{ID: user.ID(uuid.New()), Username: "alice", Email: "alice@example.com", IsActive: true, Role: user.RoleUser}
+	inactive := &user.User{ID: alice.ID, Username: "alice", IsActive: false, Role: user.RoleUser}
+	raw := "refresh-token"
+
+	token := func(modify func(*auth.RefreshToken)) *auth.RefreshToken {
+		token := &auth.RefreshToken{
+			ID:        uuid.New(),
+			UserID:    alice.ID,
+			FamilyID:  uuid.New(),
+			TokenHash: hashToken(raw),
+			ExpiresAt: testClock.Add(time.Hour),
+			CreatedAt: testClock.Add(-time.Minute),
+		}
+		if modify != nil {
+			modify(token)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name  string
+		raw   string
+		token *auth.RefreshToken
+		// setupMocks gets the presented token, nil when there is none
+		setupMocks         func(*mockRefreshTokenRepository, *mockUserRepository, *mockLogger, *auth.RefreshToken)
+		wantErr            error
+		wantSessionRevoked bool
+	}{
+		{
+			name:  "rotates the token within its family",
+			raw:   raw,
+			token: token(nil),
+			setupMocks: func(r *mockRefreshTokenRepository, u *mockUserRepository, l *mockLogger, presented *auth.RefreshToken) {
+				r.On("FindByHash", mock.Anything, hashToken(raw)).Return(presented, nil)
+				r.On("MarkUsed", mock.Anything, presented.ID, mock.Anything).Return(true, nil)
+				u.On("FindByID", mock.Anything, alice.ID).Return(alice, nil)
+				r.On("Store", mock.Anything, mock.MatchedBy(func(next *auth.RefreshToken) bool {
+					return next.FamilyID == presented.FamilyID && next.ID != presented.ID && next.TokenHash != presented.TokenHash
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "empty token",
+			raw:     "",
+			wantErr: auth.ErrInvalidRefreshToken,
+		},
+		{
+			name: "unknown token",
+			raw:  "not-a-token",
+			setupMocks: func(r *mockRefreshTokenRepository, u *mockUserRepository, l *mockLogger, presented *auth.RefreshToken) {
+				r.On("FindByHash", mock.Anything, hashToken("not-a-token")).Return(nil, auth.ErrInvalidRefreshToken)
+			},
+			wantErr: auth.ErrInvalidRefreshToken,
+		},
+		{
+			name:  "expired token",
+			raw:   raw,
+			token: token(func(t *auth.RefreshToken) { t.ExpiresAt = testClock.Add(-time.Second) }),
+			setupMocks: func(r *mockRefreshTokenRepository, u *mockUserRepository, l *mockLogger, presented *auth.RefreshToken) {
+				r.On("FindByHash", mock.Anything, hashToken(raw)).Return(presented, nil)
+			},
+			wantErr: auth.ErrInvalidRefreshToken,
+		},
+		{
+			name:  "revoked token",
+			raw:   raw,
+			token: token(func(t *auth.RefreshToken) { t.RevokedAt = testClock.Add(-time.Second) }),
+			setupMocks: func(r *mockRefreshTokenRepository, u *mockUserRepository, l *mockLogger, presented *auth.RefreshToken) {
+				r.On("FindByHash", mock.Anything, hashToken(raw)).Return(presented, nil)
+			},
+			wantErr: auth.ErrInvalidRefreshToken,
+		},
+		{
+			name:  "reused token revokes its session",
+			raw:   raw,
+			token: token(func(t *auth.RefreshToken) { t.UsedAt = testClock.Add(-time.Second) }),
+			setupMocks: func(r *mockRefreshTokenRepository, u *mockUserRepository, l *mockLogger, presented *auth.RefreshToken) {
+				r.On("FindByHash", mock.Anything, hashToken(raw)).Return(presented, nil)
+				r.On("RevokeFamily", mock.Anything, presented.FamilyID).Return(nil)
+				l.On("Warn", "Refresh token reused, revoking its session").Once()
+			},
+			wantErr:            auth.ErrRefreshTokenReused,
+			wantSessionRevoked: true,
+		},
+		{
+			name:  "token exchanged concurrently counts as reuse",
+			raw:   raw,
+			token: token(nil),
+			setupMocks: func(r *mockRefreshTokenRepository, u *mockUserRepository, l *mockLogger, presented *auth.RefreshToken) {
+				r.On("FindByHash", mock.Anything, hashToken(raw)).Return(presented, nil)
+				r.On("MarkUsed", mock.Anything, presented.ID, mock.Anything).Return(false, nil)
+				r.On("RevokeFamily", mock.Anything, presented.FamilyID).Return(nil)
+				l.On("Warn", "Refresh token reused, revoking its session").Once()
+			},
+			wantErr:            auth.ErrRefreshTokenReused,
+			wantSessionRevoked: true,
+		},
+		{
+			name:  "inactive user",
+			raw:   raw,
+			token: token(nil),
+			setupMocks: func(r *mockRefreshTokenRepository, u *mockUserRepository, l *mockLogger, presented *auth.RefreshToken) {
+				r.On("FindByHash", mock.Anything, hashToken(raw)).Return(presented, nil)
+				r.On("MarkUsed", mock.Anything, presented.ID, mock.Anything).Return(true, nil)
+				u.On("FindByID", mock.Anything, alice.ID).Return(inactive, nil)
+				r.On("RevokeFamily", mock.Anything, presented.FamilyID).Return(nil)
+			},
+			wantErr:            auth.ErrInactive,
+			wantSessionRevoked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRefreshTokenRepository{}
+			userRepo := &mockUserRepository{}
+			log := &mockLogger{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo, userRepo, log, tt.token)
+			}
+			jwtService := newTestJWTService()
+			service := newTestTokenService(repo, userRepo, jwtService, log)
+
+			// An access token of the presented token's session
+			var access string
+			if tt.token != nil {
+				access = sessionToken(t, jwtService, alice, tt.token.FamilyID)
+			}
+
+			pair, u, err := service.RefreshTokens(context.Background(), tt.raw)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, alice.ID, u.ID)
+				assert.Equal(t, tt.token.FamilyID.String(), pair.SessionID)
+				assert.NotEqual(t, tt.raw, pair.RefreshToken)
+				_, err = jwtService.Authenticate(context.Background(), pair.AccessToken)
+				assert.NoError(t, err)
+			}
+
+			if access != "" {
+				_, err = jwtService.Authenticate(context.Background(), access)
+				if tt.wantSessionRevoked {
+					assert.ErrorIs(t, err, pkgauth.ErrRevokedToken)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+
+			repo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenService_Logout(t *testing.T) {
+	alice := &user.User{ID: user.ID(uuid.New()), Username: "alice", Email: "alice@example.com", Role: user.RoleUser}
+	familyID := uuid.New()
+	stored := &auth.RefreshToken{ID: uuid.New(), UserID: alice.ID, FamilyID: familyID, TokenHash: hashToken("refresh-token")}
+
+	tests := []struct {
+		name               string
+		req                auth.LogoutRequest
+		setupMocks         func(*mockRefreshTokenRepository)
+		wantSessionRevoked bool
+	}{
+		{
+			name: "by session",
+			req:  auth.LogoutRequest{SessionID: familyID.String()},
+			setupMocks: func(r *mockRefreshTokenRepository) {
+				r.On("RevokeFamily", mock.Anything, familyID).Return(nil)
+			},
+			wantSessionRevoked: true,
+		},
+		{
+			name: "by refresh token",
+			req:  auth.LogoutRequest{RefreshToken: "refresh-token"},
+			setupMocks: func(r *mockRefreshTokenRepository) {
+				r.On("FindByHash", mock.Anything, hashToken("refresh-token")).Return(stored, nil)
+				r.On("RevokeFamily", mock.Anything, familyID).Return(nil)
+			},
+			wantSessionRevoked: true,
+		},
+		{
+			name: "unknown refresh token",
+			req:  auth.LogoutRequest{RefreshToken: "not-a-token"},
+			setupMocks: func(r *mockRefreshTokenRepository) {
+				r.On("FindByHash", mock.Anything, hashToken("not-a-token")).Return(nil, auth.ErrInvalidRefreshToken)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRefreshTokenRepository{}
+			tt.setupMocks(repo)
+			jwtService := newTestJWTService()
+			service := newTestTokenService(repo, &mockUserRepository{}, jwtService, &mockLogger{})
+			access := sessionToken(t, jwtService, alice, familyID)
+
+			require.NoError(t, service.Logout(context.Background(), tt.req))
+
+			_, err := jwtService.Authenticate(context.Background(), access)
+			if tt.wantSessionRevoked {
+				assert.ErrorIs(t, err, pkgauth.ErrRevokedToken)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenService_RevokeAll(t *testing.T) {
+	alice := &user.User{ID: user.ID(uuid.New()), Username: "alice", Email: "alice@example.com", Role: user.RoleUser}
+	first, second, other := uuid.New(), uuid.New(), uuid.New()
+
+	repo := &mockRefreshTokenRepository{}
+	repo.On("RevokeUser", mock.Anything, alice.ID).Return([]uuid.UUID{first, second}, nil)
+	jwtService := newTestJWTService()
+	service := newTestTokenService(repo, &mockUserRepository{}, jwtService, &mockLogger{})
+
+	tokens := map[uuid.UUID]string{
+		first:  sessionToken(t, jwtService, alice, first),
+		second: sessionToken(t, jwtService, alice, second),
+		other:  sessionToken(t, jwtService, alice, other),
+	}
+
+	require.NoError(t, service.RevokeAll(context.Background(), alice.ID))
+
+	for _, familyID := range []uuid.UUID{first, second} {
+		_, err := jwtService.Authenticate(context.Background(), tokens[familyID])
+		assert.ErrorIs(t, err, pkgauth.ErrRevokedToken)
+	}
+	_, err := jwtService.Authenticate(context.Background(), tokens[other])
+	assert.NoError(t, err, "other sessions are left alone")
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,164 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"mem_bank/pkg/metrics"
+)
+
+// errGenerationAborted is handed to followers when the leading request gives
+// up without producing a result
+var errGenerationAborted = errors.New("embedding generation aborted")
+
+// inflightGroup coalesces concurrent generation of the same cache key within
+// the process. The first caller to claim a key leads and must resolve it;
+// later callers wait on the leader's call.
+type inflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+// inflightCall is a pending embedding for one cache key
+type inflightCall struct {
+	done   chan struct{}
+	result EmbeddingResult
+	err    error
+}
+
+func newInflightGroup() *inflightGroup {
+	return &inflightGroup{calls: make(map[string]*inflightCall)}
+}
+
+// claim returns the call for key and whether the caller leads it
+func (g *inflightGroup) claim(key string) (*inflightCall, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if call, ok := g.calls[key]; ok {
+		return call, false
+	}
+
+	call := &inflightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	return call, true
+}
+
+// resolve publishes the outcome of a led call and releases its waiters
+func (g *inflightGroup) resolve(key string, call *inflightCall, result EmbeddingResult, err error) {
+	g.mu.Lock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+
+	call.result = result
+	call.err = err
+	close(call.done)
+}
+
+// wait blocks until call is resolved or ctx is done
+func (c *inflightCall) wait(ctx context.Context) (EmbeddingResult, error) {
+	select {
+	case <-c.done:
+		return c.result, c.err
+	case <-ctx.Done():
+		return EmbeddingResult{}, ctx.Err()
+	}
+}
+
+// releaseLockScript deletes a lock only while it still holds our token
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// lockKey returns the Redis lock key guarding generation of cacheKey
+func lockKey(cacheKey string) string {
+	return lockKeyPrefix + cacheKey[len(cacheKeyPrefix):]
+}
+
+// acquireLocks tries to take the cross-replica generation lock for each key
+// and reports which ones were acquired. Redis errors count as acquired so a
+// cache outage degrades to uncoordinated generation rather than failure.
+func (s *Service) acquireLocks(ctx context.Context, keys []string, token string) []bool {
+	acquired := make([]bool, len(keys))
+
+	pipe := s.cache.Pipeline()
+	cmds := make([]*redis.BoolCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.SetNX(ctx, lockKey(key), token, s.config.LockTTL)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		s.logger.WithError(err).Warn("Failed to acquire embedding generation locks")
+		for i := range acquired {
+			acquired[i] = true
+		}
+		return acquired
+	}
+
+	for i, cmd := range cmds {
+		acquired[i] = cmd.Val()
+	}
+	return acquired
+}
+
+// releaseLocks drops the generation locks we hold for keys
+func (s *Service) releaseLocks(ctx context.Context, keys []string, token string) {
+	for _, key := range keys {
+		if err := releaseLockScript.Run(ctx, s.cache, []string{lockKey(key)}, token).Err(); err != nil {
+			s.logger.WithError(err).Warn("Failed to release embedding generation lock")
+		}
+	}
+}
+
+// awaitRemote polls Redis for keys that another replica is generating. It
+// returns the results it found by position; entries left unset timed out and
+// should be generated locally.
+func (s *Service) awaitRemote(ctx context.Context, keys []string) []*EmbeddingResult {
+	found := make([]*EmbeddingResult, len(keys))
+	pending := len(keys)
+
+	deadline := time.Now().Add(s.config.LockWaitTimeout)
+	ticker := time.NewTicker(s.config.LockPollInterval)
+	defer ticker.Stop()
+
+	for pending > 0 && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return found
+		case <-ticker.C:
+		}
+
+		lookup := make([]string, 0, pending)
+		positions := make([]int, 0, pending)
+		for i, key := range keys {
+			if found[i] == nil {
+				lookup = append(lookup, key)
+				positions = append(positions, i)
+			}
+		}
+
+		values, err := s.cache.MGet(ctx, lookup...).Result()
+		if err != nil {
+			metrics.EmbeddingCacheErrors.WithLabelValues(tierRedis, "get").Inc()
+			return found
+		}
+
+		for i, value := range values {
+			if result, ok := decodeCachedResult(value); ok {
+				found[positions[i]] = &result
+				pending--
+			}
+		}
+	}
+
+	return found
+}
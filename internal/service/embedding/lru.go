@@ -0,0 +1,104 @@
+package embedding
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruCache is a bounded, concurrency-safe in-process cache of embedding
+// results keyed by cache key. Entries expire after ttl.
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+type lruEntry struct {
+	key       string
+	result    EmbeddingResult
+	expiresAt time.Time
+}
+
+func newLRUCache(capacity int, ttl time.Duration) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// get returns the cached result for key and marks it as recently used
+func (c *lruCache) get(key string) (EmbeddingResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return EmbeddingResult{}, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if c.ttl > 0 && time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return EmbeddingResult{}, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.result, true
+}
+
+// set stores result under key, evicting the least recently used entry when full
+func (c *lruCache) set(key string, result EmbeddingResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.result = result
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, result: result, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// delete drops the entry for key, reporting whether there was one
+func (c *lruCache) delete(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if ok {
+		c.removeElement(elem)
+	}
+	return ok
+}
+
+// len returns the number of entries currently held
+func (c *lruCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// clear drops every entry
+func (c *lruCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]*list.Element, c.capacity)
+	c.order.Init()
+}
+
+func (c *lruCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*lruEntry).key)
+}
@@ -0,0 +1,40 @@
+package embedding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newLRUCache(2, time.Minute)
+
+	cache.set("a", EmbeddingResult{Text: "a"})
+	cache.set("b", EmbeddingResult{Text: "b"})
+
+	// Touch "a" so "b" becomes the eviction candidate
+	_, ok := cache.get("a")
+	assert.True(t, ok)
+
+	cache.set("c", EmbeddingResult{Text: "c"})
+
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+	_, ok = cache.get("a")
+	assert.True(t, ok)
+	_, ok = cache.get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, cache.len())
+}
+
+func TestLRUCache_ExpiresEntries(t *testing.T) {
+	cache := newLRUCache(2, time.Millisecond)
+
+	cache.set("a", EmbeddingResult{Text: "a"})
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := cache.get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.len())
+}
@@ -0,0 +1,393 @@
+package embedding
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"mem_bank/internal/domain/pii"
+	"mem_bank/pkg/llm"
+	pkgpii "mem_bank/pkg/pii"
+)
+
+// Document is text passing through a preprocessing pipeline together with
+// what the stages learned about it
+type Document struct {
+	Text     string
+	Language string
+}
+
+// Preprocessor is a single stage of a preprocessing pipeline
+type Preprocessor interface {
+	// Name identifies the stage and its settings; it is part of the cache key
+	Name() string
+
+	// Process transforms doc in place
+	Process(doc *Document)
+}
+
+// Pipeline runs preprocessors in order
+type Pipeline []Preprocessor
+
+// PipelineConfig configures a preprocessing pipeline for one embedding model
+type PipelineConfig struct {
+	// Stage names in execution order, see NewPipeline
+	Steps []string `mapstructure:"steps"`
+
+	// Limit for the truncate_runes stage
+	MaxRunes int `mapstructure:"max_runes"`
+
+	// Limit for the truncate_tokens stage
+	MaxTokens int `mapstructure:"max_tokens"`
+}
+
+// NewPipeline builds a pipeline from config. Known steps are nfkc,
+// whitespace, lowercase, punctuation, strip_markup, mask_pii,
+// truncate_runes, truncate_tokens and detect_language.
+func NewPipeline(config PipelineConfig) (Pipeline, error) {
+	pipeline := make(Pipeline, 0, len(config.Steps))
+
+	for _, step := range config.Steps {
+		switch step {
+		case "nfkc":
+			pipeline = append(pipeline, NFKCNormalizer{})
+		case "whitespace":
+			pipeline = append(pipeline, WhitespaceNormalizer{})
+		case "lowercase":
+			pipeline = append(pipeline, Lowercaser{})
+		case "punctuation":
+			pipeline = append(pipeline, PunctuationCollapser{})
+		case "strip_markup":
+			pipeline = append(pipeline, MarkupStripper{})
+		case "mask_pii":
+			pipeline = append(pipeline, PIIMasker{})
+		case "truncate_runes":
+			if config.MaxRunes <= 0 {
+				return nil, fmt.Errorf("truncate_runes requires max_runes")
+			}
+			pipeline = append(pipeline, RuneTruncator{MaxRunes: config.MaxRunes})
+		case "truncate_tokens":
+			if config.MaxTokens <= 0 {
+				return nil, fmt.Errorf("truncate_tokens requires max_tokens")
+			}
+			pipeline = append(pipeline, TokenTruncator{MaxTokens: config.MaxTokens})
+		case "detect_language":
+			pipeline = append(pipeline, LanguageDetector{})
+		default:
+			return nil, fmt.Errorf("unknown preprocessing step %q", step)
+		}
+	}
+
+	return pipeline, nil
+}
+
+// legacyPipeline mirrors the fixed PreprocessingConfig behaviour
+func legacyPipeline(config PreprocessingConfig) Pipeline {
+	var pipeline Pipeline
+	if config.NormalizeWhitespace {
+		pipeline = append(pipeline, WhitespaceNormalizer{})
+	}
+	if config.ToLowercase {
+		pipeline = append(pipeline, Lowercaser{})
+	}
+	if config.RemoveExtraPunctuation {
+		pipeline = append(pipeline, PunctuationCollapser{})
+	}
+	return pipeline
+}
+
+// Process runs text through every stage
+func (p Pipeline) Process(text string) Document {
+	doc := Document{Text: text}
+	if text == "" {
+		return doc
+	}
+
+	for _, stage := range p {
+		stage.Process(&doc)
+	}
+	return doc
+}
+
+// Fingerprint describes the pipeline for cache keys
+func (p Pipeline) Fingerprint() string {
+	names := make([]string, len(p))
+	for i, stage := range p {
+		names[i] = stage.Name()
+	}
+	return strings.Join(names, "|")
+}
+
+// NFKCNormalizer applies Unicode NFKC normalisation, folding full-width and
+// compatibility characters into their canonical forms
+type NFKCNormalizer struct{}
+
+func (NFKCNormalizer) Name() string { return "nfkc" }
+
+func (NFKCNormalizer) Process(doc *Document) {
+	doc.Text = norm.NFKC.String(doc.Text)
+}
+
+// WhitespaceNormalizer trims text and collapses whitespace runs to one space
+type WhitespaceNormalizer struct{}
+
+func (WhitespaceNormalizer) Name() string { return "whitespace" }
+
+func (WhitespaceNormalizer) Process(doc *Document) {
+	doc.Text = strings.Join(strings.Fields(doc.Text), " ")
+}
+
+// Lowercaser converts text to lower case
+type Lowercaser struct{}
+
+func (Lowercaser) Name() string { return "lowercase" }
+
+func (Lowercaser) Process(doc *Document) {
+	doc.Text = strings.ToLower(doc.Text)
+}
+
+// PunctuationCollapser reduces repeated terminal punctuation
+type PunctuationCollapser struct{}
+
+func (PunctuationCollapser) Name() string { return "punctuation" }
+
+func (PunctuationCollapser) Process(doc *Document) {
+	doc.Text = strings.ReplaceAll(doc.Text, "...", ".")
+	doc.Text = strings.ReplaceAll(doc.Text, "!!!", "!")
+	doc.Text = strings.ReplaceAll(doc.Text, "???", "?")
+}
+
+// RuneTruncator keeps at most MaxRunes runes so multi-byte characters are
+// never split
+type RuneTruncator struct {
+	MaxRunes int
+}
+
+func (t RuneTruncator) Name() string { return fmt.Sprintf("truncate_runes(%d)", t.MaxRunes) }
+
+func (t RuneTruncator) Process(doc *Document) {
+	doc.Text = truncateRunes(doc.Text, t.MaxRunes)
+}
+
+func truncateRunes(text string, maxRunes int) string {
+	if len(text) <= maxRunes {
+		return text
+	}
+
+	count := 0
+	for i := range text {
+		if count == maxRunes {
+			return text[:i]
+		}
+		count++
+	}
+	return text
+}
+
+// TokenTruncator keeps an approximate MaxTokens tokens as counted by
+// llm.ApproximateTokenizer, cutting only at token boundaries
+type TokenTruncator struct {
+	MaxTokens int
+}
+
+func (t TokenTruncator) Name() string { return fmt.Sprintf("truncate_tokens(%d)", t.MaxTokens) }
+
+func (t TokenTruncator) Process(doc *Document) {
+	doc.Text = llm.ApproximateTokenizer{}.Truncate(doc.Text, t.MaxTokens)
+}
+
+// ModelTokenLimit cuts text to the input limit of the embedding model, counted
+// with the model's own tokenizer so requests are not rejected as too long
+type ModelTokenLimit struct {
+	Tokenizer llm.Tokenizer
+	MaxTokens int
+}
+
+func (t ModelTokenLimit) Name() string { return fmt.Sprintf("model_token_limit(%d)", t.MaxTokens) }
+
+func (t ModelTokenLimit) Process(doc *Document) {
+	doc.Text = t.Tokenizer.Truncate(doc.Text, t.MaxTokens)
+}
+
+var (
+	htmlBlockPattern    = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	htmlTagPattern      = regexp.MustCompile(`(?s)<[^>]+>`)
+	mdFencePattern      = regexp.MustCompile("(?m)^[ \\t]*(```|~~~).*$")
+	mdImagePattern      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLinkPattern       = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	mdHeadingPattern    = regexp.MustCompile(`(?m)^[ \t]{0,3}#{1,6}[ \t]+`)
+	mdQuotePattern      = regexp.MustCompile(`(?m)^[ \t]*>[ \t]?`)
+	mdListPattern       = regexp.MustCompile(`(?m)^[ \t]*(?:[-*+]|\d+\.)[ \t]+`)
+	mdRulePattern       = regexp.MustCompile(`(?m)^[ \t]*(?:[-*_][ \t]*){3,}$`)
+	mdBoldPattern       = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdItalicPattern     = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	mdStrikePattern     = regexp.MustCompile(`~~(.+?)~~`)
+	mdInlineCodePattern = regexp.MustCompile("`([^`]*)`")
+)
+
+// MarkupStripper removes HTML tags and Markdown syntax, keeping the text
+type MarkupStripper struct{}
+
+func (MarkupStripper) Name() string { return "strip_markup" }
+
+func (MarkupStripper) Process(doc *Document) {
+	text := htmlBlockPattern.ReplaceAllString(doc.Text, " ")
+	text = htmlTagPattern.ReplaceAllString(text, " ")
+	text = html.UnescapeString(text)
+
+	text = mdFencePattern.ReplaceAllString(text, "")
+	text = mdImagePattern.ReplaceAllString(text, "$1")
+	text = mdLinkPattern.ReplaceAllString(text, "$1")
+	text = mdRulePattern.ReplaceAllString(text, "")
+	text = mdHeadingPattern.ReplaceAllString(text, "")
+	text = mdQuotePattern.ReplaceAllString(text, "")
+	text = mdListPattern.ReplaceAllString(text, "")
+	text = mdBoldPattern.ReplaceAllString(text, "$2")
+	text = mdItalicPattern.ReplaceAllString(text, "$1")
+	text = mdStrikePattern.ReplaceAllString(text, "$1")
+	text = mdInlineCodePattern.ReplaceAllString(text, "$1")
+
+	doc.Text = strings.TrimSpace(text)
+}
+
+// builtinPIIDetector finds the personal data that memories are screened for
+var builtinPIIDetector = pkgpii.NewDetector(pkgpii.BuiltinRules()...)
+
+// PIIMasker replaces personal data with the placeholders used when memories
+// are redacted. It shares its rules with memory screening, see the pii
+// package.
+type PIIMasker struct {
+	// Detector finds the data to mask; nil uses the built-in rules
+	Detector *pkgpii.Detector
+}
+
+func (m PIIMasker) detector() *pkgpii.Detector {
+	if m.Detector == nil {
+		return builtinPIIDetector
+	}
+	return m.Detector
+}
+
+// Name lists the kinds masked, so changing the rules changes cache keys
+func (m PIIMasker) Name() string {
+	return fmt.Sprintf("mask_pii(%s)", strings.Join(m.detector().Kinds(), ","))
+}
+
+func (m PIIMasker) Process(doc *Document) {
+	doc.Text = pkgpii.Replace(doc.Text, m.detector().Detect(doc.Text), func(match pkgpii.Match) string {
+		return pii.RedactedPlaceholder(match.Kind)
+	})
+}
+
+// LanguageDetector guesses the dominant language from its script, using a
+// short stop-word list to tell common Latin-script languages apart. It sets
+// Document.Language to an ISO 639-1 code, or "und" when unsure.
+type LanguageDetector struct{}
+
+func (LanguageDetector) Name() string { return "detect_language" }
+
+var scriptLanguages = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Greek, "el"},
+	{unicode.Thai, "th"},
+	{unicode.Hebrew, "he"},
+}
+
+var stopWords = map[string][]string{
+	"en": {"the", "and", "is", "of", "to", "in", "that", "it", "with", "for"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "es", "por", "con"},
+	"fr": {"le", "la", "les", "de", "et", "est", "un", "une", "des", "pour"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "mit", "zu"},
+}
+
+func (LanguageDetector) Process(doc *Document) {
+	doc.Language = detectLanguage(doc.Text)
+}
+
+func detectLanguage(text string) string {
+	if text == "" {
+		return "und"
+	}
+
+	var han, kana, latin, letters int
+	scripts := make(map[string]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		default:
+			for _, s := range scriptLanguages {
+				if unicode.Is(s.table, r) {
+					scripts[s.language]++
+					break
+				}
+			}
+		}
+	}
+	if letters == 0 {
+		return "und"
+	}
+
+	// Japanese mixes kana with Han; any meaningful kana share decides it
+	if kana > 0 && kana*10 >= (kana+han) {
+		return "ja"
+	}
+
+	best, bestCount := "", 0
+	for language, count := range scripts {
+		if count > bestCount {
+			best, bestCount = language, count
+		}
+	}
+	if han > bestCount {
+		best, bestCount = "zh", han
+	}
+	if latin > bestCount {
+		return detectLatinLanguage(text)
+	}
+	if best == "" {
+		return "und"
+	}
+	return best
+}
+
+func detectLatinLanguage(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	best, bestScore := "und", 0
+	for language, list := range stopWords {
+		score := 0
+		for _, word := range words {
+			for _, stop := range list {
+				if word == stop {
+					score++
+					break
+				}
+			}
+		}
+		if score > bestScore || (score == bestScore && score > 0 && language < best) {
+			best, bestScore = language, score
+		}
+	}
+	return best
+}
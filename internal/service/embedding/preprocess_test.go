@@ -0,0 +1,131 @@
+package embedding
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/configs"
+	"mem_bank/pkg/logger"
+)
+
+func TestNewPipeline(t *testing.T) {
+	pipeline, err := NewPipeline(PipelineConfig{
+		Steps:    []string{"nfkc", "whitespace", "truncate_runes"},
+		MaxRunes: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "nfkc|whitespace|truncate_runes(10)", pipeline.Fingerprint())
+
+	_, err = NewPipeline(PipelineConfig{Steps: []string{"unknown"}})
+	assert.Error(t, err)
+
+	_, err = NewPipeline(PipelineConfig{Steps: []string{"truncate_tokens"}})
+	assert.Error(t, err)
+}
+
+func TestPreprocessors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stage    Preprocessor
+		input    string
+		expected string
+	}{
+		{
+			name:     "nfkc_folds_full_width",
+			stage:    NFKCNormalizer{},
+			input:    "ＡＢＣ１２３，你好",
+			expected: "ABC123,你好",
+		},
+		{
+			name:     "truncate_runes_keeps_whole_characters",
+			stage:    RuneTruncator{MaxRunes: 3},
+			input:    "我们的记忆",
+			expected: "我们的",
+		},
+		{
+			name:     "truncate_tokens_counts_cjk_per_character",
+			stage:    TokenTruncator{MaxTokens: 4},
+			input:    "今天天气很好",
+			expected: "今天天气",
+		},
+		{
+			name:     "truncate_tokens_cuts_at_word_boundary",
+			stage:    TokenTruncator{MaxTokens: 3},
+			input:    "remember the extraordinary milk",
+			expected: "remember the",
+		},
+		{
+			name:     "strip_html",
+			stage:    MarkupStripper{},
+			input:    "<p>Hello <b>world</b> &amp; friends</p><script>alert(1)</script>",
+			expected: "Hello  world  & friends",
+		},
+		{
+			name:     "strip_markdown",
+			stage:    MarkupStripper{},
+			input:    "# Title\n\n- **bold** item with [a link](https://example.com)\n\n```go\ncode()\n```",
+			expected: "Title\n\nbold item with a link\n\n\ncode()",
+		},
+		{
+			name:     "mask_pii",
+			stage:    PIIMasker{},
+			input:    "mail bob@example.com, call +1 (555) 123-4567 with card 4111 1111 1111 1111 in 2024",
+			expected: "mail [REDACTED:EMAIL], call [REDACTED:PHONE] with card [REDACTED:CREDIT_CARD] in 2024",
+		},
+		{
+			name:     "mask_pii_credentials",
+			stage:    PIIMasker{},
+			input:    "key sk-abcdefghijklmnopqrstuvwx and ssn 123-45-6789",
+			expected: "key [REDACTED:API_KEY] and ssn [REDACTED:SSN]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := Document{Text: tc.input}
+			tc.stage.Process(&doc)
+			assert.Equal(t, tc.expected, doc.Text)
+			assert.True(t, utf8.ValidString(doc.Text))
+		})
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	testCases := map[string]string{
+		"我今天去了超市买牛奶":                          "zh",
+		"今日はスーパーで牛乳を買いました":                    "ja",
+		"오늘 마트에서 우유를 샀어요":                     "ko",
+		"Сегодня я купил молоко":              "ru",
+		"I went to the store and bought milk": "en",
+		"Fui a la tienda y compré la leche":   "es",
+		"12345":                               "und",
+	}
+
+	for input, expected := range testCases {
+		doc := Document{Text: input}
+		LanguageDetector{}.Process(&doc)
+		assert.Equal(t, expected, doc.Language, input)
+	}
+}
+
+func TestService_UsesPipelinePerModel(t *testing.T) {
+	provider := &MockEmbeddingProvider{}
+	logger, _ := logger.NewLogger(&configs.LoggingConfig{Level: "info"})
+
+	service := NewService(provider, nil, logger, Config{
+		MaxTextLength: 30,
+		Pipelines: map[string]PipelineConfig{
+			"test-model": {Steps: []string{"nfkc", "mask_pii"}},
+			"default":    {Steps: []string{"lowercase"}},
+		},
+	})
+
+	provider.On("GetDefaultModel").Return("test-model")
+
+	text := service.preprocessText("ＭＡＩＬ a@example.com " + strings.Repeat("x", 20))
+	assert.Equal(t, "MAIL [REDACTED:EMAIL] xxxxxxxx", text)
+}
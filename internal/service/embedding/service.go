@@ -2,26 +2,53 @@ package embedding
 
 import (
 	"context"
-	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
 	"mem_bank/pkg/llm"
 	"mem_bank/pkg/logger"
+	"mem_bank/pkg/metrics"
 )
 
 // Service provides embedding generation with caching and preprocessing
 type Service struct {
 	provider llm.EmbeddingProvider
 	cache    *redis.Client
-	logger   logger.Logger
-	config   Config
+	local    *lruCache
+	stats    *cacheStats
+	inflight *inflightGroup
+
+	// Preprocessing pipelines by model, and the one used when none matches
+	pipelines map[string]Pipeline
+	fallback  Pipeline
+	logger    logger.Logger
+	config    Config
 }
 
+const (
+	// cacheKeyPrefix namespaces embedding entries in Redis
+	cacheKeyPrefix = "embedding:v2:"
+
+	// defaultPipeline is the Pipelines entry used for unlisted models
+	defaultPipeline = "default"
+
+	// lockKeyPrefix namespaces cross-replica generation locks in Redis
+	lockKeyPrefix = "embedding:lock:"
+
+	// clearCacheScanCount is the SCAN batch size used by ClearCache
+	clearCacheScanCount = 1000
+
+	tierLocal = "local"
+	tierRedis = "redis"
+)
+
 // Config holds embedding service configuration
 type Config struct {
 	// Maximum text length to process
@@ -33,11 +60,35 @@ type Config struct {
 	// Cache TTL in minutes
 	CacheTTLMinutes int `mapstructure:"cache_ttl_minutes"`
 
+	// Maximum entries held in the in-process cache tier; negative disables it
+	LocalCacheSize int `mapstructure:"local_cache_size"`
+
 	// Batch size for processing multiple texts
 	BatchSize int `mapstructure:"batch_size"`
 
-	// Content preprocessing options
+	// Whether to coordinate generation of the same text across replicas with
+	// a Redis lock; in-process coalescing is always on while caching is enabled
+	DistributedLock bool `mapstructure:"distributed_lock"`
+
+	// How long a replica may hold a generation lock
+	LockTTL time.Duration `mapstructure:"lock_ttl"`
+
+	// How long to wait for another replica before generating locally
+	LockWaitTimeout time.Duration `mapstructure:"lock_wait_timeout"`
+
+	// How often to poll Redis while waiting for another replica
+	LockPollInterval time.Duration `mapstructure:"lock_poll_interval"`
+
+	// Content preprocessing options, used when no pipeline is configured
 	PreprocessingConfig PreprocessingConfig `mapstructure:"preprocessing"`
+
+	// Preprocessing pipelines keyed by embedding model name; the "default"
+	// entry applies to models without their own
+	Pipelines map[string]PipelineConfig `mapstructure:"pipelines"`
+
+	// Tokenizers used to hold texts to the model's input limit; nil counts
+	// tokens approximately
+	Tokenizers *llm.Tokenizers `mapstructure:"-"`
 }
 
 // PreprocessingConfig holds text preprocessing configuration
@@ -63,6 +114,7 @@ type EmbeddingResult struct {
 	Text      string    `json:"text"`
 	Embedding []float32 `json:"embedding"`
 	Model     string    `json:"model"`
+	Language  string    `json:"language,omitempty"`
 	Cached    bool      `json:"cached"`
 }
 
@@ -84,6 +136,18 @@ func NewService(provider llm.EmbeddingProvider, cache *redis.Client, logger logg
 	if config.CacheTTLMinutes == 0 {
 		config.CacheTTLMinutes = 60 * 24 // 24 hours
 	}
+	if config.LockTTL == 0 {
+		config.LockTTL = 30 * time.Second
+	}
+	if config.LockWaitTimeout == 0 {
+		config.LockWaitTimeout = 10 * time.Second
+	}
+	if config.LockPollInterval == 0 {
+		config.LockPollInterval = 50 * time.Millisecond
+	}
+	if config.LocalCacheSize == 0 {
+		config.LocalCacheSize = 10000
+	}
 	if config.PreprocessingConfig.ChunkSize == 0 {
 		config.PreprocessingConfig.ChunkSize = 4000
 	}
@@ -91,11 +155,36 @@ func NewService(provider llm.EmbeddingProvider, cache *redis.Client, logger logg
 		config.PreprocessingConfig.ChunkOverlap = 200
 	}
 
+	var local *lruCache
+	if config.CacheEnabled && config.LocalCacheSize > 0 {
+		local = newLRUCache(config.LocalCacheSize, time.Duration(config.CacheTTLMinutes)*time.Minute)
+	}
+
+	// Every pipeline ends with a rune-safe cut at MaxTextLength
+	limit := RuneTruncator{MaxRunes: config.MaxTextLength}
+	pipelines := make(map[string]Pipeline, len(config.Pipelines))
+	for model, pipelineConfig := range config.Pipelines {
+		pipeline, err := NewPipeline(pipelineConfig)
+		if err != nil {
+			logger.WithFields(map[string]interface{}{
+				"model": model,
+				"error": err.Error(),
+			}).Error("Invalid preprocessing pipeline, falling back")
+			continue
+		}
+		pipelines[model] = append(pipeline, limit)
+	}
+
 	return &Service{
-		provider: provider,
-		cache:    cache,
-		logger:   logger,
-		config:   config,
+		pipelines: pipelines,
+		fallback:  append(legacyPipeline(config.PreprocessingConfig), limit),
+		provider:  provider,
+		cache:     cache,
+		local:     local,
+		stats:     &cacheStats{},
+		inflight:  newInflightGroup(),
+		logger:    logger,
+		config:    config,
 	}
 }
 
@@ -113,120 +202,330 @@ func (s *Service) GenerateEmbedding(ctx context.Context, text string) (*Embeddin
 	return &results.Results[0], nil
 }
 
-// GenerateEmbeddings generates embeddings for multiple texts
+// GenerateEmbeddings generates embeddings for multiple texts. Results are
+// returned in input order; cached entries are served from the local tier
+// first, then Redis, and only the remaining texts reach the provider.
 func (s *Service) GenerateEmbeddings(ctx context.Context, texts []string) (*BatchEmbeddingResult, error) {
 	if len(texts) == 0 {
 		return &BatchEmbeddingResult{Results: []EmbeddingResult{}}, nil
 	}
 
 	// Preprocess texts
+	pipeline := s.pipeline()
 	processedTexts := make([]string, len(texts))
+	languages := make([]string, len(texts))
 	for i, text := range texts {
-		processedTexts[i] = s.preprocessText(text)
+		doc := pipeline.Process(text)
+		processedTexts[i] = doc.Text
+		languages[i] = doc.Language
 	}
 
-	results := make([]EmbeddingResult, 0, len(processedTexts))
-	var totalUsage llm.Usage
-
-	// Check cache first if enabled
-	if s.config.CacheEnabled && s.cache != nil {
-		cachedResults, uncachedTexts, uncachedIndices := s.checkCache(ctx, processedTexts)
-		results = append(results, cachedResults...)
+	if !s.cachingEnabled() {
+		// Generate all embeddings without caching
+		generatedResults, usage, err := s.generateUncachedEmbeddings(ctx, processedTexts)
+		if err != nil {
+			return nil, err
+		}
 
-		// Generate embeddings for uncached texts
-		if len(uncachedTexts) > 0 {
-			generatedResults, usage, err := s.generateUncachedEmbeddings(ctx, uncachedTexts)
-			if err != nil {
-				return nil, err
-			}
+		setLanguages(generatedResults, languages)
+		return &BatchEmbeddingResult{
+			Results: generatedResults,
+			Usage:   usage,
+		}, nil
+	}
 
-			totalUsage.PromptTokens += usage.PromptTokens
-			totalUsage.TotalTokens += usage.TotalTokens
+	fingerprint := pipeline.Fingerprint()
+	keys := make([]string, len(processedTexts))
+	for i, text := range processedTexts {
+		keys[i] = s.getCacheKey(fingerprint, text)
+	}
 
-			// Cache new results
-			s.cacheResults(ctx, generatedResults)
+	results := make([]EmbeddingResult, len(processedTexts))
+	uncachedIndices := s.checkCache(ctx, keys, results)
 
-			// Merge results in original order
-			results = s.mergeResults(results, generatedResults, uncachedIndices)
-		}
-	} else {
-		// Generate all embeddings without caching
-		generatedResults, usage, err := s.generateUncachedEmbeddings(ctx, processedTexts)
+	var totalUsage llm.Usage
+	if len(uncachedIndices) > 0 {
+		usage, err := s.generateCoalesced(ctx, processedTexts, keys, uncachedIndices, results)
 		if err != nil {
 			return nil, err
 		}
-
-		results = generatedResults
 		totalUsage = usage
 	}
 
+	setLanguages(results, languages)
 	return &BatchEmbeddingResult{
 		Results: results,
 		Usage:   totalUsage,
 	}, nil
 }
 
+// setLanguages copies detected languages onto results; the provider may
+// return fewer results than inputs, so only the overlap is filled
+func setLanguages(results []EmbeddingResult, languages []string) {
+	for i := range results {
+		if i < len(languages) {
+			results[i].Language = languages[i]
+		}
+	}
+}
+
 // preprocessText applies preprocessing to text
 func (s *Service) preprocessText(text string) string {
-	if text == "" {
-		return text
+	return s.pipeline().Process(text).Text
+}
+
+// pipeline returns the preprocessing pipeline for the provider's model,
+// falling back to the "default" entry and then to PreprocessingConfig
+func (s *Service) pipeline() Pipeline {
+	model := s.provider.GetDefaultModel()
+
+	pipeline := s.fallback
+	if configured, ok := s.pipelines[model]; ok {
+		pipeline = configured
+	} else if configured, ok := s.pipelines[defaultPipeline]; ok {
+		pipeline = configured
 	}
 
-	config := s.config.PreprocessingConfig
+	// Hold texts to the model's input limit so the provider never rejects them
+	if maxTokens := llm.MaxInputTokens(model); maxTokens > 0 {
+		limited := make(Pipeline, 0, len(pipeline)+1)
+		limited = append(limited, pipeline...)
+		return append(limited, ModelTokenLimit{
+			Tokenizer: s.config.Tokenizers.For(model),
+			MaxTokens: maxTokens,
+		})
+	}
+	return pipeline
+}
 
-	// Normalize whitespace
-	if config.NormalizeWhitespace {
-		text = strings.TrimSpace(text)
-		// Replace multiple whitespace with single space
-		text = strings.Join(strings.Fields(text), " ")
+// generateCoalesced fills results at indices, sharing work with concurrent
+// requests for the same cache keys. Keys no other request is producing are
+// generated here in one batch; the rest are awaited. With DistributedLock set,
+// keys another replica holds the lock for are awaited through Redis too.
+func (s *Service) generateCoalesced(ctx context.Context, texts, keys []string, indices []int, results []EmbeddingResult) (llm.Usage, error) {
+	var usage llm.Usage
+
+	// Duplicates within the request resolve through their first occurrence
+	first := make(map[string]int, len(indices))
+	var unique, duplicates []int
+	for _, idx := range indices {
+		if _, ok := first[keys[idx]]; ok {
+			duplicates = append(duplicates, idx)
+			continue
+		}
+		first[keys[idx]] = idx
+		unique = append(unique, idx)
 	}
 
-	// Convert to lowercase
-	if config.ToLowercase {
-		text = strings.ToLower(text)
+	var leading, following []int
+	calls := make(map[int]*inflightCall, len(unique))
+	for _, idx := range unique {
+		call, leader := s.inflight.claim(keys[idx])
+		calls[idx] = call
+		if leader {
+			leading = append(leading, idx)
+		} else {
+			following = append(following, idx)
+		}
 	}
 
-	// Remove extra punctuation (simplified implementation)
-	if config.RemoveExtraPunctuation {
-		// Remove multiple consecutive punctuation marks
-		text = strings.ReplaceAll(text, "...", ".")
-		text = strings.ReplaceAll(text, "!!!", "!")
-		text = strings.ReplaceAll(text, "???", "?")
+	// Resolve every led call on the way out so followers never hang
+	resolved := make(map[int]bool, len(leading))
+	defer func() {
+		for _, idx := range leading {
+			if !resolved[idx] {
+				s.inflight.resolve(keys[idx], calls[idx], EmbeddingResult{}, errGenerationAborted)
+			}
+		}
+	}()
+
+	generate := leading
+	if s.config.DistributedLock && s.cache != nil && len(leading) > 0 {
+		token := uuid.NewString()
+		leadingKeys := make([]string, len(leading))
+		for i, idx := range leading {
+			leadingKeys[i] = keys[idx]
+		}
+		acquired := s.acquireLocks(ctx, leadingKeys, token)
+
+		var locked, remote []int
+		var lockedKeys, remoteKeys []string
+		for i, idx := range leading {
+			if acquired[i] {
+				locked = append(locked, idx)
+				lockedKeys = append(lockedKeys, keys[idx])
+			} else {
+				remote = append(remote, idx)
+				remoteKeys = append(remoteKeys, keys[idx])
+			}
+		}
+		defer s.releaseLocks(context.WithoutCancel(ctx), lockedKeys, token)
+
+		generate = locked
+		for i, result := range s.awaitRemote(ctx, remoteKeys) {
+			idx := remote[i]
+			if result == nil {
+				// The other replica did not finish in time; generate it ourselves
+				generate = append(generate, idx)
+				continue
+			}
+			results[idx] = *result
+			s.inflight.resolve(keys[idx], calls[idx], *result, nil)
+			resolved[idx] = true
+			metrics.EmbeddingCoalesced.WithLabelValues("redis").Inc()
+		}
 	}
 
-	// Truncate if too long
-	if len(text) > s.config.MaxTextLength {
-		text = text[:s.config.MaxTextLength]
+	if len(generate) > 0 {
+		generated, err := s.generateAt(ctx, texts, keys, generate, results, &usage)
+		if err != nil {
+			return usage, err
+		}
+		for i, idx := range generate {
+			s.inflight.resolve(keys[idx], calls[idx], generated[i], nil)
+			resolved[idx] = true
+		}
+	}
+
+	// Wait for other requests; if their leader failed, generate ourselves
+	var retry []int
+	for _, idx := range following {
+		result, err := calls[idx].wait(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return usage, err
+			}
+			retry = append(retry, idx)
+			continue
+		}
+		results[idx] = result
+		metrics.EmbeddingCoalesced.WithLabelValues("local").Inc()
+	}
+	if len(retry) > 0 {
+		if _, err := s.generateAt(ctx, texts, keys, retry, results, &usage); err != nil {
+			return usage, err
+		}
+	}
+
+	for _, idx := range duplicates {
+		results[idx] = results[first[keys[idx]]]
 	}
 
-	return text
+	return usage, nil
 }
 
-// checkCache checks for cached embeddings and returns cached results and uncached texts
-func (s *Service) checkCache(ctx context.Context, texts []string) ([]EmbeddingResult, []string, []int) {
-	cachedResults := []EmbeddingResult{}
-	uncachedTexts := []string{}
-	uncachedIndices := []int{}
+// generateAt generates and caches embeddings for texts at indices, storing
+// them in results and adding the provider usage to usage
+func (s *Service) generateAt(ctx context.Context, texts, keys []string, indices []int, results []EmbeddingResult, usage *llm.Usage) ([]EmbeddingResult, error) {
+	batch := make([]string, len(indices))
+	batchKeys := make([]string, len(indices))
+	for i, idx := range indices {
+		batch[i] = texts[idx]
+		batchKeys[i] = keys[idx]
+	}
 
-	for i, text := range texts {
-		cacheKey := s.getCacheKey(text)
+	generated, batchUsage, err := s.generateUncachedEmbeddings(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	if len(generated) != len(batch) {
+		return nil, fmt.Errorf("provider returned %d embeddings for %d texts", len(generated), len(batch))
+	}
+
+	usage.PromptTokens += batchUsage.PromptTokens
+	usage.TotalTokens += batchUsage.TotalTokens
 
-		cachedData, err := s.cache.Get(ctx, cacheKey).Result()
-		if err == nil {
-			var result EmbeddingResult
-			if err := json.Unmarshal([]byte(cachedData), &result); err == nil {
+	for i, idx := range indices {
+		results[idx] = generated[i]
+	}
+
+	// Cache new results
+	s.cacheResults(ctx, batchKeys, generated)
+
+	return generated, nil
+}
+
+// cachingEnabled reports whether any cache tier is available
+func (s *Service) cachingEnabled() bool {
+	return s.config.CacheEnabled && (s.local != nil || s.cache != nil)
+}
+
+// checkCache fills results with cached embeddings for keys and returns the
+// indices that were found in neither tier
+func (s *Service) checkCache(ctx context.Context, keys []string, results []EmbeddingResult) []int {
+	missing := make([]int, 0, len(keys))
+
+	for i, key := range keys {
+		if s.local != nil {
+			if result, ok := s.local.get(key); ok {
 				result.Cached = true
-				cachedResults = append(cachedResults, result)
+				results[i] = result
+				s.recordLookup(tierLocal, true)
 				continue
 			}
+			s.recordLookup(tierLocal, false)
+		}
+		missing = append(missing, i)
+	}
+
+	if s.cache == nil || len(missing) == 0 {
+		return missing
+	}
+
+	redisKeys := make([]string, len(missing))
+	for i, idx := range missing {
+		redisKeys[i] = keys[idx]
+	}
+
+	values, err := s.cache.MGet(ctx, redisKeys...).Result()
+	if err != nil {
+		metrics.EmbeddingCacheErrors.WithLabelValues(tierRedis, "get").Inc()
+		s.logger.WithError(err).Warn("Failed to read embeddings from cache")
+		return missing
+	}
+
+	uncached := missing[:0]
+	for i, idx := range missing {
+		if result, ok := decodeCachedResult(values[i]); ok {
+			result.Cached = true
+			results[idx] = result
+			s.recordLookup(tierRedis, true)
+			if s.local != nil {
+				s.local.set(keys[idx], result)
+			}
+			continue
 		}
 
 		// Not cached or error unmarshalling
-		uncachedTexts = append(uncachedTexts, text)
-		uncachedIndices = append(uncachedIndices, i)
+		s.recordLookup(tierRedis, false)
+		uncached = append(uncached, idx)
 	}
 
-	return cachedResults, uncachedTexts, uncachedIndices
+	return uncached
+}
+
+// decodeCachedResult decodes a single MGET value
+func decodeCachedResult(value interface{}) (EmbeddingResult, bool) {
+	data, ok := value.(string)
+	if !ok {
+		return EmbeddingResult{}, false
+	}
+
+	var result EmbeddingResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return EmbeddingResult{}, false
+	}
+	return result, true
+}
+
+// recordLookup counts a cache lookup for tier
+func (s *Service) recordLookup(tier string, hit bool) {
+	result := metrics.ResultMiss
+	if hit {
+		result = metrics.ResultHit
+	}
+	metrics.EmbeddingCacheLookups.WithLabelValues(tier, result).Inc()
+	s.stats.record(tier, hit)
 }
 
 // generateUncachedEmbeddings generates embeddings for texts not in cache
@@ -270,101 +569,176 @@ func (s *Service) generateUncachedEmbeddings(ctx context.Context, texts []string
 	return results, totalUsage, nil
 }
 
-// cacheResults stores embedding results in cache
-func (s *Service) cacheResults(ctx context.Context, results []EmbeddingResult) {
-	if !s.config.CacheEnabled || s.cache == nil {
+// cacheResults stores embedding results in both cache tiers; Redis writes
+// are sent as a single pipeline
+func (s *Service) cacheResults(ctx context.Context, keys []string, results []EmbeddingResult) {
+	if !s.cachingEnabled() || len(results) == 0 {
 		return
 	}
 
-	ttl := time.Duration(s.config.CacheTTLMinutes) * time.Minute
+	if s.local != nil {
+		for i, result := range results {
+			s.local.set(keys[i], result)
+		}
+	}
 
-	for _, result := range results {
-		cacheKey := s.getCacheKey(result.Text)
+	if s.cache == nil {
+		return
+	}
 
+	ttl := time.Duration(s.config.CacheTTLMinutes) * time.Minute
+
+	pipe := s.cache.Pipeline()
+	for i, result := range results {
 		data, err := json.Marshal(result)
 		if err != nil {
 			s.logger.WithError(err).Warn("Failed to marshal embedding result for cache")
 			continue
 		}
+		pipe.Set(ctx, keys[i], data, ttl)
+	}
 
-		if err := s.cache.Set(ctx, cacheKey, data, ttl).Err(); err != nil {
-			s.logger.WithError(err).Warn("Failed to cache embedding result")
-		}
+	if _, err := pipe.Exec(ctx); err != nil {
+		metrics.EmbeddingCacheErrors.WithLabelValues(tierRedis, "set").Inc()
+		s.logger.WithError(err).Warn("Failed to cache embedding results")
 	}
 }
 
-// mergeResults merges cached and generated results in original order
-func (s *Service) mergeResults(cachedResults []EmbeddingResult, generatedResults []EmbeddingResult, uncachedIndices []int) []EmbeddingResult {
-	totalCount := len(cachedResults) + len(generatedResults)
-	results := make([]EmbeddingResult, totalCount)
-
-	cachedIdx := 0
-	generatedIdx := 0
-
-	// Fill in the results array in the original order
-	for i := 0; i < totalCount; i++ {
-		// Check if this index was uncached
-		isUncached := false
-		for _, uncachedIdx := range uncachedIndices {
-			if uncachedIdx == i {
-				isUncached = true
-				break
-			}
-		}
+// getCacheKey generates a cache key for an already preprocessed text. The key
+// covers the model, its dimension and the preprocessing pipeline fingerprint
+// so that a configuration change never serves vectors produced under the old
+// one.
+func (s *Service) getCacheKey(fingerprint, text string) string {
+	model := s.provider.GetDefaultModel()
 
-		if isUncached {
-			results[i] = generatedResults[generatedIdx]
-			generatedIdx++
-		} else {
-			results[i] = cachedResults[cachedIdx]
-			cachedIdx++
-		}
-	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%s\x00", model, s.provider.GetEmbeddingDimension(model), fingerprint)
+	h.Write([]byte(text))
 
-	return results
+	return cacheKeyPrefix + hex.EncodeToString(h.Sum(nil))
 }
 
-// getCacheKey generates a cache key for a text
-func (s *Service) getCacheKey(text string) string {
-	model := s.provider.GetDefaultModel()
-	hash := md5.Sum([]byte(fmt.Sprintf("%s:%s", model, text)))
-	return fmt.Sprintf("embedding:%x", hash)
+// Model returns the embedding model used by the provider
+func (s *Service) Model() string {
+	return s.provider.GetDefaultModel()
 }
 
-// GetCacheStats returns cache statistics
+// GetCacheStats returns per-tier hit/miss counts and local tier occupancy
 func (s *Service) GetCacheStats(ctx context.Context) (map[string]interface{}, error) {
-	if !s.config.CacheEnabled || s.cache == nil {
+	if !s.cachingEnabled() {
 		return map[string]interface{}{
 			"cache_enabled": false,
 		}, nil
 	}
 
-	info := s.cache.Info(ctx, "memory")
-	result, err := info.Result()
-	if err != nil {
-		return nil, fmt.Errorf("getting cache stats: %w", err)
+	stats := map[string]interface{}{
+		"cache_enabled": true,
+		"tiers":         s.stats.snapshot(),
 	}
+	if s.local != nil {
+		stats["local_entries"] = s.local.len()
+		stats["local_capacity"] = s.config.LocalCacheSize
+	}
+	stats["redis_enabled"] = s.cache != nil
 
-	return map[string]interface{}{
-		"cache_enabled": true,
-		"cache_info":    result,
-	}, nil
+	return stats, nil
 }
 
-// ClearCache clears all embedding cache entries
+// ClearCache clears all embedding cache entries from both tiers. Redis keys
+// are found with SCAN so large keyspaces do not block the server.
 func (s *Service) ClearCache(ctx context.Context) error {
-	if !s.config.CacheEnabled || s.cache == nil {
+	if !s.cachingEnabled() {
+		return nil
+	}
+
+	if s.local != nil {
+		s.local.clear()
+	}
+
+	if s.cache == nil {
 		return nil
 	}
 
-	keys := s.cache.Keys(ctx, "embedding:*")
-	if keys.Err() != nil {
-		return fmt.Errorf("getting cache keys: %w", keys.Err())
+	var cursor uint64
+	for {
+		keys, next, err := s.cache.Scan(ctx, cursor, cacheKeyPrefix+"*", clearCacheScanCount).Result()
+		if err != nil {
+			return fmt.Errorf("scanning cache keys: %w", err)
+		}
+
+		if len(keys) > 0 {
+			if err := s.cache.Del(ctx, keys...).Err(); err != nil {
+				return fmt.Errorf("deleting cache keys: %w", err)
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// EvictTexts drops the cached embeddings of texts from both tiers,
+// returning how many entries were removed. Texts are preprocessed as for
+// GenerateEmbeddings, so the same keys are found; entries in the local tier
+// of other replicas expire on their own.
+func (s *Service) EvictTexts(ctx context.Context, texts []string) (int64, error) {
+	if !s.cachingEnabled() || len(texts) == 0 {
+		return 0, nil
+	}
+
+	pipeline := s.pipeline()
+	fingerprint := pipeline.Fingerprint()
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = s.getCacheKey(fingerprint, pipeline.Process(text).Text)
+	}
+
+	var evicted int64
+	if s.local != nil {
+		for _, key := range keys {
+			if s.local.delete(key) {
+				evicted++
+			}
+		}
+	}
+
+	if s.cache == nil {
+		return evicted, nil
+	}
+
+	deleted, err := s.cache.Del(ctx, keys...).Result()
+	if err != nil {
+		return evicted, fmt.Errorf("deleting cache keys: %w", err)
 	}
+	return evicted + deleted, nil
+}
+
+// cacheStats counts lookups per cache tier for GetCacheStats
+type cacheStats struct {
+	localHits   atomic.Int64
+	localMisses atomic.Int64
+	redisHits   atomic.Int64
+	redisMisses atomic.Int64
+}
 
-	if len(keys.Val()) > 0 {
-		return s.cache.Del(ctx, keys.Val()...).Err()
+func (c *cacheStats) record(tier string, hit bool) {
+	switch {
+	case tier == tierLocal && hit:
+		c.localHits.Add(1)
+	case tier == tierLocal:
+		c.localMisses.Add(1)
+	case hit:
+		c.redisHits.Add(1)
+	default:
+		c.redisMisses.Add(1)
 	}
+}
 
-	return nil
+func (c *cacheStats) snapshot() map[string]map[string]int64 {
+	return map[string]map[string]int64{
+		tierLocal: {"hits": c.localHits.Load(), "misses": c.localMisses.Load()},
+		tierRedis: {"hits": c.redisHits.Load(), "misses": c.redisMisses.Load()},
+	}
 }
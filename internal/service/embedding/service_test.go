@@ -2,6 +2,9 @@ package embedding
 
 import (
 	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -160,6 +163,7 @@ func TestService_GenerateEmbeddings_Batch(t *testing.T) {
 
 func TestService_PreprocessText(t *testing.T) {
 	provider := &MockEmbeddingProvider{}
+	provider.On("GetDefaultModel").Return("test-model")
 	logger, _ := logger.NewLogger(&configs.LoggingConfig{Level: "info"})
 
 	config := Config{
@@ -191,7 +195,12 @@ func TestService_PreprocessText(t *testing.T) {
 		{
 			name:     "truncate_long_text",
 			input:    "This is a very long text that should be truncated because it exceeds the maximum length",
-			expected: "this is a very long text that should be trunca",
+			expected: "this is a very long text that should be truncated ",
+		},
+		{
+			name:     "truncate_multibyte_text",
+			input:    strings.Repeat("记忆", 30),
+			expected: strings.Repeat("记忆", 25),
 		},
 		{
 			name:     "empty_text",
@@ -208,6 +217,20 @@ func TestService_PreprocessText(t *testing.T) {
 	}
 }
 
+func TestService_PreprocessText_ModelTokenLimit(t *testing.T) {
+	provider := &MockEmbeddingProvider{}
+	provider.On("GetDefaultModel").Return("all-minilm")
+	logger, _ := logger.NewLogger(&configs.LoggingConfig{Level: "info"})
+
+	service := NewService(provider, nil, logger, Config{})
+
+	input := strings.Repeat("word ", 1000)
+	result := service.preprocessText(input)
+
+	tokenizer := llm.ApproximateTokenizer{}
+	assert.Equal(t, llm.MaxInputTokens("all-minilm"), tokenizer.CountTokens(result))
+}
+
 func TestService_WithCache(t *testing.T) {
 	// Skip if Redis is not available
 	redisClient, err := database.NewRedisClientWithOptions(&redis.Options{
@@ -234,6 +257,7 @@ func TestService_WithCache(t *testing.T) {
 	expectedEmbedding := []float32{0.1, 0.2, 0.3}
 
 	provider.On("GetDefaultModel").Return("test-model")
+	provider.On("GetEmbeddingDimension", "test-model").Return(3)
 	provider.On("GenerateEmbeddings", mock.Anything, mock.Anything).Return(&llm.EmbeddingResponse{
 		Embeddings: [][]float32{expectedEmbedding},
 		Model:      "test-model",
@@ -278,6 +302,7 @@ func TestService_ClearCache(t *testing.T) {
 	// Add some data to cache
 	expectedEmbedding := []float32{0.1, 0.2, 0.3}
 	provider.On("GetDefaultModel").Return("test-model")
+	provider.On("GetEmbeddingDimension", "test-model").Return(3)
 	provider.On("GenerateEmbeddings", mock.Anything, mock.Anything).Return(&llm.EmbeddingResponse{
 		Embeddings: [][]float32{expectedEmbedding},
 		Model:      "test-model",
@@ -301,6 +326,197 @@ func TestService_ClearCache(t *testing.T) {
 	assert.False(t, result.Cached) // Should not be cached after clearing
 }
 
+func TestService_LocalCache_PreservesOrder(t *testing.T) {
+	provider := &MockEmbeddingProvider{}
+	logger, _ := logger.NewLogger(&configs.LoggingConfig{Level: "info"})
+
+	service := NewService(provider, nil, logger, Config{CacheEnabled: true})
+
+	provider.On("GetDefaultModel").Return("test-model")
+	provider.On("GetEmbeddingDimension", "test-model").Return(1)
+	provider.On("GenerateEmbeddings", mock.Anything, mock.MatchedBy(func(req *llm.EmbeddingRequest) bool {
+		return len(req.Input) == 2 && req.Input[0] == "a" && req.Input[1] == "b"
+	})).Return(&llm.EmbeddingResponse{
+		Embeddings: [][]float32{{1}, {2}},
+		Model:      "test-model",
+	}, nil).Once()
+	provider.On("GenerateEmbeddings", mock.Anything, mock.MatchedBy(func(req *llm.EmbeddingRequest) bool {
+		return len(req.Input) == 1 && req.Input[0] == "c"
+	})).Return(&llm.EmbeddingResponse{
+		Embeddings: [][]float32{{3}},
+		Model:      "test-model",
+	}, nil).Once()
+
+	_, err := service.GenerateEmbeddings(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+
+	result, err := service.GenerateEmbeddings(context.Background(), []string{"b", "c", "a"})
+	require.NoError(t, err)
+	require.Len(t, result.Results, 3)
+
+	assert.Equal(t, []float32{2}, result.Results[0].Embedding)
+	assert.True(t, result.Results[0].Cached)
+	assert.Equal(t, []float32{3}, result.Results[1].Embedding)
+	assert.False(t, result.Results[1].Cached)
+	assert.Equal(t, []float32{1}, result.Results[2].Embedding)
+	assert.True(t, result.Results[2].Cached)
+
+	provider.AssertExpectations(t)
+}
+
+func TestService_EvictTexts(t *testing.T) {
+	provider := &MockEmbeddingProvider{}
+	logger, _ := logger.NewLogger(&configs.LoggingConfig{Level: "info"})
+
+	service := NewService(provider, nil, logger, Config{
+		CacheEnabled:        true,
+		PreprocessingConfig: PreprocessingConfig{NormalizeWhitespace: true},
+	})
+
+	provider.On("GetDefaultModel").Return("test-model")
+	provider.On("GetEmbeddingDimension", "test-model").Return(1)
+	provider.On("GenerateEmbeddings", mock.Anything, mock.Anything).Return(&llm.EmbeddingResponse{
+		Embeddings: [][]float32{{1}, {2}},
+		Model:      "test-model",
+	}, nil).Once()
+
+	_, err := service.GenerateEmbeddings(context.Background(), []string{"my secret", "shared text"})
+	require.NoError(t, err)
+
+	// Texts are preprocessed the same way, so the stored entry is found
+	evicted, err := service.EvictTexts(context.Background(), []string{"my   secret", "never cached"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), evicted)
+
+	provider.On("GenerateEmbeddings", mock.Anything, mock.Anything).Return(&llm.EmbeddingResponse{
+		Embeddings: [][]float32{{3}},
+		Model:      "test-model",
+	}, nil).Once()
+
+	result, err := service.GenerateEmbeddings(context.Background(), []string{"my secret", "shared text"})
+	require.NoError(t, err)
+	assert.False(t, result.Results[0].Cached, "evicted text is embedded again")
+	assert.True(t, result.Results[1].Cached)
+
+	provider.AssertExpectations(t)
+}
+
+func TestService_CacheKey_IncludesSettings(t *testing.T) {
+	logger, _ := logger.NewLogger(&configs.LoggingConfig{Level: "info"})
+
+	newProvider := func(model string, dim int) *MockEmbeddingProvider {
+		provider := &MockEmbeddingProvider{}
+		provider.On("GetDefaultModel").Return(model)
+		provider.On("GetEmbeddingDimension", model).Return(dim)
+		return provider
+	}
+
+	cacheKey := func(provider *MockEmbeddingProvider, config Config) string {
+		service := NewService(provider, nil, logger, config)
+		return service.getCacheKey(service.pipeline().Fingerprint(), "text")
+	}
+
+	base := cacheKey(newProvider("model-a", 3), Config{})
+	assert.Equal(t, base, cacheKey(newProvider("model-a", 3), Config{}))
+	assert.True(t, strings.HasPrefix(base, cacheKeyPrefix))
+
+	assert.NotEqual(t, base, cacheKey(newProvider("model-b", 3), Config{}))
+	assert.NotEqual(t, base, cacheKey(newProvider("model-a", 4), Config{}))
+	assert.NotEqual(t, base, cacheKey(newProvider("model-a", 3), Config{
+		PreprocessingConfig: PreprocessingConfig{ToLowercase: true},
+	}))
+	assert.NotEqual(t, base, cacheKey(newProvider("model-a", 3), Config{
+		Pipelines: map[string]PipelineConfig{"model-a": {Steps: []string{"nfkc"}}},
+	}))
+}
+
+// blockingProvider counts provider calls and holds each one until release is closed
+type blockingProvider struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingProvider) GenerateEmbeddings(ctx context.Context, req *llm.EmbeddingRequest) (*llm.EmbeddingResponse, error) {
+	if p.calls.Add(1) == 1 {
+		close(p.started)
+	}
+	<-p.release
+
+	embeddings := make([][]float32, len(req.Input))
+	for i := range req.Input {
+		embeddings[i] = []float32{0.1, 0.2}
+	}
+	return &llm.EmbeddingResponse{Embeddings: embeddings, Model: "test-model"}, nil
+}
+
+func (p *blockingProvider) GetEmbeddingDimension(model string) int { return 2 }
+func (p *blockingProvider) GetDefaultModel() string                { return "test-model" }
+
+func TestService_CoalescesConcurrentRequests(t *testing.T) {
+	logger, _ := logger.NewLogger(&configs.LoggingConfig{Level: "info"})
+
+	provider := &blockingProvider{started: make(chan struct{}), release: make(chan struct{})}
+	service := NewService(provider, nil, logger, Config{CacheEnabled: true})
+
+	const callers = 8
+	results := make([]*EmbeddingResult, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], errs[0] = service.GenerateEmbedding(context.Background(), "same text")
+	}()
+
+	// Let the first caller claim the key before the rest arrive
+	<-provider.started
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = service.GenerateEmbedding(context.Background(), "same text")
+		}(i)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(provider.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), provider.calls.Load())
+	for i := 0; i < callers; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, []float32{0.1, 0.2}, results[i].Embedding)
+	}
+}
+
+func TestService_CoalescesDuplicatesWithinBatch(t *testing.T) {
+	provider := &MockEmbeddingProvider{}
+	logger, _ := logger.NewLogger(&configs.LoggingConfig{Level: "info"})
+
+	service := NewService(provider, nil, logger, Config{CacheEnabled: true})
+
+	provider.On("GetDefaultModel").Return("test-model")
+	provider.On("GetEmbeddingDimension", "test-model").Return(1)
+	provider.On("GenerateEmbeddings", mock.Anything, mock.MatchedBy(func(req *llm.EmbeddingRequest) bool {
+		return len(req.Input) == 2
+	})).Return(&llm.EmbeddingResponse{
+		Embeddings: [][]float32{{1}, {2}},
+		Model:      "test-model",
+	}, nil).Once()
+
+	result, err := service.GenerateEmbeddings(context.Background(), []string{"a", "b", "a"})
+	require.NoError(t, err)
+	require.Len(t, result.Results, 3)
+
+	assert.Equal(t, []float32{1}, result.Results[0].Embedding)
+	assert.Equal(t, []float32{2}, result.Results[1].Embedding)
+	assert.Equal(t, []float32{1}, result.Results[2].Embedding)
+
+	provider.AssertExpectations(t)
+}
+
 func BenchmarkService_GenerateEmbedding(b *testing.B) {
 	provider := &MockEmbeddingProvider{}
 	logger, _ := logger.NewLogger(&configs.LoggingConfig{Level: "error"}) // Reduce log noise
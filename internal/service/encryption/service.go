@@ -0,0 +1,94 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/encryption"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+	pkgencryption "mem_bank/pkg/encryption"
+	"mem_bank/pkg/logger"
+)
+
+// reencryptBatchSize is how many memories are re-encrypted per batch
+const reencryptBatchSize = 100
+
+// Service implements encryption.Service for admin and system callers
+type Service struct {
+	keys     *pkgencryption.KeyRing
+	memories encryption.MemoryReencrypter
+	userRepo user.Repository
+	logger   logger.Logger
+}
+
+// NewService creates a new encryption key service
+func NewService(keys *pkgencryption.KeyRing, memories encryption.MemoryReencrypter, userRepo user.Repository, logger logger.Logger) *Service {
+	return &Service{
+		keys:     keys,
+		memories: memories,
+		userRepo: userRepo,
+		logger:   logger,
+	}
+}
+
+func (s *Service) RotateUserKey(ctx context.Context, userID user.ID) (*encryption.KeyRotation, error) {
+	if err := authorizePrivileged(ctx); err != nil {
+		return nil, err
+	}
+	if userID.IsZero() {
+		return nil, user.ErrInvalidID
+	}
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	key, err := s.keys.Rotate(ctx, userID.String())
+	if err != nil {
+		return nil, fmt.Errorf("rotating data key: %w", err)
+	}
+
+	// Memories the user wrote into spaces they have since left are theirs
+	// to re-encrypt too, so row-level security is bypassed
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+	rotation := &encryption.KeyRotation{UserID: userID, Version: key.Version}
+	for {
+		n, err := s.memories.ReencryptMemories(ctx, userID, reencryptBatchSize)
+		rotation.Reencrypted += n
+		if err != nil {
+			return rotation, fmt.Errorf("re-encrypting memories: %w", err)
+		}
+		if n < reencryptBatchSize {
+			break
+		}
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"user_id":     userID.String(),
+		"version":     rotation.Version,
+		"reencrypted": rotation.Reencrypted,
+	}).Info("Rotated data key")
+	return rotation, nil
+}
+
+func (s *Service) RewrapKeys(ctx context.Context) (int, error) {
+	if err := authorizePrivileged(ctx); err != nil {
+		return 0, err
+	}
+
+	count, err := s.keys.Rewrap(ctx)
+	if err != nil {
+		return count, err
+	}
+	s.logger.WithField("keys", count).Info("Rewrapped data keys")
+	return count, nil
+}
+
+func authorizePrivileged(ctx context.Context) error {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok || !p.IsPrivileged() {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
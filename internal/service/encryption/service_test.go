@@ -0,0 +1,308 @@
+package encryption
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+	pkgencryption "mem_bank/pkg/encryption"
+	"mem_bank/pkg/logger"
+)
+
+// Mock data key store
+type mockKeyStore struct {
+	mock.Mock
+}
+
+func (m *mockKeyStore) List(ctx context.Context, userID string) ([]*pkgencryption.StoredKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pkgencryption.StoredKey), args.Error(1)
+}
+
+func (m *mockKeyStore) Add(ctx context.Context, key *pkgencryption.StoredKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *mockKeyStore) ListNotWrappedBy(ctx context.Context, masterKeyID string, limit int) ([]*pkgencryption.StoredKey, error) {
+	args := m.Called(ctx, masterKeyID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*pkgencryption.StoredKey), args.Error(1)
+}
+
+func (m *mockKeyStore) Rewrap(ctx context.Context, key *pkgencryption.StoredKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *mockKeyStore) Delete(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// Mock memory re-encrypter
+type mockReencrypter struct {
+	mock.Mock
+}
+
+func (m *mockReencrypter) ReencryptMemories(ctx context.Context, userID user.ID, limit int) (int, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Int(0), args.Error(1)
+}
+
+// Mock user repository
+type mockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id user.ID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+func adminContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: user.ID(uuid.New()), Role: auth.RoleAdmin})
+}
+
+func newTestKMS(t *testing.T) *pkgencryption.LocalKMS {
+	t.Helper()
+	kms, err := pkgencryption.NewLocalKMS(filepath.Join(t.TempDir(), "master.json"))
+	require.NoError(t, err)
+	return kms
+}
+
+// wrappedKey returns a stored key as the key ring writes it, wrapped by the
+// current master key of kms
+func wrappedKey(t *testing.T, kms *pkgencryption.LocalKMS, userID user.ID, version int) *pkgencryption.StoredKey {
+	t.Helper()
+	masterKeyID, wrapped, err := kms.Encrypt(context.Background(), make([]byte, 64))
+	require.NoError(t, err)
+	return &pkgencryption.StoredKey{UserID: userID.String(), Version: version, MasterKeyID: masterKeyID, WrappedKey: wrapped}
+}
+
+func TestService_RotateUserKey(t *testing.T) {
+	userID := user.ID(uuid.New())
+	missing := user.ID(uuid.New())
+	dbErr := errors.New("database error")
+
+	// Memories in spaces the user left are re-encrypted too
+	bypass := mock.MatchedBy(func(ctx context.Context) bool {
+		tenant, _ := database.TenantFromContext(ctx)
+		return tenant.Bypass
+	})
+	version := func(v int) interface{} {
+		return mock.MatchedBy(func(key *pkgencryption.StoredKey) bool {
+			return key.UserID == userID.String() && key.Version == v
+		})
+	}
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		userID          user.ID
+		setupMocks      func(*mockKeyStore, *mockReencrypter, *mockUserRepository, *mockLogger, *pkgencryption.LocalKMS)
+		wantVersion     int
+		wantReencrypted int
+		wantErr         error
+	}{
+		{
+			name:   "first key re-encrypts in batches",
+			ctx:    adminContext(),
+			userID: userID,
+			setupMocks: func(s *mockKeyStore, r *mockReencrypter, u *mockUserRepository, l *mockLogger, kms *pkgencryption.LocalKMS) {
+				u.On("FindByID", mock.Anything, userID).Return(&user.User{ID: userID}, nil)
+				s.On("List", mock.Anything, userID.String()).Return([]*pkgencryption.StoredKey{}, nil)
+				s.On("Add", mock.Anything, version(1)).Return(nil)
+				// Batches continue until one comes back short
+				r.On("ReencryptMemories", bypass, userID, reencryptBatchSize).Return(100, nil).Twice()
+				r.On("ReencryptMemories", bypass, userID, reencryptBatchSize).Return(50, nil).Once()
+				l.On("Info", "Created data key").Once()
+				l.On("Info", "Rotated data key").Once()
+			},
+			wantVersion:     1,
+			wantReencrypted: 250,
+		},
+		{
+			name:   "successor key",
+			ctx:    adminContext(),
+			userID: userID,
+			setupMocks: func(s *mockKeyStore, r *mockReencrypter, u *mockUserRepository, l *mockLogger, kms *pkgencryption.LocalKMS) {
+				u.On("FindByID", mock.Anything, userID).Return(&user.User{ID: userID}, nil)
+				s.On("List", mock.Anything, userID.String()).Return([]*pkgencryption.StoredKey{wrappedKey(t, kms, userID, 1)}, nil)
+				s.On("Add", mock.Anything, version(2)).Return(nil)
+				r.On("ReencryptMemories", bypass, userID, reencryptBatchSize).Return(0, nil).Once()
+				l.On("Info", "Created data key").Once()
+				l.On("Info", "Rotated data key").Once()
+			},
+			wantVersion: 2,
+		},
+		{
+			name:   "re-encryption failure",
+			ctx:    adminContext(),
+			userID: userID,
+			setupMocks: func(s *mockKeyStore, r *mockReencrypter, u *mockUserRepository, l *mockLogger, kms *pkgencryption.LocalKMS) {
+				u.On("FindByID", mock.Anything, userID).Return(&user.User{ID: userID}, nil)
+				s.On("List", mock.Anything, userID.String()).Return([]*pkgencryption.StoredKey{}, nil)
+				s.On("Add", mock.Anything, version(1)).Return(nil)
+				r.On("ReencryptMemories", bypass, userID, reencryptBatchSize).Return(100, nil).Once()
+				r.On("ReencryptMemories", bypass, userID, reencryptBatchSize).Return(0, dbErr).Once()
+				l.On("Info", "Created data key").Once()
+			},
+			wantErr: dbErr,
+		},
+		{
+			name:    "user is denied",
+			ctx:     auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: auth.RoleUser}),
+			userID:  userID,
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name:    "zero user ID",
+			ctx:     adminContext(),
+			userID:  user.ID{},
+			wantErr: user.ErrInvalidID,
+		},
+		{
+			name:   "unknown user gets no key",
+			ctx:    adminContext(),
+			userID: missing,
+			setupMocks: func(s *mockKeyStore, r *mockReencrypter, u *mockUserRepository, l *mockLogger, kms *pkgencryption.LocalKMS) {
+				u.On("FindByID", mock.Anything, missing).Return(nil, user.ErrNotFound)
+			},
+			wantErr: user.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kms := newTestKMS(t)
+			store := &mockKeyStore{}
+			memories := &mockReencrypter{}
+			userRepo := &mockUserRepository{}
+			log := &mockLogger{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(store, memories, userRepo, log, kms)
+			}
+			service := NewService(pkgencryption.NewKeyRing(kms, store, log), memories, userRepo, log)
+
+			rotation, err := service.RotateUserKey(tt.ctx, tt.userID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.userID, rotation.UserID)
+				assert.Equal(t, tt.wantVersion, rotation.Version)
+				assert.Equal(t, tt.wantReencrypted, rotation.Reencrypted)
+			}
+
+			store.AssertExpectations(t)
+			memories.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_RewrapKeys(t *testing.T) {
+	userID := user.ID(uuid.New())
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		setupMocks func(*mockKeyStore, *mockLogger, *pkgencryption.LocalKMS)
+		wantCount  int
+		wantErr    error
+	}{
+		{
+			name: "keys already wrapped by the current master key",
+			ctx:  adminContext(),
+			setupMocks: func(s *mockKeyStore, l *mockLogger, kms *pkgencryption.LocalKMS) {
+				current, err := kms.CurrentKeyID(context.Background())
+				require.NoError(t, err)
+				s.On("ListNotWrappedBy", mock.Anything, current, mock.Anything).Return([]*pkgencryption.StoredKey{}, nil)
+				l.On("Info", "Rewrapped data keys").Once()
+			},
+		},
+		{
+			name: "keys rewrapped after the master key rotated",
+			ctx:  adminContext(),
+			setupMocks: func(s *mockKeyStore, l *mockLogger, kms *pkgencryption.LocalKMS) {
+				old := wrappedKey(t, kms, userID, 1)
+				current, err := kms.Rotate()
+				require.NoError(t, err)
+				s.On("ListNotWrappedBy", mock.Anything, current, mock.Anything).Return([]*pkgencryption.StoredKey{old}, nil).Once()
+				s.On("ListNotWrappedBy", mock.Anything, current, mock.Anything).Return([]*pkgencryption.StoredKey{}, nil).Once()
+				s.On("Rewrap", mock.Anything, mock.MatchedBy(func(key *pkgencryption.StoredKey) bool {
+					return key.UserID == userID.String() && key.Version == 1 && key.MasterKeyID == current
+				})).Return(nil)
+				l.On("Info", "Rewrapped data keys").Once()
+			},
+			wantCount: 1,
+		},
+		{
+			name:    "anonymous caller is denied",
+			ctx:     context.Background(),
+			wantErr: auth.ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kms := newTestKMS(t)
+			store := &mockKeyStore{}
+			log := &mockLogger{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(store, log, kms)
+			}
+			service := NewService(pkgencryption.NewKeyRing(kms, store, log), &mockReencrypter{}, &mockUserRepository{}, log)
+
+			count, err := service.RewrapKeys(tt.ctx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantCount, count)
+			}
+
+			store.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package erasure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/erasure"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/queue"
+	"mem_bank/pkg/database"
+	"mem_bank/pkg/logger"
+)
+
+// listBatchSize is how many memories are listed per page when building the subject
+const listBatchSize = 500
+
+// Signer signs erasure receipts
+type Signer interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
+// ReceiptAudience is the audience of erasure receipts. Receipts are signed
+// with the access token keys, and the audience keeps them from being
+// accepted as access tokens.
+const ReceiptAudience = "erasure-receipt"
+
+// ReceiptClaims are the claims of an erasure receipt. The token ID is the
+// erasure ID and the subject is the erased user.
+type ReceiptClaims struct {
+	RequestedBy string                `json:"requested_by"`
+	RequestedAt *jwt.NumericDate      `json:"requested_at"`
+	Stores      []erasure.StoreResult `json:"stores"`
+	jwt.RegisteredClaims
+}
+
+// Service implements erasure.Service
+type Service struct {
+	repo       erasure.Repository
+	memories   erasure.MemoryLister
+	stores     []erasure.Store
+	userRepo   user.Repository
+	jobQueue   queue.Producer
+	jobFactory *queue.JobFactory
+	signer     Signer
+	logger     logger.Logger
+	now        func() time.Time
+}
+
+// NewService creates a new erasure service. Stores are wiped in the order
+// given; the store that deletes the user's rows must come last, since the
+// others are found through them.
+func NewService(
+	repo erasure.Repository,
+	memories erasure.MemoryLister,
+	stores []erasure.Store,
+	userRepo user.Repository,
+	jobQueue queue.Producer,
+	signer Signer,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		repo:       repo,
+		memories:   memories,
+		stores:     stores,
+		userRepo:   userRepo,
+		jobQueue:   jobQueue,
+		jobFactory: queue.NewJobFactory(),
+		signer:     signer,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+func (s *Service) RequestErasure(ctx context.Context, userID user.ID) (*erasure.Erasure, error) {
+	if userID.IsZero() {
+		return nil, user.ErrInvalidID
+	}
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	p, _ := auth.PrincipalFromContext(ctx)
+
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The account stops working right away; the data goes when the job runs
+	if u.IsActive {
+		u.Deactivate()
+		if err := s.userRepo.Update(ctx, u); err != nil {
+			return nil, fmt.Errorf("deactivating user: %w", err)
+		}
+	}
+
+	e := &erasure.Erasure{
+		ID:          erasure.NewID(),
+		UserID:      userID,
+		RequestedBy: p.UserID,
+		Status:      erasure.StatusPending,
+		Stores:      []erasure.StoreResult{},
+		RequestedAt: s.now(),
+	}
+	if err := s.repo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+
+	if err := s.jobQueue.Enqueue(ctx, s.jobFactory.CreateEraseUserJob(e)); err != nil {
+		return nil, fmt.Errorf("queuing erasure: %w", err)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"erasure_id":   e.ID.String(),
+		"user_id":      userID.String(),
+		"requested_by": p.UserID.String(),
+	}).Info("User erasure requested")
+	return e, nil
+}
+
+func (s *Service) GetErasure(ctx context.Context, id erasure.ID) (*erasure.Erasure, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrPermissionDenied
+	}
+	if id.IsZero() {
+		return nil, erasure.ErrInvalidID
+	}
+
+	e, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Whoever asked for the erasure keeps access to its receipt
+	if !p.IsPrivileged() && p.UserID != e.UserID && p.UserID != e.RequestedBy {
+		return nil, auth.ErrPermissionDenied
+	}
+	return e, nil
+}
+
+func (s *Service) Erase(ctx context.Context, id erasure.ID) (*erasure.Erasure, error) {
+	// The user's data spans spaces owned by others
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+
+	e, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status == erasure.StatusCompleted {
+		return e, nil
+	}
+
+	subject, err := s.subject(ctx, e.UserID)
+	if err != nil {
+		return nil, s.fail(ctx, e, fmt.Errorf("listing memories: %w", err))
+	}
+
+	for _, store := range s.stores {
+		removed, err := store.EraseUser(ctx, subject)
+		if err != nil {
+			return nil, s.fail(ctx, e, fmt.Errorf("erasing %s: %w", store.Name(), err))
+		}
+		e.AddResult(store.Name(), removed)
+		if err := s.repo.Update(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	completedAt := s.now()
+	e.Status = erasure.StatusCompleted
+	e.CompletedAt = &completedAt
+	e.LastError = ""
+	receipt, err := s.signer.Sign(&ReceiptClaims{
+		RequestedBy: e.RequestedBy.String(),
+		RequestedAt: jwt.NewNumericDate(e.RequestedAt),
+		Stores:      e.Stores,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       e.ID.String(),
+			Subject:  e.UserID.String(),
+			Audience: jwt.ClaimStrings{ReceiptAudience},
+			IssuedAt: jwt.NewNumericDate(completedAt),
+		},
+	})
+	if err != nil {
+		return nil, s.fail(ctx, e, fmt.Errorf("signing receipt: %w", err))
+	}
+	e.Receipt = receipt
+
+	if err := s.repo.Update(ctx, e); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"erasure_id": e.ID.String(),
+		"user_id":    e.UserID.String(),
+		"stores":     e.Stores,
+	}).Info("User erased")
+	return e, nil
+}
+
+// subject collects what the stores need to find the user's data
+func (s *Service) subject(ctx context.Context, userID user.ID) (*erasure.Subject, error) {
+	subject := &erasure.Subject{UserID: userID}
+
+	var cursor memory.ID
+	for {
+		page, err := s.memories.ListMemoryContents(ctx, userID, listBatchSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		subject.Memories = append(subject.Memories, page...)
+		if len(page) < listBatchSize {
+			return subject, nil
+		}
+		cursor = page[len(page)-1].ID
+	}
+}
+
+// fail records err on the erasure so it shows while the job is retried
+func (s *Service) fail(ctx context.Context, e *erasure.Erasure, err error) error {
+	e.Status = erasure.StatusPending
+	e.CompletedAt = nil
+	e.LastError = err.Error()
+	if updateErr := s.repo.Update(ctx, e); updateErr != nil {
+		s.logger.WithError(updateErr).WithField("erasure_id", e.ID.String()).Warn("Failed to record erasure error")
+	}
+
+	s.logger.WithError(err).WithFields(map[string]interface{}{
+		"erasure_id": e.ID.String(),
+		"user_id":    e.UserID.String(),
+	}).Error("User erasure failed")
+	return err
+}
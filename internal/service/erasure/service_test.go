@@ -0,0 +1,411 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/erasure"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/queue"
+	pkgauth "mem_bank/pkg/auth"
+	"mem_bank/pkg/database"
+	"mem_bank/pkg/logger"
+)
+
+const testSecret = "test-secret"
+
+// Mock erasure repository
+type mockErasureRepository struct {
+	mock.Mock
+}
+
+func (m *mockErasureRepository) Create(ctx context.Context, e *erasure.Erasure) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *mockErasureRepository) FindByID(ctx context.Context, id erasure.ID) (*erasure.Erasure, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*erasure.Erasure), args.Error(1)
+}
+
+func (m *mockErasureRepository) Update(ctx context.Context, e *erasure.Erasure) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+// Mock memory lister
+type mockMemoryLister struct {
+	mock.Mock
+}
+
+func (m *mockMemoryLister) ListMemoryContents(ctx context.Context, userID user.ID, limit int, cursor memory.ID) ([]erasure.MemoryContent, error) {
+	args := m.Called(ctx, userID, limit, cursor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]erasure.MemoryContent), args.Error(1)
+}
+
+// Mock store
+type mockStore struct {
+	mock.Mock
+	name string
+}
+
+func (m *mockStore) Name() string { return m.name }
+
+func (m *mockStore) EraseUser(ctx context.Context, subject *erasure.Subject) (int64, error) {
+	args := m.Called(ctx, subject)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Mock user repository
+type mockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id user.ID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+// Mock job producer; only the methods under test are mocked
+type mockProducer struct {
+	queue.Producer
+	mock.Mock
+}
+
+func (m *mockProducer) Enqueue(ctx context.Context, job *queue.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+// Mock job purger
+type mockPurger struct {
+	mock.Mock
+}
+
+func (m *mockPurger) PurgeJobs(ctx context.Context, match func(*queue.Job) bool) (int64, error) {
+	args := m.Called(ctx, match)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+func principalContext(userID user.ID, role string) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: role})
+}
+
+var testSigner = pkgauth.NewJWTService(testSecret, "mem_bank", time.Hour)
+
+func newTestService(repo *mockErasureRepository, lister *mockMemoryLister, stores []erasure.Store, userRepo *mockUserRepository, producer *mockProducer, log *mockLogger) *Service {
+	return NewService(repo, lister, stores, userRepo, producer, testSigner, log)
+}
+
+func TestService_RequestErasure(t *testing.T) {
+	userID := user.ID(uuid.New())
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		userID     user.ID
+		setupMocks func(*mockErasureRepository, *mockUserRepository, *mockProducer, *mockLogger)
+		wantErr    error
+	}{
+		{
+			name:   "user erases themselves",
+			ctx:    principalContext(userID, auth.RoleUser),
+			userID: userID,
+			setupMocks: func(r *mockErasureRepository, u *mockUserRepository, p *mockProducer, l *mockLogger) {
+				u.On("FindByID", mock.Anything, userID).Return(&user.User{ID: userID, IsActive: true}, nil)
+				// The account is deactivated at once
+				u.On("Update", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+					return u.ID == userID && !u.IsActive
+				})).Return(nil)
+				r.On("Create", mock.Anything, mock.MatchedBy(func(e *erasure.Erasure) bool {
+					return e.UserID == userID && e.RequestedBy == userID && e.Status == erasure.StatusPending
+				})).Return(nil)
+				p.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *queue.Job) bool {
+					return job.Type == queue.JobTypeEraseUser && queue.PriorityBand(job.Priority) == queue.PriorityBandHigh
+				})).Return(nil)
+				l.On("Info", "User erasure requested").Once()
+			},
+		},
+		{
+			name:    "other user is denied",
+			ctx:     principalContext(user.ID(uuid.New()), auth.RoleUser),
+			userID:  userID,
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name:    "zero user ID",
+			ctx:     principalContext(userID, auth.RoleUser),
+			userID:  user.ID{},
+			wantErr: user.ErrInvalidID,
+		},
+		{
+			name:   "missing user",
+			ctx:    principalContext(user.ID(uuid.New()), auth.RoleAdmin),
+			userID: userID,
+			setupMocks: func(r *mockErasureRepository, u *mockUserRepository, p *mockProducer, l *mockLogger) {
+				u.On("FindByID", mock.Anything, userID).Return(nil, user.ErrNotFound)
+			},
+			wantErr: user.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockErasureRepository{}
+			userRepo := &mockUserRepository{}
+			producer := &mockProducer{}
+			log := &mockLogger{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo, userRepo, producer, log)
+			}
+			service := newTestService(repo, &mockMemoryLister{}, nil, userRepo, producer, log)
+
+			e, err := service.RequestErasure(tt.ctx, tt.userID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, erasure.StatusPending, e.Status)
+			}
+
+			repo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			producer.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Erase(t *testing.T) {
+	userID := user.ID(uuid.New())
+	firstPage := make([]erasure.MemoryContent, listBatchSize)
+	for i := range firstPage {
+		firstPage[i] = erasure.MemoryContent{ID: memory.ID(uuid.New()), Content: "memory"}
+	}
+	lastPage := []erasure.MemoryContent{{ID: memory.ID(uuid.New()), Content: "memory"}}
+	e := &erasure.Erasure{ID: erasure.NewID(), UserID: userID, Status: erasure.StatusPending, RequestedAt: time.Now()}
+
+	// Memories in other users' spaces are erased too
+	bypass := mock.MatchedBy(func(ctx context.Context) bool {
+		tenant, _ := database.TenantFromContext(ctx)
+		return tenant.Bypass
+	})
+	fullSubject := mock.MatchedBy(func(s *erasure.Subject) bool {
+		return s.UserID == userID && len(s.Memories) == listBatchSize+1
+	})
+
+	var order []string
+	vectors := &mockStore{name: StoreVectors}
+	vectors.On("EraseUser", bypass, fullSubject).Run(func(mock.Arguments) { order = append(order, StoreVectors) }).Return(int64(7), nil).Once()
+	rows := &mockStore{name: StorePostgres}
+	rows.On("EraseUser", bypass, fullSubject).Run(func(mock.Arguments) { order = append(order, StorePostgres) }).Return(int64(listBatchSize+1), nil).Once()
+
+	lister := &mockMemoryLister{}
+	lister.On("ListMemoryContents", bypass, userID, listBatchSize, memory.ID{}).Return(firstPage, nil)
+	lister.On("ListMemoryContents", bypass, userID, listBatchSize, firstPage[listBatchSize-1].ID).Return(lastPage, nil)
+
+	repo := &mockErasureRepository{}
+	repo.On("FindByID", bypass, e.ID).Return(e, nil)
+	repo.On("Update", bypass, e).Return(nil)
+
+	log := &mockLogger{}
+	log.On("Info", "User erased").Once()
+
+	service := newTestService(repo, lister, []erasure.Store{vectors, rows}, &mockUserRepository{}, &mockProducer{}, log)
+
+	erased, err := service.Erase(context.Background(), e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{StoreVectors, StorePostgres}, order)
+	assert.Equal(t, erasure.StatusCompleted, erased.Status)
+	assert.NotNil(t, erased.CompletedAt)
+	assert.Equal(t, []erasure.StoreResult{
+		{Store: StoreVectors, Removed: 7},
+		{Store: StorePostgres, Removed: int64(listBatchSize + 1)},
+	}, erased.Stores)
+
+	var claims ReceiptClaims
+	_, err = jwt.ParseWithClaims(erased.Receipt, &claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(testSecret), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, e.ID.String(), claims.ID)
+	assert.Equal(t, userID.String(), claims.Subject)
+	assert.Equal(t, erased.Stores, claims.Stores)
+	assert.Equal(t, jwt.ClaimStrings{ReceiptAudience}, claims.Audience)
+
+	// A receipt is not an access token
+	_, err = testSigner.ValidateToken(erased.Receipt)
+	assert.ErrorIs(t, err, pkgauth.ErrInvalidToken)
+
+	// A completed erasure is not run again
+	again, err := service.Erase(context.Background(), e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, erased.Receipt, again.Receipt)
+
+	vectors.AssertExpectations(t)
+	rows.AssertExpectations(t)
+	lister.AssertExpectations(t)
+	log.AssertExpectations(t)
+}
+
+func TestService_Erase_Retry(t *testing.T) {
+	e := &erasure.Erasure{ID: erasure.NewID(), UserID: user.ID(uuid.New()), Status: erasure.StatusPending, RequestedAt: time.Now()}
+
+	vectors := &mockStore{name: StoreVectors}
+	vectors.On("EraseUser", mock.Anything, mock.Anything).Return(int64(3), nil).Once()
+	vectors.On("EraseUser", mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+	rows := &mockStore{name: StorePostgres}
+	rows.On("EraseUser", mock.Anything, mock.Anything).Return(int64(0), errors.New("store unavailable")).Once()
+	rows.On("EraseUser", mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+
+	lister := &mockMemoryLister{}
+	lister.On("ListMemoryContents", mock.Anything, e.UserID, listBatchSize, memory.ID{}).Return([]erasure.MemoryContent{}, nil)
+
+	// The repository keeps whatever the service last stored
+	repo := &mockErasureRepository{}
+	repo.On("FindByID", mock.Anything, e.ID).Return(e, nil)
+	repo.On("Update", mock.Anything, e).Return(nil)
+
+	log := &mockLogger{}
+	log.On("Error", "User erasure failed").Once()
+	log.On("Info", "User erased").Once()
+
+	service := newTestService(repo, lister, []erasure.Store{vectors, rows}, &mockUserRepository{}, &mockProducer{}, log)
+
+	_, err := service.Erase(context.Background(), e.ID)
+	require.Error(t, err)
+	assert.Equal(t, erasure.StatusPending, e.Status)
+	assert.Contains(t, e.LastError, "store unavailable")
+	assert.Equal(t, []erasure.StoreResult{{Store: StoreVectors, Removed: 3}}, e.Stores, "progress is kept")
+
+	erased, err := service.Erase(context.Background(), e.ID)
+	require.NoError(t, err)
+	assert.Empty(t, erased.LastError)
+	assert.Equal(t, []erasure.StoreResult{
+		{Store: StoreVectors, Removed: 3},
+		{Store: StorePostgres, Removed: 0},
+	}, erased.Stores, "counts add up across attempts")
+	assert.NotEmpty(t, erased.Receipt)
+
+	vectors.AssertExpectations(t)
+	rows.AssertExpectations(t)
+	log.AssertExpectations(t)
+}
+
+func TestService_GetErasure(t *testing.T) {
+	e := &erasure.Erasure{ID: erasure.NewID(), UserID: user.ID(uuid.New()), RequestedBy: user.ID(uuid.New())}
+	missing := erasure.NewID()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		id      erasure.ID
+		wantErr error
+	}{
+		{"erased user reads the receipt", principalContext(e.UserID, auth.RoleUser), e.ID, nil},
+		{"requester reads the receipt", principalContext(e.RequestedBy, auth.RoleUser), e.ID, nil},
+		{"admin reads the receipt", principalContext(user.ID(uuid.New()), auth.RoleAdmin), e.ID, nil},
+		{"other user is denied", principalContext(user.ID(uuid.New()), auth.RoleUser), e.ID, auth.ErrPermissionDenied},
+		{"anonymous caller is denied", context.Background(), e.ID, auth.ErrPermissionDenied},
+		{"missing erasure", principalContext(user.ID(uuid.New()), auth.RoleAdmin), missing, erasure.ErrNotFound},
+		{"zero ID", principalContext(e.UserID, auth.RoleUser), erasure.ID{}, erasure.ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockErasureRepository{}
+			repo.On("FindByID", mock.Anything, e.ID).Return(e, nil).Maybe()
+			repo.On("FindByID", mock.Anything, missing).Return(nil, erasure.ErrNotFound).Maybe()
+			service := newTestService(repo, &mockMemoryLister{}, nil, &mockUserRepository{}, &mockProducer{}, &mockLogger{})
+
+			found, err := service.GetErasure(tt.ctx, tt.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, e.ID, found.ID)
+			}
+		})
+	}
+}
+
+func TestJobStore(t *testing.T) {
+	userID := user.ID(uuid.New())
+	memoryID := memory.ID(uuid.New())
+	factory := queue.NewJobFactory()
+
+	tests := []struct {
+		name string
+		job  *queue.Job
+		want bool
+	}{
+		{"running erasure is left alone", factory.CreateEraseUserJob(&erasure.Erasure{ID: erasure.NewID(), UserID: userID}), false},
+		{"job naming the user", factory.CreateBatchEmbeddingJob(userID, 100, 5), true},
+		{"job naming one of their memories", factory.CreateGenerateEmbeddingJob(memoryID, 5), true},
+		{"job naming another memory", factory.CreateGenerateEmbeddingJob(memory.ID(uuid.New()), 5), false},
+		{"job naming another user", factory.CreateBatchEmbeddingJob(user.ID(uuid.New()), 100, 5), false},
+	}
+
+	var match func(*queue.Job) bool
+	purger := &mockPurger{}
+	purger.On("PurgeJobs", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		match = args.Get(1).(func(*queue.Job) bool)
+	}).Return(int64(2), nil)
+
+	subject := &erasure.Subject{UserID: userID, Memories: []erasure.MemoryContent{{ID: memoryID}}}
+	removed, err := NewJobStore(purger).EraseUser(context.Background(), subject)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), removed)
+	require.NotNil(t, match)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, match(tt.job))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+
+	"mem_bank/internal/domain/erasure"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/queue"
+	pkgencryption "mem_bank/pkg/encryption"
+)
+
+// Store names used in receipts
+const (
+	StoreJobs           = "jobs"
+	StoreVectors        = "vectors"
+	StoreEmbeddingCache = "embedding_cache"
+	StoreDataKeys       = "data_keys"
+	StorePostgres       = "postgres"
+)
+
+// jobStore drops queued jobs, their stored payloads and their results
+type jobStore struct {
+	purger queue.Purger
+}
+
+// NewJobStore wipes the jobs that name the user or one of their memories
+func NewJobStore(purger queue.Purger) erasure.Store {
+	return &jobStore{purger: purger}
+}
+
+func (s *jobStore) Name() string { return StoreJobs }
+
+func (s *jobStore) EraseUser(ctx context.Context, subject *erasure.Subject) (int64, error) {
+	memoryIDs := make(map[string]struct{}, len(subject.Memories))
+	for _, id := range subject.MemoryIDs() {
+		memoryIDs[id.String()] = struct{}{}
+	}
+	userID := subject.UserID.String()
+
+	return s.purger.PurgeJobs(ctx, func(job *queue.Job) bool {
+		// The running erasure job must be left to finish
+		if job.Type == queue.JobTypeEraseUser {
+			return false
+		}
+		if id, ok := job.Payload["user_id"].(string); ok && id == userID {
+			return true
+		}
+		id, ok := job.Payload["memory_id"].(string)
+		if !ok {
+			return false
+		}
+		_, found := memoryIDs[id]
+		return found
+	})
+}
+
+// VectorDeleter deletes every vector of a user
+type VectorDeleter interface {
+	DeleteUserVectors(ctx context.Context, userID user.ID) (int64, error)
+}
+
+type vectorStore struct {
+	vectors VectorDeleter
+}
+
+// NewVectorStore wipes the user's vectors from the vector database
+func NewVectorStore(vectors VectorDeleter) erasure.Store {
+	return &vectorStore{vectors: vectors}
+}
+
+func (s *vectorStore) Name() string { return StoreVectors }
+
+func (s *vectorStore) EraseUser(ctx context.Context, subject *erasure.Subject) (int64, error) {
+	return s.vectors.DeleteUserVectors(ctx, subject.UserID)
+}
+
+// EmbeddingEvicter drops cached embeddings of texts
+type EmbeddingEvicter interface {
+	EvictTexts(ctx context.Context, texts []string) (int64, error)
+}
+
+type embeddingCacheStore struct {
+	cache EmbeddingEvicter
+}
+
+// NewEmbeddingCacheStore wipes the cached embeddings of the user's memories.
+// Cache keys are derived from content, so content that can no longer be
+// decrypted is left to expire.
+func NewEmbeddingCacheStore(cache EmbeddingEvicter) erasure.Store {
+	return &embeddingCacheStore{cache: cache}
+}
+
+func (s *embeddingCacheStore) Name() string { return StoreEmbeddingCache }
+
+func (s *embeddingCacheStore) EraseUser(ctx context.Context, subject *erasure.Subject) (int64, error) {
+	texts := make([]string, 0, len(subject.Memories))
+	for _, m := range subject.Memories {
+		if m.Content != "" {
+			texts = append(texts, m.Content)
+		}
+	}
+	if len(texts) == 0 {
+		return 0, nil
+	}
+	return s.cache.EvictTexts(ctx, texts)
+}
+
+type dataKeyStore struct {
+	keys *pkgencryption.KeyRing
+}
+
+// NewDataKeyStore destroys the user's data keys, crypto-shredding anything
+// encrypted with them that survives elsewhere, such as backups
+func NewDataKeyStore(keys *pkgencryption.KeyRing) erasure.Store {
+	return &dataKeyStore{keys: keys}
+}
+
+func (s *dataKeyStore) Name() string { return StoreDataKeys }
+
+func (s *dataKeyStore) EraseUser(ctx context.Context, subject *erasure.Subject) (int64, error) {
+	userID := subject.UserID.String()
+
+	// Versions are numbered from one, so the newest tells how many there are
+	var versions int64
+	latest, err := s.keys.Latest(ctx, userID)
+	switch {
+	case err == nil:
+		versions = int64(latest.Version)
+	case !errors.Is(err, pkgencryption.ErrUnknownKey):
+		return 0, err
+	}
+
+	if err := s.keys.Forget(ctx, userID); err != nil {
+		return 0, err
+	}
+	return versions, nil
+}
+
+type postgresStore struct {
+	userRepo user.Repository
+}
+
+// NewPostgresStore deletes the user; their memories, tokens, keys and
+// memberships go with them by cascade. Removed counts memories.
+func NewPostgresStore(userRepo user.Repository) erasure.Store {
+	return &postgresStore{userRepo: userRepo}
+}
+
+func (s *postgresStore) Name() string { return StorePostgres }
+
+func (s *postgresStore) EraseUser(ctx context.Context, subject *erasure.Subject) (int64, error) {
+	err := s.userRepo.Delete(ctx, subject.UserID)
+	if errors.Is(err, user.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(subject.Memories)), nil
+}
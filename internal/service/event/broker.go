@@ -0,0 +1,214 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// streamBufferSize is how many events a subscriber may fall behind before
+// publishing to it blocks
+const streamBufferSize = 64
+
+// message is an event as published to Redis
+type message struct {
+	ID        string                 `json:"id"`
+	Sequence  int64                  `json:"sequence"`
+	Type      string                 `json:"type"`
+	UserID    string                 `json:"user_id"`
+	SpaceID   string                 `json:"space_id,omitempty"`
+	MemoryID  string                 `json:"memory_id"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// RedisBroker implements event.Broker with a Redis channel per user. The
+// recent events of a user are also kept in a sorted set scored by
+// sequence, from which subscribers resume.
+type RedisBroker struct {
+	client *redis.Client
+	prefix string
+	config StreamConfig
+	logger logger.Logger
+}
+
+// NewRedisBroker creates a broker storing its keys and channels under prefix
+func NewRedisBroker(client *redis.Client, prefix string, logger logger.Logger, config StreamConfig) *RedisBroker {
+	return &RedisBroker{
+		client: client,
+		prefix: prefix,
+		config: config.withDefaults(),
+		logger: logger,
+	}
+}
+
+func (b *RedisBroker) channel(userID user.ID) string {
+	return b.prefix + userID.String()
+}
+
+func (b *RedisBroker) bufferKey(userID user.ID) string {
+	return b.prefix + "buffer:" + userID.String()
+}
+
+// Publish retains e in its author's buffer, trimmed to the buffer size, and
+// sends it to the author's channel
+func (b *RedisBroker) Publish(ctx context.Context, e *event.Event) error {
+	data, err := json.Marshal(toMessage(e))
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	key := b.bufferKey(e.UserID)
+	_, err = b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(e.Sequence), Member: data})
+		pipe.ZRemRangeByRank(ctx, key, 0, int64(-b.config.BufferSize-1))
+		pipe.Expire(ctx, key, b.config.BufferTTL)
+		pipe.Publish(ctx, b.channel(e.UserID), data)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("publishing event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe listens on the channel of userID before reading the buffer, so
+// no event falls between the two. Events that were in the buffer and are
+// also received on the channel are sent once.
+func (b *RedisBroker) Subscribe(ctx context.Context, userID user.ID, after int64) (<-chan *event.Event, error) {
+	pubsub := b.client.Subscribe(ctx, b.channel(userID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("subscribing to events: %w", err)
+	}
+
+	var retained []*event.Event
+	if after > 0 {
+		members, err := b.client.ZRangeByScore(ctx, b.bufferKey(userID), &redis.ZRangeBy{
+			Min: "(" + strconv.FormatInt(after, 10),
+			Max: "+inf",
+		}).Result()
+		if err != nil {
+			pubsub.Close()
+			return nil, fmt.Errorf("reading retained events: %w", err)
+		}
+		for _, member := range members {
+			e, err := decodeMessage(member)
+			if err != nil {
+				pubsub.Close()
+				return nil, err
+			}
+			retained = append(retained, e)
+		}
+	}
+
+	events := make(chan *event.Event, streamBufferSize)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		// Publishers on several replicas may publish out of sequence, so
+		// only the retained events are skipped rather than everything up
+		// to the last sequence sent
+		sent := make(map[int64]bool, len(retained))
+		for _, e := range retained {
+			select {
+			case events <- e:
+				sent[e.Sequence] = true
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				e, err := decodeMessage(msg.Payload)
+				if err != nil {
+					b.logger.WithError(err).Warn("Dropping undecodable stream event")
+					continue
+				}
+				if sent[e.Sequence] {
+					continue
+				}
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+func toMessage(e *event.Event) message {
+	m := message{
+		ID:        e.ID.String(),
+		Sequence:  e.Sequence,
+		Type:      e.Type,
+		UserID:    e.UserID.String(),
+		MemoryID:  e.MemoryID.String(),
+		Data:      e.Data,
+		CreatedAt: e.CreatedAt,
+	}
+	if !e.SpaceID.IsZero() {
+		m.SpaceID = e.SpaceID.String()
+	}
+	return m
+}
+
+func decodeMessage(payload string) (*event.Event, error) {
+	var m message
+	if err := json.Unmarshal([]byte(payload), &m); err != nil {
+		return nil, fmt.Errorf("decoding event: %w", err)
+	}
+
+	id, err := uuid.Parse(m.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing event ID: %w", err)
+	}
+	userID, err := uuid.Parse(m.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing user ID: %w", err)
+	}
+	memoryID, err := uuid.Parse(m.MemoryID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing memory ID: %w", err)
+	}
+
+	e := &event.Event{
+		ID:        event.ID(id),
+		Sequence:  m.Sequence,
+		Type:      m.Type,
+		UserID:    user.ID(userID),
+		MemoryID:  memory.ID(memoryID),
+		Data:      m.Data,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.SpaceID != "" {
+		spaceID, err := uuid.Parse(m.SpaceID)
+		if err != nil {
+			return nil, fmt.Errorf("parsing space ID: %w", err)
+		}
+		e.SpaceID = space.ID(spaceID)
+	}
+	return e, nil
+}
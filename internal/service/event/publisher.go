@@ -0,0 +1,104 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/pkg/logger"
+)
+
+// StreamConfig holds event stream configuration
+type StreamConfig struct {
+	// PublishInterval is how often new events are published
+	PublishInterval time.Duration `mapstructure:"publish_interval"`
+	// PublishBatchSize is how many events are published per transaction
+	PublishBatchSize int `mapstructure:"publish_batch_size"`
+	// BufferSize is how many recent events are retained per user
+	BufferSize int `mapstructure:"buffer_size"`
+	// BufferTTL is how long the events of a user are retained after the
+	// last one was published
+	BufferTTL time.Duration `mapstructure:"buffer_ttl"`
+}
+
+// DefaultStreamConfig returns the default event stream configuration
+func DefaultStreamConfig() StreamConfig {
+	return StreamConfig{
+		PublishInterval:  500 * time.Millisecond,
+		PublishBatchSize: 100,
+		BufferSize:       500,
+		BufferTTL:        time.Hour,
+	}
+}
+
+func (c StreamConfig) withDefaults() StreamConfig {
+	defaults := DefaultStreamConfig()
+	if c.PublishInterval <= 0 {
+		c.PublishInterval = defaults.PublishInterval
+	}
+	if c.PublishBatchSize <= 0 {
+		c.PublishBatchSize = defaults.PublishBatchSize
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = defaults.BufferSize
+	}
+	if c.BufferTTL <= 0 {
+		c.BufferTTL = defaults.BufferTTL
+	}
+	return c
+}
+
+// Publisher moves events from the outbox to the broker. Each replica runs
+// one; an event is published by whichever locks it first, and one that
+// could not be published is tried again later.
+type Publisher struct {
+	events event.Repository
+	broker event.Broker
+	logger logger.Logger
+	config StreamConfig
+}
+
+// NewPublisher creates a new event publisher
+func NewPublisher(events event.Repository, broker event.Broker, logger logger.Logger, config StreamConfig) *Publisher {
+	return &Publisher{
+		events: events,
+		broker: broker,
+		logger: logger,
+		config: config.withDefaults(),
+	}
+}
+
+// Run publishes pending events every publish interval until ctx is done
+func (p *Publisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.PublishInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := p.Publish(ctx); err != nil {
+				p.logger.WithError(err).Error("Failed to publish memory events")
+			}
+		}
+	}
+}
+
+// Publish sends the pending events to the broker and returns how many it
+// published
+func (p *Publisher) Publish(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := p.events.PublishPending(ctx, p.config.PublishBatchSize, func(e *event.Event) error {
+			return p.broker.Publish(ctx, e)
+		})
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < p.config.PublishBatchSize {
+			return total, nil
+		}
+	}
+}
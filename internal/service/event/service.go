@@ -0,0 +1,130 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// Limits on the events returned by List
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// Config holds event feed configuration
+type Config struct {
+	// Retention is how long events are kept; the feed cannot be resumed
+	// from further back
+	Retention time.Duration `mapstructure:"retention"`
+	// PurgeInterval is how often expired events are deleted
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+}
+
+// DefaultConfig returns the default event feed configuration
+func DefaultConfig() Config {
+	return Config{
+		Retention:     7 * 24 * time.Hour,
+		PurgeInterval: time.Hour,
+	}
+}
+
+// Service implements event.Service
+type Service struct {
+	repo   event.Repository
+	broker event.Broker
+	logger logger.Logger
+	config Config
+	now    func() time.Time
+}
+
+// NewService creates a new event feed service
+func NewService(repo event.Repository, logger logger.Logger, config Config) *Service {
+	defaults := DefaultConfig()
+	if config.Retention <= 0 {
+		config.Retention = defaults.Retention
+	}
+	if config.PurgeInterval <= 0 {
+		config.PurgeInterval = defaults.PurgeInterval
+	}
+
+	return &Service{
+		repo:   repo,
+		logger: logger,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+func (s *Service) List(ctx context.Context, filter event.Filter) ([]*event.Event, error) {
+	if err := auth.Authorize(ctx, filter.UserID); err != nil {
+		return nil, err
+	}
+	if filter.Since < 0 || filter.Limit < 0 {
+		return nil, fmt.Errorf("%w: since and limit must not be negative", event.ErrInvalidFilter)
+	}
+	for _, t := range filter.Types {
+		if !event.ValidType(t) {
+			return nil, fmt.Errorf("%w: unknown event type %q", event.ErrInvalidFilter, t)
+		}
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultListLimit
+	}
+	filter.Limit = min(filter.Limit, maxListLimit)
+
+	return s.repo.List(ctx, filter)
+}
+
+// WithBroker enables Stream, with events from broker
+func (s *Service) WithBroker(broker event.Broker) *Service {
+	s.broker = broker
+	return s
+}
+
+func (s *Service) Stream(ctx context.Context, userID user.ID, after int64) (<-chan *event.Event, error) {
+	if s.broker == nil {
+		return nil, event.ErrStreamDisabled
+	}
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	if after < 0 {
+		return nil, fmt.Errorf("%w: the last event ID must not be negative", event.ErrInvalidFilter)
+	}
+	return s.broker.Subscribe(ctx, userID, after)
+}
+
+// Run deletes expired events every purge interval until ctx is done
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Purge(ctx); err != nil {
+				s.logger.WithError(err).Error("Failed to purge memory events")
+			}
+		}
+	}
+}
+
+// Purge deletes the events older than the retention period
+func (s *Service) Purge(ctx context.Context) error {
+	deleted, err := s.repo.DeleteBefore(ctx, s.now().Add(-s.config.Retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		s.logger.WithField("deleted", deleted).Info("Expired memory events purged")
+	}
+	return nil
+}
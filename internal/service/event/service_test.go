@@ -0,0 +1,395 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+	"mem_bank/pkg/logger"
+)
+
+// Mock event repository
+type mockEventRepository struct {
+	event.Repository
+	mock.Mock
+}
+
+func (m *mockEventRepository) List(ctx context.Context, filter event.Filter) ([]*event.Event, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*event.Event), args.Error(1)
+}
+
+func (m *mockEventRepository) PublishPending(ctx context.Context, limit int, fn func(e *event.Event) error) (int, error) {
+	args := m.Called(ctx, limit, fn)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockEventRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// Mock broker
+type mockBroker struct {
+	mock.Mock
+}
+
+func (m *mockBroker) Publish(ctx context.Context, e *event.Event) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *mockBroker) Subscribe(ctx context.Context, userID user.ID, after int64) (<-chan *event.Event, error) {
+	args := m.Called(ctx, userID, after)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(<-chan *event.Event), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+func newEvents(userID user.ID, n int) []*event.Event {
+	events := make([]*event.Event, n)
+	for i := range events {
+		m := memory.NewMemory(userID, "content", "", 5, "fact")
+		events[i] = event.NewMemoryEvent(event.TypeMemoryCreated, m)
+		events[i].Sequence = int64(i + 1)
+	}
+	return events
+}
+
+// passes hands events to the callback of PublishPending
+func passes(events ...*event.Event) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		fn := args.Get(2).(func(e *event.Event) error)
+		for _, e := range events {
+			if fn(e) != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestService_List(t *testing.T) {
+	owner := user.ID(uuid.New())
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: owner, Role: auth.RoleUser})
+	events := newEvents(owner, 2)
+
+	testCases := []struct {
+		name       string
+		filter     event.Filter
+		setupMocks func(*mockEventRepository)
+		wantErr    error
+	}{
+		{
+			name:   "default limit",
+			filter: event.Filter{UserID: owner, Since: 3},
+			setupMocks: func(r *mockEventRepository) {
+				r.On("List", mock.Anything, event.Filter{UserID: owner, Since: 3, Limit: defaultListLimit}).Return(events, nil)
+			},
+		},
+		{
+			name:   "limit is capped",
+			filter: event.Filter{UserID: owner, Types: []string{event.TypeMemoryCreated}, Limit: 5000},
+			setupMocks: func(r *mockEventRepository) {
+				r.On("List", mock.Anything, event.Filter{UserID: owner, Types: []string{event.TypeMemoryCreated}, Limit: maxListLimit}).Return(events, nil)
+			},
+		},
+		{
+			name:       "other user",
+			filter:     event.Filter{UserID: user.ID(uuid.New())},
+			setupMocks: func(r *mockEventRepository) {},
+			wantErr:    auth.ErrPermissionDenied,
+		},
+		{
+			name:       "negative since",
+			filter:     event.Filter{UserID: owner, Since: -1},
+			setupMocks: func(r *mockEventRepository) {},
+			wantErr:    event.ErrInvalidFilter,
+		},
+		{
+			name:       "unknown type",
+			filter:     event.Filter{UserID: owner, Types: []string{"memory.exploded"}},
+			setupMocks: func(r *mockEventRepository) {},
+			wantErr:    event.ErrInvalidFilter,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockEventRepository{}
+			tc.setupMocks(repo)
+
+			found, err := NewService(repo, &mockLogger{}, Config{}).List(ctx, tc.filter)
+
+			repo.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, found)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, events, found)
+		})
+	}
+}
+
+func TestService_Stream(t *testing.T) {
+	owner := user.ID(uuid.New())
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: owner, Role: auth.RoleUser})
+	stream := make(<-chan *event.Event)
+
+	testCases := []struct {
+		name       string
+		noBroker   bool
+		userID     user.ID
+		after      int64
+		setupMocks func(*mockBroker)
+		wantErr    error
+	}{
+		{
+			name:   "successful subscription",
+			userID: owner,
+			after:  42,
+			setupMocks: func(b *mockBroker) {
+				b.On("Subscribe", mock.Anything, owner, int64(42)).Return(stream, nil)
+			},
+		},
+		{
+			name:       "streaming disabled",
+			noBroker:   true,
+			userID:     owner,
+			setupMocks: func(b *mockBroker) {},
+			wantErr:    event.ErrStreamDisabled,
+		},
+		{
+			name:       "other user",
+			userID:     user.ID(uuid.New()),
+			setupMocks: func(b *mockBroker) {},
+			wantErr:    auth.ErrPermissionDenied,
+		},
+		{
+			name:       "negative last event ID",
+			userID:     owner,
+			after:      -1,
+			setupMocks: func(b *mockBroker) {},
+			wantErr:    event.ErrInvalidFilter,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			broker := &mockBroker{}
+			tc.setupMocks(broker)
+			service := NewService(&mockEventRepository{}, &mockLogger{}, Config{})
+			if !tc.noBroker {
+				service.WithBroker(broker)
+			}
+
+			events, err := service.Stream(ctx, tc.userID, tc.after)
+
+			broker.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, stream, events)
+		})
+	}
+}
+
+func TestService_Purge(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	cutoff := now.Add(-24 * time.Hour)
+	dbErr := errors.New("database error")
+
+	testCases := []struct {
+		name       string
+		setupMocks func(*mockEventRepository, *mockLogger)
+		wantErr    error
+	}{
+		{
+			name: "expired events deleted",
+			setupMocks: func(r *mockEventRepository, l *mockLogger) {
+				r.On("DeleteBefore", mock.Anything, cutoff).Return(int64(3), nil)
+				l.On("Info", "Expired memory events purged").Once()
+			},
+		},
+		{
+			name: "nothing expired",
+			setupMocks: func(r *mockEventRepository, l *mockLogger) {
+				r.On("DeleteBefore", mock.Anything, cutoff).Return(int64(0), nil)
+			},
+		},
+		{
+			name: "repository error",
+			setupMocks: func(r *mockEventRepository, l *mockLogger) {
+				r.On("DeleteBefore", mock.Anything, cutoff).Return(int64(0), dbErr)
+			},
+			wantErr: dbErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockEventRepository{}
+			log := &mockLogger{}
+			tc.setupMocks(repo, log)
+			service := NewService(repo, log, Config{Retention: 24 * time.Hour})
+			service.now = func() time.Time { return now }
+
+			err := service.Purge(context.Background())
+
+			repo.AssertExpectations(t)
+			log.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	events := newEvents(user.ID(uuid.New()), 3)
+	brokerErr := errors.New("redis unavailable")
+
+	testCases := []struct {
+		name       string
+		setupMocks func(*mockEventRepository, *mockBroker)
+		want       int
+		wantErr    error
+	}{
+		{
+			name: "publishes batches until the outbox is drained",
+			setupMocks: func(r *mockEventRepository, b *mockBroker) {
+				r.On("PublishPending", mock.Anything, 2, mock.Anything).Run(passes(events[:2]...)).Return(2, nil).Once()
+				r.On("PublishPending", mock.Anything, 2, mock.Anything).Run(passes(events[2])).Return(1, nil).Once()
+				for _, e := range events {
+					b.On("Publish", mock.Anything, e).Return(nil).Once()
+				}
+			},
+			want: 3,
+		},
+		{
+			name: "empty outbox",
+			setupMocks: func(r *mockEventRepository, b *mockBroker) {
+				r.On("PublishPending", mock.Anything, 2, mock.Anything).Return(0, nil).Once()
+			},
+		},
+		{
+			// Events the broker refused stay pending
+			name: "broker error",
+			setupMocks: func(r *mockEventRepository, b *mockBroker) {
+				r.On("PublishPending", mock.Anything, 2, mock.Anything).Run(passes(events[:2]...)).Return(1, brokerErr).Once()
+				b.On("Publish", mock.Anything, events[0]).Return(nil).Once()
+				b.On("Publish", mock.Anything, events[1]).Return(brokerErr).Once()
+			},
+			want:    1,
+			wantErr: brokerErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockEventRepository{}
+			broker := &mockBroker{}
+			tc.setupMocks(repo, broker)
+
+			n, err := NewPublisher(repo, broker, &mockLogger{}, StreamConfig{PublishBatchSize: 2}).Publish(context.Background())
+
+			repo.AssertExpectations(t)
+			broker.AssertExpectations(t)
+			assert.Equal(t, tc.want, n)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRedisBroker_Integration(t *testing.T) {
+	// Skip if Redis is not available
+	client, err := database.NewRedisClientWithOptions(&redis.Options{
+		Addr: "localhost:6379",
+		DB:   1, // Use different DB for testing
+	}, time.Second)
+	if err != nil {
+		t.Skip("Redis not available, skipping broker tests:", err)
+	}
+	defer client.FlushDB(context.Background())
+
+	broker := NewRedisBroker(client, "test:events:", &mockLogger{}, StreamConfig{BufferSize: 3})
+	owner := user.ID(uuid.New())
+	events := newEvents(owner, 5)
+	for _, e := range events[:4] {
+		require.NoError(t, broker.Publish(context.Background(), e))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Only the last three events are retained
+	stream, err := broker.Subscribe(ctx, owner, 1)
+	require.NoError(t, err)
+	require.NoError(t, broker.Publish(context.Background(), events[4]))
+
+	var sequences []int64
+	for e := range stream {
+		sequences = append(sequences, e.Sequence)
+		assert.Equal(t, events[e.Sequence-1].MemoryID, e.MemoryID)
+		if len(sequences) == 4 {
+			cancel()
+		}
+	}
+	assert.Equal(t, []int64{2, 3, 4, 5}, sequences)
+
+	// Without a last event ID only new events are streamed
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err = broker.Subscribe(ctx, owner, 0)
+	require.NoError(t, err)
+	next := newEvents(owner, 6)[5]
+	require.NoError(t, broker.Publish(context.Background(), next))
+	e := <-stream
+	require.NotNil(t, e)
+	assert.Equal(t, next.ID, e.ID)
+}
@@ -2,13 +2,18 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/pii"
+	"mem_bank/internal/domain/quota"
+	"mem_bank/internal/domain/space"
 	"mem_bank/internal/domain/user"
 	"mem_bank/internal/queue"
 	"mem_bank/internal/service/embedding"
+	"mem_bank/pkg/llm"
 	"mem_bank/pkg/logger"
 )
 
@@ -49,7 +54,10 @@ type AIServiceConfig struct {
 func NewAIService(
 	repo memory.Repository,
 	userRepo user.Repository,
+	spaces space.Repository,
 	embeddingService *embedding.Service,
+	quotas quota.Service,
+	piiService pii.Service,
 	jobQueue queue.Producer,
 	logger logger.Logger,
 	config AIServiceConfig,
@@ -69,6 +77,10 @@ func NewAIService(
 		service: service{
 			repo:     repo,
 			userRepo: userRepo,
+			spaces:   spaces,
+			quotas:   quotas,
+			pii:      piiService,
+			logger:   logger,
 		},
 		embeddingService: embeddingService,
 		jobQueue:         jobQueue,
@@ -139,6 +151,9 @@ func (s *AIService) SearchSimilarMemories(ctx context.Context, content string, u
 	if userID.IsZero() {
 		return nil, memory.ErrInvalidUserID
 	}
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
 
 	if strings.TrimSpace(content) == "" {
 		return nil, memory.ErrInvalidContent
@@ -153,7 +168,10 @@ func (s *AIService) SearchSimilarMemories(ctx context.Context, content string, u
 	}
 
 	// Generate embedding for the search content
-	embeddingResult, err := s.embeddingService.GenerateEmbedding(ctx, content)
+	embeddingResult, err := s.embeddingService.GenerateEmbedding(llm.WithUserID(ctx, userID.String()), content)
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		return nil, quotaError(err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("generating search embedding: %w", err)
 	}
@@ -179,11 +197,52 @@ func (s *AIService) SearchSimilarMemories(ctx context.Context, content string, u
 	return memories, nil
 }
 
+// SearchSimilarAcrossSpaces searches for similar memories in the user's
+// personal space and their shared spaces
+func (s *AIService) SearchSimilarAcrossSpaces(ctx context.Context, content string, userID user.ID, spaceIDs []space.ID, limit int, threshold float64) ([]*memory.Memory, error) {
+	if userID.IsZero() {
+		return nil, memory.ErrInvalidUserID
+	}
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(content) == "" {
+		return nil, memory.ErrInvalidContent
+	}
+
+	if limit <= 0 {
+		limit = 10 // default limit
+	}
+
+	if threshold <= 0 {
+		threshold = s.config.DefaultSimilarityThreshold
+	}
+
+	embeddingResult, err := s.embeddingService.GenerateEmbedding(llm.WithUserID(ctx, userID.String()), content)
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		return nil, quotaError(err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generating search embedding: %w", err)
+	}
+
+	memories, err := s.repo.SearchSimilarInScope(ctx, embeddingResult.Embedding, s.sharedScope(userID, spaceIDs), limit, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("searching similar memories: %w", err)
+	}
+
+	return memories, nil
+}
+
 // GenerateEmbeddingsForUser generates embeddings for all memories of a user that don't have them
 func (s *AIService) GenerateEmbeddingsForUser(ctx context.Context, userID user.ID) error {
 	if userID.IsZero() {
 		return memory.ErrInvalidUserID
 	}
+	if err := authorize(ctx, userID); err != nil {
+		return err
+	}
 
 	// Schedule batch embedding generation job
 	job := s.jobFactory.CreateBatchEmbeddingJob(userID, s.config.BatchEmbeddingSize, s.config.EmbeddingJobPriority)
@@ -225,6 +284,9 @@ func (s *AIService) SearchWithSemanticRanking(ctx context.Context, req memory.Se
 	if req.UserID.IsZero() {
 		return nil, memory.ErrInvalidUserID
 	}
+	if err := authorize(ctx, req.UserID); err != nil {
+		return nil, err
+	}
 
 	if req.Limit <= 0 {
 		req.Limit = 20
@@ -264,20 +326,19 @@ func (s *AIService) GetEmbeddingStats(ctx context.Context, userID user.ID) (map[
 	if userID.IsZero() {
 		return nil, memory.ErrInvalidUserID
 	}
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
 
-	// Get all user memories
-	memories, err := s.repo.FindByUserID(ctx, userID, 1000, 0) // Get a large batch
+	model := s.embeddingService.Model()
+	coverage, err := s.repo.GetEmbeddingCoverage(ctx, userID, model)
 	if err != nil {
-		return nil, fmt.Errorf("getting user memories: %w", err)
+		return nil, fmt.Errorf("getting embedding coverage: %w", err)
 	}
 
-	totalMemories := len(memories)
-	memoriesWithEmbeddings := 0
-
-	for _, mem := range memories {
-		if len(mem.Embedding) > 0 {
-			memoriesWithEmbeddings++
-		}
+	coveragePercent := 0.0
+	if coverage.TotalMemories > 0 {
+		coveragePercent = float64(coverage.WithModel) / float64(coverage.TotalMemories) * 100
 	}
 
 	embeddingsCacheStats, err := s.embeddingService.GetCacheStats(ctx)
@@ -287,10 +348,12 @@ func (s *AIService) GetEmbeddingStats(ctx context.Context, userID user.ID) (map[
 	}
 
 	return map[string]interface{}{
-		"total_memories":              totalMemories,
-		"memories_with_embeddings":    memoriesWithEmbeddings,
-		"memories_without_embeddings": totalMemories - memoriesWithEmbeddings,
-		"embedding_coverage_percent":  float64(memoriesWithEmbeddings) / float64(totalMemories) * 100,
+		"total_memories":              coverage.TotalMemories,
+		"memories_with_embeddings":    coverage.WithEmbedding,
+		"memories_without_embeddings": coverage.TotalMemories - coverage.WithEmbedding,
+		"memories_with_current_model": coverage.WithModel,
+		"embedding_model":             model,
+		"embedding_coverage_percent":  coveragePercent,
 		"cache_stats":                 embeddingsCacheStats,
 	}, nil
 }
@@ -314,13 +377,13 @@ func (s *AIService) scheduleEmbeddingGeneration(ctx context.Context, memoryID me
 
 func (s *AIService) generateEmbeddingSync(ctx context.Context, m *memory.Memory) error {
 	// Generate embedding
-	embeddingResult, err := s.embeddingService.GenerateEmbedding(ctx, m.Content)
+	embeddingResult, err := s.embeddingService.GenerateEmbedding(llm.WithUserID(ctx, m.UserID.String()), m.Content)
 	if err != nil {
 		return fmt.Errorf("generating embedding: %w", err)
 	}
 
 	// Update memory with embedding
-	m.UpdateEmbedding(embeddingResult.Embedding)
+	m.UpdateEmbedding(embeddingResult.Embedding, embeddingResult.Model)
 
 	// Save updated memory
 	if err := s.repo.Update(ctx, m); err != nil {
@@ -2,29 +2,46 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"mem_bank/internal/domain/auth"
 	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/pii"
+	"mem_bank/internal/domain/quota"
+	"mem_bank/internal/domain/space"
 	"mem_bank/internal/domain/user"
 	"mem_bank/internal/service/embedding"
+	"mem_bank/pkg/llm"
 	"mem_bank/pkg/logger"
 )
 
+// maxBatchSize is the most memories BatchCreateMemories accepts at once
+const maxBatchSize = 100
+
 // service implements memory.Service interface
 type service struct {
 	repo             memory.Repository
 	userRepo         user.Repository
+	spaces           space.Repository
 	embeddingService *embedding.Service
+	quotas           quota.Service
+	pii              pii.Service
 	logger           logger.Logger
 }
 
-// NewService creates a new memory service
-func NewService(repo memory.Repository, userRepo user.Repository, embeddingService *embedding.Service, logger logger.Logger) memory.Service {
+// NewService creates a new memory service; spaces may be nil to disable
+// shared spaces, quotas may be nil to disable quota checks and piiService
+// may be nil to store memories without screening them for personal data
+func NewService(repo memory.Repository, userRepo user.Repository, spaces space.Repository, embeddingService *embedding.Service, quotas quota.Service, piiService pii.Service, logger logger.Logger) memory.Service {
 	return &service{
 		repo:             repo,
 		userRepo:         userRepo,
+		spaces:           spaces,
 		embeddingService: embeddingService,
+		quotas:           quotas,
+		pii:              piiService,
 		logger:           logger,
 	}
 }
@@ -34,6 +51,14 @@ func (s *service) CreateMemory(ctx context.Context, req memory.CreateRequest) (*
 	if err := s.validateCreateRequest(req); err != nil {
 		return nil, err
 	}
+	if err := authorize(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if !req.SpaceID.IsZero() {
+		if err := s.authorizeSpace(ctx, req.SpaceID, space.RoleEditor); err != nil {
+			return nil, err
+		}
+	}
 
 	// Verify user exists
 	_, err := s.userRepo.FindByID(ctx, req.UserID)
@@ -44,8 +69,20 @@ func (s *service) CreateMemory(ctx context.Context, req memory.CreateRequest) (*
 		return nil, fmt.Errorf("verifying user: %w", err)
 	}
 
+	// Personal data is dealt with before anything is stored or embedded
+	content, summary := req.Content, req.Summary
+	screening, err := s.screen(ctx, req.UserID, &content, &summary)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkQuota(ctx, req.UserID, 1, int64(len(content))); err != nil {
+		return nil, err
+	}
+
 	// Create new memory
-	m := memory.NewMemory(req.UserID, req.Content, req.Summary, req.Importance, req.MemoryType)
+	m := memory.NewMemory(req.UserID, content, summary, req.Importance, req.MemoryType)
+	m.SpaceID = req.SpaceID
 
 	// Set optional fields
 	if len(req.Tags) > 0 {
@@ -57,12 +94,15 @@ func (s *service) CreateMemory(ctx context.Context, req memory.CreateRequest) (*
 
 	// Generate embedding for the content
 	if s.embeddingService != nil {
-		embeddingResult, err := s.embeddingService.GenerateEmbedding(ctx, req.Content)
+		embeddingResult, err := s.embeddingService.GenerateEmbedding(llm.WithUserID(ctx, req.UserID.String()), m.Content)
+		if errors.Is(err, quota.ErrQuotaExceeded) {
+			return nil, quotaError(err)
+		}
 		if err != nil {
 			s.logger.WithError(err).Warn("Failed to generate embedding for memory")
 			// Continue without embedding - this is not a fatal error
 		} else {
-			m.UpdateEmbedding(embeddingResult.Embedding)
+			m.UpdateEmbedding(embeddingResult.Embedding, embeddingResult.Model)
 		}
 	}
 
@@ -70,10 +110,115 @@ func (s *service) CreateMemory(ctx context.Context, req memory.CreateRequest) (*
 	if err := s.repo.Store(ctx, m); err != nil {
 		return nil, fmt.Errorf("storing memory: %w", err)
 	}
+	if err := s.saveTokens(ctx, []*memory.Memory{m}, []*pii.Screening{screening}); err != nil {
+		return nil, err
+	}
 
 	return m, nil
 }
 
+func (s *service) BatchCreateMemories(ctx context.Context, reqs []memory.CreateRequest) ([]*memory.Memory, error) {
+	if len(reqs) == 0 || len(reqs) > maxBatchSize {
+		return nil, memory.NewValidationError("memories", fmt.Sprintf("batch must contain between 1 and %d memories", maxBatchSize))
+	}
+
+	// Validate everything and group by user before touching storage
+	byUser := make(map[user.ID][]int)
+	var users []user.ID
+	for i, req := range reqs {
+		if err := s.validateCreateRequest(req); err != nil {
+			return nil, memory.NewValidationError(fmt.Sprintf("memories[%d]", i), err.Error())
+		}
+		if _, ok := byUser[req.UserID]; !ok {
+			users = append(users, req.UserID)
+		}
+		byUser[req.UserID] = append(byUser[req.UserID], i)
+	}
+
+	memories := make([]*memory.Memory, len(reqs))
+	for i, req := range reqs {
+		m := memory.NewMemory(req.UserID, req.Content, req.Summary, req.Importance, req.MemoryType)
+		m.SpaceID = req.SpaceID
+		if len(req.Tags) > 0 {
+			m.Tags = req.Tags
+		}
+		if req.Metadata != nil {
+			m.Metadata = req.Metadata
+		}
+		memories[i] = m
+	}
+
+	for _, userID := range users {
+		if err := authorize(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	checked := make(map[space.ID]bool)
+	for _, req := range reqs {
+		if req.SpaceID.IsZero() || checked[req.SpaceID] {
+			continue
+		}
+		if err := s.authorizeSpace(ctx, req.SpaceID, space.RoleEditor); err != nil {
+			return nil, err
+		}
+		checked[req.SpaceID] = true
+	}
+
+	screenings := make([]*pii.Screening, len(reqs))
+	for _, userID := range users {
+		indices := byUser[userID]
+
+		if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+			if err == user.ErrNotFound {
+				return nil, memory.ErrInvalidUserID
+			}
+			return nil, fmt.Errorf("verifying user: %w", err)
+		}
+
+		texts := make([]string, len(indices))
+		var bytes int64
+		for j, idx := range indices {
+			m := memories[idx]
+			screening, err := s.screen(ctx, userID, &m.Content, &m.Summary)
+			if err != nil {
+				return nil, err
+			}
+			screenings[idx] = screening
+			texts[j] = m.Content
+			bytes += int64(len(m.Content))
+		}
+		if err := s.checkQuota(ctx, userID, len(indices), bytes); err != nil {
+			return nil, err
+		}
+
+		if s.embeddingService == nil {
+			continue
+		}
+		batchResult, err := s.embeddingService.GenerateEmbeddings(llm.WithUserID(ctx, userID.String()), texts)
+		if errors.Is(err, quota.ErrQuotaExceeded) {
+			return nil, quotaError(err)
+		}
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to generate embeddings for memory batch")
+			// Continue without embeddings - the batch embedding job can fill them in
+			continue
+		}
+		for j, idx := range indices {
+			result := batchResult.Results[j]
+			memories[idx].UpdateEmbedding(result.Embedding, result.Model)
+		}
+	}
+
+	if err := s.repo.BatchStore(ctx, memories); err != nil {
+		return nil, fmt.Errorf("storing memories: %w", err)
+	}
+	if err := s.saveTokens(ctx, memories, screenings); err != nil {
+		return nil, err
+	}
+
+	return memories, nil
+}
+
 func (s *service) GetMemory(ctx context.Context, id memory.ID) (*memory.Memory, error) {
 	if id.IsZero() {
 		return nil, memory.ErrInvalidID
@@ -84,6 +229,9 @@ func (s *service) GetMemory(ctx context.Context, id memory.ID) (*memory.Memory,
 	if err != nil {
 		return nil, err
 	}
+	if err := s.authorizeMemory(ctx, m, space.RoleViewer); err != nil {
+		return nil, err
+	}
 
 	// Update access info
 	m.Access()
@@ -105,8 +253,12 @@ func (s *service) UpdateMemory(ctx context.Context, id memory.ID, req memory.Upd
 	if err != nil {
 		return nil, err
 	}
+	if err := s.authorizeMemory(ctx, m, space.RoleEditor); err != nil {
+		return nil, err
+	}
 
 	// Apply updates
+	var screening *pii.Screening
 	if req.Content != nil || req.Summary != nil || req.Importance != nil {
 		content := m.Content
 		if req.Content != nil {
@@ -133,16 +285,40 @@ func (s *service) UpdateMemory(ctx context.Context, id memory.ID, req memory.Upd
 			metadata = req.Metadata
 		}
 
+		// Only new text is screened; placeholders already in it are kept
+		var texts []*string
+		if req.Content != nil {
+			texts = append(texts, &content)
+		}
+		if req.Summary != nil {
+			texts = append(texts, &summary)
+		}
+		if len(texts) > 0 {
+			if screening, err = s.screen(ctx, m.UserID, texts...); err != nil {
+				return nil, err
+			}
+		}
+
+		// Only growth in content counts against the storage quota
+		if growth := int64(len(content) - len(m.Content)); growth > 0 {
+			if err := s.checkQuota(ctx, m.UserID, 0, growth); err != nil {
+				return nil, err
+			}
+		}
+
 		m.Update(content, summary, importance, tags, metadata)
 
 		// Regenerate embedding if content changed
 		if req.Content != nil && s.embeddingService != nil {
-			embeddingResult, err := s.embeddingService.GenerateEmbedding(ctx, *req.Content)
+			embeddingResult, err := s.embeddingService.GenerateEmbedding(llm.WithUserID(ctx, m.UserID.String()), m.Content)
+			if errors.Is(err, quota.ErrQuotaExceeded) {
+				return nil, quotaError(err)
+			}
 			if err != nil {
 				s.logger.WithError(err).Warn("Failed to regenerate embedding for updated memory")
 				// Continue without updating embedding - this is not a fatal error
 			} else {
-				m.UpdateEmbedding(embeddingResult.Embedding)
+				m.UpdateEmbedding(embeddingResult.Embedding, embeddingResult.Model)
 			}
 		}
 	}
@@ -155,15 +331,46 @@ func (s *service) UpdateMemory(ctx context.Context, id memory.ID, req memory.Upd
 	if err := s.repo.Update(ctx, m); err != nil {
 		return nil, fmt.Errorf("updating memory: %w", err)
 	}
+	if s.pii != nil && (req.Content != nil || req.Summary != nil) {
+		if err := s.pii.SaveTokens(ctx, m, screening); err != nil {
+			return nil, fmt.Errorf("saving personal data tokens: %w", err)
+		}
+	}
 
 	return m, nil
 }
 
+func (s *service) RehydrateMemory(ctx context.Context, id memory.ID) (*memory.Memory, error) {
+	m, err := s.GetMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.pii == nil {
+		return m, nil
+	}
+
+	if err := s.pii.Rehydrate(ctx, m); err != nil {
+		if errors.Is(err, auth.ErrPermissionDenied) {
+			return nil, memory.NewServiceError(memory.ErrCodePermissionDenied, "Only the author may restore personal data", err)
+		}
+		return nil, fmt.Errorf("restoring personal data: %w", err)
+	}
+	return m, nil
+}
+
 func (s *service) DeleteMemory(ctx context.Context, id memory.ID) error {
 	if id.IsZero() {
 		return memory.ErrInvalidID
 	}
 
+	m, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeMemory(ctx, m, space.RoleEditor); err != nil {
+		return err
+	}
+
 	return s.repo.Delete(ctx, id)
 }
 
@@ -171,6 +378,9 @@ func (s *service) ListUserMemories(ctx context.Context, userID user.ID, limit, o
 	if userID.IsZero() {
 		return nil, memory.ErrInvalidUserID
 	}
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
 
 	if limit <= 0 {
 		limit = 20 // default limit
@@ -182,10 +392,39 @@ func (s *service) ListUserMemories(ctx context.Context, userID user.ID, limit, o
 	return s.repo.FindByUserID(ctx, userID, limit, offset)
 }
 
+func (s *service) ListSpaceMemories(ctx context.Context, spaceID space.ID, limit, offset int) ([]*memory.Memory, error) {
+	if spaceID.IsZero() {
+		return nil, memory.NewValidationError("space_id", "space ID is required")
+	}
+	if err := s.authorizeSpace(ctx, spaceID, space.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 20 // default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	// The query checks membership again, so listing never depends on the
+	// check above alone
+	p, _ := auth.PrincipalFromContext(ctx)
+	return s.repo.FindInScope(ctx, memory.Scope{
+		UserID:       p.UserID,
+		Shared:       true,
+		SpaceIDs:     []space.ID{spaceID},
+		Unrestricted: p.IsPrivileged(),
+	}, limit, offset)
+}
+
 func (s *service) SearchMemories(ctx context.Context, req memory.SearchRequest) ([]*memory.Memory, error) {
 	if req.UserID.IsZero() {
 		return nil, memory.ErrInvalidUserID
 	}
+	if err := authorize(ctx, req.UserID); err != nil {
+		return nil, err
+	}
 
 	if req.Limit <= 0 {
 		req.Limit = 20 // default limit
@@ -194,6 +433,10 @@ func (s *service) SearchMemories(ctx context.Context, req memory.SearchRequest)
 		req.Offset = 0
 	}
 
+	if req.IncludeShared || len(req.SpaceIDs) > 0 {
+		return s.searchInScope(ctx, req)
+	}
+
 	// Search by content if query is provided
 	if strings.TrimSpace(req.Query) != "" {
 		return s.repo.SearchByContent(ctx, req.Query, req.UserID, req.Limit, req.Offset)
@@ -208,10 +451,29 @@ func (s *service) SearchMemories(ctx context.Context, req memory.SearchRequest)
 	return s.repo.FindByUserID(ctx, req.UserID, req.Limit, req.Offset)
 }
 
+// searchInScope searches the user's personal memories together with those of
+// their shared spaces
+func (s *service) searchInScope(ctx context.Context, req memory.SearchRequest) ([]*memory.Memory, error) {
+	scope := s.sharedScope(req.UserID, req.SpaceIDs)
+
+	if strings.TrimSpace(req.Query) != "" {
+		return s.repo.SearchByContentInScope(ctx, req.Query, scope, req.Limit, req.Offset)
+	}
+
+	if len(req.Tags) > 0 {
+		return s.repo.FindByTagsInScope(ctx, req.Tags, scope, req.Limit, req.Offset)
+	}
+
+	return s.repo.FindInScope(ctx, scope, req.Limit, req.Offset)
+}
+
 func (s *service) SearchSimilarMemories(ctx context.Context, content string, userID user.ID, limit int, threshold float64) ([]*memory.Memory, error) {
 	if userID.IsZero() {
 		return nil, memory.ErrInvalidUserID
 	}
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
 
 	if strings.TrimSpace(content) == "" {
 		return nil, memory.ErrInvalidContent
@@ -231,7 +493,10 @@ func (s *service) SearchSimilarMemories(ctx context.Context, content string, use
 		return []*memory.Memory{}, nil
 	}
 
-	embeddingResult, err := s.embeddingService.GenerateEmbedding(ctx, content)
+	embeddingResult, err := s.embeddingService.GenerateEmbedding(llm.WithUserID(ctx, userID.String()), content)
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		return nil, quotaError(err)
+	}
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to generate embedding for similarity search")
 		return nil, fmt.Errorf("generating embedding: %w", err)
@@ -241,14 +506,175 @@ func (s *service) SearchSimilarMemories(ctx context.Context, content string, use
 	return s.repo.SearchSimilar(ctx, embeddingResult.Embedding, userID, limit, threshold)
 }
 
+func (s *service) SearchSimilarAcrossSpaces(ctx context.Context, content string, userID user.ID, spaceIDs []space.ID, limit int, threshold float64) ([]*memory.Memory, error) {
+	if userID.IsZero() {
+		return nil, memory.ErrInvalidUserID
+	}
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(content) == "" {
+		return nil, memory.ErrInvalidContent
+	}
+
+	if limit <= 0 {
+		limit = 10 // default limit
+	}
+
+	if threshold <= 0 {
+		threshold = 0.8 // default threshold
+	}
+
+	if s.embeddingService == nil {
+		s.logger.Warn("Embedding service not available for similarity search")
+		return []*memory.Memory{}, nil
+	}
+
+	embeddingResult, err := s.embeddingService.GenerateEmbedding(llm.WithUserID(ctx, userID.String()), content)
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		return nil, quotaError(err)
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to generate embedding for similarity search")
+		return nil, fmt.Errorf("generating embedding: %w", err)
+	}
+
+	return s.repo.SearchSimilarInScope(ctx, embeddingResult.Embedding, s.sharedScope(userID, spaceIDs), limit, threshold)
+}
+
 func (s *service) GetMemoryStats(ctx context.Context, userID user.ID) (*memory.Stats, error) {
 	if userID.IsZero() {
 		return nil, memory.ErrInvalidUserID
 	}
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
 
 	return s.repo.GetStatsByUserID(ctx, userID)
 }
 
+// checkQuota verifies that count more memories of bytes total content fit in
+// the user's quota. The memories are stored after the check, outside any
+// lock, so the limit is best-effort under concurrent creates.
+func (s *service) checkQuota(ctx context.Context, userID user.ID, count int, bytes int64) error {
+	if s.quotas == nil {
+		return nil
+	}
+
+	err := s.quotas.CheckMemories(ctx, userID, count, bytes)
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		return quotaError(err)
+	}
+	if err != nil {
+		return fmt.Errorf("checking quota: %w", err)
+	}
+	return nil
+}
+
+// quotaError converts a quota violation into a service error naming the resource
+func quotaError(err error) error {
+	message := "Quota exceeded"
+	var exceeded *quota.ExceededError
+	if errors.As(err, &exceeded) {
+		message = fmt.Sprintf("Quota exceeded for %s", exceeded.Resource)
+	}
+	return memory.NewServiceError(memory.ErrCodeQuotaExceeded, message, err)
+}
+
+// screen applies the personal data policy of userID to texts in place
+func (s *service) screen(ctx context.Context, userID user.ID, texts ...*string) (*pii.Screening, error) {
+	if s.pii == nil {
+		return nil, nil
+	}
+
+	screening, err := s.pii.Screen(ctx, userID, texts...)
+	var detected *pii.DetectedError
+	if errors.As(err, &detected) {
+		return nil, memory.NewServiceError(memory.ErrCodePIIDetected,
+			fmt.Sprintf("Memory contains personal data (%s)", strings.Join(detected.Kinds, ", ")), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("screening memory for personal data: %w", err)
+	}
+	return screening, nil
+}
+
+// saveTokens stores the vault tokens of newly stored memories. If that
+// fails the memories are deleted again, as their placeholders could never
+// be restored.
+func (s *service) saveTokens(ctx context.Context, memories []*memory.Memory, screenings []*pii.Screening) error {
+	for i, m := range memories {
+		if screenings[i] == nil || len(screenings[i].Tokens) == 0 {
+			continue
+		}
+		if err := s.pii.SaveTokens(ctx, m, screenings[i]); err != nil {
+			for _, stored := range memories {
+				if deleteErr := s.repo.Delete(ctx, stored.ID); deleteErr != nil {
+					s.logger.WithError(deleteErr).WithField("memory_id", stored.ID.String()).Error("Failed to delete memory after saving personal data tokens failed")
+				}
+			}
+			return fmt.Errorf("saving personal data tokens: %w", err)
+		}
+	}
+	return nil
+}
+
+// authorize checks that the caller may access memories of owner
+func authorize(ctx context.Context, owner user.ID) error {
+	if err := auth.Authorize(ctx, owner); err != nil {
+		return memory.NewServiceError(memory.ErrCodePermissionDenied, "Access to this user's memories is not allowed", err)
+	}
+	return nil
+}
+
+// authorizeMemory checks that the caller may access m with at least role:
+// personal memories are their author's, shared ones need space membership
+func (s *service) authorizeMemory(ctx context.Context, m *memory.Memory, role string) error {
+	if !m.IsShared() {
+		return authorize(ctx, m.UserID)
+	}
+	return s.authorizeSpace(ctx, m.SpaceID, role)
+}
+
+// authorizeSpace checks that the caller is a member of the space with at
+// least role; admin and system callers may access every space
+func (s *service) authorizeSpace(ctx context.Context, spaceID space.ID, role string) error {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return memory.NewServiceError(memory.ErrCodePermissionDenied, "Access to this space is not allowed", auth.ErrPermissionDenied)
+	}
+	if p.IsPrivileged() {
+		return nil
+	}
+	if s.spaces == nil {
+		return memory.NewServiceError(memory.ErrCodePermissionDenied, "Shared spaces are not enabled", auth.ErrPermissionDenied)
+	}
+
+	member, err := s.spaces.FindMember(ctx, spaceID, p.UserID)
+	if errors.Is(err, space.ErrNotMember) {
+		return memory.NewServiceError(memory.ErrCodePermissionDenied, "Access to this space is not allowed", auth.ErrPermissionDenied)
+	}
+	if err != nil {
+		return fmt.Errorf("checking space membership: %w", err)
+	}
+	if !member.Has(role) {
+		return memory.NewServiceError(memory.ErrCodePermissionDenied, "Your role in this space does not allow this", auth.ErrPermissionDenied)
+	}
+	return nil
+}
+
+// sharedScope is the scope of userID's personal memories and their shared
+// spaces, limited to spaceIDs when set
+func (s *service) sharedScope(userID user.ID, spaceIDs []space.ID) memory.Scope {
+	return memory.Scope{
+		UserID:   userID,
+		Personal: true,
+		Shared:   s.spaces != nil,
+		SpaceIDs: spaceIDs,
+	}
+}
+
 // Validation helpers
 func (s *service) validateCreateRequest(req memory.CreateRequest) error {
 	if req.UserID.IsZero() {
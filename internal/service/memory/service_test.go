@@ -9,11 +9,18 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"mem_bank/internal/domain/auth"
 	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/pii"
+	"mem_bank/internal/domain/quota"
+	"mem_bank/internal/domain/space"
 	"mem_bank/internal/domain/user"
 	"mem_bank/internal/service/embedding"
+	piiService "mem_bank/internal/service/pii"
 	"mem_bank/pkg/logger"
+	pkgpii "mem_bank/pkg/pii"
 )
 
 // Mock repository
@@ -92,6 +99,38 @@ func (m *mockMemoryRepository) FindByTags(ctx context.Context, tags []string, us
 	return args.Get(0).([]*memory.Memory), args.Error(1)
 }
 
+func (m *mockMemoryRepository) FindInScope(ctx context.Context, scope memory.Scope, limit, offset int) ([]*memory.Memory, error) {
+	args := m.Called(ctx, scope, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*memory.Memory), args.Error(1)
+}
+
+func (m *mockMemoryRepository) SearchByContentInScope(ctx context.Context, query string, scope memory.Scope, limit, offset int) ([]*memory.Memory, error) {
+	args := m.Called(ctx, query, scope, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*memory.Memory), args.Error(1)
+}
+
+func (m *mockMemoryRepository) FindByTagsInScope(ctx context.Context, tags []string, scope memory.Scope, limit, offset int) ([]*memory.Memory, error) {
+	args := m.Called(ctx, tags, scope, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*memory.Memory), args.Error(1)
+}
+
+func (m *mockMemoryRepository) SearchSimilarInScope(ctx context.Context, emb []float32, scope memory.Scope, limit int, threshold float64) ([]*memory.Memory, error) {
+	args := m.Called(ctx, emb, scope, limit, threshold)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*memory.Memory), args.Error(1)
+}
+
 func (m *mockMemoryRepository) UpdateAccessInfo(ctx context.Context, id memory.ID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -110,6 +149,22 @@ func (m *mockMemoryRepository) CountByUserID(ctx context.Context, userID user.ID
 	return args.Int(0), args.Error(1)
 }
 
+func (m *mockMemoryRepository) FindWithoutEmbedding(ctx context.Context, userID user.ID, model string, limit int, cursor memory.ID) ([]*memory.Memory, error) {
+	args := m.Called(ctx, userID, model, limit, cursor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*memory.Memory), args.Error(1)
+}
+
+func (m *mockMemoryRepository) GetEmbeddingCoverage(ctx context.Context, userID user.ID, model string) (*memory.EmbeddingCoverage, error) {
+	args := m.Called(ctx, userID, model)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*memory.EmbeddingCoverage), args.Error(1)
+}
+
 func (m *mockMemoryRepository) BatchStore(ctx context.Context, memories []*memory.Memory) error {
 	args := m.Called(ctx, memories)
 	return args.Error(0)
@@ -285,6 +340,15 @@ func (m *mockLogger) Fatalf(format string, args ...interface{}) {
 	m.Called(format, args)
 }
 
+// systemContext returns a context whose caller may access every user's memories
+func systemContext() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{Role: auth.RoleSystem})
+}
+
+func userContext(id user.ID) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: id, Role: auth.RoleUser})
+}
+
 func TestService_CreateMemory(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -399,10 +463,10 @@ func TestService_CreateMemory(t *testing.T) {
 			// Mock logger calls (no embedding calls expected since service is nil)
 
 			// Create service
-			svc := NewService(memRepo, userRepo, nil, logger)
+			svc := NewService(memRepo, userRepo, nil, nil, nil, nil, logger)
 
 			// Execute test
-			result, err := svc.CreateMemory(context.Background(), tt.req)
+			result, err := svc.CreateMemory(systemContext(), tt.req)
 
 			// Assertions
 			if tt.wantErr {
@@ -499,10 +563,10 @@ func TestService_GetMemory(t *testing.T) {
 			}
 
 			// Create service
-			svc := NewService(memRepo, userRepo, nil, logger)
+			svc := NewService(memRepo, userRepo, nil, nil, nil, nil, logger)
 
 			// Execute test
-			result, err := svc.GetMemory(context.Background(), tt.memoryID)
+			result, err := svc.GetMemory(systemContext(), tt.memoryID)
 
 			// Assertions
 			if tt.wantErr {
@@ -598,13 +662,13 @@ func TestService_SearchSimilarMemories(t *testing.T) {
 			}
 
 			// Create service
-			svc := NewService(memRepo, nil, nil, logger)
+			svc := NewService(memRepo, nil, nil, nil, nil, nil, logger)
 
 			// Mock logger for embedding service not available
 			logger.On("Warn", "Embedding service not available for similarity search").Maybe()
 
 			// Execute test
-			result, err := svc.SearchSimilarMemories(context.Background(), tt.content, tt.userID, tt.limit, tt.threshold)
+			result, err := svc.SearchSimilarMemories(systemContext(), tt.content, tt.userID, tt.limit, tt.threshold)
 
 			// Assertions
 			if tt.wantErr {
@@ -621,3 +685,316 @@ func TestService_SearchSimilarMemories(t *testing.T) {
 		})
 	}
 }
+
+// Mock quota service
+type mockQuotaService struct {
+	mock.Mock
+}
+
+func (m *mockQuotaService) GetStatus(ctx context.Context, userID user.ID) (*quota.Status, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*quota.Status), args.Error(1)
+}
+
+func (m *mockQuotaService) UpdateQuota(ctx context.Context, userID user.ID, req quota.UpdateRequest) (*quota.Status, error) {
+	args := m.Called(ctx, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*quota.Status), args.Error(1)
+}
+
+func (m *mockQuotaService) CheckMemories(ctx context.Context, userID user.ID, count int, bytes int64) error {
+	args := m.Called(ctx, userID, count, bytes)
+	return args.Error(0)
+}
+
+func (m *mockQuotaService) CheckTokens(ctx context.Context, userID user.ID, operation string, tokens int) error {
+	args := m.Called(ctx, userID, operation, tokens)
+	return args.Error(0)
+}
+
+func TestService_CreateMemory_QuotaExceeded(t *testing.T) {
+	memRepo := &mockMemoryRepository{}
+	userRepo := &mockUserRepository{}
+	quotas := &mockQuotaService{}
+
+	userID := user.ID(uuid.New())
+	userRepo.On("FindByID", mock.Anything, userID).Return(&user.User{ID: userID}, nil)
+	quotas.On("CheckMemories", mock.Anything, userID, 1, int64(len("Test content"))).Return(&quota.ExceededError{
+		Resource: quota.ResourceMemories,
+		Limit:    10,
+		Used:     10,
+	})
+
+	svc := NewService(memRepo, userRepo, nil, nil, quotas, nil, &mockLogger{})
+	result, err := svc.CreateMemory(systemContext(), memory.CreateRequest{
+		UserID:     userID,
+		Content:    "Test content",
+		Importance: 5,
+		MemoryType: "general",
+	})
+
+	assert.Nil(t, result)
+	var serviceErr *memory.ServiceError
+	if assert.ErrorAs(t, err, &serviceErr) {
+		assert.Equal(t, memory.ErrCodeQuotaExceeded, serviceErr.Code)
+	}
+	assert.ErrorIs(t, err, quota.ErrQuotaExceeded)
+	memRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+}
+
+func TestService_CreateMemory_PersonalData(t *testing.T) {
+	const content = "Reach me at jane@example.com"
+
+	for _, policy := range []string{pii.PolicyReject, pii.PolicyRedact} {
+		t.Run(policy, func(t *testing.T) {
+			memRepo := &mockMemoryRepository{}
+			userRepo := &mockUserRepository{}
+			quotas := &mockQuotaService{}
+
+			userID := user.ID(uuid.New())
+			userRepo.On("FindByID", mock.Anything, userID).Return(&user.User{ID: userID, Settings: user.Settings{PIIPolicy: policy}}, nil)
+			redacted := "Reach me at " + pii.RedactedPlaceholder(pkgpii.KindEmail)
+			quotas.On("CheckMemories", mock.Anything, userID, 1, int64(len(redacted))).Return(nil)
+			memRepo.On("Store", mock.Anything, mock.AnythingOfType("*memory.Memory")).Return(nil)
+
+			log := &mockLogger{}
+			log.On("Info", mock.Anything).Return()
+			screener := piiService.NewService(pkgpii.NewDetector(pkgpii.BuiltinRules()...), nil, userRepo, pii.PolicyAllow, log)
+			svc := NewService(memRepo, userRepo, nil, nil, quotas, screener, &mockLogger{})
+			result, err := svc.CreateMemory(systemContext(), memory.CreateRequest{
+				UserID:     userID,
+				Content:    content,
+				Importance: 5,
+				MemoryType: "general",
+			})
+
+			if policy == pii.PolicyReject {
+				var serviceErr *memory.ServiceError
+				if assert.ErrorAs(t, err, &serviceErr) {
+					assert.Equal(t, memory.ErrCodePIIDetected, serviceErr.Code)
+				}
+				assert.ErrorIs(t, err, pii.ErrDetected)
+				memRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, redacted, result.Content)
+			quotas.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_BatchCreateMemories(t *testing.T) {
+	memRepo := &mockMemoryRepository{}
+	userRepo := &mockUserRepository{}
+	quotas := &mockQuotaService{}
+
+	alice, bob := user.ID(uuid.New()), user.ID(uuid.New())
+	userRepo.On("FindByID", mock.Anything, alice).Return(&user.User{ID: alice}, nil)
+	userRepo.On("FindByID", mock.Anything, bob).Return(&user.User{ID: bob}, nil)
+	quotas.On("CheckMemories", mock.Anything, alice, 2, int64(len("one")+len("three"))).Return(nil)
+	quotas.On("CheckMemories", mock.Anything, bob, 1, int64(len("two"))).Return(nil)
+	memRepo.On("BatchStore", mock.Anything, mock.MatchedBy(func(memories []*memory.Memory) bool {
+		return len(memories) == 3
+	})).Return(nil)
+
+	svc := NewService(memRepo, userRepo, nil, nil, quotas, nil, &mockLogger{})
+	result, err := svc.BatchCreateMemories(systemContext(), []memory.CreateRequest{
+		{UserID: alice, Content: "one", Importance: 5, MemoryType: "general"},
+		{UserID: bob, Content: "two", Importance: 5, MemoryType: "general"},
+		{UserID: alice, Content: "three", Importance: 5, MemoryType: "general"},
+	})
+
+	assert.NoError(t, err)
+	if assert.Len(t, result, 3) {
+		assert.Equal(t, "two", result[1].Content)
+		assert.Equal(t, bob, result[1].UserID)
+	}
+	quotas.AssertExpectations(t)
+	memRepo.AssertExpectations(t)
+}
+
+func TestService_BatchCreateMemories_QuotaExceededStoresNothing(t *testing.T) {
+	memRepo := &mockMemoryRepository{}
+	userRepo := &mockUserRepository{}
+	quotas := &mockQuotaService{}
+
+	userID := user.ID(uuid.New())
+	userRepo.On("FindByID", mock.Anything, userID).Return(&user.User{ID: userID}, nil)
+	quotas.On("CheckMemories", mock.Anything, userID, 2, mock.Anything).Return(&quota.ExceededError{
+		Resource: quota.ResourceStorageBytes,
+	})
+
+	svc := NewService(memRepo, userRepo, nil, nil, quotas, nil, &mockLogger{})
+	_, err := svc.BatchCreateMemories(systemContext(), []memory.CreateRequest{
+		{UserID: userID, Content: "one", Importance: 5, MemoryType: "general"},
+		{UserID: userID, Content: "two", Importance: 5, MemoryType: "general"},
+	})
+
+	assert.ErrorIs(t, err, quota.ErrQuotaExceeded)
+	memRepo.AssertNotCalled(t, "BatchStore", mock.Anything, mock.Anything)
+}
+
+func assertPermissionDenied(t *testing.T, err error) {
+	t.Helper()
+	var serviceErr *memory.ServiceError
+	if assert.ErrorAs(t, err, &serviceErr) {
+		assert.Equal(t, memory.ErrCodePermissionDenied, serviceErr.Code)
+	}
+	assert.ErrorIs(t, err, auth.ErrPermissionDenied)
+}
+
+func TestService_Authorization(t *testing.T) {
+	owner, other := user.ID(uuid.New()), user.ID(uuid.New())
+	m := &memory.Memory{ID: memory.ID(uuid.New()), UserID: owner, Content: "secret", Importance: 5, MemoryType: "general"}
+
+	memRepo := &mockMemoryRepository{}
+	memRepo.On("FindByID", mock.Anything, m.ID).Return(m, nil)
+	memRepo.On("UpdateAccessInfo", mock.Anything, m.ID).Return(nil)
+	memRepo.On("FindByUserID", mock.Anything, owner, 20, 0).Return([]*memory.Memory{m}, nil)
+	memRepo.On("Delete", mock.Anything, m.ID).Return(nil)
+
+	svc := NewService(memRepo, &mockUserRepository{}, nil, nil, nil, nil, &mockLogger{})
+
+	t.Run("owner", func(t *testing.T) {
+		ctx := userContext(owner)
+		_, err := svc.GetMemory(ctx, m.ID)
+		assert.NoError(t, err)
+		_, err = svc.ListUserMemories(ctx, owner, 20, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: other, Role: auth.RoleAdmin})
+		_, err := svc.GetMemory(ctx, m.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		ctx := userContext(other)
+		_, err := svc.GetMemory(ctx, m.ID)
+		assertPermissionDenied(t, err)
+		_, err = svc.UpdateMemory(ctx, m.ID, memory.UpdateRequest{})
+		assertPermissionDenied(t, err)
+		assertPermissionDenied(t, svc.DeleteMemory(ctx, m.ID))
+		_, err = svc.ListUserMemories(ctx, owner, 20, 0)
+		assertPermissionDenied(t, err)
+		_, err = svc.SearchMemories(ctx, memory.SearchRequest{UserID: owner, Query: "secret"})
+		assertPermissionDenied(t, err)
+		_, err = svc.SearchSimilarMemories(ctx, "secret", owner, 10, 0.8)
+		assertPermissionDenied(t, err)
+		_, err = svc.GetMemoryStats(ctx, owner)
+		assertPermissionDenied(t, err)
+		_, err = svc.CreateMemory(ctx, memory.CreateRequest{UserID: owner, Content: "planted", Importance: 5, MemoryType: "general"})
+		assertPermissionDenied(t, err)
+
+		// A batch is refused whole when any memory belongs to someone else
+		_, err = svc.BatchCreateMemories(ctx, []memory.CreateRequest{
+			{UserID: other, Content: "mine", Importance: 5, MemoryType: "general"},
+			{UserID: owner, Content: "planted", Importance: 5, MemoryType: "general"},
+		})
+		assertPermissionDenied(t, err)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		_, err := svc.GetMemory(context.Background(), m.ID)
+		assertPermissionDenied(t, err)
+	})
+
+	memRepo.AssertNumberOfCalls(t, "Delete", 0)
+	memRepo.AssertNumberOfCalls(t, "UpdateAccessInfo", 2)
+	memRepo.AssertNotCalled(t, "BatchStore", mock.Anything, mock.Anything)
+}
+
+// Mock space repository; only membership lookups are used by the memory service
+type mockSpaceRepository struct {
+	space.Repository
+	mock.Mock
+}
+
+func (m *mockSpaceRepository) FindMember(ctx context.Context, spaceID space.ID, userID user.ID) (*space.Member, error) {
+	args := m.Called(ctx, spaceID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*space.Member), args.Error(1)
+}
+
+func TestService_SpaceAuthorization(t *testing.T) {
+	author, editor, viewer, outsider := user.ID(uuid.New()), user.ID(uuid.New()), user.ID(uuid.New()), user.ID(uuid.New())
+	spaceID := space.NewID()
+	m := &memory.Memory{ID: memory.ID(uuid.New()), UserID: author, SpaceID: spaceID, Content: "team notes", Importance: 5, MemoryType: "general"}
+
+	spaces := &mockSpaceRepository{}
+	spaces.On("FindMember", mock.Anything, spaceID, author).Return(&space.Member{SpaceID: spaceID, UserID: author, Role: space.RoleOwner}, nil)
+	spaces.On("FindMember", mock.Anything, spaceID, editor).Return(&space.Member{SpaceID: spaceID, UserID: editor, Role: space.RoleEditor}, nil)
+	spaces.On("FindMember", mock.Anything, spaceID, viewer).Return(&space.Member{SpaceID: spaceID, UserID: viewer, Role: space.RoleViewer}, nil)
+	spaces.On("FindMember", mock.Anything, spaceID, outsider).Return(nil, space.ErrNotMember)
+
+	memRepo := &mockMemoryRepository{}
+	memRepo.On("FindByID", mock.Anything, m.ID).Return(m, nil)
+	memRepo.On("UpdateAccessInfo", mock.Anything, m.ID).Return(nil)
+	memRepo.On("Update", mock.Anything, m).Return(nil)
+	memRepo.On("Store", mock.Anything, mock.Anything).Return(nil)
+	memRepo.On("FindInScope", mock.Anything, mock.Anything, 20, 0).Return([]*memory.Memory{m}, nil)
+
+	userRepo := &mockUserRepository{}
+	userRepo.On("FindByID", mock.Anything, mock.Anything).Return(&user.User{}, nil)
+
+	svc := NewService(memRepo, userRepo, spaces, nil, nil, nil, &mockLogger{})
+
+	t.Run("members read, editors write", func(t *testing.T) {
+		_, err := svc.GetMemory(userContext(viewer), m.ID)
+		assert.NoError(t, err)
+		_, err = svc.UpdateMemory(userContext(editor), m.ID, memory.UpdateRequest{})
+		assert.NoError(t, err)
+
+		created, err := svc.CreateMemory(userContext(editor), memory.CreateRequest{UserID: editor, SpaceID: spaceID, Content: "shared", Importance: 5, MemoryType: "general"})
+		assert.NoError(t, err)
+		assert.Equal(t, spaceID, created.SpaceID)
+	})
+
+	t.Run("viewer cannot write", func(t *testing.T) {
+		ctx := userContext(viewer)
+		_, err := svc.UpdateMemory(ctx, m.ID, memory.UpdateRequest{})
+		assertPermissionDenied(t, err)
+		assertPermissionDenied(t, svc.DeleteMemory(ctx, m.ID))
+		_, err = svc.CreateMemory(ctx, memory.CreateRequest{UserID: viewer, SpaceID: spaceID, Content: "shared", Importance: 5, MemoryType: "general"})
+		assertPermissionDenied(t, err)
+	})
+
+	t.Run("non-member has no access", func(t *testing.T) {
+		ctx := userContext(outsider)
+		_, err := svc.GetMemory(ctx, m.ID)
+		assertPermissionDenied(t, err)
+		_, err = svc.ListSpaceMemories(ctx, spaceID, 20, 0)
+		assertPermissionDenied(t, err)
+	})
+
+	t.Run("space listing is scoped to the caller's membership", func(t *testing.T) {
+		_, err := svc.ListSpaceMemories(userContext(viewer), spaceID, 20, 0)
+		assert.NoError(t, err)
+		memRepo.AssertCalled(t, "FindInScope", mock.Anything, memory.Scope{
+			UserID:   viewer,
+			Shared:   true,
+			SpaceIDs: []space.ID{spaceID},
+		}, 20, 0)
+	})
+
+	t.Run("search spans personal and shared spaces", func(t *testing.T) {
+		_, err := svc.SearchMemories(userContext(viewer), memory.SearchRequest{UserID: viewer, IncludeShared: true})
+		assert.NoError(t, err)
+		memRepo.AssertCalled(t, "FindInScope", mock.Anything, memory.Scope{
+			UserID:   viewer,
+			Personal: true,
+			Shared:   true,
+		}, 20, 0)
+	})
+
+	memRepo.AssertNumberOfCalls(t, "Delete", 0)
+}
@@ -0,0 +1,213 @@
+package pii
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/pii"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+	"mem_bank/pkg/logger"
+	pkgpii "mem_bank/pkg/pii"
+)
+
+// tokenBytes is the size of the random part of a vault token
+const tokenBytes = 8
+
+// Service implements pii.Service
+type Service struct {
+	detector      *pkgpii.Detector
+	vault         pii.Vault
+	userRepo      user.Repository
+	defaultPolicy string
+	logger        logger.Logger
+	now           func() time.Time
+}
+
+// NewService creates a new personal data service. defaultPolicy applies to
+// users who have not chosen a policy in their settings.
+func NewService(detector *pkgpii.Detector, vault pii.Vault, userRepo user.Repository, defaultPolicy string, logger logger.Logger) *Service {
+	return &Service{
+		detector:      detector,
+		vault:         vault,
+		userRepo:      userRepo,
+		defaultPolicy: defaultPolicy,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+func (s *Service) Screen(ctx context.Context, userID user.ID, texts ...*string) (*pii.Screening, error) {
+	policy, err := s.policy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	screening := &pii.Screening{Policy: policy}
+	if policy == pii.PolicyAllow {
+		return screening, nil
+	}
+
+	found := make([][]pkgpii.Match, len(texts))
+	seen := make(map[string]bool)
+	for i, text := range texts {
+		if text == nil || *text == "" {
+			continue
+		}
+		found[i] = s.detector.Detect(*text)
+		for _, m := range found[i] {
+			if !seen[m.Kind] {
+				seen[m.Kind] = true
+				screening.Kinds = append(screening.Kinds, m.Kind)
+			}
+		}
+	}
+	if len(screening.Kinds) == 0 {
+		return screening, nil
+	}
+
+	switch policy {
+	case pii.PolicyReject:
+		return nil, &pii.DetectedError{Kinds: screening.Kinds}
+	case pii.PolicyRedact:
+		for i, text := range texts {
+			if len(found[i]) > 0 {
+				*text = pkgpii.Replace(*text, found[i], func(m pkgpii.Match) string {
+					return pii.RedactedPlaceholder(m.Kind)
+				})
+			}
+		}
+	case pii.PolicyTokenize:
+		// The same value gets the same token wherever it appears in the memory
+		placeholders := make(map[string]string)
+		for i, text := range texts {
+			if len(found[i]) == 0 {
+				continue
+			}
+			var tokenErr error
+			*text = pkgpii.Replace(*text, found[i], func(m pkgpii.Match) string {
+				key := m.Kind + "\x00" + m.Value
+				if placeholder, ok := placeholders[key]; ok {
+					return placeholder
+				}
+				token, err := newToken()
+				if err != nil {
+					tokenErr = err
+					return ""
+				}
+				screening.Tokens = append(screening.Tokens, pii.Token{
+					UserID:    userID,
+					Token:     token,
+					Kind:      m.Kind,
+					Value:     m.Value,
+					CreatedAt: s.now(),
+				})
+				placeholders[key] = pii.Placeholder(m.Kind, token)
+				return placeholders[key]
+			})
+			if tokenErr != nil {
+				return nil, tokenErr
+			}
+		}
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"user_id": userID.String(),
+		"policy":  policy,
+		"kinds":   screening.Kinds,
+	}).Info("Personal data screened out of memory")
+	return screening, nil
+}
+
+func (s *Service) SaveTokens(ctx context.Context, m *memory.Memory, screening *pii.Screening) error {
+	// The memory service has authorized the write. An editor of a shared
+	// space cannot see the author's tokens, so the vault is used on the
+	// author's behalf.
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+
+	var added []pii.Token
+	if screening != nil {
+		added = screening.Tokens
+	}
+
+	existing, err := s.vault.FindByMemoryID(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	if len(added) == 0 && len(existing) == 0 {
+		return nil
+	}
+
+	// Tokens whose placeholder was edited out of the memory are dropped
+	tokens := make([]pii.Token, 0, len(existing)+len(added))
+	for _, t := range existing {
+		if refersTo(m, t.Placeholder()) {
+			tokens = append(tokens, t)
+		}
+	}
+	if len(added) == 0 && len(tokens) == len(existing) {
+		return nil
+	}
+	for _, t := range added {
+		t.MemoryID = m.ID
+		t.UserID = m.UserID
+		tokens = append(tokens, t)
+	}
+	return s.vault.Replace(ctx, m.ID, tokens)
+}
+
+func (s *Service) Rehydrate(ctx context.Context, m *memory.Memory) error {
+	if err := auth.Authorize(ctx, m.UserID); err != nil {
+		return err
+	}
+	if !strings.Contains(m.Content, "[PII:") && !strings.Contains(m.Summary, "[PII:") {
+		return nil
+	}
+
+	tokens, err := s.vault.FindByMemoryID(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	pairs := make([]string, 0, 2*len(tokens))
+	for _, t := range tokens {
+		pairs = append(pairs, t.Placeholder(), t.Value)
+	}
+	replacer := strings.NewReplacer(pairs...)
+	m.Content = replacer.Replace(m.Content)
+	m.Summary = replacer.Replace(m.Summary)
+	return nil
+}
+
+// policy returns the policy chosen by userID, or the default
+func (s *Service) policy(ctx context.Context, userID user.ID) (string, error) {
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("getting personal data policy: %w", err)
+	}
+	if u.Settings.PIIPolicy != "" {
+		return u.Settings.PIIPolicy, nil
+	}
+	return s.defaultPolicy, nil
+}
+
+func refersTo(m *memory.Memory, placeholder string) bool {
+	return strings.Contains(m.Content, placeholder) || strings.Contains(m.Summary, placeholder)
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating personal data token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,355 @@
+package pii
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/pii"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/database"
+	"mem_bank/pkg/logger"
+	pkgpii "mem_bank/pkg/pii"
+)
+
+// Mock vault
+type mockVault struct {
+	mock.Mock
+}
+
+func (m *mockVault) Replace(ctx context.Context, memoryID memory.ID, tokens []pii.Token) error {
+	args := m.Called(ctx, memoryID, tokens)
+	return args.Error(0)
+}
+
+func (m *mockVault) FindByMemoryID(ctx context.Context, memoryID memory.ID) ([]pii.Token, error) {
+	args := m.Called(ctx, memoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]pii.Token), args.Error(1)
+}
+
+// Mock user repository
+type mockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id user.ID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+func newTestService(vault *mockVault, userRepo *mockUserRepository, log *mockLogger) *Service {
+	detector := pkgpii.NewDetector(pkgpii.BuiltinRules()...)
+	return NewService(detector, vault, userRepo, pii.PolicyAllow, log)
+}
+
+// bypassesTenancy matches contexts that bypass row-level security
+func bypassesTenancy(ctx context.Context) bool {
+	tenant, _ := database.TenantFromContext(ctx)
+	return tenant.Bypass
+}
+
+func TestService_Screen(t *testing.T) {
+	const text = "Mail jane@example.com or call +1 415 555 2671"
+	userID := user.ID(uuid.New())
+	withPolicy := func(policy string) *user.User {
+		return &user.User{ID: userID, Settings: user.Settings{PIIPolicy: policy}}
+	}
+
+	testCases := []struct {
+		name        string
+		text        string
+		setupMocks  func(*mockUserRepository, *mockLogger)
+		wantText    string
+		wantKinds   []string
+		wantTokens  int
+		wantErr     error
+		wantDetails []string
+	}{
+		{
+			name: "default policy allows",
+			text: text,
+			setupMocks: func(r *mockUserRepository, l *mockLogger) {
+				r.On("FindByID", mock.Anything, userID).Return(withPolicy(""), nil)
+			},
+			wantText: text,
+		},
+		{
+			name: "allow",
+			text: text,
+			setupMocks: func(r *mockUserRepository, l *mockLogger) {
+				r.On("FindByID", mock.Anything, userID).Return(withPolicy(pii.PolicyAllow), nil)
+			},
+			wantText: text,
+		},
+		{
+			name: "redact",
+			text: text,
+			setupMocks: func(r *mockUserRepository, l *mockLogger) {
+				r.On("FindByID", mock.Anything, userID).Return(withPolicy(pii.PolicyRedact), nil)
+				l.On("Info", "Personal data screened out of memory").Once()
+			},
+			wantText:  "Mail [REDACTED:EMAIL] or call [REDACTED:PHONE]",
+			wantKinds: []string{pkgpii.KindEmail, pkgpii.KindPhone},
+		},
+		{
+			name: "tokenize",
+			text: "Mail jane@example.com, again jane@example.com",
+			setupMocks: func(r *mockUserRepository, l *mockLogger) {
+				r.On("FindByID", mock.Anything, userID).Return(withPolicy(pii.PolicyTokenize), nil)
+				l.On("Info", "Personal data screened out of memory").Once()
+			},
+			wantKinds:  []string{pkgpii.KindEmail},
+			wantTokens: 1,
+		},
+		{
+			name: "redact without personal data",
+			text: "Nothing to see here",
+			setupMocks: func(r *mockUserRepository, l *mockLogger) {
+				r.On("FindByID", mock.Anything, userID).Return(withPolicy(pii.PolicyRedact), nil)
+			},
+			wantText: "Nothing to see here",
+		},
+		{
+			name: "reject",
+			text: text,
+			setupMocks: func(r *mockUserRepository, l *mockLogger) {
+				r.On("FindByID", mock.Anything, userID).Return(withPolicy(pii.PolicyReject), nil)
+			},
+			wantText:    text,
+			wantErr:     pii.ErrDetected,
+			wantDetails: []string{pkgpii.KindEmail, pkgpii.KindPhone},
+		},
+		{
+			name: "user not found",
+			text: text,
+			setupMocks: func(r *mockUserRepository, l *mockLogger) {
+				r.On("FindByID", mock.Anything, userID).Return(nil, user.ErrNotFound)
+			},
+			wantText: text,
+			wantErr:  user.ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := &mockUserRepository{}
+			log := &mockLogger{}
+			tc.setupMocks(userRepo, log)
+			content := tc.text
+
+			screening, err := newTestService(&mockVault{}, userRepo, log).Screen(context.Background(), userID, &content)
+
+			userRepo.AssertExpectations(t)
+			log.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				if tc.wantDetails != nil {
+					var detected *pii.DetectedError
+					require.ErrorAs(t, err, &detected)
+					assert.Equal(t, tc.wantDetails, detected.Kinds)
+				}
+				assert.Equal(t, tc.wantText, content)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantKinds, screening.Kinds)
+			require.Len(t, screening.Tokens, tc.wantTokens)
+			if tc.wantTokens == 0 {
+				assert.Equal(t, tc.wantText, content)
+				return
+			}
+
+			// A repeated value gets one token
+			placeholder := screening.Tokens[0].Placeholder()
+			assert.Equal(t, "Mail "+placeholder+", again "+placeholder, content)
+			assert.Equal(t, "jane@example.com", screening.Tokens[0].Value)
+			assert.Equal(t, userID, screening.Tokens[0].UserID)
+		})
+	}
+}
+
+func TestService_SaveTokens(t *testing.T) {
+	userID := user.ID(uuid.New())
+	card := pii.Token{UserID: userID, Token: "0123456789abcdef", Kind: pkgpii.KindCreditCard, Value: "4111 1111 1111 1111"}
+	email := pii.Token{UserID: userID, Token: "fedcba9876543210", Kind: pkgpii.KindEmail, Value: "jane@example.com"}
+	dbErr := errors.New("database error")
+
+	testCases := []struct {
+		name       string
+		content    string
+		screening  *pii.Screening
+		setupMocks func(*mockVault, memory.ID)
+		wantErr    error
+	}{
+		{
+			// The vault is written on the author's behalf
+			name:      "new tokens",
+			content:   "Card " + card.Placeholder(),
+			screening: &pii.Screening{Policy: pii.PolicyTokenize, Tokens: []pii.Token{card}},
+			setupMocks: func(v *mockVault, id memory.ID) {
+				v.On("FindByMemoryID", mock.MatchedBy(bypassesTenancy), id).Return([]pii.Token{}, nil)
+				v.On("Replace", mock.MatchedBy(bypassesTenancy), id, mock.MatchedBy(func(tokens []pii.Token) bool {
+					return len(tokens) == 1 && tokens[0].MemoryID == id && tokens[0].Token == card.Token
+				})).Return(nil)
+			},
+		},
+		{
+			name:      "tokens edited out are dropped",
+			content:   "Card on file, mail " + email.Placeholder(),
+			screening: &pii.Screening{Policy: pii.PolicyTokenize},
+			setupMocks: func(v *mockVault, id memory.ID) {
+				v.On("FindByMemoryID", mock.Anything, id).Return([]pii.Token{card, email}, nil)
+				v.On("Replace", mock.Anything, id, []pii.Token{email}).Return(nil)
+			},
+		},
+		{
+			name:    "unchanged tokens are not rewritten",
+			content: "Mail " + email.Placeholder(),
+			setupMocks: func(v *mockVault, id memory.ID) {
+				v.On("FindByMemoryID", mock.Anything, id).Return([]pii.Token{email}, nil)
+			},
+		},
+		{
+			name:    "no tokens",
+			content: "Nothing to see here",
+			setupMocks: func(v *mockVault, id memory.ID) {
+				v.On("FindByMemoryID", mock.Anything, id).Return([]pii.Token{}, nil)
+			},
+		},
+		{
+			name:      "vault error",
+			content:   "Card " + card.Placeholder(),
+			screening: &pii.Screening{Policy: pii.PolicyTokenize, Tokens: []pii.Token{card}},
+			setupMocks: func(v *mockVault, id memory.ID) {
+				v.On("FindByMemoryID", mock.Anything, id).Return(nil, dbErr)
+			},
+			wantErr: dbErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := memory.NewMemory(userID, tc.content, "", 5, "fact")
+			vault := &mockVault{}
+			tc.setupMocks(vault, m.ID)
+
+			err := newTestService(vault, &mockUserRepository{}, &mockLogger{}).SaveTokens(context.Background(), m, tc.screening)
+
+			vault.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestService_Rehydrate(t *testing.T) {
+	userID := user.ID(uuid.New())
+	email := pii.Token{UserID: userID, Token: "fedcba9876543210", Kind: pkgpii.KindEmail, Value: "jane@example.com"}
+	ownerCtx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: auth.RoleUser})
+	otherCtx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: user.ID(uuid.New()), Role: auth.RoleUser})
+	dbErr := errors.New("database error")
+
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		content     string
+		summary     string
+		setupMocks  func(*mockVault, memory.ID)
+		wantContent string
+		wantSummary string
+		wantErr     error
+	}{
+		{
+			name:    "successful rehydration",
+			ctx:     ownerCtx,
+			content: "Mail " + email.Placeholder(),
+			summary: "Contact " + email.Placeholder(),
+			setupMocks: func(v *mockVault, id memory.ID) {
+				v.On("FindByMemoryID", mock.Anything, id).Return([]pii.Token{email}, nil)
+			},
+			wantContent: "Mail jane@example.com",
+			wantSummary: "Contact jane@example.com",
+		},
+		{
+			name:        "no placeholders",
+			ctx:         ownerCtx,
+			content:     "Nothing to see here",
+			summary:     "Nothing at all",
+			setupMocks:  func(v *mockVault, id memory.ID) {},
+			wantContent: "Nothing to see here",
+			wantSummary: "Nothing at all",
+		},
+		{
+			name:       "other user",
+			ctx:        otherCtx,
+			content:    "Mail " + email.Placeholder(),
+			setupMocks: func(v *mockVault, id memory.ID) {},
+			wantErr:    auth.ErrPermissionDenied,
+		},
+		{
+			name:    "vault error",
+			ctx:     ownerCtx,
+			content: "Mail " + email.Placeholder(),
+			setupMocks: func(v *mockVault, id memory.ID) {
+				v.On("FindByMemoryID", mock.Anything, id).Return(nil, dbErr)
+			},
+			wantErr: dbErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := memory.NewMemory(userID, tc.content, tc.summary, 5, "fact")
+			vault := &mockVault{}
+			tc.setupMocks(vault, m.ID)
+
+			err := newTestService(vault, &mockUserRepository{}, &mockLogger{}).Rehydrate(tc.ctx, m)
+
+			vault.AssertExpectations(t)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantContent, m.Content)
+			assert.Equal(t, tc.wantSummary, m.Summary)
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/quota"
+	"mem_bank/internal/domain/usage"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// Config holds quota service configuration
+type Config struct {
+	// Limits per plan name
+	Plans map[string]quota.Limits `mapstructure:"plans"`
+
+	// Plan applied to users without an assignment
+	DefaultPlan string `mapstructure:"default_plan"`
+}
+
+// Service enforces per-user quotas. The memory limit comes from the user's
+// Settings.MaxMemories, which only admins may change, falling back to the
+// plan; storage and token limits come from the plan unless overridden for
+// the user. It implements both quota.Service and llm.UsageLimiter.
+//
+// Checks read current usage and are not atomic with the write that follows,
+// so concurrent requests may together overshoot a limit by up to one request
+// each.
+type Service struct {
+	repo      quota.Repository
+	userRepo  user.Repository
+	usageRepo usage.Repository
+	logger    logger.Logger
+	config    Config
+}
+
+// NewService creates a new quota service
+func NewService(repo quota.Repository, userRepo user.Repository, usageRepo usage.Repository, logger logger.Logger, config Config) *Service {
+	if config.DefaultPlan == "" {
+		config.DefaultPlan = "default"
+	}
+	if _, ok := config.Plans[config.DefaultPlan]; !ok {
+		plans := make(map[string]quota.Limits, len(config.Plans)+1)
+		for name, limits := range config.Plans {
+			plans[name] = limits
+		}
+		// An unconfigured default plan leaves everything but MaxMemories unlimited
+		plans[config.DefaultPlan] = quota.Limits{}
+		config.Plans = plans
+	}
+
+	return &Service{
+		repo:      repo,
+		userRepo:  userRepo,
+		usageRepo: usageRepo,
+		logger:    logger,
+		config:    config,
+	}
+}
+
+func (s *Service) GetStatus(ctx context.Context, userID user.ID) (*quota.Status, error) {
+	if userID.IsZero() {
+		return nil, quota.ErrInvalidUserID
+	}
+
+	status, err := s.limits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	memories, bytes, err := s.repo.GetStorage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	embeddingTokens, completionTokens, err := s.monthlyTokens(ctx, userID, status.Usage.PeriodStart)
+	if err != nil {
+		return nil, err
+	}
+
+	status.Usage.Memories = memories
+	status.Usage.StorageBytes = bytes
+	status.Usage.EmbeddingTokens = embeddingTokens
+	status.Usage.CompletionTokens = completionTokens
+	return status, nil
+}
+
+func (s *Service) UpdateQuota(ctx context.Context, userID user.ID, req quota.UpdateRequest) (*quota.Status, error) {
+	if userID.IsZero() {
+		return nil, quota.ErrInvalidUserID
+	}
+	if err := validateUpdateRequest(req); err != nil {
+		return nil, err
+	}
+	if req.Plan != nil {
+		if _, ok := s.config.Plans[*req.Plan]; !ok {
+			return nil, quota.ErrUnknownPlan
+		}
+	}
+
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.repo.FindByUserID(ctx, userID)
+	if errors.Is(err, quota.ErrNotFound) {
+		assignment = &quota.Assignment{UserID: userID, Plan: s.config.DefaultPlan}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if req.ResetOverrides {
+		assignment.Overrides = quota.Overrides{}
+	}
+	if req.Plan != nil {
+		assignment.Plan = *req.Plan
+	}
+	if req.MaxStorageBytes != nil {
+		assignment.Overrides.MaxStorageBytes = req.MaxStorageBytes
+	}
+	if req.MonthlyEmbeddingTokens != nil {
+		assignment.Overrides.MonthlyEmbeddingTokens = req.MonthlyEmbeddingTokens
+	}
+	if req.MonthlyCompletionTokens != nil {
+		assignment.Overrides.MonthlyCompletionTokens = req.MonthlyCompletionTokens
+	}
+
+	if err := s.repo.Save(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	if req.MaxMemories != nil {
+		settings := u.Settings
+		settings.MaxMemories = *req.MaxMemories
+		if err := s.userRepo.UpdateSettings(ctx, userID, settings); err != nil {
+			return nil, fmt.Errorf("updating max memories: %w", err)
+		}
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"user_id": userID.String(),
+		"plan":    assignment.Plan,
+	}).Info("Quota updated")
+
+	return s.GetStatus(ctx, userID)
+}
+
+func (s *Service) CheckMemories(ctx context.Context, userID user.ID, count int, bytes int64) error {
+	status, err := s.limits(ctx, userID)
+	if err != nil {
+		return err
+	}
+	limits := status.Limits
+	if limits.MaxMemories == 0 && limits.MaxStorageBytes == 0 {
+		return nil
+	}
+
+	memories, used, err := s.repo.GetStorage(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxMemories > 0 && memories+count > limits.MaxMemories {
+		return &quota.ExceededError{
+			Resource:  quota.ResourceMemories,
+			Limit:     int64(limits.MaxMemories),
+			Used:      int64(memories),
+			Requested: int64(count),
+		}
+	}
+	if limits.MaxStorageBytes > 0 && used+bytes > limits.MaxStorageBytes {
+		return &quota.ExceededError{
+			Resource:  quota.ResourceStorageBytes,
+			Limit:     limits.MaxStorageBytes,
+			Used:      used,
+			Requested: bytes,
+		}
+	}
+	return nil
+}
+
+func (s *Service) CheckTokens(ctx context.Context, userID user.ID, operation string, tokens int) error {
+	status, err := s.limits(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var resource string
+	var limit int64
+	switch operation {
+	case usage.OperationEmbedding:
+		resource, limit = quota.ResourceEmbeddingTokens, status.Limits.MonthlyEmbeddingTokens
+	case usage.OperationCompletion:
+		resource, limit = quota.ResourceCompletionTokens, status.Limits.MonthlyCompletionTokens
+	}
+	if limit == 0 {
+		return nil
+	}
+
+	embeddingTokens, completionTokens, err := s.monthlyTokens(ctx, userID, status.Usage.PeriodStart)
+	if err != nil {
+		return err
+	}
+	used := embeddingTokens
+	if operation == usage.OperationCompletion {
+		used = completionTokens
+	}
+
+	if used+int64(tokens) > limit {
+		return &quota.ExceededError{
+			Resource:  resource,
+			Limit:     limit,
+			Used:      used,
+			Requested: int64(tokens),
+		}
+	}
+	return nil
+}
+
+// AllowUsage checks a provider call against the monthly token budget of
+// userID. Only system work, which is attributed to no user, is not limited;
+// jobs attribute their calls to the user named in the job.
+func (s *Service) AllowUsage(ctx context.Context, userID string, operation string, tokens int) error {
+	if userID == "" {
+		return nil
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("%w: %q", quota.ErrInvalidUserID, userID)
+	}
+	return s.CheckTokens(ctx, user.ID(id), operation, tokens)
+}
+
+// limits resolves the plan and effective limits of a user
+func (s *Service) limits(ctx context.Context, userID user.ID) (*quota.Status, error) {
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := s.config.DefaultPlan
+	var overrides quota.Overrides
+
+	assignment, err := s.repo.FindByUserID(ctx, userID)
+	switch {
+	case errors.Is(err, quota.ErrNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		overrides = assignment.Overrides
+		if _, ok := s.config.Plans[assignment.Plan]; ok {
+			plan = assignment.Plan
+		} else {
+			s.logger.WithFields(map[string]interface{}{
+				"user_id": userID.String(),
+				"plan":    assignment.Plan,
+			}).Warn("User assigned to unknown quota plan, using default plan")
+		}
+	}
+
+	limits := s.config.Plans[plan]
+	if u.Settings.MaxMemories > 0 {
+		limits.MaxMemories = u.Settings.MaxMemories
+	}
+	if overrides.MaxStorageBytes != nil {
+		limits.MaxStorageBytes = *overrides.MaxStorageBytes
+	}
+	if overrides.MonthlyEmbeddingTokens != nil {
+		limits.MonthlyEmbeddingTokens = *overrides.MonthlyEmbeddingTokens
+	}
+	if overrides.MonthlyCompletionTokens != nil {
+		limits.MonthlyCompletionTokens = *overrides.MonthlyCompletionTokens
+	}
+
+	return &quota.Status{
+		UserID: userID,
+		Plan:   plan,
+		Limits: limits,
+		Usage:  quota.Usage{PeriodStart: quota.PeriodStart(time.Now())},
+	}, nil
+}
+
+// monthlyTokens returns the embedding and completion tokens a user consumed
+// since periodStart
+func (s *Service) monthlyTokens(ctx context.Context, userID user.ID, periodStart time.Time) (int64, int64, error) {
+	summaries, err := s.usageRepo.Summarize(ctx, userID, periodStart, periodStart.AddDate(0, 1, 0))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var embeddingTokens, completionTokens int64
+	for _, summary := range summaries {
+		switch summary.Operation {
+		case usage.OperationEmbedding:
+			embeddingTokens += int64(summary.TotalTokens)
+		case usage.OperationCompletion:
+			completionTokens += int64(summary.TotalTokens)
+		}
+	}
+	return embeddingTokens, completionTokens, nil
+}
+
+func validateUpdateRequest(req quota.UpdateRequest) error {
+	if req.MaxMemories != nil && *req.MaxMemories < 0 {
+		return quota.ErrInvalidLimit
+	}
+	for _, limit := range []*int64{req.MaxStorageBytes, req.MonthlyEmbeddingTokens, req.MonthlyCompletionTokens} {
+		if limit != nil && *limit < 0 {
+			return quota.ErrInvalidLimit
+		}
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/quota"
+	"mem_bank/internal/domain/usage"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// Mock quota repository
+type mockQuotaRepository struct {
+	mock.Mock
+}
+
+func (m *mockQuotaRepository) FindByUserID(ctx context.Context, userID user.ID) (*quota.Assignment, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*quota.Assignment), args.Error(1)
+}
+
+func (m *mockQuotaRepository) Save(ctx context.Context, assignment *quota.Assignment) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *mockQuotaRepository) GetStorage(ctx context.Context, userID user.ID) (int, int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Get(1).(int64), args.Error(2)
+}
+
+// Mock user repository; only the methods the quota service uses are expected
+type mockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id user.ID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserRepository) UpdateSettings(ctx context.Context, id user.ID, settings user.Settings) error {
+	args := m.Called(ctx, id, settings)
+	return args.Error(0)
+}
+
+// Mock usage repository
+type mockUsageRepository struct {
+	mock.Mock
+}
+
+func (m *mockUsageRepository) Store(ctx context.Context, record *usage.Record) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *mockUsageRepository) Summarize(ctx context.Context, userID user.ID, from, to time.Time) ([]usage.Summary, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usage.Summary), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+var testConfig = Config{
+	DefaultPlan: "free",
+	Plans: map[string]quota.Limits{
+		"free": {MaxMemories: 100, MaxStorageBytes: 1000, MonthlyEmbeddingTokens: 500, MonthlyCompletionTokens: 50},
+		"pro":  {MaxMemories: 10000, MaxStorageBytes: 1 << 30},
+	},
+}
+
+func testUser(userID user.ID, maxMemories int) *user.User {
+	return &user.User{ID: userID, Settings: user.Settings{MaxMemories: maxMemories}}
+}
+
+func TestService_CheckMemories(t *testing.T) {
+	userID := user.ID(uuid.New())
+	storageOverride := int64(100)
+
+	tests := []struct {
+		name         string
+		count        int
+		bytes        int64
+		setupMocks   func(*mockQuotaRepository, *mockUserRepository, *mockLogger)
+		wantResource string
+		wantLimit    int64
+	}{
+		{
+			name:  "settings limit overrides plan",
+			count: 1,
+			bytes: 10,
+			setupMocks: func(r *mockQuotaRepository, u *mockUserRepository, l *mockLogger) {
+				u.On("FindByID", mock.Anything, userID).Return(testUser(userID, 5), nil)
+				r.On("FindByUserID", mock.Anything, userID).Return(nil, quota.ErrNotFound)
+				r.On("GetStorage", mock.Anything, userID).Return(5, int64(10), nil)
+			},
+			wantResource: quota.ResourceMemories,
+			wantLimit:    5,
+		},
+		{
+			name:  "within plan limit",
+			count: 1,
+			bytes: 10,
+			setupMocks: func(r *mockQuotaRepository, u *mockUserRepository, l *mockLogger) {
+				u.On("FindByID", mock.Anything, userID).Return(testUser(userID, 0), nil)
+				r.On("FindByUserID", mock.Anything, userID).Return(nil, quota.ErrNotFound)
+				r.On("GetStorage", mock.Anything, userID).Return(99, int64(0), nil)
+			},
+		},
+		{
+			name:  "plan limit applies without settings",
+			count: 2,
+			bytes: 10,
+			setupMocks: func(r *mockQuotaRepository, u *mockUserRepository, l *mockLogger) {
+				u.On("FindByID", mock.Anything, userID).Return(testUser(userID, 0), nil)
+				r.On("FindByUserID", mock.Anything, userID).Return(nil, quota.ErrNotFound)
+				r.On("GetStorage", mock.Anything, userID).Return(99, int64(0), nil)
+			},
+			wantResource: quota.ResourceMemories,
+			wantLimit:    100,
+		},
+		{
+			name:  "storage override",
+			count: 1,
+			bytes: 20,
+			setupMocks: func(r *mockQuotaRepository, u *mockUserRepository, l *mockLogger) {
+				u.On("FindByID", mock.Anything, userID).Return(testUser(userID, 0), nil)
+				r.On("FindByUserID", mock.Anything, userID).Return(&quota.Assignment{
+					UserID:    userID,
+					Plan:      "pro",
+					Overrides: quota.Overrides{MaxStorageBytes: &storageOverride},
+				}, nil)
+				r.On("GetStorage", mock.Anything, userID).Return(1, int64(90), nil)
+			},
+			wantResource: quota.ResourceStorageBytes,
+			wantLimit:    100,
+		},
+		{
+			name:  "unknown plan falls back to the default plan",
+			count: 1,
+			bytes: 1000,
+			setupMocks: func(r *mockQuotaRepository, u *mockUserRepository, l *mockLogger) {
+				u.On("FindByID", mock.Anything, userID).Return(testUser(userID, 0), nil)
+				r.On("FindByUserID", mock.Anything, userID).Return(&quota.Assignment{UserID: userID, Plan: "legacy"}, nil)
+				r.On("GetStorage", mock.Anything, userID).Return(1, int64(1), nil)
+				l.On("Warn", "User assigned to unknown quota plan, using default plan").Once()
+			},
+			wantResource: quota.ResourceStorageBytes,
+			wantLimit:    1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockQuotaRepository{}
+			userRepo := &mockUserRepository{}
+			log := &mockLogger{}
+			tt.setupMocks(repo, userRepo, log)
+			service := NewService(repo, userRepo, &mockUsageRepository{}, log, testConfig)
+
+			err := service.CheckMemories(context.Background(), userID, tt.count, tt.bytes)
+			if tt.wantResource == "" {
+				assert.NoError(t, err)
+			} else {
+				var exceeded *quota.ExceededError
+				require.ErrorAs(t, err, &exceeded)
+				assert.ErrorIs(t, err, quota.ErrQuotaExceeded)
+				assert.Equal(t, tt.wantResource, exceeded.Resource)
+				assert.Equal(t, tt.wantLimit, exceeded.Limit)
+			}
+
+			repo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_CheckTokens(t *testing.T) {
+	userID := user.ID(uuid.New())
+
+	tests := []struct {
+		name      string
+		operation string
+		tokens    int
+		wantErr   error
+	}{
+		{"embedding within budget", usage.OperationEmbedding, 50, nil},
+		{"embedding over budget", usage.OperationEmbedding, 51, quota.ErrQuotaExceeded},
+		{"completion over budget", usage.OperationCompletion, 41, quota.ErrQuotaExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockQuotaRepository{}
+			repo.On("FindByUserID", mock.Anything, userID).Return(nil, quota.ErrNotFound)
+			userRepo := &mockUserRepository{}
+			userRepo.On("FindByID", mock.Anything, userID).Return(testUser(userID, 0), nil)
+			usageRepo := &mockUsageRepository{}
+			usageRepo.On("Summarize", mock.Anything, userID, mock.Anything, mock.Anything).Return([]usage.Summary{
+				{Operation: usage.OperationEmbedding, TotalTokens: 450},
+				{Operation: usage.OperationCompletion, TotalTokens: 10},
+			}, nil)
+			service := NewService(repo, userRepo, usageRepo, &mockLogger{}, testConfig)
+
+			err := service.CheckTokens(context.Background(), userID, tt.operation, tt.tokens)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, err, service.AllowUsage(context.Background(), userID.String(), tt.operation, tt.tokens))
+		})
+	}
+
+	t.Run("system work is not limited", func(t *testing.T) {
+		service := NewService(&mockQuotaRepository{}, &mockUserRepository{}, &mockUsageRepository{}, &mockLogger{}, testConfig)
+		assert.NoError(t, service.AllowUsage(context.Background(), "", usage.OperationEmbedding, 1_000_000))
+	})
+
+	t.Run("malformed user is rejected", func(t *testing.T) {
+		service := NewService(&mockQuotaRepository{}, &mockUserRepository{}, &mockUsageRepository{}, &mockLogger{}, testConfig)
+		err := service.AllowUsage(context.Background(), "not-a-user", usage.OperationEmbedding, 1)
+		assert.ErrorIs(t, err, quota.ErrInvalidUserID)
+	})
+}
+
+func TestService_UpdateQuota(t *testing.T) {
+	userID := user.ID(uuid.New())
+	plan := "pro"
+	unknown := "enterprise"
+	maxMemories := 50
+	budget := int64(1_000_000)
+	negative := int64(-1)
+
+	tests := []struct {
+		name       string
+		req        quota.UpdateRequest
+		setupMocks func(*mockQuotaRepository, *mockUserRepository, *mockUsageRepository, *mockLogger)
+		wantErr    error
+	}{
+		{
+			name: "successful update",
+			req: quota.UpdateRequest{
+				Plan:                   &plan,
+				MaxMemories:            &maxMemories,
+				MonthlyEmbeddingTokens: &budget,
+			},
+			setupMocks: func(r *mockQuotaRepository, u *mockUserRepository, us *mockUsageRepository, l *mockLogger) {
+				u.On("FindByID", mock.Anything, userID).Return(testUser(userID, 5), nil)
+				r.On("FindByUserID", mock.Anything, userID).Return(nil, quota.ErrNotFound).Once()
+				r.On("Save", mock.Anything, mock.MatchedBy(func(a *quota.Assignment) bool {
+					return a.Plan == "pro" && a.Overrides.MonthlyEmbeddingTokens != nil && *a.Overrides.MonthlyEmbeddingTokens == budget
+				})).Return(nil)
+				u.On("UpdateSettings", mock.Anything, userID, user.Settings{MaxMemories: 50}).Return(nil)
+				l.On("Info", "Quota updated").Once()
+
+				// The status reload after saving sees the stored assignment
+				r.On("FindByUserID", mock.Anything, userID).Return(&quota.Assignment{
+					UserID:    userID,
+					Plan:      "pro",
+					Overrides: quota.Overrides{MonthlyEmbeddingTokens: &budget},
+				}, nil)
+				r.On("GetStorage", mock.Anything, userID).Return(3, int64(300), nil)
+				us.On("Summarize", mock.Anything, userID, mock.Anything, mock.Anything).Return([]usage.Summary{}, nil)
+			},
+		},
+		{
+			name:    "unknown plan",
+			req:     quota.UpdateRequest{Plan: &unknown},
+			wantErr: quota.ErrUnknownPlan,
+		},
+		{
+			name:    "negative limit",
+			req:     quota.UpdateRequest{MaxStorageBytes: &negative},
+			wantErr: quota.ErrInvalidLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockQuotaRepository{}
+			userRepo := &mockUserRepository{}
+			usageRepo := &mockUsageRepository{}
+			log := &mockLogger{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo, userRepo, usageRepo, log)
+			}
+			service := NewService(repo, userRepo, usageRepo, log, testConfig)
+
+			status, err := service.UpdateQuota(context.Background(), userID, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "pro", status.Plan)
+				assert.Equal(t, budget, status.Limits.MonthlyEmbeddingTokens)
+				assert.Equal(t, 3, status.Usage.Memories)
+			}
+
+			repo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package space
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// maxNameLength bounds space names
+const maxNameLength = 100
+
+// Service implements space.Service. Spaces are only visible to their
+// members: others get space.ErrNotFound, members lacking a role get
+// auth.ErrPermissionDenied. Admin and system callers may manage any space.
+type Service struct {
+	repo     space.Repository
+	userRepo user.Repository
+	logger   logger.Logger
+	now      func() time.Time
+}
+
+// NewService creates a new space service
+func NewService(repo space.Repository, userRepo user.Repository, logger logger.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func (s *Service) CreateSpace(ctx context.Context, req space.CreateRequest) (*space.Space, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok || p.UserID.IsZero() {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, space.ErrInvalidName
+	}
+
+	sp := space.NewSpace(name, strings.TrimSpace(req.Description), p.UserID)
+	sp.CreatedAt, sp.UpdatedAt = s.now(), s.now()
+	owner := &space.Member{
+		SpaceID:   sp.ID,
+		UserID:    p.UserID,
+		Role:      space.RoleOwner,
+		CreatedAt: sp.CreatedAt,
+	}
+	if err := s.repo.Create(ctx, sp, owner); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"space_id": sp.ID.String(),
+		"user_id":  p.UserID.String(),
+	}).Info("Space created")
+	return sp, nil
+}
+
+func (s *Service) GetSpace(ctx context.Context, id space.ID) (*space.Space, error) {
+	if err := s.authorize(ctx, id, space.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *Service) ListSpaces(ctx context.Context) ([]*space.Membership, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok || p.UserID.IsZero() {
+		return nil, auth.ErrPermissionDenied
+	}
+	return s.repo.ListByUserID(ctx, p.UserID)
+}
+
+func (s *Service) UpdateSpace(ctx context.Context, id space.ID, req space.UpdateRequest) (*space.Space, error) {
+	if err := s.authorize(ctx, id, space.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	sp, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > maxNameLength {
+			return nil, space.ErrInvalidName
+		}
+		sp.Name = name
+	}
+	if req.Description != nil {
+		sp.Description = strings.TrimSpace(*req.Description)
+	}
+	sp.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, sp); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+func (s *Service) DeleteSpace(ctx context.Context, id space.ID) error {
+	if err := s.authorize(ctx, id, space.RoleOwner); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *Service) ListMembers(ctx context.Context, id space.ID) ([]*space.Member, error) {
+	if err := s.authorize(ctx, id, space.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMembers(ctx, id)
+}
+
+func (s *Service) AddMember(ctx context.Context, id space.ID, req space.AddMemberRequest) (*space.Member, error) {
+	if req.UserID.IsZero() {
+		return nil, user.ErrInvalidID
+	}
+	if !space.ValidRole(req.Role) {
+		return nil, space.ErrInvalidRole
+	}
+	if err := s.authorize(ctx, id, space.RoleOwner); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.FindByID(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+
+	member := &space.Member{
+		SpaceID:   id,
+		UserID:    req.UserID,
+		Role:      req.Role,
+		CreatedAt: s.now(),
+	}
+	if err := s.repo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *Service) UpdateMember(ctx context.Context, id space.ID, userID user.ID, role string) (*space.Member, error) {
+	if !space.ValidRole(role) {
+		return nil, space.ErrInvalidRole
+	}
+	if err := s.authorize(ctx, id, space.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.FindMember(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == space.RoleOwner && role != space.RoleOwner {
+		if err := s.keepOwner(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, id, userID, role); err != nil {
+		return nil, err
+	}
+	member.Role = role
+	return member, nil
+}
+
+func (s *Service) RemoveMember(ctx context.Context, id space.ID, userID user.ID) error {
+	// Members may always leave; removing others takes an owner
+	p, _ := auth.PrincipalFromContext(ctx)
+	required := space.RoleOwner
+	if !p.UserID.IsZero() && p.UserID == userID {
+		required = space.RoleViewer
+	}
+	if err := s.authorize(ctx, id, required); err != nil {
+		return err
+	}
+
+	member, err := s.repo.FindMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role == space.RoleOwner {
+		if err := s.keepOwner(ctx, id); err != nil {
+			return err
+		}
+	}
+	return s.repo.RemoveMember(ctx, id, userID)
+}
+
+// keepOwner returns space.ErrLastOwner when a space has a single owner left
+func (s *Service) keepOwner(ctx context.Context, id space.ID) error {
+	owners, err := s.repo.CountOwners(ctx, id)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return space.ErrLastOwner
+	}
+	return nil
+}
+
+// authorize checks that the caller holds at least role in a space
+func (s *Service) authorize(ctx context.Context, id space.ID, role string) error {
+	if id.IsZero() {
+		return space.ErrInvalidID
+	}
+
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return auth.ErrPermissionDenied
+	}
+	if p.IsPrivileged() {
+		_, err := s.repo.FindByID(ctx, id)
+		return err
+	}
+
+	member, err := s.repo.FindMember(ctx, id, p.UserID)
+	if errors.Is(err, space.ErrNotMember) {
+		// Do not reveal spaces to non-members
+		return space.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !member.Has(role) {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
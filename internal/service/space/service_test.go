@@ -0,0 +1,539 @@
+package space
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/space"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/logger"
+)
+
+// Mock space repository
+type mockSpaceRepository struct {
+	mock.Mock
+}
+
+func (m *mockSpaceRepository) Create(ctx context.Context, sp *space.Space, owner *space.Member) error {
+	args := m.Called(ctx, sp, owner)
+	return args.Error(0)
+}
+
+func (m *mockSpaceRepository) FindByID(ctx context.Context, id space.ID) (*space.Space, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*space.Space), args.Error(1)
+}
+
+func (m *mockSpaceRepository) ListByUserID(ctx context.Context, userID user.ID) ([]*space.Membership, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*space.Membership), args.Error(1)
+}
+
+func (m *mockSpaceRepository) Update(ctx context.Context, sp *space.Space) error {
+	args := m.Called(ctx, sp)
+	return args.Error(0)
+}
+
+func (m *mockSpaceRepository) Delete(ctx context.Context, id space.ID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockSpaceRepository) FindMember(ctx context.Context, spaceID space.ID, userID user.ID) (*space.Member, error) {
+	args := m.Called(ctx, spaceID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*space.Member), args.Error(1)
+}
+
+func (m *mockSpaceRepository) ListMembers(ctx context.Context, spaceID space.ID) ([]*space.Member, error) {
+	args := m.Called(ctx, spaceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*space.Member), args.Error(1)
+}
+
+func (m *mockSpaceRepository) AddMember(ctx context.Context, member *space.Member) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *mockSpaceRepository) UpdateMemberRole(ctx context.Context, spaceID space.ID, userID user.ID, role string) error {
+	args := m.Called(ctx, spaceID, userID, role)
+	return args.Error(0)
+}
+
+func (m *mockSpaceRepository) RemoveMember(ctx context.Context, spaceID space.ID, userID user.ID) error {
+	args := m.Called(ctx, spaceID, userID)
+	return args.Error(0)
+}
+
+func (m *mockSpaceRepository) CountOwners(ctx context.Context, spaceID space.ID) (int, error) {
+	args := m.Called(ctx, spaceID)
+	return args.Int(0), args.Error(1)
+}
+
+// Mock user repository
+type mockUserRepository struct {
+	mock.Mock
+	user.Repository
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id user.ID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+func userContext(id user.ID) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: id, Role: auth.RoleUser})
+}
+
+// testSpace is a space with an owner, an editor and a viewer
+type testSpace struct {
+	space                  *space.Space
+	owner, editor, viewer  user.ID
+	outsider               user.ID
+	ownerCtx, editorCtx    context.Context
+	viewerCtx, outsiderCtx context.Context
+	adminCtx               context.Context
+	members                map[user.ID]string
+	unknownSpace           space.ID
+}
+
+func newTestSpace() *testSpace {
+	ts := &testSpace{
+		owner:        user.ID(uuid.New()),
+		editor:       user.ID(uuid.New()),
+		viewer:       user.ID(uuid.New()),
+		outsider:     user.ID(uuid.New()),
+		unknownSpace: space.NewID(),
+	}
+	ts.space = space.NewSpace("Research", "", ts.owner)
+	ts.members = map[user.ID]string{ts.owner: space.RoleOwner, ts.editor: space.RoleEditor, ts.viewer: space.RoleViewer}
+	ts.ownerCtx, ts.editorCtx = userContext(ts.owner), userContext(ts.editor)
+	ts.viewerCtx, ts.outsiderCtx = userContext(ts.viewer), userContext(ts.outsider)
+	ts.adminCtx = auth.WithPrincipal(context.Background(), auth.Principal{UserID: ts.outsider, Role: auth.RoleAdmin})
+	return ts
+}
+
+// expectMembers lets the repository answer membership lookups for the space
+func (ts *testSpace) expectMembers(r *mockSpaceRepository) {
+	for userID, role := range ts.members {
+		r.On("FindMember", mock.Anything, ts.space.ID, userID).
+			Return(&space.Member{SpaceID: ts.space.ID, UserID: userID, Role: role}, nil).Maybe()
+	}
+	r.On("FindMember", mock.Anything, ts.space.ID, ts.outsider).Return(nil, space.ErrNotMember).Maybe()
+}
+
+func TestService_CreateSpace(t *testing.T) {
+	owner := user.ID(uuid.New())
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		req        space.CreateRequest
+		setupMocks func(*mockSpaceRepository, *mockLogger)
+		wantErr    error
+	}{
+		{
+			name: "creator becomes the owner",
+			ctx:  userContext(owner),
+			req:  space.CreateRequest{Name: " Research "},
+			setupMocks: func(r *mockSpaceRepository, l *mockLogger) {
+				r.On("Create", mock.Anything,
+					mock.MatchedBy(func(sp *space.Space) bool { return sp.Name == "Research" && sp.CreatedBy == owner }),
+					mock.MatchedBy(func(m *space.Member) bool { return m.UserID == owner && m.Role == space.RoleOwner }),
+				).Return(nil)
+				l.On("Info", "Space created").Once()
+			},
+		},
+		{
+			name:    "blank name",
+			ctx:     userContext(owner),
+			req:     space.CreateRequest{Name: "  "},
+			wantErr: space.ErrInvalidName,
+		},
+		{
+			name:    "anonymous caller",
+			ctx:     context.Background(),
+			req:     space.CreateRequest{Name: "anonymous"},
+			wantErr: auth.ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockSpaceRepository{}
+			log := &mockLogger{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo, log)
+			}
+			service := NewService(repo, &mockUserRepository{}, log)
+
+			sp, err := service.CreateSpace(tt.ctx, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "Research", sp.Name)
+			}
+
+			repo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ListSpaces(t *testing.T) {
+	ts := newTestSpace()
+	memberships := []*space.Membership{{Space: ts.space, Role: space.RoleViewer}}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		setupMocks func(*mockSpaceRepository)
+		want       []*space.Membership
+		wantErr    error
+	}{
+		{
+			name: "member lists their spaces",
+			ctx:  ts.viewerCtx,
+			setupMocks: func(r *mockSpaceRepository) {
+				r.On("ListByUserID", mock.Anything, ts.viewer).Return(memberships, nil)
+			},
+			want: memberships,
+		},
+		{
+			name:    "anonymous caller",
+			ctx:     context.Background(),
+			wantErr: auth.ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockSpaceRepository{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo)
+			}
+			service := NewService(repo, &mockUserRepository{}, &mockLogger{})
+
+			got, err := service.ListSpaces(tt.ctx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Authorization(t *testing.T) {
+	ts := newTestSpace()
+	name := "Renamed"
+
+	tests := []struct {
+		name       string
+		call       func(*testing.T, *Service) error
+		setupMocks func(*mockSpaceRepository)
+		wantErr    error
+	}{
+		{
+			name: "members read the space",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.GetSpace(ts.viewerCtx, ts.space.ID)
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository) {
+				r.On("FindByID", mock.Anything, ts.space.ID).Return(ts.space, nil)
+			},
+		},
+		{
+			name: "members list the members",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.ListMembers(ts.viewerCtx, ts.space.ID)
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository) {
+				r.On("ListMembers", mock.Anything, ts.space.ID).Return([]*space.Member{}, nil)
+			},
+		},
+		{
+			name: "space is hidden from non-members",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.GetSpace(ts.outsiderCtx, ts.space.ID)
+				return err
+			},
+			wantErr: space.ErrNotFound,
+		},
+		{
+			name: "members are hidden from non-members",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.ListMembers(ts.outsiderCtx, ts.space.ID)
+				return err
+			},
+			wantErr: space.ErrNotFound,
+		},
+		{
+			name: "editor cannot rename",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.UpdateSpace(ts.editorCtx, ts.space.ID, space.UpdateRequest{Name: &name})
+				return err
+			},
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name: "editor cannot add members",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.AddMember(ts.editorCtx, ts.space.ID, space.AddMemberRequest{UserID: ts.outsider, Role: space.RoleViewer})
+				return err
+			},
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name: "editor cannot remove others",
+			call: func(t *testing.T, s *Service) error {
+				return s.RemoveMember(ts.editorCtx, ts.space.ID, ts.viewer)
+			},
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name: "editor cannot delete",
+			call: func(t *testing.T, s *Service) error {
+				return s.DeleteSpace(ts.editorCtx, ts.space.ID)
+			},
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name: "owner renames",
+			call: func(t *testing.T, s *Service) error {
+				sp, err := s.UpdateSpace(ts.ownerCtx, ts.space.ID, space.UpdateRequest{Name: &name})
+				if err == nil && sp.Name != name {
+					t.Errorf("name = %q, want %q", sp.Name, name)
+				}
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository) {
+				found := *ts.space
+				r.On("FindByID", mock.Anything, ts.space.ID).Return(&found, nil)
+				r.On("Update", mock.Anything, mock.MatchedBy(func(sp *space.Space) bool { return sp.Name == name })).Return(nil)
+			},
+		},
+		{
+			name: "admins read any space",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.GetSpace(ts.adminCtx, ts.space.ID)
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository) {
+				r.On("FindByID", mock.Anything, ts.space.ID).Return(ts.space, nil)
+			},
+		},
+		{
+			name: "admins get missing spaces reported",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.GetSpace(ts.adminCtx, ts.unknownSpace)
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository) {
+				r.On("FindByID", mock.Anything, ts.unknownSpace).Return(nil, space.ErrNotFound)
+			},
+			wantErr: space.ErrNotFound,
+		},
+		{
+			name: "anonymous caller",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.GetSpace(context.Background(), ts.space.ID)
+				return err
+			},
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name: "zero space ID",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.GetSpace(ts.ownerCtx, space.ID{})
+				return err
+			},
+			wantErr: space.ErrInvalidID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockSpaceRepository{}
+			ts.expectMembers(repo)
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo)
+			}
+			service := NewService(repo, &mockUserRepository{}, &mockLogger{})
+
+			err := tt.call(t, service)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Members(t *testing.T) {
+	ts := newTestSpace()
+	newcomer := user.ID(uuid.New())
+
+	tests := []struct {
+		name       string
+		call       func(*testing.T, *Service) error
+		setupMocks func(*mockSpaceRepository, *mockUserRepository)
+		wantErr    error
+	}{
+		{
+			name: "owner adds a member",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.AddMember(ts.ownerCtx, ts.space.ID, space.AddMemberRequest{UserID: newcomer, Role: space.RoleViewer})
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository, u *mockUserRepository) {
+				u.On("FindByID", mock.Anything, newcomer).Return(&user.User{ID: newcomer, IsActive: true}, nil)
+				r.On("AddMember", mock.Anything, mock.MatchedBy(func(m *space.Member) bool {
+					return m.SpaceID == ts.space.ID && m.UserID == newcomer && m.Role == space.RoleViewer
+				})).Return(nil)
+			},
+		},
+		{
+			name: "duplicate member",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.AddMember(ts.ownerCtx, ts.space.ID, space.AddMemberRequest{UserID: ts.viewer, Role: space.RoleEditor})
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository, u *mockUserRepository) {
+				u.On("FindByID", mock.Anything, ts.viewer).Return(&user.User{ID: ts.viewer, IsActive: true}, nil)
+				r.On("AddMember", mock.Anything, mock.Anything).Return(space.ErrAlreadyMember)
+			},
+			wantErr: space.ErrAlreadyMember,
+		},
+		{
+			name: "unknown user",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.AddMember(ts.ownerCtx, ts.space.ID, space.AddMemberRequest{UserID: newcomer, Role: space.RoleViewer})
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository, u *mockUserRepository) {
+				u.On("FindByID", mock.Anything, newcomer).Return(nil, user.ErrNotFound)
+			},
+			wantErr: user.ErrNotFound,
+		},
+		{
+			name: "invalid role",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.AddMember(ts.ownerCtx, ts.space.ID, space.AddMemberRequest{UserID: newcomer, Role: "admin"})
+				return err
+			},
+			wantErr: space.ErrInvalidRole,
+		},
+		{
+			name: "members may leave",
+			call: func(t *testing.T, s *Service) error {
+				return s.RemoveMember(ts.viewerCtx, ts.space.ID, ts.viewer)
+			},
+			setupMocks: func(r *mockSpaceRepository, u *mockUserRepository) {
+				r.On("RemoveMember", mock.Anything, ts.space.ID, ts.viewer).Return(nil)
+			},
+		},
+		{
+			name: "the last owner cannot leave",
+			call: func(t *testing.T, s *Service) error {
+				return s.RemoveMember(ts.ownerCtx, ts.space.ID, ts.owner)
+			},
+			setupMocks: func(r *mockSpaceRepository, u *mockUserRepository) {
+				r.On("CountOwners", mock.Anything, ts.space.ID).Return(1, nil)
+			},
+			wantErr: space.ErrLastOwner,
+		},
+		{
+			name: "the last owner cannot step down",
+			call: func(t *testing.T, s *Service) error {
+				_, err := s.UpdateMember(ts.ownerCtx, ts.space.ID, ts.owner, space.RoleEditor)
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository, u *mockUserRepository) {
+				r.On("CountOwners", mock.Anything, ts.space.ID).Return(1, nil)
+			},
+			wantErr: space.ErrLastOwner,
+		},
+		{
+			name: "an owner steps down when another remains",
+			call: func(t *testing.T, s *Service) error {
+				member, err := s.UpdateMember(ts.ownerCtx, ts.space.ID, ts.owner, space.RoleViewer)
+				if err == nil && member.Role != space.RoleViewer {
+					t.Errorf("role = %q, want %q", member.Role, space.RoleViewer)
+				}
+				return err
+			},
+			setupMocks: func(r *mockSpaceRepository, u *mockUserRepository) {
+				r.On("CountOwners", mock.Anything, ts.space.ID).Return(2, nil)
+				r.On("UpdateMemberRole", mock.Anything, ts.space.ID, ts.owner, space.RoleViewer).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockSpaceRepository{}
+			userRepo := &mockUserRepository{}
+			ts.expectMembers(repo)
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo, userRepo)
+			}
+			service := NewService(repo, userRepo, &mockLogger{})
+
+			err := tt.call(t, service)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
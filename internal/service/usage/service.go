@@ -0,0 +1,140 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/usage"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/llm"
+	"mem_bank/pkg/logger"
+	"mem_bank/pkg/metrics"
+)
+
+// maxRange is the longest time range a single usage report may cover
+const maxRange = 366 * 24 * time.Hour
+
+// Pricing is the price of a model in US dollars per 1K tokens
+type Pricing struct {
+	PromptPer1K     float64 `mapstructure:"prompt_per_1k"`
+	CompletionPer1K float64 `mapstructure:"completion_per_1k"`
+}
+
+// DefaultPricing holds list prices for the models the providers default to.
+// Models without an entry, such as local Ollama models, cost nothing.
+var DefaultPricing = map[string]Pricing{
+	"text-embedding-ada-002": {PromptPer1K: 0.0001},
+	"text-embedding-3-small": {PromptPer1K: 0.00002},
+	"text-embedding-3-large": {PromptPer1K: 0.00013},
+	"gpt-3.5-turbo":          {PromptPer1K: 0.0005, CompletionPer1K: 0.0015},
+	"gpt-4":                  {PromptPer1K: 0.03, CompletionPer1K: 0.06},
+	"gpt-4-turbo":            {PromptPer1K: 0.01, CompletionPer1K: 0.03},
+	"gpt-4o":                 {PromptPer1K: 0.0025, CompletionPer1K: 0.01},
+	"gpt-4o-mini":            {PromptPer1K: 0.00015, CompletionPer1K: 0.0006},
+}
+
+// Config holds usage service configuration
+type Config struct {
+	// Per-model prices overriding DefaultPricing
+	Pricing map[string]Pricing `mapstructure:"pricing"`
+}
+
+// Service records LLM usage into the ledger and reports it per user. It
+// implements both usage.Service and llm.UsageRecorder.
+type Service struct {
+	repo    usage.Repository
+	pricing map[string]Pricing
+	logger  logger.Logger
+}
+
+// NewService creates a new usage service
+func NewService(repo usage.Repository, logger logger.Logger, config Config) *Service {
+	pricing := make(map[string]Pricing, len(DefaultPricing)+len(config.Pricing))
+	for model, price := range DefaultPricing {
+		pricing[model] = price
+	}
+	for model, price := range config.Pricing {
+		pricing[model] = price
+	}
+
+	return &Service{
+		repo:    repo,
+		pricing: pricing,
+		logger:  logger,
+	}
+}
+
+// RecordUsage prices a provider call and appends it to the ledger. Failures
+// are logged rather than returned so accounting never fails the call itself.
+func (s *Service) RecordUsage(ctx context.Context, record llm.UsageRecord) {
+	cost := s.Cost(record.Model, record.Usage)
+	metrics.LLMCost.WithLabelValues(record.Provider, record.Model, record.Operation).Add(cost)
+
+	entry := &usage.Record{
+		ID:               uuid.New(),
+		Provider:         record.Provider,
+		Model:            record.Model,
+		Operation:        record.Operation,
+		PromptTokens:     record.Usage.PromptTokens,
+		CompletionTokens: record.Usage.CompletionTokens,
+		TotalTokens:      record.Usage.TotalTokens,
+		Estimated:        record.Usage.Estimated,
+		CostUSD:          cost,
+		CreatedAt:        time.Now(),
+	}
+	if record.UserID != "" {
+		if id, err := uuid.Parse(record.UserID); err == nil {
+			userID := user.ID(id)
+			entry.UserID = &userID
+		}
+	}
+
+	// Record even when the caller's context is cancelled
+	ctx = context.WithoutCancel(ctx)
+	if err := s.repo.Store(ctx, entry); err != nil {
+		s.logger.WithFields(map[string]interface{}{
+			"user_id":   record.UserID,
+			"model":     record.Model,
+			"operation": record.Operation,
+			"tokens":    record.Usage.TotalTokens,
+			"error":     err.Error(),
+		}).Error("Failed to record LLM usage")
+	}
+}
+
+// Cost returns the price in US dollars of usage on model
+func (s *Service) Cost(model string, u llm.Usage) float64 {
+	price := s.pricing[model]
+	return float64(u.PromptTokens)/1000*price.PromptPer1K +
+		float64(u.CompletionTokens)/1000*price.CompletionPer1K
+}
+
+// GetUsage reports a user's token usage and cost in [from, to)
+func (s *Service) GetUsage(ctx context.Context, userID user.ID, from, to time.Time) (*usage.Report, error) {
+	if userID.IsZero() {
+		return nil, usage.ErrInvalidUserID
+	}
+	if !from.Before(to) || to.Sub(from) > maxRange {
+		return nil, usage.ErrInvalidRange
+	}
+
+	items, err := s.repo.Summarize(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &usage.Report{
+		UserID: userID,
+		From:   from,
+		To:     to,
+		Items:  items,
+	}
+	for _, item := range items {
+		report.Requests += item.Requests
+		report.TotalTokens += item.TotalTokens
+		report.CostUSD += item.CostUSD
+	}
+	return report, nil
+}
@@ -0,0 +1,185 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/usage"
+	"mem_bank/internal/domain/user"
+	"mem_bank/pkg/llm"
+	"mem_bank/pkg/logger"
+)
+
+// Mock repository
+type mockUsageRepository struct {
+	mock.Mock
+}
+
+func (m *mockUsageRepository) Store(ctx context.Context, record *usage.Record) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *mockUsageRepository) Summarize(ctx context.Context, userID user.ID, from, to time.Time) ([]usage.Summary, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]usage.Summary), args.Error(1)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+func TestService_Cost(t *testing.T) {
+	service := NewService(&mockUsageRepository{}, &mockLogger{}, Config{
+		Pricing: map[string]Pricing{
+			"gpt-4":       {PromptPer1K: 0.01, CompletionPer1K: 0.02},
+			"local-model": {PromptPer1K: 0.5},
+		},
+	})
+
+	testCases := []struct {
+		name     string
+		model    string
+		usage    llm.Usage
+		expected float64
+	}{
+		{"default price", "text-embedding-ada-002", llm.Usage{PromptTokens: 10000, TotalTokens: 10000}, 0.001},
+		{"configured override", "gpt-4", llm.Usage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500}, 0.02},
+		{"configured model", "local-model", llm.Usage{PromptTokens: 2000, TotalTokens: 2000}, 1},
+		{"unpriced model", "llama2", llm.Usage{PromptTokens: 1000, CompletionTokens: 1000, TotalTokens: 2000}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, service.Cost(tc.model, tc.usage), 1e-12)
+		})
+	}
+}
+
+func TestService_RecordUsage(t *testing.T) {
+	userID := uuid.New()
+
+	testCases := []struct {
+		name       string
+		record     llm.UsageRecord
+		setupMocks func(*mockUsageRepository, *mockLogger)
+		wantCost   float64
+	}{
+		{
+			name: "attributed usage",
+			record: llm.UsageRecord{
+				UserID:    userID.String(),
+				Provider:  "openai",
+				Model:     "gpt-3.5-turbo",
+				Operation: usage.OperationCompletion,
+				Usage:     llm.Usage{PromptTokens: 2000, CompletionTokens: 1000, TotalTokens: 3000, Estimated: true},
+			},
+			setupMocks: func(r *mockUsageRepository, l *mockLogger) {
+				r.On("Store", mock.Anything, mock.MatchedBy(func(record *usage.Record) bool {
+					return record.UserID != nil && uuid.UUID(*record.UserID) == userID &&
+						record.Provider == "openai" &&
+						record.Model == "gpt-3.5-turbo" &&
+						record.Operation == usage.OperationCompletion &&
+						record.TotalTokens == 3000 &&
+						record.Estimated
+				})).Return(nil).Once()
+			},
+			wantCost: 0.0025,
+		},
+		{
+			// A failing ledger must not panic or surface to the caller
+			name: "unattributed usage with failing ledger",
+			record: llm.UsageRecord{
+				Provider:  "openai",
+				Model:     "text-embedding-ada-002",
+				Operation: usage.OperationEmbedding,
+				Usage:     llm.Usage{PromptTokens: 10, TotalTokens: 10},
+			},
+			setupMocks: func(r *mockUsageRepository, l *mockLogger) {
+				r.On("Store", mock.Anything, mock.MatchedBy(func(record *usage.Record) bool {
+					return record.UserID == nil
+				})).Return(errors.New("database down")).Once()
+				l.On("Error", "Failed to record LLM usage").Once()
+			},
+			wantCost: 0.000001,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &mockUsageRepository{}
+			log := &mockLogger{}
+			tc.setupMocks(repo, log)
+
+			NewService(repo, log, Config{}).RecordUsage(context.Background(), tc.record)
+
+			repo.AssertExpectations(t)
+			log.AssertExpectations(t)
+			stored := repo.Calls[0].Arguments.Get(1).(*usage.Record)
+			assert.InDelta(t, tc.wantCost, stored.CostUSD, 1e-12)
+		})
+	}
+}
+
+func TestService_GetUsage(t *testing.T) {
+	repo := &mockUsageRepository{}
+	service := NewService(repo, &mockLogger{}, Config{})
+
+	userID := user.ID(uuid.New())
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	repo.On("Summarize", mock.Anything, userID, from, to).Return([]usage.Summary{
+		{Model: "gpt-3.5-turbo", Operation: "completion", Requests: 2, TotalTokens: 300, CostUSD: 0.25},
+		{Model: "text-embedding-ada-002", Operation: "embedding", Requests: 5, TotalTokens: 700, CostUSD: 0.5},
+	}, nil)
+
+	report, err := service.GetUsage(context.Background(), userID, from, to)
+	require.NoError(t, err)
+
+	assert.Len(t, report.Items, 2)
+	assert.Equal(t, 7, report.Requests)
+	assert.Equal(t, 1000, report.TotalTokens)
+	assert.InDelta(t, 0.75, report.CostUSD, 1e-12)
+}
+
+func TestService_GetUsage_InvalidInput(t *testing.T) {
+	service := NewService(&mockUsageRepository{}, &mockLogger{}, Config{})
+
+	userID := user.ID(uuid.New())
+	now := time.Now()
+
+	_, err := service.GetUsage(context.Background(), user.ID{}, now.Add(-time.Hour), now)
+	assert.ErrorIs(t, err, usage.ErrInvalidUserID)
+
+	_, err = service.GetUsage(context.Background(), userID, now, now.Add(-time.Hour))
+	assert.ErrorIs(t, err, usage.ErrInvalidRange)
+
+	_, err = service.GetUsage(context.Background(), userID, now.AddDate(-2, 0, 0), now)
+	assert.ErrorIs(t, err, usage.ErrInvalidRange)
+}
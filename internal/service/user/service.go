@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/pii"
 	"mem_bank/internal/domain/user"
 )
 
@@ -124,7 +126,22 @@ func (s *service) UpdateUser(ctx context.Context, id user.ID, req user.UpdateReq
 	}
 
 	if req.Settings != nil {
-		u.UpdateSettings(*req.Settings)
+		settings := *req.Settings
+		if err := validateSettings(settings); err != nil {
+			return nil, err
+		}
+		// The memory limit is a quota, which only admins may change
+		if p, _ := auth.PrincipalFromContext(ctx); !p.IsPrivileged() {
+			settings.MaxMemories = u.Settings.MaxMemories
+		}
+		u.UpdateSettings(settings)
+	}
+
+	if req.Role != nil {
+		if !user.ValidRole(*req.Role) {
+			return nil, user.ErrInvalidRole
+		}
+		u.SetRole(*req.Role)
 	}
 
 	if req.IsActive != nil {
@@ -205,6 +222,13 @@ func (s *service) validateCreateRequest(req user.CreateRequest) error {
 		return user.ErrInvalidEmail
 	}
 
+	return validateSettings(req.Settings)
+}
+
+func validateSettings(settings user.Settings) error {
+	if settings.PIIPolicy != "" && !pii.ValidPolicy(settings.PIIPolicy) {
+		return user.ErrInvalidSettings
+	}
 	return nil
 }
 
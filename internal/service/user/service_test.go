@@ -0,0 +1,87 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/user"
+)
+
+// Mock user repository
+type mockUserRepository struct {
+	user.Repository
+	mock.Mock
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id user.ID) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *mockUserRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func TestService_UpdateUser_Settings(t *testing.T) {
+	userID := user.ID(uuid.New())
+
+	tests := []struct {
+		name            string
+		role            string
+		settings        user.Settings
+		wantMaxMemories int
+		wantErr         error
+	}{
+		{
+			name:            "user keeps their memory limit",
+			role:            auth.RoleUser,
+			settings:        user.Settings{Language: "de", MaxMemories: 1000000},
+			wantMaxMemories: 100,
+		},
+		{
+			name:            "admin changes the memory limit",
+			role:            auth.RoleAdmin,
+			settings:        user.Settings{Language: "de", MaxMemories: 1000000},
+			wantMaxMemories: 1000000,
+		},
+		{
+			name:     "invalid personal data policy",
+			role:     auth.RoleUser,
+			settings: user.Settings{PIIPolicy: "shred"},
+			wantErr:  user.ErrInvalidSettings,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockUserRepository{}
+			existing := &user.User{ID: userID, Username: "alice", Settings: user.Settings{Language: "en", MaxMemories: 100}}
+			repo.On("FindByID", mock.Anything, userID).Return(existing, nil)
+			if tt.wantErr == nil {
+				repo.On("Update", mock.Anything, existing).Return(nil)
+			}
+			ctx := auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: tt.role})
+
+			u, err := NewService(repo).UpdateUser(ctx, userID, user.UpdateRequest{Settings: &tt.settings})
+
+			repo.AssertExpectations(t)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "de", u.Settings.Language)
+			assert.Equal(t, tt.wantMaxMemories, u.Settings.MaxMemories)
+		})
+	}
+}
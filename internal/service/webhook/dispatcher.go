@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/webhook"
+	"mem_bank/internal/queue"
+	"mem_bank/pkg/database"
+	"mem_bank/pkg/logger"
+)
+
+// Dispatcher moves events from the outbox to the job queue: each pending
+// event becomes a delivery job per subscriber and is then marked
+// dispatched. An event whose jobs could not all be queued is dispatched
+// again later, so subscribers receive events at least once.
+type Dispatcher struct {
+	events     event.Repository
+	repo       webhook.Repository
+	jobQueue   queue.Producer
+	jobFactory *queue.JobFactory
+	logger     logger.Logger
+	config     Config
+}
+
+// NewDispatcher creates a new webhook dispatcher
+func NewDispatcher(events event.Repository, repo webhook.Repository, jobQueue queue.Producer, logger logger.Logger, config Config) *Dispatcher {
+	return &Dispatcher{
+		events:     events,
+		repo:       repo,
+		jobQueue:   jobQueue,
+		jobFactory: queue.NewJobFactory(),
+		logger:     logger,
+		config:     config.withDefaults(),
+	}
+}
+
+// Run dispatches pending events every dispatch interval until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.DispatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.Dispatch(ctx); err != nil {
+				d.logger.WithError(err).Error("Failed to dispatch memory events")
+			}
+		}
+	}
+}
+
+// Dispatch queues the deliveries of the pending events and returns how
+// many events it dispatched
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	// Subscribers are found across users
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+
+	total := 0
+	for {
+		n, err := d.events.DispatchPending(ctx, d.config.DispatchBatchSize, func(e *event.Event) error {
+			return d.dispatch(ctx, e)
+		})
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < d.config.DispatchBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, e *event.Event) error {
+	subscribers, err := d.repo.FindSubscribers(ctx, e)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subscribers {
+		job := d.jobFactory.CreateDeliverWebhookJob(sub, e, d.config.MaxAttempts)
+		if err := d.jobQueue.Enqueue(ctx, job); err != nil {
+			return fmt.Errorf("queuing webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
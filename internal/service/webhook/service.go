@@ -0,0 +1,379 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/domain/webhook"
+	"mem_bank/pkg/database"
+	"mem_bank/pkg/logger"
+	pkgwebhook "mem_bank/pkg/webhook"
+)
+
+const (
+	// secretBytes sizes the signing secret of a subscription
+	secretBytes = 32
+
+	// maxDescriptionLength bounds subscription descriptions
+	maxDescriptionLength = 255
+
+	// maxErrorLength bounds the error kept in the delivery log
+	maxErrorLength = 1000
+
+	// Limits on the deliveries returned by ListDeliveries
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// Config holds webhook configuration
+type Config struct {
+	// Maximum number of subscriptions per user
+	MaxSubscriptionsPerUser int `mapstructure:"max_subscriptions_per_user"`
+	// Timeout of one delivery attempt
+	Timeout time.Duration `mapstructure:"timeout"`
+	// AllowPrivateNetworks permits endpoints on loopback, private and
+	// link-local addresses; for development only
+	AllowPrivateNetworks bool `mapstructure:"allow_private_networks"`
+	// Attempts per delivery before it is given up
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// How often and how many pending events the dispatcher picks up
+	DispatchInterval  time.Duration `mapstructure:"dispatch_interval"`
+	DispatchBatchSize int           `mapstructure:"dispatch_batch_size"`
+}
+
+// DefaultConfig returns the default webhook configuration
+func DefaultConfig() Config {
+	return Config{
+		MaxSubscriptionsPerUser: 10,
+		Timeout:                 10 * time.Second,
+		MaxAttempts:             5,
+		DispatchInterval:        2 * time.Second,
+		DispatchBatchSize:       100,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.MaxSubscriptionsPerUser <= 0 {
+		c.MaxSubscriptionsPerUser = defaults.MaxSubscriptionsPerUser
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaults.Timeout
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaults.MaxAttempts
+	}
+	if c.DispatchInterval <= 0 {
+		c.DispatchInterval = defaults.DispatchInterval
+	}
+	if c.DispatchBatchSize <= 0 {
+		c.DispatchBatchSize = defaults.DispatchBatchSize
+	}
+	return c
+}
+
+// Payload is the body of a webhook request, and an event as the feed
+// returns it
+type Payload struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	Sequence  int64                  `json:"sequence"`
+	MemoryID  string                 `json:"memory_id"`
+	UserID    string                 `json:"user_id"`
+	SpaceID   string                 `json:"space_id,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NewPayload describes e for consumers
+func NewPayload(e *event.Event) Payload {
+	p := Payload{
+		ID:        e.ID.String(),
+		Type:      e.Type,
+		Sequence:  e.Sequence,
+		MemoryID:  e.MemoryID.String(),
+		UserID:    e.UserID.String(),
+		Data:      e.Data,
+		CreatedAt: e.CreatedAt,
+	}
+	if !e.SpaceID.IsZero() {
+		p.SpaceID = e.SpaceID.String()
+	}
+	return p
+}
+
+// Service implements webhook.Service. Deliveries are POST requests with a
+// JSON Payload, signed with the subscription's secret as described in
+// pkg/webhook.
+type Service struct {
+	repo   webhook.Repository
+	events event.Repository
+	client *http.Client
+	logger logger.Logger
+	config Config
+	now    func() time.Time
+}
+
+// NewService creates a new webhook service
+func NewService(repo webhook.Repository, events event.Repository, logger logger.Logger, config Config) *Service {
+	config = config.withDefaults()
+	return &Service{
+		repo:   repo,
+		events: events,
+		client: pkgwebhook.NewClient(config.Timeout, config.AllowPrivateNetworks),
+		logger: logger,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, userID user.ID, req webhook.CreateRequest) (*webhook.Subscription, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	sub := &webhook.Subscription{
+		ID:     webhook.NewID(),
+		UserID: userID,
+		Active: true,
+	}
+	if err := s.apply(sub, webhook.UpdateRequest{URL: &req.URL, Description: &req.Description, EventTypes: &req.EventTypes}); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.config.MaxSubscriptionsPerUser {
+		return nil, webhook.ErrTooManySubscriptions
+	}
+
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generating webhook secret: %w", err)
+	}
+	sub.Secret = "whsec_" + hex.EncodeToString(secret)
+	sub.CreatedAt = s.now()
+	sub.UpdatedAt = sub.CreatedAt
+
+	if err := s.repo.Store(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"user_id":         userID.String(),
+		"subscription_id": sub.ID.String(),
+	}).Info("Webhook subscription created")
+
+	return sub, nil
+}
+
+func (s *Service) List(ctx context.Context, userID user.ID) ([]*webhook.Subscription, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByUserID(ctx, userID)
+}
+
+func (s *Service) Get(ctx context.Context, userID user.ID, id webhook.ID) (*webhook.Subscription, error) {
+	if err := auth.Authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Other users' subscriptions are reported as missing
+	if sub.UserID != userID {
+		return nil, webhook.ErrNotFound
+	}
+	return sub, nil
+}
+
+func (s *Service) Update(ctx context.Context, userID user.ID, id webhook.ID, req webhook.UpdateRequest) (*webhook.Subscription, error) {
+	sub, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(sub, req); err != nil {
+		return nil, err
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	sub.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *Service) Delete(ctx context.Context, userID user.ID, id webhook.ID) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"user_id":         userID.String(),
+		"subscription_id": id.String(),
+	}).Info("Webhook subscription deleted")
+	return nil
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, userID user.ID, id webhook.ID, limit, offset int) ([]*webhook.Delivery, error) {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	return s.repo.ListDeliveries(ctx, id, min(limit, maxDeliveryLimit), max(offset, 0))
+}
+
+func (s *Service) Deliver(ctx context.Context, id webhook.ID, eventID event.ID, attempt int) error {
+	// The job only names a subscription, so it runs outside any one user's rows
+	ctx = database.WithTenant(ctx, database.Tenant{Bypass: true})
+
+	// Subscriptions deleted or paused and events purged since the event was
+	// dispatched are dropped
+	sub, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, webhook.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !sub.Active {
+		return nil
+	}
+	e, err := s.events.FindByID(ctx, eventID)
+	if errors.Is(err, event.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d := &webhook.Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		EventID:        e.ID,
+		EventType:      e.Type,
+		Attempt:        attempt,
+		CreatedAt:      s.now(),
+	}
+	d.StatusCode, err = s.post(ctx, sub, e)
+	d.Duration = s.now().Sub(d.CreatedAt)
+	d.Succeeded = err == nil
+	if err != nil {
+		d.Error = err.Error()
+		if len(d.Error) > maxErrorLength {
+			d.Error = d.Error[:maxErrorLength]
+		}
+	}
+
+	if storeErr := s.repo.StoreDelivery(ctx, d); storeErr != nil {
+		// The log is best effort; the outcome of the delivery decides retries
+		s.logger.WithError(storeErr).WithField("subscription_id", sub.ID.String()).Warn("Failed to record webhook delivery")
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"subscription_id": sub.ID.String(),
+		"event_id":        e.ID.String(),
+		"event_type":      e.Type,
+		"attempt":         attempt,
+		"status_code":     d.StatusCode,
+		"duration_ms":     d.Duration.Milliseconds(),
+		"succeeded":       d.Succeeded,
+	}).Debug("Webhook delivered")
+
+	return err
+}
+
+// post sends e to the subscription's endpoint and returns the response's
+// status code; responses outside 2xx are errors
+func (s *Service) post(ctx context.Context, sub *webhook.Subscription, e *event.Event) (int, error) {
+	body, err := json.Marshal(NewPayload(e))
+	if err != nil {
+		return 0, fmt.Errorf("marshaling webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("creating webhook request: %w", err)
+	}
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mem_bank-webhooks/1.0")
+	req.Header.Set(pkgwebhook.HeaderEvent, e.Type)
+	req.Header.Set(pkgwebhook.HeaderEventID, e.ID.String())
+	req.Header.Set(pkgwebhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(pkgwebhook.HeaderSignature, pkgwebhook.Sign(sub.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// apply validates and sets the fields of req on sub
+func (s *Service) apply(sub *webhook.Subscription, req webhook.UpdateRequest) error {
+	if req.URL != nil {
+		url := strings.TrimSpace(*req.URL)
+		if err := pkgwebhook.ValidateURL(url, s.config.AllowPrivateNetworks); err != nil {
+			return fmt.Errorf("%w: %v", webhook.ErrInvalidURL, err)
+		}
+		sub.URL = url
+	}
+	if req.Description != nil {
+		description := strings.TrimSpace(*req.Description)
+		if len(description) > maxDescriptionLength {
+			return webhook.ErrInvalidDescription
+		}
+		sub.Description = description
+	}
+	if req.EventTypes != nil {
+		seen := make(map[string]bool, len(*req.EventTypes))
+		types := make([]string, 0, len(*req.EventTypes))
+		for _, t := range *req.EventTypes {
+			if !event.ValidType(t) {
+				return fmt.Errorf("%w: %q", webhook.ErrInvalidEventType, t)
+			}
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+		sub.EventTypes = types
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/internal/domain/auth"
+	"mem_bank/internal/domain/event"
+	"mem_bank/internal/domain/memory"
+	"mem_bank/internal/domain/user"
+	"mem_bank/internal/domain/webhook"
+	"mem_bank/internal/queue"
+	"mem_bank/pkg/logger"
+	pkgwebhook "mem_bank/pkg/webhook"
+)
+
+// Mock webhook repository
+type mockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *mockWebhookRepository) Store(ctx context.Context, s *webhook.Subscription) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepository) FindByID(ctx context.Context, id webhook.ID) (*webhook.Subscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Subscription), args.Error(1)
+}
+
+func (m *mockWebhookRepository) ListByUserID(ctx context.Context, userID user.ID) ([]*webhook.Subscription, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Subscription), args.Error(1)
+}
+
+func (m *mockWebhookRepository) CountByUserID(ctx context.Context, userID user.ID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockWebhookRepository) Update(ctx context.Context, s *webhook.Subscription) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepository) Delete(ctx context.Context, id webhook.ID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepository) FindSubscribers(ctx context.Context, e *event.Event) ([]*webhook.Subscription, error) {
+	args := m.Called(ctx, e)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Subscription), args.Error(1)
+}
+
+func (m *mockWebhookRepository) StoreDelivery(ctx context.Context, d *webhook.Delivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepository) ListDeliveries(ctx context.Context, id webhook.ID, limit, offset int) ([]*webhook.Delivery, error) {
+	args := m.Called(ctx, id, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Delivery), args.Error(1)
+}
+
+// Mock event repository; only the methods under test are mocked
+type mockEventRepository struct {
+	event.Repository
+	mock.Mock
+}
+
+func (m *mockEventRepository) FindByID(ctx context.Context, id event.ID) (*event.Event, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*event.Event), args.Error(1)
+}
+
+func (m *mockEventRepository) DispatchPending(ctx context.Context, limit int, fn func(e *event.Event) error) (int, error) {
+	args := m.Called(ctx, limit, fn)
+	return args.Int(0), args.Error(1)
+}
+
+// Mock job producer; only the methods under test are mocked
+type mockProducer struct {
+	queue.Producer
+	mock.Mock
+}
+
+func (m *mockProducer) Enqueue(ctx context.Context, job *queue.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+// Mock logger
+type mockLogger struct {
+	mock.Mock
+}
+
+func (m *mockLogger) WithError(err error) logger.Logger                      { return m }
+func (m *mockLogger) WithField(key string, value interface{}) logger.Logger  { return m }
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.Logger { return m }
+func (m *mockLogger) Debug(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Info(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Warn(args ...interface{})                               { m.Called(args...) }
+func (m *mockLogger) Error(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Fatal(args ...interface{})                              { m.Called(args...) }
+func (m *mockLogger) Debugf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Infof(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Warnf(format string, args ...interface{})               { m.Called(format, args) }
+func (m *mockLogger) Errorf(format string, args ...interface{})              { m.Called(format, args) }
+func (m *mockLogger) Fatalf(format string, args ...interface{})              { m.Called(format, args) }
+
+// testConfig lets test servers listen on loopback
+var testConfig = Config{AllowPrivateNetworks: true, Timeout: time.Second}
+
+func principalContext(userID user.ID) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID, Role: auth.RoleUser})
+}
+
+func TestService_Create(t *testing.T) {
+	owner := user.ID(uuid.New())
+
+	tests := []struct {
+		name       string
+		userID     user.ID
+		config     Config
+		req        webhook.CreateRequest
+		setupMocks func(*mockWebhookRepository, *mockLogger)
+		want       *webhook.Subscription
+		wantErr    error
+	}{
+		{
+			name:   "successful creation",
+			userID: owner,
+			config: testConfig,
+			req: webhook.CreateRequest{
+				URL:        " https://example.com/hook ",
+				EventTypes: []string{event.TypeMemoryCreated, event.TypeMemoryCreated},
+			},
+			setupMocks: func(r *mockWebhookRepository, l *mockLogger) {
+				r.On("CountByUserID", mock.Anything, owner).Return(0, nil)
+				r.On("Store", mock.Anything, mock.AnythingOfType("*webhook.Subscription")).Return(nil)
+				l.On("Info", "Webhook subscription created").Once()
+			},
+			want: &webhook.Subscription{
+				UserID:     owner,
+				URL:        "https://example.com/hook",
+				EventTypes: []string{event.TypeMemoryCreated},
+				Active:     true,
+			},
+		},
+		{
+			name:    "unsupported scheme",
+			userID:  owner,
+			config:  testConfig,
+			req:     webhook.CreateRequest{URL: "ftp://example.com"},
+			wantErr: webhook.ErrInvalidURL,
+		},
+		{
+			name:    "internal address refused by default",
+			userID:  owner,
+			req:     webhook.CreateRequest{URL: "http://169.254.169.254/latest"},
+			wantErr: webhook.ErrInvalidURL,
+		},
+		{
+			name:    "unknown event type",
+			userID:  owner,
+			config:  testConfig,
+			req:     webhook.CreateRequest{URL: "https://example.com", EventTypes: []string{"memory.read"}},
+			wantErr: webhook.ErrInvalidEventType,
+		},
+		{
+			name:    "other user is denied",
+			userID:  user.ID(uuid.New()),
+			config:  testConfig,
+			req:     webhook.CreateRequest{URL: "https://example.com"},
+			wantErr: auth.ErrPermissionDenied,
+		},
+		{
+			name:   "too many subscriptions",
+			userID: owner,
+			config: Config{AllowPrivateNetworks: true, MaxSubscriptionsPerUser: 1},
+			req:    webhook.CreateRequest{URL: "https://example.com/other"},
+			setupMocks: func(r *mockWebhookRepository, l *mockLogger) {
+				r.On("CountByUserID", mock.Anything, owner).Return(1, nil)
+			},
+			wantErr: webhook.ErrTooManySubscriptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockWebhookRepository{}
+			log := &mockLogger{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(repo, log)
+			}
+			service := NewService(repo, &mockEventRepository{}, log, tt.config)
+
+			sub, err := service.Create(principalContext(owner), tt.userID, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, sub)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want.UserID, sub.UserID)
+				assert.Equal(t, tt.want.URL, sub.URL)
+				assert.Equal(t, tt.want.EventTypes, sub.EventTypes)
+				assert.Equal(t, tt.want.Active, sub.Active)
+				assert.Regexp(t, "^whsec_[0-9a-f]{64}$", sub.Secret)
+			}
+
+			repo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Get(t *testing.T) {
+	owner := user.ID(uuid.New())
+	other := user.ID(uuid.New())
+	sub := &webhook.Subscription{ID: webhook.NewID(), UserID: owner, URL: "https://example.com/hook", Active: true}
+	missing := webhook.NewID()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		userID  user.ID
+		id      webhook.ID
+		wantErr error
+	}{
+		{"owner reads the subscription", principalContext(owner), owner, sub.ID, nil},
+		{"other user's subscription is missing", principalContext(other), other, sub.ID, webhook.ErrNotFound},
+		{"other user's list is denied", principalContext(other), owner, sub.ID, auth.ErrPermissionDenied},
+		{"missing subscription", principalContext(owner), owner, missing, webhook.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockWebhookRepository{}
+			repo.On("FindByID", mock.Anything, sub.ID).Return(sub, nil).Maybe()
+			repo.On("FindByID", mock.Anything, missing).Return(nil, webhook.ErrNotFound).Maybe()
+			service := NewService(repo, &mockEventRepository{}, &mockLogger{}, testConfig)
+
+			found, err := service.Get(tt.ctx, tt.userID, tt.id)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				assert.Equal(t, sub.ID, found.ID)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			// Update and Delete look the subscription up the same way
+			active := false
+			_, err = service.Update(tt.ctx, tt.userID, tt.id, webhook.UpdateRequest{Active: &active})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorIs(t, service.Delete(tt.ctx, tt.userID, tt.id), tt.wantErr)
+		})
+	}
+}
+
+func TestService_Update(t *testing.T) {
+	owner := user.ID(uuid.New())
+	sub := &webhook.Subscription{ID: webhook.NewID(), UserID: owner, URL: "https://example.com/hook", Active: true}
+
+	repo := &mockWebhookRepository{}
+	repo.On("FindByID", mock.Anything, sub.ID).Return(sub, nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(s *webhook.Subscription) bool {
+		return s.ID == sub.ID && !s.Active
+	})).Return(nil)
+	service := NewService(repo, &mockEventRepository{}, &mockLogger{}, testConfig)
+
+	active := false
+	updated, err := service.Update(principalContext(owner), owner, sub.ID, webhook.UpdateRequest{Active: &active})
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+	assert.Equal(t, "https://example.com/hook", updated.URL)
+
+	repo.AssertExpectations(t)
+}
+
+func TestService_Deliver(t *testing.T) {
+	owner := user.ID(uuid.New())
+	m := memory.NewMemory(owner, "content", "", 5, "fact")
+	e := event.NewMemoryEvent(event.TypeMemoryCreated, m)
+	missingEvent := event.NewID()
+
+	var received *http.Request
+	var body []byte
+	responseStatus := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(responseStatus)
+	}))
+	defer server.Close()
+
+	sub := &webhook.Subscription{ID: webhook.NewID(), UserID: owner, URL: server.URL, Secret: "whsec_test", Active: true}
+	paused := &webhook.Subscription{ID: webhook.NewID(), UserID: owner, URL: server.URL, Secret: "whsec_test"}
+	deleted := webhook.NewID()
+
+	tests := []struct {
+		name           string
+		subscriptionID webhook.ID
+		eventID        event.ID
+		status         int
+		wantDelivery   *webhook.Delivery
+		wantErr        bool
+	}{
+		{
+			name:           "successful delivery",
+			subscriptionID: sub.ID,
+			eventID:        e.ID,
+			status:         http.StatusOK,
+			wantDelivery:   &webhook.Delivery{Succeeded: true, StatusCode: http.StatusOK, Attempt: 1},
+		},
+		{
+			name:           "failures are returned so the job is retried",
+			subscriptionID: sub.ID,
+			eventID:        e.ID,
+			status:         http.StatusServiceUnavailable,
+			wantDelivery:   &webhook.Delivery{Succeeded: false, StatusCode: http.StatusServiceUnavailable, Attempt: 1},
+			wantErr:        true,
+		},
+		{name: "paused subscription is dropped", subscriptionID: paused.ID, eventID: e.ID},
+		{name: "deleted subscription is dropped", subscriptionID: deleted, eventID: e.ID},
+		{name: "purged event is dropped", subscriptionID: sub.ID, eventID: missingEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received, body, responseStatus = nil, nil, tt.status
+
+			repo := &mockWebhookRepository{}
+			repo.On("FindByID", mock.Anything, sub.ID).Return(sub, nil).Maybe()
+			repo.On("FindByID", mock.Anything, paused.ID).Return(paused, nil).Maybe()
+			repo.On("FindByID", mock.Anything, deleted).Return(nil, webhook.ErrNotFound).Maybe()
+			events := &mockEventRepository{}
+			events.On("FindByID", mock.Anything, e.ID).Return(e, nil).Maybe()
+			events.On("FindByID", mock.Anything, missingEvent).Return(nil, event.ErrNotFound).Maybe()
+			log := &mockLogger{}
+
+			var delivery *webhook.Delivery
+			if tt.wantDelivery != nil {
+				repo.On("StoreDelivery", mock.Anything, mock.AnythingOfType("*webhook.Delivery")).Run(func(args mock.Arguments) {
+					delivery = args.Get(1).(*webhook.Delivery)
+				}).Return(nil).Once()
+				log.On("Debug", "Webhook delivered").Once()
+			}
+
+			service := NewService(repo, events, log, testConfig)
+			err := service.Deliver(context.Background(), tt.subscriptionID, tt.eventID, 1)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.wantDelivery == nil {
+				assert.Nil(t, received, "nothing is sent")
+			} else {
+				require.NotNil(t, received)
+				assert.Equal(t, event.TypeMemoryCreated, received.Header.Get(pkgwebhook.HeaderEvent))
+				assert.Equal(t, e.ID.String(), received.Header.Get(pkgwebhook.HeaderEventID))
+				assert.NoError(t, pkgwebhook.Verify(sub.Secret,
+					received.Header.Get(pkgwebhook.HeaderTimestamp),
+					received.Header.Get(pkgwebhook.HeaderSignature),
+					body, time.Minute, time.Now()))
+				assert.Contains(t, string(body), e.MemoryID.String())
+
+				require.NotNil(t, delivery)
+				assert.Equal(t, sub.ID, delivery.SubscriptionID)
+				assert.Equal(t, e.ID, delivery.EventID)
+				assert.Equal(t, tt.wantDelivery.Succeeded, delivery.Succeeded)
+				assert.Equal(t, tt.wantDelivery.StatusCode, delivery.StatusCode)
+				assert.Equal(t, tt.wantDelivery.Attempt, delivery.Attempt)
+				if !tt.wantDelivery.Succeeded {
+					assert.Contains(t, delivery.Error, "503")
+				}
+			}
+
+			repo.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	owner := user.ID(uuid.New())
+	m := memory.NewMemory(owner, "content", "", 5, "fact")
+	created := event.NewMemoryEvent(event.TypeMemoryCreated, m)
+	deleted := event.NewMemoryEvent(event.TypeMemoryDeleted, m)
+	all := &webhook.Subscription{ID: webhook.NewID(), UserID: owner, Active: true}
+	deletes := &webhook.Subscription{ID: webhook.NewID(), UserID: owner, Active: true, EventTypes: []string{event.TypeMemoryDeleted}}
+
+	// One event per batch: the dispatcher keeps going until a batch comes
+	// back short
+	dispatch := func(e *event.Event) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			fn := args.Get(2).(func(*event.Event) error)
+			require.NoError(t, fn(e))
+		}
+	}
+	events := &mockEventRepository{}
+	events.On("DispatchPending", mock.Anything, 1, mock.Anything).Run(dispatch(created)).Return(1, nil).Once()
+	events.On("DispatchPending", mock.Anything, 1, mock.Anything).Run(dispatch(deleted)).Return(1, nil).Once()
+	events.On("DispatchPending", mock.Anything, 1, mock.Anything).Return(0, nil).Once()
+
+	repo := &mockWebhookRepository{}
+	repo.On("FindSubscribers", mock.Anything, created).Return([]*webhook.Subscription{all}, nil)
+	repo.On("FindSubscribers", mock.Anything, deleted).Return([]*webhook.Subscription{all, deletes}, nil)
+
+	deliveries := make(map[string][]string)
+	producer := &mockProducer{}
+	producer.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *queue.Job) bool {
+		return job.Type == queue.JobTypeDeliverWebhook && job.MaxRetries == 3 && job.Payload["user_id"] == owner.String()
+	})).Run(func(args mock.Arguments) {
+		job := args.Get(1).(*queue.Job)
+		subscriptionID := job.Payload["subscription_id"].(string)
+		deliveries[subscriptionID] = append(deliveries[subscriptionID], job.Payload["event_id"].(string))
+	}).Return(nil).Times(3)
+
+	dispatcher := NewDispatcher(events, repo, producer, &mockLogger{}, Config{DispatchBatchSize: 1, MaxAttempts: 3})
+	n, err := dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{created.ID.String(), deleted.ID.String()}, deliveries[all.ID.String()])
+	assert.Equal(t, []string{deleted.ID.String()}, deliveries[deletes.ID.String()])
+
+	events.AssertExpectations(t)
+	repo.AssertExpectations(t)
+	producer.AssertExpectations(t)
+}
@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_memories_embedded;
+DROP INDEX IF EXISTS idx_memories_missing_embedding;
+
+-- Drop columns
+ALTER TABLE memories DROP COLUMN IF EXISTS embedding_model;
@@ -0,0 +1,9 @@
+-- Track which model produced each embedding so memories can be re-embedded after a model change
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS embedding_model VARCHAR(100);
+
+-- Partial index for memories still waiting for an embedding
+CREATE INDEX IF NOT EXISTS idx_memories_missing_embedding ON memories(user_id, id) WHERE embedding IS NULL;
+
+-- Partial index for walking embedded memories to find those produced by an outdated model;
+-- together with idx_memories_missing_embedding it serves both sides of the re-embed scan
+CREATE INDEX IF NOT EXISTS idx_memories_embedded ON memories(user_id, id) WHERE embedding IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_llm_usage_user_created;
+
+-- Drop tables
+DROP TABLE IF EXISTS llm_usage;
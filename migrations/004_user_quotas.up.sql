@@ -0,0 +1,9 @@
+-- Quota plan assignment per user; NULL limits inherit the plan's value
+CREATE TABLE IF NOT EXISTS user_quotas (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    plan VARCHAR(50) NOT NULL,
+    max_storage_bytes BIGINT,
+    monthly_embedding_tokens BIGINT,
+    monthly_completion_tokens BIGINT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,13 @@
+-- Asymmetric JWT signing keys shared by every replica. A key signs tokens
+-- from active_at until its successor becomes active and verifies them
+-- until retire_at. Private keys are PKCS #8 PEM.
+CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+    id VARCHAR(64) PRIMARY KEY,
+    algorithm VARCHAR(16) NOT NULL,
+    private_key BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    active_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    retire_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_jwt_signing_keys_retire_at ON jwt_signing_keys(retire_at);
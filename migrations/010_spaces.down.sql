@@ -0,0 +1,10 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_memories_personal;
+DROP INDEX IF EXISTS idx_memories_space_id;
+
+-- Drop columns
+ALTER TABLE memories DROP COLUMN IF EXISTS space_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS space_members;
+DROP TABLE IF EXISTS spaces;
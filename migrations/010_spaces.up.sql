@@ -0,0 +1,32 @@
+-- Shared memory spaces and their members. Memories with a space_id belong
+-- to that space and are visible to all of its members; memories without
+-- one belong to the personal space of their user.
+CREATE TABLE IF NOT EXISTS spaces (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS space_members (
+    space_id UUID NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (space_id, user_id)
+);
+
+-- Membership lookups by user drive every shared-space query
+CREATE INDEX IF NOT EXISTS idx_space_members_user_id ON space_members(user_id, space_id);
+
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS space_id UUID REFERENCES spaces(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_memories_space_id ON memories(space_id, created_at DESC) WHERE space_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_memories_personal ON memories(user_id, created_at DESC) WHERE space_id IS NULL;
+
+CREATE TRIGGER update_spaces_updated_at
+    BEFORE UPDATE ON spaces
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,31 @@
+-- Drop policies
+DROP POLICY IF EXISTS user_quotas_write ON user_quotas;
+DROP POLICY IF EXISTS user_quotas_select ON user_quotas;
+DROP POLICY IF EXISTS llm_usage_write ON llm_usage;
+DROP POLICY IF EXISTS llm_usage_select ON llm_usage;
+DROP POLICY IF EXISTS memories_tenant ON memories;
+DROP POLICY IF EXISTS spaces_delete ON spaces;
+DROP POLICY IF EXISTS spaces_update ON spaces;
+DROP POLICY IF EXISTS spaces_insert ON spaces;
+DROP POLICY IF EXISTS spaces_select ON spaces;
+DROP POLICY IF EXISTS space_members_delete ON space_members;
+DROP POLICY IF EXISTS space_members_update ON space_members;
+DROP POLICY IF EXISTS space_members_insert ON space_members;
+DROP POLICY IF EXISTS space_members_select ON space_members;
+
+-- Disable row-level security
+ALTER TABLE user_quotas NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE user_quotas DISABLE ROW LEVEL SECURITY;
+ALTER TABLE llm_usage NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE llm_usage DISABLE ROW LEVEL SECURITY;
+ALTER TABLE memories NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE memories DISABLE ROW LEVEL SECURITY;
+ALTER TABLE spaces NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE spaces DISABLE ROW LEVEL SECURITY;
+ALTER TABLE space_members NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE space_members DISABLE ROW LEVEL SECURITY;
+
+-- Drop functions
+DROP FUNCTION IF EXISTS mem_bank_manages_space(UUID);
+DROP FUNCTION IF EXISTS mem_bank_bypass_rls();
+DROP FUNCTION IF EXISTS mem_bank_current_user_id();
@@ -0,0 +1,110 @@
+-- Row-level security on tenant data. The application sets the caller in
+-- the mem_bank.user_id session variable for each transaction, see
+-- pkg/database.Transaction; admin and system callers set
+-- mem_bank.bypass_rls instead. Without either, the policies match nothing.
+-- FORCE applies the policies to the table owner as well, so they hold when
+-- the application connects as the user that ran the migrations. Superusers
+-- and roles with BYPASSRLS are never subject to them.
+--
+-- Authentication tables (credentials, refresh tokens, API keys, identities)
+-- are read before a caller is known and are not covered.
+
+CREATE OR REPLACE FUNCTION mem_bank_current_user_id() RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('mem_bank.user_id', true), '')::UUID
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION mem_bank_bypass_rls() RETURNS BOOLEAN AS $$
+    SELECT COALESCE(current_setting('mem_bank.bypass_rls', true), '') = 'on'
+$$ LANGUAGE sql STABLE;
+
+-- mem_bank_manages_space reports whether the caller may change the
+-- memberships of a space: its owners may, and so may its creator while it
+-- has no members yet, which lets a new space receive its first owner. As a
+-- function it is opaque to the planner, so the space_members policies can
+-- consult memberships without recursing into themselves; SECURITY DEFINER
+-- keeps the check independent of what the caller's role may read.
+CREATE OR REPLACE FUNCTION mem_bank_manages_space(target UUID) RETURNS BOOLEAN AS $$
+    SELECT EXISTS (
+            SELECT 1 FROM space_members
+            WHERE space_id = target AND user_id = mem_bank_current_user_id() AND role = 'owner')
+        OR (EXISTS (SELECT 1 FROM spaces WHERE id = target AND created_by = mem_bank_current_user_id())
+            AND NOT EXISTS (SELECT 1 FROM space_members WHERE space_id = target))
+$$ LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public;
+
+-- Memberships are what the other policies consult and hold no content, so
+-- any caller may read them. Only owners may change them, except that
+-- members may always leave.
+ALTER TABLE space_members ENABLE ROW LEVEL SECURITY;
+ALTER TABLE space_members FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY space_members_select ON space_members FOR SELECT
+    USING (mem_bank_bypass_rls() OR mem_bank_current_user_id() IS NOT NULL);
+
+CREATE POLICY space_members_insert ON space_members FOR INSERT
+    WITH CHECK (mem_bank_bypass_rls() OR mem_bank_manages_space(space_id));
+
+CREATE POLICY space_members_update ON space_members FOR UPDATE
+    USING (mem_bank_bypass_rls() OR mem_bank_manages_space(space_id))
+    WITH CHECK (mem_bank_bypass_rls() OR mem_bank_manages_space(space_id));
+
+CREATE POLICY space_members_delete ON space_members FOR DELETE
+    USING (mem_bank_bypass_rls()
+        OR user_id = mem_bank_current_user_id()
+        OR mem_bank_manages_space(space_id));
+
+-- Spaces are visible to their members. The creator may also see a space,
+-- which lets it be inserted before its first member exists.
+ALTER TABLE spaces ENABLE ROW LEVEL SECURITY;
+ALTER TABLE spaces FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY spaces_select ON spaces FOR SELECT
+    USING (mem_bank_bypass_rls()
+        OR created_by = mem_bank_current_user_id()
+        OR id IN (SELECT space_id FROM space_members WHERE user_id = mem_bank_current_user_id()));
+
+CREATE POLICY spaces_insert ON spaces FOR INSERT
+    WITH CHECK (mem_bank_bypass_rls() OR created_by = mem_bank_current_user_id());
+
+CREATE POLICY spaces_update ON spaces FOR UPDATE
+    USING (mem_bank_bypass_rls()
+        OR id IN (SELECT space_id FROM space_members WHERE user_id = mem_bank_current_user_id()));
+
+CREATE POLICY spaces_delete ON spaces FOR DELETE
+    USING (mem_bank_bypass_rls()
+        OR id IN (SELECT space_id FROM space_members WHERE user_id = mem_bank_current_user_id()));
+
+-- Memories are visible to their author while personal and to the members
+-- of their space while shared. Which members may write is up to the
+-- memory service.
+ALTER TABLE memories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE memories FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY memories_tenant ON memories
+    USING (mem_bank_bypass_rls()
+        OR (space_id IS NULL AND user_id = mem_bank_current_user_id())
+        OR space_id IN (SELECT space_id FROM space_members WHERE user_id = mem_bank_current_user_id()))
+    WITH CHECK (mem_bank_bypass_rls()
+        OR (space_id IS NULL AND user_id = mem_bank_current_user_id())
+        OR space_id IN (SELECT space_id FROM space_members WHERE user_id = mem_bank_current_user_id()));
+
+-- The LLM usage ledger and quota assignments are readable by their user.
+-- Only the accounting layer and admins write them, under bypass.
+ALTER TABLE llm_usage ENABLE ROW LEVEL SECURITY;
+ALTER TABLE llm_usage FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY llm_usage_select ON llm_usage FOR SELECT
+    USING (mem_bank_bypass_rls() OR user_id = mem_bank_current_user_id());
+
+CREATE POLICY llm_usage_write ON llm_usage
+    USING (mem_bank_bypass_rls())
+    WITH CHECK (mem_bank_bypass_rls());
+
+ALTER TABLE user_quotas ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_quotas FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY user_quotas_select ON user_quotas FOR SELECT
+    USING (mem_bank_bypass_rls() OR user_id = mem_bank_current_user_id());
+
+CREATE POLICY user_quotas_write ON user_quotas
+    USING (mem_bank_bypass_rls())
+    WITH CHECK (mem_bank_bypass_rls());
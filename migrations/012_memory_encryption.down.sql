@@ -0,0 +1,17 @@
+-- Encrypted memories cannot be read once their key versions and data keys
+-- are dropped.
+
+-- Drop policies
+DROP POLICY IF EXISTS memory_search_tokens_tenant ON memory_search_tokens;
+DROP POLICY IF EXISTS user_data_keys_bypass ON user_data_keys;
+
+-- Drop columns
+ALTER TABLE memories DROP COLUMN IF EXISTS key_version;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_memory_search_tokens_token;
+DROP INDEX IF EXISTS idx_user_data_keys_master_key_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS memory_search_tokens;
+DROP TABLE IF EXISTS user_data_keys;
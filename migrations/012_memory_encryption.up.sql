@@ -0,0 +1,43 @@
+-- Envelope encryption of memory content. Each user has a data key, wrapped
+-- by a master key held in a KMS; rotation adds a version. The data key
+-- encrypts content, summary and metadata, and memories record the version
+-- that did so. Rows with a NULL key_version are still plaintext.
+CREATE TABLE IF NOT EXISTS user_data_keys (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    master_key_id VARCHAR(255) NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_data_keys_master_key_id ON user_data_keys(master_key_id);
+
+ALTER TABLE memories ADD COLUMN IF NOT EXISTS key_version INTEGER;
+
+-- Blind index of encrypted content: a keyed hash of each word, so content
+-- search matches whole words without the database seeing them
+CREATE TABLE IF NOT EXISTS memory_search_tokens (
+    memory_id UUID NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+    token CHAR(32) NOT NULL,
+    PRIMARY KEY (memory_id, token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_search_tokens_token ON memory_search_tokens(token);
+
+-- Data keys are only read through the key store, which decrypts shared
+-- memories with their author's key and so always bypasses the tenant
+ALTER TABLE user_data_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_data_keys FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY user_data_keys_bypass ON user_data_keys
+    USING (mem_bank_bypass_rls())
+    WITH CHECK (mem_bank_bypass_rls());
+
+-- Tokens follow the visibility of their memory
+ALTER TABLE memory_search_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE memory_search_tokens FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY memory_search_tokens_tenant ON memory_search_tokens
+    USING (mem_bank_bypass_rls() OR EXISTS (SELECT 1 FROM memories WHERE memories.id = memory_id))
+    WITH CHECK (mem_bank_bypass_rls() OR EXISTS (SELECT 1 FROM memories WHERE memories.id = memory_id));
@@ -0,0 +1,8 @@
+-- Drop policies
+DROP POLICY IF EXISTS user_erasures_bypass ON user_erasures;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_user_erasures_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS user_erasures;
@@ -0,0 +1,25 @@
+-- "Forget me" requests. An erasure outlives the user it names, so user_id
+-- has no foreign key; the row and its signed receipt are the record that
+-- the user's data was removed.
+CREATE TABLE IF NOT EXISTS user_erasures (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    requested_by UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    stores JSONB NOT NULL DEFAULT '[]',
+    last_error TEXT,
+    receipt TEXT,
+    requested_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_erasures_user_id ON user_erasures(user_id);
+
+-- Erasures are written by the erasure service and job and read by admins,
+-- all of which bypass the tenant
+ALTER TABLE user_erasures ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_erasures FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY user_erasures_bypass ON user_erasures
+    USING (mem_bank_bypass_rls())
+    WITH CHECK (mem_bank_bypass_rls());
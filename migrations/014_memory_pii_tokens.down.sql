@@ -0,0 +1,10 @@
+-- Tokenized values cannot be restored once the vault is dropped.
+
+-- Drop policies
+DROP POLICY IF EXISTS memory_pii_tokens_owner ON memory_pii_tokens;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_memory_pii_tokens_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS memory_pii_tokens;
@@ -0,0 +1,23 @@
+-- Vault of personal data tokenized out of memories. The memory holds a
+-- placeholder per value; the value is kept here, encrypted with the
+-- author's data key when encryption is enabled (key_version is then set).
+CREATE TABLE IF NOT EXISTS memory_pii_tokens (
+    memory_id UUID NOT NULL REFERENCES memories(id) ON DELETE CASCADE,
+    token VARCHAR(32) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(50) NOT NULL,
+    value TEXT NOT NULL,
+    key_version INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (memory_id, token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_pii_tokens_user_id ON memory_pii_tokens(user_id);
+
+-- Only the author of a memory may restore its values, even in a shared space
+ALTER TABLE memory_pii_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE memory_pii_tokens FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY memory_pii_tokens_owner ON memory_pii_tokens
+    USING (mem_bank_bypass_rls() OR user_id = mem_bank_current_user_id())
+    WITH CHECK (mem_bank_bypass_rls() OR user_id = mem_bank_current_user_id());
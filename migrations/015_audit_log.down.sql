@@ -0,0 +1,16 @@
+-- Drop policies
+DROP POLICY IF EXISTS audit_log_bypass ON audit_log;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS mem_bank_audit_log_append_only();
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_audit_log_created_at;
+DROP INDEX IF EXISTS idx_audit_log_request_id;
+DROP INDEX IF EXISTS idx_audit_log_resource;
+DROP INDEX IF EXISTS idx_audit_log_actor_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,49 @@
+-- Append-only record of actions on memories and users. Entries hold IDs and
+-- hashes only, and outlive the users and resources they name, so there are
+-- no foreign keys.
+CREATE TABLE IF NOT EXISTS audit_log (
+    sequence BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_user_id UUID,
+    actor_role VARCHAR(20),
+    actor_api_key_id UUID,
+    action VARCHAR(50) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(100) NOT NULL,
+    request_id VARCHAR(100),
+    before_hash VARCHAR(64),
+    after_hash VARCHAR(64),
+    prev_hash VARCHAR(64),
+    hash VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_user_id ON audit_log(actor_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log(resource_type, resource_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_request_id ON audit_log(request_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
+
+-- Entries can be added but never changed or removed
+CREATE OR REPLACE FUNCTION mem_bank_audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION mem_bank_audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION mem_bank_audit_log_append_only();
+
+-- Entries are written by the audit job and read by admins, both of which
+-- bypass the tenant
+ALTER TABLE audit_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_log FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY audit_log_bypass ON audit_log
+    USING (mem_bank_bypass_rls())
+    WITH CHECK (mem_bank_bypass_rls());
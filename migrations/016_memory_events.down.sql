@@ -0,0 +1,17 @@
+-- Drop policies
+DROP POLICY IF EXISTS webhook_deliveries_owner ON webhook_deliveries;
+DROP POLICY IF EXISTS webhook_subscriptions_owner ON webhook_subscriptions;
+DROP POLICY IF EXISTS memory_events_tenant ON memory_events;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_webhook_deliveries_subscription_id;
+DROP INDEX IF EXISTS idx_webhook_subscriptions_user_id;
+DROP INDEX IF EXISTS idx_memory_events_created_at;
+DROP INDEX IF EXISTS idx_memory_events_pending;
+DROP INDEX IF EXISTS idx_memory_events_space_id;
+DROP INDEX IF EXISTS idx_memory_events_user_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TABLE IF EXISTS memory_events;
@@ -0,0 +1,82 @@
+-- Outbox of memory changes. The memory repository adds an event in the
+-- transaction of each change; the webhook dispatcher delivers events not
+-- yet dispatched, and GET /events pages through them by sequence. Events
+-- outlive their memory, so memory_id has no foreign key.
+CREATE TABLE IF NOT EXISTS memory_events (
+    sequence BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    type VARCHAR(50) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    space_id UUID,
+    memory_id UUID NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    dispatched_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_events_user_id ON memory_events(user_id, sequence);
+CREATE INDEX IF NOT EXISTS idx_memory_events_space_id ON memory_events(space_id, sequence) WHERE space_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_memory_events_pending ON memory_events(sequence) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_memory_events_created_at ON memory_events(created_at);
+
+-- Events are visible to whoever may see the memory: its author while
+-- personal and the members of its space while shared
+ALTER TABLE memory_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE memory_events FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY memory_events_tenant ON memory_events
+    USING (mem_bank_bypass_rls()
+        OR (space_id IS NULL AND user_id = mem_bank_current_user_id())
+        OR space_id IN (SELECT space_id FROM space_members WHERE user_id = mem_bank_current_user_id()))
+    WITH CHECK (mem_bank_bypass_rls()
+        OR (space_id IS NULL AND user_id = mem_bank_current_user_id())
+        OR space_id IN (SELECT space_id FROM space_members WHERE user_id = mem_bank_current_user_id()));
+
+-- Endpoints users registered for events. The secret signs deliveries and
+-- must be kept in the clear to do so.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_user_id ON webhook_subscriptions(user_id);
+
+-- One row per delivery attempt
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    succeeded BOOLEAN NOT NULL,
+    error TEXT,
+    duration_ms INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id, created_at);
+
+-- Subscriptions and their deliveries belong to one user; the dispatcher
+-- and the delivery job bypass the tenant
+ALTER TABLE webhook_subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY webhook_subscriptions_owner ON webhook_subscriptions
+    USING (mem_bank_bypass_rls() OR user_id = mem_bank_current_user_id())
+    WITH CHECK (mem_bank_bypass_rls() OR user_id = mem_bank_current_user_id());
+
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY webhook_deliveries_owner ON webhook_deliveries
+    USING (mem_bank_bypass_rls() OR user_id = mem_bank_current_user_id())
+    WITH CHECK (mem_bank_bypass_rls() OR user_id = mem_bank_current_user_id());
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_memory_events_unpublished;
+
+-- Drop columns
+ALTER TABLE memory_events DROP COLUMN IF EXISTS published_at;
@@ -0,0 +1,5 @@
+-- Events are published to the memory streams separately from webhook
+-- dispatch, so each has its own marker in the outbox
+ALTER TABLE memory_events ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_memory_events_unpublished ON memory_events(sequence) WHERE published_at IS NULL;
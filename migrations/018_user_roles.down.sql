@@ -0,0 +1,5 @@
+-- Drop constraints
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+
+-- Drop columns
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -13,22 +14,43 @@ var (
 	ErrInvalidToken  = errors.New("invalid token")
 	ErrExpiredToken  = errors.New("token has expired")
 	ErrTokenNotFound = errors.New("token not found")
+	ErrRevokedToken  = errors.New("token has been revoked")
 )
 
+// AudienceAccessToken is the audience of access tokens. ValidateToken only
+// accepts tokens for it, so other documents signed with the same keys cannot
+// be used as credentials.
+const AudienceAccessToken = "access"
+
 // Claims represents the JWT claims
 type Claims struct {
 	UserID   uuid.UUID `json:"user_id"`
 	Username string    `json:"username"`
 	Email    string    `json:"email"`
 	Role     string    `json:"role"`
+
+	// SessionID ties access tokens to the refresh token family they came
+	// from so a whole session can be revoked at once
+	SessionID string `json:"sid,omitempty"`
+
+	// Scopes limit what a caller authenticated by API key may do; they are
+	// empty for interactive sessions, which act with the user's full rights
+	Scopes []string `json:"scopes,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// JWTService handles JWT token operations
+// JWTService handles JWT token operations. Tokens are signed with HS256 and
+// the shared secret unless a key ring is set. HS256 tokens are then still
+// accepted for one token lifetime, so switching to asymmetric keys does not
+// log anyone out.
 type JWTService struct {
-	secretKey []byte
-	issuer    string
-	expiry    time.Duration
+	secretKey   []byte
+	issuer      string
+	expiry      time.Duration
+	revocations RevocationList
+	keys        *KeyRing
+	legacyUntil time.Time
+	now         func() time.Time
 }
 
 // NewJWTService creates a new JWT service
@@ -37,28 +59,84 @@ func NewJWTService(secretKey string, issuer string, expiry time.Duration) *JWTSe
 		secretKey: []byte(secretKey),
 		issuer:    issuer,
 		expiry:    expiry,
+		now:       time.Now,
+	}
+}
+
+// WithRevocationList makes Authenticate reject revoked tokens and sessions
+func (j *JWTService) WithRevocationList(revocations RevocationList) *JWTService {
+	j.revocations = revocations
+	return j
+}
+
+// WithKeyRing signs tokens with the ring's current asymmetric key. HS256
+// tokens are accepted until those issued before expire.
+func (j *JWTService) WithKeyRing(keys *KeyRing) *JWTService {
+	j.keys = keys
+	j.legacyUntil = j.now().Add(j.expiry)
+	return j
+}
+
+// JWKS returns the public keys tokens may be verified with
+func (j *JWTService) JWKS() JWKS {
+	if j.keys == nil {
+		return JWKS{Keys: []JWK{}}
 	}
+	return j.keys.JWKS()
+}
+
+// Expiry returns the lifetime of access tokens
+func (j *JWTService) Expiry() time.Duration {
+	return j.expiry
 }
 
 // GenerateToken generates a new JWT token for the user
 func (j *JWTService) GenerateToken(userID uuid.UUID, username, email, role string) (string, error) {
-	now := time.Now()
+	return j.GenerateSessionToken(userID, username, email, role, "")
+}
+
+// GenerateSessionToken generates a JWT token belonging to a refresh token session
+func (j *JWTService) GenerateSessionToken(userID uuid.UUID, username, email, role, sessionID string) (string, error) {
+	now := j.now()
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
-		Role:     role,
+		UserID:    userID,
+		Username:  username,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.expiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    j.issuer,
 			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{AudienceAccessToken},
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(j.secretKey)
+	return j.Sign(claims)
+}
+
+// Sign signs arbitrary claims with the same key as access tokens, so anyone
+// holding the JWKS (or the shared secret) can verify them
+func (j *JWTService) Sign(claims jwt.Claims) (string, error) {
+	if j.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		tokenString, err := token.SignedString(j.secretKey)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign token: %w", err)
+		}
+		return tokenString, nil
+	}
+
+	key, err := j.keys.SigningKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	tokenString, err := token.SignedString(key.PrivateKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -66,15 +144,14 @@ func (j *JWTService) GenerateToken(userID uuid.UUID, username, email, role strin
 	return tokenString, nil
 }
 
-// ValidateToken validates and parses a JWT token
+// ValidateToken validates and parses an access token
 func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return j.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.verificationKey,
+		jwt.WithValidMethods([]string{AlgorithmHS256, AlgorithmRS256, AlgorithmEdDSA}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(j.issuer),
+		jwt.WithAudience(AudienceAccessToken),
+		jwt.WithTimeFunc(j.now))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -95,39 +172,81 @@ func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
-// RefreshToken generates a new token with extended expiry
-func (j *JWTService) RefreshToken(tokenString string) (string, error) {
+// verificationKey selects the key a token is verified with: the shared
+// secret for HS256, otherwise the ring key named by the kid header
+func (j *JWTService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if j.keys != nil && !j.now().Before(j.legacyUntil) {
+			return nil, fmt.Errorf("HS256 tokens are no longer accepted")
+		}
+		return j.secretKey, nil
+	}
+	if j.keys == nil {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, err := j.keys.VerificationKey(kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.method().Alg() != token.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.PrivateKey.Public(), nil
+}
+
+// Authenticate validates a token and checks that neither it nor its session
+// has been revoked. Without a revocation list it is ValidateToken.
+func (j *JWTService) Authenticate(ctx context.Context, tokenString string) (*Claims, error) {
 	claims, err := j.ValidateToken(tokenString)
 	if err != nil {
-		// Allow refresh of expired tokens (within reasonable time)
-		if !errors.Is(err, ErrExpiredToken) {
-			return "", err
-		}
+		return nil, err
+	}
+	if j.revocations == nil {
+		return claims, nil
+	}
 
-		// Parse expired token to get claims
-		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			return j.secretKey, nil
-		})
-		if err != nil {
-			return "", ErrInvalidToken
-		}
+	keys := []string{tokenRevocationKey(claims.ID)}
+	if claims.SessionID != "" {
+		keys = append(keys, sessionRevocationKey(claims.SessionID))
+	}
+	revoked, err := j.revocations.IsRevoked(ctx, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("checking token revocation: %w", err)
+	}
+	if revoked {
+		return nil, ErrRevokedToken
+	}
 
-		var ok bool
-		claims, ok = token.Claims.(*Claims)
-		if !ok {
-			return "", ErrInvalidToken
-		}
+	return claims, nil
+}
 
-		// Check if token is too old to refresh (e.g., more than 7 days)
-		if time.Since(claims.ExpiresAt.Time) > 7*24*time.Hour {
-			return "", ErrExpiredToken
-		}
+// RevokeToken rejects a token for the rest of its lifetime
+func (j *JWTService) RevokeToken(ctx context.Context, claims *Claims) error {
+	if j.revocations == nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
 	}
+	return j.revocations.Revoke(ctx, tokenRevocationKey(claims.ID), ttl)
+}
 
-	// Generate new token with same user info
-	return j.GenerateToken(claims.UserID, claims.Username, claims.Email, claims.Role)
+// RevokeSession rejects every token of a session. The entry is kept for one
+// access token lifetime, which outlives every token already issued for it.
+func (j *JWTService) RevokeSession(ctx context.Context, sessionID string) error {
+	if j.revocations == nil || sessionID == "" {
+		return nil
+	}
+	return j.revocations.Revoke(ctx, sessionRevocationKey(sessionID), j.expiry)
 }
 
+func tokenRevocationKey(id string) string { return "jti:" + id }
+
+func sessionRevocationKey(id string) string { return "sid:" + id }
+
 // ExtractTokenFromHeader extracts JWT token from Authorization header
 func ExtractTokenFromHeader(authHeader string) string {
 	const bearerPrefix = "Bearer "
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTService_ValidateToken(t *testing.T) {
+	jwtService := NewJWTService(testSecret, "test", time.Hour)
+	now := time.Now()
+	valid := func() jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   uuid.NewString(),
+			Issuer:    "test",
+			Audience:  jwt.ClaimStrings{AudienceAccessToken},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		modify  func(*jwt.RegisteredClaims)
+		wantErr error
+	}{
+		{
+			name:   "valid token",
+			modify: func(c *jwt.RegisteredClaims) {},
+		},
+		{
+			name:    "expired",
+			modify:  func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) },
+			wantErr: ErrExpiredToken,
+		},
+		{
+			name:    "no expiry",
+			modify:  func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "other issuer",
+			modify:  func(c *jwt.RegisteredClaims) { c.Issuer = "elsewhere" },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "no audience",
+			modify:  func(c *jwt.RegisteredClaims) { c.Audience = nil },
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "other audience",
+			modify:  func(c *jwt.RegisteredClaims) { c.Audience = jwt.ClaimStrings{"erasure-receipt"} },
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := &Claims{Role: "user", RegisteredClaims: valid()}
+			tc.modify(&claims.RegisteredClaims)
+			token, err := jwtService.Sign(claims)
+			require.NoError(t, err)
+
+			_, err = jwtService.ValidateToken(token)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
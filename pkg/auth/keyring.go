@@ -0,0 +1,257 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"mem_bank/pkg/logger"
+)
+
+// KeyStore persists signing keys so every replica signs and verifies with
+// the same set
+type KeyStore interface {
+	// List returns every stored key
+	List(ctx context.Context) ([]*SigningKey, error)
+
+	// Add stores a new key
+	Add(ctx context.Context, key *SigningKey) error
+
+	// DeleteRetired removes keys retired before t
+	DeleteRetired(ctx context.Context, t time.Time) error
+}
+
+// KeyRingConfig holds key rotation configuration
+type KeyRingConfig struct {
+	// Algorithm of generated keys, RS256 or EdDSA
+	Algorithm string
+
+	// How long each key signs new tokens before its successor takes over
+	RotationInterval time.Duration
+
+	// How long a new key is published before it signs, so replicas and
+	// JWKS consumers know it before they see tokens signed with it
+	PropagationDelay time.Duration
+
+	// How long a key keeps verifying after its successor took over; at
+	// least the lifetime of access tokens
+	VerificationGrace time.Duration
+
+	// How often keys are reloaded from the store
+	RefreshInterval time.Duration
+}
+
+// KeyRing holds the signing keys of a service and rotates them on schedule
+type KeyRing struct {
+	store  KeyStore
+	logger logger.Logger
+	config KeyRingConfig
+	now    func() time.Time
+
+	mu   sync.RWMutex
+	keys []*SigningKey // ordered by ActiveAt, newest first
+}
+
+// NewKeyRing creates a key ring; call Maintain before use to load or create keys
+func NewKeyRing(store KeyStore, logger logger.Logger, config KeyRingConfig) (*KeyRing, error) {
+	if config.Algorithm != AlgorithmRS256 && config.Algorithm != AlgorithmEdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", config.Algorithm)
+	}
+	if config.RotationInterval <= 0 {
+		config.RotationInterval = 30 * 24 * time.Hour
+	}
+	if config.PropagationDelay <= 0 {
+		config.PropagationDelay = 5 * time.Minute
+	}
+	if config.VerificationGrace <= 0 {
+		config.VerificationGrace = 24 * time.Hour
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = time.Minute
+	}
+	if config.PropagationDelay >= config.RotationInterval {
+		return nil, errors.New("key propagation delay must be shorter than the rotation interval")
+	}
+
+	return &KeyRing{
+		store:  store,
+		logger: logger,
+		config: config,
+		now:    time.Now,
+	}, nil
+}
+
+// Run keeps the ring up to date until ctx is done
+func (r *KeyRing) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Maintain(ctx); err != nil {
+				r.logger.WithError(err).Error("Failed to maintain JWT signing keys")
+			}
+		}
+	}
+}
+
+// Maintain reloads the keys, schedules a successor when the signing key is
+// due for rotation and deletes retired keys
+func (r *KeyRing) Maintain(ctx context.Context) error {
+	keys, err := r.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("loading signing keys: %w", err)
+	}
+
+	now := r.now()
+	next, err := r.successor(keys, now)
+	if err != nil {
+		return err
+	}
+	if next != nil {
+		if err := r.store.Add(ctx, next); err != nil {
+			return fmt.Errorf("storing signing key: %w", err)
+		}
+		keys = append(keys, next)
+		r.logger.WithFields(map[string]interface{}{
+			"kid":       next.ID,
+			"algorithm": next.Algorithm,
+			"active_at": next.ActiveAt,
+		}).Info("Scheduled new JWT signing key")
+	}
+
+	if err := r.store.DeleteRetired(ctx, now); err != nil {
+		return fmt.Errorf("deleting retired signing keys: %w", err)
+	}
+
+	r.set(keys, now)
+	return nil
+}
+
+// successor returns a new key when none of keys will be signing once the
+// propagation delay has passed, or nil
+func (r *KeyRing) successor(keys []*SigningKey, now time.Time) (*SigningKey, error) {
+	var latest *SigningKey
+	hasActive := false
+	for _, key := range keys {
+		if key.Algorithm != r.config.Algorithm || key.IsRetired(now) {
+			continue
+		}
+		if key.IsActive(now) {
+			hasActive = true
+		}
+		if latest == nil || key.ActiveAt.After(latest.ActiveAt) {
+			latest = key
+		}
+	}
+
+	activeAt := now
+	switch {
+	case !hasActive:
+		// Nothing can sign now, so the new key cannot wait to propagate
+	case latest.ActiveAt.Add(r.config.RotationInterval).After(now.Add(r.config.PropagationDelay)):
+		return nil, nil
+	default:
+		activeAt = latest.ActiveAt.Add(r.config.RotationInterval)
+		if earliest := now.Add(r.config.PropagationDelay); activeAt.Before(earliest) {
+			activeAt = earliest
+		}
+	}
+
+	key, err := GenerateSigningKey(r.config.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	key.CreatedAt = now
+	key.ActiveAt = activeAt
+	key.RetireAt = activeAt.Add(r.config.RotationInterval + r.config.VerificationGrace)
+	return key, nil
+}
+
+// Rotate schedules a new key now, for example after a key was compromised.
+// The new key signs once it has propagated; existing keys keep verifying
+// until they retire.
+func (r *KeyRing) Rotate(ctx context.Context) (*SigningKey, error) {
+	now := r.now()
+	key, err := GenerateSigningKey(r.config.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	key.CreatedAt = now
+	key.ActiveAt = now.Add(r.config.PropagationDelay)
+	key.RetireAt = key.ActiveAt.Add(r.config.RotationInterval + r.config.VerificationGrace)
+
+	if err := r.store.Add(ctx, key); err != nil {
+		return nil, fmt.Errorf("storing signing key: %w", err)
+	}
+
+	r.mu.RLock()
+	keys := append([]*SigningKey{key}, r.keys...)
+	r.mu.RUnlock()
+	r.set(keys, now)
+	return key, nil
+}
+
+func (r *KeyRing) set(keys []*SigningKey, now time.Time) {
+	current := make([]*SigningKey, 0, len(keys))
+	for _, key := range keys {
+		if !key.IsRetired(now) {
+			current = append(current, key)
+		}
+	}
+	sort.Slice(current, func(i, j int) bool {
+		return current[i].ActiveAt.After(current[j].ActiveAt)
+	})
+
+	r.mu.Lock()
+	r.keys = current
+	r.mu.Unlock()
+}
+
+// SigningKey returns the key new tokens are signed with
+func (r *KeyRing) SigningKey() (*SigningKey, error) {
+	now := r.now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, key := range r.keys {
+		if key.Algorithm == r.config.Algorithm && key.IsActive(now) {
+			return key, nil
+		}
+	}
+	return nil, errors.New("no active signing key")
+}
+
+// VerificationKey returns the key with ID kid unless it has retired. Keys
+// that are still propagating are accepted.
+func (r *KeyRing) VerificationKey(kid string) (*SigningKey, error) {
+	now := r.now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, key := range r.keys {
+		if key.ID == kid && !key.IsRetired(now) {
+			return key, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// JWKS returns the public keys of every key that is not retired
+func (r *KeyRing) JWKS() JWKS {
+	now := r.now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := JWKS{Keys: make([]JWK, 0, len(r.keys))}
+	for _, key := range r.keys {
+		if !key.IsRetired(now) {
+			set.Keys = append(set.Keys, key.JWK())
+		}
+	}
+	return set
+}
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/configs"
+	"mem_bank/pkg/logger"
+)
+
+// In-memory key store
+type memoryKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]*SigningKey
+}
+
+func (s *memoryKeyStore) List(ctx context.Context) ([]*SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]*SigningKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s *memoryKeyStore) Add(ctx context.Context, key *SigningKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.ID] = key
+	return nil
+}
+
+func (s *memoryKeyStore) DeleteRetired(ctx context.Context, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, key := range s.keys {
+		if key.IsRetired(t) {
+			delete(s.keys, id)
+		}
+	}
+	return nil
+}
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+type keyRingFixture struct {
+	store *memoryKeyStore
+	now   time.Time
+	rings []*KeyRing
+}
+
+// replica creates a key ring sharing the fixture's store and clock
+func (f *keyRingFixture) replica(t *testing.T, algorithm string) (*KeyRing, *JWTService) {
+	t.Helper()
+	log, err := logger.NewLogger(&configs.LoggingConfig{Level: "info", Output: "stdout"})
+	require.NoError(t, err)
+
+	ring, err := NewKeyRing(f.store, log, KeyRingConfig{
+		Algorithm:         algorithm,
+		RotationInterval:  24 * time.Hour,
+		PropagationDelay:  10 * time.Minute,
+		VerificationGrace: time.Hour,
+	})
+	require.NoError(t, err)
+	ring.now = func() time.Time { return f.now }
+	require.NoError(t, ring.Maintain(context.Background()))
+	f.rings = append(f.rings, ring)
+
+	return ring, NewJWTService(testSecret, "test", time.Hour).WithKeyRing(ring)
+}
+
+func (f *keyRingFixture) advance(t *testing.T, d time.Duration) {
+	t.Helper()
+	f.now = f.now.Add(d)
+	for _, ring := range f.rings {
+		require.NoError(t, ring.Maintain(context.Background()))
+	}
+}
+
+func newKeyRingFixture() *keyRingFixture {
+	return &keyRingFixture{
+		store: &memoryKeyStore{keys: make(map[string]*SigningKey)},
+		now:   time.Now(),
+	}
+}
+
+func kidOf(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	require.NoError(t, err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestKeyRing_SignsAndVerifiesAcrossReplicas(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmRS256, AlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			f := newKeyRingFixture()
+			ring, signer := f.replica(t, algorithm)
+			_, verifier := f.replica(t, algorithm)
+
+			token, err := signer.GenerateToken(uuid.New(), "alice", "alice@example.com", "user")
+			require.NoError(t, err)
+			claims, err := verifier.ValidateToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, "alice", claims.Username)
+
+			key, err := ring.SigningKey()
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, kidOf(t, token))
+
+			jwks := verifier.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, key.ID, jwks.Keys[0].KeyID)
+			assert.Equal(t, algorithm, jwks.Keys[0].Algorithm)
+			assert.Equal(t, "sig", jwks.Keys[0].Use)
+		})
+	}
+}
+
+func TestKeyRing_RotatesWithoutInvalidatingTokens(t *testing.T) {
+	f := newKeyRingFixture()
+	ring, jwtService := f.replica(t, AlgorithmEdDSA)
+
+	first, err := ring.SigningKey()
+	require.NoError(t, err)
+	old, err := jwtService.GenerateToken(uuid.New(), "alice", "alice@example.com", "user")
+	require.NoError(t, err)
+
+	f.advance(t, 24*time.Hour-15*time.Minute)
+	assert.Len(t, jwtService.JWKS().Keys, 1)
+
+	// The successor is published one propagation delay before it signs
+	f.advance(t, 5*time.Minute)
+	assert.Len(t, jwtService.JWKS().Keys, 2)
+	current, err := ring.SigningKey()
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, current.ID)
+
+	f.advance(t, 10*time.Minute)
+	current, err = ring.SigningKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, current.ID)
+
+	// Tokens of the previous key stay valid through the grace period
+	_, err = jwtService.ValidateToken(old)
+	assert.NotErrorIs(t, err, ErrInvalidToken)
+
+	fresh, err := jwtService.GenerateToken(uuid.New(), "bob", "bob@example.com", "user")
+	require.NoError(t, err)
+	assert.Equal(t, current.ID, kidOf(t, fresh))
+
+	// Retired keys are dropped from the store and the JWKS
+	f.advance(t, time.Hour)
+	assert.Len(t, jwtService.JWKS().Keys, 1)
+	assert.NotContains(t, f.store.keys, first.ID)
+	_, err = jwtService.ValidateToken(old)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestKeyRing_AcceptsLegacyHS256Tokens(t *testing.T) {
+	legacy, err := NewJWTService(testSecret, "test", 24*time.Hour).GenerateToken(uuid.New(), "alice", "alice@example.com", "user")
+	require.NoError(t, err)
+
+	f := newKeyRingFixture()
+	_, jwtService := f.replica(t, AlgorithmRS256)
+	_, err = jwtService.ValidateToken(legacy)
+	assert.NoError(t, err)
+
+	// Once tokens issued before the switch have expired, HS256 is rejected
+	now := time.Now()
+	jwtService.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, err = jwtService.ValidateToken(legacy)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	jwtService.now = time.Now
+
+	// Unknown key IDs are rejected
+	other := newKeyRingFixture()
+	_, foreign := other.replica(t, AlgorithmRS256)
+	token, err := foreign.GenerateToken(uuid.New(), "mallory", "mallory@example.com", "admin")
+	require.NoError(t, err)
+	_, err = jwtService.ValidateToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
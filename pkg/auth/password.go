@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password length bounds; bcrypt ignores everything past 72 bytes
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// Password errors
+var (
+	ErrPasswordTooShort = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	ErrPasswordTooLong  = fmt.Errorf("password must be at most %d bytes", MaxPasswordLength)
+	ErrPasswordMismatch = errors.New("password does not match")
+)
+
+// PasswordHasher hashes passwords with bcrypt at a fixed cost
+type PasswordHasher struct {
+	cost int
+}
+
+// NewPasswordHasher creates a hasher; costs outside bcrypt's range use bcrypt.DefaultCost
+func NewPasswordHasher(cost int) *PasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &PasswordHasher{cost: cost}
+}
+
+// Validate checks the length constraints of a new password
+func (h *PasswordHasher) Validate(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	return nil
+}
+
+// Hash returns the bcrypt hash of password
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if err := h.Validate(password); err != nil {
+		return "", err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("hashing password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Verify returns ErrPasswordMismatch unless password matches hash
+func (h *PasswordHasher) Verify(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+	if err != nil {
+		return fmt.Errorf("verifying password: %w", err)
+	}
+	return nil
+}
+
+// NeedsRehash reports whether hash was made with a cost other than the hasher's
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
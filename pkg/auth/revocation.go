@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RevocationList records revoked token and session identifiers until the
+// tokens they cover would have expired anyway
+type RevocationList interface {
+	// Revoke marks key as revoked for ttl
+	Revoke(ctx context.Context, key string, ttl time.Duration) error
+
+	// IsRevoked reports whether any of keys is revoked
+	IsRevoked(ctx context.Context, keys ...string) (bool, error)
+}
+
+// RedisRevocationList keeps revoked identifiers in Redis so every replica
+// sees a logout immediately
+type RedisRevocationList struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisRevocationList creates a revocation list storing its keys under prefix
+func NewRedisRevocationList(client *redis.Client, prefix string) *RedisRevocationList {
+	return &RedisRevocationList{client: client, prefix: prefix}
+}
+
+// Revoke marks key as revoked for ttl
+func (l *RedisRevocationList) Revoke(ctx context.Context, key string, ttl time.Duration) error {
+	if err := l.client.Set(ctx, l.prefix+key, 1, ttl).Err(); err != nil {
+		return fmt.Errorf("revoking %s: %w", key, err)
+	}
+	return nil
+}
+
+// IsRevoked reports whether any of keys is revoked
+func (l *RedisRevocationList) IsRevoked(ctx context.Context, keys ...string) (bool, error) {
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = l.prefix + key
+	}
+
+	count, err := l.client.Exists(ctx, prefixed...).Result()
+	if err != nil {
+		return false, fmt.Errorf("checking revocation: %w", err)
+	}
+	return count > 0, nil
+}
+
+// MemoryRevocationList keeps revoked identifiers in process. Revocations are
+// only seen by the replica that made them, so it suits single-node
+// deployments running without Redis.
+type MemoryRevocationList struct {
+	mu        sync.Mutex
+	expires   map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRevocationList creates an in-process revocation list
+func NewMemoryRevocationList() *MemoryRevocationList {
+	return &MemoryRevocationList{
+		expires: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Revoke marks key as revoked for ttl
+func (l *MemoryRevocationList) Revoke(ctx context.Context, key string, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	l.expires[key] = now.Add(ttl)
+	return nil
+}
+
+// IsRevoked reports whether any of keys is revoked
+func (l *MemoryRevocationList) IsRevoked(ctx context.Context, keys ...string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for _, key := range keys {
+		if expires, ok := l.expires[key]; ok && expires.After(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sweep drops expired revocations. Callers must hold mu.
+func (l *MemoryRevocationList) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for key, expires := range l.expires {
+		if !expires.After(now) {
+			delete(l.expires, key)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRevocationList(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	list := NewMemoryRevocationList()
+	list.now = func() time.Time { return now }
+
+	require.NoError(t, list.Revoke(ctx, "jti:a", time.Minute))
+
+	revoked, err := list.IsRevoked(ctx, "jti:b", "jti:a")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = list.IsRevoked(ctx, "jti:b")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// Revocations lapse once the tokens they cover have expired
+	now = now.Add(2 * time.Minute)
+	revoked, err = list.IsRevoked(ctx, "jti:a")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, list.Revoke(ctx, "jti:c", time.Minute))
+	assert.NotContains(t, list.expires, "jti:a")
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Session variables read by the row-level security policies, see
+// migrations/011_row_level_security.up.sql
+const (
+	userIDSetting = "mem_bank.user_id"
+	bypassSetting = "mem_bank.bypass_rls"
+)
+
+// Tenant is whose rows the row-level security policies let a transaction see
+type Tenant struct {
+	UserID string
+	// Bypass lets admin and system callers see every row
+	Bypass bool
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context whose transactions are scoped to tenant
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant attached to ctx, if any
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(Tenant)
+	return tenant, ok
+}
+
+// Transaction runs fn in a transaction scoped to the tenant in ctx. The
+// session variables are set with set_config(..., true) and so end with the
+// transaction; they never carry over to the next user of a pooled
+// connection. Without a tenant the policies match no rows. Called with a
+// transaction, fn runs in a savepoint of it.
+func Transaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	tenant, _ := TenantFromContext(ctx)
+	bypass := "off"
+	if tenant.Bypass {
+		bypass = "on"
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("SELECT set_config(?, ?, true), set_config(?, ?, true)",
+			userIDSetting, tenant.UserID, bypassSetting, bypass).Error
+		if err != nil {
+			return fmt.Errorf("setting tenant: %w", err)
+		}
+		return fn(tx)
+	})
+}
@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// keySize is the size of master, data and index keys: AES-256 and HMAC-SHA256
+const keySize = 32
+
+// minTokenLength drops words too short to be worth indexing
+const minTokenLength = 2
+
+var ErrDecrypt = errors.New("decryption failed")
+
+// seal encrypts plaintext with AES-256-GCM, returning nonce and ciphertext.
+// aad is authenticated but not encrypted; open must be given the same.
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(key, ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, aad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Words splits text into the lower-cased words a blind index matches on.
+// Each word is returned once.
+func Words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(fields))
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < minTokenLength || seen[field] {
+			continue
+		}
+		seen[field] = true
+		words = append(words, field)
+	}
+	return words
+}
+
+// blindToken is the keyed hash a word is indexed under
+func blindToken(indexKey []byte, word string) string {
+	mac := hmac.New(sha256.New, indexKey)
+	mac.Write([]byte(word))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
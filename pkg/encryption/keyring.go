@@ -0,0 +1,337 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"mem_bank/pkg/logger"
+)
+
+var (
+	ErrUnknownKey = errors.New("unknown data key")
+	ErrKeyExists  = errors.New("data key version already exists")
+)
+
+const (
+	// cacheTTL bounds how long a replica keeps using keys it loaded, so it
+	// notices rotations and erasures made elsewhere
+	cacheTTL = time.Minute
+
+	// rewrapBatchSize is how many keys Rewrap loads at a time
+	rewrapBatchSize = 100
+)
+
+// StoredKey is a data key as persisted, wrapped by a master key of the KMS
+type StoredKey struct {
+	UserID      string
+	Version     int
+	MasterKeyID string
+	WrappedKey  []byte
+	CreatedAt   time.Time
+}
+
+// KeyStore persists wrapped data keys
+type KeyStore interface {
+	// List returns the keys of a user, newest version first
+	List(ctx context.Context, userID string) ([]*StoredKey, error)
+
+	// Add stores a new key version; ErrKeyExists when the version is taken
+	Add(ctx context.Context, key *StoredKey) error
+
+	// ListNotWrappedBy returns up to limit keys wrapped by another master
+	// key than masterKeyID
+	ListNotWrappedBy(ctx context.Context, masterKeyID string, limit int) ([]*StoredKey, error)
+
+	// Rewrap replaces the master key ID and wrapped key of a stored key
+	Rewrap(ctx context.Context, key *StoredKey) error
+
+	// Delete destroys every key of a user
+	Delete(ctx context.Context, userID string) error
+}
+
+// DataKey encrypts the data of one user. All versions of a user's key
+// share the index key, so blind index tokens survive rotation.
+type DataKey struct {
+	UserID   string
+	Version  int
+	key      []byte
+	indexKey []byte
+}
+
+// Seal encrypts plaintext; aad binds the ciphertext to its context, such as
+// the record and field it belongs to
+func (k *DataKey) Seal(plaintext, aad []byte) ([]byte, error) {
+	return seal(k.key, plaintext, aad)
+}
+
+// Open decrypts a ciphertext made by Seal with the same aad
+func (k *DataKey) Open(ciphertext, aad []byte) ([]byte, error) {
+	return open(k.key, ciphertext, aad)
+}
+
+// BlindIndex returns the tokens the words of text are indexed under. Equal
+// words give equal tokens for one user and unrelated tokens across users.
+func (k *DataKey) BlindIndex(text string) []string {
+	words := Words(text)
+	tokens := make([]string, len(words))
+	for i, word := range words {
+		tokens[i] = blindToken(k.indexKey, word)
+	}
+	return tokens
+}
+
+type cachedKeys struct {
+	keys     []*DataKey // newest version first
+	loadedAt time.Time
+}
+
+// KeyRing hands out per-user data keys, creating them on first use and
+// keeping them unwrapped in memory for a short while
+type KeyRing struct {
+	kms    KMS
+	store  KeyStore
+	logger logger.Logger
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*cachedKeys
+}
+
+// NewKeyRing creates a key ring over the keys in store, wrapped by kms
+func NewKeyRing(kms KMS, store KeyStore, logger logger.Logger) *KeyRing {
+	return &KeyRing{
+		kms:    kms,
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+		cache:  make(map[string]*cachedKeys),
+	}
+}
+
+// Current returns the newest key of a user, creating the first one
+func (r *KeyRing) Current(ctx context.Context, userID string) (*DataKey, error) {
+	keys, err := r.keys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) > 0 {
+		return keys[0], nil
+	}
+
+	key, err := r.add(ctx, userID, nil)
+	if errors.Is(err, ErrKeyExists) {
+		// Created concurrently, possibly by another replica
+		keys, err := r.load(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 {
+			return nil, ErrUnknownKey
+		}
+		return keys[0], nil
+	}
+	return key, err
+}
+
+// Latest returns the newest key of a user without creating one; ErrUnknownKey
+// when the user has none
+func (r *KeyRing) Latest(ctx context.Context, userID string) (*DataKey, error) {
+	keys, err := r.keys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, ErrUnknownKey
+	}
+	return keys[0], nil
+}
+
+// Key returns one version of a user's key
+func (r *KeyRing) Key(ctx context.Context, userID string, version int) (*DataKey, error) {
+	keys, err := r.keys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if key := findVersion(keys, version); key != nil {
+		return key, nil
+	}
+
+	// The version may have been added by another replica since we loaded
+	keys, err = r.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if key := findVersion(keys, version); key != nil {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+// Rotate adds a key version that encrypts new writes from now on. Older
+// versions keep decrypting what they encrypted until it is re-encrypted.
+func (r *KeyRing) Rotate(ctx context.Context, userID string) (*DataKey, error) {
+	keys, err := r.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var previous *DataKey
+	if len(keys) > 0 {
+		previous = keys[0]
+	}
+	return r.add(ctx, userID, previous)
+}
+
+// Rewrap wraps every stored key with the current master key of the KMS,
+// after the master key was rotated there. It returns how many keys changed.
+func (r *KeyRing) Rewrap(ctx context.Context) (int, error) {
+	current, err := r.kms.CurrentKeyID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("getting current master key: %w", err)
+	}
+
+	rewrapped := 0
+	for {
+		stored, err := r.store.ListNotWrappedBy(ctx, current, rewrapBatchSize)
+		if err != nil {
+			return rewrapped, fmt.Errorf("listing data keys: %w", err)
+		}
+		if len(stored) == 0 {
+			return rewrapped, nil
+		}
+
+		for _, key := range stored {
+			material, err := r.kms.Decrypt(ctx, key.MasterKeyID, key.WrappedKey)
+			if err != nil {
+				return rewrapped, fmt.Errorf("unwrapping data key %s/%d: %w", key.UserID, key.Version, err)
+			}
+			masterKeyID, wrapped, err := r.kms.Encrypt(ctx, material)
+			if err != nil {
+				return rewrapped, fmt.Errorf("wrapping data key %s/%d: %w", key.UserID, key.Version, err)
+			}
+			if masterKeyID != current {
+				return rewrapped, errors.New("master key rotated again during rewrap")
+			}
+
+			key.MasterKeyID, key.WrappedKey = masterKeyID, wrapped
+			if err := r.store.Rewrap(ctx, key); err != nil {
+				return rewrapped, fmt.Errorf("storing data key %s/%d: %w", key.UserID, key.Version, err)
+			}
+			rewrapped++
+		}
+	}
+}
+
+// Forget destroys every key of a user, after which nothing they encrypted
+// can be read. Other replicas may hold the keys for up to a minute.
+func (r *KeyRing) Forget(ctx context.Context, userID string) error {
+	if err := r.store.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("deleting data keys: %w", err)
+	}
+	r.mu.Lock()
+	delete(r.cache, userID)
+	r.mu.Unlock()
+
+	r.logger.WithField("user_id", userID).Info("Destroyed data keys")
+	return nil
+}
+
+// keys returns the cached keys of a user, loading them when stale
+func (r *KeyRing) keys(ctx context.Context, userID string) ([]*DataKey, error) {
+	r.mu.Lock()
+	cached, ok := r.cache[userID]
+	r.mu.Unlock()
+	if ok && r.now().Sub(cached.loadedAt) < cacheTTL {
+		return cached.keys, nil
+	}
+	return r.load(ctx, userID)
+}
+
+// load reads and unwraps the keys of a user and caches them
+func (r *KeyRing) load(ctx context.Context, userID string) ([]*DataKey, error) {
+	stored, err := r.store.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("loading data keys: %w", err)
+	}
+
+	keys := make([]*DataKey, 0, len(stored))
+	for _, s := range stored {
+		material, err := r.kms.Decrypt(ctx, s.MasterKeyID, s.WrappedKey)
+		if err != nil {
+			return nil, fmt.Errorf("unwrapping data key version %d: %w", s.Version, err)
+		}
+		if len(material) != 2*keySize {
+			return nil, fmt.Errorf("data key version %d has %d bytes", s.Version, len(material))
+		}
+		keys = append(keys, &DataKey{
+			UserID:   userID,
+			Version:  s.Version,
+			key:      material[:keySize],
+			indexKey: material[keySize:],
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Version > keys[j].Version })
+
+	r.mu.Lock()
+	r.cache[userID] = &cachedKeys{keys: keys, loadedAt: r.now()}
+	r.mu.Unlock()
+	return keys, nil
+}
+
+// add creates and stores the successor of previous, or the first key
+func (r *KeyRing) add(ctx context.Context, userID string, previous *DataKey) (*DataKey, error) {
+	key := &DataKey{UserID: userID, Version: 1, key: make([]byte, keySize)}
+	if _, err := rand.Read(key.key); err != nil {
+		return nil, fmt.Errorf("generating data key: %w", err)
+	}
+	if previous != nil {
+		key.Version = previous.Version + 1
+		key.indexKey = previous.indexKey
+	} else {
+		key.indexKey = make([]byte, keySize)
+		if _, err := rand.Read(key.indexKey); err != nil {
+			return nil, fmt.Errorf("generating index key: %w", err)
+		}
+	}
+
+	material := make([]byte, 0, 2*keySize)
+	material = append(append(material, key.key...), key.indexKey...)
+	masterKeyID, wrapped, err := r.kms.Encrypt(ctx, material)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping data key: %w", err)
+	}
+
+	err = r.store.Add(ctx, &StoredKey{
+		UserID:      userID,
+		Version:     key.Version,
+		MasterKeyID: masterKeyID,
+		WrappedKey:  wrapped,
+		CreatedAt:   r.now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	delete(r.cache, userID)
+	r.mu.Unlock()
+
+	r.logger.WithFields(map[string]interface{}{
+		"user_id": userID,
+		"version": key.Version,
+	}).Info("Created data key")
+	return key, nil
+}
+
+func findVersion(keys []*DataKey, version int) *DataKey {
+	for _, key := range keys {
+		if key.Version == version {
+			return key
+		}
+	}
+	return nil
+}
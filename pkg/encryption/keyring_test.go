@@ -0,0 +1,207 @@
+package encryption
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/configs"
+	"mem_bank/pkg/logger"
+)
+
+// In-memory key store
+type memoryKeyStore struct {
+	mu   sync.Mutex
+	keys map[string][]*StoredKey
+}
+
+func (s *memoryKeyStore) List(ctx context.Context, userID string) ([]*StoredKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := append([]*StoredKey(nil), s.keys[userID]...)
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Version > keys[j].Version })
+	return keys, nil
+}
+
+func (s *memoryKeyStore) Add(ctx context.Context, key *StoredKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.keys[key.UserID] {
+		if existing.Version == key.Version {
+			return ErrKeyExists
+		}
+	}
+	stored := *key
+	s.keys[key.UserID] = append(s.keys[key.UserID], &stored)
+	return nil
+}
+
+func (s *memoryKeyStore) ListNotWrappedBy(ctx context.Context, masterKeyID string, limit int) ([]*StoredKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []*StoredKey
+	for _, userKeys := range s.keys {
+		for _, key := range userKeys {
+			if key.MasterKeyID != masterKeyID && len(keys) < limit {
+				found := *key
+				keys = append(keys, &found)
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (s *memoryKeyStore) Rewrap(ctx context.Context, key *StoredKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.keys[key.UserID] {
+		if existing.Version == key.Version {
+			existing.MasterKeyID, existing.WrappedKey = key.MasterKeyID, key.WrappedKey
+		}
+	}
+	return nil
+}
+
+func (s *memoryKeyStore) Delete(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, userID)
+	return nil
+}
+
+func newTestKeyRing(t *testing.T) (*KeyRing, *LocalKMS, *memoryKeyStore) {
+	t.Helper()
+	log, err := logger.NewLogger(&configs.LoggingConfig{Level: "info", Output: "stdout"})
+	require.NoError(t, err)
+
+	kms, err := NewLocalKMS(filepath.Join(t.TempDir(), "master.json"))
+	require.NoError(t, err)
+	store := &memoryKeyStore{keys: make(map[string][]*StoredKey)}
+	return NewKeyRing(kms, store, log), kms, store
+}
+
+func TestDataKey_SealOpen(t *testing.T) {
+	ring, _, _ := newTestKeyRing(t)
+	ctx := context.Background()
+
+	key, err := ring.Current(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, key.Version)
+
+	ciphertext, err := key.Seal([]byte("blood pressure 120/80"), []byte("memory-1/content"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "blood pressure")
+
+	plaintext, err := key.Open(ciphertext, []byte("memory-1/content"))
+	require.NoError(t, err)
+	assert.Equal(t, "blood pressure 120/80", string(plaintext))
+
+	_, err = key.Open(ciphertext, []byte("memory-2/content"))
+	assert.ErrorIs(t, err, ErrDecrypt, "ciphertext moved to another record must not open")
+
+	other, err := ring.Current(ctx, "bob")
+	require.NoError(t, err)
+	_, err = other.Open(ciphertext, []byte("memory-1/content"))
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestDataKey_BlindIndex(t *testing.T) {
+	ring, _, _ := newTestKeyRing(t)
+	ctx := context.Background()
+
+	alice, err := ring.Current(ctx, "alice")
+	require.NoError(t, err)
+	bob, err := ring.Current(ctx, "bob")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"my", "blood", "test"}, Words("My blood-test, my BLOOD test!"))
+	tokens := alice.BlindIndex("Blood test")
+	require.Len(t, tokens, 2)
+	assert.Equal(t, tokens, alice.BlindIndex("the BLOOD test")[1:])
+	assert.NotEqual(t, tokens, bob.BlindIndex("Blood test"))
+}
+
+func TestKeyRing_Rotate(t *testing.T) {
+	ring, _, _ := newTestKeyRing(t)
+	ctx := context.Background()
+
+	first, err := ring.Current(ctx, "alice")
+	require.NoError(t, err)
+	ciphertext, err := first.Seal([]byte("old note"), nil)
+	require.NoError(t, err)
+
+	second, err := ring.Rotate(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Version)
+
+	current, err := ring.Current(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2, current.Version, "new writes use the rotated key")
+	assert.Equal(t, first.BlindIndex("old note"), current.BlindIndex("old note"), "index tokens survive rotation")
+
+	old, err := ring.Key(ctx, "alice", 1)
+	require.NoError(t, err)
+	plaintext, err := old.Open(ciphertext, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "old note", string(plaintext))
+
+	_, err = ring.Key(ctx, "alice", 3)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyRing_RewrapAfterMasterKeyRotation(t *testing.T) {
+	ring, kms, store := newTestKeyRing(t)
+	ctx := context.Background()
+
+	key, err := ring.Current(ctx, "alice")
+	require.NoError(t, err)
+	_, err = ring.Current(ctx, "bob")
+	require.NoError(t, err)
+	ciphertext, err := key.Seal([]byte("note"), nil)
+	require.NoError(t, err)
+
+	newMaster, err := kms.Rotate()
+	require.NoError(t, err)
+
+	count, err := ring.Rewrap(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	for _, userKeys := range store.keys {
+		for _, stored := range userKeys {
+			assert.Equal(t, newMaster, stored.MasterKeyID)
+		}
+	}
+
+	// A fresh ring reading the rewrapped keys still decrypts
+	reloaded, err := NewLocalKMS(kms.path)
+	require.NoError(t, err)
+	fresh := NewKeyRing(reloaded, store, ring.logger)
+	key, err = fresh.Key(ctx, "alice", 1)
+	require.NoError(t, err)
+	plaintext, err := key.Open(ciphertext, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "note", string(plaintext))
+
+	count, err = ring.Rewrap(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestKeyRing_Forget(t *testing.T) {
+	ring, _, _ := newTestKeyRing(t)
+	ctx := context.Background()
+
+	_, err := ring.Current(ctx, "alice")
+	require.NoError(t, err)
+	require.NoError(t, ring.Forget(ctx, "alice"))
+
+	_, err = ring.Key(ctx, "alice", 1)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	_, err = ring.Latest(ctx, "alice")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
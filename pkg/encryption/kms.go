@@ -0,0 +1,156 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var ErrUnknownMasterKey = errors.New("unknown master key")
+
+// KMS wraps data keys under master keys it never hands out, such as a cloud
+// key management service or, for development, a LocalKMS
+type KMS interface {
+	// CurrentKeyID returns the ID of the master key new data keys are
+	// wrapped with
+	CurrentKeyID(ctx context.Context) (string, error)
+
+	// Encrypt wraps plaintext under the current master key and returns the
+	// ID of that key with the ciphertext
+	Encrypt(ctx context.Context, plaintext []byte) (string, []byte, error)
+
+	// Decrypt unwraps ciphertext with master key keyID
+	Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error)
+}
+
+// localKeyFile is the layout of a LocalKMS key file
+type localKeyFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"` // base64 AES-256 keys by ID
+}
+
+// LocalKMS keeps master keys in a JSON file. It is meant for development:
+// anyone who can read the file can decrypt every memory.
+type LocalKMS struct {
+	path string
+
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+// NewLocalKMS loads the master keys in path, creating the file with a
+// first key when it does not exist
+func NewLocalKMS(path string) (*LocalKMS, error) {
+	k := &LocalKMS{path: path, keys: make(map[string][]byte)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if _, err := k.Rotate(); err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading master key file: %w", err)
+	}
+
+	var file localKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing master key file: %w", err)
+	}
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("master key %s is not a base64 %d-byte key", id, keySize)
+		}
+		k.keys[id] = key
+	}
+	if _, ok := k.keys[file.Current]; !ok {
+		return nil, fmt.Errorf("current master key %q is not in %s", file.Current, path)
+	}
+	k.current = file.Current
+	return k, nil
+}
+
+// Rotate adds a master key and makes it current. Data keys wrapped by
+// earlier keys keep working until they are rewrapped, see KeyRing.Rewrap.
+func (k *LocalKMS) Rotate() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generating master key: %w", err)
+	}
+	id := "local-" + time.Now().UTC().Format("20060102T150405.000000000")
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	previous := k.current
+	k.keys[id] = key
+	k.current = id
+	if err := k.save(); err != nil {
+		delete(k.keys, id)
+		k.current = previous
+		return "", err
+	}
+	return id, nil
+}
+
+// save writes the key file atomically; callers hold k.mu
+func (k *LocalKMS) save() error {
+	file := localKeyFile{Current: k.current, Keys: make(map[string]string, len(k.keys))}
+	for id, key := range k.keys {
+		file.Keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding master key file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(k.path), 0o700); err != nil {
+		return fmt.Errorf("creating master key directory: %w", err)
+	}
+	tmp := k.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing master key file: %w", err)
+	}
+	if err := os.Rename(tmp, k.path); err != nil {
+		return fmt.Errorf("writing master key file: %w", err)
+	}
+	return nil
+}
+
+func (k *LocalKMS) CurrentKeyID(ctx context.Context) (string, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current, nil
+}
+
+func (k *LocalKMS) Encrypt(ctx context.Context, plaintext []byte) (string, []byte, error) {
+	k.mu.RLock()
+	id, key := k.current, k.keys[k.current]
+	k.mu.RUnlock()
+
+	ciphertext, err := seal(key, plaintext, []byte(id))
+	if err != nil {
+		return "", nil, err
+	}
+	return id, ciphertext, nil
+}
+
+func (k *LocalKMS) Decrypt(ctx context.Context, keyID string, ciphertext []byte) ([]byte, error) {
+	k.mu.RLock()
+	key, ok := k.keys[keyID]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownMasterKey
+	}
+	return open(key, ciphertext, []byte(keyID))
+}
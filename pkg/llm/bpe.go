@@ -0,0 +1,211 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// encodingPatterns holds the pre-tokenisation pattern per tiktoken encoding.
+// RE2 has no lookahead, so the `\s+(?!\S)` alternative of the originals is
+// emulated in BPETokenizer.split.
+var encodingPatterns = map[string]string{
+	"cl100k_base": `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`,
+	"o200k_base": `[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?|` +
+		`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?|` +
+		`\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+`,
+}
+
+// unknownToken stands in for bytes missing from a partial vocabulary
+const unknownToken = -1
+
+// BPETokenizer implements byte-level byte pair encoding over a tiktoken
+// vocabulary, matching the token counts OpenAI bills for
+type BPETokenizer struct {
+	ranks   map[string]int
+	decoder map[int]string
+	pattern *regexp.Regexp
+}
+
+// NewBPETokenizer creates a tokenizer from merge ranks and a pre-tokenisation pattern
+func NewBPETokenizer(ranks map[string]int, pattern string) (*BPETokenizer, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling pattern: %w", err)
+	}
+
+	decoder := make(map[int]string, len(ranks))
+	for token, rank := range ranks {
+		decoder[rank] = token
+	}
+
+	return &BPETokenizer{ranks: ranks, decoder: decoder, pattern: re}, nil
+}
+
+// LoadBPETokenizer reads a tiktoken file of "<base64 token> <rank>" lines
+func LoadBPETokenizer(path, pattern string) (*BPETokenizer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected token and rank", line)
+		}
+
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: decoding token: %w", line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: parsing rank: %w", line, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading vocabulary: %w", err)
+	}
+
+	return NewBPETokenizer(ranks, pattern)
+}
+
+// Encode returns the token ids for text
+func (t *BPETokenizer) Encode(text string) []int {
+	var tokens []int
+	for _, piece := range t.split(text) {
+		tokens = append(tokens, t.encodePiece([]byte(piece))...)
+	}
+	return tokens
+}
+
+// Decode returns the text for token ids, skipping unknown tokens
+func (t *BPETokenizer) Decode(tokens []int) string {
+	var buf bytes.Buffer
+	for _, token := range tokens {
+		buf.WriteString(t.decoder[token])
+	}
+	return buf.String()
+}
+
+// CountTokens returns the number of tokens in text
+func (t *BPETokenizer) CountTokens(text string) int {
+	count := 0
+	for _, piece := range t.split(text) {
+		if _, ok := t.ranks[piece]; ok {
+			count++
+			continue
+		}
+		count += len(t.encodePiece([]byte(piece)))
+	}
+	return count
+}
+
+// Truncate returns the longest prefix of text with at most maxTokens tokens.
+// A cut inside a multi-byte character drops the partial character.
+func (t *BPETokenizer) Truncate(text string, maxTokens int) string {
+	tokens := t.Encode(text)
+	if len(tokens) <= maxTokens {
+		return text
+	}
+
+	truncated := t.Decode(tokens[:maxTokens])
+	for len(truncated) > 0 && !utf8.ValidString(truncated) {
+		truncated = truncated[:len(truncated)-1]
+	}
+	return strings.TrimRightFunc(truncated, unicode.IsSpace)
+}
+
+// split breaks text into the pieces BPE runs on
+func (t *BPETokenizer) split(text string) []string {
+	var pieces []string
+
+	for pos := 0; pos < len(text); {
+		loc := t.pattern.FindStringIndex(text[pos:])
+		if loc == nil || loc[1] == loc[0] {
+			pieces = append(pieces, text[pos:])
+			break
+		}
+
+		start, end := pos+loc[0], pos+loc[1]
+		if start > pos {
+			pieces = append(pieces, text[pos:start])
+		}
+
+		// Emulate \s+(?!\S): a whitespace run without newlines followed by a
+		// non-space leaves its last character to prefix the next piece.
+		// Runs containing newlines were matched by \s*[\r\n]+ instead.
+		if end < len(text) && isWhitespace(text[start:end]) && !strings.ContainsAny(text[start:end], "\r\n") {
+			_, last := utf8.DecodeLastRuneInString(text[start:end])
+			if end-last > start {
+				end -= last
+			}
+		}
+
+		pieces = append(pieces, text[start:end])
+		pos = end
+	}
+
+	return pieces
+}
+
+// encodePiece merges the bytes of piece by ascending rank
+func (t *BPETokenizer) encodePiece(piece []byte) []int {
+	if rank, ok := t.ranks[string(piece)]; ok {
+		return []int{rank}
+	}
+
+	// parts holds the start offset of every current part plus len(piece)
+	parts := make([]int, len(piece)+1)
+	for i := range parts {
+		parts[i] = i
+	}
+
+	for len(parts) > 2 {
+		best, bestRank := -1, math.MaxInt
+		for i := 0; i+2 < len(parts); i++ {
+			if rank, ok := t.ranks[string(piece[parts[i]:parts[i+2]])]; ok && rank < bestRank {
+				best, bestRank = i, rank
+			}
+		}
+		if best < 0 {
+			break
+		}
+		parts = append(parts[:best+1], parts[best+2:]...)
+	}
+
+	tokens := make([]int, 0, len(parts)-1)
+	for i := 0; i+1 < len(parts); i++ {
+		rank, ok := t.ranks[string(piece[parts[i]:parts[i+1]])]
+		if !ok {
+			rank = unknownToken
+		}
+		tokens = append(tokens, rank)
+	}
+	return tokens
+}
+
+func isWhitespace(s string) bool {
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
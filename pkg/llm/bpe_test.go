@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBPETokenizer builds a tokenizer over single bytes plus a few merges
+func newTestBPETokenizer(t *testing.T) *BPETokenizer {
+	t.Helper()
+
+	ranks := make(map[string]int, 260)
+	for b := 0; b < 256; b++ {
+		ranks[string([]byte{byte(b)})] = b
+	}
+	ranks["he"] = 256
+	ranks["ll"] = 257
+	ranks["hell"] = 258
+	ranks[" w"] = 259
+
+	tokenizer, err := NewBPETokenizer(ranks, encodingPatterns["cl100k_base"])
+	require.NoError(t, err)
+	return tokenizer
+}
+
+func TestBPETokenizer_Encode(t *testing.T) {
+	tokenizer := newTestBPETokenizer(t)
+
+	assert.Equal(t, []int{258, 'o'}, tokenizer.Encode("hello"))
+	assert.Equal(t, []int{258, 'o', 259, 'o', 'r', 'l', 'd'}, tokenizer.Encode("hello world"))
+	assert.Equal(t, "hello world", tokenizer.Decode(tokenizer.Encode("hello world")))
+}
+
+func TestBPETokenizer_Split(t *testing.T) {
+	tokenizer := newTestBPETokenizer(t)
+
+	// The last space of a run is left to prefix the following word
+	assert.Equal(t, []string{"a", "  ", " b"}, tokenizer.split("a   b"))
+	assert.Equal(t, []string{"a", "\n\n", "b"}, tokenizer.split("a\n\nb"))
+	assert.Equal(t, []string{"it", "'s", " ", "123", "4"}, tokenizer.split("it's 1234"))
+}
+
+func TestBPETokenizer_CountTokens(t *testing.T) {
+	tokenizer := newTestBPETokenizer(t)
+
+	assert.Equal(t, 0, tokenizer.CountTokens(""))
+	assert.Equal(t, 2, tokenizer.CountTokens("hello"))
+	assert.Equal(t, len(tokenizer.Encode("hello world, hello")), tokenizer.CountTokens("hello world, hello"))
+}
+
+func TestBPETokenizer_Truncate(t *testing.T) {
+	tokenizer := newTestBPETokenizer(t)
+
+	assert.Equal(t, "hello", tokenizer.Truncate("hello", 5))
+	assert.Equal(t, "hell", tokenizer.Truncate("hello world", 1))
+	assert.Equal(t, "hello", tokenizer.Truncate("hello world", 2))
+
+	// "é" is two byte tokens; cutting between them drops the character
+	assert.Equal(t, "caf", tokenizer.Truncate("café", 4))
+}
@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"mem_bank/pkg/metrics"
+)
+
+// InstrumentedProvider wraps a Provider and records request counts, latency
+// and token usage per provider and model
+type InstrumentedProvider struct {
+	Provider
+}
+
+// NewInstrumentedProvider wraps provider with metrics collection
+func NewInstrumentedProvider(provider Provider) *InstrumentedProvider {
+	return &InstrumentedProvider{Provider: provider}
+}
+
+// GenerateEmbeddings generates embeddings and records metrics
+func (p *InstrumentedProvider) GenerateEmbeddings(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = p.Provider.GetDefaultModel()
+	}
+
+	start := time.Now()
+	resp, err := p.Provider.GenerateEmbeddings(ctx, req)
+	p.observe("embedding", model, start, err)
+	if err == nil {
+		p.observeUsage("embedding", resp.Model, resp.Usage)
+	}
+
+	return resp, err
+}
+
+// GenerateCompletion generates a completion and records metrics
+func (p *InstrumentedProvider) GenerateCompletion(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = CompletionModelOf(p.Provider)
+	}
+
+	start := time.Now()
+	resp, err := p.Provider.GenerateCompletion(ctx, req)
+	p.observe("completion", model, start, err)
+	if err == nil {
+		p.observeUsage("completion", resp.Model, resp.Usage)
+	}
+
+	return resp, err
+}
+
+func (p *InstrumentedProvider) observe(operation, model string, start time.Time, err error) {
+	status := metrics.StatusSuccess
+	if err != nil {
+		status = metrics.StatusError
+	}
+
+	name := p.Provider.Name()
+	metrics.LLMRequests.WithLabelValues(name, model, operation, status).Inc()
+	metrics.LLMRequestDuration.WithLabelValues(name, model, operation).Observe(time.Since(start).Seconds())
+}
+
+func (p *InstrumentedProvider) observeUsage(operation, model string, usage Usage) {
+	name := p.Provider.Name()
+	if usage.PromptTokens > 0 {
+		metrics.LLMTokens.WithLabelValues(name, model, operation, "prompt").Add(float64(usage.PromptTokens))
+	}
+	if usage.CompletionTokens > 0 {
+		metrics.LLMTokens.WithLabelValues(name, model, operation, "completion").Add(float64(usage.CompletionTokens))
+	}
+}
+
+// CompletionModel returns the wrapped provider's default completion model
+func (p *InstrumentedProvider) CompletionModel() string {
+	return CompletionModelOf(p.Provider)
+}
@@ -66,6 +66,10 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens,omitempty"`
 	TotalTokens      int `json:"total_tokens"`
+
+	// Estimated is set when the provider did not report usage and the
+	// counts come from a tokenizer
+	Estimated bool `json:"estimated,omitempty"`
 }
 
 // EmbeddingProvider defines the interface for embedding generation
@@ -101,6 +105,22 @@ type Provider interface {
 	IsHealthy(ctx context.Context) error
 }
 
+// completionModeler is implemented by providers that expose their default
+// completion model; CompletionProvider.GetDefaultModel is shared with the
+// embedding side and returns the embedding model
+type completionModeler interface {
+	CompletionModel() string
+}
+
+// CompletionModelOf returns provider's default completion model, or
+// "default" when it does not expose one
+func CompletionModelOf(provider Provider) string {
+	if p, ok := provider.(completionModeler); ok {
+		return p.CompletionModel()
+	}
+	return "default"
+}
+
 // Config holds LLM provider configuration
 type Config struct {
 	// Provider type (e.g., "openai", "azure", "local")
@@ -141,9 +161,10 @@ func (e *Error) Error() string {
 
 // Common error types
 var (
-	ErrInvalidAPIKey      = &Error{Type: "invalid_api_key", Message: "invalid API key"}
-	ErrRateLimitExceeded  = &Error{Type: "rate_limit_exceeded", Message: "rate limit exceeded"}
-	ErrModelNotFound      = &Error{Type: "model_not_found", Message: "model not found"}
-	ErrInvalidRequest     = &Error{Type: "invalid_request", Message: "invalid request"}
-	ErrServiceUnavailable = &Error{Type: "service_unavailable", Message: "service unavailable"}
+	ErrInvalidAPIKey         = &Error{Type: "invalid_api_key", Message: "invalid API key"}
+	ErrRateLimitExceeded     = &Error{Type: "rate_limit_exceeded", Message: "rate limit exceeded"}
+	ErrModelNotFound         = &Error{Type: "model_not_found", Message: "model not found"}
+	ErrInvalidRequest        = &Error{Type: "invalid_request", Message: "invalid request"}
+	ErrServiceUnavailable    = &Error{Type: "service_unavailable", Message: "service unavailable"}
+	ErrContextLengthExceeded = &Error{Type: "context_length_exceeded", Message: "input exceeds the model's context length"}
 )
@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// perMessageTokens is the chat format overhead OpenAI adds for each message
+const perMessageTokens = 4
+
+// MeteredProvider wraps a Provider, rejects inputs longer than the model
+// accepts or over the caller's budget, fills in usage the provider did not
+// report and hands every call's usage to a UsageRecorder
+type MeteredProvider struct {
+	Provider
+	tokenizers *Tokenizers
+	recorder   UsageRecorder
+	limiter    UsageLimiter
+}
+
+// NewMeteredProvider wraps provider with input checks, budget checks and usage
+// recording; recorder and limiter may be nil
+func NewMeteredProvider(provider Provider, tokenizers *Tokenizers, recorder UsageRecorder, limiter UsageLimiter) *MeteredProvider {
+	return &MeteredProvider{
+		Provider:   provider,
+		tokenizers: tokenizers,
+		recorder:   recorder,
+		limiter:    limiter,
+	}
+}
+
+// GenerateEmbeddings checks every input against the model limit, generates
+// embeddings and records usage
+func (p *MeteredProvider) GenerateEmbeddings(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = p.Provider.GetDefaultModel()
+	}
+	tokenizer := p.tokenizers.For(model)
+
+	promptTokens := 0
+	for i, input := range req.Input {
+		tokens := tokenizer.CountTokens(input)
+		if err := CheckInputLength(model, tokens); err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+		promptTokens += tokens
+	}
+	if err := p.allow(ctx, "embedding", promptTokens); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.Provider.GenerateEmbeddings(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Usage.TotalTokens == 0 && promptTokens > 0 {
+		resp.Usage = Usage{
+			PromptTokens: promptTokens,
+			TotalTokens:  promptTokens,
+			Estimated:    true,
+		}
+	}
+	p.record(ctx, "embedding", resp.Model, resp.Usage)
+
+	return resp, nil
+}
+
+// GenerateCompletion checks the prompt against the model limit, generates a
+// completion and records usage
+func (p *MeteredProvider) GenerateCompletion(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = CompletionModelOf(p.Provider)
+	}
+	tokenizer := p.tokenizers.For(model)
+
+	promptTokens := 0
+	for _, message := range req.Messages {
+		promptTokens += perMessageTokens + tokenizer.CountTokens(message.Content)
+	}
+	if err := CheckInputLength(model, promptTokens); err != nil {
+		return nil, err
+	}
+	if err := p.allow(ctx, "completion", promptTokens); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.Provider.GenerateCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Usage.TotalTokens == 0 {
+		completionTokens := tokenizer.CountTokens(resp.Content)
+		resp.Usage = Usage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+			Estimated:        true,
+		}
+	}
+	p.record(ctx, "completion", resp.Model, resp.Usage)
+
+	return resp, nil
+}
+
+// CompletionModel returns the wrapped provider's default completion model
+func (p *MeteredProvider) CompletionModel() string {
+	return CompletionModelOf(p.Provider)
+}
+
+func (p *MeteredProvider) allow(ctx context.Context, operation string, tokens int) error {
+	if p.limiter == nil {
+		return nil
+	}
+	return p.limiter.AllowUsage(ctx, UserIDFromContext(ctx), operation, tokens)
+}
+
+func (p *MeteredProvider) record(ctx context.Context, operation, model string, usage Usage) {
+	if p.recorder == nil {
+		return
+	}
+
+	p.recorder.RecordUsage(ctx, UsageRecord{
+		UserID:    UserIDFromContext(ctx),
+		Provider:  p.Provider.Name(),
+		Model:     model,
+		Operation: operation,
+		Usage:     usage,
+	})
+}
@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubProvider returns canned responses and counts calls
+type stubProvider struct {
+	usage Usage
+	calls int
+}
+
+func (p *stubProvider) GenerateEmbeddings(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+	p.calls++
+	embeddings := make([][]float32, len(req.Input))
+	for i := range embeddings {
+		embeddings[i] = []float32{0.1, 0.2}
+	}
+	return &EmbeddingResponse{Embeddings: embeddings, Model: p.GetDefaultModel(), Usage: p.usage}, nil
+}
+
+func (p *stubProvider) GenerateCompletion(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	p.calls++
+	return &CompletionResponse{Content: "ok then", Model: p.CompletionModel(), Usage: p.usage}, nil
+}
+
+func (p *stubProvider) GetEmbeddingDimension(model string) int { return 2 }
+func (p *stubProvider) GetDefaultModel() string                { return "text-embedding-ada-002" }
+func (p *stubProvider) CompletionModel() string                { return "gpt-4" }
+func (p *stubProvider) Name() string                           { return "stub" }
+func (p *stubProvider) IsHealthy(ctx context.Context) error    { return nil }
+
+// recordingRecorder keeps every usage record it receives
+type recordingRecorder struct {
+	records []UsageRecord
+}
+
+func (r *recordingRecorder) RecordUsage(ctx context.Context, record UsageRecord) {
+	r.records = append(r.records, record)
+}
+
+func TestMeteredProvider_RecordsReportedUsage(t *testing.T) {
+	stub := &stubProvider{usage: Usage{PromptTokens: 7, TotalTokens: 7}}
+	recorder := &recordingRecorder{}
+	provider := NewMeteredProvider(stub, nil, recorder, nil)
+
+	ctx := WithUserID(context.Background(), "user-1")
+	_, err := provider.GenerateEmbeddings(ctx, &EmbeddingRequest{Input: []string{"hello world"}})
+	require.NoError(t, err)
+
+	require.Len(t, recorder.records, 1)
+	record := recorder.records[0]
+	assert.Equal(t, "user-1", record.UserID)
+	assert.Equal(t, "stub", record.Provider)
+	assert.Equal(t, "text-embedding-ada-002", record.Model)
+	assert.Equal(t, "embedding", record.Operation)
+	assert.Equal(t, Usage{PromptTokens: 7, TotalTokens: 7}, record.Usage)
+}
+
+func TestMeteredProvider_EstimatesMissingUsage(t *testing.T) {
+	stub := &stubProvider{}
+	recorder := &recordingRecorder{}
+	provider := NewMeteredProvider(stub, nil, recorder, nil)
+
+	resp, err := provider.GenerateCompletion(context.Background(), &CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "the cat sat"}},
+	})
+	require.NoError(t, err)
+
+	expected := Usage{
+		PromptTokens:     perMessageTokens + 3,
+		CompletionTokens: 2,
+		TotalTokens:      perMessageTokens + 5,
+		Estimated:        true,
+	}
+	assert.Equal(t, expected, resp.Usage)
+	require.Len(t, recorder.records, 1)
+	assert.Equal(t, "completion", recorder.records[0].Operation)
+	assert.Equal(t, "gpt-4", recorder.records[0].Model)
+	assert.Empty(t, recorder.records[0].UserID)
+}
+
+// budgetLimiter rejects calls once the tokens allowed so far exceed budget
+type budgetLimiter struct {
+	budget int
+	spent  int
+	users  []string
+}
+
+var errOverBudget = errors.New("over budget")
+
+func (l *budgetLimiter) AllowUsage(ctx context.Context, userID string, operation string, tokens int) error {
+	l.users = append(l.users, userID)
+	if l.spent+tokens > l.budget {
+		return errOverBudget
+	}
+	l.spent += tokens
+	return nil
+}
+
+func TestMeteredProvider_EnforcesLimiter(t *testing.T) {
+	stub := &stubProvider{}
+	limiter := &budgetLimiter{budget: 5}
+	provider := NewMeteredProvider(stub, nil, nil, limiter)
+
+	ctx := WithUserID(context.Background(), "user-1")
+	_, err := provider.GenerateEmbeddings(ctx, &EmbeddingRequest{Input: []string{"the cat sat"}})
+	require.NoError(t, err)
+
+	_, err = provider.GenerateEmbeddings(ctx, &EmbeddingRequest{Input: []string{"the cat sat"}})
+	assert.ErrorIs(t, err, errOverBudget)
+	assert.Equal(t, 1, stub.calls)
+	assert.Equal(t, []string{"user-1", "user-1"}, limiter.users)
+}
+
+func TestMeteredProvider_RejectsOversizedInput(t *testing.T) {
+	stub := &stubProvider{}
+	recorder := &recordingRecorder{}
+	provider := NewMeteredProvider(stub, nil, recorder, nil)
+
+	oversized := strings.Repeat("word ", MaxInputTokens("text-embedding-ada-002")+1)
+	_, err := provider.GenerateEmbeddings(context.Background(), &EmbeddingRequest{
+		Input: []string{"fine", oversized},
+	})
+
+	assert.ErrorIs(t, err, ErrContextLengthExceeded)
+	assert.Zero(t, stub.calls)
+	assert.Empty(t, recorder.records)
+}
@@ -51,15 +51,17 @@ func NewOllamaProvider(config *Config) *OllamaProvider {
 	}
 }
 
-// ollamaEmbeddingRequest represents Ollama's embedding request format
+// ollamaEmbeddingRequest represents Ollama's /api/embed request format
 type ollamaEmbeddingRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
+	Model string   `json:"model"`
+	Input []string `json:"input"`
 }
 
-// ollamaEmbeddingResponse represents Ollama's embedding response format
+// ollamaEmbeddingResponse represents Ollama's /api/embed response format
 type ollamaEmbeddingResponse struct {
-	Embedding []float64 `json:"embedding"`
+	Model           string      `json:"model"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
 }
 
 // ollamaCompletionRequest represents Ollama's completion request format
@@ -98,7 +100,9 @@ type ollamaCompletionResponse struct {
 			} `json:"function"`
 		} `json:"tool_calls,omitempty"`
 	} `json:"message"`
-	Done bool `json:"done"`
+	Done            bool `json:"done"`
+	PromptEvalCount int  `json:"prompt_eval_count"`
+	EvalCount       int  `json:"eval_count"`
 }
 
 // GenerateEmbeddings generates embeddings for the given texts using Ollama
@@ -108,57 +112,51 @@ func (p *OllamaProvider) GenerateEmbeddings(ctx context.Context, req *EmbeddingR
 		model = p.embeddingModel
 	}
 
-	embeddings := make([][]float32, len(req.Input))
-
-	// Generate embeddings for each input text
-	for i, text := range req.Input {
-		ollamaReq := ollamaEmbeddingRequest{
-			Model:  model,
-			Prompt: text,
-		}
+	ollamaReq := ollamaEmbeddingRequest{
+		Model: model,
+		Input: req.Input,
+	}
 
-		reqBytes, err := json.Marshal(ollamaReq)
-		if err != nil {
-			return nil, fmt.Errorf("marshaling request: %w", err)
-		}
+	reqBytes, err := json.Marshal(ollamaReq)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
 
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/api/embeddings", bytes.NewBuffer(reqBytes))
-		if err != nil {
-			return nil, fmt.Errorf("creating request: %w", err)
-		}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/api/embed", bytes.NewBuffer(reqBytes))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
 
-		httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Content-Type", "application/json")
 
-		resp, err := p.client.Do(httpReq)
-		if err != nil {
-			return nil, p.handleError(err)
-		}
-		defer resp.Body.Close()
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, p.handleError(err)
+	}
+	defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
-		}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ollama API error (status %d): %s", resp.StatusCode, string(body))
+	}
 
-		var ollamaResp ollamaEmbeddingResponse
-		if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
-			return nil, fmt.Errorf("decoding response: %w", err)
-		}
+	var ollamaResp ollamaEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
 
-		// Convert float64 to float32
-		embedding := make([]float32, len(ollamaResp.Embedding))
-		for j, v := range ollamaResp.Embedding {
-			embedding[j] = float32(v)
-		}
-		embeddings[i] = embedding
+	if len(ollamaResp.Embeddings) != len(req.Input) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d inputs", len(ollamaResp.Embeddings), len(req.Input))
 	}
 
+	// Ollama reports the prompt tokens it evaluated; older servers omit the
+	// count, which leaves usage at zero for callers to estimate
 	return &EmbeddingResponse{
-		Embeddings: embeddings,
+		Embeddings: ollamaResp.Embeddings,
 		Model:      model,
 		Usage: Usage{
-			PromptTokens: len(req.Input) * 10, // Rough estimate
-			TotalTokens:  len(req.Input) * 10,
+			PromptTokens: ollamaResp.PromptEvalCount,
+			TotalTokens:  ollamaResp.PromptEvalCount,
 		},
 	}, nil
 }
@@ -231,9 +229,9 @@ func (p *OllamaProvider) GenerateCompletion(ctx context.Context, req *Completion
 		Content: ollamaResp.Message.Content,
 		Model:   model,
 		Usage: Usage{
-			PromptTokens:     len(reqBytes) / 4, // Rough estimate
-			CompletionTokens: len(ollamaResp.Message.Content) / 4,
-			TotalTokens:      (len(reqBytes) + len(ollamaResp.Message.Content)) / 4,
+			PromptTokens:     ollamaResp.PromptEvalCount,
+			CompletionTokens: ollamaResp.EvalCount,
+			TotalTokens:      ollamaResp.PromptEvalCount + ollamaResp.EvalCount,
 		},
 	}
 
@@ -276,6 +274,11 @@ func (p *OllamaProvider) GetDefaultModel() string {
 	return p.embeddingModel
 }
 
+// CompletionModel returns the default completion model
+func (p *OllamaProvider) CompletionModel() string {
+	return p.completionModel
+}
+
 // Name returns the provider name
 func (p *OllamaProvider) Name() string {
 	return "ollama"
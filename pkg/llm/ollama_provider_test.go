@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOllamaProvider_GenerateEmbeddings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/embed", r.URL.Path)
+
+		var req ollamaEmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "nomic-embed-text", req.Model)
+		assert.Equal(t, []string{"first", "second"}, req.Input)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"model":             req.Model,
+			"embeddings":        [][]float32{{0.1, 0.2}, {0.3, 0.4}},
+			"prompt_eval_count": 5,
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOllamaProvider(&Config{BaseURL: server.URL})
+	resp, err := provider.GenerateEmbeddings(context.Background(), &EmbeddingRequest{
+		Input: []string{"first", "second"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]float32{{0.1, 0.2}, {0.3, 0.4}}, resp.Embeddings)
+	assert.Equal(t, Usage{PromptTokens: 5, TotalTokens: 5}, resp.Usage)
+}
+
+func TestOllamaProvider_GenerateEmbeddings_CountMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"embeddings": [][]float32{{0.1, 0.2}},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOllamaProvider(&Config{BaseURL: server.URL})
+	_, err := provider.GenerateEmbeddings(context.Background(), &EmbeddingRequest{
+		Input: []string{"first", "second"},
+	})
+	assert.Error(t, err)
+}
+
+func TestOllamaProvider_GenerateCompletion_ReportsEvalCounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"model":             "llama2",
+			"message":           map[string]string{"role": "assistant", "content": "hi"},
+			"done":              true,
+			"prompt_eval_count": 12,
+			"eval_count":        3,
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOllamaProvider(&Config{BaseURL: server.URL})
+	resp, err := provider.GenerateCompletion(context.Background(), &CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "hello"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "hi", resp.Content)
+	assert.Equal(t, Usage{PromptTokens: 12, CompletionTokens: 3, TotalTokens: 15}, resp.Usage)
+}
@@ -190,6 +190,11 @@ func (p *OpenAIProvider) GetDefaultModel() string {
 	return p.embeddingModel
 }
 
+// CompletionModel returns the default completion model
+func (p *OpenAIProvider) CompletionModel() string {
+	return p.completionModel
+}
+
 // Name returns the provider name
 func (p *OpenAIProvider) Name() string {
 	return "openai"
@@ -0,0 +1,178 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Tokenizer counts tokens the way a model's provider bills them
+type Tokenizer interface {
+	// CountTokens returns the number of tokens in text
+	CountTokens(text string) int
+
+	// Truncate returns the longest prefix of text with at most maxTokens tokens
+	Truncate(text string, maxTokens int) string
+}
+
+// ApproximateTokenizer estimates token counts without a vocabulary. CJK
+// characters count as one token each, other words as one token per four
+// runes and every other symbol as one token, which tracks BPE tokenizers
+// closely enough for limits and cost estimates.
+type ApproximateTokenizer struct{}
+
+// CountTokens returns the estimated number of tokens in text
+func (ApproximateTokenizer) CountTokens(text string) int {
+	count, _ := approximateTokens(text, -1)
+	return count
+}
+
+// Truncate cuts text at the token boundary before maxTokens is exceeded
+func (ApproximateTokenizer) Truncate(text string, maxTokens int) string {
+	_, cut := approximateTokens(text, maxTokens)
+	return strings.TrimRightFunc(text[:cut], unicode.IsSpace)
+}
+
+// approximateTokens counts tokens in text, stopping once maxTokens would be
+// exceeded when maxTokens is not negative. It returns the count and the byte
+// offset where counting stopped.
+func approximateTokens(text string, maxTokens int) (int, int) {
+	tokens := 0
+	wordRunes := 0
+	wordStart := 0
+
+	for i, r := range text {
+		switch {
+		case isCJK(r):
+			tokens++
+			wordRunes = 0
+			if maxTokens >= 0 && tokens > maxTokens {
+				return tokens - 1, i
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if wordRunes == 0 {
+				wordStart = i
+			}
+			if wordRunes%4 == 0 {
+				tokens++
+			}
+			wordRunes++
+			if maxTokens >= 0 && tokens > maxTokens {
+				return tokens - 1, wordStart
+			}
+		case unicode.IsSpace(r):
+			wordRunes = 0
+		default:
+			tokens++
+			wordRunes = 0
+			if maxTokens >= 0 && tokens > maxTokens {
+				return tokens - 1, i
+			}
+		}
+	}
+
+	return tokens, len(text)
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// Tokenizers resolves the tokenizer for a model. BPE vocabularies are loaded
+// from tiktoken files ("cl100k_base.tiktoken", "o200k_base.tiktoken") in a
+// directory; models without a loaded vocabulary use ApproximateTokenizer.
+type Tokenizers struct {
+	encodings map[string]Tokenizer
+	fallback  Tokenizer
+}
+
+// NewTokenizers loads the BPE vocabularies found in dir. Missing files are
+// not an error; an empty dir yields approximate counting for every model.
+func NewTokenizers(dir string) (*Tokenizers, error) {
+	t := &Tokenizers{
+		encodings: make(map[string]Tokenizer),
+		fallback:  ApproximateTokenizer{},
+	}
+	if dir == "" {
+		return t, nil
+	}
+
+	for encoding, pattern := range encodingPatterns {
+		path := filepath.Join(dir, encoding+".tiktoken")
+		tokenizer, err := LoadBPETokenizer(path, pattern)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", encoding, err)
+		}
+		t.encodings[encoding] = tokenizer
+	}
+
+	return t, nil
+}
+
+// For returns the tokenizer for model
+func (t *Tokenizers) For(model string) Tokenizer {
+	if t == nil {
+		return ApproximateTokenizer{}
+	}
+	if tokenizer, ok := t.encodings[encodingForModel(model)]; ok {
+		return tokenizer
+	}
+	return t.fallback
+}
+
+// Exact reports whether model is counted with its real vocabulary
+func (t *Tokenizers) Exact(model string) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.encodings[encodingForModel(model)]
+	return ok
+}
+
+// encodingForModel maps OpenAI model names to their tiktoken encoding
+func encodingForModel(model string) string {
+	switch {
+	case strings.HasPrefix(model, "gpt-4o"), strings.HasPrefix(model, "o1"), strings.HasPrefix(model, "o3"):
+		return "o200k_base"
+	case strings.HasPrefix(model, "gpt-4"), strings.HasPrefix(model, "gpt-3.5"), strings.HasPrefix(model, "text-embedding-"):
+		return "cl100k_base"
+	default:
+		return ""
+	}
+}
+
+// modelInputLimits holds the maximum input tokens accepted per model
+var modelInputLimits = map[string]int{
+	"text-embedding-ada-002": 8191,
+	"text-embedding-3-small": 8191,
+	"text-embedding-3-large": 8191,
+	"gpt-3.5-turbo":          16385,
+	"gpt-4":                  8192,
+	"gpt-4-turbo":            128000,
+	"gpt-4o":                 128000,
+	"gpt-4o-mini":            128000,
+	"nomic-embed-text":       8192,
+	"all-minilm":             256,
+	"llama2":                 4096,
+}
+
+// MaxInputTokens returns the input token limit for model, or 0 when unknown
+func MaxInputTokens(model string) int {
+	return modelInputLimits[model]
+}
+
+// CheckInputLength returns ErrContextLengthExceeded when tokens is more than
+// model accepts
+func CheckInputLength(model string, tokens int) error {
+	limit := MaxInputTokens(model)
+	if limit > 0 && tokens > limit {
+		return fmt.Errorf("%d tokens exceeds the %d token limit of %s: %w", tokens, limit, model, ErrContextLengthExceeded)
+	}
+	return nil
+}
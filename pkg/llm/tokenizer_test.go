@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproximateTokenizer_CountTokens(t *testing.T) {
+	tokenizer := ApproximateTokenizer{}
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{"empty", "", 0},
+		{"short words", "the cat sat", 3},
+		{"long word", "internationalization", 5},
+		{"punctuation", "hi, there!", 5},
+		{"cjk", "你好世界", 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tokenizer.CountTokens(tc.text))
+		})
+	}
+}
+
+func TestApproximateTokenizer_Truncate(t *testing.T) {
+	tokenizer := ApproximateTokenizer{}
+
+	assert.Equal(t, "the cat", tokenizer.Truncate("the cat sat", 2))
+	assert.Equal(t, "the cat sat", tokenizer.Truncate("the cat sat", 10))
+	assert.Equal(t, "你好", tokenizer.Truncate("你好世界", 2))
+	assert.Equal(t, "", tokenizer.Truncate("internationalization", 0))
+}
+
+func TestTokenizers_LoadsVocabularies(t *testing.T) {
+	dir := t.TempDir()
+
+	var vocab strings.Builder
+	for b := 0; b < 256; b++ {
+		fmt.Fprintf(&vocab, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(b)}), b)
+	}
+	fmt.Fprintf(&vocab, "%s 256\n", base64.StdEncoding.EncodeToString([]byte("ab")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cl100k_base.tiktoken"), []byte(vocab.String()), 0o644))
+
+	tokenizers, err := NewTokenizers(dir)
+	require.NoError(t, err)
+
+	assert.True(t, tokenizers.Exact("text-embedding-ada-002"))
+	assert.False(t, tokenizers.Exact("gpt-4o"))
+	assert.False(t, tokenizers.Exact("nomic-embed-text"))
+	assert.Equal(t, 2, tokenizers.For("gpt-4").CountTokens("abab"))
+	assert.IsType(t, ApproximateTokenizer{}, tokenizers.For("gpt-4o"))
+}
+
+func TestTokenizers_RejectsMalformedVocabulary(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "o200k_base.tiktoken"), []byte("not-a-rank-line\n"), 0o644))
+
+	_, err := NewTokenizers(dir)
+	assert.Error(t, err)
+}
+
+func TestTokenizers_NilFallsBackToApproximate(t *testing.T) {
+	var tokenizers *Tokenizers
+
+	assert.False(t, tokenizers.Exact("gpt-4"))
+	assert.Equal(t, 3, tokenizers.For("gpt-4").CountTokens("the cat sat"))
+}
+
+func TestCheckInputLength(t *testing.T) {
+	assert.NoError(t, CheckInputLength("text-embedding-ada-002", 8191))
+	assert.NoError(t, CheckInputLength("unknown-model", 1_000_000))
+
+	err := CheckInputLength("text-embedding-ada-002", 8192)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrContextLengthExceeded)
+}
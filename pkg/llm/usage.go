@@ -0,0 +1,38 @@
+package llm
+
+import "context"
+
+// UsageRecord describes the tokens consumed by one provider call
+type UsageRecord struct {
+	// UserID is the user the call is attributed to, empty for system work
+	UserID    string
+	Provider  string
+	Model     string
+	Operation string
+	Usage     Usage
+}
+
+// UsageRecorder receives a record for every provider call
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, record UsageRecord)
+}
+
+// UsageLimiter decides whether a provider call may spend tokens before it is
+// made. userID is the user the call is attributed to, empty for system work.
+// Returned errors are passed to the caller unchanged.
+type UsageLimiter interface {
+	AllowUsage(ctx context.Context, userID string, operation string, tokens int) error
+}
+
+type userIDKey struct{}
+
+// WithUserID attributes provider calls made with ctx to userID
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the user provider calls are attributed to
+func UserIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey{}).(string)
+	return userID
+}
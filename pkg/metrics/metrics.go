@@ -0,0 +1,215 @@
+// Package metrics defines the Prometheus collectors exported on /metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mem_bank"
+
+// Registry holds every mem_bank collector plus the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+// Queue metrics
+var (
+	QueueJobsEnqueued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "jobs_enqueued_total",
+		Help:      "Jobs added to the queue.",
+	}, []string{"job_type"})
+
+	QueueJobsStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "jobs_started_total",
+		Help:      "Jobs picked up by a handler.",
+	}, []string{"job_type"})
+
+	QueueJobsSucceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "jobs_succeeded_total",
+		Help:      "Jobs whose handler completed without error.",
+	}, []string{"job_type"})
+
+	QueueJobsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "jobs_failed_total",
+		Help:      "Jobs that failed permanently after exhausting their retries.",
+	}, []string{"job_type"})
+
+	QueueJobsRetried = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "jobs_retried_total",
+		Help:      "Handler failures that were scheduled for another attempt.",
+	}, []string{"job_type"})
+
+	QueueHandlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "handler_duration_seconds",
+		Help:      "Time spent in job handlers.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"job_type", "status"})
+
+	QueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "depth",
+		Help:      "Jobs waiting to be processed per priority band.",
+	}, []string{"band"})
+
+	QueueOldestJobAge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "queue",
+		Name:      "oldest_job_age_seconds",
+		Help:      "Age of the oldest waiting job per priority band.",
+	}, []string{"band"})
+)
+
+// HTTP metrics
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	HTTPRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "HTTP requests rejected by the rate limiter.",
+	}, []string{"method", "route"})
+
+	GRPCRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "rate_limited_total",
+		Help:      "gRPC calls rejected by the rate limiter.",
+	}, []string{"method"})
+)
+
+// Embedding cache metrics
+var (
+	EmbeddingCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "embedding_cache",
+		Name:      "lookups_total",
+		Help:      "Embedding cache lookups by tier and result (hit or miss).",
+	}, []string{"tier", "result"})
+
+	EmbeddingCacheErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "embedding_cache",
+		Name:      "errors_total",
+		Help:      "Embedding cache operations that failed.",
+	}, []string{"tier", "operation"})
+
+	EmbeddingCoalesced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "embedding",
+		Name:      "coalesced_total",
+		Help:      "Embeddings served by another in-flight request instead of a provider call, by scope (local or redis).",
+	}, []string{"scope"})
+)
+
+// LLM metrics
+var (
+	LLMRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "requests_total",
+		Help:      "LLM provider calls by operation and outcome.",
+	}, []string{"provider", "model", "operation", "status"})
+
+	LLMRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "request_duration_seconds",
+		Help:      "LLM provider call latency.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30, 60},
+	}, []string{"provider", "model", "operation"})
+
+	LLMTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "tokens_total",
+		Help:      "Tokens reported by LLM providers, split into prompt and completion.",
+	}, []string{"provider", "model", "operation", "type"})
+
+	LLMCost = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "cost_usd_total",
+		Help:      "Estimated LLM spend in US dollars from the configured model pricing.",
+	}, []string{"provider", "model", "operation"})
+)
+
+// Label values shared by instrumented components
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+
+	ResultHit  = "hit"
+	ResultMiss = "miss"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+
+		QueueJobsEnqueued,
+		QueueJobsStarted,
+		QueueJobsSucceeded,
+		QueueJobsFailed,
+		QueueJobsRetried,
+		QueueHandlerDuration,
+		QueueDepth,
+		QueueOldestJobAge,
+
+		HTTPRequests,
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		HTTPRateLimited,
+		GRPCRateLimited,
+
+		EmbeddingCacheLookups,
+		EmbeddingCacheErrors,
+		EmbeddingCoalesced,
+
+		LLMRequests,
+		LLMRequestDuration,
+		LLMTokens,
+		LLMCost,
+	)
+}
+
+// Handler returns the HTTP handler serving the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,174 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval limits how often an unknown kid refetches the JWKS, so
+// forged tokens cannot make us hammer the provider
+const minRefreshInterval = time.Minute
+
+// jsonWebKey is a public key published in the provider's JWKS
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// remoteKeySet caches the provider's signing keys and refetches them when
+// a token names a key it does not know, which is how providers rotate
+type remoteKeySet struct {
+	httpClient *http.Client
+	uri        string
+	now        func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	algs      map[string]string
+	fetchedAt time.Time
+}
+
+func newRemoteKeySet(httpClient *http.Client, uri string) *remoteKeySet {
+	return &remoteKeySet{httpClient: httpClient, uri: uri, now: time.Now}
+}
+
+// key returns the public key with ID kid for verifying a token signed with alg
+func (s *remoteKeySet) key(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.lookup(kid, alg)
+	if !ok && s.now().Sub(s.fetchedAt) >= minRefreshInterval {
+		if err := s.refresh(ctx); err != nil {
+			return nil, err
+		}
+		key, ok = s.lookup(kid, alg)
+	}
+	if !ok {
+		return nil, fmt.Errorf("no provider key for kid %q", kid)
+	}
+	return key, nil
+}
+
+// lookup finds a cached key; tokens without a kid are accepted when the
+// provider publishes a single key
+func (s *remoteKeySet) lookup(kid, alg string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for id := range s.keys {
+			kid = id
+		}
+	}
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, false
+	}
+	if keyAlg := s.algs[kid]; keyAlg != "" && keyAlg != alg {
+		return nil, false
+	}
+	return key, true
+}
+
+func (s *remoteKeySet) refresh(ctx context.Context) error {
+	var set jsonWebKeySet
+	if err := getJSON(ctx, s.httpClient, s.uri, &set); err != nil {
+		return fmt.Errorf("fetching provider keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	algs := make(map[string]string, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip keys of unsupported types rather than failing every login
+			continue
+		}
+		keys[jwk.KeyID] = key
+		algs[jwk.KeyID] = jwk.Algorithm
+	}
+
+	s.keys = keys
+	s.algs = algs
+	s.fetchedAt = s.now()
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,216 @@
+// Package oidc implements the relying party side of OpenID Connect: the
+// authorization code flow with PKCE and verification of ID tokens against
+// the provider's published keys.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid ID token")
+	ErrExchangeFailed = errors.New("authorization code exchange failed")
+)
+
+// maxResponseBytes bounds documents read from the provider
+const maxResponseBytes = 1 << 20
+
+// Config holds the relying party registration at a provider
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// providerMetadata is the subset of the discovery document that is used
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Client talks to one OpenID provider
+type Client struct {
+	config     Config
+	metadata   providerMetadata
+	keys       *remoteKeySet
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// Discover fetches the provider's configuration and creates a client for it
+func Discover(ctx context.Context, httpClient *http.Client, config Config) (*Client, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	wellKnown := strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration"
+	var metadata providerMetadata
+	if err := getJSON(ctx, httpClient, wellKnown, &metadata); err != nil {
+		return nil, fmt.Errorf("discovering OpenID provider: %w", err)
+	}
+	// The issuer must match exactly, or tokens could be accepted from a
+	// provider impersonating the configured one
+	if metadata.Issuer != config.Issuer {
+		return nil, fmt.Errorf("discovering OpenID provider: issuer %q does not match %q", metadata.Issuer, config.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("discovering OpenID provider: incomplete provider metadata")
+	}
+
+	return &Client{
+		config:     config,
+		metadata:   metadata,
+		keys:       newRemoteKeySet(httpClient, metadata.JWKSURI),
+		httpClient: httpClient,
+		now:        time.Now,
+	}, nil
+}
+
+// Issuer returns the provider's issuer identifier
+func (c *Client) Issuer() string {
+	return c.metadata.Issuer
+}
+
+// AuthCodeURL returns the URL the user is sent to for signing in
+func (c *Client) AuthCodeURL(state, nonce, verifier string) string {
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.config.ClientID},
+		"redirect_uri":          {c.config.RedirectURL},
+		"scope":                 {strings.Join(c.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(c.metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return c.metadata.AuthorizationEndpoint + separator + params.Encode()
+}
+
+// tokenResponse is the token endpoint's answer
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// Exchange trades an authorization code for tokens and returns the
+// verified claims of the ID token
+func (c *Client) Exchange(ctx context.Context, code, verifier, nonce string) (*IDToken, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("%w: decoding response: %v", ErrExchangeFailed, err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrExchangeFailed, token.Error, token.Description)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: no ID token in response", ErrExchangeFailed)
+	}
+
+	return c.Verify(ctx, token.IDToken, nonce)
+}
+
+// IDToken holds the claims of a verified ID token
+type IDToken struct {
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	GivenName         string `json:"given_name"`
+	FamilyName        string `json:"family_name"`
+	PreferredUsername string `json:"preferred_username"`
+	Picture           string `json:"picture"`
+	jwt.RegisteredClaims
+}
+
+// Verify checks the signature, issuer, audience, lifetime and nonce of an
+// ID token
+func (c *Client) Verify(ctx context.Context, rawIDToken, nonce string) (*IDToken, error) {
+	claims := &IDToken{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return c.keys.key(ctx, kid, token.Method.Alg())
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}),
+		jwt.WithIssuer(c.metadata.Issuer),
+		jwt.WithAudience(c.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+		jwt.WithTimeFunc(c.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(v)
+}
@@ -0,0 +1,116 @@
+package oidc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mem_bank/pkg/oidc/oidctest"
+)
+
+func newClient(t *testing.T, provider *oidctest.Server) *Client {
+	t.Helper()
+	client, err := Discover(context.Background(), nil, Config{
+		Issuer:       provider.Issuer(),
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURL:  "http://localhost/callback",
+	})
+	require.NoError(t, err)
+	return client
+}
+
+// exchange runs a complete login and returns the verified ID token
+func exchange(t *testing.T, client *Client, provider *oidctest.Server) (*IDToken, error) {
+	t.Helper()
+	verifier, err := RandomString()
+	require.NoError(t, err)
+
+	code, state, err := provider.Login(client.AuthCodeURL("state", "nonce", verifier))
+	require.NoError(t, err)
+	require.Equal(t, "state", state)
+	return client.Exchange(context.Background(), code, verifier, "nonce")
+}
+
+func TestDiscover(t *testing.T) {
+	provider := oidctest.NewServer("client", "secret")
+	defer provider.Close()
+
+	client := newClient(t, provider)
+	assert.Equal(t, provider.Issuer(), client.Issuer())
+
+	_, err := Discover(context.Background(), nil, Config{Issuer: provider.Issuer() + "/other"})
+	assert.Error(t, err)
+}
+
+func TestClient_Exchange(t *testing.T) {
+	provider := oidctest.NewServer("client", "secret")
+	defer provider.Close()
+	provider.SetIdentity(oidctest.Identity{Subject: "sub-1", Email: "alice@example.com"})
+	client := newClient(t, provider)
+
+	token, err := exchange(t, client, provider)
+	require.NoError(t, err)
+	assert.Equal(t, provider.Issuer(), token.Issuer)
+	assert.Equal(t, "sub-1", token.Subject)
+	assert.Equal(t, "alice@example.com", token.Email)
+
+	t.Run("wrong client secret", func(t *testing.T) {
+		other, err := Discover(context.Background(), nil, Config{
+			Issuer:       provider.Issuer(),
+			ClientID:     provider.ClientID,
+			ClientSecret: "wrong",
+			RedirectURL:  "http://localhost/callback",
+		})
+		require.NoError(t, err)
+
+		_, err = exchange(t, other, provider)
+		assert.ErrorIs(t, err, ErrExchangeFailed)
+	})
+}
+
+func TestClient_VerifyAfterProviderKeyRotation(t *testing.T) {
+	provider := oidctest.NewServer("client", "secret")
+	defer provider.Close()
+	provider.SetIdentity(oidctest.Identity{Subject: "sub-1"})
+	client := newClient(t, provider)
+
+	clock := time.Now()
+	client.keys.now = func() time.Time { return clock }
+
+	_, err := exchange(t, client, provider)
+	require.NoError(t, err)
+
+	// Unknown keys are refetched at most once a minute
+	provider.RotateKey()
+	_, err = exchange(t, client, provider)
+	assert.ErrorIs(t, err, ErrInvalidIDToken)
+
+	clock = clock.Add(2 * time.Minute)
+	_, err = exchange(t, client, provider)
+	assert.NoError(t, err)
+}
+
+func TestMemoryStateStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStateStore()
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Save(ctx, "state-1", LoginState{Verifier: "v", Nonce: "n"}, time.Minute))
+	state, err := store.Take(ctx, "state-1")
+	require.NoError(t, err)
+	assert.Equal(t, "v", state.Verifier)
+
+	// Each state is used at most once
+	_, err = store.Take(ctx, "state-1")
+	assert.ErrorIs(t, err, ErrUnknownState)
+
+	require.NoError(t, store.Save(ctx, "state-2", LoginState{}, time.Minute))
+	now = now.Add(2 * time.Minute)
+	_, err = store.Take(ctx, "state-2")
+	assert.ErrorIs(t, err, ErrUnknownState)
+}
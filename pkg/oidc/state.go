@@ -0,0 +1,123 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrUnknownState = errors.New("unknown or expired login state")
+
+// LoginState is what the relying party remembers between redirecting the
+// user to the provider and handling the callback
+type LoginState struct {
+	Verifier  string    `json:"verifier"`
+	Nonce     string    `json:"nonce"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StateStore keeps pending logins keyed by the state parameter
+type StateStore interface {
+	// Save stores state for ttl
+	Save(ctx context.Context, key string, state LoginState, ttl time.Duration) error
+
+	// Take returns and removes the state stored under key, so each state is
+	// used at most once
+	Take(ctx context.Context, key string) (*LoginState, error)
+}
+
+// RedisStateStore keeps pending logins in Redis so the callback may land on
+// any replica
+type RedisStateStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStateStore creates a state store storing its keys under prefix
+func NewRedisStateStore(client *redis.Client, prefix string) *RedisStateStore {
+	return &RedisStateStore{client: client, prefix: prefix}
+}
+
+// Save stores state for ttl
+func (s *RedisStateStore) Save(ctx context.Context, key string, state LoginState, ttl time.Duration) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding login state: %w", err)
+	}
+	if err := s.client.Set(ctx, s.prefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("saving login state: %w", err)
+	}
+	return nil
+}
+
+// Take returns and removes the state stored under key
+func (s *RedisStateStore) Take(ctx context.Context, key string) (*LoginState, error) {
+	data, err := s.client.GetDel(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrUnknownState
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading login state: %w", err)
+	}
+
+	var state LoginState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("decoding login state: %w", err)
+	}
+	return &state, nil
+}
+
+// MemoryStateStore keeps pending logins in process. The callback must reach
+// the replica that started the login, so it suits single-node deployments
+// running without Redis.
+type MemoryStateStore struct {
+	mu     sync.Mutex
+	states map[string]memoryState
+	now    func() time.Time
+}
+
+type memoryState struct {
+	state   LoginState
+	expires time.Time
+}
+
+// NewMemoryStateStore creates an in-process state store
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{
+		states: make(map[string]memoryState),
+		now:    time.Now,
+	}
+}
+
+// Save stores state for ttl, dropping expired states on the way
+func (s *MemoryStateStore) Save(ctx context.Context, key string, state LoginState, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, stored := range s.states {
+		if !stored.expires.After(now) {
+			delete(s.states, k)
+		}
+	}
+	s.states[key] = memoryState{state: state, expires: now.Add(ttl)}
+	return nil
+}
+
+// Take returns and removes the state stored under key
+func (s *MemoryStateStore) Take(ctx context.Context, key string) (*LoginState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.states[key]
+	delete(s.states, key)
+	if !ok || !stored.expires.After(s.now()) {
+		return nil, ErrUnknownState
+	}
+	return &stored.state, nil
+}
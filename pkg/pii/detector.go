@@ -0,0 +1,126 @@
+package pii
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule detects one kind of personal data. Pattern finds candidates and
+// Validate, when set, keeps only those that pass a check such as a checksum.
+type Rule struct {
+	Kind     string
+	Pattern  *regexp.Regexp
+	Validate func(match string) bool
+}
+
+// NewRule creates a rule from a regular expression, as found in configuration
+func NewRule(kind, pattern string) (Rule, error) {
+	kind = strings.TrimSpace(kind)
+	if kind == "" {
+		return Rule{}, fmt.Errorf("rule requires a kind")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("compiling pattern of rule %s: %w", kind, err)
+	}
+	return Rule{Kind: kind, Pattern: re}, nil
+}
+
+// Match is a piece of personal data found in a text; Start and End are byte
+// offsets
+type Match struct {
+	Kind  string
+	Start int
+	End   int
+	Value string
+}
+
+// Detector finds personal data with a list of rules
+type Detector struct {
+	rules []Rule
+}
+
+// NewDetector creates a detector. When matches of two rules overlap, the
+// one starting first wins, then the longer, then the rule listed first.
+func NewDetector(rules ...Rule) *Detector {
+	return &Detector{rules: rules}
+}
+
+// Kinds returns the kinds of data the detector looks for
+func (d *Detector) Kinds() []string {
+	kinds := make([]string, 0, len(d.rules))
+	seen := make(map[string]bool, len(d.rules))
+	for _, rule := range d.rules {
+		if !seen[rule.Kind] {
+			seen[rule.Kind] = true
+			kinds = append(kinds, rule.Kind)
+		}
+	}
+	return kinds
+}
+
+// Detect returns the non-overlapping matches in text, in order
+func (d *Detector) Detect(text string) []Match {
+	type candidate struct {
+		Match
+		rule int
+	}
+
+	var candidates []candidate
+	for i, rule := range d.rules {
+		for _, loc := range rule.Pattern.FindAllStringIndex(text, -1) {
+			value := text[loc[0]:loc[1]]
+			if loc[0] == loc[1] || (rule.Validate != nil && !rule.Validate(value)) {
+				continue
+			}
+			candidates = append(candidates, candidate{
+				Match: Match{Kind: rule.Kind, Start: loc[0], End: loc[1], Value: value},
+				rule:  i,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.End != b.End {
+			return a.End > b.End
+		}
+		return a.rule < b.rule
+	})
+
+	matches := make([]Match, 0, len(candidates))
+	end := 0
+	for _, c := range candidates {
+		if c.Start < end {
+			continue
+		}
+		matches = append(matches, c.Match)
+		end = c.End
+	}
+	return matches
+}
+
+// Replace returns text with every match replaced by what replacement
+// returns for it. Matches must be in order and must not overlap, as
+// returned by Detect.
+func Replace(text string, matches []Match, replacement func(Match) string) string {
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(replacement(m))
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
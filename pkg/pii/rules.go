@@ -0,0 +1,121 @@
+package pii
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Kinds of personal data found by the built-in rules
+const (
+	KindAPIKey     = "api_key"
+	KindEmail      = "email"
+	KindCreditCard = "credit_card"
+	KindIBAN       = "iban"
+	KindSSN        = "ssn"
+	KindPhone      = "phone"
+)
+
+var (
+	// Credentials with a recognisable prefix: OpenAI, Stripe, AWS, GitHub,
+	// Slack, Google and mem_bank's own keys
+	apiKeyPattern = regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_\-]{20,}|sk_(?:live|test)_[A-Za-z0-9]{16,}|AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|xox[abprs]-[A-Za-z0-9\-]{10,}|AIza[0-9A-Za-z_\-]{35}|mbk_[0-9a-f]{8}_[A-Za-z0-9_\-]{43})`)
+
+	emailPattern      = regexp.MustCompile(`(?i)\b[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}\b`)
+	creditCardPattern = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+	ibanPattern       = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`)
+	ssnPattern        = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	phonePattern      = regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?(?:\(\d{1,4}\)[ .\-]?)?\d{2,4}(?:[ .\-]?\d{2,4}){1,4}\b`)
+)
+
+// BuiltinRules returns the rules for common identifiers, most specific first
+func BuiltinRules() []Rule {
+	return []Rule{
+		{Kind: KindAPIKey, Pattern: apiKeyPattern},
+		{Kind: KindEmail, Pattern: emailPattern},
+		{Kind: KindCreditCard, Pattern: creditCardPattern, Validate: validCardNumber},
+		{Kind: KindIBAN, Pattern: ibanPattern, Validate: validIBAN},
+		{Kind: KindSSN, Pattern: ssnPattern, Validate: validSSN},
+		{Kind: KindPhone, Pattern: phonePattern, Validate: validPhone},
+	}
+}
+
+// digits returns the decimal digits of s
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// validCardNumber checks the length and Luhn checksum of a card number
+func validCardNumber(match string) bool {
+	number := digits(match)
+	if len(number) < 13 || len(number) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validIBAN checks the ISO 13616 mod-97 checksum of an IBAN
+func validIBAN(match string) bool {
+	iban := strings.ReplaceAll(match, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	// Move the country code and check digits to the end, then read letters
+	// as numbers from 10 (A) to 35 (Z)
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			numeric.WriteString(big.NewInt(int64(r - 'A' + 10)).String())
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// validSSN rejects US social security numbers that are never issued
+func validSSN(match string) bool {
+	area, group, serial := match[0:3], match[4:6], match[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// validPhone keeps numbers that look dialable: an international prefix or
+// area code in brackets, or at least ten digits in groups. Dates and bare
+// runs of digits such as order numbers are left alone.
+func validPhone(match string) bool {
+	n := len(digits(match))
+	if n < 7 || n > 15 {
+		return false
+	}
+	if strings.HasPrefix(match, "+") || strings.Contains(match, "(") {
+		return true
+	}
+	return n >= 10 && len(match) > n
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryLimiter drops keys whose bucket is full again
+const sweepInterval = time.Minute
+
+// MemoryLimiter keeps GCRA state in process. Limits are per replica, so it
+// suits single-node deployments and tests.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryLimiter creates an in-process limiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		tats: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+// Allow records a request for key when it is within limit
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	result, tat := gcra(l.tats[key], now, limit)
+	if result.Allowed {
+		l.tats[key] = tat
+	}
+	return result, nil
+}
+
+// sweep evicts idle keys; a key whose arrival time has passed holds no state
+// beyond a full bucket. Callers must hold mu.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, tat := range l.tats {
+		if !tat.After(now) {
+			delete(l.tats, key)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestLimiter() (*MemoryLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := NewMemoryLimiter()
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func TestMemoryLimiter_AllowsBurstThenRejects(t *testing.T) {
+	limiter, _ := newTestLimiter()
+	limit := Limit{Requests: 60, Period: time.Minute, Burst: 3}
+	ctx := context.Background()
+
+	for want := 2; want >= 0; want-- {
+		result, err := limiter.Allow(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, want, result.Remaining)
+	}
+
+	result, err := limiter.Allow(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.ResetAfter)
+}
+
+func TestMemoryLimiter_ReplenishesAtRate(t *testing.T) {
+	limiter, clock := newTestLimiter()
+	limit := Limit{Requests: 60, Period: time.Minute, Burst: 2}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(ctx, "ip:10.0.0.1", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	result, err := limiter.Allow(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	clock.Advance(time.Second)
+	result, err = limiter.Allow(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Keys are independent
+	result, err = limiter.Allow(ctx, "ip:10.0.0.2", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+}
+
+func TestMemoryLimiter_EvictsIdleKeys(t *testing.T) {
+	limiter, clock := newTestLimiter()
+	limit := PerMinute(60)
+	ctx := context.Background()
+
+	_, err := limiter.Allow(ctx, "ip:10.0.0.1", limit)
+	require.NoError(t, err)
+
+	clock.Advance(2 * sweepInterval)
+	_, err = limiter.Allow(ctx, "ip:10.0.0.2", limit)
+	require.NoError(t, err)
+
+	assert.NotContains(t, limiter.tats, "ip:10.0.0.1")
+	assert.Contains(t, limiter.tats, "ip:10.0.0.2")
+}
+
+func TestMemoryLimiter_ConcurrentUse(t *testing.T) {
+	limiter := NewMemoryLimiter()
+	limit := Limit{Requests: 100, Period: time.Hour}
+	ctx := context.Background()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := limiter.Allow(ctx, "user:1", limit)
+			if err == nil && result.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, allowed)
+}
+
+func TestLimit_Validate(t *testing.T) {
+	assert.NoError(t, PerMinute(10).Validate())
+	assert.Error(t, Limit{Requests: 0, Period: time.Minute}.Validate())
+	assert.Error(t, Limit{Requests: 10}.Validate())
+	assert.Error(t, Limit{Requests: 10, Period: time.Minute, Burst: -1}.Validate())
+
+	_, err := NewMemoryLimiter().Allow(context.Background(), "k", Limit{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+// Package ratelimit implements the generic cell rate algorithm (GCRA) over
+// Redis for limits shared by every replica, and in process for single nodes.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Limit allows Requests per Period, with up to Burst requests at once
+type Limit struct {
+	Requests int
+	Period   time.Duration
+
+	// Burst is the largest number of requests allowed back to back;
+	// zero means Requests
+	Burst int
+}
+
+// PerMinute returns a limit of requests per minute with a burst of the same size
+func PerMinute(requests int) Limit {
+	return Limit{Requests: requests, Period: time.Minute}
+}
+
+// Validate reports whether the limit can be enforced
+func (l Limit) Validate() error {
+	if l.Requests <= 0 {
+		return fmt.Errorf("requests must be positive, got %d", l.Requests)
+	}
+	if l.Period <= 0 {
+		return fmt.Errorf("period must be positive, got %s", l.Period)
+	}
+	if l.Burst < 0 {
+		return fmt.Errorf("burst must not be negative, got %d", l.Burst)
+	}
+	return nil
+}
+
+// burst returns the effective burst size
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// emissionInterval returns the time one request adds to the bucket
+func (l Limit) emissionInterval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// String formats the limit as a RateLimit-Policy header value
+func (l Limit) String() string {
+	return fmt.Sprintf("%d;w=%d;burst=%d", l.Requests, int(l.Period.Seconds()), l.burst())
+}
+
+// Result describes the decision for one request
+type Result struct {
+	Allowed bool
+
+	// Limit is the burst size the caller is held to
+	Limit int
+
+	// Remaining is the number of requests that could be made right now
+	Remaining int
+
+	// RetryAfter is how long to wait before the next request is allowed;
+	// zero when Allowed
+	RetryAfter time.Duration
+
+	// ResetAfter is how long until the full burst is available again
+	ResetAfter time.Duration
+}
+
+// Limiter decides whether a request identified by key is within limit
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// gcra applies one request to the theoretical arrival time tat of a key at
+// now. It returns the decision and the tat to store when the request is allowed.
+func gcra(tat, now time.Time, limit Limit) (*Result, time.Time) {
+	burst := limit.burst()
+	emission := limit.emissionInterval()
+	tolerance := emission * time.Duration(burst)
+
+	if tat.Before(now) {
+		tat = now
+	}
+	newTAT := tat.Add(emission)
+	allowAt := newTAT.Add(-tolerance)
+
+	if diff := now.Sub(allowAt); diff < 0 {
+		return &Result{
+			Allowed:    false,
+			Limit:      burst,
+			Remaining:  0,
+			RetryAfter: -diff,
+			ResetAfter: tat.Sub(now),
+		}, tat
+	}
+
+	return &Result{
+		Allowed:    true,
+		Limit:      burst,
+		Remaining:  int(now.Sub(allowAt) / emission),
+		ResetAfter: newTAT.Sub(now),
+	}, newTAT
+}
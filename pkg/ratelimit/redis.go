@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript applies one request to the theoretical arrival time stored at
+// KEYS[1], in microseconds since the epoch. The clock is the Redis server's
+// so replicas with skewed clocks share one schedule.
+//
+// ARGV: burst, emission interval (µs)
+// Returns: allowed (0/1), remaining, retry after (µs), reset after (µs)
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+
+local burst = tonumber(ARGV[1])
+local emission = tonumber(ARGV[2])
+local tolerance = emission * burst
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + emission
+local diff = now - (new_tat - tolerance)
+if diff < 0 then
+	return {0, 0, -diff, tat - now}
+end
+
+local reset_after = new_tat - now
+redis.call("SET", KEYS[1], string.format("%.0f", new_tat), "PX", math.ceil(reset_after / 1000))
+return {1, math.floor(diff / emission), 0, reset_after}
+`)
+
+// RedisLimiter keeps GCRA state in Redis, one key per caller, so every
+// replica enforces the same limit. Keys expire once their bucket is full.
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisLimiter creates a limiter storing its keys under prefix
+func NewRedisLimiter(client *redis.Client, prefix string) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: prefix}
+}
+
+// Allow records a request for key when it is within limit
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+
+	emission := limit.emissionInterval().Microseconds()
+	if emission < 1 {
+		emission = 1
+	}
+
+	values, err := gcraScript.Run(ctx, l.client, []string{l.prefix + key}, limit.burst(), emission).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("running rate limit script: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit script reply: %v", values)
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit.burst(),
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
@@ -222,9 +222,9 @@ func getRequestID(c *gin.Context) string {
 // getErrorTypeFromCode maps error codes to error types
 func getErrorTypeFromCode(code string) string {
 	switch code {
-	case "validation_failed", "invalid_request", "missing_field", "invalid_field":
+	case "validation_failed", "invalid_request", "missing_field", "invalid_field", "weak_password":
 		return ErrorTypeValidation
-	case "unauthorized", "invalid_credentials", "missing_auth_header", "invalid_token", "token_expired":
+	case "unauthorized", "invalid_credentials", "missing_auth_header", "invalid_token", "token_expired", "token_revoked":
 		return ErrorTypeAuthentication
 	case "forbidden", "insufficient_permissions":
 		return ErrorTypeAuthorization
@@ -232,7 +232,7 @@ func getErrorTypeFromCode(code string) string {
 		return ErrorTypeNotFound
 	case "conflict", "already_exists":
 		return ErrorTypeConflict
-	case "rate_limit_exceeded":
+	case "rate_limit_exceeded", "account_locked":
 		return ErrorTypeRateLimit
 	default:
 		return ErrorTypeInternal
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned when a webhook URL resolves to an address
+// that is not publicly routable
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// ValidateURL checks that rawURL is an absolute http or https URL. Unless
+// allowPrivate is set, hosts that are loopback, private or link-local
+// addresses, or localhost, are rejected; NewClient checks the addresses
+// names resolve to when connecting.
+func ValidateURL(rawURL string, allowPrivate bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.New("missing host")
+	}
+	if u.User != nil {
+		return errors.New("credentials are not allowed in the URL")
+	}
+	if allowPrivate {
+		return nil
+	}
+	if u.Hostname() == "localhost" {
+		return ErrPrivateAddress
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !isPublic(addr) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// NewClient returns an HTTP client for delivering webhooks. Redirects are
+// not followed, and unless allowPrivate is set, connections to addresses
+// that are not publicly routable are refused, so a subscription cannot be
+// used to reach internal services.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublic(addrPort.Addr()) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !addr.IsLoopback() && !addr.IsLinkLocalUnicast()
+}
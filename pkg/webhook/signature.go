@@ -0,0 +1,63 @@
+// Package webhook signs and sends outbound webhook requests.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of a webhook request. The event ID is the same on every attempt
+// to deliver an event, so receivers can drop duplicates.
+const (
+	HeaderSignature = "X-MemBank-Signature"
+	HeaderTimestamp = "X-MemBank-Timestamp"
+	HeaderEvent     = "X-MemBank-Event"
+	HeaderEventID   = "X-MemBank-Event-ID"
+)
+
+// signaturePrefix names the algorithm in the signature header
+const signaturePrefix = "sha256="
+
+// Errors returned by Verify
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrStaleTimestamp   = errors.New("webhook timestamp outside tolerance")
+)
+
+// Sign returns the signature header value for body sent at timestamp (Unix
+// seconds): the hex HMAC-SHA256 under secret of the timestamp, a dot and
+// the body. Signing the timestamp lets receivers reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a received request
+// against secret. Requests whose timestamp is further than tolerance from
+// now are rejected; a zero tolerance skips the check.
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		if skew := now.Sub(time.Unix(ts, 0)); skew > tolerance || skew < -tolerance {
+			return ErrStaleTimestamp
+		}
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -108,7 +108,7 @@ func TestAIMemoryIntegration(t *testing.T) {
 		DefaultSimilarityThreshold: 0.8,
 		AutoGenerateEmbeddings:     true,
 	}
-	aiMemoryService := memoryService.NewAIService(memoryRepo, userRepo, embedSvc, jobQueue, appLogger, aiConfig)
+	aiMemoryService := memoryService.NewAIService(memoryRepo, userRepo, nil, embedSvc, nil, nil, jobQueue, appLogger, aiConfig)
 
 	// Run integration tests
 	t.Run("end_to_end_memory_with_ai", func(t *testing.T) {